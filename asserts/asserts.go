@@ -232,6 +232,9 @@ func init() {
 	// 1: support for constraints
 	maxSupportedFormat[AccountKeyType.Name] = 1
 
+	// 1: support for connections
+	maxSupportedFormat[ModelType.Name] = 1
+
 	for _, at := range typeRegistry {
 		at.validate()
 	}
@@ -264,6 +267,7 @@ func MockOptionalPrimaryKey(assertType *AssertionType, key, defaultValue string)
 
 var formatAnalyzer = map[*AssertionType]func(headers map[string]any, body []byte) (formatnum int, err error){
 	AccountKeyType:      accountKeyFormatAnalyze,
+	ModelType:           modelFormatAnalyze,
 	SnapDeclarationType: snapDeclarationFormatAnalyze,
 	SystemUserType:      systemUserFormatAnalyze,
 }
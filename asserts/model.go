@@ -592,6 +592,8 @@ type Model struct {
 
 	validationSets []*ModelValidationSet
 
+	connections []*ModelConnection
+
 	serialAuthority  []string
 	sysUserAuthority []string
 	preseedAuthority []string
@@ -755,6 +757,12 @@ func (mod *Model) ValidationSets() []*ModelValidationSet {
 	return mod.validationSets
 }
 
+// Connections returns the interface connections the model requires to be
+// established on the device.
+func (mod *Model) Connections() []*ModelConnection {
+	return mod.connections
+}
+
 // SerialAuthority returns the authority ids that are accepted as
 // signers for serial assertions for this model. It always includes the
 // brand of the model.
@@ -970,6 +978,109 @@ func checkOptionalModelValidationSets(headers map[string]any, brandID string) ([
 	return vss, nil
 }
 
+// ModelConnection describes an interface connection that the model
+// requires to be established on the device.
+type ModelConnection struct {
+	// PlugSnap is the name of the snap with the plug.
+	PlugSnap string
+	Plug     string
+	// SlotSnap is the name of the snap with the slot, "system" refers
+	// to the system snap.
+	SlotSnap string
+	Slot     string
+}
+
+// PlugRef returns the plug reference, in the usual <snap>:<plug> form.
+func (mc *ModelConnection) PlugRef() string {
+	return mc.PlugSnap + ":" + mc.Plug
+}
+
+// SlotRef returns the slot reference, in the usual <snap>:<slot> form.
+func (mc *ModelConnection) SlotRef() string {
+	return mc.SlotSnap + ":" + mc.Slot
+}
+
+func (mc *ModelConnection) String() string {
+	return mc.PlugRef() + " " + mc.SlotRef()
+}
+
+func checkModelConnectionEndpoint(headers map[string]any, side string, validateName func(string) error) (snapName, name string, err error) {
+	ref, err := checkNotEmptyStringWhat(headers, side, "of connection")
+	if err != nil {
+		return "", "", err
+	}
+	snapName, name, ok := strings.Cut(ref, ":")
+	if !ok {
+		return "", "", fmt.Errorf("%s of connection must be <snap>:<%s>, not %q", side, side, ref)
+	}
+	if err := naming.ValidateSnap(snapName); err != nil {
+		return "", "", fmt.Errorf("invalid %s of connection %q: %v", side, ref, err)
+	}
+	if err := validateName(name); err != nil {
+		return "", "", fmt.Errorf("invalid %s of connection %q: %v", side, ref, err)
+	}
+	return snapName, name, nil
+}
+
+func checkOptionalModelConnections(headers map[string]any, allSnaps []*ModelSnap) ([]*ModelConnection, error) {
+	connections, ok := headers["connections"]
+	if !ok {
+		return nil, nil
+	}
+
+	entries, ok := connections.([]any)
+	if !ok {
+		return nil, fmt.Errorf(`"connections" header must be a list of maps`)
+	}
+
+	inModel := make(map[string]bool, len(allSnaps))
+	for _, sn := range allSnaps {
+		inModel[sn.Name] = true
+	}
+
+	conns := make([]*ModelConnection, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		data, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf(`"connections" header must be a list of maps`)
+		}
+		plugSnap, plug, err := checkModelConnectionEndpoint(data, "plug", naming.ValidatePlug)
+		if err != nil {
+			return nil, err
+		}
+		slotSnap, slot, err := checkModelConnectionEndpoint(data, "slot", naming.ValidateSlot)
+		if err != nil {
+			return nil, err
+		}
+		conn := &ModelConnection{
+			PlugSnap: plugSnap,
+			Plug:     plug,
+			SlotSnap: slotSnap,
+			Slot:     slot,
+		}
+		if !inModel[plugSnap] {
+			return nil, fmt.Errorf("cannot declare connection %q of snap %q not required by the model", conn, plugSnap)
+		}
+		if slotSnap != "system" && !inModel[slotSnap] {
+			return nil, fmt.Errorf("cannot declare connection %q of snap %q not required by the model", conn, slotSnap)
+		}
+		if seen[conn.String()] {
+			return nil, fmt.Errorf("cannot declare connection %q twice", conn)
+		}
+		seen[conn.String()] = true
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+func modelFormatAnalyze(headers map[string]any, body []byte) (formatnum int, err error) {
+	if _, ok := headers["connections"]; ok {
+		formatnum = 1
+	}
+	return formatnum, nil
+}
+
 var (
 	modelMandatory           = []string{"architecture", "gadget", "kernel"}
 	extendedMandatory        = []string{"architecture", "base"}
@@ -1199,6 +1310,14 @@ func assembleModel(assert assertionBase) (Assertion, error) {
 		return nil, err
 	}
 
+	connections, err := checkOptionalModelConnections(assert.headers, allSnaps)
+	if err != nil {
+		return nil, err
+	}
+	if len(connections) > 0 && assert.Format() < 1 {
+		return nil, fmt.Errorf(`the "connections" header is only supported for format 1 or greater`)
+	}
+
 	// NB:
 	// * core is not supported at this time, it defaults to ubuntu-core
 	// in prepare-image until rename and/or introduction of the header.
@@ -1219,6 +1338,7 @@ func assembleModel(assert assertionBase) (Assertion, error) {
 		requiredWithEssentialSnaps: requiredWithEssentialSnaps,
 		numEssentialSnaps:          numEssentialSnaps,
 		validationSets:             valSets,
+		connections:                connections,
 		serialAuthority:            serialAuthority,
 		sysUserAuthority:           sysUserAuthority,
 		preseedAuthority:           preseedAuthority,
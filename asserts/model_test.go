@@ -1469,6 +1469,77 @@ func (mods *modelSuite) TestValidationSetsDecodeOK(c *C) {
 	}
 }
 
+func (mods *modelSuite) TestConnectionsDecodeOK(c *C) {
+	encoded := strings.Replace(core20ModelExample, "TSLINE", mods.tsLine, 1)
+	encoded = strings.Replace(encoded, "OTHER", `format: 1
+connections:
+  -
+    plug: myapp:camera
+    slot: system:camera
+  -
+    plug: myapp:network-manager
+    slot: nm:service
+`, 1)
+	a, err := asserts.Decode([]byte(encoded))
+	c.Assert(err, IsNil)
+	model := a.(*asserts.Model)
+	c.Check(model.Connections(), DeepEquals, []*asserts.ModelConnection{
+		{PlugSnap: "myapp", Plug: "camera", SlotSnap: "system", Slot: "camera"},
+		{PlugSnap: "myapp", Plug: "network-manager", SlotSnap: "nm", Slot: "service"},
+	})
+	c.Check(model.Connections()[1].String(), Equals, "myapp:network-manager nm:service")
+
+	// no connections
+	encoded = strings.Replace(core20ModelExample, "TSLINE", mods.tsLine, 1)
+	encoded = strings.Replace(encoded, "OTHER", "", 1)
+	a, err = asserts.Decode([]byte(encoded))
+	c.Assert(err, IsNil)
+	c.Check(a.(*asserts.Model).Connections(), HasLen, 0)
+}
+
+func (mods *modelSuite) TestConnectionsDecodeInvalid(c *C) {
+	encoded := strings.Replace(core20ModelExample, "TSLINE", mods.tsLine, 1)
+	for _, t := range []struct {
+		frag        string
+		expectedErr string
+	}{
+		{"connections: foo\n", `"connections" header must be a list of maps`},
+		{"connections:\n  - foo\n", `"connections" header must be a list of maps`},
+		{"connections:\n  -\n    slot: system:camera\n", `"plug" of connection is mandatory`},
+		{"connections:\n  -\n    plug: myapp:camera\n", `"slot" of connection is mandatory`},
+		{"connections:\n  -\n    plug: myapp\n    slot: system:camera\n", `plug of connection must be <snap>:<plug>, not "myapp"`},
+		{"connections:\n  -\n    plug: my_app:camera\n    slot: system:camera\n", `invalid plug of connection "my_app:camera": invalid snap name: "my_app"`},
+		{"connections:\n  -\n    plug: myapp:camera\n    slot: system:Camera\n", `invalid slot of connection "system:Camera": invalid slot name: "Camera"`},
+		{"connections:\n  -\n    plug: other:camera\n    slot: system:camera\n", `cannot declare connection "other:camera system:camera" of snap "other" not required by the model`},
+		{"connections:\n  -\n    plug: myapp:camera\n    slot: other:camera\n", `cannot declare connection "myapp:camera other:camera" of snap "other" not required by the model`},
+		{"connections:\n  -\n    plug: myapp:camera\n    slot: system:camera\n  -\n    plug: myapp:camera\n    slot: system:camera\n", `cannot declare connection "myapp:camera system:camera" twice`},
+	} {
+		invalid := strings.Replace(encoded, "OTHER", "format: 1\n"+t.frag, 1)
+		_, err := asserts.Decode([]byte(invalid))
+		c.Check(err, ErrorMatches, "assertion model: "+t.expectedErr, Commentf("%s", t.frag))
+	}
+
+	// connections need format 1
+	invalid := strings.Replace(encoded, "OTHER", "connections:\n  -\n    plug: myapp:camera\n    slot: system:camera\n", 1)
+	_, err := asserts.Decode([]byte(invalid))
+	c.Check(err, ErrorMatches, `assertion model: the "connections" header is only supported for format 1 or greater`)
+}
+
+func (mods *modelSuite) TestSuggestFormat(c *C) {
+	fmtnum, err := asserts.SuggestFormat(asserts.ModelType, nil, nil)
+	c.Assert(err, IsNil)
+	c.Check(fmtnum, Equals, 0)
+
+	headers := map[string]any{
+		"connections": []any{
+			map[string]any{"plug": "myapp:camera", "slot": "system:camera"},
+		},
+	}
+	fmtnum, err = asserts.SuggestFormat(asserts.ModelType, headers, nil)
+	c.Assert(err, IsNil)
+	c.Check(fmtnum, Equals, 1)
+}
+
 func (mods *modelSuite) TestModelValidationSetSequenceKey(c *C) {
 	mvs := &asserts.ModelValidationSet{
 		AccountID: "test",
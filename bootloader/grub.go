@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/snapcore/snapd/arch"
@@ -125,9 +126,7 @@ func (g *grub) SetRecoverySystemEnv(recoverySystemDir string, values map[string]
 		return err
 	}
 	genv := grubenv.NewEnv(recoverySystemGrubEnv)
-	for k, v := range values {
-		genv.Set(k, v)
-	}
+	setGrubEnvSorted(genv, values)
 	return genv.Save()
 }
 
@@ -174,12 +173,23 @@ func (g *grub) SetBootVars(values map[string]string) error {
 	if err := env.Load(); err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	for k, v := range values {
-		env.Set(k, v)
-	}
+	setGrubEnvSorted(env, values)
 	return env.Save()
 }
 
+// setGrubEnvSorted sets the values in key order, so that the written grubenv
+// does not depend on the map iteration order.
+func setGrubEnvSorted(env *grubenv.Env, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env.Set(k, values[k])
+	}
+}
+
 func (g *grub) extractedKernelDir(prefix string, s snap.PlaceInfo) string {
 	return filepath.Join(
 		prefix,
@@ -56,6 +56,27 @@ type AppInfo struct {
 	Active      bool             `json:"active,omitempty"`
 	CommonID    string           `json:"common-id,omitempty"`
 	Activators  []AppActivator   `json:"activators,omitempty"`
+	Probes      []AppProbe       `json:"probes,omitempty"`
+}
+
+const (
+	ProbeStatusUnknown = "unknown"
+	ProbeStatusPassing = "passing"
+	ProbeStatusFailing = "failing"
+)
+
+// AppProbe describes a liveness or readiness probe of a service and its
+// status.
+type AppProbe struct {
+	// Kind is either "liveness" or "readiness".
+	Kind string `json:"kind"`
+	// Type is one of "exec", "tcp" or "http".
+	Type   string    `json:"type"`
+	Status string    `json:"status"`
+	Since  time.Time `json:"since,omitempty"`
+	// Message is the error of the last failed run of the probe.
+	Message  string `json:"message,omitempty"`
+	Restarts int    `json:"restarts,omitempty"`
 }
 
 // MarshalJSON marshals the AppActivator in such a way to retain
@@ -672,6 +672,27 @@ type RefreshInfo struct {
 	Last     string `json:"last,omitempty"`
 	Hold     string `json:"hold,omitempty"`
 	Next     string `json:"next,omitempty"`
+	// SnapTimers contains the refresh.snap-timer settings, keyed by snap
+	// instance name.
+	SnapTimers map[string]string `json:"snap-timers,omitempty"`
+	// Blackout contains the refresh.blackout setting.
+	Blackout string `json:"blackout,omitempty"`
+	// Staging contains the staging state of auto-refreshes for snaps with
+	// a refresh.staging delay, keyed by snap instance name.
+	Staging map[string]RefreshStagingInfo `json:"staging,omitempty"`
+}
+
+// RefreshStagingInfo contains information about the staging of
+// auto-refreshes of a snap.
+type RefreshStagingInfo struct {
+	// MaxDelay contains the refresh.staging setting.
+	MaxDelay string `json:"max-delay"`
+	// Delay is the share of MaxDelay that applies to this device.
+	Delay string `json:"delay"`
+	// Revision is the new revision being staged, if any.
+	Revision string `json:"revision,omitempty"`
+	// Until is when the staged revision will be accepted by auto-refreshes.
+	Until string `json:"until,omitempty"`
 }
 
 // SysInfo holds system information
@@ -781,6 +802,17 @@ func (client *Client) DebugGet(aspect string, result any, params map[string]stri
 	return err
 }
 
+// DebugDenials lists the recent security denials of the given snap, searching
+// the given number of journal entries or a default number if zero.
+func (client *Client) DebugDenials(snapName string, lines int, result any) error {
+	query := url.Values{"snap": []string{snapName}}
+	if lines > 0 {
+		query.Set("lines", strconv.Itoa(lines))
+	}
+	_, err := client.doSync("GET", "/v2/debug/denials", query, nil, nil, &result)
+	return err
+}
+
 type SystemRecoveryKeysResponse struct {
 	RecoveryKey  string `json:"recovery-key"`
 	ReinstallKey string `json:"reinstall-key,omitempty"`
@@ -806,6 +838,29 @@ func (c *Client) MigrateSnapHome(snaps []string) (changeID string, err error) {
 	return c.doAsync("POST", "/v2/debug", nil, nil, bytes.NewReader(body))
 }
 
+// SetDeviceAudit enables or disables auditing of the device accesses denied
+// by the device cgroup of the given snap.
+func (c *Client) SetDeviceAudit(snapName string, enable bool) (changeID string, err error) {
+	body, err := json.Marshal(struct {
+		Action string   `json:"action"`
+		Snaps  []string `json:"snaps"`
+		Params struct {
+			Enable bool `json:"enable"`
+		} `json:"params"`
+	}{
+		Action: "device-audit",
+		Snaps:  []string{snapName},
+		Params: struct {
+			Enable bool `json:"enable"`
+		}{Enable: enable},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return c.doAsync("POST", "/v2/debug", nil, nil, bytes.NewReader(body))
+}
+
 // DebugRaw allows to make raw queries to the API with the intention of using it
 // from the debug code.
 func (client *Client) DebugRaw(ctx context.Context, method, urlpath string, query url.Values, headers map[string]string, body io.Reader) (*http.Response, error) {
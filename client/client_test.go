@@ -646,6 +646,24 @@ func (cs *clientSuite) TestDebugGet(c *C) {
 	c.Check(cs.reqs[0].URL.Query(), DeepEquals, url.Values{"aspect": []string{"do-something"}, "foo": []string{"bar"}})
 }
 
+func (cs *clientSuite) TestDebugDenials(c *C) {
+	cs.rsp = `{"type": "sync", "result":[{"kind": "seccomp"}]}`
+
+	var result []map[string]any
+	err := cs.cli.DebugDenials("foo", 100, &result)
+	c.Check(err, IsNil)
+	c.Check(result, DeepEquals, []map[string]any{{"kind": "seccomp"}})
+	c.Check(cs.reqs, HasLen, 1)
+	c.Check(cs.reqs[0].Method, Equals, "GET")
+	c.Check(cs.reqs[0].URL.Path, Equals, "/v2/debug/denials")
+	c.Check(cs.reqs[0].URL.Query(), DeepEquals, url.Values{"snap": []string{"foo"}, "lines": []string{"100"}})
+
+	err = cs.cli.DebugDenials("foo", 0, &result)
+	c.Check(err, IsNil)
+	c.Check(cs.reqs, HasLen, 2)
+	c.Check(cs.reqs[1].URL.Query(), DeepEquals, url.Values{"snap": []string{"foo"}})
+}
+
 func (cs *clientSuite) TestDebugMigrateHome(c *C) {
 	cs.status = 202
 	cs.rsp = `{"type": "async", "status-code": 202, "change": "123"}`
@@ -663,6 +681,22 @@ func (cs *clientSuite) TestDebugMigrateHome(c *C) {
 	c.Check(string(data), Equals, `{"action":"migrate-home","snaps":["foo","bar"]}`)
 }
 
+func (cs *clientSuite) TestDebugSetDeviceAudit(c *C) {
+	cs.status = 202
+	cs.rsp = `{"type": "async", "status-code": 202, "change": "123"}`
+
+	changeID, err := cs.cli.SetDeviceAudit("foo", true)
+	c.Check(err, IsNil)
+	c.Check(changeID, Equals, "123")
+
+	c.Check(cs.reqs, HasLen, 1)
+	c.Check(cs.reqs[0].Method, Equals, "POST")
+	c.Check(cs.reqs[0].URL.Path, Equals, "/v2/debug")
+	data, err := io.ReadAll(cs.reqs[0].Body)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"action":"device-audit","snaps":["foo"],"params":{"enable":true}}`)
+}
+
 type integrationSuite struct{}
 
 var _ = Suite(&integrationSuite{})
@@ -101,13 +101,15 @@ func ClientAppInfoNotes(app *client.AppInfo) string {
 	if app.DaemonScope == snap.UserDaemon {
 		notes = append(notes, "user")
 	}
-	var seenTimer, seenSocket, seenDbus bool
+	var seenTimer, seenSocket, seenPath, seenDbus bool
 	for _, act := range app.Activators {
 		switch act.Type {
 		case "timer":
 			seenTimer = true
 		case "socket":
 			seenSocket = true
+		case "path":
+			seenPath = true
 		case "dbus":
 			seenDbus = true
 		}
@@ -118,9 +120,23 @@ func ClientAppInfoNotes(app *client.AppInfo) string {
 	if seenSocket {
 		notes = append(notes, "socket-activated")
 	}
+	if seenPath {
+		notes = append(notes, "path-activated")
+	}
 	if seenDbus {
 		notes = append(notes, "dbus-activated")
 	}
+	for _, probe := range app.Probes {
+		if probe.Status != client.ProbeStatusFailing {
+			continue
+		}
+		switch probe.Kind {
+		case "liveness":
+			notes = append(notes, "unhealthy")
+		case "readiness":
+			notes = append(notes, "not-ready")
+		}
+	}
 	if len(notes) == 0 {
 		return "-"
 	}
@@ -146,6 +162,13 @@ func ClientAppInfosFromSnapAppInfos(apps []*snap.AppInfo, decorator StatusDecora
 
 		appInfo.Daemon = app.Daemon
 		appInfo.DaemonScope = app.DaemonScope
+		for _, probe := range app.Probes() {
+			appInfo.Probes = append(appInfo.Probes, client.AppProbe{
+				Kind:   string(probe.Kind),
+				Type:   probe.Type(),
+				Status: client.ProbeStatusUnknown,
+			})
+		}
 		if !app.IsService() || decorator == nil || !app.Snap.IsActive() {
 			out = append(out, appInfo)
 			continue
@@ -300,6 +300,14 @@ func (*cmdSuite) TestAppStatusNotes(c *C) {
 	}
 	c.Check(clientutil.ClientAppInfoNotes(&ai), Equals, "socket-activated")
 
+	ai = client.AppInfo{
+		Daemon: "simple",
+		Activators: []client.AppActivator{
+			{Type: "path"},
+		},
+	}
+	c.Check(clientutil.ClientAppInfoNotes(&ai), Equals, "path-activated")
+
 	ai = client.AppInfo{
 		Daemon: "oneshot",
 		Activators: []client.AppActivator{
@@ -328,4 +336,43 @@ func (*cmdSuite) TestAppStatusNotes(c *C) {
 		},
 	}
 	c.Check(clientutil.ClientAppInfoNotes(&ai), Equals, "user,timer-activated,socket-activated,dbus-activated")
+
+	// failing probes are noted
+	ai = client.AppInfo{
+		Daemon: "simple",
+		Probes: []client.AppProbe{
+			{Kind: "liveness", Status: client.ProbeStatusPassing},
+			{Kind: "readiness", Status: client.ProbeStatusFailing},
+		},
+	}
+	c.Check(clientutil.ClientAppInfoNotes(&ai), Equals, "not-ready")
+	ai = client.AppInfo{
+		Daemon: "simple",
+		Probes: []client.AppProbe{
+			{Kind: "liveness", Status: client.ProbeStatusFailing},
+			{Kind: "readiness", Status: client.ProbeStatusUnknown},
+		},
+	}
+	c.Check(clientutil.ClientAppInfoNotes(&ai), Equals, "unhealthy")
+}
+
+func (*cmdSuite) TestClientAppInfosFromSnapAppInfosProbes(c *C) {
+	si := &snap.Info{SideInfo: snap.SideInfo{RealName: "the-snap", Revision: snap.R(1)}}
+	svc := &snap.AppInfo{Snap: si, Name: "svc", Daemon: "simple", DaemonScope: snap.SystemDaemon}
+	svc.LivenessProbe = &snap.ProbeInfo{App: svc, Kind: snap.LivenessProbe, HTTPPort: 8080, HTTPPath: "/"}
+	svc.ReadinessProbe = &snap.ProbeInfo{App: svc, Kind: snap.ReadinessProbe, Exec: "bin/ready"}
+	si.Apps = map[string]*snap.AppInfo{"svc": svc}
+
+	apps, err := clientutil.ClientAppInfosFromSnapAppInfos([]*snap.AppInfo{svc}, nil)
+	c.Assert(err, IsNil)
+	c.Check(apps, DeepEquals, []client.AppInfo{{
+		Snap:        "the-snap",
+		Name:        "svc",
+		Daemon:      "simple",
+		DaemonScope: snap.SystemDaemon,
+		Probes: []client.AppProbe{
+			{Kind: "liveness", Type: "http", Status: client.ProbeStatusUnknown},
+			{Kind: "readiness", Type: "exec", Status: client.ProbeStatusUnknown},
+		},
+	}})
 }
@@ -21,6 +21,7 @@ package client
 
 import (
 	"net/url"
+	"time"
 )
 
 // Connection describes a connection between a plug and a slot.
@@ -32,10 +33,17 @@ type Connection struct {
 	Manual bool `json:"manual"`
 	// Gadget is set for connections that were enabled by the gadget snap.
 	Gadget bool `json:"gadget"`
+	// Model is set for connections that were declared by the model.
+	Model bool `json:"model,omitempty"`
 	// SlotAttrs is the list of attributes of the slot side of the connection.
 	SlotAttrs map[string]any `json:"slot-attrs,omitempty"`
 	// PlugAttrs is the list of attributes of the plug side of the connection.
 	PlugAttrs map[string]any `json:"plug-attrs,omitempty"`
+	// Expiry is set for time-limited connections to the time they are
+	// automatically disconnected at.
+	Expiry time.Time `json:"expiry,omitzero"`
+	// Users is set for connections limited to some users to their IDs.
+	Users []int `json:"users,omitempty"`
 }
 
 // Connections contains information about connections, as well as related plugs
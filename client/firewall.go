@@ -0,0 +1,53 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// FirewallStatus holds the state of the firewall configuration.
+type FirewallStatus struct {
+	// ConfirmBefore is set when the firewall rules applied last are rolled
+	// back unless confirmed before that time.
+	ConfirmBefore *time.Time `json:"confirm-before,omitempty"`
+}
+
+// Firewall returns the state of the firewall configuration.
+func (client *Client) Firewall() (*FirewallStatus, error) {
+	var status FirewallStatus
+	if _, err := client.doSync("GET", "/v2/system-firewall", nil, nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ConfirmFirewall confirms the firewall rules applied last, so that they are
+// kept instead of being rolled back.
+func (client *Client) ConfirmFirewall() error {
+	body, err := json.Marshal(map[string]string{"action": "confirm"})
+	if err != nil {
+		return err
+	}
+	_, err = client.doSync("POST", "/v2/system-firewall", nil, nil, bytes.NewReader(body), nil)
+	return err
+}
@@ -0,0 +1,82 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (cs *clientSuite) TestClientFirewall(c *C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": {"confirm-before": "2026-10-19T12:00:00Z"}
+	}`
+
+	status, err := cs.cli.Firewall()
+	c.Assert(err, IsNil)
+	c.Check(cs.req.Method, Equals, "GET")
+	c.Check(cs.req.URL.Path, Equals, "/v2/system-firewall")
+	c.Assert(status.ConfirmBefore, NotNil)
+	c.Check(status.ConfirmBefore.Equal(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)), Equals, true)
+}
+
+func (cs *clientSuite) TestClientFirewallNothingPending(c *C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": {}
+	}`
+
+	status, err := cs.cli.Firewall()
+	c.Assert(err, IsNil)
+	c.Check(status.ConfirmBefore, IsNil)
+}
+
+func (cs *clientSuite) TestClientConfirmFirewall(c *C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": null
+	}`
+
+	err := cs.cli.ConfirmFirewall()
+	c.Assert(err, IsNil)
+	c.Check(cs.req.Method, Equals, "POST")
+	c.Check(cs.req.URL.Path, Equals, "/v2/system-firewall")
+	var body map[string]any
+	c.Assert(json.NewDecoder(cs.req.Body).Decode(&body), IsNil)
+	c.Check(body, DeepEquals, map[string]any{"action": "confirm"})
+}
+
+func (cs *clientSuite) TestClientConfirmFirewallError(c *C) {
+	cs.status = 400
+	cs.rsp = `{
+		"type": "error",
+		"status-code": 400,
+		"result": {"message": "no firewall changes are waiting for confirmation"}
+	}`
+
+	err := cs.cli.ConfirmFirewall()
+	c.Check(err, ErrorMatches, "no firewall changes are waiting for confirmation")
+}
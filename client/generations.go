@@ -0,0 +1,82 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/snapcore/snapd/snap"
+)
+
+// SnapSetGeneration is a set of snaps that were installed or refreshed
+// together by a single change, and that can be reverted as a whole.
+type SnapSetGeneration struct {
+	ID       int                     `json:"id"`
+	ChangeID string                  `json:"change-id"`
+	Time     time.Time               `json:"time"`
+	Snaps    []SnapSetGenerationSnap `json:"snaps"`
+}
+
+// SnapSetGenerationSnap holds the revisions of a snap of a snap set
+// generation. Before is unset if the snap was installed by the generation.
+type SnapSetGenerationSnap struct {
+	Name   string        `json:"name"`
+	Before snap.Revision `json:"before"`
+	After  snap.Revision `json:"after"`
+}
+
+// SnapSetGenerations lists the remembered snap set generations, oldest
+// first.
+func (client *Client) SnapSetGenerations() ([]*SnapSetGeneration, error) {
+	var res []*SnapSetGeneration
+	if _, err := client.doSync("GET", "/v2/snap-set-generations", nil, nil, nil, &res); err != nil {
+		return nil, fmt.Errorf("cannot list snap set generations: %w", err)
+	}
+	return res, nil
+}
+
+type postSnapSetGenerationData struct {
+	Action string `json:"action"`
+	ID     int    `json:"id"`
+	Purge  bool   `json:"purge,omitempty"`
+}
+
+// RevertSnapSetGeneration reverts all the snaps of the given snap set
+// generation to the revisions they had before it. Only the Purge option is
+// taken into account, for the snaps the generation installed.
+func (client *Client) RevertSnapSetGeneration(id int, options *SnapOptions) (changeID string, err error) {
+	if options == nil {
+		options = &SnapOptions{}
+	}
+	data := &postSnapSetGenerationData{
+		Action: "revert",
+		ID:     id,
+		Purge:  options.Purge,
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(data); err != nil {
+		return "", err
+	}
+	return client.doAsync("POST", "/v2/snap-set-generations", nil, nil, &body)
+}
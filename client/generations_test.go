@@ -0,0 +1,88 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/snap"
+)
+
+func (cs *clientSuite) TestClientSnapSetGenerations(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": [{
+			"id": 2,
+			"change-id": "42",
+			"time": "2026-10-01T10:00:00Z",
+			"snaps": [
+				{"name": "new-snap", "before": "unset", "after": "3"},
+				{"name": "some-snap", "before": "1", "after": "2"}
+			]
+		}]
+	}`
+	gens, err := cs.cli.SnapSetGenerations()
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snap-set-generations")
+	c.Check(gens, check.DeepEquals, []*client.SnapSetGeneration{{
+		ID:       2,
+		ChangeID: "42",
+		Time:     time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+		Snaps: []client.SnapSetGenerationSnap{
+			{Name: "new-snap", After: snap.R(3)},
+			{Name: "some-snap", Before: snap.R(1), After: snap.R(2)},
+		},
+	}})
+}
+
+func (cs *clientSuite) TestClientSnapSetGenerationsError(c *check.C) {
+	cs.err = errors.New("boom")
+	_, err := cs.cli.SnapSetGenerations()
+	c.Check(err, check.ErrorMatches, "cannot list snap set generations: .*boom")
+}
+
+func (cs *clientSuite) TestClientRevertSnapSetGeneration(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"type": "async",
+		"status-code": 202,
+		"result": { },
+		"change": "chgid"
+	}`
+	id, err := cs.cli.RevertSnapSetGeneration(2, &client.SnapOptions{Purge: true})
+	c.Assert(err, check.IsNil)
+	c.Check(id, check.Equals, "chgid")
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snap-set-generations")
+	var body map[string]any
+	c.Assert(json.NewDecoder(cs.req.Body).Decode(&body), check.IsNil)
+	c.Check(body, check.DeepEquals, map[string]any{
+		"action": "revert",
+		"id":     float64(2),
+		"purge":  true,
+	})
+}
@@ -22,8 +22,10 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // Plug represents the potential of a given snap to connect to a slot.
@@ -71,10 +73,13 @@ type Interface struct {
 
 // InterfaceAction represents an action performed on the interface system.
 type InterfaceAction struct {
-	Action string `json:"action"`
-	Forget bool   `json:"forget,omitempty"`
-	Plugs  []Plug `json:"plugs,omitempty"`
-	Slots  []Slot `json:"slots,omitempty"`
+	Action   string    `json:"action"`
+	Forget   bool      `json:"forget,omitempty"`
+	Plugs    []Plug    `json:"plugs,omitempty"`
+	Slots    []Slot    `json:"slots,omitempty"`
+	Expiry   time.Time `json:"expiry,omitzero"`
+	Duration string    `json:"duration,omitempty"`
+	Users    []int     `json:"users,omitempty"`
 }
 
 // InterfaceOptions represents opt-in elements include in responses.
@@ -86,9 +91,28 @@ type InterfaceOptions struct {
 	Connected bool
 }
 
+// ConnectOptions represents extra options for connect op
+type ConnectOptions struct {
+	// Expiry makes the connection time-limited, it is disconnected
+	// automatically at the given time.
+	Expiry time.Time
+	// Duration makes the connection time-limited, it is disconnected
+	// automatically once the duration elapsed.
+	Duration time.Duration
+	// Users limits the connection to the given user IDs.
+	Users []int
+	// ForSelf limits the connection to the calling user, it can be
+	// used by users who cannot manage interfaces for everyone.
+	ForSelf bool
+}
+
 // DisconnectOptions represents extra options for disconnect op
 type DisconnectOptions struct {
 	Forget bool
+	// Users disconnects the given user IDs only.
+	Users []int
+	// ForSelf disconnects the calling user only.
+	ForSelf bool
 }
 
 func (client *Client) Interfaces(opts *InterfaceOptions) ([]*Interface, error) {
@@ -121,29 +145,69 @@ func (client *Client) Interfaces(opts *InterfaceOptions) ([]*Interface, error) {
 
 // performInterfaceAction performs a single action on the interface system.
 func (client *Client) performInterfaceAction(sa *InterfaceAction) (changeID string, err error) {
+	return client.performInterfaceActionAt("/v2/interfaces", sa)
+}
+
+// performOwnInterfaceAction performs a single action on the interface
+// system, limited to the calling user.
+func (client *Client) performOwnInterfaceAction(sa *InterfaceAction) (changeID string, err error) {
+	return client.performInterfaceActionAt("/v2/interfaces/own", sa)
+}
+
+func (client *Client) performInterfaceActionAt(urlpath string, sa *InterfaceAction) (changeID string, err error) {
 	b, err := json.Marshal(sa)
 	if err != nil {
 		return "", err
 	}
-	return client.doAsync("POST", "/v2/interfaces", nil, nil, bytes.NewReader(b))
+	return client.doAsync("POST", urlpath, nil, nil, bytes.NewReader(b))
 }
 
 // Connect establishes a connection between a plug and a slot.
 // The plug and the slot must have the same interface.
 func (client *Client) Connect(plugSnapName, plugName, slotSnapName, slotName string) (changeID string, err error) {
-	return client.performInterfaceAction(&InterfaceAction{
+	return client.ConnectWithOptions(plugSnapName, plugName, slotSnapName, slotName, nil)
+}
+
+// ConnectWithOptions establishes a connection between a plug and a slot, like
+// Connect, with the given options.
+func (client *Client) ConnectWithOptions(plugSnapName, plugName, slotSnapName, slotName string, opts *ConnectOptions) (changeID string, err error) {
+	action := &InterfaceAction{
 		Action: "connect",
 		Plugs:  []Plug{{Snap: plugSnapName, Name: plugName}},
 		Slots:  []Slot{{Snap: slotSnapName, Name: slotName}},
-	})
+	}
+	if opts != nil {
+		action.Expiry = opts.Expiry
+		if opts.Duration != 0 {
+			action.Duration = opts.Duration.String()
+		}
+		action.Users = opts.Users
+		if opts.ForSelf {
+			if len(opts.Users) > 0 {
+				return "", fmt.Errorf("cannot connect for self and for other users at the same time")
+			}
+			return client.performOwnInterfaceAction(action)
+		}
+	}
+	return client.performInterfaceAction(action)
 }
 
 // Disconnect breaks the connection between a plug and a slot.
 func (client *Client) Disconnect(plugSnapName, plugName, slotSnapName, slotName string, opts *DisconnectOptions) (changeID string, err error) {
-	return client.performInterfaceAction(&InterfaceAction{
+	action := &InterfaceAction{
 		Action: "disconnect",
-		Forget: opts != nil && opts.Forget,
 		Plugs:  []Plug{{Snap: plugSnapName, Name: plugName}},
 		Slots:  []Slot{{Snap: slotSnapName, Name: slotName}},
-	})
+	}
+	if opts != nil {
+		action.Forget = opts.Forget
+		action.Users = opts.Users
+		if opts.ForSelf {
+			if len(opts.Users) > 0 {
+				return "", fmt.Errorf("cannot disconnect for self and for other users at the same time")
+			}
+			return client.performOwnInterfaceAction(action)
+		}
+	}
+	return client.performInterfaceAction(action)
 }
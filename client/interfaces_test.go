@@ -21,6 +21,7 @@ package client_test
 
 import (
 	"encoding/json"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -194,6 +195,89 @@ func (cs *clientSuite) TestClientConnect(c *check.C) {
 	})
 }
 
+func (cs *clientSuite) TestClientConnectWithOptions(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"type": "async",
+		"status-code": 202,
+		"result": { },
+		"change": "foo"
+	}`
+	id, err := cs.cli.ConnectWithOptions("producer", "plug", "consumer", "slot", &client.ConnectOptions{
+		Duration: 90 * time.Minute,
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(id, check.Equals, "foo")
+	var body map[string]any
+	decoder := json.NewDecoder(cs.req.Body)
+	err = decoder.Decode(&body)
+	c.Check(err, check.IsNil)
+	c.Check(body, check.DeepEquals, map[string]any{
+		"action": "connect",
+		"plugs": []any{
+			map[string]any{
+				"snap": "producer",
+				"plug": "plug",
+			},
+		},
+		"slots": []any{
+			map[string]any{
+				"snap": "consumer",
+				"slot": "slot",
+			},
+		},
+		"duration": "1h30m0s",
+	})
+
+	expiry := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	_, err = cs.cli.ConnectWithOptions("producer", "plug", "consumer", "slot", &client.ConnectOptions{
+		Expiry: expiry,
+	})
+	c.Assert(err, check.IsNil)
+	body = nil
+	decoder = json.NewDecoder(cs.req.Body)
+	err = decoder.Decode(&body)
+	c.Check(err, check.IsNil)
+	c.Check(body["expiry"], check.Equals, "2026-10-20T12:00:00Z")
+	c.Check(body["duration"], check.IsNil)
+}
+
+func (cs *clientSuite) TestClientConnectForUsers(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"type": "async",
+		"status-code": 202,
+		"result": { },
+		"change": "foo"
+	}`
+	_, err := cs.cli.ConnectWithOptions("producer", "plug", "consumer", "slot", &client.ConnectOptions{
+		Users: []int{1000, 1001},
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces")
+	var body map[string]any
+	err = json.NewDecoder(cs.req.Body).Decode(&body)
+	c.Check(err, check.IsNil)
+	c.Check(body["users"], check.DeepEquals, []any{1000.0, 1001.0})
+
+	_, err = cs.cli.ConnectWithOptions("producer", "plug", "consumer", "slot", &client.ConnectOptions{
+		ForSelf: true,
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/own")
+	body = nil
+	err = json.NewDecoder(cs.req.Body).Decode(&body)
+	c.Check(err, check.IsNil)
+	c.Check(body["action"], check.Equals, "connect")
+	c.Check(body["users"], check.IsNil)
+
+	_, err = cs.cli.ConnectWithOptions("producer", "plug", "consumer", "slot", &client.ConnectOptions{
+		Users:   []int{1000},
+		ForSelf: true,
+	})
+	c.Check(err, check.ErrorMatches, "cannot connect for self and for other users at the same time")
+}
+
 func (cs *clientSuite) TestClientDisconnectCallsEndpoint(c *check.C) {
 	cs.cli.Disconnect("producer", "plug", "consumer", "slot", nil)
 	c.Check(cs.req.Method, check.Equals, "POST")
@@ -266,3 +350,27 @@ func (cs *clientSuite) TestClientDisconnectForget(c *check.C) {
 		},
 	})
 }
+
+func (cs *clientSuite) TestClientDisconnectForUsers(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"type": "async",
+		"status-code": 202,
+		"result": { },
+		"change": "42"
+	}`
+	_, err := cs.cli.Disconnect("producer", "plug", "consumer", "slot", &client.DisconnectOptions{Users: []int{1000}})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces")
+	var body map[string]any
+	err = json.NewDecoder(cs.req.Body).Decode(&body)
+	c.Check(err, check.IsNil)
+	c.Check(body["users"], check.DeepEquals, []any{1000.0})
+
+	_, err = cs.cli.Disconnect("producer", "plug", "consumer", "slot", &client.DisconnectOptions{ForSelf: true})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/own")
+
+	_, err = cs.cli.Disconnect("producer", "plug", "consumer", "slot", &client.DisconnectOptions{Users: []int{1000}, ForSelf: true})
+	c.Check(err, check.ErrorMatches, "cannot disconnect for self and for other users at the same time")
+}
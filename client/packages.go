@@ -160,6 +160,9 @@ var ErrNoSnapsInstalled = errors.New("no snaps installed")
 
 type ListOptions struct {
 	All bool
+	// Autoremove selects the snaps that were installed only as
+	// prerequisites of other snaps and are no longer needed.
+	Autoremove bool
 }
 
 // Information about a category
@@ -175,8 +178,13 @@ func (client *Client) List(names []string, opts *ListOptions) ([]*Snap, error) {
 	}
 
 	q := make(url.Values)
-	if opts.All {
+	switch {
+	case opts.All && opts.Autoremove:
+		return nil, fmt.Errorf("cannot select all and autoremove snaps together")
+	case opts.All:
 		q.Add("select", "all")
+	case opts.Autoremove:
+		q.Add("select", "autoremove")
 	}
 	if len(names) > 0 {
 		q.Add("snaps", strings.Join(names, ","))
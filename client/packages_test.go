@@ -40,6 +40,18 @@ func (cs *clientSuite) TestClientSnapsCallsEndpoint(c *check.C) {
 	c.Check(cs.req.URL.Query(), check.DeepEquals, url.Values{})
 }
 
+func (cs *clientSuite) TestClientSnapsAutoremoveSetsQuery(c *check.C) {
+	_, _ = cs.cli.List(nil, &client.ListOptions{Autoremove: true})
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snaps")
+	c.Check(cs.req.URL.Query(), check.DeepEquals, url.Values{
+		"select": []string{"autoremove"},
+	})
+
+	_, err := cs.cli.List(nil, &client.ListOptions{All: true, Autoremove: true})
+	c.Check(err, check.ErrorMatches, "cannot select all and autoremove snaps together")
+}
+
 func (cs *clientSuite) TestClientFindRefreshSetsQuery(c *check.C) {
 	_, _, _ = cs.cli.Find(&client.FindOptions{
 		Refresh: true,
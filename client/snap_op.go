@@ -160,6 +160,12 @@ func (client *Client) RemoveMany(names []string, components map[string][]string,
 	return client.doMultiSnapAction("remove", names, components, options)
 }
 
+// Autoremove removes the snaps that were installed only as prerequisites of
+// other snaps and are no longer needed by any installed snap.
+func (client *Client) Autoremove(options *SnapOptions) (changeID string, err error) {
+	return client.doMultiSnapAction("autoremove", nil, nil, options)
+}
+
 // Refresh refreshes the snap with the given name (switching it to track
 // the given channel if given).
 func (client *Client) Refresh(name string, components []string, options *SnapOptions) (changeID string, err error) {
@@ -24,7 +24,10 @@
 #include "utils.h"
 
 #include <errno.h>
+#include <limits.h>
+#include <stdio.h>
 #include <string.h>
+#include <unistd.h>
 #ifdef HAVE_APPARMOR
 #include <sys/apparmor.h>
 #endif  // ifdef HAVE_APPARMOR
@@ -40,6 +43,8 @@
 #define SC_AA_KILL_STR "kill"
 #define SC_AA_UNCONFINED_STR "unconfined"
 
+#define SC_APPARMOR_PROFILES_DIR "/var/lib/snapd/apparmor/profiles"
+
 void sc_init_apparmor_support(struct sc_apparmor *apparmor) {
 #ifdef HAVE_APPARMOR
     // Use aa_is_enabled() to see if apparmor is available in the kernel and
@@ -137,3 +142,17 @@ void sc_maybe_aa_change_onexec(struct sc_apparmor *apparmor, const char *profile
     }
 #endif  // ifdef HAVE_APPARMOR
 }
+
+void sc_maybe_aa_change_onexec_for_user(struct sc_apparmor *apparmor, const char *profile, uid_t uid) {
+    char user_profile[PATH_MAX] = {0};
+    char user_profile_path[PATH_MAX] = {0};
+    sc_must_snprintf(user_profile, sizeof user_profile, "%s.user-%lu", profile, (unsigned long)uid);
+    sc_must_snprintf(user_profile_path, sizeof user_profile_path, "%s/%s", SC_APPARMOR_PROFILES_DIR, user_profile);
+    // Snapd writes a variant of the profile for each user with connections
+    // limited to them, use it when there is one for the calling user.
+    if (access(user_profile_path, F_OK) == 0) {
+        sc_maybe_aa_change_onexec(apparmor, user_profile);
+        return;
+    }
+    sc_maybe_aa_change_onexec(apparmor, profile);
+}
@@ -19,6 +19,7 @@
 #define SNAP_CONFINE_APPARMOR_SUPPORT_H
 
 #include <stdbool.h>
+#include <sys/types.h>
 
 /**
  * Type of apparmor confinement.
@@ -78,4 +79,13 @@ void sc_init_apparmor_support(struct sc_apparmor *apparmor);
  **/
 void sc_maybe_aa_change_onexec(struct sc_apparmor *apparmor, const char *profile);
 
+/**
+ * Maybe call aa_change_onexec(2) with the variant of the profile of the user
+ *
+ * The variant named <profile>.user-<uid> is used when snapd wrote it, which
+ * happens when some connections of the snap are limited to the given user.
+ * Otherwise this behaves as sc_maybe_aa_change_onexec.
+ **/
+void sc_maybe_aa_change_onexec_for_user(struct sc_apparmor *apparmor, const char *profile, uid_t uid);
+
 #endif
@@ -59,6 +59,7 @@ struct sc_device_cgroup {
 #ifdef ENABLE_BPF
         struct {
             int devmap_fd;
+            int auditmap_fd;
             int prog_fd;
             char *tag;
             char pretty_name[BPF_OBJ_NAME_LEN]; /* only for presentation */
@@ -78,6 +79,9 @@ static int _sc_cgroup_v1_init(sc_device_cgroup *self, int flags) {
 
     /* are we creating the group or just using whatever there is? */
     const bool from_existing = (flags & SC_DEVICE_CGROUP_FROM_EXISTING) != 0;
+    if ((flags & SC_DEVICE_CGROUP_AUDIT) != 0) {
+        debug("device access auditing is not supported with cgroup v1");
+    }
     /* initialize to something sane */
     if (sc_udev_open_cgroup_v1(self->security_tag, flags, &self->v1.fds) < 0) {
         if (from_existing) {
@@ -151,8 +155,14 @@ typedef struct sc_cgroup_v2_device_key sc_cgroup_v2_device_key;
  */
 typedef uint8_t sc_cgroup_v2_device_value;
 
+/**
+ * sc_cgroup_v2_device_audit_value holds the number of denied accesses to a
+ * device, which is the value stored in the audit map keyed by the device
+ */
+typedef uint64_t sc_cgroup_v2_device_audit_value;
+
 #ifdef ENABLE_BPF
-static int load_devcgroup_prog(int map_fd, const char *name) {
+static int load_devcgroup_prog(int map_fd, int audit_map_fd, const char *name) {
     /* Basic rules about registers:
      * r0    - return value of built in functions and exit code of the program
      * r1-r5 - respective arguments to built in functions, clobbered by calls
@@ -164,6 +174,10 @@ static int load_devcgroup_prog(int map_fd, const char *name) {
      * this:
      *   int program(struct bpf_cgroup_dev_ctx * ctx)
      * where *ctx is passed in r1, while the result goes to r0
+     *
+     * When audit_map_fd is valid, accesses which end up being denied are
+     * counted in the audit map, keyed by the device. The outcome of the
+     * program is the same regardless.
      */
 
     /* just a placeholder for map value where the value is 1 byte, but
@@ -182,8 +196,13 @@ static int load_devcgroup_prog(int map_fd, const char *name) {
      * described above and such that the whole structure fits on the stack (even
      * with some spare room) */
     size_t key_start = 17;
+    /* stack offset of the initial audit map value, aligned to 8 bytes and
+     * below the key */
+    const int audit_value_start = 32;
     struct bpf_insn prog[] = {
         /* r1 holds pointer to bpf_cgroup_dev_ctx */
+        /* keep the context around for auditing, r1 is clobbered by calls */
+        BPF_MOV64_REG(BPF_REG_7, BPF_REG_1), /* r7 = r1 */
         /* initialize r0 */
         BPF_MOV64_IMM(BPF_REG_0, 0), /* r0 = 0 */
         /* make some place on the stack for the key */
@@ -235,16 +254,55 @@ static int load_devcgroup_prog(int map_fd, const char *name) {
         BPF_JMP_IMM(BPF_JEQ, BPF_REG_0, 0, 2),                               /* if (value_ptr == 0) goto pc + 2 */
         /* we found a match with any minor number for that type|major */
         BPF_MOV64_IMM(BPF_REG_0, 1), /* r0 = 1 */
-        BPF_JMP_A(1),
+        BPF_EXIT_INSN(),
+        /* no match, access denied, fall through to the deny tail */
+    };
+    struct bpf_insn audit[] = {
+        /* restore the minor number of the key */
+        BPF_LDX_MEM(BPF_W, BPF_REG_2, BPF_REG_7,
+                    offsetof(struct bpf_cgroup_dev_ctx, minor)), /* r2 = *(u32)(r7->minor) */
+        BPF_STX_MEM(BPF_W, BPF_REG_6, BPF_REG_2,
+                    offsetof(struct sc_cgroup_v2_device_key, minor)), /* *(r6 + offsetof(minor)) = r2 */
+        BPF_LD_MAP_FD(BPF_REG_1, audit_map_fd),
+        BPF_MOV64_REG(BPF_REG_2, BPF_REG_6),                                 /* r2 = (struct key *) r6, */
+        BPF_RAW_INSN(BPF_JMP | BPF_CALL, 0, 0, 0, BPF_FUNC_map_lookup_elem), /* r0 = bpf_map_lookup_elem(<audit map>,
+                                                                                &key) */
+        BPF_JMP_IMM(BPF_JEQ, BPF_REG_0, 0, 3),                               /* if (value_ptr == 0) goto pc + 3 */
+        /* the device was denied before, bump the counter */
+        BPF_MOV64_IMM(BPF_REG_1, 1),                   /* r1 = 1 */
+        BPF_STX_XADD(BPF_DW, BPF_REG_0, BPF_REG_1, 0), /* lock *(u64 *)r0 += r1 */
+        BPF_JMP_A(8),                                  /* goto pc + 8 */
+        /* first denial of the device, add it to the map */
+        BPF_ST_MEM(BPF_DW, BPF_REG_10, -audit_value_start, 1), /* *(u64 *)(sp - value start offset) = 1 */
+        BPF_LD_MAP_FD(BPF_REG_1, audit_map_fd),
+        BPF_MOV64_REG(BPF_REG_2, BPF_REG_6),                                 /* r2 = (struct key *) r6 */
+        BPF_MOV64_REG(BPF_REG_3, BPF_REG_10),                                /* r3 = r10 (sp) */
+        BPF_ALU64_IMM(BPF_ADD, BPF_REG_3, -audit_value_start),               /* r3 = sp + (-value start offset) */
+        BPF_MOV64_IMM(BPF_REG_4, BPF_ANY),                                   /* r4 = BPF_ANY */
+        BPF_RAW_INSN(BPF_JMP | BPF_CALL, 0, 0, 0, BPF_FUNC_map_update_elem), /* r0 = bpf_map_update_elem(<audit map>,
+                                                                                &key, &value, BPF_ANY) */
+    };
+    struct bpf_insn deny[] = {
         BPF_MOV64_IMM(BPF_REG_0, 0), /* r0 = 0 */
         BPF_EXIT_INSN(),
     };
 
+    struct bpf_insn insns[SC_ARRAY_SIZE(prog) + SC_ARRAY_SIZE(audit) + SC_ARRAY_SIZE(deny)];
+    size_t insns_cnt = 0;
+    memcpy(insns, prog, sizeof(prog));
+    insns_cnt += SC_ARRAY_SIZE(prog);
+    if (audit_map_fd >= 0) {
+        memcpy(insns + insns_cnt, audit, sizeof(audit));
+        insns_cnt += SC_ARRAY_SIZE(audit);
+    }
+    memcpy(insns + insns_cnt, deny, sizeof(deny));
+    insns_cnt += SC_ARRAY_SIZE(deny);
+
     /* 32kB, should be more than enough to store verifier logs if program
        loading fails */
     char log_buf[32768] = {0};
 
-    int prog_fd = bpf_load_prog(BPF_PROG_TYPE_CGROUP_DEVICE, prog, SC_ARRAY_SIZE(prog), log_buf, sizeof(log_buf), name);
+    int prog_fd = bpf_load_prog(BPF_PROG_TYPE_CGROUP_DEVICE, insns, insns_cnt, log_buf, sizeof(log_buf), name);
     if (prog_fd < 0) {
         die("cannot load program, verifier output:\n%s\n", log_buf);
     }
@@ -322,8 +380,50 @@ static bool _sc_is_snap_cgroup(const char *group, const char *expected_group_nam
     return true;
 }
 
+/**
+ * _sc_cgroup_v2_open_audit_map returns the audit map of the device cgroup,
+ * creating and pinning it at the given path if needed.
+ */
+static int _sc_cgroup_v2_open_audit_map(sc_device_cgroup *self, const char *path) {
+    int bpf_snap_fd SC_CLEANUP(sc_cleanup_close) = -1;
+    bpf_snap_fd = open("/sys/fs/bpf/snap", O_PATH | O_DIRECTORY | O_NOFOLLOW | O_CLOEXEC);
+    if (bpf_snap_fd < 0) {
+        die("cannot open /sys/fs/bpf/snap");
+    }
+    if (sc_ensure_mkdirat(bpf_snap_fd, "audit", 0700, 0, 0) != 0) {
+        die("cannot create /sys/fs/bpf/snap/audit directory");
+    }
+
+    /* the counters are kept across invocations, snapd reads them and
+     * removes the map once auditing is disabled */
+    int auditmap_fd = bpf_get_by_path(path);
+    if (auditmap_fd >= 0) {
+        debug("found existing audit map");
+        return auditmap_fd;
+    }
+    if (errno != ENOENT) {
+        die("cannot get existing audit map");
+    }
+    /* least recently denied devices are dropped when the map is full */
+    const size_t max_entries = 1000;
+    auditmap_fd = bpf_create_map(BPF_MAP_TYPE_LRU_HASH, sizeof(struct sc_cgroup_v2_device_key),
+                                 sizeof(sc_cgroup_v2_device_audit_value), max_entries, self->v2.pretty_name);
+    if (auditmap_fd < 0) {
+        die("cannot create bpf audit map");
+    }
+    debug("got bpf audit map at fd: %d", auditmap_fd);
+    if (bpf_pin_to_path(auditmap_fd, path) < 0) {
+        die("cannot pin audit map to %s", path);
+    }
+    if (chown(path, 0, 0) != 0) {
+        die("cannot chown BPF audit map");
+    }
+    return auditmap_fd;
+}
+
 static int _sc_cgroup_v2_init_bpf(sc_device_cgroup *self, int flags) {
     self->v2.devmap_fd = -1;
+    self->v2.auditmap_fd = -1;
     self->v2.prog_fd = -1;
 
     /* fix the memlock limit if needed, this affects creating maps */
@@ -502,9 +602,15 @@ static int _sc_cgroup_v2_init_bpf(sc_device_cgroup *self, int flags) {
         }
     }
 
+    if (!from_existing && (flags & SC_DEVICE_CGROUP_AUDIT) != 0) {
+        char audit_path[PATH_MAX] = {0};
+        sc_must_snprintf(audit_path, sizeof audit_path, "%s/snap/audit/%s", bpf_base, self->v2.tag);
+        self->v2.auditmap_fd = _sc_cgroup_v2_open_audit_map(self, audit_path);
+    }
+
     if (!from_existing) {
         /* load and attach the BPF program */
-        int prog_fd = load_devcgroup_prog(devmap_fd, self->v2.pretty_name);
+        int prog_fd = load_devcgroup_prog(devmap_fd, self->v2.auditmap_fd, self->v2.pretty_name);
         /* keep track of the program */
         self->v2.prog_fd = prog_fd;
     }
@@ -522,6 +628,7 @@ static void _sc_cgroup_v2_close_bpf(sc_device_cgroup *self) {
     /* the map is pinned to a per-snap-application file and referenced by the
      * program */
     sc_cleanup_close(&self->v2.devmap_fd);
+    sc_cleanup_close(&self->v2.auditmap_fd);
     sc_cleanup_close(&self->v2.prog_fd);
 }
 
@@ -28,6 +28,9 @@ enum {
     /* when creating a device cgroup wrapped, do not set up a new cgroup but
      * rather use an existing one */
     SC_DEVICE_CGROUP_FROM_EXISTING = 1,
+    /* count the device accesses denied by the cgroup in an audit map pinned
+     * next to the device map, only supported with cgroup v2 */
+    SC_DEVICE_CGROUP_AUDIT = 2,
 };
 
 /**
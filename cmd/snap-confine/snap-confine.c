@@ -566,7 +566,9 @@ int main(int argc, char **argv) {
     setup_user_data();
 
     // https://wiki.ubuntu.com/SecurityTeam/Specifications/SnappyConfinement
-    sc_maybe_aa_change_onexec(&apparmor, invocation.security_tag);
+    // Connections limited to some users are only granted by the variant of
+    // the profile of the calling user.
+    sc_maybe_aa_change_onexec_for_user(&apparmor, invocation.security_tag, real_uid);
 #ifdef HAVE_SELINUX
     // For classic and confined snaps
     sc_selinux_set_snap_execcon();
@@ -721,6 +723,7 @@ static const size_t DEVICES_FILE_MAX_WAIT = 120;
 struct sc_device_cgroup_options {
     bool self_managed;
     bool non_strict;
+    bool audit;
 };
 
 static void sc_get_device_cgroup_setup(const sc_invocation *inv, struct sc_device_cgroup_options *devsetup) {
@@ -756,8 +759,16 @@ static void sc_get_device_cgroup_setup(const sc_invocation *inv, struct sc_devic
         sc_die_on_error(err);
     }
 
+    rewind(stream);
+
+    char *audit_value SC_CLEANUP(sc_cleanup_string) = NULL;
+    if (sc_infofile_get_key(stream, "audit", &audit_value, &err) < 0) {
+        sc_die_on_error(err);
+    }
+
     devsetup->self_managed = sc_streq(self_managed_value, "true");
     devsetup->non_strict = sc_streq(non_strict_value, "true");
+    devsetup->audit = sc_streq(audit_value, "true");
 }
 
 static sc_device_cgroup_mode device_cgroup_mode_for_snap(sc_invocation *inv) {
@@ -824,7 +835,7 @@ static void enter_non_classic_execution_environment(sc_invocation *inv, struct s
     } else {
         // Set up a device cgroup, unless the snap has been allowed to manage the
         // device cgroup by itself.
-        struct sc_device_cgroup_options cgdevopts = {false, false};
+        struct sc_device_cgroup_options cgdevopts = {false, false, false};
         sc_get_device_cgroup_setup(inv, &cgdevopts);
 
         if (cgdevopts.self_managed) {
@@ -833,7 +844,7 @@ static void enter_non_classic_execution_environment(sc_invocation *inv, struct s
             debug("device cgroup skipped, snap in non-strict confinement");
         } else {
             sc_device_cgroup_mode mode = device_cgroup_mode_for_snap(inv);
-            sc_setup_device_cgroup(inv->security_tag, mode);
+            sc_setup_device_cgroup(inv->security_tag, mode, cgdevopts.audit);
         }
     }
 
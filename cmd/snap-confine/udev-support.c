@@ -242,8 +242,10 @@ static void setup_current_tags_support(void) {
     /* coverity[leaked_storage] */
 }
 
-void sc_setup_device_cgroup(const char *security_tag, sc_device_cgroup_mode mode) {
-    debug("setting up device cgroup, mode \"%s\"", mode == SC_DEVICE_CGROUP_MODE_REQUIRED ? "required" : "optional");
+void sc_setup_device_cgroup(const char *security_tag, sc_device_cgroup_mode mode, bool audit) {
+    debug("setting up device cgroup, mode \"%s\"%s", mode == SC_DEVICE_CGROUP_MODE_REQUIRED ? "required" : "optional",
+          audit ? ", audited" : "");
+    const int cgroup_flags = audit ? SC_DEVICE_CGROUP_AUDIT : 0;
 
     setup_current_tags_support();
     if (__sc_udev_device_has_current_tag == NULL) {
@@ -300,7 +302,7 @@ void sc_setup_device_cgroup(const char *security_tag, sc_device_cgroup_mode mode
         /* Normally the cgroup setup is done lazily, but since device cgroup is
          * required, prepare for mediation of device access regardless of
          * devices being properly tagged. */
-        cgroup = sc_device_cgroup_new(security_tag, 0);
+        cgroup = sc_device_cgroup_new(security_tag, cgroup_flags);
         /* Setup the device group access control list */
         sc_udev_setup_acls_common(cgroup);
     }
@@ -339,7 +341,7 @@ void sc_setup_device_cgroup(const char *security_tag, sc_device_cgroup_mode mode
         if (cgroup == NULL) {
             /* Lazy initialization of cgroup wrapper only when we are sure that
              * there are devices assigned to this snap */
-            cgroup = sc_device_cgroup_new(security_tag, 0);
+            cgroup = sc_device_cgroup_new(security_tag, cgroup_flags);
             /* Setup the device group access control list */
             sc_udev_setup_acls_common(cgroup);
         }
@@ -22,6 +22,7 @@ package snap_exec
 import (
 	"syscall"
 
+	"github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/testutil"
 )
 
@@ -71,3 +72,11 @@ func MockSyscallStat(f func(string, *syscall.Stat_t) (err error)) func() {
 	syscallStat = f
 	return r
 }
+
+func MockLandlockRestrictSelf(f func(rules []landlock.Rule) error) (restore func()) {
+	return testutil.Mock(&landlockRestrictSelf, f)
+}
+
+func MockOsGetuid(uid int) (restore func()) {
+	return testutil.Mock(&osGetuid, func() int { return uid })
+}
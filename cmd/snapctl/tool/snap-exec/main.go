@@ -26,14 +26,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"syscall"
 
 	"github.com/jessevdk/go-flags"
 
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snapenv"
 
@@ -45,10 +48,12 @@ import (
 var syscallExec = syscall.Exec
 var syscallStat = syscall.Stat
 var osReadlink = os.Readlink
+var landlockRestrictSelf = landlock.RestrictSelf
+var osGetuid = os.Getuid
 
 // commandline args
 var opts struct {
-	Command string `long:"command" description:"use a different command like {stop,post-stop} from the app"`
+	Command string `long:"command" description:"use a different command like {stop,post-stop,liveness-probe} from the app"`
 	Hook    string `long:"hook" description:"hook to run" hidden:"yes"`
 }
 
@@ -127,6 +132,14 @@ func findCommand(app *snap.AppInfo, command string) (string, error) {
 		cmd = app.ReloadCommand
 	case "post-stop":
 		cmd = app.PostStopCommand
+	case "liveness-probe":
+		if app.LivenessProbe != nil {
+			cmd = app.LivenessProbe.Exec
+		}
+	case "readiness-probe":
+		if app.ReadinessProbe != nil {
+			cmd = app.ReadinessProbe.Exec
+		}
 	case "", "gdb", "gdbserver":
 		cmd = app.Command
 	default:
@@ -258,6 +271,10 @@ func execApp(snapTarget, revision, command string, args []string) error {
 
 	fullCmd = append(absoluteCommandChain(app.Snap.MountDir(), app.CommandChain), fullCmd...)
 
+	if err := restrictFilesystem(app.SecurityTag(), env); err != nil {
+		return err
+	}
+
 	logger.StartupStageTimestamp("snap-exec to app")
 	if err := syscallExec(fullCmd[0], fullCmd, env.ForExec()); err != nil {
 		return fmt.Errorf("cannot exec %q: %s", fullCmd[0], err)
@@ -266,6 +283,40 @@ func execApp(snapTarget, revision, command string, args []string) error {
 	return nil
 }
 
+// restrictFilesystem applies the landlock ruleset of the given security tag,
+// if there is one and the landlock feature is enabled, to the program about
+// to be executed. The variables of the ruleset are expanded from the
+// environment of the program, except $HOME which refers to the real home
+// directory of the user.
+func restrictFilesystem(securityTag string, env osutil.Environment) error {
+	// rulesets left behind while the feature was enabled are ignored
+	if !features.Landlock.IsEnabled() {
+		return nil
+	}
+	rules, err := landlock.ReadRuleset(filepath.Join(dirs.SnapLandlockDir, securityTag))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	// rules of connections limited to some users only apply to them
+	rules = landlock.RulesForUser(rules, osGetuid())
+	rules = landlock.ExpandRules(rules, func(name string) string {
+		if name == "HOME" && env["SNAP_REAL_HOME"] != "" {
+			return env["SNAP_REAL_HOME"]
+		}
+		return env[name]
+	})
+	// the restriction only applies to the current thread, which must be
+	// the one executing the program
+	runtime.LockOSThread()
+	if err := landlockRestrictSelf(rules); err != nil {
+		return fmt.Errorf("cannot restrict filesystem access: %v", err)
+	}
+	return nil
+}
+
 func getComponentInfo(name string, snapInfo *snap.Info) (*snap.ComponentInfo, error) {
 	return snap.ReadCurrentComponentInfo(name, snapInfo)
 }
@@ -321,6 +372,10 @@ func execHook(snapTarget string, revision, hookName string) error {
 
 	hookPath := filepath.Join(mountDir, "meta", "hooks", hookName)
 
+	if err := restrictFilesystem(hook.SecurityTag(), env); err != nil {
+		return err
+	}
+
 	// run the hook
 	cmd := append(absoluteCommandChain(mountDir, hook.CommandChain), hookPath)
 	return syscallExec(cmd[0], cmd, env.ForExec())
@@ -32,8 +32,10 @@ import (
 
 	"github.com/snapcore/snapd/cmd/snapctl/tool/snap-exec"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/testutil"
@@ -67,6 +69,8 @@ apps:
   command: run-app cmd-arg1 $SNAP_DATA
   stop-command: stop-app
   post-stop-command: post-stop-app
+  liveness-probe:
+   exec: check-app
   completer: you/complete/me
   environment:
    BASE_PATH: /some/path
@@ -143,6 +147,7 @@ func (s *snapExecSuite) TestFindCommand(c *C) {
 		{cmd: "", expected: `run-app cmd-arg1 $SNAP_DATA`},
 		{cmd: "stop", expected: "stop-app"},
 		{cmd: "post-stop", expected: "post-stop-app"},
+		{cmd: "liveness-probe", expected: "check-app"},
 	} {
 		cmd, err := snap_exec.FindCommand(info.Apps["app"], t.cmd)
 		c.Check(err, IsNil)
@@ -164,6 +169,9 @@ func (s *snapExecSuite) TestFindCommandNoCommand(c *C) {
 
 	_, err = snap_exec.FindCommand(info.Apps["nostop"], "stop")
 	c.Check(err, ErrorMatches, `no "stop" command found for "nostop"`)
+
+	_, err = snap_exec.FindCommand(info.Apps["nostop"], "liveness-probe")
+	c.Check(err, ErrorMatches, `no "liveness-probe" command found for "nostop"`)
 }
 
 func (s *snapExecSuite) TestSnapExecAppIntegration(c *C) {
@@ -313,6 +321,102 @@ func (s *snapExecSuite) TestSnapExecHookIntegration(c *C) {
 	c.Check(execArgs, DeepEquals, []string{execArgv0})
 }
 
+func mockLandlockFeature(c *C) {
+	c.Assert(os.MkdirAll(dirs.FeaturesDir, 0755), IsNil)
+	c.Assert(os.WriteFile(features.Landlock.ControlFile(), nil, 0644), IsNil)
+}
+
+func (s *snapExecSuite) TestSnapExecAppLandlockFeatureDisabled(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	snaptest.MockSnap(c, string(mockYaml), &snap.SideInfo{
+		Revision: snap.R("42"),
+	})
+	c.Assert(os.MkdirAll(dirs.SnapLandlockDir, 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapLandlockDir, "snap.snapname.app"),
+		[]byte("rx /usr\n"), 0644), IsNil)
+
+	restore := snap_exec.MockLandlockRestrictSelf(func(rules []landlock.Rule) error {
+		c.Fatalf("unexpected restriction")
+		return nil
+	})
+	defer restore()
+	execCalled := false
+	restore = snap_exec.MockSyscallExec(func(argv0 string, argv []string, env []string) error {
+		execCalled = true
+		return nil
+	})
+	defer restore()
+
+	err := snap_exec.ExecApp("snapname.app", "42", "", nil)
+	c.Assert(err, IsNil)
+	c.Check(execCalled, Equals, true)
+}
+
+func (s *snapExecSuite) TestSnapExecAppLandlockRuleset(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	snaptest.MockSnap(c, string(mockYaml), &snap.SideInfo{
+		Revision: snap.R("42"),
+	})
+	mockLandlockFeature(c)
+	c.Assert(os.MkdirAll(dirs.SnapLandlockDir, 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapLandlockDir, "snap.snapname.app"),
+		[]byte("# comment\nrx /usr\nrw $SNAP_DATA\nrw $HOME/.config/foo\nrw $UNSET\nuid=1001 rw /media\nuid=1000,1002 r /srv\n"), 0644), IsNil)
+	restore := snap_exec.MockOsGetuid(1000)
+	defer restore()
+
+	os.Setenv("SNAP_DATA", "/var/snap/snapname/42")
+	defer os.Unsetenv("SNAP_DATA")
+	os.Setenv("SNAP_REAL_HOME", "/home/user")
+	defer os.Unsetenv("SNAP_REAL_HOME")
+
+	var calls []string
+	restore = snap_exec.MockLandlockRestrictSelf(func(rules []landlock.Rule) error {
+		calls = append(calls, "restrict")
+		// the rule limited to another user is dropped
+		c.Check(rules, DeepEquals, []landlock.Rule{
+			{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/home/user/.config/foo"},
+			{Access: landlock.AccessRead, Path: "/srv"},
+			{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/usr"},
+			{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/var/snap/snapname/42"},
+		})
+		return nil
+	})
+	defer restore()
+	restore = snap_exec.MockSyscallExec(func(argv0 string, argv []string, env []string) error {
+		calls = append(calls, "exec")
+		return nil
+	})
+	defer restore()
+
+	err := snap_exec.ExecApp("snapname.app", "42", "", nil)
+	c.Assert(err, IsNil)
+	c.Check(calls, DeepEquals, []string{"restrict", "exec"})
+}
+
+func (s *snapExecSuite) TestSnapExecHookLandlockRulesetError(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	snaptest.MockSnap(c, string(mockHookYaml), &snap.SideInfo{
+		Revision: snap.R("42"),
+	})
+	mockLandlockFeature(c)
+	c.Assert(os.MkdirAll(dirs.SnapLandlockDir, 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapLandlockDir, "snap.snapname.hook.configure"),
+		[]byte("rx /usr\n"), 0644), IsNil)
+
+	restore := snap_exec.MockLandlockRestrictSelf(func(rules []landlock.Rule) error {
+		return fmt.Errorf("boom")
+	})
+	defer restore()
+	restore = snap_exec.MockSyscallExec(func(argv0 string, argv []string, env []string) error {
+		c.Fatalf("unexpected exec")
+		return nil
+	})
+	defer restore()
+
+	err := snap_exec.ExecHook("snapname", "42", "configure")
+	c.Assert(err, ErrorMatches, "cannot restrict filesystem access: boom")
+}
+
 func (s *snapExecSuite) TestSnapExecHookCommandChainIntegration(c *C) {
 	dirs.SetRootDir(c.MkDir())
 	snaptest.MockSnap(c, string(mockHookCommandChainYaml), &snap.SideInfo{
@@ -0,0 +1,111 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdAutoremove struct {
+	waitMixin
+
+	DryRun bool `long:"dry-run"`
+	Purge  bool `long:"purge"`
+}
+
+var shortAutoremoveHelp = i18n.G("Remove snaps that are no longer needed")
+var longAutoremoveHelp = i18n.G(`
+The autoremove command removes the snaps that were installed only because
+other snaps needed them, as their base or as the default provider of one of
+their content plugs, and that no installed snap needs anymore.
+
+Snaps that were explicitly installed or refreshed are never removed this way.
+`)
+
+func init() {
+	addCommand("autoremove", shortAutoremoveHelp, longAutoremoveHelp, func() flags.Commander {
+		return &cmdAutoremove{}
+	}, waitDescs.also(map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"dry-run": i18n.G("Only show the snaps that would be removed"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"purge": i18n.G("Remove the snaps without saving a snapshot of their data"),
+	}), nil)
+}
+
+func (x *cmdAutoremove) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	if x.DryRun {
+		return x.showCandidates()
+	}
+
+	changeID, err := x.client.Autoremove(&client.SnapOptions{Purge: x.Purge})
+	if err != nil {
+		return err
+	}
+
+	chg, err := x.wait(changeID)
+	if err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	var names []string
+	if err := chg.Get("snap-names", &names); err != nil {
+		if errors.Is(err, client.ErrNoData) {
+			fmt.Fprintln(Stdout, i18n.G("No snaps to remove."))
+			return nil
+		}
+		return err
+	}
+	changes, err := changedSnapsFromChange(chg)
+	if err != nil {
+		return err
+	}
+	showRemovedSnaps(nil, changes.names, nil, changes.snapshots)
+	return nil
+}
+
+func (x *cmdAutoremove) showCandidates() error {
+	snaps, err := x.client.List(nil, &client.ListOptions{Autoremove: true})
+	if err != nil {
+		if err == client.ErrNoSnapsInstalled {
+			fmt.Fprintln(Stdout, i18n.G("No snaps to remove."))
+			return nil
+		}
+		return err
+	}
+
+	for _, snap := range snaps {
+		fmt.Fprintf(Stdout, i18n.G("%s would be removed\n"), snap.Name)
+	}
+	return nil
+}
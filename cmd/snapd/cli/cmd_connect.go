@@ -20,13 +20,21 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/jessevdk/go-flags"
 
+	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/i18n"
 )
 
 type cmdConnect struct {
 	waitMixin
+	ExpireIn    string `long:"expire-in" value-name:"<duration>"`
+	ExpireAt    string `long:"expire-at" value-name:"<time>"`
+	User        bool   `long:"user"`
 	Positionals struct {
 		PlugSpec connectPlugSpec `required:"yes"`
 		SlotSpec connectSlotSpec
@@ -52,12 +60,25 @@ $ snap connect <snap>:<plug>
 
 Connects the provided plug to the slot in the core snap with a name matching
 the plug name.
+
+With --expire-in or --expire-at the connection is time-limited and is
+disconnected automatically once it expires.
+
+With --user the connection is established for the calling user only, other
+users of the system are not granted the access it provides.
 `)
 
 func init() {
 	addCommand("connect", shortConnectHelp, longConnectHelp, func() flags.Commander {
 		return &cmdConnect{}
-	}, waitDescs, []argDesc{
+	}, waitDescs.also(map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"expire-in": i18n.G("Disconnect automatically after the given duration, e.g. 2h30m"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"expire-at": i18n.G("Disconnect automatically at the given time, in RFC 3339 format"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"user": i18n.G("Connect for the calling user only"),
+	}), []argDesc{
 		// TRANSLATORS: This needs to begin with < and end with >
 		{name: i18n.G("<snap>:<plug>")},
 		// TRANSLATORS: This needs to begin with < and end with >
@@ -77,7 +98,12 @@ func (x *cmdConnect) Execute(args []string) error {
 		x.Positionals.PlugSpec.Snap = ""
 	}
 
-	id, err := x.client.Connect(x.Positionals.PlugSpec.Snap, x.Positionals.PlugSpec.Name, x.Positionals.SlotSpec.Snap, x.Positionals.SlotSpec.Name)
+	opts, err := x.connectOptions()
+	if err != nil {
+		return err
+	}
+
+	id, err := x.client.ConnectWithOptions(x.Positionals.PlugSpec.Snap, x.Positionals.PlugSpec.Name, x.Positionals.SlotSpec.Snap, x.Positionals.SlotSpec.Name, opts)
 	if err != nil {
 		return err
 	}
@@ -91,3 +117,30 @@ func (x *cmdConnect) Execute(args []string) error {
 
 	return nil
 }
+
+func (x *cmdConnect) connectOptions() (*client.ConnectOptions, error) {
+	opts := &client.ConnectOptions{ForSelf: x.User}
+	switch {
+	case x.ExpireIn != "" && x.ExpireAt != "":
+		return nil, errors.New(i18n.G("cannot use --expire-in and --expire-at together"))
+	case x.ExpireIn != "":
+		dur, err := time.ParseDuration(x.ExpireIn)
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("expire-in value must be a number of hours, minutes or seconds: %v"), err)
+		}
+		if dur <= 0 {
+			return nil, fmt.Errorf(i18n.G("expire-in value must be positive: %s"), x.ExpireIn)
+		}
+		opts.Duration = dur
+	case x.ExpireAt != "":
+		expiry, err := time.Parse(time.RFC3339, x.ExpireAt)
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("expire-at value must be a time in RFC 3339 format: %v"), err)
+		}
+		opts.Expiry = expiry
+	}
+	if !opts.ForSelf && opts.Duration == 0 && opts.Expiry.IsZero() {
+		return nil, nil
+	}
+	return opts, nil
+}
@@ -53,9 +53,20 @@ $ snap connect <snap>:<plug>
 Connects the provided plug to the slot in the core snap with a name matching
 the plug name.
 
+With --expire-in or --expire-at the connection is time-limited and is
+disconnected automatically once it expires.
+
+With --user the connection is established for the calling user only, other
+users of the system are not granted the access it provides.
+
 [connect command options]
-      --no-wait          Do not wait for the operation to finish but just print
-                         the change id.
+      --no-wait                   Do not wait for the operation to finish but
+                                  just print the change id.
+      --expire-in=<duration>      Disconnect automatically after the given
+                                  duration, e.g. 2h30m
+      --expire-at=<time>          Disconnect automatically at the given time,
+                                  in RFC 3339 format
+      --user                      Connect for the calling user only
 `
 	s.testSubCommandHelp(c, "connect", msg)
 }
@@ -94,6 +105,105 @@ func (s *SnapSuite) TestConnectExplicitEverything(c *C) {
 	c.Assert(rest, DeepEquals, []string{})
 }
 
+func (s *SnapSuite) TestConnectExpireIn(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/interfaces":
+			c.Check(r.Method, Equals, "POST")
+			c.Check(DecodedRequestBody(c, r), DeepEquals, map[string]any{
+				"action": "connect",
+				"plugs": []any{
+					map[string]any{
+						"snap": "producer",
+						"plug": "plug",
+					},
+				},
+				"slots": []any{
+					map[string]any{
+						"snap": "consumer",
+						"slot": "slot",
+					},
+				},
+				"duration": "2h30m0s",
+			})
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "status-code": 202, "change": "zzz"}`)
+		case "/v2/changes/zzz":
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintln(w, `{"type":"sync", "result":{"ready": true, "status": "Done"}}`)
+		default:
+			c.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	})
+	rest, err := Parser(Client()).ParseArgs([]string{"connect", "--expire-in=2h30m", "producer:plug", "consumer:slot"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+}
+
+func (s *SnapSuite) TestConnectExpireAt(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/interfaces":
+			c.Check(r.Method, Equals, "POST")
+			body := DecodedRequestBody(c, r)
+			c.Check(body["expiry"], Equals, "2026-10-20T12:00:00+02:00")
+			c.Check(body["duration"], IsNil)
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "status-code": 202, "change": "zzz"}`)
+		case "/v2/changes/zzz":
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintln(w, `{"type":"sync", "result":{"ready": true, "status": "Done"}}`)
+		default:
+			c.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	})
+	rest, err := Parser(Client()).ParseArgs([]string{"connect", "--expire-at=2026-10-20T12:00:00+02:00", "producer:plug", "consumer:slot"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+}
+
+func (s *SnapSuite) TestConnectUser(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/interfaces/own":
+			c.Check(r.Method, Equals, "POST")
+			body := DecodedRequestBody(c, r)
+			c.Check(body["action"], Equals, "connect")
+			c.Check(body["users"], IsNil)
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "status-code": 202, "change": "zzz"}`)
+		case "/v2/changes/zzz":
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintln(w, `{"type":"sync", "result":{"ready": true, "status": "Done"}}`)
+		default:
+			c.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	})
+	rest, err := Parser(Client()).ParseArgs([]string{"connect", "--user", "producer:plug", "consumer:slot"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+}
+
+func (s *SnapSuite) TestConnectExpiryErrors(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request to %q", r.URL.Path)
+	})
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--expire-in=1h", "--expire-at=2026-10-20T12:00:00Z"}, "cannot use --expire-in and --expire-at together"},
+		{[]string{"--expire-in=1d"}, `expire-in value must be a number of hours, minutes or seconds: .*`},
+		{[]string{"--expire-in=-1h"}, "expire-in value must be positive: -1h"},
+		{[]string{"--expire-at=tomorrow"}, `expire-at value must be a time in RFC 3339 format: .*`},
+	} {
+		args := append([]string{"connect"}, tc.args...)
+		args = append(args, "producer:plug", "consumer:slot")
+		_, err := Parser(Client()).ParseArgs(args)
+		c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.args))
+	}
+}
+
 func (s *SnapSuite) TestConnectExplicitPlugImplicitSlot(c *C) {
 	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
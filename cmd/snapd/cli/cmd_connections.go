@@ -23,16 +23,19 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/jessevdk/go-flags"
 
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/osutil/user"
 )
 
 type cmdConnections struct {
 	clientMixin
+	timeMixin
 	All         bool `long:"all"`
 	Positionals struct {
 		Snap installedSnapName
@@ -57,9 +60,9 @@ snap.
 func init() {
 	addCommand("connections", shortConnectionsHelp, longConnectionsHelp, func() flags.Commander {
 		return &cmdConnections{}
-	}, map[string]string{
+	}, timeDescs.also(map[string]string{
 		"all": i18n.G("Show connected and unconnected plugs and slots"),
-	}, []argDesc{{
+	}), []argDesc{{
 		// TRANSLATORS: This needs to be wrapped in <>s.
 		name: "<snap>",
 		// TRANSLATORS: This should not start with a lowercase letter.
@@ -85,6 +88,12 @@ type connection struct {
 	interfaceDeterminant string
 	manual               bool
 	gadget               bool
+	model                bool
+	// expiry is the formatted expiry time of time-limited connections
+	expiry string
+	// users are the names of the users of connections limited to some
+	// users
+	users []string
 }
 
 func (cn connection) String() string {
@@ -95,6 +104,15 @@ func (cn connection) String() string {
 	if cn.gadget {
 		opts = append(opts, "gadget")
 	}
+	if cn.model {
+		opts = append(opts, "model")
+	}
+	if cn.expiry != "" {
+		opts = append(opts, "expires "+cn.expiry)
+	}
+	if len(cn.users) > 0 {
+		opts = append(opts, "users "+strings.Join(cn.users, " "))
+	}
 	if len(opts) == 0 {
 		return "-"
 	}
@@ -138,6 +156,25 @@ func interfaceDeterminant(conn *client.Connection) string {
 	return fmt.Sprintf("[%v]", value)
 }
 
+var userLookupId = user.LookupId
+
+// userNames returns the names of the users with the given IDs, or the IDs
+// themselves for unknown users.
+func userNames(uids []int) []string {
+	if len(uids) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		name := strconv.Itoa(uid)
+		if u, err := userLookupId(name); err == nil {
+			name = u.Username
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
 func (x *cmdConnections) Execute(args []string) error {
 	if len(args) > 0 {
 		return ErrExtraArgs
@@ -171,11 +208,18 @@ func (x *cmdConnections) Execute(args []string) error {
 
 	annotatedConns := make([]connection, 0, len(connections.Established)+len(connections.Undesired))
 	for _, conn := range connections.Established {
+		var expiry string
+		if !conn.Expiry.IsZero() {
+			expiry = x.fmtTime(conn.Expiry)
+		}
 		annotatedConns = append(annotatedConns, connection{
 			plug:                 endpoint(conn.Plug.Snap, conn.Plug.Name),
 			slot:                 endpoint(conn.Slot.Snap, conn.Slot.Name),
 			manual:               conn.Manual,
 			gadget:               conn.Gadget,
+			model:                conn.Model,
+			expiry:               expiry,
+			users:                userNames(conn.Users),
 			interfaceName:        conn.Interface,
 			interfaceDeterminant: interfaceDeterminant(&conn),
 		})
@@ -25,11 +25,13 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/client"
 	. "github.com/snapcore/snapd/cmd/snapd/cli"
+	"github.com/snapcore/snapd/osutil/user"
 )
 
 func (s *SnapSuite) TestConnectionsNoneConnected(c *C) {
@@ -196,6 +198,7 @@ func (s *SnapSuite) TestConnectionsSomeConnected(c *C) {
 				Plug:      client.PlugRef{Snap: "keyboard-lights", Name: "scrollock"},
 				Slot:      client.SlotRef{Snap: "core", Name: "scrollock-led"},
 				Interface: "leds",
+				Model:     true,
 			},
 		},
 		Plugs: []client.Plug{
@@ -273,7 +276,98 @@ func (s *SnapSuite) TestConnectionsSomeConnected(c *C) {
 		"Interface  Plug                       Slot                        Notes\n" +
 		"leds       keyboard-lights:capslock   leds-provider:capslock-led  gadget\n" +
 		"leds       keyboard-lights:numlock    :numlock-led                manual\n" +
-		"leds       keyboard-lights:scrollock  :scrollock-led              -\n"
+		"leds       keyboard-lights:scrollock  :scrollock-led              model\n"
+	c.Assert(s.Stdout(), Equals, expectedStdout)
+	c.Assert(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestConnectionsTimeLimited(c *C) {
+	expiry, err := time.Parse(time.RFC3339, "2026-10-20T12:00:00Z")
+	c.Assert(err, IsNil)
+	result := client.Connections{
+		Established: []client.Connection{
+			{
+				Plug:      client.PlugRef{Snap: "support", Name: "log-observe"},
+				Slot:      client.SlotRef{Snap: "core", Name: "log-observe"},
+				Interface: "log-observe",
+				Manual:    true,
+				Expiry:    expiry,
+			},
+		},
+		Plugs: []client.Plug{
+			{
+				Snap:      "support",
+				Name:      "log-observe",
+				Interface: "log-observe",
+				Connections: []client.SlotRef{{
+					Snap: "core",
+					Name: "log-observe",
+				}},
+			},
+		},
+	}
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/connections")
+		EncodeResponseBody(c, w, map[string]any{
+			"type":   "sync",
+			"result": result,
+		})
+	})
+	rest, err := Parser(Client()).ParseArgs([]string{"connections", "--abs-time"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	expectedStdout := "" +
+		"Interface    Plug                 Slot          Notes\n" +
+		"log-observe  support:log-observe  :log-observe  manual,expires 2026-10-20T12:00:00Z\n"
+	c.Assert(s.Stdout(), Equals, expectedStdout)
+	c.Assert(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestConnectionsForUsers(c *C) {
+	restore := MockUserLookupId(func(uid string) (*user.User, error) {
+		if uid == "1000" {
+			return &user.User{Uid: uid, Username: "alice"}, nil
+		}
+		return nil, fmt.Errorf("unknown user")
+	})
+	defer restore()
+	result := client.Connections{
+		Established: []client.Connection{
+			{
+				Plug:      client.PlugRef{Snap: "support", Name: "log-observe"},
+				Slot:      client.SlotRef{Snap: "core", Name: "log-observe"},
+				Interface: "log-observe",
+				Manual:    true,
+				Users:     []int{1000, 1001},
+			},
+		},
+		Plugs: []client.Plug{
+			{
+				Snap:      "support",
+				Name:      "log-observe",
+				Interface: "log-observe",
+				Connections: []client.SlotRef{{
+					Snap: "core",
+					Name: "log-observe",
+				}},
+			},
+		},
+	}
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/connections")
+		EncodeResponseBody(c, w, map[string]any{
+			"type":   "sync",
+			"result": result,
+		})
+	})
+	rest, err := Parser(Client()).ParseArgs([]string{"connections"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	expectedStdout := "" +
+		"Interface    Plug                 Slot          Notes\n" +
+		"log-observe  support:log-observe  :log-observe  manual,users alice 1001\n"
 	c.Assert(s.Stdout(), Equals, expectedStdout)
 	c.Assert(s.Stderr(), Equals, "")
 }
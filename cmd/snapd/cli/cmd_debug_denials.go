@@ -0,0 +1,168 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/interfaces/denials"
+)
+
+var shortDebugDenialsHelp = i18n.G("Show recent security denials of a snap")
+var longDebugDenialsHelp = i18n.G(`
+The denials command shows the AppArmor and seccomp denials recently logged
+for the given snap, together with the interfaces that would grant the denied
+access. Device accesses denied by the device cgroup are included when
+enabled with 'snap debug device-audit'.
+
+At most 10000 journal entries are searched. The command requires root.
+`)
+
+type cmdDebugDenials struct {
+	clientMixin
+	timeMixin
+	Lines      int `long:"lines"`
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addDebugCommand("denials",
+		shortDebugDenialsHelp,
+		longDebugDenialsHelp,
+		func() flags.Commander { return &cmdDebugDenials{} },
+		timeDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"lines": i18n.G("Number of journal entries to search for denials"),
+		}),
+		[]argDesc{
+			// TRANSLATORS: This needs to begin with < and end with >
+			{name: i18n.G("<snap>"),
+				// TRANSLATORS: This should not start with a lowercase letter.
+				desc: i18n.G("Snap name")},
+		},
+	)
+}
+
+type debugDenial struct {
+	denials.Denial
+	Candidates []struct {
+		Interface string `json:"interface"`
+		Plug      string `json:"plug,omitempty"`
+		Connected bool   `json:"connected,omitempty"`
+	} `json:"candidates,omitempty"`
+}
+
+// describeDenial returns a short human readable description of the denied
+// access.
+func describeDenial(d *denials.Denial) string {
+	if d.Kind == denials.KindSeccomp {
+		syscall := d.Syscall
+		if syscall == "" {
+			syscall = strconv.Itoa(d.SyscallNumber)
+		}
+		return fmt.Sprintf("seccomp: syscall %s", syscall)
+	}
+	if d.Kind == denials.KindDevice && d.Device != nil {
+		desc := fmt.Sprintf("device: %s %d:%d", d.Device.Type, d.Device.Major, d.Device.Minor)
+		if d.Path != "" {
+			desc += " " + d.Path
+		}
+		return fmt.Sprintf("%s (%d times)", desc, d.Device.Count)
+	}
+	var what []string
+	switch d.Class {
+	case "cap":
+		what = []string{"capability", d.Capability}
+	case "net":
+		what = []string{"network", d.Family, d.SockType}
+	case "dbus":
+		what = []string{"dbus", d.Bus, d.Path, d.Interface, d.Member}
+	default:
+		what = []string{d.Operation, d.Path}
+		if d.Owner {
+			what = append(what, "(owner)")
+		}
+		if d.Permissions != "" {
+			what = append(what, d.Permissions)
+		}
+	}
+	parts := []string{"apparmor:"}
+	for _, w := range what {
+		if w != "" {
+			parts = append(parts, w)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (x *cmdDebugDenials) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	if x.Lines < 0 {
+		return errors.New(i18n.G("cannot use a negative number of lines"))
+	}
+
+	snapName := string(x.Positional.Snap)
+	var result []debugDenial
+	if err := x.client.DebugDenials(snapName, x.Lines, &result); err != nil {
+		return err
+	}
+	if len(result) == 0 {
+		fmt.Fprintf(Stderr, i18n.G("No denials found for snap %q.\n"), snapName)
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Time\tLabel\tDenial\tCandidates"))
+	for _, d := range result {
+		candidates := make([]string, 0, len(d.Candidates))
+		for _, cand := range d.Candidates {
+			switch {
+			case cand.Plug == "":
+				candidates = append(candidates, fmt.Sprintf(i18n.G("%s (no plug)"), cand.Interface))
+			case cand.Connected:
+				candidates = append(candidates, fmt.Sprintf(i18n.G("%s (plug %s, connected)"), cand.Interface, cand.Plug))
+			default:
+				candidates = append(candidates, fmt.Sprintf(i18n.G("%s (plug %s)"), cand.Interface, cand.Plug))
+			}
+		}
+		when := "-"
+		if !d.Time.IsZero() {
+			when = x.fmtTime(d.Time)
+		}
+		cands := "-"
+		if len(candidates) > 0 {
+			cands = strings.Join(candidates, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", when, d.Label, describeDenial(&d.Denial), cands)
+	}
+	return nil
+}
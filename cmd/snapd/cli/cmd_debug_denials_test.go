@@ -0,0 +1,80 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snapd/cli"
+)
+
+func (s *SnapSuite) TestDebugDenials(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/debug/denials")
+			c.Check(r.URL.Query().Get("snap"), check.Equals, "foo")
+			c.Check(r.URL.Query().Get("lines"), check.Equals, "500")
+			fmt.Fprintln(w, `{"type": "sync", "result": [
+{"kind": "apparmor", "time": "2026-10-19T10:00:00Z", "label": "snap.foo.app", "snap": "foo", "operation": "open", "class": "file", "path": "/proc/net/dev", "permissions": "r", "candidates": [{"interface": "network-observe", "plug": "network-observe"}, {"interface": "system-observe"}]},
+{"kind": "apparmor", "label": "snap.foo.app", "snap": "foo", "operation": "capable", "class": "cap", "capability": "net_admin", "candidates": [{"interface": "network-control", "plug": "network-control", "connected": true}]},
+{"kind": "seccomp", "time": "2026-10-19T10:01:00Z", "label": "snap.foo.app", "snap": "foo", "syscall-number": 321},
+{"kind": "device", "label": "snap.foo.app", "snap": "foo", "path": "/dev/video0", "device": {"type": "c", "major": 81, "minor": 0, "subsystem": "video4linux", "kernel": "video0", "count": 3}, "candidates": [{"interface": "camera", "plug": "camera"}]}
+]}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials", "--abs-time", "--lines=500", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `
+Time                  Label         Denial                                Candidates
+2026-10-19T10:00:00Z  snap.foo.app  apparmor: open /proc/net/dev r        network-observe (plug network-observe), system-observe (no plug)
+-                     snap.foo.app  apparmor: capability net_admin        network-control (plug network-control, connected)
+2026-10-19T10:01:00Z  snap.foo.app  seccomp: syscall 321                  -
+-                     snap.foo.app  device: c 81:0 /dev/video0 (3 times)  camera (plug camera)
+`[1:])
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(n, check.Equals, 1)
+}
+
+func (s *SnapSuite) TestDebugDenialsNone(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "No denials found for snap \"foo\".\n")
+}
+
+func (s *SnapSuite) TestDebugDenialsNegativeLines(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials", "--lines=-1", "foo"})
+	c.Assert(err, check.ErrorMatches, "cannot use a negative number of lines")
+}
@@ -0,0 +1,96 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+var shortDebugDeviceAuditHelp = i18n.G("Audit device accesses denied to a snap")
+var longDebugDeviceAuditHelp = i18n.G(`
+The device-audit command enables or disables counting of the device accesses
+denied by the device cgroup of the given snap. Once enabled, the denied
+accesses are reported by 'snap debug denials'.
+
+Auditing requires cgroup v2 and takes effect the next time the applications
+of the snap are started.
+`)
+
+type cmdDebugDeviceAudit struct {
+	waitMixin
+	Enable     bool `long:"enable"`
+	Disable    bool `long:"disable"`
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addDebugCommand("device-audit",
+		shortDebugDeviceAuditHelp,
+		longDebugDeviceAuditHelp,
+		func() flags.Commander { return &cmdDebugDeviceAudit{} },
+		waitDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"enable": i18n.G("Enable auditing of denied device accesses"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"disable": i18n.G("Disable auditing of denied device accesses"),
+		}),
+		[]argDesc{
+			// TRANSLATORS: This needs to begin with < and end with >
+			{name: i18n.G("<snap>"),
+				// TRANSLATORS: This should not start with a lowercase letter.
+				desc: i18n.G("Snap name")},
+		},
+	)
+}
+
+func (x *cmdDebugDeviceAudit) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	if x.Enable == x.Disable {
+		return errors.New(i18n.G("exactly one of --enable or --disable is required"))
+	}
+
+	snapName := string(x.Positional.Snap)
+	chgID, err := x.client.SetDeviceAudit(snapName, x.Enable)
+	if err != nil {
+		return err
+	}
+	if _, err := x.wait(chgID); err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	if x.Enable {
+		fmt.Fprintf(Stdout, i18n.G("Auditing of device accesses of snap %q enabled\n"), snapName)
+	} else {
+		fmt.Fprintf(Stdout, i18n.G("Auditing of device accesses of snap %q disabled\n"), snapName)
+	}
+	return nil
+}
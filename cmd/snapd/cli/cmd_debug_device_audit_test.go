@@ -0,0 +1,80 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snapd/cli"
+)
+
+func (s *SnapSuite) TestDebugDeviceAudit(c *check.C) {
+	for _, enable := range []bool{true, false} {
+		s.ResetStdStreams()
+		n := 0
+		s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+			switch n {
+			case 0:
+				c.Check(r.Method, check.Equals, "POST")
+				c.Check(r.URL.Path, check.Equals, "/v2/debug")
+				c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]any{
+					"action": "device-audit",
+					"snaps":  []any{"foo"},
+					"params": map[string]any{"enable": enable},
+				})
+				w.WriteHeader(202)
+				fmt.Fprintln(w, `{"type": "async", "status-code": 202, "result": {}, "change": "12"}`)
+			case 1:
+				c.Check(r.Method, check.Equals, "GET")
+				c.Check(r.URL.Path, check.Equals, "/v2/changes/12")
+				fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done"}}`)
+			default:
+				c.Fatalf("expected to get 2 requests, now on %d", n+1)
+			}
+			n++
+		})
+
+		flag := "--enable"
+		expected := "Auditing of device accesses of snap \"foo\" enabled\n"
+		if !enable {
+			flag = "--disable"
+			expected = "Auditing of device accesses of snap \"foo\" disabled\n"
+		}
+		rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "device-audit", flag, "foo"})
+		c.Assert(err, check.IsNil)
+		c.Assert(rest, check.DeepEquals, []string{})
+		c.Check(s.Stdout(), check.Equals, expected)
+		c.Check(s.Stderr(), check.Equals, "")
+		c.Check(n, check.Equals, 2)
+	}
+}
+
+func (s *SnapSuite) TestDebugDeviceAuditFlags(c *check.C) {
+	for _, args := range [][]string{
+		{"debug", "device-audit", "foo"},
+		{"debug", "device-audit", "--enable", "--disable", "foo"},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(args)
+		c.Check(err, check.ErrorMatches, "exactly one of --enable or --disable is required")
+	}
+}
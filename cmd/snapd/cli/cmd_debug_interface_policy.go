@@ -0,0 +1,322 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/policy"
+	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snapfile"
+)
+
+var shortDebugInterfacePolicyHelp = i18n.G("Evaluate the interface policy for a snap offline")
+var longDebugInterfacePolicyHelp = i18n.G(`
+The interface-policy command evaluates the interface policy of the builtin
+base-declaration, and optionally of a snap-declaration and a model, for the
+given snap.yaml or .snap file, without contacting snapd or the store.
+
+For every plug and slot it reports whether installation is allowed, for every
+plug whether it is auto-connected and to which slot, and which rule of which
+declaration produced the decision. Auto-connection is only evaluated against
+the implicit slots of the system and the slots of the snap itself.
+
+The assertions are read from files and are not verified.
+`)
+
+type cmdDebugInterfacePolicy struct {
+	SnapDeclaration flags.Filename `long:"snap-declaration" value-name:"<file>"`
+	Model           flags.Filename `long:"model" value-name:"<file>"`
+	Positional      struct {
+		Snap flags.Filename `positional-arg-name:"<snap.yaml|snap-file>" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addDebugCommand("interface-policy",
+		shortDebugInterfacePolicyHelp,
+		longDebugInterfacePolicyHelp,
+		func() flags.Commander { return &cmdDebugInterfacePolicy{} },
+		map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"snap-declaration": i18n.G("File with the snap-declaration of the snap"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"model": i18n.G("File with the model of the device"),
+		},
+		[]argDesc{
+			// TRANSLATORS: This needs to begin with < and end with >
+			{name: i18n.G("<snap.yaml|snap-file>"),
+				// TRANSLATORS: This should not start with a lowercase letter.
+				desc: i18n.G("The snap.yaml or .snap file of the snap")},
+		},
+	)
+}
+
+func readSnapInfoForPolicy(path string) (*snap.Info, error) {
+	if strings.HasSuffix(path, ".snap") {
+		snapf, err := snapfile.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		return snap.ReadInfoFromSnapFile(snapf, nil)
+	}
+	yaml, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := snap.InfoFromSnapYaml(yaml)
+	if err != nil {
+		return nil, err
+	}
+	if err := snap.Validate(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// readAssertionForPolicy reads the first assertion of the given type from
+// the file.
+func readAssertionForPolicy(path string, assertType *asserts.AssertionType) (asserts.Assertion, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := asserts.NewDecoder(f)
+	for {
+		a, err := dec.Decode()
+		if err == io.EOF {
+			return nil, fmt.Errorf(i18n.G("cannot find a %s assertion in %q"), assertType.Name, path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("cannot read assertion from %q: %v"), path, err)
+		}
+		if a.Type() == assertType {
+			return a, nil
+		}
+	}
+}
+
+// systemSnapForPolicy returns a system snap carrying the implicit slots of
+// the builtin interfaces.
+func systemSnapForPolicy() (*snap.Info, *interfaces.SnapAppSet, error) {
+	info := &snap.Info{
+		SuggestedName: "snapd",
+		SnapType:      snap.TypeSnapd,
+		Slots:         make(map[string]*snap.SlotInfo),
+	}
+	for _, iface := range builtin.Interfaces() {
+		si := interfaces.StaticInfoOf(iface)
+		if (release.OnClassic && si.ImplicitOnClassic) || (!release.OnClassic && si.ImplicitOnCore) {
+			name := iface.Name()
+			info.Slots[name] = &snap.SlotInfo{
+				Snap:      info,
+				Name:      name,
+				Interface: name,
+			}
+		}
+	}
+	appSet, err := interfaces.NewSnapAppSet(info, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, appSet, nil
+}
+
+// slotsForInterface returns the slots of the snap of the given interface,
+// sorted by name.
+func slotsForInterface(info *snap.Info, iface string) []*snap.SlotInfo {
+	var slots []*snap.SlotInfo
+	for _, slot := range info.Slots {
+		if slot.Interface == iface {
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Name < slots[j].Name })
+	return slots
+}
+
+func (x *cmdDebugInterfacePolicy) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	// plug/slot sanitization is disabled (no-op) by default at the package
+	// level for "snap" command, we want however the same attributes the
+	// policy checks see in snapd.
+	snap.SanitizePlugsSlots = builtin.SanitizePlugsSlots
+
+	info, err := readSnapInfoForPolicy(string(x.Positional.Snap))
+	if err != nil {
+		return err
+	}
+
+	var snapDecl *asserts.SnapDeclaration
+	if x.SnapDeclaration != "" {
+		a, err := readAssertionForPolicy(string(x.SnapDeclaration), asserts.SnapDeclarationType)
+		if err != nil {
+			return err
+		}
+		snapDecl = a.(*asserts.SnapDeclaration)
+		if snapDecl.SnapName() != info.SnapName() {
+			return fmt.Errorf(i18n.G("cannot use snap-declaration of snap %q for snap %q"), snapDecl.SnapName(), info.SnapName())
+		}
+		info.SnapID = snapDecl.SnapID()
+	}
+	var model *asserts.Model
+	if x.Model != "" {
+		a, err := readAssertionForPolicy(string(x.Model), asserts.ModelType)
+		if err != nil {
+			return err
+		}
+		model = a.(*asserts.Model)
+		// the on-classic constraints are evaluated against the model
+		// instead of the host
+		release.OnClassic = model.Classic()
+	}
+
+	baseDecl := asserts.BuiltinBaseDeclaration()
+	if baseDecl == nil {
+		return errors.New(i18n.G("internal error: cannot find the builtin base-declaration"))
+	}
+
+	appSet, err := interfaces.NewSnapAppSet(info, nil)
+	if err != nil {
+		return err
+	}
+	systemInfo, systemAppSet, err := systemSnapForPolicy()
+	if err != nil {
+		return err
+	}
+
+	installCand := policy.InstallCandidate{
+		Snap:            info,
+		SnapDeclaration: snapDecl,
+		BaseDeclaration: baseDecl,
+		Model:           model,
+	}
+
+	plugNames := make([]string, 0, len(info.Plugs))
+	for name := range info.Plugs {
+		plugNames = append(plugNames, name)
+	}
+	sort.Strings(plugNames)
+	for _, name := range plugNames {
+		plug := info.Plugs[name]
+		origin, err := installCand.CheckPlug(plug)
+		fmt.Fprintf(Stdout, "plug %s:\n", name)
+		fmt.Fprintf(Stdout, "  interface: %s\n", plug.Interface)
+		fmt.Fprintf(Stdout, "  install: %s\n", policyDecision(err))
+		fmt.Fprintf(Stdout, "  install-rule: %s\n", origin)
+
+		// candidate slots are the ones of the snap itself and the
+		// implicit ones of the system
+		var candidates []*interfaces.ConnectedSlot
+		for _, slot := range slotsForInterface(info, plug.Interface) {
+			candidates = append(candidates, interfaces.NewConnectedSlot(slot, appSet, nil, nil))
+		}
+		for _, slot := range slotsForInterface(systemInfo, plug.Interface) {
+			candidates = append(candidates, interfaces.NewConnectedSlot(slot, systemAppSet, nil, nil))
+		}
+		if len(candidates) == 0 {
+			fmt.Fprintf(Stdout, "  auto-connect: %s\n", i18n.G("no (no candidate slot)"))
+			continue
+		}
+		connectedPlug := interfaces.NewConnectedPlug(plug, appSet, nil, nil)
+		var allowed []string
+		var firstOrigin policy.RuleOrigin
+		var firstErr error
+		anySlotsPerPlug := true
+		for i, slot := range candidates {
+			var slotDecl *asserts.SnapDeclaration
+			if slot.Snap() == info {
+				slotDecl = snapDecl
+			}
+			connCand := policy.ConnectCandidate{
+				Plug:                connectedPlug,
+				PlugSnapDeclaration: snapDecl,
+				Slot:                slot,
+				SlotSnapDeclaration: slotDecl,
+				BaseDeclaration:     baseDecl,
+				Model:               model,
+				CompatEnabled:       plug.Interface != "content",
+			}
+			arity, err := connCand.CheckAutoConnect()
+			if i == 0 {
+				firstOrigin = connCand.RuleOrigin()
+				firstErr = err
+			}
+			if err != nil {
+				continue
+			}
+			if len(allowed) == 0 {
+				firstOrigin = connCand.RuleOrigin()
+			}
+			if !arity.SlotsPerPlugAny() {
+				anySlotsPerPlug = false
+			}
+			allowed = append(allowed, fmt.Sprintf("%s:%s", slot.Snap().InstanceName(), slot.Name()))
+		}
+		switch {
+		case len(allowed) == 0:
+			fmt.Fprintf(Stdout, "  auto-connect: %s\n", policyDecision(firstErr))
+		case len(allowed) > 1 && !anySlotsPerPlug:
+			fmt.Fprintf(Stdout, "  auto-connect: "+i18n.G("no (multiple candidate slots: %s)")+"\n", strings.Join(allowed, ", "))
+		default:
+			fmt.Fprintf(Stdout, "  auto-connect: %s\n", strings.Join(allowed, ", "))
+		}
+		fmt.Fprintf(Stdout, "  auto-connect-rule: %s\n", firstOrigin)
+	}
+
+	slotNames := make([]string, 0, len(info.Slots))
+	for name := range info.Slots {
+		slotNames = append(slotNames, name)
+	}
+	sort.Strings(slotNames)
+	for _, name := range slotNames {
+		slot := info.Slots[name]
+		origin, err := installCand.CheckSlot(slot)
+		fmt.Fprintf(Stdout, "slot %s:\n", name)
+		fmt.Fprintf(Stdout, "  interface: %s\n", slot.Interface)
+		fmt.Fprintf(Stdout, "  install: %s\n", policyDecision(err))
+		fmt.Fprintf(Stdout, "  install-rule: %s\n", origin)
+	}
+	return nil
+}
+
+func policyDecision(err error) string {
+	if err != nil {
+		return fmt.Sprintf(i18n.G("no (%v)"), err)
+	}
+	return i18n.G("allowed")
+}
@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snapd/cli"
+	"github.com/snapcore/snapd/release"
+	snapinfo "github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+const interfacePolicySnapYaml = `name: foo
+version: 1
+plugs:
+  network:
+  camera:
+  snapd-control:
+slots:
+  docker:
+`
+
+const interfacePolicySnapDecl = `type: snap-declaration
+authority-id: canonical
+series: 16
+snap-name: %s
+snap-id: foosnapidfoosnapidfoosnapid0001
+publisher-id: publisher
+plugs:
+  snapd-control:
+    allow-installation: true
+    allow-auto-connection: true
+timestamp: 2016-09-30T12:00:00Z
+sign-key-sha3-384: Jv8_JiHiIzJVcO9M55pPdqSDWUvuhfDIBJUS-3VW7F_idjix7Ffn5qMxB21ZQuij
+
+AXNpZw==
+`
+
+func (s *SnapSuite) mockInterfacePolicyFiles(c *C, declSnapName string) (snapYaml, snapDecl string) {
+	restore := release.MockOnClassic(true)
+	s.AddCleanup(restore)
+	// the command enables plug/slot sanitization
+	s.AddCleanup(snapinfo.MockSanitizePlugsSlots(func(*snapinfo.Info) {}))
+
+	dir := c.MkDir()
+	snapYaml = filepath.Join(dir, "snap.yaml")
+	c.Assert(os.WriteFile(snapYaml, []byte(interfacePolicySnapYaml), 0644), IsNil)
+	snapDecl = filepath.Join(dir, "foo.snap-declaration")
+	c.Assert(os.WriteFile(snapDecl, []byte(fmt.Sprintf(interfacePolicySnapDecl, declSnapName)), 0644), IsNil)
+	return snapYaml, snapDecl
+}
+
+func (s *SnapSuite) TestDebugInterfacePolicy(c *C) {
+	snapYaml, _ := s.mockInterfacePolicyFiles(c, "foo")
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "interface-policy", snapYaml})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `plug camera:
+  interface: camera
+  install: allowed
+  install-rule: no plug rule for interface "camera"
+  auto-connect: no (auto-connection denied by slot rule of interface "camera")
+  auto-connect-rule: slot rule of interface "camera" in the base-declaration
+plug network:
+  interface: network
+  install: allowed
+  install-rule: no plug rule for interface "network"
+  auto-connect: snapd:network
+  auto-connect-rule: slot rule of interface "network" in the base-declaration
+plug snapd-control:
+  interface: snapd-control
+  install: no (installation not allowed by "snapd-control" plug rule of interface "snapd-control")
+  install-rule: plug rule of interface "snapd-control" in the base-declaration
+  auto-connect: no (auto-connection denied by plug rule of interface "snapd-control")
+  auto-connect-rule: plug rule of interface "snapd-control" in the base-declaration
+slot docker:
+  interface: docker
+  install: no (installation denied by "docker" slot rule of interface "docker")
+  install-rule: slot rule of interface "docker" in the base-declaration
+`)
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugInterfacePolicySnapDeclaration(c *C) {
+	snapYaml, snapDecl := s.mockInterfacePolicyFiles(c, "foo")
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "interface-policy", "--snap-declaration", snapDecl, snapYaml})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), testutil.Contains, `plug snapd-control:
+  interface: snapd-control
+  install: allowed
+  install-rule: plug rule of interface "snapd-control" in the snap-declaration of "foo"
+  auto-connect: snapd:snapd-control
+  auto-connect-rule: plug rule of interface "snapd-control" in the snap-declaration of "foo"
+`)
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugInterfacePolicyErrors(c *C) {
+	snapYaml, snapDecl := s.mockInterfacePolicyFiles(c, "bar")
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "interface-policy", "--snap-declaration", snapDecl, snapYaml})
+	c.Check(err, ErrorMatches, `cannot use snap-declaration of snap "bar" for snap "foo"`)
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"debug", "interface-policy", "--model", snapDecl, snapYaml})
+	c.Check(err, ErrorMatches, `cannot find a model assertion in ".*/foo.snap-declaration"`)
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"debug", "interface-policy", "--snap-declaration", snapYaml, snapYaml})
+	c.Check(err, ErrorMatches, `cannot read assertion from ".*/snap.yaml": .*`)
+}
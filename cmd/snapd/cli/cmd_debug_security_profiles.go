@@ -0,0 +1,131 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/interfaces/inspect"
+)
+
+var shortDebugSecurityProfilesHelp = i18n.G("Show the effective security profiles of a snap")
+var longDebugSecurityProfilesHelp = i18n.G(`
+The security-profiles command shows the effective security profiles of the
+given snap for every security backend, with every rule annotated by where it
+comes from: the base template, an interface plug or slot, a connection, the
+snap layout or a parallel instance.
+
+When --plug and --slot are given, only the rules that connecting them would
+add to the profiles are shown.
+`)
+
+type cmdDebugSecurityProfiles struct {
+	clientMixin
+	Backend    string `long:"backend"`
+	Plug       string `long:"plug" value-name:"<snap>:<plug>"`
+	Slot       string `long:"slot" value-name:"<snap>:<slot>"`
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addDebugCommand("security-profiles",
+		shortDebugSecurityProfilesHelp,
+		longDebugSecurityProfilesHelp,
+		func() flags.Commander { return &cmdDebugSecurityProfiles{} },
+		map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"backend": i18n.G("Only show the profiles of the given security backend"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"plug": i18n.G("Plug of a prospective connection"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"slot": i18n.G("Slot of a prospective connection"),
+		},
+		[]argDesc{
+			// TRANSLATORS: This needs to begin with < and end with >
+			{name: i18n.G("<snap>"),
+				// TRANSLATORS: This should not start with a lowercase letter.
+				desc: i18n.G("Snap name")},
+		},
+	)
+}
+
+func (x *cmdDebugSecurityProfiles) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	if (x.Plug == "") != (x.Slot == "") {
+		return errors.New(i18n.G("--plug and --slot must be used together"))
+	}
+
+	params := map[string]string{"snap": string(x.Positional.Snap)}
+	if x.Plug != "" {
+		params["plug"] = x.Plug
+		params["slot"] = x.Slot
+	}
+	var profiles []inspect.Profile
+	if err := x.client.DebugGet("security-profiles", &profiles, params); err != nil {
+		return err
+	}
+
+	// in diff mode all the lines are additions
+	prefix := ""
+	if x.Plug != "" {
+		prefix = "+"
+	}
+	shown := 0
+	for _, profile := range profiles {
+		if x.Backend != "" && string(profile.Backend) != x.Backend {
+			continue
+		}
+		if shown > 0 {
+			fmt.Fprintln(Stdout)
+		}
+		shown++
+		// all the backends use # for comments, the output is therefore
+		// still a valid profile
+		fmt.Fprintf(Stdout, "# %s: %s\n", profile.Backend, profile.Name)
+		for _, line := range profile.Lines {
+			if len(line.Origins) == 0 {
+				fmt.Fprintf(Stdout, "%s%s\n", prefix, line.Text)
+				continue
+			}
+			origins := make([]string, len(line.Origins))
+			for i, origin := range line.Origins {
+				origins[i] = origin.String()
+			}
+			fmt.Fprintf(Stdout, "%s%s  # %s\n", prefix, line.Text, strings.Join(origins, "; "))
+		}
+	}
+	if shown == 0 {
+		if x.Plug != "" {
+			fmt.Fprintf(Stderr, i18n.G("Connecting %s to %s would not change the profiles of snap %q.\n"), x.Plug, x.Slot, x.Positional.Snap)
+		} else {
+			fmt.Fprintf(Stderr, i18n.G("No security profiles found for snap %q.\n"), x.Positional.Snap)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snapd/cli"
+)
+
+const securityProfilesJSON = `{"type": "sync", "result": [
+{"backend": "apparmor", "name": "snap.foo.app", "lines": [
+  {"text": "#include <tunables/global>", "origins": [{"kind": "base-template"}]},
+  {"text": ""},
+  {"text": "@{PROC}/net/dev r,", "origins": [{"kind": "connected-plug", "interface": "network-observe", "plug": {"snap": "foo", "plug": "network-observe"}, "slot": {"snap": "core", "slot": "network-observe"}}]}
+]},
+{"backend": "seccomp", "name": "snap.foo.app", "lines": [
+  {"text": "socket AF_NETLINK - NETLINK_ROUTE", "origins": [{"kind": "plug", "interface": "network-observe", "plug": {"snap": "foo", "plug": "network-observe"}}]}
+]}
+]}`
+
+func (s *SnapSuite) TestDebugSecurityProfiles(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/debug")
+			c.Check(r.URL.Query().Get("aspect"), check.Equals, "security-profiles")
+			c.Check(r.URL.Query().Get("snap"), check.Equals, "foo")
+			c.Check(r.URL.Query().Has("plug"), check.Equals, false)
+			fmt.Fprintln(w, securityProfilesJSON)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "security-profiles", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `# apparmor: snap.foo.app
+#include <tunables/global>  # base-template
+
+@{PROC}/net/dev r,  # network-observe connected-plug foo:network-observe core:network-observe
+
+# seccomp: snap.foo.app
+socket AF_NETLINK - NETLINK_ROUTE  # network-observe plug foo:network-observe
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(n, check.Equals, 1)
+}
+
+func (s *SnapSuite) TestDebugSecurityProfilesConnectionBackend(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Query().Get("aspect"), check.Equals, "security-profiles")
+		c.Check(r.URL.Query().Get("snap"), check.Equals, "foo")
+		c.Check(r.URL.Query().Get("plug"), check.Equals, "foo:network-observe")
+		c.Check(r.URL.Query().Get("slot"), check.Equals, ":network-observe")
+		fmt.Fprintln(w, securityProfilesJSON)
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "security-profiles", "--backend=seccomp", "--plug=foo:network-observe", "--slot=:network-observe", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `# seccomp: snap.foo.app
++socket AF_NETLINK - NETLINK_ROUTE  # network-observe plug foo:network-observe
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestDebugSecurityProfilesConnectionNoChange(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "security-profiles", "--plug=foo:home", "--slot=:home", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "Connecting foo:home to :home would not change the profiles of snap \"foo\".\n")
+}
+
+func (s *SnapSuite) TestDebugSecurityProfilesPlugWithoutSlot(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "security-profiles", "--plug=foo:home", "foo"})
+	c.Assert(err, check.ErrorMatches, "--plug and --slot must be used together")
+}
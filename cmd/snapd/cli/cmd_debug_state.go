@@ -436,6 +436,9 @@ func (c *cmdDebugState) showConnections(st *state.State) error {
 		if conn.ByGadget {
 			notes = append(notes, "by-gadget")
 		}
+		if conn.ByModel {
+			notes = append(notes, "by-model")
+		}
 		fmt.Fprintf(w, "%s\t%s:%s\t%s:%s\t%s\n", conn.Interface, conn.PlugSnap, conn.PlugName, conn.SlotSnap, conn.SlotName, strings.Join(notes, ","))
 	}
 	w.Flush()
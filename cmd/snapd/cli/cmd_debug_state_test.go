@@ -515,6 +515,7 @@ func (s *SnapSuite) TestDebugConnectionDetails(c *C) {
 			"id: gnome-calculator:gtk-3-themes gtk-common-themes:gtk-3-themes\n"+
 				"auto: true\n"+
 				"by-gadget: false\n"+
+				"by-model: false\n"+
 				"interface: content\n"+
 				"undesired: false\n"+
 				"plug-static:\n"+
@@ -545,6 +546,7 @@ func (s *SnapSuite) TestDebugConnectionPlugAndSlot(c *C) {
 		"id: gnome-calculator:network core:network\n"+
 			"auto: true\n"+
 			"by-gadget: false\n"+
+			"by-model: false\n"+
 			"interface: network\n"+
 			"undesired: false\n"+
 			"\n", Commentf("#0: %s", connArg))
@@ -574,36 +576,42 @@ func (s *SnapSuite) TestDebugConnectionDetailsMany(c *C) {
 		"id: gnome-calculator:desktop-legacy core:desktop-legacy\n"+
 			"auto: true\n"+
 			"by-gadget: false\n"+
+			"by-model: false\n"+
 			"interface: desktop-legacy\n"+
 			"undesired: false\n"+
 			"\n"+
 			"id: gnome-calculator:network core:network\n"+
 			"auto: true\n"+
 			"by-gadget: false\n"+
+			"by-model: false\n"+
 			"interface: network\n"+
 			"undesired: false\n"+
 			"\n"+
 			"id: gnome-calculator:x11 core:x11\n"+
 			"auto: true\n"+
 			"by-gadget: false\n"+
+			"by-model: false\n"+
 			"interface: x11\n"+
 			"undesired: false\n"+
 			"\n"+
 			"id: some-snap:network core:network\n"+
 			"auto: true\n"+
 			"by-gadget: true\n"+
+			"by-model: false\n"+
 			"interface: network\n"+
 			"undesired: false\n"+
 			"\n"+
 			"id: vlc:network core:network\n"+
 			"auto: true\n"+
 			"by-gadget: false\n"+
+			"by-model: false\n"+
 			"interface: network\n"+
 			"undesired: true\n"+
 			"\n"+
 			"id: vlc:x11 core:x11\n"+
 			"auto: true\n"+
 			"by-gadget: false\n"+
+			"by-model: false\n"+
 			"interface: x11\n"+
 			"undesired: false\n"+
 			"\n")
@@ -623,12 +631,14 @@ func (s *SnapSuite) TestDebugConnectionDetailsManySlotSide(c *C) {
 		"id: gnome-calculator:x11 core:x11\n"+
 			"auto: true\n"+
 			"by-gadget: false\n"+
+			"by-model: false\n"+
 			"interface: x11\n"+
 			"undesired: false\n"+
 			"\n"+
 			"id: vlc:x11 core:x11\n"+
 			"auto: true\n"+
 			"by-gadget: false\n"+
+			"by-model: false\n"+
 			"interface: x11\n"+
 			"undesired: false\n"+
 			"\n")
@@ -31,6 +31,7 @@ import (
 type cmdDisconnect struct {
 	waitMixin
 	Forget      bool `long:"forget"`
+	User        bool `long:"user"`
 	Positionals struct {
 		Offer disconnectSlotOrPlugSpec `required:"true"`
 		Use   disconnectSlotSpec
@@ -55,12 +56,19 @@ When an automatic connection is manually disconnected, its disconnected state
 is retained after a snap refresh. The --forget flag can be added to the
 disconnect command to reset this behaviour, and consequently re-enable
 an automatic reconnection after a snap refresh.
+
+With --user the connection is removed for the calling user only, it must have
+been limited to some users with snap connect --user.
 `)
 
 func init() {
 	addCommand("disconnect", shortDisconnectHelp, longDisconnectHelp, func() flags.Commander {
 		return &cmdDisconnect{}
-	}, waitDescs.also(map[string]string{"forget": "Forget remembered state about the given connection."}), []argDesc{
+	}, waitDescs.also(map[string]string{
+		"forget": "Forget remembered state about the given connection.",
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"user": i18n.G("Disconnect for the calling user only"),
+	}), []argDesc{
 		// TRANSLATORS: This needs to begin with < and end with >
 		{name: i18n.G("<snap>:<plug>")},
 		// TRANSLATORS: This needs to begin with < and end with >
@@ -83,7 +91,7 @@ func (x *cmdDisconnect) Execute(args []string) error {
 		offer, use = use, offer
 	}
 
-	opts := &client.DisconnectOptions{Forget: x.Forget}
+	opts := &client.DisconnectOptions{Forget: x.Forget, ForSelf: x.User}
 	id, err := x.client.Disconnect(offer.Snap, offer.Name, use.Snap, use.Name, opts)
 	if err != nil {
 		if client.IsInterfacesUnchangedError(err) {
@@ -51,10 +51,14 @@ is retained after a snap refresh. The --forget flag can be added to the
 disconnect command to reset this behaviour, and consequently re-enable
 an automatic reconnection after a snap refresh.
 
+With --user the connection is removed for the calling user only, it must have
+been limited to some users with snap connect --user.
+
 [disconnect command options]
       --no-wait          Do not wait for the operation to finish but just print
                          the change id.
       --forget           Forget remembered state about the given connection.
+      --user             Disconnect for the calling user only
 `
 	s.testSubCommandHelp(c, "disconnect", msg)
 }
@@ -132,6 +136,30 @@ func (s *SnapSuite) TestDisconnectWithForgetFlag(c *C) {
 	c.Assert(s.Stderr(), Equals, "")
 }
 
+func (s *SnapSuite) TestDisconnectUser(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/interfaces/own":
+			c.Check(r.Method, Equals, "POST")
+			body := DecodedRequestBody(c, r)
+			c.Check(body["action"], Equals, "disconnect")
+			c.Check(body["users"], IsNil)
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "status-code": 202, "change": "zzz"}`)
+		case "/v2/changes/zzz":
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintln(w, `{"type":"sync", "result":{"ready": true, "status": "Done"}}`)
+		default:
+			c.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	})
+	rest, err := Parser(Client()).ParseArgs([]string{"disconnect", "--user", "consumer:plug", "producer:slot"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Assert(s.Stdout(), Equals, "")
+	c.Assert(s.Stderr(), Equals, "")
+}
+
 func (s *SnapSuite) TestDisconnectEverythingFromSpecificSlot(c *C) {
 	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
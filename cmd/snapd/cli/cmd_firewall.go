@@ -0,0 +1,89 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+var shortFirewallHelp = i18n.G("Show or confirm the state of the firewall configuration")
+var longFirewallHelp = i18n.G(`
+The firewall command shows whether the firewall rules applied last by
+'snap set system system.firewall.*' are waiting for confirmation.
+
+New firewall rules are rolled back unless confirmed in time, so that rules
+locking out whoever is managing the device do not stay in place:
+
+    $ snap firewall confirm
+`)
+
+type cmdFirewall struct {
+	clientMixin
+	timeMixin
+	Positional struct {
+		Action string `positional-arg-name:"<action>"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addCommand("firewall", shortFirewallHelp, longFirewallHelp, func() flags.Commander { return &cmdFirewall{} },
+		timeDescs, []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<action>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("Action to perform on the firewall configuration (only 'confirm' is supported)"),
+		}})
+}
+
+func (x *cmdFirewall) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	switch x.Positional.Action {
+	case "":
+		return x.showStatus()
+	case "confirm":
+		if err := x.client.ConfirmFirewall(); err != nil {
+			return err
+		}
+		fmt.Fprintln(Stdout, i18n.G("Firewall configuration confirmed."))
+		return nil
+	default:
+		return fmt.Errorf(i18n.G("unsupported firewall action %q"), x.Positional.Action)
+	}
+}
+
+func (x *cmdFirewall) showStatus() error {
+	status, err := x.client.Firewall()
+	if err != nil {
+		return err
+	}
+	if status.ConfirmBefore == nil {
+		fmt.Fprintln(Stdout, i18n.G("No firewall changes are waiting for confirmation."))
+		return nil
+	}
+	fmt.Fprintf(Stdout, i18n.G("Firewall changes must be confirmed with \"snap firewall confirm\" before %s, otherwise they are reverted.\n"), x.fmtTime(*status.ConfirmBefore))
+	return nil
+}
@@ -0,0 +1,103 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snapd/cli"
+)
+
+func (s *SnapSuite) TestFirewallStatus(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/system-firewall")
+		fmt.Fprintln(w, `{"type": "sync", "result": {"confirm-before": "2026-10-19T12:00:00Z"}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"firewall", "--abs-time"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "Firewall changes must be confirmed with \"snap firewall confirm\" before 2026-10-19T12:00:00Z, otherwise they are reverted.\n")
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestFirewallStatusRelativeTime(c *C) {
+	restore := snap.MockTimeutilHuman(func(t time.Time) string {
+		c.Check(t.Equal(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)), Equals, true)
+		return "today at 12:00 UTC"
+	})
+	defer restore()
+
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": {"confirm-before": "2026-10-19T12:00:00Z"}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"firewall"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "Firewall changes must be confirmed with \"snap firewall confirm\" before today at 12:00 UTC, otherwise they are reverted.\n")
+}
+
+func (s *SnapSuite) TestFirewallStatusNothingPending(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": {}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"firewall"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "No firewall changes are waiting for confirmation.\n")
+}
+
+func (s *SnapSuite) TestFirewallConfirm(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/system-firewall")
+		c.Check(DecodedRequestBody(c, r), DeepEquals, map[string]any{"action": "confirm"})
+		fmt.Fprintln(w, `{"type": "sync", "result": null}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"firewall", "confirm"})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	c.Check(s.Stdout(), Equals, "Firewall configuration confirmed.\n")
+}
+
+func (s *SnapSuite) TestFirewallConfirmNothingPending(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprintln(w, `{"type": "error", "status-code": 400, "result": {"message": "no firewall changes are waiting for confirmation"}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"firewall", "confirm"})
+	c.Check(err, ErrorMatches, "no firewall changes are waiting for confirmation")
+}
+
+func (s *SnapSuite) TestFirewallBadArgs(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"firewall", "revert"})
+	c.Check(err, ErrorMatches, `unsupported firewall action "revert"`)
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"firewall", "confirm", "now"})
+	c.Check(err, ErrorMatches, "too many arguments for command")
+}
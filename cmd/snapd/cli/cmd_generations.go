@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+)
+
+var shortGenerationsHelp = i18n.G("List snap set generations")
+var longGenerationsHelp = i18n.G(`
+The generations command lists the sets of snaps that were installed or
+refreshed together with an all-snaps transaction, and that can be reverted
+as a whole with 'snap revert-generation'.
+`)
+
+var shortRevertGenerationHelp = i18n.G("Revert a snap set generation")
+var longRevertGenerationHelp = i18n.G(`
+The revert-generation command reverts all the snaps of the given snap set
+generation to the revisions they had before it, in a single transaction.
+The configuration and the interface connections the snaps had before the
+generation are restored, and the snaps it installed are removed.
+`)
+
+type cmdGenerations struct {
+	clientMixin
+	timeMixin
+}
+
+type cmdRevertGeneration struct {
+	waitMixin
+
+	Purge bool `long:"purge"`
+
+	Positional struct {
+		ID string `positional-arg-name:"<id>" required:"1"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func init() {
+	addCommand("generations", shortGenerationsHelp, longGenerationsHelp, func() flags.Commander {
+		return &cmdGenerations{}
+	}, timeDescs, nil)
+	addCommand("revert-generation", shortRevertGenerationHelp, longRevertGenerationHelp, func() flags.Commander {
+		return &cmdRevertGeneration{}
+	}, waitDescs.also(map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"purge": i18n.G("Remove the snaps installed by the generation without saving a snapshot of their data"),
+	}), []argDesc{{
+		// TRANSLATORS: This needs to begin with < and end with >
+		name: i18n.G("<id>"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		desc: i18n.G("Snap set generation to revert"),
+	}})
+}
+
+func (x *cmdGenerations) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	gens, err := x.client.SnapSetGenerations()
+	if err != nil {
+		return err
+	}
+	if len(gens) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No snap set generations."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprint(w, i18n.G("ID\tChange\tTime\tSnap\tBefore\tAfter\n"))
+	for _, gen := range gens {
+		for _, gs := range gen.Snaps {
+			before := "-"
+			if !gs.Before.Unset() {
+				before = gs.Before.String()
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", gen.ID, gen.ChangeID, x.fmtTime(gen.Time), gs.Name, before, gs.After)
+		}
+	}
+	return nil
+}
+
+func (x *cmdRevertGeneration) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	id, err := strconv.Atoi(x.Positional.ID)
+	if err != nil || id <= 0 {
+		return fmt.Errorf(i18n.G("invalid snap set generation: %q"), x.Positional.ID)
+	}
+
+	changeID, err := x.client.RevertSnapSetGeneration(id, &client.SnapOptions{Purge: x.Purge})
+	if err != nil {
+		return err
+	}
+
+	if _, err := x.wait(changeID); err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	fmt.Fprintf(Stdout, i18n.G("Snap set generation %d reverted\n"), id)
+	return nil
+}
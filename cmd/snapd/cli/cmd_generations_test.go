@@ -0,0 +1,110 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cli_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snapd/cli"
+)
+
+func (s *SnapSuite) TestGenerations(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/snap-set-generations")
+		fmt.Fprintln(w, `{"type": "sync", "result": [{
+			"id": 2,
+			"change-id": "42",
+			"time": "2026-10-01T10:00:00Z",
+			"snaps": [
+				{"name": "new-snap", "before": "unset", "after": "3"},
+				{"name": "some-snap", "before": "1", "after": "2"}
+			]
+		}]}`)
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"generations", "--abs-time"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+ID   Change  Time                  Snap       Before  After
+2    42      2026-10-01T10:00:00Z  new-snap   -       3
+2    42      2026-10-01T10:00:00Z  some-snap  1       2
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestGenerationsNone(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"generations"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No snap set generations.\n")
+}
+
+func (s *SnapSuite) TestRevertGeneration(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, Equals, "POST")
+			c.Check(r.URL.Path, Equals, "/v2/snap-set-generations")
+			c.Check(DecodedRequestBody(c, r), DeepEquals, map[string]any{
+				"action": "revert",
+				"id":     json.Number("2"),
+				"purge":  true,
+			})
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type": "async", "change": "43", "status-code": 202}`)
+		case 1:
+			c.Check(r.Method, Equals, "GET")
+			c.Check(r.URL.Path, Equals, "/v2/changes/43")
+			fmt.Fprintln(w, `{"type": "sync", "result": {"kind": "revert-snap-set-generation", "ready": true, "status": "Done"}}`)
+		default:
+			c.Fatalf("expected to get 2 requests, now on %d", n+1)
+		}
+		n++
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"revert-generation", "--purge", "2"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, "Snap set generation 2 reverted\n")
+	c.Check(s.Stderr(), Equals, "")
+	c.Check(n, Equals, 2)
+}
+
+func (s *SnapSuite) TestRevertGenerationInvalid(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request")
+	})
+
+	for _, id := range []string{"foo", "0", "-1"} {
+		_, err := snap.Parser(snap.Client()).ParseArgs([]string{"revert-generation", "--", id})
+		c.Check(err, ErrorMatches, fmt.Sprintf(`invalid snap set generation: %q`, id))
+	}
+}
@@ -208,7 +208,7 @@ var helpCategories = []helpCategory{
 	}, {
 		Label:       i18n.G("...more"),
 		Description: i18n.G("slightly more advanced snap management"),
-		Commands:    []string{"refresh", "revert", "switch", "disable", "enable", "create-cohort"},
+		Commands:    []string{"refresh", "revert", "switch", "disable", "enable", "autoremove", "generations", "revert-generation", "create-cohort"},
 	}, {
 		Label:       i18n.G("History"),
 		Description: i18n.G("manage system change transactions"),
@@ -245,7 +245,7 @@ var helpCategories = []helpCategory{
 	}, {
 		Label:       i18n.G("Device"),
 		Description: i18n.G("manage device"),
-		Commands:    []string{"model", "remodel", "reboot", "recovery"},
+		Commands:    []string{"model", "remodel", "reboot", "recovery", "firewall"},
 	}, {
 		Label:       i18n.G("Warnings"),
 		Other:       true,
@@ -61,6 +61,7 @@ type cmdPrepareImage struct {
 	WriteRevisionsFile       string   `long:"write-revisions" optional:"true" optional-value:"./seed.manifest"`
 	Validation               string   `long:"validation" choice:"ignore" choice:"enforce"`
 	AllowSnapdKernelMismatch bool     `long:"allow-snapd-kernel-mismatch"`
+	SnapMirror               string   `long:"snap-mirror" value-name:"<dir>"`
 
 	// Filenames for extra assertions
 	ExtraAssertionFiles []string `long:"assert" value-name:"<filename>"`
@@ -111,6 +112,8 @@ For preparing classic images it supports a --classic mode`),
 			"allow-snapd-kernel-mismatch": i18n.G("Whether a mismatch between versions of the snapd snap and snapd in kernel is allowed"),
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"assert": i18n.G("Include the assertion from the local file"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"snap-mirror": i18n.G("Resolve snaps and assertions only against the given local mirror directory instead of the store"),
 		}, []argDesc{
 			{
 				// TRANSLATORS: This needs to begin with < and end with >
@@ -144,6 +147,7 @@ func (x *cmdPrepareImage) Execute(args []string) error {
 		SeedManifestPath:         x.WriteRevisionsFile,
 		AllowSnapdKernelMismatch: x.AllowSnapdKernelMismatch,
 		ExtraAssertionsFiles:     x.ExtraAssertionFiles,
+		SnapMirrorDir:            x.SnapMirror,
 	}
 
 	if x.RevisionsFile != "" {
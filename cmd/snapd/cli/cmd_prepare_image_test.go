@@ -315,6 +315,26 @@ func (s *SnapPrepareImageSuite) TestPrepareImageWriteRevisions(c *C) {
 	})
 }
 
+func (s *SnapPrepareImageSuite) TestPrepareImageSnapMirror(c *C) {
+	var opts *image.Options
+	prep := func(o *image.Options) error {
+		opts = o
+		return nil
+	}
+	r := cmdsnap.MockImagePrepare(prep)
+	defer r()
+
+	rest, err := cmdsnap.Parser(cmdsnap.Client()).ParseArgs([]string{"prepare-image", "--snap-mirror", "/srv/mirror", "model", "prepare-dir"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+
+	c.Check(opts, DeepEquals, &image.Options{
+		ModelFile:     "model",
+		PrepareDir:    "prepare-dir",
+		SnapMirrorDir: "/srv/mirror",
+	})
+}
+
 func (s *SnapPrepareImageSuite) TestPrepareImageValidation(c *C) {
 	var opts *image.Options
 	prep := func(o *image.Options) error {
@@ -142,13 +142,20 @@ func (x *cmdSet) Execute([]string) error {
 		return err
 	}
 
-	if _, err := x.wait(chgID); err != nil {
+	chg, err := x.wait(chgID)
+	if err != nil {
 		if err == noWait {
 			return nil
 		}
 		return err
 	}
 
+	// new firewall rules are rolled back unless confirmed in time
+	var confirmBefore time.Time
+	if err := chg.Get("firewall-confirm-before", &confirmBefore); err == nil {
+		fmt.Fprintf(Stdout, i18n.G("Firewall changes must be confirmed with \"snap firewall confirm\" before %s, otherwise they are reverted.\n"), timeutilHuman(confirmBefore))
+	}
+
 	return nil
 }
 
@@ -25,6 +25,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -142,6 +143,33 @@ func (s *snapSetSuite) TestSnapSetAsString(c *check.C) {
 	c.Check(s.setConfApiCalls, check.Equals, 1)
 }
 
+func (s *snapSetSuite) TestSnapSetFirewallConfirmation(c *check.C) {
+	restore := snapset.MockTimeutilHuman(func(t time.Time) string {
+		c.Check(t.Equal(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)), check.Equals, true)
+		return "today at 12:00 UTC"
+	})
+	defer restore()
+
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/snaps/system/conf":
+			c.Check(r.Method, check.Equals, "PUT")
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "status-code": 202, "change": "zzz"}`)
+		case "/v2/changes/zzz":
+			c.Check(r.Method, check.Equals, "GET")
+			fmt.Fprintln(w, `{"type":"sync", "result":{"ready": true, "status": "Done", "data": {"firewall-confirm-before": "2026-10-19T12:00:00Z"}}}`)
+		default:
+			c.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	})
+
+	_, err := snapset.Parser(snapset.Client()).ParseArgs([]string{"set", "system", "system.firewall.allow=22/tcp"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "Firewall changes must be confirmed with \"snap firewall confirm\" before today at 12:00 UTC, otherwise they are reverted.\n")
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
 func (s *snapSetSuite) mockSetConfigServer(c *check.C, expectedValue any) {
 	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
@@ -480,7 +480,7 @@ func changedSnapsFromChange(chg *client.Change) (*changedSnapsData, error) {
 		snapshots: make(map[string]bool, len(snapNames)),
 	}
 
-	if chg.Kind == "remove-snap" {
+	if chg.Kind == "remove-snap" || chg.Kind == "autoremove-snap" {
 		for _, t := range chg.Tasks {
 			var affected []string
 			if t.Kind == "save-snapshot" {
@@ -1107,6 +1107,36 @@ func (x *cmdRefresh) showRefreshTimes() error {
 	} else {
 		fmt.Fprintf(Stdout, "next: n/a\n")
 	}
+	if sysinfo.Refresh.Blackout != "" {
+		fmt.Fprintf(Stdout, "blackout: %s\n", sysinfo.Refresh.Blackout)
+	}
+	if len(sysinfo.Refresh.SnapTimers) > 0 {
+		names := make([]string, 0, len(sysinfo.Refresh.SnapTimers))
+		for name := range sysinfo.Refresh.SnapTimers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(Stdout, "snap-timers:\n")
+		for _, name := range names {
+			fmt.Fprintf(Stdout, "  %s: %s\n", name, sysinfo.Refresh.SnapTimers[name])
+		}
+	}
+	if len(sysinfo.Refresh.Staging) > 0 {
+		names := make([]string, 0, len(sysinfo.Refresh.Staging))
+		for name := range sysinfo.Refresh.Staging {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(Stdout, "staging:\n")
+		for _, name := range names {
+			staging := sysinfo.Refresh.Staging[name]
+			fmt.Fprintf(Stdout, "  %s: delay %s of %s", name, staging.Delay, staging.MaxDelay)
+			if until := parseSysinfoTime(staging.Until); staging.Revision != "" && !until.IsZero() {
+				fmt.Fprintf(Stdout, ", revision %s held until %s", staging.Revision, x.fmtTime(until))
+			}
+			fmt.Fprintf(Stdout, "\n")
+		}
+	}
 	return nil
 }
 
@@ -195,6 +195,14 @@ func MockUserCurrent(f func() (*user.User, error)) (restore func()) {
 	}
 }
 
+func MockUserLookupId(f func(string) (*user.User, error)) (restore func()) {
+	old := userLookupId
+	userLookupId = f
+	return func() {
+		userLookupId = old
+	}
+}
+
 func MockStoreNew(f func(*store.Config, store.DeviceAndAuthContext) *store.Store) (restore func()) {
 	storeNewOrig := storeNew
 	storeNew = f
@@ -210,6 +210,28 @@ func (ac authenticatedAccess) CheckAccess(d *Daemon, r *http.Request, ucred *ucr
 	return checkAccess(d, r, ucred, user, opts)
 }
 
+// userAccess allows requests from users acting for themselves only,
+// provided they were not received on snapd-snap.socket and the user is
+// either root or granted access by Polkit. The handler must limit the
+// effects of the request to the requesting user.
+type userAccess struct {
+	// Polkit is the polkit action to check if the user is not root.
+	// Unlike for authenticatedAccess, a macaroon does not grant access
+	// and the action may only require the authentication of the user
+	// themselves, e.g. with auth_self_keep, as the effects of the
+	// request are limited to them.
+	Polkit string
+}
+
+func (ac userAccess) CheckAccess(d *Daemon, r *http.Request, ucred *ucrednet, user *auth.UserState) *apiError {
+	opts := accessOptions{
+		AccessLevel:  accessLevelRoot,
+		Sockets:      []string{dirs.SnapdSocket},
+		PolkitAction: ac.Polkit,
+	}
+	return checkAccess(d, r, ucred, user, opts)
+}
+
 // rootAccess allows requests from the root uid, provided they
 // were not received on snapd-snap.socket
 type rootAccess struct{}
@@ -146,6 +146,38 @@ func (s *accessSuite) TestAuthenticatedAccessPolkit(c *C) {
 	c.Check(ac.CheckAccess(nil, req, ucred, nil), IsNil)
 }
 
+func (s *accessSuite) TestUserAccess(c *C) {
+	var ac daemon.AccessChecker = daemon.UserAccess{Polkit: "action-id"}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	user := &auth.UserState{}
+
+	restore := daemon.MockCheckPolkitAction(func(r *http.Request, ucred *daemon.Ucrednet, action string) *daemon.APIError {
+		c.Fail()
+		return daemon.Forbidden("access denied")
+	})
+	defer restore()
+
+	// userAccess denies access from snapd-snap.socket
+	ucred := &daemon.Ucrednet{Uid: 0, Pid: 100, Socket: dirs.SnapSocket}
+	c.Check(ac.CheckAccess(nil, req, ucred, nil), DeepEquals, errForbidden)
+
+	// The root user is granted access
+	ucred = &daemon.Ucrednet{Uid: 0, Pid: 100, Socket: dirs.SnapdSocket}
+	c.Check(ac.CheckAccess(nil, req, ucred, nil), IsNil)
+
+	// polkit is checked for regular users, even with macaroon auth
+	restore = daemon.MockCheckPolkitAction(func(r *http.Request, u *daemon.Ucrednet, action string) *daemon.APIError {
+		c.Check(u, Equals, ucred)
+		c.Check(action, Equals, "action-id")
+		return daemon.Forbidden("access denied")
+	})
+	defer restore()
+	ucred = &daemon.Ucrednet{Uid: 42, Pid: 100, Socket: dirs.SnapdSocket}
+	c.Check(ac.CheckAccess(nil, req, ucred, nil), DeepEquals, errForbidden)
+	c.Check(ac.CheckAccess(nil, req, ucred, user), DeepEquals, errForbidden)
+}
+
 func (s *accessSuite) TestCheckPolkitActionImpl(c *C) {
 	logbuf, restore := logger.MockLogger()
 	defer restore()
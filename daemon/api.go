@@ -67,10 +67,12 @@ var api = []*Command{
 	logsCmd,
 	warningsCmd,
 	debugPprofCmd,
+	debugDenialsCmd,
 	debugCmd,
 	snapshotCmd,
 	snapshotExportCmd,
 	connectionsCmd,
+	ownInterfacesCmd,
 	modelCmd,
 	cohortsCmd,
 	serialModelCmd,
@@ -82,6 +84,7 @@ var api = []*Command{
 	validationSetsCmd,
 	routineConsoleConfStartCmd,
 	systemRecoveryKeysCmd,
+	systemFirewallCmd,
 	quotaGroupsCmd,
 	quotaGroupInfoCmd,
 	confdbCmd,
@@ -95,6 +98,7 @@ var api = []*Command{
 	requestsRuleCmd,
 	systemSecurebootCmd,
 	systemVolumesCmd,
+	snapSetGenerationsCmd,
 }
 
 type featureEndpoint struct {
@@ -134,6 +138,7 @@ const (
 	polkitActionLogin               = "io.snapcraft.snapd.login"
 	polkitActionManage              = "io.snapcraft.snapd.manage"
 	polkitActionManageInterfaces    = "io.snapcraft.snapd.manage-interfaces"
+	polkitActionManageOwnInterfaces = "io.snapcraft.snapd.manage-own-interfaces"
 	polkitActionManageConfiguration = "io.snapcraft.snapd.manage-configuration"
 	polkitActionManageFDE           = "io.snapcraft.snapd.manage-fde"
 )
@@ -210,6 +215,10 @@ var (
 	snapstateLongestGatingHold              = snapstate.LongestGatingHold
 	snapstateSystemHold                     = snapstate.SystemHold
 	snapstateRemoveComponents               = snapstate.RemoveComponents
+	snapstateAutoremove                     = snapstate.Autoremove
+	snapstateAutoremoveCandidates           = snapstate.AutoremoveCandidates
+	snapstateSnapSetGenerations             = snapstate.SnapSetGenerations
+	snapstateRevertSnapSetGeneration        = snapstate.RevertSnapSetGeneration
 
 	configstateConfigureInstalled = configstate.ConfigureInstalled
 
@@ -118,6 +118,14 @@ func getAppsInfo(c *Command, r *http.Request, user *auth.UserState) Response {
 		return InternalError("%v", err)
 	}
 
+	st := c.d.overlord.State()
+	st.Lock()
+	err = servicestate.DecorateWithProbeStatus(st, clientAppInfos)
+	st.Unlock()
+	if err != nil {
+		return InternalError("%v", err)
+	}
+
 	return SyncResponse(clientAppInfos)
 }
 
@@ -32,6 +32,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -365,6 +366,63 @@ func (s *appsSuite) TestGetAppsInfoServices(c *check.C) {
 	c.Check(sort.StringsAreSorted(appNames), check.Equals, true)
 }
 
+func (s *appsSuite) TestGetAppsInfoProbes(c *check.C) {
+	r := daemon.MockNewStatusDecorator(func(ctx context.Context, isGlobal bool, uid string) clientutil.StatusDecorator {
+		return s
+	})
+	defer r()
+	s.decoratorResults = map[string]appsSuiteDecoratorResult{
+		"snap-f.svc5": {
+			daemonType: "simple",
+			active:     true,
+			enabled:    true,
+		},
+	}
+
+	s.mkInstalledInState(c, s.d, "snap-f", "dev", "v1", snap.R(1), true, `apps:
+  svc5:
+    daemon: simple
+    liveness-probe:
+      http:
+        port: 8080
+    readiness-probe:
+      exec: bin/ready
+`)
+	since := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	st := s.d.Overlord().State()
+	st.Lock()
+	st.Set("probes", map[string]any{
+		"snap-f.svc5:liveness": map[string]any{
+			"failing":    true,
+			"since":      since,
+			"last-error": "connection refused",
+			"restarts":   2,
+		},
+	})
+	st.Unlock()
+
+	req, err := http.NewRequest("GET", "/v2/apps?names=snap-f", nil)
+	c.Assert(err, check.IsNil)
+
+	rsp := s.syncReq(c, req, nil, actionIsExpected)
+	c.Assert(rsp.Status, check.Equals, 200)
+	c.Assert(rsp.Result, check.FitsTypeOf, []client.AppInfo{})
+	svcs := rsp.Result.([]client.AppInfo)
+	c.Assert(svcs, check.HasLen, 1)
+	c.Check(svcs[0].Probes, check.DeepEquals, []client.AppProbe{{
+		Kind:     "liveness",
+		Type:     "http",
+		Status:   client.ProbeStatusFailing,
+		Since:    since,
+		Message:  "connection refused",
+		Restarts: 2,
+	}, {
+		Kind:   "readiness",
+		Type:   "exec",
+		Status: client.ProbeStatusUnknown,
+	}})
+}
+
 func (s *appsSuite) TestGetAppsInfoServicesWithGlobal(c *check.C) {
 	// System services from active snaps
 	svcNames := []string{"snap-a.svc1", "snap-a.svc2"}
@@ -163,9 +163,12 @@ func collectConnections(ifaceMgr *ifacestate.InterfaceManager, filter collectFil
 			Plug:      plugRef,
 			Manual:    !cstate.Auto,
 			Gadget:    cstate.ByGadget,
+			Model:     cstate.ByModel,
 			Interface: cstate.Interface,
 			PlugAttrs: mergeAttrs(cstate.StaticPlugAttrs, cstate.DynamicPlugAttrs),
 			SlotAttrs: mergeAttrs(cstate.StaticSlotAttrs, cstate.DynamicSlotAttrs),
+			Expiry:    cstate.Expiry,
+			Users:     cstate.Users,
 		}
 		if cstate.Undesired {
 			// explicitly disconnected are always manual
@@ -45,7 +45,7 @@ var debugCmd = &Command{
 	Actions: []string{
 		"add-warning", "unshow-warnings", "ensure-state-soon",
 		"can-manage-refreshes", "prune", "stacktraces",
-		"create-recovery-system", "migrate-home",
+		"create-recovery-system", "migrate-home", "device-audit",
 	},
 	ReadAccess:  openAccess{},
 	WriteAccess: rootAccess{},
@@ -58,6 +58,8 @@ type debugAction struct {
 		ChgID string `json:"chg-id"`
 
 		RecoverySystemLabel string `json:"recovery-system-label"`
+
+		Enable bool `json:"enable"`
 	} `json:"params"`
 	Snaps []string `json:"snaps"`
 }
@@ -418,6 +420,8 @@ func getDebug(c *Command, r *http.Request, user *auth.UserState) Response {
 		return getRAAInfo(st)
 	case "features":
 		return getFeatures(c)
+	case "security-profiles":
+		return getSecurityProfiles(c, query.Get("snap"), query.Get("plug"), query.Get("slot"))
 	default:
 		return BadRequest("unknown debug aspect %q", aspect)
 	}
@@ -459,6 +463,8 @@ func postDebug(c *Command, r *http.Request, user *auth.UserState) Response {
 		return createRecovery(st, a.Params.RecoverySystemLabel)
 	case "migrate-home":
 		return migrateHome(st, a.Snaps)
+	case "device-audit":
+		return setDeviceAudit(st, a.Snaps, a.Params.Enable)
 	default:
 		return BadRequest("unknown debug action: %v", a.Action)
 	}
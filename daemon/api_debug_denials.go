@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/snap"
+)
+
+// The denials are read from the journal and leak the activity of the snap,
+// so unlike the other debug aspects they are only available to root.
+var debugDenialsCmd = &Command{
+	Path:       "/v2/debug/denials",
+	GET:        getDebugDenials,
+	ReadAccess: rootAccess{},
+}
+
+// defaultDenialsLines is the number of journal entries searched for denials
+// unless requested otherwise, it is also the maximum that can be requested.
+const defaultDenialsLines = 10000
+
+var (
+	denialsCollect        = denials.Collect
+	denialsCollectDevices = denials.CollectDevices
+)
+
+type denialInfo struct {
+	*denials.Denial
+	// Candidates are the interfaces that would grant the denied access.
+	Candidates []denialCandidate `json:"candidates,omitempty"`
+}
+
+type denialCandidate struct {
+	Interface string `json:"interface"`
+	Plug      string `json:"plug,omitempty"`
+	Connected bool   `json:"connected,omitempty"`
+}
+
+func getDebugDenials(c *Command, r *http.Request, user *auth.UserState) Response {
+	query := r.URL.Query()
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	return getDenials(c, query.Get("snap"), query.Get("lines"))
+}
+
+func getDenials(c *Command, instanceName, linesStr string) Response {
+	if instanceName == "" {
+		return BadRequest("cannot list denials: snap name is required")
+	}
+	lines := defaultDenialsLines
+	if linesStr != "" {
+		n, err := strconv.Atoi(linesStr)
+		if err != nil || n <= 0 {
+			return BadRequest("cannot list denials: invalid number of lines %q", linesStr)
+		}
+		lines = n
+		if lines > defaultDenialsLines {
+			lines = defaultDenialsLines
+		}
+	}
+
+	st := c.d.overlord.State()
+	info, err := snapstate.CurrentInfo(st, instanceName)
+	if err != nil {
+		var notInstalled *snap.NotInstalledError
+		if errors.As(err, &notInstalled) {
+			return SnapNotInstalled(instanceName, err)
+		}
+		return InternalError("cannot list denials: %v", err)
+	}
+
+	repo := c.d.overlord.InterfaceManager().Repository()
+	matcher, err := denials.NewMatcher(info, repo.AllInterfaces())
+	if err != nil {
+		return InternalError("cannot list denials: %v", err)
+	}
+
+	// reading the journal may take a while, do not block the state meanwhile
+	st.Unlock()
+	ds, err := denialsCollect(instanceName, lines)
+	if err == nil {
+		// the device accesses denied by the device cgroup are only
+		// counted when auditing is enabled for the snap
+		var devs []*denials.Denial
+		devs, err = denialsCollectDevices(info)
+		ds = append(ds, devs...)
+	}
+	st.Lock()
+	if err != nil {
+		return InternalError("cannot list denials: %v", err)
+	}
+
+	result := make([]denialInfo, 0, len(ds))
+	for _, d := range ds {
+		di := denialInfo{Denial: d}
+		for _, m := range matcher.Match(d) {
+			candidate := denialCandidate{Interface: m.Interface, Plug: m.Plug}
+			if m.Plug != "" {
+				conns, err := repo.Connected(instanceName, m.Plug)
+				candidate.Connected = err == nil && len(conns) > 0
+			}
+			di.Candidates = append(di.Candidates, candidate)
+		}
+		result = append(result, di)
+	}
+	return SyncResponse(result)
+}
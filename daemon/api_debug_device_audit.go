@@ -0,0 +1,58 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/overlord/swfeats"
+)
+
+var ifacestateSetDeviceAudit = ifacestate.SetDeviceAudit
+
+var deviceAuditChangeKind = swfeats.RegisterChangeKind("device-audit")
+
+func setDeviceAudit(st *state.State, snaps []string, enable bool) Response {
+	if len(snaps) != 1 {
+		return BadRequest("device audit action requires exactly one snap")
+	}
+	instanceName := snaps[0]
+
+	ts, err := ifacestateSetDeviceAudit(st, instanceName, enable)
+	if err != nil {
+		return errToResponse(err, snaps, BadRequest, "%v")
+	}
+
+	var summary string
+	if enable {
+		summary = fmt.Sprintf(i18n.G("Enable auditing of device accesses of snap %q"), instanceName)
+	} else {
+		summary = fmt.Sprintf(i18n.G("Disable auditing of device accesses of snap %q"), instanceName)
+	}
+	chg := st.NewChange(deviceAuditChangeKind, summary)
+	chg.AddAll(ts)
+	chg.Set("api-data", map[string][]string{"snap-names": snaps})
+
+	ensureStateSoon(st)
+	return AsyncResponse(nil, chg.ID())
+}
@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+// getSecurityProfiles returns the effective security profiles of a snap with
+// the origin of every line or, if a plug and a slot are given, what
+// connecting them would add to the profiles.
+func getSecurityProfiles(c *Command, instanceName, plug, slot string) Response {
+	if instanceName == "" {
+		return BadRequest("cannot inspect security profiles: snap name is required")
+	}
+	ifaceMgr := c.d.overlord.InterfaceManager()
+
+	var ref *interfaces.ConnRef
+	if plug != "" || slot != "" {
+		plugSnap, plugName, _ := strings.Cut(plug, ":")
+		slotSnap, slotName, _ := strings.Cut(slot, ":")
+		var err error
+		ref, err = ifaceMgr.Repository().ResolveConnect(plugSnap, plugName, slotSnap, slotName)
+		if err != nil {
+			return BadRequest("cannot inspect security profiles: %v", err)
+		}
+		if ref.PlugRef.Snap != instanceName && ref.SlotRef.Snap != instanceName {
+			return BadRequest("cannot inspect security profiles: snap %q is not part of connection %q", instanceName, ref.ID())
+		}
+	}
+
+	profiles, err := ifaceMgr.SnapProfiles(instanceName, ref)
+	if err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return SnapNotInstalled(instanceName, fmt.Errorf("snap %q is not installed", instanceName))
+		}
+		return InternalError("cannot inspect security profiles: %v", err)
+	}
+	return SyncResponse(profiles)
+}
@@ -24,6 +24,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -31,6 +32,11 @@ import (
 
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/interfaces/inspect"
+	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/testutil"
@@ -409,3 +415,305 @@ func (s *postDebugSuite) TestRefreshAppAwarenessUnhappy(c *check.C) {
 	c.Check(rsp.Status, check.Equals, 500)
 	c.Check(rsp.Message, check.Equals, "boom!")
 }
+
+func (s *postDebugSuite) TestGetDebugDenials(c *check.C) {
+	s.expectReadAccess(daemon.RootAccess{})
+	d := s.daemon(c)
+	s.mkInstalledInState(c, d, "foo", "", "v1", snap.R(1), true, `
+apps:
+  app:
+    command: app
+    plugs: [netctl]
+plugs:
+  netctl:
+    interface: network-control
+`)
+
+	capDenial := &denials.Denial{
+		Kind:       denials.KindAppArmor,
+		Label:      "snap.foo.app",
+		Snap:       "foo",
+		Operation:  "capable",
+		Class:      "cap",
+		Capability: "net_admin",
+		Message:    `apparmor="DENIED" operation="capable" profile="snap.foo.app" capname="net_admin"`,
+	}
+	var collected []string
+	restore := daemon.MockDenialsCollect(func(instanceName string, lines int) ([]*denials.Denial, error) {
+		collected = append(collected, fmt.Sprintf("%s %d", instanceName, lines))
+		return []*denials.Denial{capDenial}, nil
+	})
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/debug/denials?snap=foo&lines=100", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil, actionIsExpected)
+	c.Check(collected, check.DeepEquals, []string{"foo 100"})
+
+	// round trip through JSON as clients would see it
+	data, err := json.Marshal(rsp.Result)
+	c.Assert(err, check.IsNil)
+	var result []map[string]any
+	c.Assert(json.Unmarshal(data, &result), check.IsNil)
+	c.Assert(result, check.HasLen, 1)
+	c.Check(result[0]["label"], check.Equals, "snap.foo.app")
+	c.Check(result[0]["capability"], check.Equals, "net_admin")
+	c.Check(result[0]["candidates"], testutil.DeepContains, map[string]any{
+		"interface": "network-control",
+		"plug":      "netctl",
+	})
+	c.Check(result[0]["candidates"], testutil.DeepContains, map[string]any{
+		"interface": "firewall-control",
+	})
+
+	// the default number of lines
+	req, err = http.NewRequest("GET", "/v2/debug/denials?snap=foo", nil)
+	c.Assert(err, check.IsNil)
+	s.syncReq(c, req, nil, actionIsExpected)
+	c.Check(collected[1], check.Equals, "foo 10000")
+
+	// the number of lines is capped
+	req, err = http.NewRequest("GET", "/v2/debug/denials?snap=foo&lines=100000000", nil)
+	c.Assert(err, check.IsNil)
+	s.syncReq(c, req, nil, actionIsExpected)
+	c.Check(collected[2], check.Equals, "foo 10000")
+}
+
+func (s *postDebugSuite) TestGetDebugDenialsErrors(c *check.C) {
+	s.expectReadAccess(daemon.RootAccess{})
+	d := s.daemon(c)
+	s.mkInstalledInState(c, d, "foo", "", "v1", snap.R(1), true, "")
+
+	restore := daemon.MockDenialsCollect(func(instanceName string, lines int) ([]*denials.Denial, error) {
+		return nil, errors.New("boom")
+	})
+	defer restore()
+
+	for _, t := range []struct {
+		query  string
+		status int
+		err    string
+	}{
+		{"", 400, "cannot list denials: snap name is required"},
+		{"snap=foo&lines=x", 400, `cannot list denials: invalid number of lines "x"`},
+		{"snap=foo&lines=0", 400, `cannot list denials: invalid number of lines "0"`},
+		{"snap=bar", 400, `snap "bar" is not installed`},
+		{"snap=foo", 500, "cannot list denials: boom"},
+	} {
+		req, err := http.NewRequest("GET", "/v2/debug/denials?"+t.query, nil)
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil, actionIsExpected)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf(t.query))
+		c.Check(rspe.Message, check.Equals, t.err, check.Commentf(t.query))
+	}
+}
+
+func (s *postDebugSuite) TestGetDebugDenialsDevices(c *check.C) {
+	s.expectReadAccess(daemon.RootAccess{})
+	d := s.daemon(c)
+	s.mkInstalledInState(c, d, "foo", "", "v1", snap.R(1), true, `
+apps:
+  app:
+    command: app
+`)
+
+	restore := daemon.MockDenialsCollect(func(instanceName string, lines int) ([]*denials.Denial, error) {
+		return nil, nil
+	})
+	defer restore()
+	restore = daemon.MockDenialsCollectDevices(func(info *snap.Info) ([]*denials.Denial, error) {
+		c.Check(info.InstanceName(), check.Equals, "foo")
+		return []*denials.Denial{{
+			Kind:    denials.KindDevice,
+			Label:   "snap.foo.app",
+			Snap:    "foo",
+			Path:    "/dev/video0",
+			Device:  &denials.DeviceAccess{Type: "c", Major: 81, Minor: 0, Subsystem: "video4linux", Kernel: "video0", Count: 2},
+			Message: "device cgroup denied access to c 81:0",
+		}}, nil
+	})
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/debug/denials?snap=foo", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil, actionIsExpected)
+
+	data, err := json.Marshal(rsp.Result)
+	c.Assert(err, check.IsNil)
+	var result []map[string]any
+	c.Assert(json.Unmarshal(data, &result), check.IsNil)
+	c.Assert(result, check.HasLen, 1)
+	c.Check(result[0]["kind"], check.Equals, "device")
+	c.Check(result[0]["device"], check.DeepEquals, map[string]any{
+		"type":      "c",
+		"major":     81.0,
+		"minor":     0.0,
+		"subsystem": "video4linux",
+		"kernel":    "video0",
+		"count":     2.0,
+	})
+	c.Check(result[0]["candidates"], testutil.DeepContains, map[string]any{
+		"interface": "camera",
+	})
+}
+
+func (s *postDebugSuite) TestPostDebugDeviceAudit(c *check.C) {
+	d := s.daemonWithOverlordMock()
+	s.expectRootAccess()
+
+	var calls []string
+	restore := daemon.MockIfacestateSetDeviceAudit(func(st *state.State, instanceName string, enable bool) (*state.TaskSet, error) {
+		calls = append(calls, fmt.Sprintf("%s %v", instanceName, enable))
+		return state.NewTaskSet(st.NewTask("set-device-audit", "")), nil
+	})
+	defer restore()
+
+	for _, enable := range []bool{true, false} {
+		body := strings.NewReader(fmt.Sprintf(`{"action": "device-audit", "snaps": ["foo"], "params": {"enable": %v}}`, enable))
+		req, err := http.NewRequest("POST", "/v2/debug", body)
+		c.Assert(err, check.IsNil)
+		rsp := s.asyncReq(c, req, nil, actionIsExpected)
+
+		st := d.Overlord().State()
+		st.Lock()
+		chg := st.Change(rsp.Change)
+		c.Check(chg.Kind(), check.Equals, "device-audit")
+		if enable {
+			c.Check(chg.Summary(), check.Equals, `Enable auditing of device accesses of snap "foo"`)
+		} else {
+			c.Check(chg.Summary(), check.Equals, `Disable auditing of device accesses of snap "foo"`)
+		}
+		var data map[string][]string
+		c.Assert(chg.Get("api-data", &data), check.IsNil)
+		c.Check(data["snap-names"], check.DeepEquals, []string{"foo"})
+		st.Unlock()
+	}
+	c.Check(calls, check.DeepEquals, []string{"foo true", "foo false"})
+}
+
+func (s *postDebugSuite) TestPostDebugDeviceAuditErrors(c *check.C) {
+	s.daemonWithOverlordMock()
+	s.expectRootAccess()
+
+	restore := daemon.MockIfacestateSetDeviceAudit(func(st *state.State, instanceName string, enable bool) (*state.TaskSet, error) {
+		switch instanceName {
+		case "missing":
+			return nil, &snap.NotInstalledError{Snap: "missing"}
+		case "busy":
+			return nil, &snapstate.ChangeConflictError{Snap: "busy", ChangeKind: "refresh"}
+		}
+		return nil, errors.New(`cannot audit device accesses of snap "devmode": snap is not strictly confined`)
+	})
+	defer restore()
+
+	for _, t := range []struct {
+		snaps  string
+		status int
+		err    string
+	}{
+		{`[]`, 400, "device audit action requires exactly one snap"},
+		{`["foo", "bar"]`, 400, "device audit action requires exactly one snap"},
+		{`["missing"]`, 400, `snap "missing" is not installed`},
+		{`["busy"]`, 409, `snap "busy" has "refresh" change in progress`},
+		{`["devmode"]`, 400, `cannot audit device accesses of snap "devmode": snap is not strictly confined`},
+	} {
+		body := strings.NewReader(fmt.Sprintf(`{"action": "device-audit", "snaps": %s, "params": {"enable": true}}`, t.snaps))
+		req, err := http.NewRequest("POST", "/v2/debug", body)
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil, actionIsExpected)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf(t.snaps))
+		c.Check(rspe.Message, check.Equals, t.err, check.Commentf(t.snaps))
+	}
+}
+
+func (s *postDebugSuite) TestGetDebugSecurityProfiles(c *check.C) {
+	d := s.daemon(c)
+	s.mockSnap(c, `
+name: consumer
+version: 1
+apps:
+  app:
+    command: app
+plugs:
+  plug:
+    interface: network
+`)
+	s.mockSnap(c, `
+name: core
+version: 1
+type: os
+slots:
+  network:
+`)
+	backend := &ifacetest.TestSecurityBackend{BackendName: "rendering"}
+	c.Assert(d.Overlord().InterfaceManager().Repository().AddBackend(&renderingTestBackend{backend}), check.IsNil)
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=security-profiles&snap=consumer", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil, actionIsExpected)
+	c.Assert(rsp.Result, check.FitsTypeOf, []*inspect.Profile(nil))
+	var rendered *inspect.Profile
+	for _, p := range rsp.Result.([]*inspect.Profile) {
+		if p.Backend == "rendering" {
+			rendered = p
+		}
+	}
+	c.Assert(rendered, check.NotNil)
+	c.Check(rendered.Lines, check.DeepEquals, []inspect.Line{
+		{Text: "template", Origins: []interfaces.SpecificationOrigin{{Kind: interfaces.OriginBaseTemplate}}},
+	})
+
+	// the network interface has no test specific rules, but the
+	// connection resolves
+	req, err = http.NewRequest("GET", "/v2/debug?aspect=security-profiles&snap=consumer&plug=consumer:plug", nil)
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil, actionIsExpected)
+	c.Assert(rsp.Result, check.FitsTypeOf, []*inspect.Profile(nil))
+}
+
+func (s *postDebugSuite) TestGetDebugSecurityProfilesErrors(c *check.C) {
+	s.daemon(c)
+	s.mockSnap(c, `
+name: consumer
+version: 1
+plugs:
+  plug:
+    interface: network
+`)
+	s.mockSnap(c, `
+name: core
+version: 1
+type: os
+slots:
+  network:
+`)
+
+	for _, t := range []struct {
+		query  string
+		status int
+		err    string
+	}{
+		{"", 400, "cannot inspect security profiles: snap name is required"},
+		{"&snap=consumer&plug=consumer:missing", 400, `cannot inspect security profiles: snap "consumer" has no plug named "missing"`},
+		{"&snap=other&plug=consumer:plug", 400, `cannot inspect security profiles: snap "other" is not part of connection "consumer:plug core:network"`},
+		{"&snap=other", 400, `snap "other" is not installed`},
+	} {
+		req, err := http.NewRequest("GET", "/v2/debug?aspect=security-profiles"+t.query, nil)
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil, actionIsExpected)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf(t.query))
+		c.Check(rspe.Message, check.Equals, t.err, check.Commentf(t.query))
+	}
+}
+
+type renderingTestBackend struct {
+	*ifacetest.TestSecurityBackend
+}
+
+func (b *renderingTestBackend) SnapContributions(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) []*interfaces.SpecificationContribution {
+	return nil
+}
+
+func (b *renderingTestBackend) RenderProfiles(spec interfaces.Specification, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) (map[string][]byte, error) {
+	return map[string][]byte{appSet.InstanceName(): []byte("template\n")}, nil
+}
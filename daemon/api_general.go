@@ -41,6 +41,7 @@ import (
 	"github.com/snapcore/snapd/overlord/fdestate"
 	"github.com/snapcore/snapd/overlord/hookstate/ctlcmd"
 	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/sandbox"
@@ -149,6 +150,18 @@ func sysInfo(c *Command, r *http.Request, user *auth.UserState) Response {
 	if err != nil {
 		return InternalError("cannot get refresh schedule: %s", err)
 	}
+	refreshSnapTimers, err := snapMgr.RefreshSnapTimers()
+	if err != nil {
+		return InternalError("cannot get per-snap refresh schedules: %s", err)
+	}
+	refreshBlackout, err := snapMgr.RefreshBlackout()
+	if err != nil {
+		return InternalError("cannot get refresh blackout: %s", err)
+	}
+	refreshStaging, err := snapMgr.RefreshStaging()
+	if err != nil {
+		return InternalError("cannot get refresh staging: %s", err)
+	}
 	users, err := auth.Users(st)
 	if err != nil && !errors.Is(err, state.ErrNoState) {
 		return InternalError("cannot get user auth data: %s", err)
@@ -168,6 +181,10 @@ func sysInfo(c *Command, r *http.Request, user *auth.UserState) Response {
 		Last: formatRefreshTime(lastRefresh),
 		Hold: formatRefreshTime(refreshHold),
 		Next: formatRefreshTime(nextRefresh),
+
+		SnapTimers: refreshSnapTimers,
+		Blackout:   refreshBlackout,
+		Staging:    formatRefreshStaging(refreshStaging),
 	}
 	if !legacySchedule {
 		refreshInfo.Timer = refreshScheduleStr
@@ -267,6 +284,25 @@ func sysInfoPost(c *Command, r *http.Request, user *auth.UserState) Response {
 	return AsyncResponse(nil, chg.ID())
 }
 
+func formatRefreshStaging(staging map[string]*snapstate.RefreshStaging) map[string]client.RefreshStagingInfo {
+	if len(staging) == 0 {
+		return nil
+	}
+	out := make(map[string]client.RefreshStagingInfo, len(staging))
+	for name, rs := range staging {
+		info := client.RefreshStagingInfo{
+			MaxDelay: rs.MaxDelay.String(),
+			Delay:    rs.Delay.String(),
+			Until:    formatRefreshTime(rs.Until),
+		}
+		if !rs.Revision.Unset() {
+			info.Revision = rs.Revision.String()
+		}
+		out[name] = info
+	}
+	return out
+}
+
 func formatRefreshTime(t time.Time) string {
 	if t.IsZero() {
 		return ""
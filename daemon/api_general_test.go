@@ -311,6 +311,60 @@ func (s *generalSuite) TestSysInfoLegacyRefresh(c *check.C) {
 	c.Check(rsp.Result, check.DeepEquals, expected)
 }
 
+func (s *generalSuite) TestSysInfoRefreshScheduling(c *check.C) {
+	s.expectSystemInfoReadAccess()
+	req, err := http.NewRequest("GET", "/v2/system-info", nil)
+	c.Assert(err, check.IsNil)
+
+	restore := daemon.MockSystemdVirt("kvm")
+	defer restore()
+
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	tr := config.NewTransaction(st)
+	tr.Set("core", "refresh.timer", "00:00-12:00")
+	tr.Set("core", "refresh.snap-timer.db", "sun,02:00-04:00")
+	tr.Set("core", "refresh.blackout", "2026-12-20..2027-01-05")
+	tr.Set("core", "refresh.staging.db", "72h")
+	tr.Commit()
+	st.Set("refresh-staging", map[string]any{
+		"db": map[string]any{
+			"revision":   "8",
+			"first-seen": "2026-10-10T10:00:00Z",
+		},
+	})
+	st.Unlock()
+
+	s.expectSystemInfoReadAccess()
+
+	rec := httptest.NewRecorder()
+	s.req(c, req, nil, actionIsExpected).ServeHTTP(rec, nil)
+	c.Check(rec.Code, check.Equals, 200)
+
+	var rsp daemon.RespJSON
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), check.IsNil)
+	m, _ := rsp.Result.(map[string]any)
+	c.Assert(m, check.NotNil)
+	c.Check(m["refresh"], check.DeepEquals, map[string]any{
+		"timer": "00:00-12:00",
+		"snap-timers": map[string]any{
+			"db": "sun,02:00-04:00",
+		},
+		"blackout": "2026-12-20..2027-01-05",
+		"staging": map[string]any{
+			"db": map[string]any{
+				// without a serial the full delay applies
+				"max-delay": "72h0m0s",
+				"delay":     "72h0m0s",
+				"revision":  "8",
+				"until":     "2026-10-13T10:00:00Z",
+			},
+		},
+	})
+}
+
 func (s *generalSuite) testSysInfoBinOrigin(c *check.C, exp string, expErr string) {
 	s.expectSystemInfoReadAccess()
 	req, err := http.NewRequest("GET", "/v2/system-info", nil)
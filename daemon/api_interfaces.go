@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/overlord/auth"
@@ -44,6 +45,13 @@ var (
 		ReadAccess:  openAccess{},
 		WriteAccess: authenticatedAccess{Polkit: polkitActionManageInterfaces},
 	}
+
+	ownInterfacesCmd = &Command{
+		Path:        "/v2/interfaces/own",
+		POST:        changeOwnInterfaces,
+		Actions:     []string{"connect", "disconnect"},
+		WriteAccess: userAccess{Polkit: polkitActionManageOwnInterfaces},
+	}
 )
 
 var (
@@ -136,6 +144,32 @@ func changeInterfaces(c *Command, r *http.Request, user *auth.UserState) Respons
 	if err := decoder.Decode(&a); err != nil {
 		return BadRequest("cannot decode request body into an interface action: %v", err)
 	}
+	return performInterfaceAction(c, &a)
+}
+
+// changeOwnInterfaces connects and disconnects interfaces for the requesting
+// user only.
+func changeOwnInterfaces(c *Command, r *http.Request, user *auth.UserState) Response {
+	var a interfaceAction
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&a); err != nil {
+		return BadRequest("cannot decode request body into an interface action: %v", err)
+	}
+	if len(a.Users) > 0 {
+		return BadRequest("cannot specify users of own connections")
+	}
+	if a.Forget {
+		return BadRequest("cannot forget own connections")
+	}
+	uid, err := uidFromRequest(r)
+	if err != nil {
+		return Forbidden("cannot get remote user: %v", err)
+	}
+	a.Users = []int{int(uid)}
+	return performInterfaceAction(c, &a)
+}
+
+func performInterfaceAction(c *Command, a *interfaceAction) Response {
 	if a.Action == "" {
 		return BadRequest("interface action not specified")
 	}
@@ -148,9 +182,12 @@ func changeInterfaces(c *Command, r *http.Request, user *auth.UserState) Respons
 	if len(a.Plugs) == 0 || len(a.Slots) == 0 {
 		return BadRequest("at least one plug and slot is required")
 	}
+	connectOpts, err := a.connectOptions()
+	if err != nil {
+		return BadRequest("%v", err)
+	}
 
 	var summary string
-	var err error
 
 	var tasksets []*state.TaskSet
 	var affected []string
@@ -198,8 +235,12 @@ func changeInterfaces(c *Command, r *http.Request, user *auth.UserState) Respons
 			var ts *state.TaskSet
 			affected = snapNamesFromConns([]*interfaces.ConnRef{connRef})
 			summary = fmt.Sprintf("Connect %s:%s to %s:%s", connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name)
-			ts, err = ifacestate.Connect(st, connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name)
+			ts, err = ifacestate.ConnectWithOptions(st, connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name, connectOpts)
 			if _, ok := err.(*ifacestate.ErrAlreadyConnected); ok {
+				if connectOpts != nil && !connectOpts.Expiry.IsZero() {
+					// do not silently ignore the requested expiry
+					return BadRequest("cannot set expiry of existing connection %s, disconnect it first", connRef.ID())
+				}
 				change := newChange(st, connectSnapChangeKind, summary, nil, affected)
 				change.SetStatus(state.DoneStatus)
 				return AsyncResponse(nil, change.ID())
@@ -226,7 +267,11 @@ func changeInterfaces(c *Command, r *http.Request, user *auth.UserState) Respons
 					if err != nil {
 						break
 					}
-					ts, err = ifacestate.Disconnect(st, conn)
+					if len(a.Users) > 0 {
+						ts, err = ifacestate.DisconnectForUsers(st, conn, a.Users)
+					} else {
+						ts, err = ifacestate.Disconnect(st, conn)
+					}
 					if err != nil {
 						break
 					}
@@ -251,6 +296,37 @@ func changeInterfaces(c *Command, r *http.Request, user *auth.UserState) Respons
 	return AsyncResponse(nil, change.ID())
 }
 
+// connectOptions returns the options of a connect action.
+func (a *interfaceAction) connectOptions() (*ifacestate.ConnectOptions, error) {
+	if len(a.Users) > 0 && a.Forget {
+		return nil, fmt.Errorf("cannot forget connections of some users only")
+	}
+	if a.Expiry.IsZero() && a.Duration == "" {
+		if len(a.Users) == 0 || a.Action != "connect" {
+			return nil, nil
+		}
+		return &ifacestate.ConnectOptions{Users: a.Users}, nil
+	}
+	if a.Action != "connect" {
+		return nil, fmt.Errorf("expiry can only be set when connecting")
+	}
+	if !a.Expiry.IsZero() && a.Duration != "" {
+		return nil, fmt.Errorf("cannot use both expiry and duration")
+	}
+	opts := &ifacestate.ConnectOptions{Expiry: a.Expiry, Users: a.Users}
+	if a.Duration != "" {
+		duration, err := time.ParseDuration(a.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse duration: %v", err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("duration must be positive, not %q", a.Duration)
+		}
+		opts.Expiry = time.Now().Add(duration)
+	}
+	return opts, nil
+}
+
 func snapNamesFromConns(conns []*interfaces.ConnRef) []string {
 	m := make(map[string]bool)
 	for _, conn := range conns {
@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -177,6 +178,278 @@ func (s *interfacesSuite) TestConnectPlugSuccess(c *check.C) {
 	}})
 }
 
+func (s *interfacesSuite) TestConnectPlugWithDuration(c *check.C) {
+	restore := builtin.MockInterface(&ifacetest.TestInterface{InterfaceName: "test"})
+	defer restore()
+
+	d := s.daemon(c)
+
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	d.Overlord().Loop()
+	defer d.Overlord().Stop()
+
+	action := &client.InterfaceAction{
+		Action:   "connect",
+		Plugs:    []client.Plug{{Snap: "consumer", Name: "plug"}},
+		Slots:    []client.Slot{{Snap: "producer", Name: "slot"}},
+		Duration: "2h",
+	}
+	text, err := json.Marshal(action)
+	c.Assert(err, check.IsNil)
+	buf := bytes.NewBuffer(text)
+	req, err := http.NewRequest("POST", "/v2/interfaces", buf)
+	c.Assert(err, check.IsNil)
+	before := time.Now()
+	rec := httptest.NewRecorder()
+	s.req(c, req, nil, actionIsExpected).ServeHTTP(rec, req)
+	after := time.Now()
+	c.Check(rec.Code, check.Equals, 202)
+	var body map[string]any
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	c.Check(err, check.IsNil)
+	id := body["change"].(string)
+
+	st := d.Overlord().State()
+	st.Lock()
+	chg := st.Change(id)
+	st.Unlock()
+	c.Assert(chg, check.NotNil)
+
+	<-chg.Ready()
+
+	st.Lock()
+	defer st.Unlock()
+	c.Assert(chg.Err(), check.IsNil)
+
+	connStates, err := ifacestate.ConnectionStates(st)
+	c.Assert(err, check.IsNil)
+	expiry := connStates["consumer:plug producer:slot"].Expiry
+	c.Check(expiry.Before(before.Add(2*time.Hour)), check.Equals, false)
+	c.Check(expiry.After(after.Add(2*time.Hour)), check.Equals, false)
+}
+
+func (s *interfacesSuite) TestConnectExpiryErrors(c *check.C) {
+	s.daemon(c)
+
+	for _, tc := range []struct {
+		action *client.InterfaceAction
+		err    string
+	}{{
+		action: &client.InterfaceAction{Action: "connect", Duration: "1h", Expiry: time.Now().Add(time.Hour)},
+		err:    "cannot use both expiry and duration",
+	}, {
+		action: &client.InterfaceAction{Action: "connect", Duration: "1 hour"},
+		err:    `cannot parse duration: time: unknown unit " hour" in duration "1 hour"`,
+	}, {
+		action: &client.InterfaceAction{Action: "connect", Duration: "-5m"},
+		err:    `duration must be positive, not "-5m"`,
+	}, {
+		action: &client.InterfaceAction{Action: "disconnect", Duration: "5m"},
+		err:    "expiry can only be set when connecting",
+	}} {
+		tc.action.Plugs = []client.Plug{{Snap: "consumer", Name: "plug"}}
+		tc.action.Slots = []client.Slot{{Snap: "producer", Name: "slot"}}
+		text, err := json.Marshal(tc.action)
+		c.Assert(err, check.IsNil)
+		req, err := http.NewRequest("POST", "/v2/interfaces", bytes.NewBuffer(text))
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil, actionIsExpected)
+		c.Check(rspe.Status, check.Equals, 400)
+		c.Check(rspe.Message, check.Equals, tc.err)
+	}
+}
+
+func (s *interfacesSuite) TestConnectExpiryInThePast(c *check.C) {
+	d := s.daemon(c)
+
+	mockIface(c, d, &ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	action := &client.InterfaceAction{
+		Action: "connect",
+		Plugs:  []client.Plug{{Snap: "consumer", Name: "plug"}},
+		Slots:  []client.Slot{{Snap: "producer", Name: "slot"}},
+		Expiry: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	text, err := json.Marshal(action)
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", "/v2/interfaces", bytes.NewBuffer(text))
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil, actionIsExpected)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, "cannot connect consumer:plug to producer:slot: expiry time 2020-01-01T00:00:00Z is in the past")
+}
+
+func (s *interfacesSuite) postInterfaceAction(c *check.C, d *daemon.Daemon, urlpath string, action *client.InterfaceAction) {
+	text, err := json.Marshal(action)
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", urlpath, bytes.NewBuffer(text))
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = "pid=100;uid=1000;socket=;"
+	rsp := s.asyncReq(c, req, nil, actionIsExpected)
+
+	st := d.Overlord().State()
+	st.Lock()
+	chg := st.Change(rsp.Change)
+	st.Unlock()
+	c.Assert(chg, check.NotNil)
+
+	<-chg.Ready()
+
+	st.Lock()
+	defer st.Unlock()
+	c.Assert(chg.Err(), check.IsNil)
+}
+
+func (s *interfacesSuite) TestConnectForUsers(c *check.C) {
+	restore := ifacestate.MockUserLimitableInterfaces("test")
+	defer restore()
+	restore = builtin.MockInterface(&ifacetest.TestInterface{InterfaceName: "test"})
+	defer restore()
+
+	d := s.daemon(c)
+
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	d.Overlord().Loop()
+	defer d.Overlord().Stop()
+
+	s.postInterfaceAction(c, d, "/v2/interfaces", &client.InterfaceAction{
+		Action: "connect",
+		Plugs:  []client.Plug{{Snap: "consumer", Name: "plug"}},
+		Slots:  []client.Slot{{Snap: "producer", Name: "slot"}},
+		Users:  []int{1001, 1000},
+	})
+
+	st := d.Overlord().State()
+	st.Lock()
+	connStates, err := ifacestate.ConnectionStates(st)
+	st.Unlock()
+	c.Assert(err, check.IsNil)
+	c.Check(connStates["consumer:plug producer:slot"].Users, check.DeepEquals, []int{1000, 1001})
+
+	s.postInterfaceAction(c, d, "/v2/interfaces", &client.InterfaceAction{
+		Action: "disconnect",
+		Plugs:  []client.Plug{{Snap: "consumer", Name: "plug"}},
+		Slots:  []client.Slot{{Snap: "producer", Name: "slot"}},
+		Users:  []int{1001},
+	})
+
+	st.Lock()
+	connStates, err = ifacestate.ConnectionStates(st)
+	st.Unlock()
+	c.Assert(err, check.IsNil)
+	c.Check(connStates["consumer:plug producer:slot"].Users, check.DeepEquals, []int{1000})
+}
+
+func (s *interfacesSuite) TestConnectOwn(c *check.C) {
+	restore := ifacestate.MockUserLimitableInterfaces("test")
+	defer restore()
+	restore = builtin.MockInterface(&ifacetest.TestInterface{InterfaceName: "test"})
+	defer restore()
+
+	s.expectWriteAccess(daemon.UserAccess{Polkit: "io.snapcraft.snapd.manage-own-interfaces"})
+
+	d := s.daemon(c)
+
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	d.Overlord().Loop()
+	defer d.Overlord().Stop()
+
+	s.postInterfaceAction(c, d, "/v2/interfaces/own", &client.InterfaceAction{
+		Action: "connect",
+		Plugs:  []client.Plug{{Snap: "consumer", Name: "plug"}},
+		Slots:  []client.Slot{{Snap: "producer", Name: "slot"}},
+	})
+
+	st := d.Overlord().State()
+	st.Lock()
+	connStates, err := ifacestate.ConnectionStates(st)
+	st.Unlock()
+	c.Assert(err, check.IsNil)
+	c.Check(connStates["consumer:plug producer:slot"].Users, check.DeepEquals, []int{1000})
+
+	// the last user disconnecting removes the connection
+	s.postInterfaceAction(c, d, "/v2/interfaces/own", &client.InterfaceAction{
+		Action: "disconnect",
+		Plugs:  []client.Plug{{Snap: "consumer", Name: "plug"}},
+		Slots:  []client.Slot{{Snap: "producer", Name: "slot"}},
+	})
+
+	repo := d.Overlord().InterfaceManager().Repository()
+	c.Check(repo.Interfaces().Connections, check.HasLen, 0)
+}
+
+func (s *interfacesSuite) TestConnectOwnErrors(c *check.C) {
+	s.expectWriteAccess(daemon.UserAccess{Polkit: "io.snapcraft.snapd.manage-own-interfaces"})
+	s.daemon(c)
+
+	for _, tc := range []struct {
+		action *client.InterfaceAction
+		err    string
+	}{{
+		action: &client.InterfaceAction{Action: "connect", Users: []int{1001}},
+		err:    "cannot specify users of own connections",
+	}, {
+		action: &client.InterfaceAction{Action: "disconnect", Forget: true},
+		err:    "cannot forget own connections",
+	}} {
+		tc.action.Plugs = []client.Plug{{Snap: "consumer", Name: "plug"}}
+		tc.action.Slots = []client.Slot{{Snap: "producer", Name: "slot"}}
+		text, err := json.Marshal(tc.action)
+		c.Assert(err, check.IsNil)
+		req, err := http.NewRequest("POST", "/v2/interfaces/own", bytes.NewBuffer(text))
+		c.Assert(err, check.IsNil)
+		req.RemoteAddr = "pid=100;uid=1000;socket=;"
+		rspe := s.errorReq(c, req, nil, actionIsExpected)
+		c.Check(rspe.Status, check.Equals, 400)
+		c.Check(rspe.Message, check.Equals, tc.err)
+	}
+}
+
+func (s *interfacesSuite) TestConnectForUsersErrors(c *check.C) {
+	d := s.daemon(c)
+
+	mockIface(c, d, &ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	action := &client.InterfaceAction{
+		Action: "connect",
+		Plugs:  []client.Plug{{Snap: "consumer", Name: "plug"}},
+		Slots:  []client.Slot{{Snap: "producer", Name: "slot"}},
+		Users:  []int{1000},
+	}
+	text, err := json.Marshal(action)
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", "/v2/interfaces", bytes.NewBuffer(text))
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil, actionIsExpected)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, `cannot limit connection to users: interface "test" does not support it`)
+
+	action = &client.InterfaceAction{
+		Action: "disconnect",
+		Forget: true,
+		Plugs:  []client.Plug{{Snap: "consumer", Name: "plug"}},
+		Slots:  []client.Slot{{Snap: "producer", Name: "slot"}},
+		Users:  []int{1000},
+	}
+	text, err = json.Marshal(action)
+	c.Assert(err, check.IsNil)
+	req, err = http.NewRequest("POST", "/v2/interfaces", bytes.NewBuffer(text))
+	c.Assert(err, check.IsNil)
+	rspe = s.errorReq(c, req, nil, actionIsExpected)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, "cannot forget connections of some users only")
+}
+
 func (s *interfacesSuite) TestConnectPlugFailureInterfaceMismatch(c *check.C) {
 	d := s.daemon(c)
 
@@ -20,6 +20,8 @@
 package daemon
 
 import (
+	"time"
+
 	"github.com/snapcore/snapd/interfaces"
 )
 
@@ -62,6 +64,12 @@ type interfaceAction struct {
 	Forget bool       `json:"forget,omitempty"`
 	Plugs  []plugJSON `json:"plugs,omitempty"`
 	Slots  []slotJSON `json:"slots,omitempty"`
+	// Expiry and Duration make a connection time-limited, they are
+	// mutually exclusive.
+	Expiry   time.Time `json:"expiry,omitzero"`
+	Duration string    `json:"duration,omitempty"`
+	// Users limits the action to the users with the given IDs.
+	Users []int `json:"users,omitempty"`
 }
 
 // connectionsJSON aids in marshalling information about a single connection
@@ -72,8 +80,11 @@ type connectionJSON struct {
 	Interface string             `json:"interface"`
 	Manual    bool               `json:"manual,omitempty"`
 	Gadget    bool               `json:"gadget,omitempty"`
+	Model     bool               `json:"model,omitempty"`
 	SlotAttrs map[string]any     `json:"slot-attrs,omitempty"`
 	PlugAttrs map[string]any     `json:"plug-attrs,omitempty"`
+	Expiry    time.Time          `json:"expiry,omitzero"`
+	Users     []int              `json:"users,omitempty"`
 }
 
 // legacyConnectionsJSON aids in marshaling legacy connections into JSON.
@@ -50,7 +50,7 @@ func (s *noticesSuite) SetUpTest(c *C) {
 	dirstest.MustMockDefaultLibExecDir(dirs.GlobalRootDir)
 	dirs.SetRootDir(dirs.GlobalRootDir)
 
-	s.expectReadAccess(daemon.InterfaceOpenAccess{Interfaces: []string{"snap-refresh-observe", "snap-interfaces-requests-control"}})
+	s.expectReadAccess(daemon.InterfaceOpenAccess{Interfaces: []string{"snap-refresh-observe", "snap-interfaces-requests-control", "snap-notices-observe"}})
 	s.expectWriteAccess(daemon.OpenAccess{})
 }
 
@@ -400,6 +400,60 @@ func (s *noticesSuite) TestNoticesFilterTypesForSnapForbidden(c *C) {
 	c.Check(rsp.Status, Equals, 403)
 }
 
+func (s *noticesSuite) TestNoticesSnapCustomForSnap(c *C) {
+	s.daemon(c)
+
+	st := s.d.Overlord().State()
+	st.Lock()
+	addNotice(c, st, nil, state.ChangeUpdateNotice, "123", nil)
+	addNotice(c, st, nil, state.SnapCustomNotice, "foo/model-downloaded", &state.AddNoticeOptions{
+		Data: map[string]string{"model": "large"},
+	})
+	addNotice(c, st, nil, state.SnapCustomNotice, "bar/firmware-staged", nil)
+	st.Unlock()
+
+	// snap-notices-observe interface allows accessing snap-custom notices
+	// only, which are returned by default
+	req, err := http.NewRequest("GET", "/v2/notices", nil)
+	c.Assert(err, IsNil)
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1000;socket=%s;iface=snap-notices-observe;", dirs.SnapSocket)
+	rsp := s.syncReq(c, req, nil, actionIsExpected)
+	c.Check(rsp.Status, Equals, 200)
+	notices, ok := rsp.Result.([]*state.Notice)
+	c.Assert(ok, Equals, true)
+	c.Assert(notices, HasLen, 2)
+	for _, notice := range notices {
+		c.Check(notice.Type(), Equals, state.SnapCustomNotice)
+	}
+
+	// and they can be filtered by key
+	req, err = http.NewRequest("GET", "/v2/notices?types=snap-custom&keys=foo/model-downloaded", nil)
+	c.Assert(err, IsNil)
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1000;socket=%s;iface=snap-notices-observe;", dirs.SnapSocket)
+	rsp = s.syncReq(c, req, nil, actionIsExpected)
+	c.Check(rsp.Status, Equals, 200)
+	notices, ok = rsp.Result.([]*state.Notice)
+	c.Assert(ok, Equals, true)
+	c.Assert(notices, HasLen, 1)
+	n := noticeToMap(c, notices[0])
+	c.Check(n["key"], Equals, "foo/model-downloaded")
+	c.Check(n["last-data"], DeepEquals, map[string]any{"model": "large"})
+
+	// snap-notices-observe doesn't give access to change-update notices
+	req, err = http.NewRequest("GET", "/v2/notices?types=change-update", nil)
+	c.Assert(err, IsNil)
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1000;socket=%s;iface=snap-notices-observe;", dirs.SnapSocket)
+	errRsp := s.errorReq(c, req, nil, actionIsExpected)
+	c.Check(errRsp.Status, Equals, 403)
+
+	// snap-refresh-observe doesn't give access to snap-custom notices
+	req, err = http.NewRequest("GET", "/v2/notices?types=snap-custom", nil)
+	c.Assert(err, IsNil)
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1000;socket=%s;iface=snap-refresh-observe;", dirs.SnapSocket)
+	errRsp = s.errorReq(c, req, nil, actionIsExpected)
+	c.Check(errRsp.Status, Equals, 403)
+}
+
 func (s *noticesSuite) TestNoticesUserIDAdminDefault(c *C) {
 	s.daemon(c)
 
@@ -56,7 +56,11 @@ func getSnapConf(c *Command, r *http.Request, user *auth.UserState) Response {
 	s := c.d.overlord.State()
 	s.Lock()
 	tr := config.NewTransaction(s)
+	schema, err := configstate.SnapConfigSchema(s, snapName)
 	s.Unlock()
+	if err != nil {
+		return InternalError("%v", err)
+	}
 
 	currentConfValues := make(map[string]any)
 	// Special case - return root document
@@ -65,7 +69,21 @@ func getSnapConf(c *Command, r *http.Request, user *auth.UserState) Response {
 	}
 	for _, key := range keys {
 		var value any
-		if err := tr.Get(snapName, key, &value); err != nil {
+		err := tr.Get(snapName, key, &value)
+		if schema != nil && (err == nil || config.IsNoOption(err)) {
+			// show the defaults declared in the configuration schema of
+			// the snap for options which are not set, and never show
+			// options declared as secret
+			var secret bool
+			value, secret = schema.Redact(key, schema.WithDefaults(key, value))
+			if secret {
+				return Forbidden("cannot get secret option %q of snap %q", key, snapName)
+			}
+			if value != nil {
+				err = nil
+			}
+		}
+		if err != nil {
 			if config.IsNoOption(err) {
 				if key == "" {
 					// no configuration - return empty document
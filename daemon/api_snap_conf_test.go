@@ -114,6 +114,46 @@ func (s *snapConfSuite) TestGetConfMissingKey(c *check.C) {
 	})
 }
 
+func (s *snapConfSuite) TestGetConfSchemaDefaultsAndSecrets(c *check.C) {
+	d := s.daemon(c)
+	info := s.mockSnap(c, "name: test-snap\nversion: 1\n")
+	err := os.WriteFile(filepath.Join(info.MountDir(), "meta", "config-schema.json"), []byte(`{
+	"schema": {
+		"port": {"type": "int", "default": 8080},
+		"password": {"type": "string", "visibility": "secret"},
+		"db": {
+			"schema": {
+				"host": {"type": "string", "default": "localhost"},
+				"token": {"type": "string", "visibility": "secret"}
+			}
+		}
+	}
+}`), 0644)
+	c.Assert(err, check.IsNil)
+
+	d.Overlord().State().Lock()
+	tr := config.NewTransaction(d.Overlord().State())
+	tr.Set("test-snap", "password", "hunter2")
+	tr.Set("test-snap", "db.token", "abc")
+	tr.Commit()
+	d.Overlord().State().Unlock()
+
+	result := s.runGetConf(c, "test-snap", nil, 200)
+	c.Check(result, check.DeepEquals, map[string]any{
+		"port": 8080.0,
+		"db":   map[string]any{"host": "localhost"},
+	})
+
+	result = s.runGetConf(c, "test-snap", []string{"port", "db"}, 200)
+	c.Check(result, check.DeepEquals, map[string]any{
+		"port": 8080.0,
+		"db":   map[string]any{"host": "localhost"},
+	})
+
+	result = s.runGetConf(c, "test-snap", []string{"db.token"}, 403)
+	c.Check(result["message"], check.Equals, `cannot get secret option "db.token" of snap "test-snap"`)
+}
+
 func (s *snapConfSuite) TestGetConfCoreUnsupportedExperimentalFlag(c *check.C) {
 	d := s.daemon(c)
 
@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/swfeats"
+)
+
+var (
+	snapSetGenerationsCmd = &Command{
+		Path:        "/v2/snap-set-generations",
+		GET:         getSnapSetGenerations,
+		POST:        postSnapSetGenerations,
+		Actions:     []string{"revert"},
+		ReadAccess:  openAccess{},
+		WriteAccess: authenticatedAccess{Polkit: polkitActionManage},
+	}
+)
+
+var revertSnapSetGenerationChangeKind = swfeats.RegisterChangeKind("revert-snap-set-generation")
+
+func getSnapSetGenerations(c *Command, r *http.Request, user *auth.UserState) Response {
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	gens, err := snapstateSnapSetGenerations(st)
+	if err != nil {
+		return InternalError("cannot get snap set generations: %v", err)
+	}
+
+	// the configuration of the snaps is not exposed, reading it requires
+	// more privileges than listing the generations
+	res := make([]*client.SnapSetGeneration, 0, len(gens))
+	for _, gen := range gens {
+		g := &client.SnapSetGeneration{
+			ID:       gen.ID,
+			ChangeID: gen.ChangeID,
+			Time:     gen.Time,
+			Snaps:    make([]client.SnapSetGenerationSnap, 0, len(gen.Snaps)),
+		}
+		for _, gs := range gen.Snaps {
+			g.Snaps = append(g.Snaps, client.SnapSetGenerationSnap{
+				Name:   gs.InstanceName,
+				Before: gs.Before,
+				After:  gs.After,
+			})
+		}
+		res = append(res, g)
+	}
+	return SyncResponse(res)
+}
+
+type snapSetGenerationAction struct {
+	Action string `json:"action"`
+	ID     int    `json:"id"`
+	Purge  bool   `json:"purge,omitempty"`
+}
+
+func postSnapSetGenerations(c *Command, r *http.Request, user *auth.UserState) Response {
+	var a snapSetGenerationAction
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&a); err != nil {
+		return BadRequest("cannot decode request body into a snap set generation action: %v", err)
+	}
+	if a.Action != "revert" {
+		return BadRequest("unsupported snap set generation action: %q", a.Action)
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	gens, err := snapstateSnapSetGenerations(st)
+	if err != nil {
+		return InternalError("cannot get snap set generations: %v", err)
+	}
+	var names []string
+	for _, gen := range gens {
+		if gen.ID != a.ID {
+			continue
+		}
+		for _, gs := range gen.Snaps {
+			names = append(names, gs.InstanceName)
+		}
+	}
+	if len(names) == 0 {
+		return NotFound("cannot find snap set generation %d", a.ID)
+	}
+
+	tss, err := snapstateRevertSnapSetGeneration(st, a.ID, &snapstate.RemoveFlags{Purge: a.Purge}, "")
+	if err != nil {
+		return errToResponse(err, names, BadRequest, "%v")
+	}
+
+	summary := fmt.Sprintf(i18n.G("Revert snap set generation %d"), a.ID)
+	chg := newChange(st, revertSnapSetGenerationChangeKind, summary, tss, names)
+	ensureStateSoon(st)
+
+	return AsyncResponse(nil, chg.ID())
+}
@@ -0,0 +1,144 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var _ = check.Suite(&snapSetGenerationsSuite{})
+
+type snapSetGenerationsSuite struct {
+	apiBaseSuite
+}
+
+func (s *snapSetGenerationsSuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectWriteAccess(daemon.AuthenticatedAccess{Polkit: "io.snapcraft.snapd.manage"})
+}
+
+func (s *snapSetGenerationsSuite) mockGenerations(c *check.C, d *daemon.Daemon) {
+	before := []byte(`{"secret":"value"}`)
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	st.Set("snap-set-generations", []map[string]any{{
+		"id":        2,
+		"change-id": "42",
+		"time":      time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+		"snaps": []map[string]any{
+			{"name": "new-snap", "before": "unset", "after": "3"},
+			{"name": "some-snap", "before": "1", "after": "2", "config-before": string(before)},
+		},
+	}})
+}
+
+func (s *snapSetGenerationsSuite) TestGetSnapSetGenerations(c *check.C) {
+	d := s.daemonWithOverlordMock()
+	s.mockGenerations(c, d)
+
+	req, err := http.NewRequest("GET", "/v2/snap-set-generations", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil, actionIsUnexpected)
+	c.Check(rsp.Result, check.DeepEquals, []*client.SnapSetGeneration{{
+		ID:       2,
+		ChangeID: "42",
+		Time:     time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+		Snaps: []client.SnapSetGenerationSnap{
+			{Name: "new-snap", After: snap.R(3)},
+			{Name: "some-snap", Before: snap.R(1), After: snap.R(2)},
+		},
+	}})
+}
+
+func (s *snapSetGenerationsSuite) TestGetSnapSetGenerationsNone(c *check.C) {
+	s.daemonWithOverlordMock()
+
+	req, err := http.NewRequest("GET", "/v2/snap-set-generations", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil, actionIsUnexpected)
+	c.Check(rsp.Result, check.DeepEquals, []*client.SnapSetGeneration{})
+}
+
+func (s *snapSetGenerationsSuite) TestPostSnapSetGenerationsRevert(c *check.C) {
+	d := s.daemonWithOverlordMock()
+	s.mockGenerations(c, d)
+
+	defer daemon.MockSnapstateRevertSnapSetGeneration(func(st *state.State, id int, flags *snapstate.RemoveFlags, fromChange string) ([]*state.TaskSet, error) {
+		c.Check(id, check.Equals, 2)
+		c.Check(flags, check.DeepEquals, &snapstate.RemoveFlags{Purge: true})
+		t := st.NewTask("fake-revert", "Revert")
+		return []*state.TaskSet{state.NewTaskSet(t)}, nil
+	})()
+
+	buf := strings.NewReader(`{"action": "revert", "id": 2, "purge": true}`)
+	req, err := http.NewRequest("POST", "/v2/snap-set-generations", buf)
+	c.Assert(err, check.IsNil)
+	rsp := s.asyncReq(c, req, nil, actionIsExpected)
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Kind(), check.Equals, "revert-snap-set-generation")
+	c.Check(chg.Summary(), check.Equals, "Revert snap set generation 2")
+	var names []string
+	c.Assert(chg.Get("snap-names", &names), check.IsNil)
+	c.Check(names, check.DeepEquals, []string{"new-snap", "some-snap"})
+}
+
+func (s *snapSetGenerationsSuite) TestPostSnapSetGenerationsErrors(c *check.C) {
+	d := s.daemonWithOverlordMock()
+	s.mockGenerations(c, d)
+
+	defer daemon.MockSnapstateRevertSnapSetGeneration(func(st *state.State, id int, flags *snapstate.RemoveFlags, fromChange string) ([]*state.TaskSet, error) {
+		return nil, snapstate.ErrNothingToDo
+	})()
+
+	for _, tc := range []struct {
+		body    string
+		action  actionExpectedBool
+		status  int
+		message string
+	}{
+		{`{"action": "foo", "id": 2}`, actionIsUnexpected, 400, `unsupported snap set generation action: "foo"`},
+		{`{"action": "revert", "id": 3}`, actionIsExpected, 404, `cannot find snap set generation 3`},
+		{`garbage`, actionIsUnexpected, 400, `cannot decode request body into a snap set generation action: .*`},
+		{`{"action": "revert", "id": 2}`, actionIsExpected, 400, `nothing to do`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/snap-set-generations", strings.NewReader(tc.body))
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil, tc.action)
+		c.Check(rspe.Status, check.Equals, tc.status, check.Commentf(tc.body))
+		c.Check(rspe.Message, check.Matches, tc.message, check.Commentf(tc.body))
+	}
+}
@@ -58,6 +58,8 @@ const (
 	removeCmdAction   = "remove"
 	enableCmdAction   = "enable"
 	disableCmdAction  = "disable"
+
+	autoremoveCmdAction = "autoremove"
 )
 
 var (
@@ -83,7 +85,7 @@ var (
 			installCmdAction, refreshCmdAction, revertCmdAction,
 			switchCmdAction, holdCmdAction, unholdCmdAction,
 			snapshotCmdAction, removeCmdAction, enableCmdAction,
-			disableCmdAction,
+			disableCmdAction, autoremoveCmdAction,
 		},
 		ReadAccess:  interfaceOpenAccess{Interfaces: []string{"snap-refresh-observe", "desktop-launch"}},
 		WriteAccess: authenticatedAccess{Polkit: polkitActionManage},
@@ -101,6 +103,8 @@ var (
 	revertSnapChangeKind   = swfeats.RegisterChangeKind(revertCmdAction + "-snap")
 	enableSnapChangeKind   = swfeats.RegisterChangeKind(enableCmdAction + "-snap")
 	disableSnapChangeKind  = swfeats.RegisterChangeKind(disableCmdAction + "-snap")
+
+	autoremoveSnapChangeKind = swfeats.RegisterChangeKind(autoremoveCmdAction + "-snap")
 )
 
 func getSnapInfo(c *Command, r *http.Request, user *auth.UserState) Response {
@@ -163,6 +167,8 @@ func changeKind(action string) (string, bool) {
 		return enableSnapChangeKind, true
 	case disableCmdAction:
 		return disableSnapChangeKind, true
+	case autoremoveCmdAction:
+		return autoremoveSnapChangeKind, true
 	}
 	return "", false
 }
@@ -447,6 +453,10 @@ func (inst *snapInstruction) validate() error {
 		}
 	}
 
+	if inst.Action == autoremoveCmdAction && (len(inst.Snaps) > 0 || len(inst.CompsForSnaps) > 0) {
+		return errors.New("snap names cannot be specified for autoremove")
+	}
+
 	if inst.Unaliased && inst.Prefer {
 		return errUnaliasedPreferConflict
 	}
@@ -610,6 +620,9 @@ func snapUpdate(ctx context.Context, inst *snapInstruction, st *state.State) (*s
 	if inst.Amend {
 		flags.Amend = true
 	}
+	// the snap is wanted by the user from now on, even if it was
+	// installed only as a prerequisite of another snap
+	flags.Explicit = true
 
 	// we need refreshed snap-declarations to enforce refresh-control as best as we can
 	if err = assertstateRefreshSnapAssertions(st, inst.userID, nil); err != nil {
@@ -922,6 +935,8 @@ func (inst *snapInstruction) dispatchForMany() (op snapManyActionFunc) {
 		op = snapHoldMany
 	case unholdCmdAction:
 		op = snapUnholdMany
+	case autoremoveCmdAction:
+		op = snapAutoremoveMany
 	}
 	return op
 }
@@ -1078,6 +1093,8 @@ func snapUpdateMany(ctx context.Context, inst *snapInstruction, st *state.State)
 	flags := snapstate.Flags{
 		IgnoreRunning: inst.IgnoreRunning,
 		Transaction:   inst.Transaction,
+		// snaps refreshed by name are wanted by the user from now on
+		Explicit: len(inst.Snaps) > 0,
 	}
 
 	// TODO: once we completely move away from the old snapstate API, this
@@ -1270,6 +1287,28 @@ func snapRemoveMany(_ context.Context, inst *snapInstruction, st *state.State) (
 	}, nil
 }
 
+func snapAutoremoveMany(_ context.Context, inst *snapInstruction, st *state.State) (*snapInstructionResult, error) {
+	flags := &snapstate.RemoveFlags{Purge: inst.Purge}
+	removed, tasksets, err := snapstateAutoremove(st, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg string
+	if len(removed) == 0 {
+		msg = i18n.G("Autoremove snaps: nothing to remove")
+	} else {
+		// TRANSLATORS: the %s is a comma-separated list of quoted snap names
+		msg = fmt.Sprintf(i18n.G("Autoremove snaps %s"), strutil.Quoted(removed))
+	}
+
+	return &snapInstructionResult{
+		Summary:  msg,
+		Affected: removed,
+		Tasksets: tasksets,
+	}, nil
+}
+
 // query many snaps
 func getSnapsInfo(c *Command, r *http.Request, user *auth.UserState) Response {
 	if shouldSearchStore(r) {
@@ -1288,6 +1327,8 @@ func getSnapsInfo(c *Command, r *http.Request, user *auth.UserState) Response {
 		sel = snapSelectEnabled
 	case "refresh-inhibited":
 		sel = snapSelectRefreshInhibited
+	case "autoremove":
+		sel = snapSelectAutoremove
 	default:
 		return BadRequest("invalid select parameter: %q", sel)
 	}
@@ -629,6 +629,70 @@ func (s *snapsSuite) TestPostSnapsRemoveManyWithPurge(c *check.C) {
 	c.Check(chg.Summary(), check.Equals, `Remove snaps "foo", "bar"`)
 }
 
+func (s *snapsSuite) TestPostSnapsAutoremove(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+
+	defer daemon.MockSnapstateAutoremove(func(s *state.State, opts *snapstate.RemoveFlags) ([]string, []*state.TaskSet, error) {
+		c.Check(opts.Purge, check.Equals, true)
+		t := s.NewTask("fake-remove-2", "Remove two")
+		return []string{"foo", "bar"}, []*state.TaskSet{state.NewTaskSet(t)}, nil
+	})()
+
+	buf := strings.NewReader(`{"action": "autoremove", "purge":true}`)
+	req, err := http.NewRequest("POST", "/v2/snaps", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp := s.jsonReq(c, req, nil, actionIsExpected)
+	c.Check(rsp.Status, check.Equals, 202)
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Check(chg.Kind(), check.Equals, "autoremove-snap")
+	c.Check(chg.Summary(), check.Equals, `Autoremove snaps "foo", "bar"`)
+	var names []string
+	c.Assert(chg.Get("snap-names", &names), check.IsNil)
+	c.Check(names, check.DeepEquals, []string{"foo", "bar"})
+}
+
+func (s *snapsSuite) TestPostSnapsAutoremoveNothing(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+
+	defer daemon.MockSnapstateAutoremove(func(s *state.State, opts *snapstate.RemoveFlags) ([]string, []*state.TaskSet, error) {
+		return nil, nil, nil
+	})()
+
+	buf := strings.NewReader(`{"action": "autoremove"}`)
+	req, err := http.NewRequest("POST", "/v2/snaps", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp := s.jsonReq(c, req, nil, actionIsExpected)
+	c.Check(rsp.Status, check.Equals, 202)
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Check(chg.Summary(), check.Equals, `Autoremove snaps: nothing to remove`)
+	c.Check(chg.Status(), check.Equals, state.DoneStatus)
+}
+
+func (s *snapsSuite) TestPostSnapsAutoremoveWithNames(c *check.C) {
+	s.daemonWithOverlordMockAndStore()
+
+	buf := strings.NewReader(`{"action": "autoremove", "snaps": ["foo"]}`)
+	req, err := http.NewRequest("POST", "/v2/snaps", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rspe := s.errorReq(c, req, nil, actionIsExpected)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, "snap names cannot be specified for autoremove")
+}
+
 func (s *snapsSuite) TestPostSnapsOptionsClean(c *check.C) {
 	var snapshotSaveCalled int
 	defer daemon.MockSnapshotSave(func(s *state.State, snaps, users []string,
@@ -1612,6 +1676,25 @@ func (s *snapsSuite) TestSnapManyInfosSelectRefreshInhibited(c *check.C) {
 	}
 }
 
+func (s *snapsSuite) TestSnapManyInfosSelectAutoremove(c *check.C) {
+	s.expectSnapsReadAccess()
+	d := s.daemon(c)
+	s.mkInstalledInState(c, d, "snap-a", "bar", "v0", snap.R(5), true, "")
+	s.mkInstalledInState(c, d, "snap-b", "bar", "v0", snap.R(5), true, "")
+
+	defer daemon.MockSnapstateAutoremoveCandidates(func(st *state.State) ([]string, error) {
+		return []string{"snap-b"}, nil
+	})()
+
+	req, err := http.NewRequest("GET", "/v2/snaps?select=autoremove", nil)
+	c.Assert(err, check.IsNil)
+
+	rsp := s.jsonReq(c, req, nil, actionIsExpected)
+	snaps := snapList(rsp.Result)
+	c.Assert(snaps, check.HasLen, 1)
+	c.Check(snaps[0]["name"], check.Equals, "snap-b")
+}
+
 func (s *snapsSuite) TestSnapInfoReturnsRefreshFailures(c *check.C) {
 	s.expectSnapsNameReadAccess()
 	d := s.daemon(c)
@@ -2821,6 +2904,7 @@ func (s *snapsSuite) TestRefresh(c *check.C) {
 
 	c.Check(assertstateCalledUserID, check.Equals, 17)
 	c.Check(calledFlags, check.DeepEquals, snapstate.Flags{
+		Explicit:    true,
 		Transaction: client.TransactionPerSnap,
 	})
 	c.Check(calledUserID, check.Equals, 17)
@@ -2829,6 +2913,42 @@ func (s *snapsSuite) TestRefresh(c *check.C) {
 	c.Check(res.Summary, check.Equals, `Refresh "some-snap" snap`)
 }
 
+func (s *snapsSuite) TestRefreshExplicit(c *check.C) {
+	var calledFlags snapstate.Flags
+	defer daemon.MockSnapstateUpdateOne(func(ctx context.Context, st *state.State, g snapstate.UpdateGoal, filter func(*snap.Info, *snapstate.SnapState) bool, opts snapstate.Options) (*state.TaskSet, error) {
+		calledFlags = opts.Flags
+		t := st.NewTask("fake-refresh-snap", "Doing a fake refresh")
+		return state.NewTaskSet(t), nil
+	})()
+	defer daemon.MockAssertstateRefreshSnapAssertions(func(s *state.State, userID int, opts *assertstate.RefreshAssertionsOptions) error {
+		return nil
+	})()
+
+	d := s.daemon(c)
+	s.mkInstalledInState(c, d, "some-snap", "bar", "v0", snap.R(5), true, "")
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(st, "some-snap", &snapst), check.IsNil)
+	snapst.Prerequisite = true
+	snapstate.Set(st, "some-snap", &snapst)
+
+	inst := &daemon.SnapInstruction{
+		Action: "refresh",
+		Snaps:  []string{"some-snap"},
+	}
+	_, err := inst.Dispatch()(context.Background(), inst, st)
+	c.Assert(err, check.IsNil)
+
+	// the mark is only cleared once the snap is linked
+	c.Check(calledFlags.Explicit, check.Equals, true)
+	c.Assert(snapstate.Get(st, "some-snap", &snapst), check.IsNil)
+	c.Check(snapst.Prerequisite, check.Equals, true)
+}
+
 func (s *snapsSuite) TestRefreshDevMode(c *check.C) {
 	var calledFlags snapstate.Flags
 	calledUserID := 0
@@ -2862,6 +2982,7 @@ func (s *snapsSuite) TestRefreshDevMode(c *check.C) {
 	c.Check(err, check.IsNil)
 
 	flags := snapstate.Flags{
+		Explicit:    true,
 		DevMode:     true,
 		Transaction: client.TransactionPerSnap,
 	}
@@ -2898,6 +3019,7 @@ func (s *snapsSuite) TestRefreshClassic(c *check.C) {
 	c.Check(err, check.IsNil)
 
 	c.Check(calledFlags, check.DeepEquals, snapstate.Flags{
+		Explicit:    true,
 		Classic:     true,
 		Transaction: client.TransactionPerSnap,
 	})
@@ -2936,6 +3058,7 @@ func (s *snapsSuite) TestRefreshIgnoreValidation(c *check.C) {
 	c.Check(err, check.IsNil)
 
 	flags := snapstate.Flags{
+		Explicit:         true,
 		IgnoreValidation: true,
 		Transaction:      client.TransactionPerSnap,
 	}
@@ -2977,6 +3100,7 @@ func (s *snapsSuite) TestRefreshIgnoreRunning(c *check.C) {
 	c.Check(err, check.IsNil)
 
 	flags := snapstate.Flags{
+		Explicit:      true,
 		IgnoreRunning: true,
 		Transaction:   client.TransactionPerSnap,
 	}
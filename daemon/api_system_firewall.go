@@ -0,0 +1,97 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+)
+
+var systemFirewallCmd = &Command{
+	Path:        "/v2/system-firewall",
+	GET:         getSystemFirewall,
+	POST:        postSystemFirewall,
+	Actions:     []string{"confirm"},
+	ReadAccess:  authenticatedAccess{Polkit: polkitActionManageConfiguration},
+	WriteAccess: authenticatedAccess{Polkit: polkitActionManageConfiguration},
+}
+
+type systemFirewallResponse struct {
+	// ConfirmBefore is set when the firewall rules applied last are
+	// rolled back unless confirmed before that time.
+	ConfirmBefore *time.Time `json:"confirm-before,omitempty"`
+}
+
+func getSystemFirewall(c *Command, r *http.Request, user *auth.UserState) Response {
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	deadline, err := configcore.FirewallConfirmationDeadline(st)
+	if err != nil {
+		return InternalError(err.Error())
+	}
+	var resp systemFirewallResponse
+	if !deadline.IsZero() {
+		resp.ConfirmBefore = &deadline
+	}
+	return SyncResponse(resp)
+}
+
+type postSystemFirewallData struct {
+	Action string `json:"action"`
+}
+
+func postSystemFirewall(c *Command, r *http.Request, user *auth.UserState) Response {
+	var postData postSystemFirewallData
+
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&postData); err != nil {
+		return BadRequest("cannot decode firewall action data from request body: %v", err)
+	}
+	if decoder.More() {
+		return BadRequest("spurious content after firewall action")
+	}
+	switch postData.Action {
+	case "":
+		return BadRequest("missing firewall action")
+	default:
+		return BadRequest("unsupported firewall action %q", postData.Action)
+	case "confirm":
+		// only currently supported action
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	if err := configcore.ConfirmFirewall(st); err != nil {
+		if errors.Is(err, configcore.ErrNoFirewallConfirmation) {
+			return BadRequest(err.Error())
+		}
+		return InternalError(err.Error())
+	}
+	return SyncResponse(nil)
+}
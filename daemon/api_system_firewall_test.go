@@ -0,0 +1,122 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/testutil"
+)
+
+var _ = Suite(&systemFirewallSuite{})
+
+type systemFirewallSuite struct {
+	apiBaseSuite
+}
+
+func (s *systemFirewallSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectReadAccess(daemon.AuthenticatedAccess{Polkit: "io.snapcraft.snapd.manage-configuration"})
+	s.expectWriteAccess(daemon.AuthenticatedAccess{Polkit: "io.snapcraft.snapd.manage-configuration"})
+}
+
+func (s *systemFirewallSuite) mockPendingConfirmation(c *C, st *state.State, deadline time.Time) *state.Task {
+	st.Lock()
+	defer st.Unlock()
+	chg := st.NewChange("revert-firewall", "...")
+	t := st.NewTask("revert-firewall", "...")
+	chg.AddTask(t)
+	// an empty ruleset removes the firewall
+	st.Set("firewall-pending-confirmation", map[string]any{
+		"ruleset":   "",
+		"change-id": chg.ID(),
+		"deadline":  deadline,
+	})
+	return t
+}
+
+func (s *systemFirewallSuite) TestGetSystemFirewall(c *C) {
+	d := s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/system-firewall", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil, actionIsExpected)
+	c.Check(rsp.Result, DeepEquals, daemon.SystemFirewallResponse{})
+
+	deadline := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s.mockPendingConfirmation(c, d.Overlord().State(), deadline)
+
+	rsp = s.syncReq(c, req, nil, actionIsExpected)
+	resp, ok := rsp.Result.(daemon.SystemFirewallResponse)
+	c.Assert(ok, Equals, true)
+	c.Assert(resp.ConfirmBefore, NotNil)
+	c.Check(resp.ConfirmBefore.Equal(deadline), Equals, true)
+}
+
+func (s *systemFirewallSuite) TestPostSystemFirewallConfirm(c *C) {
+	d := s.daemon(c)
+	st := d.Overlord().State()
+	t := s.mockPendingConfirmation(c, st, time.Now().Add(time.Minute))
+
+	buf := bytes.NewBufferString(`{"action":"confirm"}`)
+	req, err := http.NewRequest("POST", "/v2/system-firewall", buf)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil, actionIsExpected)
+	c.Check(rsp.Result, IsNil)
+
+	st.Lock()
+	defer st.Unlock()
+	var pending map[string]any
+	c.Check(st.Get("firewall-pending-confirmation", &pending), testutil.ErrorIs, state.ErrNoState)
+	// the revert task is not needed anymore
+	c.Check(t.Status(), Equals, state.DoneStatus)
+}
+
+func (s *systemFirewallSuite) TestPostSystemFirewallConfirmNothingPending(c *C) {
+	s.daemon(c)
+
+	buf := bytes.NewBufferString(`{"action":"confirm"}`)
+	req, err := http.NewRequest("POST", "/v2/system-firewall", buf)
+	c.Assert(err, IsNil)
+	rspe := s.errorReq(c, req, nil, actionIsExpected)
+	c.Check(rspe, DeepEquals, daemon.BadRequest("no firewall changes are waiting for confirmation"))
+}
+
+func (s *systemFirewallSuite) TestPostSystemFirewallBadAction(c *C) {
+	s.daemon(c)
+
+	for body, expected := range map[string]string{
+		`{}`:                      "missing firewall action",
+		`{"action":"revert"}`:     `unsupported firewall action "revert"`,
+		`{"action":"confirm"} {}`: "spurious content after firewall action",
+	} {
+		req, err := http.NewRequest("POST", "/v2/system-firewall", bytes.NewBufferString(body))
+		c.Assert(err, IsNil)
+		rspe := s.errorReq(c, req, nil, actionIsUnexpected)
+		c.Check(rspe, DeepEquals, daemon.BadRequest(expected), Commentf(body))
+	}
+}
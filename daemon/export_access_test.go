@@ -33,6 +33,7 @@ type (
 	OpenAccess                   = openAccess
 	AuthenticatedAccess          = authenticatedAccess
 	RootAccess                   = rootAccess
+	UserAccess                   = userAccess
 	SnapAccess                   = snapAccess
 	InterfaceOpenAccess          = interfaceOpenAccess
 	InterfaceAuthenticatedAccess = interfaceAuthenticatedAccess
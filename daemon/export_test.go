@@ -33,6 +33,7 @@ import (
 	"github.com/snapcore/snapd/client/clientutil"
 	"github.com/snapcore/snapd/confdb"
 	"github.com/snapcore/snapd/features"
+	"github.com/snapcore/snapd/interfaces/denials"
 	"github.com/snapcore/snapd/osutil/user"
 	"github.com/snapcore/snapd/overlord"
 	"github.com/snapcore/snapd/overlord/assertstate"
@@ -259,6 +260,18 @@ func MockSnapstateRemoveMany(mock func(*state.State, []string, *snapstate.Remove
 	}
 }
 
+func MockSnapstateAutoremove(mock func(*state.State, *snapstate.RemoveFlags) ([]string, []*state.TaskSet, error)) (restore func()) {
+	return testutil.Mock(&snapstateAutoremove, mock)
+}
+
+func MockSnapstateAutoremoveCandidates(mock func(*state.State) ([]string, error)) (restore func()) {
+	return testutil.Mock(&snapstateAutoremoveCandidates, mock)
+}
+
+func MockSnapstateRevertSnapSetGeneration(mock func(*state.State, int, *snapstate.RemoveFlags, string) ([]*state.TaskSet, error)) (restore func()) {
+	return testutil.Mock(&snapstateRevertSnapSetGeneration, mock)
+}
+
 func MockSnapstateInstallPathMany(f func(context.Context, *state.State, []*snap.SideInfo, []string, int, *snapstate.Flags) ([]*state.TaskSet, error)) func() {
 	old := snapstateInstallPathMany
 	snapstateInstallPathMany = f
@@ -469,3 +482,15 @@ func ResetBuildIDDetection() {
 func MockDevicestateReprovision(f func(st *state.State) (*state.Change, error)) (restore func()) {
 	return testutil.Mock(&devicestateReprovision, f)
 }
+
+func MockDenialsCollect(f func(instanceName string, lines int) ([]*denials.Denial, error)) (restore func()) {
+	return testutil.Mock(&denialsCollect, f)
+}
+
+func MockDenialsCollectDevices(f func(info *snap.Info) ([]*denials.Denial, error)) (restore func()) {
+	return testutil.Mock(&denialsCollectDevices, f)
+}
+
+func MockIfacestateSetDeviceAudit(f func(st *state.State, instanceName string, enable bool) (*state.TaskSet, error)) (restore func()) {
+	return testutil.Mock(&ifacestateSetDeviceAudit, f)
+}
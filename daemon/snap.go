@@ -119,6 +119,7 @@ const (
 	snapSelectAll
 	snapSelectEnabled
 	snapSelectRefreshInhibited
+	snapSelectAutoremove
 )
 
 // allLocalSnapInfos returns the information about the all current snaps and their SnapStates.
@@ -132,6 +133,18 @@ func allLocalSnapInfos(st *state.State, sel snapSelect, wanted map[string]bool)
 	}
 	about := make([]aboutSnap, 0, len(snapStates))
 
+	var autoremovable map[string]bool
+	if sel == snapSelectAutoremove {
+		candidates, err := snapstateAutoremoveCandidates(st)
+		if err != nil {
+			return nil, err
+		}
+		autoremovable = make(map[string]bool, len(candidates))
+		for _, name := range candidates {
+			autoremovable[name] = true
+		}
+	}
+
 	healths, err := healthstate.All(st)
 	if err != nil {
 		return nil, err
@@ -141,6 +154,10 @@ func allLocalSnapInfos(st *state.State, sel snapSelect, wanted map[string]bool)
 		if len(wanted) > 0 && !wanted[name] {
 			continue
 		}
+		if sel == snapSelectAutoremove && !autoremovable[name] {
+			// skip snaps that are still needed
+			continue
+		}
 		health := clientHealthFromHealthstate(healths[name])
 
 		userHold, gatingHold, err := getUserAndGatingHolds(st, name)
@@ -52,6 +52,7 @@ var (
 	SnapLdconfigDir      string
 	SnapSeccompBase      string
 	SnapSeccompDir       string
+	SnapLandlockDir      string
 	SnapMountPolicyDir   string
 	SnapCgroupPolicyDir  string
 	SnapUdevRulesDir     string
@@ -106,6 +107,7 @@ var (
 	SnapAuxStoreInfoDir string
 	SnapIconsPoolDir    string
 	SnapIconsDir        string
+	SnapProfileCacheDir string
 
 	SnapBinariesDir        string
 	SnapServicesDir        string
@@ -589,6 +591,7 @@ func SetRootDir(rootdir string) {
 	SnapDownloadCacheDir = filepath.Join(rootdir, snappyDir, "cache")
 	SnapSeccompBase = filepath.Join(rootdir, snappyDir, "seccomp")
 	SnapSeccompDir = filepath.Join(SnapSeccompBase, "bpf")
+	SnapLandlockDir = filepath.Join(rootdir, snappyDir, "landlock", "rulesets")
 	SnapMountPolicyDir = filepath.Join(rootdir, snappyDir, "mount")
 	SnapCgroupPolicyDir = filepath.Join(rootdir, snappyDir, "cgroup")
 	SnapdMaintenanceFile = filepath.Join(rootdir, snappyDir, "maintenance.json")
@@ -634,6 +637,7 @@ func SetRootDir(rootdir string) {
 	SnapAuxStoreInfoDir = filepath.Join(SnapCacheDir, "aux")
 	SnapIconsPoolDir = filepath.Join(SnapCacheDir, "icons-pool")
 	SnapIconsDir = filepath.Join(SnapCacheDir, "icons")
+	SnapProfileCacheDir = filepath.Join(SnapCacheDir, "profiles")
 
 	SnapSeedDir = SnapSeedDirUnder(rootdir)
 	SnapDeviceDir = SnapDeviceDirUnder(rootdir)
@@ -76,6 +76,9 @@ const (
 	SeedRefresh
 	// SnapDeltaFormat enables deltas that use the "snap delta" format
 	SnapDeltaFormat
+	// Landlock enables confining the filesystem access of snaps with Landlock
+	// on systems without AppArmor.
+	Landlock
 	// lastFeature is the final known feature, it is only used for testing.
 	lastFeature
 )
@@ -126,6 +129,8 @@ var featureNames = map[SnapdFeature]string{
 	SeedRefresh: "seed-refresh",
 
 	SnapDeltaFormat: "snap-delta-format",
+
+	Landlock: "landlock",
 }
 
 // featuresEnabledWhenUnset contains a set of features that are enabled when not explicitly configured.
@@ -143,6 +148,7 @@ var featuresExported = map[SnapdFeature]bool{
 	RefreshAppAwarenessUX: true,
 	Confdb:                true,
 	AppArmorPrompting:     true,
+	Landlock:              true,
 }
 
 // featuresGraduated contains features that used to be guarded by an
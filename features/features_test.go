@@ -64,6 +64,7 @@ func (*featureSuite) TestName(c *C) {
 	check(features.RemoteDeviceManagement, "remote-device-management")
 	check(features.SeedRefresh, "seed-refresh")
 	check(features.SnapDeltaFormat, "snap-delta-format")
+	check(features.Landlock, "landlock")
 
 	c.Check(tested, Equals, features.NumberOfFeatures())
 	c.Check(func() { _ = features.SnapdFeature(1000).String() }, PanicMatches, "unknown feature flag code 1000")
@@ -104,6 +105,7 @@ func (*featureSuite) TestIsExported(c *C) {
 	check(features.RemoteDeviceManagement, false)
 	check(features.SeedRefresh, false)
 	check(features.SnapDeltaFormat, false)
+	check(features.Landlock, true)
 
 	c.Check(tested, Equals, features.NumberOfFeatures())
 }
@@ -239,6 +241,7 @@ func (*featureSuite) TestIsEnabledWhenUnset(c *C) {
 	check(features.RemoteDeviceManagement, false)
 	check(features.SeedRefresh, false)
 	check(features.SnapDeltaFormat, false)
+	check(features.Landlock, false)
 
 	c.Check(tested, Equals, features.NumberOfFeatures())
 }
@@ -30,6 +30,7 @@ import (
 var (
 	DecodeModelAssertion = decodeModelAssertion
 	MakeLabel            = makeLabel
+	ImageBuildTime       = imageBuildTime
 	SetupSeed            = setupSeed
 	InstallCloudConfig   = installCloudConfig
 )
@@ -26,6 +26,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -102,7 +103,10 @@ func classicHasSnaps(model *asserts.Model, opts *Options) bool {
 	return model.Gadget() != "" || len(model.RequiredNoEssentialSnaps()) != 0 || len(opts.Snaps) != 0
 }
 
-var newToolingStoreFromModel = tooling.NewToolingStoreFromModel
+var (
+	newToolingStoreFromModel  = tooling.NewToolingStoreFromModel
+	newToolingStoreFromMirror = tooling.NewToolingStoreFromMirror
+)
 
 func Prepare(opts *Options) error {
 	var model *asserts.Model
@@ -138,7 +142,15 @@ func Prepare(opts *Options) error {
 		}
 	}
 
-	tsto, err := newToolingStoreFromModel(model, opts.Architecture)
+	var tsto *tooling.ToolingStore
+	if opts.SnapMirrorDir != "" {
+		if opts.WideCohortKey != "" {
+			return fmt.Errorf("cannot use a cohort key with a snap mirror")
+		}
+		tsto, err = newToolingStoreFromMirror(opts.SnapMirrorDir)
+	} else {
+		tsto, err = newToolingStoreFromModel(model, opts.Architecture)
+	}
 	if err != nil {
 		return err
 	}
@@ -297,6 +309,25 @@ func makeLabel(now time.Time) string {
 	return now.UTC().Format("20060102")
 }
 
+// imageBuildTime returns the time the image is considered built at, which
+// determines the recovery system label. Images built from a snap mirror are
+// meant to be reproducible, for them SOURCE_DATE_EPOCH is honoured and the
+// model timestamp is used otherwise so that identical inputs produce
+// identical seeds.
+func imageBuildTime(model *asserts.Model, opts *Options) (time.Time, error) {
+	if opts.SnapMirrorDir == "" {
+		return time.Now(), nil
+	}
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		secs, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("cannot parse SOURCE_DATE_EPOCH: %v", err)
+		}
+		return time.Unix(secs, 0), nil
+	}
+	return model.Timestamp(), nil
+}
+
 type imageSeeder struct {
 	model *asserts.Model
 	tsto  *tooling.ToolingStore
@@ -309,6 +340,7 @@ type imageSeeder struct {
 	allowSnapdKernelMismatch bool
 
 	hasModes    bool
+	buildTime   time.Time
 	rootDir     string
 	bootRootDir string
 	seedDir     string
@@ -344,6 +376,12 @@ func newImageSeeder(tsto *tooling.ToolingStore, model *asserts.Model, opts *Opti
 		s.allowSnapdKernelMismatch = true
 	}
 
+	buildTime, err := imageBuildTime(model, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.buildTime = buildTime
+
 	if !s.hasModes {
 		if err := s.setModelessDirs(); err != nil {
 			return nil, err
@@ -423,7 +461,7 @@ func (s *imageSeeder) setModelessDirs() error {
 func (s *imageSeeder) setModesDirs() error {
 	// Core 20, writing for the system-seed partition
 	s.seedDir = filepath.Join(s.prepareDir, "system-seed")
-	s.label = makeLabel(time.Now())
+	s.label = makeLabel(s.buildTime)
 	s.bootRootDir = s.seedDir
 
 	// validity check target
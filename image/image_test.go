@@ -3240,6 +3240,32 @@ func (s *imageSuite) TestCore20MakeLabel(c *C) {
 	c.Check(image.MakeLabel(time.Date(2019, 10, 30, 0, 0, 0, 0, time.UTC)), Equals, "20191030")
 }
 
+func (s *imageSuite) TestImageBuildTime(c *C) {
+	model := s.makeUC20Model(nil)
+	os.Setenv("SOURCE_DATE_EPOCH", "1572393600")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+	// SOURCE_DATE_EPOCH only applies to builds from a snap mirror
+	before := time.Now()
+	t, err := image.ImageBuildTime(model, &image.Options{})
+	c.Assert(err, IsNil)
+	c.Check(t.Before(before), Equals, false)
+
+	t, err = image.ImageBuildTime(model, &image.Options{SnapMirrorDir: "/mirror"})
+	c.Assert(err, IsNil)
+	c.Check(t.Equal(time.Unix(1572393600, 0)), Equals, true)
+
+	os.Setenv("SOURCE_DATE_EPOCH", "invalid")
+	_, err = image.ImageBuildTime(model, &image.Options{SnapMirrorDir: "/mirror"})
+	c.Check(err, ErrorMatches, `cannot parse SOURCE_DATE_EPOCH: .*`)
+
+	// without it the model timestamp is used
+	os.Unsetenv("SOURCE_DATE_EPOCH")
+	t, err = image.ImageBuildTime(model, &image.Options{SnapMirrorDir: "/mirror"})
+	c.Assert(err, IsNil)
+	c.Check(t.Equal(model.Timestamp()), Equals, true)
+}
+
 func (s *imageSuite) makeSnap(c *C, yamlKey string, files [][]string, revno snap.Revision, publisher string) {
 	if publisher == "" {
 		publisher = "canonical"
@@ -3491,6 +3517,163 @@ func (s *imageSuite) TestSetupSeedCore20GrubNoKernelMaxFormats(c *C) {
 	c.Check(s.stderr.String(), Equals, "WARNING: the kernel for the specified UC20+ model does not carry assertion max formats information, assuming possibly incorrectly the kernel revision can use the same formats as snapd\n")
 }
 
+func (s *imageSuite) makeUC20SnapMirror(c *C, channelsYaml string) string {
+	s.makeSnap(c, "snapd", [][]string{snapdInfoFile}, snap.R(1), "")
+	s.makeSnap(c, "core20", nil, snap.R(20), "")
+	s.makeSnap(c, "pc-kernel=20", [][]string{{"snapd-info", `VERSION=2.55`}}, snap.R(1), "")
+	gadgetContent := [][]string{
+		{"grub-recovery.conf", "# recovery grub.cfg"},
+		{"grub.conf", "# boot grub.cfg"},
+		{"meta/gadget.yaml", pcUC20GadgetYaml},
+	}
+	s.makeSnap(c, "pc=20", gadgetContent, snap.R(22), "")
+	comRevs := map[string]snap.Revision{
+		"comp1": snap.R(22),
+		"comp2": snap.R(33),
+	}
+	s.SeedSnaps.MakeAssertedSnapWithComps(c, seedtest.SampleSnapYaml["required20"], nil,
+		snap.R(21), comRevs, "other", s.StoreSigning.Database)
+
+	mirrorDir := c.MkDir()
+	s.SeedSnaps.MakeSnapMirror(c, mirrorDir, channelsYaml, "snapd", "core20", "pc-kernel", "pc", "required20")
+	return mirrorDir
+}
+
+func treeContents(c *C, root string) map[string]string {
+	contents := make(map[string]string)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		c.Assert(err, IsNil)
+		data, err := os.ReadFile(path)
+		c.Assert(err, IsNil)
+		contents[rel] = string(data)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	return contents
+}
+
+func (s *imageSuite) TestPrepareFromSnapMirrorReproducible(c *C) {
+	bootloader.Force(nil)
+	restore := image.MockTrusted(s.StoreSigning.Trusted)
+	defer restore()
+	restore = image.MockNewToolingStoreFromModel(func(model *asserts.Model, fallbackArchitecture string) (*tooling.ToolingStore, error) {
+		c.Fatalf("unexpected use of the store")
+		return nil, nil
+	})
+	defer restore()
+
+	mirrorDir := s.makeUC20SnapMirror(c, `
+snapd:
+  stable: 1
+core20:
+  stable: 20
+pc-kernel:
+  20/stable: 1
+pc:
+  20/stable: 22
+required20:
+  stable: 21
+`)
+	model := s.makeUC20Model(nil)
+	fn := filepath.Join(c.MkDir(), "model.assertion")
+	c.Assert(os.WriteFile(fn, asserts.Encode(model), 0644), IsNil)
+
+	var trees []map[string]string
+	for i := 0; i < 2; i++ {
+		prepareDir := c.MkDir()
+		err := image.Prepare(&image.Options{
+			ModelFile:     fn,
+			PrepareDir:    prepareDir,
+			SnapMirrorDir: mirrorDir,
+			Customizations: image.Customizations{
+				Validation: "ignore",
+			},
+		})
+		c.Assert(err, IsNil)
+
+		// the label is derived from the model
+		label := image.MakeLabel(model.Timestamp())
+		c.Check(filepath.Join(prepareDir, "system-seed/systems", label, "model"), testutil.FilePresent)
+		c.Check(filepath.Join(prepareDir, "system-seed/snaps/pc_22.snap"), testutil.FilePresent)
+		c.Check(filepath.Join(prepareDir, "system-seed/snaps/required20+comp1_22.comp"), testutil.FilePresent)
+
+		trees = append(trees, treeContents(c, filepath.Join(prepareDir, "system-seed")))
+	}
+	c.Check(trees[0], DeepEquals, trees[1])
+
+	// the store was never used
+	c.Check(s.storeActions, HasLen, 0)
+	c.Check(s.assertReqs, HasLen, 0)
+}
+
+func (s *imageSuite) TestPrepareFromSnapMirrorSourceDateEpoch(c *C) {
+	bootloader.Force(nil)
+	restore := image.MockTrusted(s.StoreSigning.Trusted)
+	defer restore()
+	os.Setenv("SOURCE_DATE_EPOCH", "1572393600")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+	mirrorDir := s.makeUC20SnapMirror(c, `
+snapd: {stable: 1}
+core20: {stable: 20}
+pc-kernel: {20/stable: 1}
+pc: {20/stable: 22}
+required20: {stable: 21}
+`)
+	model := s.makeUC20Model(nil)
+	fn := filepath.Join(c.MkDir(), "model.assertion")
+	c.Assert(os.WriteFile(fn, asserts.Encode(model), 0644), IsNil)
+
+	prepareDir := c.MkDir()
+	err := image.Prepare(&image.Options{
+		ModelFile:     fn,
+		PrepareDir:    prepareDir,
+		SnapMirrorDir: mirrorDir,
+	})
+	c.Assert(err, IsNil)
+	c.Check(filepath.Join(prepareDir, "system-seed/systems/20191030/model"), testutil.FilePresent)
+}
+
+func (s *imageSuite) TestPrepareFromSnapMirrorMissingSnap(c *C) {
+	bootloader.Force(nil)
+	restore := image.MockTrusted(s.StoreSigning.Trusted)
+	defer restore()
+
+	mirrorDir := s.makeUC20SnapMirror(c, `
+snapd: {stable: 1}
+core20: {stable: 20}
+pc-kernel: {20/stable: 1}
+required20: {stable: 21}
+`)
+	model := s.makeUC20Model(nil)
+	fn := filepath.Join(c.MkDir(), "model.assertion")
+	c.Assert(os.WriteFile(fn, asserts.Encode(model), 0644), IsNil)
+
+	err := image.Prepare(&image.Options{
+		ModelFile:     fn,
+		PrepareDir:    c.MkDir(),
+		SnapMirrorDir: mirrorDir,
+	})
+	c.Check(err, ErrorMatches, `cannot download snap "pc" from snap mirror: no revision in channel "20"`)
+}
+
+func (s *imageSuite) TestPrepareFromSnapMirrorWithCohortFails(c *C) {
+	fn := filepath.Join(c.MkDir(), "model.assertion")
+	c.Assert(os.WriteFile(fn, asserts.Encode(s.model), 0644), IsNil)
+
+	err := image.Prepare(&image.Options{
+		ModelFile:     fn,
+		PrepareDir:    c.MkDir(),
+		SnapMirrorDir: c.MkDir(),
+		WideCohortKey: "wide-cohort",
+	})
+	c.Check(err, ErrorMatches, `cannot use a cohort key with a snap mirror`)
+}
+
 func (s *imageSuite) TestSetupSeedCore20UBoot(c *C) {
 	bootloader.Force(nil)
 	restore := image.MockTrusted(s.StoreSigning.Trusted)
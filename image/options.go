@@ -61,6 +61,12 @@ type Options struct {
 	// seed.manifest file should be written.
 	SeedManifestPath string
 
+	// SnapMirrorDir if set, points to a local directory mirroring the
+	// snaps, components and assertions needed for the image, which are
+	// then resolved only against it instead of the store. See
+	// tooling.NewToolingStoreFromMirror for the expected layout.
+	SnapMirrorDir string
+
 	// WideCohortKey can be used to supply a cohort covering all
 	// the snaps in the image, there is no generally suppported API
 	// to create such a cohort key.
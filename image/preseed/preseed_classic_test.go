@@ -418,6 +418,7 @@ func (s *preseedSuite) TestReset(c *C) {
 			{filepath.Join(dirs.SnapServicesDir, "snap.foo.service"), ""},
 			{filepath.Join(dirs.SnapServicesDir, "snap.foo.timer"), ""},
 			{filepath.Join(dirs.SnapServicesDir, "snap.foo.socket"), ""},
+			{filepath.Join(dirs.SnapServicesDir, "snap.foo.path"), ""},
 			{filepath.Join(dirs.SnapServicesDir, "snap-foo.mount"), ""},
 			// In order to allow preseeding of images with older snapd, we need to also
 			// add the mount in multi-user.target
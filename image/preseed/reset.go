@@ -104,6 +104,7 @@ var ResetPreseededChroot = func(preseedChroot string) error {
 		filepath.Join(dirs.SnapServicesDir, "snap.*.service"),
 		filepath.Join(dirs.SnapServicesDir, "snap.*.timer"),
 		filepath.Join(dirs.SnapServicesDir, "snap.*.socket"),
+		filepath.Join(dirs.SnapServicesDir, "snap.*.path"),
 		filepath.Join(dirs.SnapServicesDir, "snap-*.mount"),
 		filepath.Join(dirs.SnapServicesDir, "multi-user.target.wants", "snap-*.mount"),
 		filepath.Join(dirs.SnapServicesDir, "default.target.wants", "snap-*.mount"),
@@ -111,9 +112,11 @@ var ResetPreseededChroot = func(preseedChroot string) error {
 		filepath.Join(dirs.SnapUserServicesDir, "snap.*.service"),
 		filepath.Join(dirs.SnapUserServicesDir, "snap.*.socket"),
 		filepath.Join(dirs.SnapUserServicesDir, "snap.*.timer"),
+		filepath.Join(dirs.SnapUserServicesDir, "snap.*.path"),
 		filepath.Join(dirs.SnapUserServicesDir, "default.target.wants", "snap.*.service"),
 		filepath.Join(dirs.SnapUserServicesDir, "sockets.target.wants", "snap.*.socket"),
 		filepath.Join(dirs.SnapUserServicesDir, "timers.target.wants", "snap.*.timer"),
+		filepath.Join(dirs.SnapUserServicesDir, "paths.target.wants", "snap.*.path"),
 		filepath.Join(runinhibit.InhibitDir, "*.lock"),
 	}
 
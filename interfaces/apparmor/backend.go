@@ -46,6 +46,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces"
@@ -79,6 +80,9 @@ type Backend struct {
 
 	coreSnap  *snap.Info
 	snapdSnap *snap.Info
+
+	compiledCacheMu sync.Mutex
+	compiledCache   *compiledCache
 }
 
 // Name returns the name of the backend.
@@ -104,6 +108,15 @@ func (b *Backend) Initialize(opts *interfaces.SecurityBackendOptions) error {
 	return nil
 }
 
+// currentCompiledCache returns the cache of compiled profiles, reusing the
+// one of the previous calls while it is still valid.
+func (b *Backend) currentCompiledCache() *compiledCache {
+	b.compiledCacheMu.Lock()
+	defer b.compiledCacheMu.Unlock()
+	b.compiledCache = newCompiledCache(b.compiledCache)
+	return b.compiledCache
+}
+
 func (b *Backend) initializeSnapConfineProfiles(preseed, reinitializing bool) error {
 	// NOTE: It would be nice if we could also generate the profile for
 	// snap-confine executing from the core snap, right here, and not have to
@@ -431,14 +444,11 @@ func (b *Backend) prepareProfiles(appSet *interfaces.SnapAppSet, opts interfaces
 
 	snapInfo := appSet.Info()
 
-	// Add snippets for parallel snap installation mapping
-	spec.(*Specification).AddOvername(snapInfo)
-
-	// Add snippets derived from the layout definition.
-	spec.(*Specification).AddLayout(appSet)
-
-	// Add additional mount layouts rules for the snap.
-	spec.(*Specification).AddExtraLayouts(snapInfo, opts.ExtraLayouts)
+	// Add snippets for parallel snap installation mapping and the ones
+	// derived from the layout definition.
+	for _, contrib := range b.SnapContributions(appSet, opts) {
+		contrib.Add(spec)
+	}
 
 	// Perform any host-specific setup needed for core and snapd snaps.
 	// TODO: Remove this once Prepare is being called.
@@ -505,14 +515,29 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 	// the cache (since we know those changed for sure).  This allows us to
 	// work despite time being wrong (e.g. in the past). For more details see
 	// https://forum.snapcraft.io/t/apparmor-profile-caching/1268/18
+	// Changed profiles which were already compiled, e.g. for another
+	// revision, are restored from the cache of compiled profiles and then
+	// loaded like the unchanged ones.
+	cc := b.currentCompiledCache()
+	changed, restored := prof.changed, []string(nil)
+	if cc != nil {
+		timings.Run(tm, "restore-compiled-profiles", fmt.Sprintf("restore compiled security profiles of snap %q", snapInfo.InstanceName()), func(nesttm timings.Measurer) {
+			changed, restored = cc.restore(prof.changed)
+		})
+	}
 	var errReloadChanged error
 	aaFlags := apparmor_sandbox.SkipReadCache
 	if b.preseed {
 		aaFlags |= apparmor_sandbox.SkipKernelLoad
 	}
 	timings.Run(tm, "load-profiles[changed]", fmt.Sprintf("load changed security profiles of snap %q", snapInfo.InstanceName()), func(nesttm timings.Measurer) {
-		errReloadChanged = loadProfiles(prof.changed, apparmor_sandbox.CacheDir, aaFlags)
+		errReloadChanged = loadProfiles(changed, apparmor_sandbox.CacheDir, aaFlags)
 	})
+	if cc != nil && errReloadChanged == nil {
+		timings.Run(tm, "store-compiled-profiles", fmt.Sprintf("store compiled security profiles of snap %q", snapInfo.InstanceName()), func(nesttm timings.Measurer) {
+			cc.store(changed)
+		})
+	}
 
 	// Load all unchanged profiles anyway. This ensures those are correct in
 	// the kernel even if the files on disk were not changed. We rely on
@@ -522,8 +547,9 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 	if b.preseed {
 		aaFlags |= apparmor_sandbox.SkipKernelLoad
 	}
+	unchanged := append(prof.unchanged, restored...)
 	timings.Run(tm, "load-profiles[unchanged]", fmt.Sprintf("load unchanged security profiles of snap %q", snapInfo.InstanceName()), func(nesttm timings.Measurer) {
-		errReloadOther = loadProfiles(prof.unchanged, apparmor_sandbox.CacheDir, aaFlags)
+		errReloadOther = loadProfiles(unchanged, apparmor_sandbox.CacheDir, aaFlags)
 	})
 	errRemoveCached := removeCachedProfiles(prof.removed, apparmor_sandbox.CacheDir)
 	if errReloadChanged != nil {
@@ -558,6 +584,15 @@ func (b *Backend) SetupMany(appSets []*interfaces.SnapAppSet, confinement func(s
 	}
 
 	if !fallback {
+		cc := b.currentCompiledCache()
+		if cc != nil {
+			var restored []string
+			timings.Run(tm, "restore-compiled-profiles-many", fmt.Sprintf("restore compiled security profiles of %d snaps", len(appSets)), func(nesttm timings.Measurer) {
+				allChangedPaths, restored = cc.restore(allChangedPaths)
+			})
+			allUnchangedPaths = append(allUnchangedPaths, restored...)
+		}
+
 		aaFlags := apparmor_sandbox.SkipReadCache | apparmor_sandbox.ConserveCPU
 		if b.preseed {
 			aaFlags |= apparmor_sandbox.SkipKernelLoad
@@ -566,6 +601,11 @@ func (b *Backend) SetupMany(appSets []*interfaces.SnapAppSet, confinement func(s
 		timings.Run(tm, "load-profiles[changed-many]", fmt.Sprintf("load changed security profiles of %d snaps", len(appSets)), func(nesttm timings.Measurer) {
 			errReloadChanged = loadProfiles(allChangedPaths, apparmor_sandbox.CacheDir, aaFlags)
 		})
+		if cc != nil && errReloadChanged == nil {
+			timings.Run(tm, "store-compiled-profiles-many", fmt.Sprintf("store compiled security profiles of %d snaps", len(appSets)), func(nesttm timings.Measurer) {
+				cc.store(allChangedPaths)
+			})
+		}
 
 		aaFlags = apparmor_sandbox.ConserveCPU
 		if b.preseed {
@@ -685,7 +725,19 @@ func (b *Backend) deriveContent(spec *Specification, appSet *interfaces.SnapAppS
 
 	// Add profile for apps and hooks.
 	for _, r := range runnables {
-		b.addContent(r.SecurityTag, snapInfo, r.CommandName, opts, spec.SnippetForTag(r.SecurityTag), content, spec)
+		snippets := spec.SnippetForTag(r.SecurityTag)
+		b.addContent(r.SecurityTag, r.SecurityTag, snapInfo, r.CommandName, opts, snippets, content, spec)
+		// The connections limited to some users are only part of the
+		// variants of the profile for these users, snap-confine uses
+		// the variant of the user running the application if any.
+		for _, uid := range spec.Users() {
+			userSnippets := spec.UserSnippetForTag(uid, r.SecurityTag)
+			if userSnippets == "" {
+				continue
+			}
+			profile := apparmor_sandbox.UserProfileName(r.SecurityTag, uid)
+			b.addContent(r.SecurityTag, profile, snapInfo, r.CommandName, opts, snippets+"\n"+userSnippets, content, spec)
+		}
 	}
 
 	// Add profile for snap-update-ns if we have any apps or hooks.
@@ -742,7 +794,7 @@ func addUpdateNSProfile(snapInfo *snap.Info, snippets string, content map[string
 // Allow optional trailing ' ' after "###PROMPT###"
 var promptReplacer = regexp.MustCompile("###PROMPT### ?")
 
-func (b *Backend) addContent(securityTag string, snapInfo *snap.Info, cmdName string, opts interfaces.ConfinementOptions, snippetForTag string, content map[string]osutil.FileState, spec *Specification) {
+func (b *Backend) addContent(securityTag, profileName string, snapInfo *snap.Info, cmdName string, opts interfaces.ConfinementOptions, snippetForTag string, content map[string]osutil.FileState, spec *Specification) {
 	// If base is specified and it doesn't match the core snaps (not
 	// specifying a base should use the default core policy since in this
 	// case, the 'core' snap is used for the runtime), use the base
@@ -937,7 +989,7 @@ func (b *Backend) addContent(securityTag string, snapInfo *snap.Info, cmdName st
 		case "###VAR###":
 			return templateVariables(snapInfo, securityTag, cmdName)
 		case "###PROFILEATTACH###":
-			return fmt.Sprintf("profile \"%s\"", securityTag)
+			return fmt.Sprintf("profile \"%s\"", profileName)
 		case "###FLAGS###":
 			// default flags
 			flags := []string{"attach_disconnected", "mediate_deleted"}
@@ -1061,12 +1113,48 @@ func (b *Backend) addContent(securityTag string, snapInfo *snap.Info, cmdName st
 		return ""
 	})
 
-	content[securityTag] = &osutil.MemoryFileState{
+	content[profileName] = &osutil.MemoryFileState{
 		Content: []byte(policy),
 		Mode:    0644,
 	}
 }
 
+// SnapContributions returns the contributions to the specification of the
+// snap that are derived from the snap itself: the remapping of the
+// directories of parallel instances and the rules for its layout.
+func (b *Backend) SnapContributions(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) []*interfaces.SpecificationContribution {
+	snapInfo := appSet.Info()
+	return []*interfaces.SpecificationContribution{{
+		Origin: interfaces.SpecificationOrigin{Kind: interfaces.OriginParallelInstance},
+		Add: func(spec interfaces.Specification) error {
+			spec.(*Specification).AddOvername(snapInfo)
+			return nil
+		},
+	}, {
+		Origin: interfaces.SpecificationOrigin{Kind: interfaces.OriginLayout},
+		Add: func(spec interfaces.Specification) error {
+			spec.(*Specification).AddLayout(appSet)
+			// Add additional mount layouts rules for the snap.
+			spec.(*Specification).AddExtraLayouts(snapInfo, opts.ExtraLayouts)
+			return nil
+		},
+	}}
+}
+
+// RenderProfiles returns the apparmor profiles of the snap derived from the
+// given specification, without writing or loading them.
+func (b *Backend) RenderProfiles(spec interfaces.Specification, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) (map[string][]byte, error) {
+	return memoryContent(b.deriveContent(spec.(*Specification), appSet, opts)), nil
+}
+
+func memoryContent(content map[string]osutil.FileState) map[string][]byte {
+	rendered := make(map[string][]byte, len(content))
+	for name, state := range content {
+		rendered[name] = state.(*osutil.MemoryFileState).Content
+	}
+	return rendered
+}
+
 // NewSpecification returns a new, empty apparmor specification.
 func (b *Backend) NewSpecification(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) interfaces.Specification {
 	return &Specification{
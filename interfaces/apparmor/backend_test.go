@@ -23,9 +23,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	. "gopkg.in/check.v1"
 
@@ -112,6 +114,11 @@ func (s *backendSuite) SetUpTest(c *C) {
 		return s.removeCachedProfilesReturn
 	})
 	s.AddCleanup(restore)
+	// the cache of compiled profiles is tested separately
+	restore = apparmor.MockCacheDirForFeatures(func(cacheDir string) (string, error) {
+		return "", fmt.Errorf("no cache in tests")
+	})
+	s.AddCleanup(restore)
 
 	err = s.Backend.Initialize(ifacetest.DefaultInitializeOpts)
 	c.Assert(err, IsNil)
@@ -307,6 +314,108 @@ func (s *backendSuite) TestInstallingSnapWritesAndLoadsProfiles(c *C) {
 	})
 }
 
+func (s *backendSuite) TestSetupReusesCompiledProfiles(c *C) {
+	forest := filepath.Join(apparmor_sandbox.CacheDir, "deadbeef.0")
+	c.Assert(os.MkdirAll(forest, 0755), IsNil)
+	restore := apparmor.MockCacheDirForFeatures(func(cacheDir string) (string, error) {
+		c.Check(cacheDir, Equals, apparmor_sandbox.CacheDir)
+		return forest, nil
+	})
+	defer restore()
+	restore = apparmor.MockAppArmorParser(func() (*exec.Cmd, bool, error) {
+		return exec.Command("apparmor_parser"), false, nil
+	})
+	defer restore()
+	restore = apparmor.MockLoadProfiles(func(fnames []string, cacheDir string, flags apparmor_sandbox.AaParserFlags) error {
+		if len(fnames) == 0 {
+			return nil
+		}
+		s.loadProfilesCalls = append(s.loadProfilesCalls, loadProfilesParams{fnames, cacheDir, flags})
+		if flags&apparmor_sandbox.SkipReadCache != 0 {
+			for _, fname := range fnames {
+				c.Assert(os.WriteFile(filepath.Join(forest, filepath.Base(fname)), []byte("compiled"), 0644), IsNil)
+			}
+		}
+		return nil
+	})
+	defer restore()
+
+	updateNSProfile := filepath.Join(dirs.SnapAppArmorDir, "snap-update-ns.samba")
+	profile := filepath.Join(dirs.SnapAppArmorDir, "snap.samba.smbd")
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 1)
+	c.Check(s.loadProfilesCalls, DeepEquals, []loadProfilesParams{
+		{[]string{updateNSProfile, profile}, apparmor_sandbox.CacheDir, apparmor_sandbox.SkipReadCache},
+	})
+	s.RemoveSnap(c, snapInfo)
+	c.Assert(os.RemoveAll(forest), IsNil)
+	c.Assert(os.MkdirAll(forest, 0755), IsNil)
+
+	// the profiles are identical, the compiled ones are taken from the
+	// cache and loaded without compiling them again
+	s.loadProfilesCalls = nil
+	s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 1)
+	c.Check(s.loadProfilesCalls, DeepEquals, []loadProfilesParams{
+		{[]string{updateNSProfile, profile}, apparmor_sandbox.CacheDir, 0},
+	})
+	c.Check(filepath.Join(forest, "snap.samba.smbd"), testutil.FileEquals, "compiled")
+}
+
+func (s *backendSuite) TestSetupReusesCompiledCacheSalt(c *C) {
+	forest := filepath.Join(apparmor_sandbox.CacheDir, "deadbeef.0")
+	c.Assert(os.MkdirAll(forest, 0755), IsNil)
+	tunables := filepath.Join(apparmor_sandbox.ConfDir, "tunables")
+	c.Assert(os.MkdirAll(filepath.Join(tunables, "home.d"), 0755), IsNil)
+	calls := 0
+	restore := apparmor.MockCacheDirForFeatures(func(cacheDir string) (string, error) {
+		calls++
+		return forest, nil
+	})
+	defer restore()
+	restore = apparmor.MockAppArmorParser(func() (*exec.Cmd, bool, error) {
+		return exec.Command("apparmor_parser"), false, nil
+	})
+	defer restore()
+
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 1)
+	c.Check(calls, Equals, 1)
+	snapInfo = s.UpdateSnap(c, snapInfo, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1WithNmbd, 2)
+	// nothing changed, the salt is not computed again
+	c.Check(calls, Equals, 1)
+
+	// a change to the included files invalidates the salt
+	c.Assert(os.WriteFile(filepath.Join(tunables, "home.d", "extra"), nil, 0644), IsNil)
+	future := time.Now().Add(time.Hour)
+	c.Assert(os.Chtimes(filepath.Join(tunables, "home.d"), future, future), IsNil)
+	s.UpdateSnap(c, snapInfo, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1, 3)
+	c.Check(calls, Equals, 2)
+}
+
+func (s *backendSuite) TestRenderProfiles(c *C) {
+	appSet := s.AddSnap(c, "", ifacetest.SambaYamlV1, 1)
+	opts := interfaces.ConfinementOptions{}
+	renderer := s.Backend.(interfaces.SecurityBackendRenderer)
+
+	spec, err := s.Repo.SnapSpecification(s.Backend.Name(), appSet, opts)
+	c.Assert(err, IsNil)
+	contribs := renderer.SnapContributions(appSet, opts)
+	c.Assert(contribs, HasLen, 2)
+	c.Check(contribs[0].Origin.Kind, Equals, interfaces.OriginParallelInstance)
+	c.Check(contribs[1].Origin.Kind, Equals, interfaces.OriginLayout)
+	for _, contrib := range contribs {
+		c.Assert(contrib.Add(spec), IsNil)
+	}
+
+	profiles, err := renderer.RenderProfiles(spec, appSet, opts)
+	c.Assert(err, IsNil)
+	c.Check(profiles, HasLen, 2)
+	c.Check(string(profiles["snap.samba.smbd"]), testutil.Contains, `profile "snap.samba.smbd"`)
+	c.Check(string(profiles["snap-update-ns.samba"]), testutil.Contains, `profile snap-update-ns.samba`)
+
+	// nothing is written nor loaded
+	c.Check(filepath.Join(dirs.SnapAppArmorDir, "snap.samba.smbd"), testutil.FileAbsent)
+	c.Check(s.loadProfilesCalls, HasLen, 0)
+}
+
 func (s *backendSuite) TestInstallingSnapWithHookWritesAndLoadsProfiles(c *C) {
 	s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.HookYaml, 1)
 	profile := filepath.Join(dirs.SnapAppArmorDir, "snap.foo.hook.configure")
@@ -1320,6 +1429,60 @@ func (s *backendSuite) TestCombineSnippets(c *C) {
 	}
 }
 
+const consumerYaml = `
+name: consumer
+version: 1
+apps:
+    app:
+        plugs: [plug]
+plugs:
+    plug:
+        interface: iface
+`
+
+func (s *backendSuite) TestConnectionLimitedToUsers(c *C) {
+	restoreTemplate := apparmor.MockTemplate("\n" +
+		"###PROFILEATTACH### ###FLAGS### {\n" +
+		"###SNIPPETS###\n" +
+		"}\n")
+	defer restoreTemplate()
+	s.Iface.AppArmorConnectedPlugCallback = func(spec *apparmor.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+		spec.AddSnippet("connected-plug")
+		return nil
+	}
+	s.Iface.AppArmorPermanentPlugCallback = func(spec *apparmor.Specification, plug *snap.PlugInfo) error {
+		spec.AddSnippet("permanent-plug")
+		return nil
+	}
+
+	slotAppSet := s.AddSnap(c, "", ifacetest.SambaYamlV1, 1)
+	appSet := s.AddSnap(c, "", consumerYaml, 1)
+	connRef := interfaces.NewConnRef(appSet.Info().Plugs["plug"], slotAppSet.Info().Slots["slot"])
+	_, err := s.Repo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(s.Repo.SetConnectionUsers(connRef, []int{1000}), IsNil)
+
+	sctx := interfaces.SetupContext{Reason: interfaces.SnapSetupReasonOther}
+	c.Assert(s.Backend.Setup(appSet, interfaces.ConfinementOptions{}, sctx, s.Repo, s.meas), IsNil)
+
+	// the default profile does not grant the connection
+	profile := filepath.Join(dirs.SnapAppArmorDir, "snap.consumer.app")
+	c.Check(profile, testutil.FileContains, `profile "snap.consumer.app" flags=(attach_disconnected,mediate_deleted) {`)
+	c.Check(profile, testutil.FileContains, "permanent-plug")
+	c.Check(profile, Not(testutil.FileContains), "connected-plug")
+	// the variant of the profile for the user does
+	userProfile := filepath.Join(dirs.SnapAppArmorDir, "snap.consumer.app.user-1000")
+	c.Check(userProfile, testutil.FileContains, `profile "snap.consumer.app.user-1000" flags=(attach_disconnected,mediate_deleted) {`)
+	c.Check(userProfile, testutil.FileContains, "permanent-plug")
+	c.Check(userProfile, testutil.FileContains, "connected-plug")
+
+	// the variant goes away with the limitation
+	c.Assert(s.Repo.SetConnectionUsers(connRef, nil), IsNil)
+	c.Assert(s.Backend.Setup(appSet, interfaces.ConfinementOptions{}, sctx, s.Repo, s.meas), IsNil)
+	c.Check(profile, testutil.FileContains, "connected-plug")
+	c.Check(userProfile, testutil.FileAbsent)
+}
+
 func (s *backendSuite) TestUnconfinedFlag(c *C) {
 	restore := apparmor_sandbox.MockLevel(apparmor_sandbox.Full)
 	defer restore()
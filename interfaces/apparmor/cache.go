@@ -0,0 +1,194 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package apparmor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/profilecache"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	apparmor_sandbox "github.com/snapcore/snapd/sandbox/apparmor"
+)
+
+var (
+	apparmorCacheDirForFeatures = apparmor_sandbox.CacheDirForFeatures
+	apparmorParser              = apparmor_sandbox.AppArmorParser
+)
+
+// compiledCache shares the profiles compiled by apparmor_parser across
+// revisions and snaps. Compiled profiles are keyed by the content of the
+// profile, salted with everything else that influences the compilation:
+// the kernel features, the parser and the files included by the profiles.
+//
+// A nil compiledCache is valid and caches nothing.
+type compiledCache struct {
+	cache *profilecache.Cache
+	// forest is the directory where apparmor_parser reads and writes the
+	// compiled profiles for the features of the running kernel
+	forest string
+	salt   string
+	// parser and dirs identify the parser and the state of the directories
+	// of the included files the salt was computed for
+	parser string
+	dirs   map[string]time.Time
+}
+
+// newCompiledCache returns the cache of compiled profiles, or nil if the
+// location of the apparmor_parser cache cannot be determined. Computing the
+// salt runs apparmor_parser and walks the included files, the previous cache
+// is therefore returned as is when neither the parser nor the directories
+// of the included files changed since it was computed. Packages replace
+// files by renaming them, which updates the directories they are in.
+func newCompiledCache(prev *compiledCache) *compiledCache {
+	cmd, _, err := apparmorParser()
+	if err != nil {
+		logger.Debugf("cannot use cache of compiled apparmor profiles: %v", err)
+		return nil
+	}
+	var parser strings.Builder
+	fmt.Fprintf(&parser, "parser:%s\n", strings.Join(cmd.Args, " "))
+	if fi, err := os.Stat(cmd.Path); err == nil {
+		fmt.Fprintf(&parser, "parser-stat:%d %d\n", fi.Size(), fi.ModTime().UnixNano())
+	}
+	if prev != nil && prev.parser == parser.String() && !prev.dirsChanged() {
+		return prev
+	}
+
+	forest, err := apparmorCacheDirForFeatures(apparmor_sandbox.CacheDir)
+	if err != nil {
+		logger.Debugf("cannot use cache of compiled apparmor profiles: %v", err)
+		return nil
+	}
+
+	var salt strings.Builder
+	fmt.Fprintf(&salt, "features:%s\n", filepath.Base(forest))
+	salt.WriteString(parser.String())
+	// the profiles include tunables and abstractions from the base
+	// directory, any change there must invalidate the compiled profiles
+	base := apparmor_sandbox.ConfDir
+	for i, arg := range cmd.Args {
+		if arg == "--base" && i+1 < len(cmd.Args) {
+			base = cmd.Args[i+1]
+		}
+	}
+	includeDirs := make(map[string]time.Time)
+	for _, dir := range []string{"tunables", "abstractions"} {
+		dir = filepath.Join(base, dir)
+		// a missing directory is recorded too, so that creating it is
+		// noticed
+		includeDirs[dir] = time.Time{}
+		filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if fi.IsDir() {
+				includeDirs[path] = fi.ModTime()
+			}
+			fmt.Fprintf(&salt, "include:%s %d %d\n", path, fi.Size(), fi.ModTime().UnixNano())
+			return nil
+		})
+	}
+
+	return &compiledCache{
+		cache:  profilecache.New(filepath.Join(dirs.SnapProfileCacheDir, "apparmor")),
+		forest: forest,
+		salt:   salt.String(),
+		parser: parser.String(),
+		dirs:   includeDirs,
+	}
+}
+
+// dirsChanged returns whether any of the directories of the included files
+// was modified, created or removed since the salt was computed.
+func (cc *compiledCache) dirsChanged() bool {
+	for dir, mtime := range cc.dirs {
+		var current time.Time
+		if fi, err := os.Stat(dir); err == nil {
+			current = fi.ModTime()
+		}
+		if !current.Equal(mtime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cc *compiledCache) key(profile string) (string, error) {
+	content, err := os.ReadFile(profile)
+	if err != nil {
+		return "", err
+	}
+	return profilecache.Key(cc.salt, content), nil
+}
+
+// restore puts the compiled profiles found in the cache in place for
+// apparmor_parser. It returns the profiles that still need compiling and the
+// ones that were restored.
+func (cc *compiledCache) restore(profiles []string) (misses, hits []string) {
+	if cc == nil || !osutil.IsDirectory(cc.forest) {
+		return profiles, nil
+	}
+	for _, profile := range profiles {
+		key, err := cc.key(profile)
+		if err != nil {
+			misses = append(misses, profile)
+			continue
+		}
+		found, err := cc.cache.Fetch(key, filepath.Join(cc.forest, filepath.Base(profile)))
+		if err != nil {
+			logger.Noticef("cannot restore compiled apparmor profile %s: %v", profile, err)
+		}
+		if !found || err != nil {
+			misses = append(misses, profile)
+			continue
+		}
+		hits = append(hits, profile)
+	}
+	return misses, hits
+}
+
+// store adds the profiles just compiled by apparmor_parser to the cache.
+func (cc *compiledCache) store(profiles []string) {
+	if cc == nil || len(profiles) == 0 {
+		return
+	}
+	for _, profile := range profiles {
+		compiled := filepath.Join(cc.forest, filepath.Base(profile))
+		if !osutil.FileExists(compiled) {
+			continue
+		}
+		key, err := cc.key(profile)
+		if err == nil {
+			err = cc.cache.Store(key, compiled)
+		}
+		if err != nil {
+			logger.Noticef("cannot cache compiled apparmor profile %s: %v", profile, err)
+		}
+	}
+	if err := cc.cache.Prune(profilecache.MaxEntries); err != nil {
+		logger.Noticef("cannot prune cache of compiled apparmor profiles: %v", err)
+	}
+}
@@ -21,6 +21,7 @@ package apparmor
 
 import (
 	"os"
+	"os/exec"
 
 	apparmor_sandbox "github.com/snapcore/snapd/sandbox/apparmor"
 	"github.com/snapcore/snapd/snap"
@@ -41,6 +42,14 @@ func MockLoadProfiles(f func(fnames []string, cacheDir string, flags apparmor_sa
 	return r
 }
 
+func MockCacheDirForFeatures(f func(cacheDir string) (string, error)) (restore func()) {
+	return testutil.Mock(&apparmorCacheDirForFeatures, f)
+}
+
+func MockAppArmorParser(f func() (*exec.Cmd, bool, error)) (restore func()) {
+	return testutil.Mock(&apparmorParser, f)
+}
+
 func MockRemoveCachedProfiles(f func(fnames []string, cacheDir string) error) (restore func()) {
 	r := testutil.Backup(&removeCachedProfiles)
 	removeCachedProfiles = f
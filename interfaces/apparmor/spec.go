@@ -232,6 +232,11 @@ type Specification struct {
 	// Unconfined profile mode allows a profile to be applied without any
 	// real confinement
 	unconfined UnconfinedMode
+
+	// userSpecs hold the policy of the connections limited to some users,
+	// indexed by user ID. The policy is only part of the variants of the
+	// profiles for these users.
+	userSpecs map[int]*Specification
 }
 
 func NewSpecification(appSet *interfaces.SnapAppSet) *Specification {
@@ -907,6 +912,19 @@ func (spec *Specification) AddConnectedPlug(iface interfaces.Interface, plug *in
 			return err
 		}
 
+		if users := plug.Users(); len(users) > 0 {
+			for _, uid := range users {
+				uspec := spec.userSpec(uid)
+				restore := uspec.setScope(tags)
+				err := iface.AppArmorConnectedPlug(uspec, plug, slot)
+				restore()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
 		restore := spec.setScope(tags)
 		defer restore()
 		return iface.AppArmorConnectedPlug(spec, plug, slot)
@@ -914,6 +932,36 @@ func (spec *Specification) AddConnectedPlug(iface interfaces.Interface, plug *in
 	return nil
 }
 
+func (spec *Specification) userSpec(uid int) *Specification {
+	if spec.userSpecs == nil {
+		spec.userSpecs = make(map[int]*Specification)
+	}
+	if spec.userSpecs[uid] == nil {
+		spec.userSpecs[uid] = NewSpecification(spec.appSet)
+	}
+	return spec.userSpecs[uid]
+}
+
+// Users returns the sorted IDs of the users some connections are limited to.
+func (spec *Specification) Users() []int {
+	users := make([]int, 0, len(spec.userSpecs))
+	for uid := range spec.userSpecs {
+		users = append(users, uid)
+	}
+	sort.Ints(users)
+	return users
+}
+
+// UserSnippetForTag returns the snippets of the connections limited to the
+// given user for the given security tag.
+func (spec *Specification) UserSnippetForTag(uid int, tag string) string {
+	uspec := spec.userSpecs[uid]
+	if uspec == nil {
+		return ""
+	}
+	return uspec.SnippetForTag(tag)
+}
+
 // AddConnectedSlot records apparmor-specific side-effects of having a connected slot.
 func (spec *Specification) AddConnectedSlot(iface interfaces.Interface, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
 	type definer interface {
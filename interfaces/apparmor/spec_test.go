@@ -118,6 +118,43 @@ func (s *specSuite) TestSpecificationIface(c *C) {
 	})
 }
 
+func (s *specSuite) TestConnectedPlugLimitedToUsers(c *C) {
+	const plugYaml = `name: snap1
+version: 1
+plugs:
+ name:
+  interface: test
+apps:
+ app1:
+  plugs: [name]
+`
+	plug, plugInfo := ifacetest.MockConnectedPlug(c, plugYaml, nil, "name")
+	repo := interfaces.NewRepository()
+	c.Assert(repo.AddInterface(s.iface), IsNil)
+	plugAppSet, err := interfaces.NewSnapAppSet(plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.AddAppSet(plugAppSet), IsNil)
+	slotAppSet, err := interfaces.NewSnapAppSet(s.slot.Snap(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.AddAppSet(slotAppSet), IsNil)
+	connRef := interfaces.NewConnRef(plugInfo, s.slotInfo)
+	_, err = repo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.SetConnectionUsers(connRef, []int{1001, 1000}), IsNil)
+	conn, err := repo.Connection(connRef)
+	c.Assert(err, IsNil)
+
+	spec := apparmor.NewSpecification(plugAppSet)
+	c.Assert(spec.AddConnectedPlug(s.iface, conn.Plug, conn.Slot), IsNil)
+	c.Assert(spec.AddPermanentPlug(s.iface, plugInfo), IsNil)
+	// the policy of the connection is only given to its users
+	c.Check(spec.SnippetForTag("snap.snap1.app1"), Equals, "permanent-plug")
+	c.Check(spec.Users(), DeepEquals, []int{1000, 1001})
+	c.Check(spec.UserSnippetForTag(1000, "snap.snap1.app1"), Equals, "connected-plug")
+	c.Check(spec.UserSnippetForTag(1001, "snap.snap1.app1"), Equals, "connected-plug")
+	c.Check(spec.UserSnippetForTag(1002, "snap.snap1.app1"), Equals, "")
+}
+
 // MetadataTagSnippet wraps a snippet in the given metadata tags.
 func (s *specSuite) TestMetadataTagSnippet(c *C) {
 	tagFoo := apparmor.RegisterMetadataTagWithInterface("foo", "an-interface")
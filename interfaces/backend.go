@@ -77,6 +77,10 @@ type ConfinementOptions struct {
 	// KernelSnap is the name of the kernel snap in the system
 	// (empty for classic systems).
 	KernelSnap string
+	// DeviceAudit indicates whether the device accesses denied by the
+	// device cgroup of the snap are to be audited. It does not affect what
+	// the snap is allowed to access.
+	DeviceAudit bool
 }
 
 // SecurityBackendOptions carries extra flags that affect initialization of the
@@ -234,3 +238,16 @@ func SupportsDelayingEffects(backend SecurityBackend) bool {
 	_, ok := backend.(DelayedSideEffectsBackend)
 	return ok
 }
+
+// SecurityBackendRenderer interface may be implemented by backends that can
+// render the security artefacts of a snap without installing them, which is
+// used to inspect the effective profiles of snaps.
+type SecurityBackendRenderer interface {
+	// SnapContributions returns the contributions to the specification of
+	// the given snap the backend derives from the snap itself rather than
+	// from its interfaces, such as the ones for its layout.
+	SnapContributions(appSet *SnapAppSet, opts ConfinementOptions) []*SpecificationContribution
+	// RenderProfiles returns the content of the security artefacts derived
+	// from the given specification of the snap, keyed by file name.
+	RenderProfiles(spec Specification, appSet *SnapAppSet, opts ConfinementOptions) (map[string][]byte, error)
+}
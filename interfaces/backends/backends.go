@@ -20,11 +20,13 @@
 package backends
 
 import (
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
 	"github.com/snapcore/snapd/interfaces/configfiles"
 	"github.com/snapcore/snapd/interfaces/dbus"
 	"github.com/snapcore/snapd/interfaces/kmod"
+	"github.com/snapcore/snapd/interfaces/landlock"
 	"github.com/snapcore/snapd/interfaces/ldconfig"
 	"github.com/snapcore/snapd/interfaces/mount"
 	"github.com/snapcore/snapd/interfaces/polkit"
@@ -34,6 +36,7 @@ import (
 	"github.com/snapcore/snapd/interfaces/udev"
 	"github.com/snapcore/snapd/logger"
 	apparmor_sandbox "github.com/snapcore/snapd/sandbox/apparmor"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
 )
 
 // All returns a set of all available security backends.
@@ -79,6 +82,18 @@ func All() []interfaces.SecurityBackend {
 	switch apparmor_sandbox.ProbedLevel() {
 	case apparmor_sandbox.Partial, apparmor_sandbox.Full:
 		all = append(all, &apparmor.Backend{})
+	default:
+		// Without AppArmor fall back to Landlock for confining the access
+		// of snaps to the filesystem, if the kernel supports it and the
+		// experimental feature is enabled. Changing the feature requires
+		// a restart of snapd.
+		if !features.Landlock.IsEnabled() {
+			break
+		}
+		logger.Noticef("Landlock status: %s\n", landlock_sandbox.Summary())
+		if landlock_sandbox.ProbedABI() > 0 {
+			all = append(all, &landlock.Backend{})
+		}
 	}
 	return all
 }
@@ -18,13 +18,17 @@
 package backends_test
 
 import (
+	"os"
 	"testing"
 
 	. "gopkg.in/check.v1"
 
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/backends"
 	apparmor_sandbox "github.com/snapcore/snapd/sandbox/apparmor"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/snapdenv"
 	"github.com/snapcore/snapd/testutil"
 )
@@ -51,6 +55,43 @@ func (s *backendsSuite) TestIsAppArmorEnabled(c *C) {
 	}
 }
 
+func (s *backendsSuite) TestIsLandlockEnabled(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	defer dirs.SetRootDir("")
+
+	for _, t := range []struct {
+		level    apparmor_sandbox.LevelType
+		abi      int
+		feature  bool
+		landlock bool
+	}{
+		{apparmor_sandbox.Unsupported, 0, true, false},
+		{apparmor_sandbox.Unsupported, 1, true, true},
+		{apparmor_sandbox.Unsupported, 1, false, false},
+		{apparmor_sandbox.Unusable, 3, true, true},
+		{apparmor_sandbox.Unusable, 3, false, false},
+		{apparmor_sandbox.Partial, 3, true, false},
+		{apparmor_sandbox.Full, 3, true, false},
+	} {
+		restore := apparmor_sandbox.MockLevel(t.level)
+		defer restore()
+		restore = landlock_sandbox.MockABI(t.abi)
+		defer restore()
+		if t.feature {
+			c.Assert(os.MkdirAll(dirs.FeaturesDir, 0755), IsNil)
+			c.Assert(os.WriteFile(features.Landlock.ControlFile(), nil, 0644), IsNil)
+		} else {
+			c.Assert(os.RemoveAll(features.Landlock.ControlFile()), IsNil)
+		}
+
+		if t.landlock {
+			c.Check(backendNames(backends.All()), testutil.Contains, "landlock", Commentf("%v", t))
+		} else {
+			c.Check(backendNames(backends.All()), Not(testutil.Contains), "landlock", Commentf("%v", t))
+		}
+	}
+}
+
 func (s *backendsSuite) TestEssentialOrdering(c *C) {
 	restore := apparmor_sandbox.MockLevel(apparmor_sandbox.Full)
 	defer restore()
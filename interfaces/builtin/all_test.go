@@ -32,6 +32,7 @@ import (
 	"github.com/snapcore/snapd/interfaces/dbus"
 	"github.com/snapcore/snapd/interfaces/ifacetest"
 	"github.com/snapcore/snapd/interfaces/kmod"
+	"github.com/snapcore/snapd/interfaces/landlock"
 	"github.com/snapcore/snapd/interfaces/mount"
 	"github.com/snapcore/snapd/interfaces/polkit"
 	"github.com/snapcore/snapd/interfaces/seccomp"
@@ -90,6 +91,19 @@ type kmodDefiner4 interface {
 	KModPermanentSlot(spec *kmod.Specification, slot *snap.SlotInfo) error
 }
 
+type landlockDefiner1 interface {
+	LandlockConnectedPlug(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
+}
+type landlockDefiner2 interface {
+	LandlockConnectedSlot(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
+}
+type landlockDefiner3 interface {
+	LandlockPermanentPlug(spec *landlock.Specification, plug *snap.PlugInfo) error
+}
+type landlockDefiner4 interface {
+	LandlockPermanentSlot(spec *landlock.Specification, slot *snap.SlotInfo) error
+}
+
 type mountDefiner1 interface {
 	MountConnectedPlug(spec *mount.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
 }
@@ -172,6 +186,11 @@ var allGoodDefiners = []reflect.Type{
 	reflect.TypeOf((*kmodDefiner2)(nil)).Elem(),
 	reflect.TypeOf((*kmodDefiner3)(nil)).Elem(),
 	reflect.TypeOf((*kmodDefiner4)(nil)).Elem(),
+	// landlock
+	reflect.TypeOf((*landlockDefiner1)(nil)).Elem(),
+	reflect.TypeOf((*landlockDefiner2)(nil)).Elem(),
+	reflect.TypeOf((*landlockDefiner3)(nil)).Elem(),
+	reflect.TypeOf((*landlockDefiner4)(nil)).Elem(),
 	// mount
 	reflect.TypeOf((*mountDefiner1)(nil)).Elem(),
 	reflect.TypeOf((*mountDefiner2)(nil)).Elem(),
@@ -20,7 +20,16 @@
 package builtin
 
 import (
+	"fmt"
+	"path/filepath"
+	"regexp"
 	"strings"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/hotplug"
+	"github.com/snapcore/snapd/interfaces/udev"
+	"github.com/snapcore/snapd/snap"
 )
 
 const cameraSummary = `allows access to all cameras`
@@ -39,7 +48,15 @@ const cameraConnectedPlugAppArmor = `
 
 # VideoCore cameras (shared device with VideoCore/EGL)
 ###PROMPT### /dev/vchiq rw,
+` + cameraConnectedPlugAppArmorDetection
+
+// Rules for slots of individual cameras, created by the hotplug subsystem.
+const cameraConnectedPlugAppArmorPath = `
+# Description: Allow access to an individual camera.
+###PROMPT### %s rwk,
+`
 
+const cameraConnectedPlugAppArmorDetection = `
 # Allow detection of cameras. Leaks plugged in USB device info
 /sys/bus/usb/devices/ r,
 /sys/devices/pci**/usb*/**/busnum r,
@@ -73,8 +90,76 @@ var cameraConnectedPlugUDev = []string{
 	`KERNEL=="vchiq"`,
 }
 
+// cameraInterface is the type for the camera interface. Besides the
+// implicit slot giving access to all cameras, slots for individual cameras
+// are created by the hotplug subsystem.
+type cameraInterface struct {
+	commonInterface
+}
+
+// Pattern to match the device nodes of cameras, the path attribute of slots
+// for individual cameras is compared to this for validity.
+var cameraDeviceNodePattern = regexp.MustCompile("^/dev/video[0-9]{1,3}$")
+
+// BeforePrepareSlot checks validity of the path attribute identifying an
+// individual camera, if any.
+func (iface *cameraInterface) BeforePrepareSlot(slot *snap.SlotInfo) error {
+	path, ok := slot.Attrs["path"]
+	if !ok {
+		return nil
+	}
+	if path, ok := path.(string); !ok || !cameraDeviceNodePattern.MatchString(filepath.Clean(path)) {
+		return fmt.Errorf("camera path attribute must be a valid device node")
+	}
+	return nil
+}
+
+func (iface *cameraInterface) AppArmorConnectedPlug(spec *apparmor.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	var path string
+	if err := slot.Attr("path", &path); err != nil || path == "" {
+		return iface.commonInterface.AppArmorConnectedPlug(spec, plug, slot)
+	}
+
+	// The slot is for an individual camera, only allow access to that one
+	spec.AddSnippet(fmt.Sprintf(cameraConnectedPlugAppArmorPath, filepath.Clean(path)) + cameraConnectedPlugAppArmorDetection)
+	return nil
+}
+
+func (iface *cameraInterface) UDevConnectedPlug(spec *udev.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	var path string
+	if err := slot.Attr("path", &path); err != nil || path == "" {
+		return iface.commonInterface.UDevConnectedPlug(spec, plug, slot)
+	}
+
+	// The slot is for an individual camera, only tag that one
+	spec.TagDevice(fmt.Sprintf(`SUBSYSTEM=="video4linux", KERNEL=="%s"`, strings.TrimPrefix(filepath.Clean(path), "/dev/")))
+	return nil
+}
+
+func (iface *cameraInterface) HotplugDeviceDetected(di *hotplug.HotplugDeviceInfo) (*hotplug.ProposedSlot, error) {
+	if di.Subsystem() != "video4linux" || !cameraDeviceNodePattern.MatchString(di.DeviceName()) {
+		return nil, nil
+	}
+	// cameras usually expose additional nodes for metadata, only the
+	// ones capable of capturing video are interesting
+	if caps, _ := di.Attribute("ID_V4L_CAPABILITIES"); !strings.Contains(caps, ":capture:") {
+		return nil, nil
+	}
+
+	slot := hotplug.ProposedSlot{
+		Attrs: map[string]any{
+			"path": di.DeviceName(),
+		},
+	}
+	return &slot, nil
+}
+
+func (iface *cameraInterface) HotplugKey(di *hotplug.HotplugDeviceInfo) (snap.HotplugKey, error) {
+	return hotplugKeyFromAttrs(di, usbInterfaceKeyAttrs)
+}
+
 func init() {
-	registerIface(&commonInterface{
+	registerIface(&cameraInterface{commonInterface: commonInterface{
 		name:                  "camera",
 		summary:               cameraSummary,
 		implicitOnCore:        true,
@@ -82,5 +167,5 @@ func init() {
 		baseDeclarationSlots:  cameraBaseDeclarationSlots,
 		connectedPlugAppArmor: cameraConnectedPlugAppArmor,
 		connectedPlugUDev:     cameraConnectedPlugUDev,
-	})
+	}})
 }
@@ -28,8 +28,10 @@ import (
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
 	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/hotplug"
 	"github.com/snapcore/snapd/interfaces/udev"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/testutil"
 )
 
@@ -133,3 +135,89 @@ func (s *CameraInterfaceSuite) TestDetectCameraFromPath(c *C) {
 		c.Check(builtin.DetectCameraFromPath(path), Equals, false, Commentf("%q should not be detected as camera path"))
 	}
 }
+
+const cameraHotplugSlotYaml = `name: core
+version: 0
+type: os
+slots:
+  webcam:
+    interface: camera
+    path: /dev/video2
+`
+
+func (s *CameraInterfaceSuite) TestSanitizeDeviceSlot(c *C) {
+	info := snaptest.MockInfo(c, cameraHotplugSlotYaml, nil)
+	c.Assert(interfaces.BeforePrepareSlot(s.iface, info.Slots["webcam"]), IsNil)
+
+	for _, path := range []string{`""`, "/dev/vchiq", "/dev/video", "/dev/video1234", "/dev/ttyUSB0"} {
+		info := snaptest.MockInfo(c, fmt.Sprintf("name: core\nversion: 0\ntype: os\nslots:\n  webcam:\n    interface: camera\n    path: %s\n", path), nil)
+		c.Check(interfaces.BeforePrepareSlot(s.iface, info.Slots["webcam"]), ErrorMatches, `camera path attribute must be a valid device node`, Commentf("%s", path))
+	}
+}
+
+func (s *CameraInterfaceSuite) TestAppArmorSpecDeviceSlot(c *C) {
+	slot, _ := MockConnectedSlot(c, cameraHotplugSlotYaml, nil, "webcam")
+	appSet, err := interfaces.NewSnapAppSet(s.plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec := apparmor.NewSpecification(appSet)
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, slot), IsNil)
+	c.Assert(spec.SecurityTags(), DeepEquals, []string{"snap.consumer.app"})
+	snippet := spec.SnippetForTag("snap.consumer.app")
+	c.Check(snippet, testutil.Contains, "###PROMPT### /dev/video2 rwk,")
+	c.Check(snippet, testutil.Contains, "/sys/bus/usb/devices/ r,")
+	c.Check(snippet, Not(testutil.Contains), "/dev/video[0-9]*")
+	c.Check(snippet, Not(testutil.Contains), "/dev/vchiq")
+}
+
+func (s *CameraInterfaceSuite) TestUDevSpecDeviceSlot(c *C) {
+	slot, _ := MockConnectedSlot(c, cameraHotplugSlotYaml, nil, "webcam")
+	appSet, err := interfaces.NewSnapAppSet(s.plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec := udev.NewSpecification(appSet)
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, slot), IsNil)
+	c.Assert(spec.Snippets(), HasLen, 2)
+	c.Assert(spec.Snippets(), testutil.Contains, `# camera
+SUBSYSTEM=="video4linux", KERNEL=="video2", TAG+="snap_consumer_app"`)
+}
+
+func (s *CameraInterfaceSuite) TestHotplugDeviceDetected(c *C) {
+	hotplugIface := s.iface.(hotplug.Definer)
+	di, err := hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo/video4linux/video2", "DEVNAME": "/dev/video2", "ID_V4L_CAPABILITIES": ":capture:", "ID_VENDOR_ID": "046d", "ID_MODEL_ID": "0825", "ACTION": "add", "SUBSYSTEM": "video4linux"})
+	c.Assert(err, IsNil)
+	proposedSlot, err := hotplugIface.HotplugDeviceDetected(di)
+	c.Assert(err, IsNil)
+	c.Assert(proposedSlot, DeepEquals, &hotplug.ProposedSlot{Attrs: map[string]any{"path": "/dev/video2"}})
+
+	// metadata nodes are ignored
+	di, err = hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo/video4linux/video3", "DEVNAME": "/dev/video3", "ID_V4L_CAPABILITIES": ":", "ACTION": "add", "SUBSYSTEM": "video4linux"})
+	c.Assert(err, IsNil)
+	proposedSlot, err = hotplugIface.HotplugDeviceDetected(di)
+	c.Assert(err, IsNil)
+	c.Check(proposedSlot, IsNil)
+
+	// so are other devices
+	di, err = hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo/vchiq", "DEVNAME": "/dev/vchiq", "ACTION": "add", "SUBSYSTEM": "vchiq"})
+	c.Assert(err, IsNil)
+	proposedSlot, err = hotplugIface.HotplugDeviceDetected(di)
+	c.Assert(err, IsNil)
+	c.Check(proposedSlot, IsNil)
+}
+
+func (s *CameraInterfaceSuite) TestHotplugKey(c *C) {
+	keyHandler := s.iface.(hotplug.HotplugKeyHandler)
+	env := map[string]string{"DEVPATH": "/sys/foo", "SUBSYSTEM": "video4linux", "ID_VENDOR_ID": "046d", "ID_MODEL_ID": "0825", "ID_SERIAL_SHORT": "ABCD", "ID_USB_INTERFACE_NUM": "00"}
+	di, err := hotplug.NewHotplugDeviceInfo(env)
+	c.Assert(err, IsNil)
+	key, err := keyHandler.HotplugKey(di)
+	c.Assert(err, IsNil)
+	c.Check(key, Not(Equals), snap.HotplugKey(""))
+
+	// another interface of the same device gets a different key
+	env["ID_USB_INTERFACE_NUM"] = "02"
+	di, err = hotplug.NewHotplugDeviceInfo(env)
+	c.Assert(err, IsNil)
+	otherKey, err := keyHandler.HotplugKey(di)
+	c.Assert(err, IsNil)
+	c.Check(otherKey, Not(Equals), snap.HotplugKey(""))
+	c.Check(otherKey, Not(Equals), key)
+}
@@ -27,7 +27,9 @@ import (
 
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/landlock"
 	apparmor_sandbox "github.com/snapcore/snapd/sandbox/apparmor"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/snap"
 )
 
@@ -172,3 +174,23 @@ func (iface *commonFilesInterface) AppArmorConnectedPlug(spec *apparmor.Specific
 
 	return nil
 }
+
+func (iface *commonFilesInterface) LandlockConnectedPlug(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	var reads, writes []any
+	_ = plug.Attr("read", &reads)
+	_ = plug.Attr("write", &writes)
+
+	// $HOME is kept as a variable, it is expanded when the ruleset is
+	// applied
+	for _, p := range reads {
+		if p, ok := p.(string); ok {
+			spec.AddPath(p, landlock_sandbox.AccessRead)
+		}
+	}
+	for _, p := range writes {
+		if p, ok := p.(string); ok {
+			spec.AddPath(p, landlock_sandbox.AccessRead|landlock_sandbox.AccessWrite)
+		}
+	}
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/hotplug"
 	"github.com/snapcore/snapd/interfaces/udev"
 	"github.com/snapcore/snapd/snap"
 )
@@ -193,6 +194,42 @@ func (iface *hidrawInterface) AutoConnect(*snap.PlugInfo, *snap.SlotInfo) bool {
 	return true
 }
 
+func (iface *hidrawInterface) HotplugDeviceDetected(di *hotplug.HotplugDeviceInfo) (*hotplug.ProposedSlot, error) {
+	if di.Subsystem() != "hidraw" || !hidrawDeviceNodePattern.MatchString(di.DeviceName()) {
+		return nil, nil
+	}
+
+	// The device node may change when the device is plugged again, in
+	// which case the slot is updated by the hotplug subsystem.
+	slot := hotplug.ProposedSlot{
+		Attrs: map[string]any{
+			"path": di.DeviceName(),
+		},
+	}
+	return &slot, nil
+}
+
+func (iface *hidrawInterface) HotplugKey(di *hotplug.HotplugDeviceInfo) (snap.HotplugKey, error) {
+	return hotplugKeyFromAttrs(di, usbInterfaceKeyAttrs)
+}
+
+func (iface *hidrawInterface) HandledByGadget(di *hotplug.HotplugDeviceInfo, slot *snap.SlotInfo) bool {
+	// if the slot has vendor and product set, check if they match
+	var usbVendor, usbProduct int64
+	if err := slot.Attr("usb-vendor", &usbVendor); err == nil {
+		if err := slot.Attr("usb-product", &usbProduct); err != nil {
+			return false
+		}
+		return slotDeviceAttrEqual(di, "ID_VENDOR_ID", usbVendor) && slotDeviceAttrEqual(di, "ID_MODEL_ID", usbProduct)
+	}
+
+	var path string
+	if err := slot.Attr("path", &path); err != nil {
+		return false
+	}
+	return di.DeviceName() == filepath.Clean(path)
+}
+
 func (iface *hidrawInterface) hasUsbAttrs(attrs interfaces.Attrer) bool {
 	var v int64
 	if err := attrs.Attr("usb-vendor", &v); err == nil {
@@ -28,6 +28,7 @@ import (
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
 	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/hotplug"
 	"github.com/snapcore/snapd/interfaces/udev"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
@@ -377,3 +378,57 @@ SUBSYSTEM=="hidraw", SUBSYSTEMS=="usb", ATTRS{idVendor}=="ffff", ATTRS{idProduct
 func (s *HidrawInterfaceSuite) TestInterfaces(c *C) {
 	c.Check(builtin.Interfaces(), testutil.DeepContains, s.iface)
 }
+
+func (s *HidrawInterfaceSuite) TestHotplugDeviceDetected(c *C) {
+	hotplugIface := s.iface.(hotplug.Definer)
+	di, err := hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo/hidraw/hidraw3", "DEVNAME": "/dev/hidraw3", "ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ACTION": "add", "SUBSYSTEM": "hidraw"})
+	c.Assert(err, IsNil)
+	proposedSlot, err := hotplugIface.HotplugDeviceDetected(di)
+	c.Assert(err, IsNil)
+	c.Assert(proposedSlot, DeepEquals, &hotplug.ProposedSlot{Attrs: map[string]any{"path": "/dev/hidraw3"}})
+
+	// the proposed slot is valid
+	slot := &snap.SlotInfo{Snap: s.osSnapInfo, Name: "hidraw", Interface: "hidraw", Attrs: proposedSlot.Attrs}
+	c.Check(interfaces.BeforePrepareSlot(s.iface, slot), IsNil)
+
+	di, err = hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo/hidraw/other", "DEVNAME": "/dev/other", "ACTION": "add", "SUBSYSTEM": "hidraw"})
+	c.Assert(err, IsNil)
+	proposedSlot, err = hotplugIface.HotplugDeviceDetected(di)
+	c.Assert(err, IsNil)
+	c.Check(proposedSlot, IsNil)
+}
+
+func (s *HidrawInterfaceSuite) TestHotplugKey(c *C) {
+	keyHandler := s.iface.(hotplug.HotplugKeyHandler)
+	di, err := hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo", "DEVNAME": "/dev/hidraw3", "SUBSYSTEM": "hidraw", "ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ID_SERIAL_SHORT": "0001", "ID_USB_INTERFACE_NUM": "00"})
+	c.Assert(err, IsNil)
+	key, err := keyHandler.HotplugKey(di)
+	c.Assert(err, IsNil)
+	c.Check(key, Not(Equals), snap.HotplugKey(""))
+
+	// the same device gets the same key under another device node
+	di, err = hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/bar", "DEVNAME": "/dev/hidraw5", "SUBSYSTEM": "hidraw", "ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ID_SERIAL_SHORT": "0001", "ID_USB_INTERFACE_NUM": "00"})
+	c.Assert(err, IsNil)
+	sameKey, err := keyHandler.HotplugKey(di)
+	c.Assert(err, IsNil)
+	c.Check(sameKey, Equals, key)
+
+	// the default key is used when the device cannot be identified
+	di, err = hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo", "DEVNAME": "/dev/hidraw3", "SUBSYSTEM": "hidraw"})
+	c.Assert(err, IsNil)
+	key, err = keyHandler.HotplugKey(di)
+	c.Assert(err, IsNil)
+	c.Check(key, Equals, snap.HotplugKey(""))
+}
+
+func (s *HidrawInterfaceSuite) TestHotplugHandledByGadget(c *C) {
+	byGadgetPred := s.iface.(hotplug.HandledByGadgetPredicate)
+	di, err := hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo", "DEVNAME": "/dev/hidraw0", "ID_VENDOR_ID": "0001", "ID_MODEL_ID": "0001", "SUBSYSTEM": "hidraw"})
+	c.Assert(err, IsNil)
+	// path matches
+	c.Check(byGadgetPred.HandledByGadget(di, s.testSlot1Info), Equals, true)
+	c.Check(byGadgetPred.HandledByGadget(di, s.testSlot2Info), Equals, false)
+	// usb vendor and product match
+	c.Check(byGadgetPred.HandledByGadget(di, s.testUDev1Info), Equals, true)
+	c.Check(byGadgetPred.HandledByGadget(di, s.testUDev2Info), Equals, false)
+}
@@ -24,6 +24,8 @@ import (
 
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/landlock"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/snap"
 )
 
@@ -129,6 +131,39 @@ func (iface *homeInterface) AppArmorConnectedPlug(spec *apparmor.Specification,
 	return nil
 }
 
+func (iface *homeInterface) LandlockConnectedPlug(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	var read string
+	_ = plug.Attr("read", &read)
+	// Landlock rules apply to whole directory trees, access is granted
+	// to the entries of $HOME matched by the AppArmor rules, which
+	// leaves out the hidden files and $HOME/snap. $HOME/bin cannot be
+	// excluded like with AppArmor.
+	for _, p := range homeLandlockPaths {
+		spec.AddPath("$HOME/"+p, landlock_sandbox.AccessRead|landlock_sandbox.AccessWrite|landlock_sandbox.AccessExecute)
+	}
+	if read == "all" {
+		for _, p := range homeLandlockPaths {
+			spec.AddPath("/home/*/"+p, landlock_sandbox.AccessRead)
+		}
+	}
+	return nil
+}
+
+// homeLandlockPaths are the entries of the home directories the home
+// interface grants access to. The wildcards only match the entries which
+// exist when the application starts, files cannot be created directly in
+// $HOME.
+var homeLandlockPaths = []string{
+	"[^s.]*",
+	"s[^n]*",
+	"sn[^a]*",
+	"sna[^p]*",
+	"snap?*",
+	"s",
+	"sn",
+	"sna",
+}
+
 func init() {
 	registerIface(&homeInterface{commonInterface{
 		name:                 "home",
@@ -20,11 +20,16 @@
 package builtin_test
 
 import (
+	"os"
+	"path/filepath"
+
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
 	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/landlock"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/testutil"
@@ -172,6 +177,73 @@ apps:
 	c.Check(apparmorSpec.SnippetForTag("snap.home-plug-snap.app2"), testutil.Contains, `# Allow non-owner read`)
 }
 
+func (s *HomeInterfaceSuite) TestConnectedPlugLandlock(c *C) {
+	const rwx = landlock_sandbox.AccessRead | landlock_sandbox.AccessWrite | landlock_sandbox.AccessExecute
+	landlockSpec := landlock.NewSpecification(s.plug.AppSet())
+	err := landlockSpec.AddConnectedPlug(s.iface, s.plug, s.slot)
+	c.Assert(err, IsNil)
+	c.Check(landlockSpec.RulesForTag("snap.other.app"), DeepEquals, []landlock_sandbox.Rule{
+		{Access: rwx, Path: "$HOME/[^s.]*"},
+		{Access: rwx, Path: "$HOME/s"},
+		{Access: rwx, Path: "$HOME/s[^n]*"},
+		{Access: rwx, Path: "$HOME/sn"},
+		{Access: rwx, Path: "$HOME/sn[^a]*"},
+		{Access: rwx, Path: "$HOME/sna"},
+		{Access: rwx, Path: "$HOME/sna[^p]*"},
+		{Access: rwx, Path: "$HOME/snap?*"},
+	})
+
+	const mockSnapYaml = `name: home-plug-snap
+version: 1.0
+plugs:
+ home:
+  read: all
+apps:
+ app2:
+  command: foo
+`
+	plug, _ := MockConnectedPlug(c, mockSnapYaml, nil, "home")
+	landlockSpec = landlock.NewSpecification(plug.AppSet())
+	err = landlockSpec.AddConnectedPlug(s.iface, plug, s.slot)
+	c.Assert(err, IsNil)
+	rules := landlockSpec.RulesForTag("snap.home-plug-snap.app2")
+	c.Check(rules, HasLen, 16)
+	c.Check(rules, testutil.DeepContains, landlock_sandbox.Rule{Access: rwx, Path: "$HOME/[^s.]*"})
+	c.Check(rules, testutil.DeepContains, landlock_sandbox.Rule{Access: landlock_sandbox.AccessRead, Path: "/home/*/[^s.]*"})
+	c.Check(rules, testutil.DeepContains, landlock_sandbox.Rule{Access: landlock_sandbox.AccessRead, Path: "/home/*/snap?*"})
+}
+
+func (s *HomeInterfaceSuite) TestConnectedPlugLandlockHiddenFiles(c *C) {
+	home := c.MkDir()
+	for _, p := range []string{".ssh/id_rsa", ".config/foo", "Documents/doc", "snap/other/1/data", "snapshots/foo", "sa", "s"} {
+		c.Assert(os.MkdirAll(filepath.Join(home, filepath.Dir(p)), 0755), IsNil)
+		c.Assert(os.WriteFile(filepath.Join(home, p), nil, 0644), IsNil)
+	}
+
+	landlockSpec := landlock.NewSpecification(s.plug.AppSet())
+	c.Assert(landlockSpec.AddConnectedPlug(s.iface, s.plug, s.slot), IsNil)
+	rules := landlock_sandbox.ExpandRules(landlockSpec.RulesForTag("snap.other.app"), func(name string) string {
+		if name == "HOME" {
+			return home
+		}
+		return ""
+	})
+	var paths []string
+	for _, r := range rules {
+		paths = append(paths, r.Path)
+	}
+	// $HOME itself, the hidden files and $HOME/snap are not accessible,
+	// the paths without wildcards are kept even if they do not exist
+	c.Check(paths, DeepEquals, []string{
+		filepath.Join(home, "Documents"),
+		filepath.Join(home, "s"),
+		filepath.Join(home, "sa"),
+		filepath.Join(home, "sn"),
+		filepath.Join(home, "sna"),
+		filepath.Join(home, "snapshots"),
+	})
+}
+
 func (s *HomeInterfaceSuite) TestInterfaces(c *C) {
 	c.Check(builtin.Interfaces(), testutil.DeepContains, s.iface)
 }
@@ -0,0 +1,99 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package builtin
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+
+	"github.com/snapcore/snapd/interfaces/hotplug"
+	"github.com/snapcore/snapd/snap"
+)
+
+// Attributes identifying a USB device (or an interface of it) across
+// reboots and re-plugging, grouped by similarity. The first non-empty
+// attribute within each group goes into the key.
+var usbDeviceKeyAttrs = [][]string{
+	{"ID_VENDOR_ID"},
+	{"ID_MODEL_ID"},
+	// prefer the serial number, so that the device keeps its key when
+	// moved to another port, but fall back to the physical port for
+	// devices that have none
+	{"ID_SERIAL_SHORT", "ID_PATH"},
+}
+
+var usbInterfaceKeyAttrs = append(usbDeviceKeyAttrs[:len(usbDeviceKeyAttrs):len(usbDeviceKeyAttrs)],
+	[]string{"ID_USB_INTERFACE_NUM"})
+
+// hotplugKeyFromAttrs computes a hotplug key for the given device from its
+// subsystem and the first non-empty attribute of each of the attribute
+// groups. An empty key is returned if any of the groups has no value for the
+// device, in which case the hotplug subsystem falls back to its default key.
+// Warning, changing the attributes used for an interface changes the keys of
+// its existing hotplug slots.
+func hotplugKeyFromAttrs(di *hotplug.HotplugDeviceInfo, attrGroups [][]string) (snap.HotplugKey, error) {
+	key := sha256.New()
+	key.Write([]byte(di.Subsystem()))
+	key.Write([]byte{0})
+	for _, group := range attrGroups {
+		found := false
+		for _, attr := range group {
+			if val, ok := di.Attribute(attr); ok && val != "" {
+				key.Write([]byte(attr))
+				key.Write([]byte{0})
+				key.Write([]byte(val))
+				key.Write([]byte{0})
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", nil
+		}
+	}
+	return snap.HotplugKey(fmt.Sprintf("%x", key.Sum(nil))), nil
+}
+
+// hotplugUsbIDs returns the USB vendor and product identifiers of the device,
+// if present and valid.
+func hotplugUsbIDs(di *hotplug.HotplugDeviceInfo) (vendor, product int64, ok bool) {
+	if vendor, ok = hexDeviceAttr(di, "ID_VENDOR_ID"); !ok {
+		return 0, 0, false
+	}
+	if product, ok = hexDeviceAttr(di, "ID_MODEL_ID"); !ok {
+		return 0, 0, false
+	}
+	return vendor, product, true
+}
+
+// hexDeviceAttr returns the value of a hexadecimal attribute of the device,
+// such as the USB vendor and product identifiers.
+func hexDeviceAttr(di *hotplug.HotplugDeviceInfo, devinfoAttribute string) (int64, bool) {
+	attr, ok := di.Attribute(devinfoAttribute)
+	if !ok {
+		return 0, false
+	}
+	val, err := strconv.ParseInt(attr, 16, 64)
+	if err != nil {
+		return 0, false
+	}
+	return val, true
+}
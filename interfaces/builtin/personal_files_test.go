@@ -27,8 +27,10 @@ import (
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
 	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/landlock"
 	"github.com/snapcore/snapd/interfaces/mount"
 	"github.com/snapcore/snapd/osutil"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/testutil"
@@ -100,6 +102,23 @@ owner "@{HOME}/.local/share/dir1/dir2/target{,/,/**}" rwkl,
   owner @{HOME}/.local/share/dir1/dir2/ rw,`)
 }
 
+func (s *personalFilesInterfaceSuite) TestConnectedPlugLandlock(c *C) {
+	landlockSpec := landlock.NewSpecification(s.plug.AppSet())
+	err := landlockSpec.AddConnectedPlug(s.iface, s.plug, s.slot)
+	c.Assert(err, IsNil)
+	c.Assert(landlockSpec.SecurityTags(), DeepEquals, []string{"snap.other.app"})
+	r := landlock_sandbox.AccessRead
+	rw := landlock_sandbox.AccessRead | landlock_sandbox.AccessWrite
+	c.Check(landlockSpec.RulesForTag("snap.other.app"), DeepEquals, []landlock_sandbox.Rule{
+		{Access: rw, Path: "$HOME/.local/share/dir1/dir2/target"},
+		{Access: rw, Path: "$HOME/.local/share/target"},
+		{Access: r, Path: "$HOME/.read-dir"},
+		{Access: r, Path: "$HOME/.read-file"},
+		{Access: rw, Path: "$HOME/.write-dir"},
+		{Access: rw, Path: "$HOME/.write-file"},
+	})
+}
+
 func (s *personalFilesInterfaceSuite) TestConnectedPlugApparmorErrorNotString(c *C) {
 	const mockPlugSnapInfo = `name: other
 version: 1.0
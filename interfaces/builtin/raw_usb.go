@@ -19,6 +19,18 @@
 
 package builtin
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/hotplug"
+	"github.com/snapcore/snapd/interfaces/udev"
+	"github.com/snapcore/snapd/snap"
+)
+
 const rawusbSummary = `allows raw access to all USB devices`
 
 const rawusbBaseDeclarationSlots = `
@@ -40,7 +52,27 @@ const rawusbConnectedPlugAppArmor = `
 
 # Allow raw access to USB printers (i.e. for receipt printers in POS systems).
 /dev/usb/lp[0-9]* rwk,
+` + rawusbConnectedPlugAppArmorDetection + `
+/run/udev/data/c16[67]:[0-9] r, # ACM USB modems
+/run/udev/data/b180:*    r, # various USB block devices
+/run/udev/data/c18[089]:* r, # various USB character devices: USB serial converters, etc.
+/run/udev/data/+usb:* r,
+`
+
+// Rules for slots of individual USB devices, the device node is either the
+// one of the path attribute or, for slots identifying the device by vendor
+// and product only, an approximation of rawusbDeviceNodePattern. UDev
+// tagging and device cgroups restrict access to the specific device.
+const rawusbConnectedPlugAppArmorDevice = `
+# Description: Allow raw access to an individual USB device.
+# This gives privileged access to the system.
+%s rw,
+` + rawusbConnectedPlugAppArmorDetection + `
+/run/udev/data/c189:* r,
+/run/udev/data/+usb:* r,
+`
 
+const rawusbConnectedPlugAppArmorDetection = `
 # Allow detection of usb devices. Leaks plugged in USB device info
 /sys/bus/usb/devices/ r,
 /sys/devices/pci**/usb[0-9]** r,
@@ -50,11 +82,6 @@ const rawusbConnectedPlugAppArmor = `
 /sys/devices/platform/scb/*.pcie/pci**/usb[0-9]** r,
 /sys/devices/platform/axi/*.pcie/*.usb/xhci-hcd.[0-9]*/usb[0-9]** r,
 /sys/devices/platform/axi/*.usb/usb[0-9]** r,
-
-/run/udev/data/c16[67]:[0-9] r, # ACM USB modems
-/run/udev/data/b180:*    r, # various USB block devices
-/run/udev/data/c18[089]:* r, # various USB character devices: USB serial converters, etc.
-/run/udev/data/+usb:* r,
 `
 
 const rawusbConnectedPlugSecComp = `
@@ -71,8 +98,125 @@ var rawusbConnectedPlugUDev = []string{
 	`SUBSYSTEM=="tty", ENV{ID_BUS}=="usb"`,
 }
 
+// rawUsbInterface is the type for the raw-usb interface. Besides the
+// implicit slot giving access to all USB devices, slots for individual USB
+// devices are created by the hotplug subsystem.
+type rawUsbInterface struct {
+	commonInterface
+}
+
+// Pattern to match the device nodes of USB devices, the path attribute of
+// slots for individual devices is compared to this for validity.
+var rawusbDeviceNodePattern = regexp.MustCompile("^/dev/bus/usb/[0-9]{3}/[0-9]{3}$")
+
+// BeforePrepareSlot checks validity of the slot attributes identifying an
+// individual USB device, if any.
+func (iface *rawUsbInterface) BeforePrepareSlot(slot *snap.SlotInfo) error {
+	if !iface.hasUsbAttrs(slot) {
+		return nil
+	}
+
+	usbVendor, ok := slot.Attrs["usb-vendor"].(int64)
+	if !ok {
+		return fmt.Errorf("raw-usb slot failed to find usb-vendor attribute")
+	}
+	if (usbVendor < 0x1) || (usbVendor > 0xFFFF) {
+		return fmt.Errorf("raw-usb usb-vendor attribute not valid: %d", usbVendor)
+	}
+	usbProduct, ok := slot.Attrs["usb-product"].(int64)
+	if !ok {
+		return fmt.Errorf("raw-usb slot failed to find usb-product attribute")
+	}
+	if (usbProduct < 0x0) || (usbProduct > 0xFFFF) {
+		return fmt.Errorf("raw-usb usb-product attribute not valid: %d", usbProduct)
+	}
+	if path, ok := slot.Attrs["path"]; ok {
+		path, ok := path.(string)
+		if !ok || !rawusbDeviceNodePattern.MatchString(path) {
+			return fmt.Errorf("raw-usb path attribute must be a valid device node")
+		}
+	}
+	return nil
+}
+
+func (iface *rawUsbInterface) AppArmorConnectedPlug(spec *apparmor.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	if !iface.hasUsbAttrs(slot) {
+		return iface.commonInterface.AppArmorConnectedPlug(spec, plug, slot)
+	}
+
+	// The slot is for an individual device, only allow access to that one
+	node := "/dev/bus/usb/[0-9][0-9][0-9]/[0-9][0-9][0-9]"
+	var path string
+	if err := slot.Attr("path", &path); err == nil && path != "" {
+		node = path
+	}
+	spec.AddSnippet(fmt.Sprintf(rawusbConnectedPlugAppArmorDevice, node))
+	return nil
+}
+
+func (iface *rawUsbInterface) UDevConnectedPlug(spec *udev.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	if !iface.hasUsbAttrs(slot) {
+		return iface.commonInterface.UDevConnectedPlug(spec, plug, slot)
+	}
+
+	// The slot is for an individual device, only tag that one
+	var usbVendor, usbProduct int64
+	if err := slot.Attr("usb-vendor", &usbVendor); err != nil {
+		return nil
+	}
+	if err := slot.Attr("usb-product", &usbProduct); err != nil {
+		return nil
+	}
+	var path string
+	if err := slot.Attr("path", &path); err == nil && path != "" {
+		spec.TagDevice(fmt.Sprintf(`SUBSYSTEM=="usb", ATTR{idVendor}=="%04x", ATTR{idProduct}=="%04x", ENV{DEVNAME}=="%s"`,
+			usbVendor, usbProduct, path))
+	} else {
+		spec.TagDevice(fmt.Sprintf(`SUBSYSTEM=="usb", ATTR{idVendor}=="%04x", ATTR{idProduct}=="%04x"`, usbVendor, usbProduct))
+	}
+	return nil
+}
+
+func (iface *rawUsbInterface) HotplugDeviceDetected(di *hotplug.HotplugDeviceInfo) (*hotplug.ProposedSlot, error) {
+	if di.Subsystem() != "usb" || di.DeviceType() != "usb_device" || !rawusbDeviceNodePattern.MatchString(di.DeviceName()) {
+		return nil, nil
+	}
+	// hubs are not interesting on their own, the devices behind them are
+	if typ, _ := di.Attribute("TYPE"); strings.HasPrefix(typ, "9/") {
+		return nil, nil
+	}
+	usbVendor, usbProduct, ok := hotplugUsbIDs(di)
+	if !ok {
+		return nil, nil
+	}
+
+	slot := hotplug.ProposedSlot{
+		Attrs: map[string]any{
+			"path":        di.DeviceName(),
+			"usb-vendor":  usbVendor,
+			"usb-product": usbProduct,
+		},
+	}
+	return &slot, nil
+}
+
+func (iface *rawUsbInterface) HotplugKey(di *hotplug.HotplugDeviceInfo) (snap.HotplugKey, error) {
+	return hotplugKeyFromAttrs(di, usbDeviceKeyAttrs)
+}
+
+func (iface *rawUsbInterface) hasUsbAttrs(attrs interfaces.Attrer) bool {
+	var v int64
+	if err := attrs.Attr("usb-vendor", &v); err == nil {
+		return true
+	}
+	if err := attrs.Attr("usb-product", &v); err == nil {
+		return true
+	}
+	return false
+}
+
 func init() {
-	registerIface(&commonInterface{
+	registerIface(&rawUsbInterface{commonInterface: commonInterface{
 		name:                  "raw-usb",
 		summary:               rawusbSummary,
 		implicitOnCore:        true,
@@ -81,5 +225,5 @@ func init() {
 		connectedPlugAppArmor: rawusbConnectedPlugAppArmor,
 		connectedPlugSecComp:  rawusbConnectedPlugSecComp,
 		connectedPlugUDev:     rawusbConnectedPlugUDev,
-	})
+	}})
 }
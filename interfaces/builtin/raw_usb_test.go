@@ -28,9 +28,11 @@ import (
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
 	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/hotplug"
 	"github.com/snapcore/snapd/interfaces/seccomp"
 	"github.com/snapcore/snapd/interfaces/udev"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/testutil"
 )
 
@@ -125,3 +127,117 @@ func (s *RawUsbInterfaceSuite) TestAutoConnect(c *C) {
 func (s *RawUsbInterfaceSuite) TestInterfaces(c *C) {
 	c.Check(builtin.Interfaces(), testutil.DeepContains, s.iface)
 }
+
+const rawusbHotplugSlotYaml = `name: core
+version: 0
+type: os
+slots:
+  device:
+    interface: raw-usb
+    path: /dev/bus/usb/001/005
+    usb-vendor: 0x1050
+    usb-product: 0x0407
+`
+
+func (s *RawUsbInterfaceSuite) TestSanitizeDeviceSlot(c *C) {
+	info := snaptest.MockInfo(c, rawusbHotplugSlotYaml, nil)
+	c.Assert(interfaces.BeforePrepareSlot(s.iface, info.Slots["device"]), IsNil)
+
+	for _, tc := range []struct {
+		attrs string
+		err   string
+	}{
+		{"path: /dev/bus/usb/001/005\n    usb-product: 0x0407", `raw-usb slot failed to find usb-vendor attribute`},
+		{"usb-vendor: 0x1050", `raw-usb slot failed to find usb-product attribute`},
+		{"usb-vendor: 0x10000\n    usb-product: 0x0407", `raw-usb usb-vendor attribute not valid: 65536`},
+		{"usb-vendor: 0x1050\n    usb-product: -1", `raw-usb usb-product attribute not valid: -1`},
+		{"usb-vendor: 0x1050\n    usb-product: 0x0407\n    path: /dev/ttyUSB0", `raw-usb path attribute must be a valid device node`},
+	} {
+		info := snaptest.MockInfo(c, fmt.Sprintf("name: core\nversion: 0\ntype: os\nslots:\n  device:\n    interface: raw-usb\n    %s\n", tc.attrs), nil)
+		c.Check(interfaces.BeforePrepareSlot(s.iface, info.Slots["device"]), ErrorMatches, tc.err, Commentf("%s", tc.attrs))
+	}
+}
+
+func (s *RawUsbInterfaceSuite) TestAppArmorSpecDeviceSlot(c *C) {
+	slot, _ := MockConnectedSlot(c, rawusbHotplugSlotYaml, nil, "device")
+	appSet, err := interfaces.NewSnapAppSet(s.plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec := apparmor.NewSpecification(appSet)
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, slot), IsNil)
+	c.Assert(spec.SecurityTags(), DeepEquals, []string{"snap.consumer.app"})
+	snippet := spec.SnippetForTag("snap.consumer.app")
+	c.Check(snippet, testutil.Contains, "/dev/bus/usb/001/005 rw,")
+	c.Check(snippet, testutil.Contains, "/sys/bus/usb/devices/ r,")
+	c.Check(snippet, Not(testutil.Contains), "/dev/bus/usb/[0-9][0-9][0-9]/[0-9][0-9][0-9] rw,")
+	c.Check(snippet, Not(testutil.Contains), "/dev/tty{USB,ACM}[0-9]* rwk,")
+	c.Check(snippet, Not(testutil.Contains), "/dev/usb/lp[0-9]* rwk,")
+}
+
+func (s *RawUsbInterfaceSuite) TestUDevSpecDeviceSlot(c *C) {
+	slot, _ := MockConnectedSlot(c, rawusbHotplugSlotYaml, nil, "device")
+	appSet, err := interfaces.NewSnapAppSet(s.plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec := udev.NewSpecification(appSet)
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, slot), IsNil)
+	c.Assert(spec.Snippets(), HasLen, 2)
+	c.Assert(spec.Snippets(), testutil.Contains, `# raw-usb
+SUBSYSTEM=="usb", ATTR{idVendor}=="1050", ATTR{idProduct}=="0407", ENV{DEVNAME}=="/dev/bus/usb/001/005", TAG+="snap_consumer_app"`)
+}
+
+func (s *RawUsbInterfaceSuite) TestHotplugDeviceDetected(c *C) {
+	hotplugIface := s.iface.(hotplug.Definer)
+	di, err := hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/devices/pci0000:00/0000:00:14.0/usb1/1-2", "DEVNAME": "/dev/bus/usb/001/005", "DEVTYPE": "usb_device", "TYPE": "0/0/0", "ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ACTION": "add", "SUBSYSTEM": "usb"})
+	c.Assert(err, IsNil)
+	proposedSlot, err := hotplugIface.HotplugDeviceDetected(di)
+	c.Assert(err, IsNil)
+	c.Assert(proposedSlot, DeepEquals, &hotplug.ProposedSlot{Attrs: map[string]any{"path": "/dev/bus/usb/001/005", "usb-vendor": int64(0x1050), "usb-product": int64(0x0407)}})
+}
+
+func (s *RawUsbInterfaceSuite) TestHotplugDeviceDetectedIgnored(c *C) {
+	hotplugIface := s.iface.(hotplug.Definer)
+	for _, env := range []map[string]string{
+		// usb interface rather than device
+		{"DEVPATH": "/sys/foo/1-2:1.0", "DEVTYPE": "usb_interface", "ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "SUBSYSTEM": "usb"},
+		// hub
+		{"DEVPATH": "/sys/foo/1-1", "DEVNAME": "/dev/bus/usb/001/002", "DEVTYPE": "usb_device", "TYPE": "9/0/1", "ID_VENDOR_ID": "05e3", "ID_MODEL_ID": "0610", "SUBSYSTEM": "usb"},
+		// no identifiers
+		{"DEVPATH": "/sys/foo/1-2", "DEVNAME": "/dev/bus/usb/001/005", "DEVTYPE": "usb_device", "SUBSYSTEM": "usb"},
+		// other subsystem
+		{"DEVPATH": "/sys/foo/ttyUSB0", "DEVNAME": "/dev/ttyUSB0", "ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "SUBSYSTEM": "tty"},
+	} {
+		di, err := hotplug.NewHotplugDeviceInfo(env)
+		c.Assert(err, IsNil)
+		proposedSlot, err := hotplugIface.HotplugDeviceDetected(di)
+		c.Assert(err, IsNil)
+		c.Check(proposedSlot, IsNil, Commentf("%v", env))
+	}
+}
+
+func (s *RawUsbInterfaceSuite) TestHotplugKey(c *C) {
+	keyHandler := s.iface.(hotplug.HotplugKeyHandler)
+	key := func(env map[string]string) snap.HotplugKey {
+		env["SUBSYSTEM"] = "usb"
+		env["DEVPATH"] = "/sys/foo"
+		di, err := hotplug.NewHotplugDeviceInfo(env)
+		c.Assert(err, IsNil)
+		key, err := keyHandler.HotplugKey(di)
+		c.Assert(err, IsNil)
+		return key
+	}
+
+	withSerial := key(map[string]string{"ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ID_SERIAL_SHORT": "0001", "ID_PATH": "pci-0000:00:14.0-usb-0:2"})
+	c.Check(withSerial, Not(Equals), snap.HotplugKey(""))
+	// the key does not depend on the port when the device has a serial
+	c.Check(key(map[string]string{"ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ID_SERIAL_SHORT": "0001", "ID_PATH": "pci-0000:00:14.0-usb-0:3"}), Equals, withSerial)
+	c.Check(key(map[string]string{"ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ID_SERIAL_SHORT": "0002", "ID_PATH": "pci-0000:00:14.0-usb-0:2"}), Not(Equals), withSerial)
+
+	// otherwise the port identifies the device
+	withPath := key(map[string]string{"ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ID_PATH": "pci-0000:00:14.0-usb-0:2"})
+	c.Check(withPath, Not(Equals), snap.HotplugKey(""))
+	c.Check(withPath, Not(Equals), withSerial)
+	c.Check(key(map[string]string{"ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407", "ID_PATH": "pci-0000:00:14.0-usb-0:3"}), Not(Equals), withPath)
+
+	// the default key is used when the device cannot be identified
+	c.Check(key(map[string]string{"ID_VENDOR_ID": "1050", "ID_MODEL_ID": "0407"}), Equals, snap.HotplugKey(""))
+	c.Check(key(map[string]string{"ID_VENDOR_ID": "1050", "ID_SERIAL_SHORT": "0001"}), Equals, snap.HotplugKey(""))
+}
@@ -21,11 +21,13 @@ package builtin
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/hotplug"
 	"github.com/snapcore/snapd/interfaces/udev"
 	"github.com/snapcore/snapd/snap"
 )
@@ -154,6 +156,57 @@ func (iface *rawVolumeInterface) AutoConnect(*snap.PlugInfo, *snap.SlotInfo) boo
 	return true
 }
 
+// Attributes identifying a partition of a removable disk across reboots
+// and re-plugging, see hotplugKeyFromAttrs.
+var rawVolumeKeyAttrs = [][]string{
+	{"ID_SERIAL_SHORT", "ID_SERIAL"},
+	{"ID_PART_ENTRY_UUID", "ID_PART_ENTRY_NUMBER"},
+}
+
+// isRemovableDisk returns whether the block device belongs to a disk that can
+// be plugged and unplugged at runtime, such as USB sticks and SD cards.
+func isRemovableDisk(di *hotplug.HotplugDeviceInfo) bool {
+	if bus, _ := di.Attribute("ID_BUS"); bus == "usb" {
+		return true
+	}
+	for _, attr := range []string{"ID_DRIVE_FLASH_SD", "ID_DRIVE_MEDIA_FLASH_SD"} {
+		if val, _ := di.Attribute(attr); val == "1" {
+			return true
+		}
+	}
+	return false
+}
+
+func (iface *rawVolumeInterface) HotplugDeviceDetected(di *hotplug.HotplugDeviceInfo) (*hotplug.ProposedSlot, error) {
+	// only partitions of removable disks get a slot, fixed disks are
+	// expected to be described by the gadget
+	if di.Subsystem() != "block" || di.DeviceType() != "partition" || !isRemovableDisk(di) {
+		return nil, nil
+	}
+	if !rawVolumePartitionPattern.MatchString(di.DeviceName()) {
+		return nil, nil
+	}
+
+	slot := hotplug.ProposedSlot{
+		Attrs: map[string]any{
+			"path": di.DeviceName(),
+		},
+	}
+	return &slot, nil
+}
+
+func (iface *rawVolumeInterface) HotplugKey(di *hotplug.HotplugDeviceInfo) (snap.HotplugKey, error) {
+	return hotplugKeyFromAttrs(di, rawVolumeKeyAttrs)
+}
+
+func (iface *rawVolumeInterface) HandledByGadget(di *hotplug.HotplugDeviceInfo, slot *snap.SlotInfo) bool {
+	path, ok := slot.Attrs["path"].(string)
+	if !ok {
+		return false
+	}
+	return di.DeviceName() == filepath.Clean(path)
+}
+
 func init() {
 	registerIface(&rawVolumeInterface{})
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
 	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/hotplug"
 	"github.com/snapcore/snapd/interfaces/udev"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
@@ -354,3 +355,61 @@ func (s *rawVolumeInterfaceSuite) TestAutoConnect(c *C) {
 func (s *rawVolumeInterfaceSuite) TestInterfaces(c *C) {
 	c.Check(builtin.Interfaces(), testutil.DeepContains, s.iface)
 }
+
+func (s *rawVolumeInterfaceSuite) TestHotplugDeviceDetected(c *C) {
+	hotplugIface := s.iface.(hotplug.Definer)
+	for _, env := range []map[string]string{
+		{"DEVPATH": "/sys/foo/sdb/sdb1", "DEVNAME": "/dev/sdb1", "DEVTYPE": "partition", "ID_BUS": "usb", "SUBSYSTEM": "block"},
+		{"DEVPATH": "/sys/foo/mmcblk1/mmcblk1p2", "DEVNAME": "/dev/mmcblk1p2", "DEVTYPE": "partition", "ID_DRIVE_FLASH_SD": "1", "SUBSYSTEM": "block"},
+	} {
+		di, err := hotplug.NewHotplugDeviceInfo(env)
+		c.Assert(err, IsNil)
+		proposedSlot, err := hotplugIface.HotplugDeviceDetected(di)
+		c.Assert(err, IsNil)
+		c.Check(proposedSlot, DeepEquals, &hotplug.ProposedSlot{Attrs: map[string]any{"path": env["DEVNAME"]}})
+	}
+
+	for _, env := range []map[string]string{
+		// whole disk
+		{"DEVPATH": "/sys/foo/sdb", "DEVNAME": "/dev/sdb", "DEVTYPE": "disk", "ID_BUS": "usb", "SUBSYSTEM": "block"},
+		// fixed disk
+		{"DEVPATH": "/sys/foo/sda/sda1", "DEVNAME": "/dev/sda1", "DEVTYPE": "partition", "ID_BUS": "ata", "SUBSYSTEM": "block"},
+		// not a disk partition
+		{"DEVPATH": "/sys/foo/sr0", "DEVNAME": "/dev/sr0", "DEVTYPE": "partition", "ID_BUS": "usb", "SUBSYSTEM": "block"},
+	} {
+		di, err := hotplug.NewHotplugDeviceInfo(env)
+		c.Assert(err, IsNil)
+		proposedSlot, err := hotplugIface.HotplugDeviceDetected(di)
+		c.Assert(err, IsNil)
+		c.Check(proposedSlot, IsNil, Commentf("%v", env))
+	}
+}
+
+func (s *rawVolumeInterfaceSuite) TestHotplugKey(c *C) {
+	keyHandler := s.iface.(hotplug.HotplugKeyHandler)
+	key := func(env map[string]string) snap.HotplugKey {
+		env["SUBSYSTEM"] = "block"
+		env["DEVPATH"] = "/sys/foo"
+		di, err := hotplug.NewHotplugDeviceInfo(env)
+		c.Assert(err, IsNil)
+		key, err := keyHandler.HotplugKey(di)
+		c.Assert(err, IsNil)
+		return key
+	}
+
+	part1 := key(map[string]string{"DEVNAME": "/dev/sdb1", "ID_SERIAL_SHORT": "4C530001", "ID_PART_ENTRY_UUID": "1234abcd-01"})
+	c.Check(part1, Not(Equals), snap.HotplugKey(""))
+	// the partition keeps its key when the disk shows up under another name
+	c.Check(key(map[string]string{"DEVNAME": "/dev/sdc1", "ID_SERIAL_SHORT": "4C530001", "ID_PART_ENTRY_UUID": "1234abcd-01"}), Equals, part1)
+	c.Check(key(map[string]string{"DEVNAME": "/dev/sdb2", "ID_SERIAL_SHORT": "4C530001", "ID_PART_ENTRY_UUID": "1234abcd-02"}), Not(Equals), part1)
+	// the default key is used when the partition cannot be identified
+	c.Check(key(map[string]string{"DEVNAME": "/dev/sdb1", "ID_SERIAL_SHORT": "4C530001"}), Equals, snap.HotplugKey(""))
+}
+
+func (s *rawVolumeInterfaceSuite) TestHotplugHandledByGadget(c *C) {
+	byGadgetPred := s.iface.(hotplug.HandledByGadgetPredicate)
+	di, err := hotplug.NewHotplugDeviceInfo(map[string]string{"DEVPATH": "/sys/foo", "DEVNAME": "/dev/vda1", "DEVTYPE": "partition", "SUBSYSTEM": "block"})
+	c.Assert(err, IsNil)
+	c.Check(byGadgetPred.HandledByGadget(di, s.testUDev1Info), Equals, true)
+	c.Check(byGadgetPred.HandledByGadget(di, s.testUDev2Info), Equals, false)
+}
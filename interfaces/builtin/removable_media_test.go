@@ -25,6 +25,8 @@ import (
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/apparmor"
 	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/landlock"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/testutil"
 )
@@ -82,6 +84,19 @@ func (s *RemovableMediaInterfaceSuite) TestUsedSecuritySystems(c *C) {
 	c.Check(apparmorSpec.SnippetForTag("snap.client-snap.other"), testutil.Contains, "/mnt/** mrwklix,")
 }
 
+func (s *RemovableMediaInterfaceSuite) TestConnectedPlugLandlock(c *C) {
+	landlockSpec := landlock.NewSpecification(s.plug.AppSet())
+	err := landlockSpec.AddConnectedPlug(s.iface, s.plug, s.slot)
+	c.Assert(err, IsNil)
+	c.Assert(landlockSpec.SecurityTags(), DeepEquals, []string{"snap.client-snap.other"})
+	rwx := landlock_sandbox.AccessRead | landlock_sandbox.AccessWrite | landlock_sandbox.AccessExecute
+	c.Check(landlockSpec.RulesForTag("snap.client-snap.other"), DeepEquals, []landlock_sandbox.Rule{
+		{Access: rwx, Path: "/media/*"},
+		{Access: rwx, Path: "/mnt"},
+		{Access: rwx, Path: "/run/media/*"},
+	})
+}
+
 func (s *RemovableMediaInterfaceSuite) TestInterfaces(c *C) {
 	c.Check(builtin.Interfaces(), testutil.DeepContains, s.iface)
 }
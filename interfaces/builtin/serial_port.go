@@ -23,7 +23,6 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/snapcore/snapd/interfaces"
@@ -237,13 +236,8 @@ func (iface *serialPortInterface) HotplugDeviceDetected(di *hotplug.HotplugDevic
 }
 
 func slotDeviceAttrEqual(di *hotplug.HotplugDeviceInfo, devinfoAttribute string, slotAttributeValue int64) bool {
-	var attr string
-	var ok bool
-	if attr, ok = di.Attribute(devinfoAttribute); !ok {
-		return false
-	}
-	val, err := strconv.ParseInt(attr, 16, 64)
-	return err == nil && val == slotAttributeValue
+	val, ok := hexDeviceAttr(di, devinfoAttribute)
+	return ok && val == slotAttributeValue
 }
 
 func (iface *serialPortInterface) HandledByGadget(di *hotplug.HotplugDeviceInfo, slot *snap.SlotInfo) bool {
@@ -0,0 +1,47 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package builtin
+
+const snapNoticesObserveSummary = `allows read access to custom notices recorded by snaps`
+
+const snapNoticesObserveBaseDeclarationPlugs = `
+  snap-notices-observe:
+    allow-installation: false
+    deny-auto-connection: true
+`
+
+const snapNoticesObserveBaseDeclarationSlots = `
+  snap-notices-observe:
+    allow-installation:
+      slot-snap-type:
+        - core
+    deny-auto-connection: true
+`
+
+func init() {
+	registerIface(&commonInterface{
+		name:                 "snap-notices-observe",
+		summary:              snapNoticesObserveSummary,
+		implicitOnCore:       true,
+		implicitOnClassic:    true,
+		baseDeclarationPlugs: snapNoticesObserveBaseDeclarationPlugs,
+		baseDeclarationSlots: snapNoticesObserveBaseDeclarationSlots,
+	})
+}
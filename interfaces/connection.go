@@ -40,6 +40,9 @@ type ConnectedPlug struct {
 	appSet       *SnapAppSet
 	staticAttrs  map[string]any
 	dynamicAttrs map[string]any
+	// users holds the IDs of the users the connection is limited to, it's
+	// empty for connections of all the users
+	users []int
 }
 
 // LabelExpression returns the label expression for the given plug. It is
@@ -183,6 +186,12 @@ func (plug *ConnectedPlug) AppSet() *SnapAppSet {
 	return plug.appSet
 }
 
+// Users returns the IDs of the users the connection is limited to, or nil
+// if the plug is connected for all the users.
+func (plug *ConnectedPlug) Users() []int {
+	return plug.users
+}
+
 // Runnables returns a list of all runnables that should be connected to the
 // given plug.
 func (plug *ConnectedPlug) Runnables() []snap.Runnable {
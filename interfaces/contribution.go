@@ -0,0 +1,191 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package interfaces
+
+import (
+	"fmt"
+)
+
+// OriginKind is the kind of origin of a part of the security specification
+// of a snap.
+type OriginKind string
+
+const (
+	// OriginBaseTemplate is the base template of the security backend.
+	OriginBaseTemplate OriginKind = "base-template"
+	// OriginPlug is the permanent plug side snippet of an interface.
+	OriginPlug OriginKind = "plug"
+	// OriginSlot is the permanent slot side snippet of an interface.
+	OriginSlot OriginKind = "slot"
+	// OriginConnectedPlug is the plug side snippet of a connection.
+	OriginConnectedPlug OriginKind = "connected-plug"
+	// OriginConnectedSlot is the slot side snippet of a connection.
+	OriginConnectedSlot OriginKind = "connected-slot"
+	// OriginLayout is the snippet derived from the layout of the snap.
+	OriginLayout OriginKind = "layout"
+	// OriginParallelInstance is the snippet remapping the directories of a
+	// parallel instance of a snap.
+	OriginParallelInstance OriginKind = "parallel-instance"
+)
+
+// SpecificationOrigin describes where a part of the security specification
+// of a snap comes from.
+type SpecificationOrigin struct {
+	Kind      OriginKind `json:"kind"`
+	Interface string     `json:"interface,omitempty"`
+	// Plug is set for plug side and connection origins.
+	Plug *PlugRef `json:"plug,omitempty"`
+	// Slot is set for slot side and connection origins.
+	Slot *SlotRef `json:"slot,omitempty"`
+}
+
+func (o SpecificationOrigin) String() string {
+	switch o.Kind {
+	case OriginPlug:
+		return fmt.Sprintf("%s plug %s", o.Interface, o.Plug)
+	case OriginSlot:
+		return fmt.Sprintf("%s slot %s", o.Interface, o.Slot)
+	case OriginConnectedPlug, OriginConnectedSlot:
+		return fmt.Sprintf("%s %s %s %s", o.Interface, o.Kind, o.Plug, o.Slot)
+	}
+	return string(o.Kind)
+}
+
+// SpecificationContribution is the part of the security specification of a
+// snap coming from a single origin.
+type SpecificationContribution struct {
+	Origin SpecificationOrigin
+	// Add adds the contribution to the given specification.
+	Add func(spec Specification) error
+}
+
+// SnapSpecificationContributions returns the contributions of the interfaces
+// of the given snap to its security specification, in the order they are
+// added to it by SnapSpecification.
+func (r *Repository) SnapSpecificationContributions(appSet *SnapAppSet) []*SpecificationContribution {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.snapSpecificationContributions(appSet.InstanceName())
+}
+
+func (r *Repository) snapSpecificationContributions(snapName string) []*SpecificationContribution {
+	var contribs []*SpecificationContribution
+	// slot side
+	for _, slotInfo := range r.slots[snapName] {
+		iface := r.ifaces[slotInfo.Interface]
+		slotInfo := slotInfo
+		contribs = append(contribs, &SpecificationContribution{
+			Origin: SpecificationOrigin{
+				Kind:      OriginSlot,
+				Interface: iface.Name(),
+				Slot:      &SlotRef{Snap: snapName, Name: slotInfo.Name},
+			},
+			Add: func(spec Specification) error {
+				return spec.AddPermanentSlot(iface, slotInfo)
+			},
+		})
+		for _, conn := range r.slotPlugs[slotInfo] {
+			contribs = append(contribs, connectionContribution(iface, conn, OriginConnectedSlot))
+		}
+	}
+	// plug side
+	for _, plugInfo := range r.plugs[snapName] {
+		iface := r.ifaces[plugInfo.Interface]
+		plugInfo := plugInfo
+		contribs = append(contribs, &SpecificationContribution{
+			Origin: SpecificationOrigin{
+				Kind:      OriginPlug,
+				Interface: iface.Name(),
+				Plug:      &PlugRef{Snap: snapName, Name: plugInfo.Name},
+			},
+			Add: func(spec Specification) error {
+				return spec.AddPermanentPlug(iface, plugInfo)
+			},
+		})
+		for _, conn := range r.plugSlots[plugInfo] {
+			contribs = append(contribs, connectionContribution(iface, conn, OriginConnectedPlug))
+		}
+	}
+	return contribs
+}
+
+func connectionContribution(iface Interface, conn *Connection, kind OriginKind) *SpecificationContribution {
+	return &SpecificationContribution{
+		Origin: SpecificationOrigin{
+			Kind:      kind,
+			Interface: iface.Name(),
+			Plug:      &PlugRef{Snap: conn.Plug.Snap().InstanceName(), Name: conn.Plug.Name()},
+			Slot:      &SlotRef{Snap: conn.Slot.Snap().InstanceName(), Name: conn.Slot.Name()},
+		},
+		Add: func(spec Specification) error {
+			if kind == OriginConnectedSlot {
+				return spec.AddConnectedSlot(iface, conn.Plug, conn.Slot)
+			}
+			return spec.AddConnectedPlug(iface, conn.Plug, conn.Slot)
+		},
+	}
+}
+
+// ConnectionContributions returns the contributions a prospective connection
+// would make to the security specification of the given snap, which must be
+// the plug or the slot side of the connection. The connection is not checked
+// against policy and its attributes are the static ones of the plug and slot.
+func (r *Repository) ConnectionContributions(ref *ConnRef, snapName string) ([]*SpecificationContribution, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	plug := r.plugs[ref.PlugRef.Snap][ref.PlugRef.Name]
+	if plug == nil {
+		return nil, &NoPlugOrSlotError{
+			message: fmt.Sprintf("snap %q has no plug named %q", ref.PlugRef.Snap, ref.PlugRef.Name)}
+	}
+	slot := r.slots[ref.SlotRef.Snap][ref.SlotRef.Name]
+	if slot == nil {
+		return nil, &NoPlugOrSlotError{
+			message: fmt.Sprintf("snap %q has no slot named %q", ref.SlotRef.Snap, ref.SlotRef.Name)}
+	}
+	if slot.Interface != plug.Interface {
+		return nil, fmt.Errorf(`cannot connect plug "%s:%s" (interface %q) to "%s:%s" (interface %q)`,
+			ref.PlugRef.Snap, ref.PlugRef.Name, plug.Interface, ref.SlotRef.Snap, ref.SlotRef.Name, slot.Interface)
+	}
+	iface := r.ifaces[plug.Interface]
+	plugAppSet := r.appSets[ref.PlugRef.Snap]
+	slotAppSet := r.appSets[ref.SlotRef.Snap]
+	if plugAppSet == nil || slotAppSet == nil {
+		return nil, fmt.Errorf("internal error: no app set for connection %q", ref.ID())
+	}
+
+	conn := &Connection{
+		Plug: NewConnectedPlug(plug, plugAppSet, nil, nil),
+		Slot: NewConnectedSlot(slot, slotAppSet, nil, nil),
+	}
+	var contribs []*SpecificationContribution
+	if ref.SlotRef.Snap == snapName {
+		contribs = append(contribs, connectionContribution(iface, conn, OriginConnectedSlot))
+	}
+	if ref.PlugRef.Snap == snapName {
+		contribs = append(contribs, connectionContribution(iface, conn, OriginConnectedPlug))
+	}
+	if len(contribs) == 0 {
+		return nil, fmt.Errorf("snap %q is not part of connection %q", snapName, ref.ID())
+	}
+	return contribs, nil
+}
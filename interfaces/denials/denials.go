@@ -0,0 +1,281 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package denials collects the AppArmor and seccomp denials of snap
+// applications from the journal, as well as the device accesses denied by
+// the device cgroup when audited, and finds the interfaces that would grant
+// the denied access.
+package denials
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/snapcore/snapd/osutil"
+	apparmor_sandbox "github.com/snapcore/snapd/sandbox/apparmor"
+	"github.com/snapcore/snapd/snap/naming"
+	"github.com/snapcore/snapd/systemd"
+)
+
+const (
+	// KindAppArmor is the kind of denials reported by AppArmor.
+	KindAppArmor = "apparmor"
+	// KindSeccomp is the kind of denials reported by seccomp.
+	KindSeccomp = "seccomp"
+	// KindDevice is the kind of denials counted by the device cgroup.
+	KindDevice = "device"
+)
+
+// Denial is a single AppArmor or seccomp denial of a snap application or
+// hook, or the denied accesses to a device.
+type Denial struct {
+	Kind string    `json:"kind"`
+	Time time.Time `json:"time,omitzero"`
+	// Label is the security tag of the denied application or hook.
+	Label string `json:"label"`
+	// Snap is the instance name of the snap.
+	Snap string `json:"snap"`
+
+	// Operation and Class describe the kind of access denied by AppArmor,
+	// e.g. "open" and "file".
+	Operation string `json:"operation,omitempty"`
+	Class     string `json:"class,omitempty"`
+	// Path is the denied file or D-Bus object path.
+	Path string `json:"path,omitempty"`
+	// Permissions are the requested permissions, e.g. "r" for files or
+	// "send" for D-Bus.
+	Permissions string `json:"permissions,omitempty"`
+	// Owner is set when the denied file is owned by the denied process.
+	Owner bool `json:"owner,omitempty"`
+	// Capability is the name of the denied capability.
+	Capability string `json:"capability,omitempty"`
+	// Family and SockType describe a denied socket.
+	Family   string `json:"family,omitempty"`
+	SockType string `json:"sock-type,omitempty"`
+	// Bus, Interface, Member and Name describe a denied D-Bus message.
+	Bus       string `json:"bus,omitempty"`
+	Interface string `json:"interface,omitempty"`
+	Member    string `json:"member,omitempty"`
+	Name      string `json:"name,omitempty"`
+
+	// Syscall is the name of the syscall denied by seccomp, if known, and
+	// SyscallNumber its number for the audit architecture Arch.
+	Syscall       string `json:"syscall,omitempty"`
+	SyscallNumber int    `json:"syscall-number,omitempty"`
+	Arch          string `json:"arch,omitempty"`
+
+	// Device describes the device denied by the device cgroup.
+	Device *DeviceAccess `json:"device,omitempty"`
+
+	// Message is the original message of the denial.
+	Message string `json:"message"`
+}
+
+// auditFields splits an audit record into its key=value fields. Quoted
+// values are unquoted.
+func auditFields(msg string) map[string]string {
+	fields := make(map[string]string)
+	for len(msg) > 0 {
+		msg = strings.TrimLeftFunc(msg, unicode.IsSpace)
+		eq := strings.IndexByte(msg, '=')
+		if eq < 0 {
+			break
+		}
+		key := msg[:eq]
+		if sp := strings.LastIndexFunc(key, unicode.IsSpace); sp >= 0 {
+			// skip words that are not part of a key=value pair
+			key = key[sp+1:]
+		}
+		msg = msg[eq+1:]
+
+		var value string
+		if strings.HasPrefix(msg, `"`) {
+			end := strings.IndexByte(msg[1:], '"')
+			if end < 0 {
+				value, msg = msg[1:], ""
+			} else {
+				value, msg = msg[1:end+1], msg[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(msg, unicode.IsSpace)
+			if end < 0 {
+				end = len(msg)
+			}
+			value, msg = msg[:end], msg[end:]
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// auditString returns an unquoted value of a field that may have been hex
+// encoded by the kernel, which is done for values containing spaces or
+// other special characters.
+func auditString(fields map[string]string, key string) string {
+	value := fields[key]
+	if value == "" || len(value)%2 != 0 {
+		return value
+	}
+	if decoded, err := hex.DecodeString(value); err == nil && strings.HasPrefix(string(decoded), "/") {
+		return string(decoded)
+	}
+	return value
+}
+
+// parseLabel returns the security tag and snap instance name from an
+// AppArmor label, which may carry a mode or a child profile, or be the
+// variant of the profile for a user.
+func parseLabel(label string) (tag, instanceName string, ok bool) {
+	label, _, _ = strings.Cut(label, " ")
+	label, _, _ = strings.Cut(label, "//")
+	label = apparmor_sandbox.SecurityTagFromLabel(label)
+	secTag, err := naming.ParseSecurityTag(label)
+	if err != nil {
+		return "", "", false
+	}
+	return label, secTag.InstanceName(), true
+}
+
+// Parse parses a kernel audit message and returns the AppArmor or seccomp
+// denial it describes, or nil if it does not describe a denial of a snap.
+func Parse(msg string) *Denial {
+	switch {
+	case strings.Contains(msg, `apparmor="DENIED"`):
+		return parseAppArmor(msg)
+	case strings.HasPrefix(msg, "SECCOMP ") || strings.Contains(msg, "type=1326 "):
+		return parseSeccomp(msg)
+	}
+	return nil
+}
+
+func parseAppArmor(msg string) *Denial {
+	fields := auditFields(msg)
+	label := fields["profile"]
+	if label == "" {
+		// D-Bus denials carry the label of the sender
+		label = fields["label"]
+	}
+	tag, instanceName, ok := parseLabel(label)
+	if !ok {
+		return nil
+	}
+
+	d := &Denial{
+		Kind:        KindAppArmor,
+		Label:       tag,
+		Snap:        instanceName,
+		Operation:   fields["operation"],
+		Class:       fields["class"],
+		Permissions: fields["requested_mask"],
+		Message:     msg,
+	}
+	if d.Permissions == "" {
+		d.Permissions = fields["denied_mask"]
+	}
+
+	switch {
+	case fields["capname"] != "":
+		d.Class = "cap"
+		d.Capability = fields["capname"]
+	case fields["family"] != "":
+		d.Class = "net"
+		d.Family = fields["family"]
+		d.SockType = fields["sock_type"]
+	case fields["bus"] != "":
+		d.Class = "dbus"
+		d.Bus = fields["bus"]
+		d.Path = fields["path"]
+		d.Interface = fields["interface"]
+		d.Member = fields["member"]
+		d.Name = fields["name"]
+		d.Permissions = fields["mask"]
+	case fields["name"] != "":
+		if d.Class == "" {
+			d.Class = "file"
+		}
+		d.Path = auditString(fields, "name")
+		fsuid, ouid := fields["fsuid"], fields["ouid"]
+		d.Owner = fsuid != "" && fsuid == ouid
+	}
+	return d
+}
+
+func parseSeccomp(msg string) *Denial {
+	fields := auditFields(msg)
+	tag, instanceName, ok := parseLabel(fields["subj"])
+	if !ok {
+		return nil
+	}
+	nr, err := strconv.Atoi(fields["syscall"])
+	if err != nil {
+		return nil
+	}
+	d := &Denial{
+		Kind:          KindSeccomp,
+		Label:         tag,
+		Snap:          instanceName,
+		SyscallNumber: nr,
+		Arch:          fields["arch"],
+		Message:       msg,
+	}
+	d.Syscall = syscallName(d.Arch, nr)
+	return d
+}
+
+var osutilStreamCommand = osutil.StreamCommand
+
+// Collect returns the denials of the given snap found within the last lines
+// kernel and audit messages of the journal of the current boot, oldest
+// first.
+func Collect(instanceName string, lines int) ([]*Denial, error) {
+	stream, err := osutilStreamCommand("journalctl", "-o", "json", "--no-pager", "-b",
+		"-n", strconv.Itoa(lines), "_TRANSPORT=kernel", "_TRANSPORT=audit")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the journal: %v", err)
+	}
+	defer stream.Close()
+
+	var denials []*Denial
+	decoder := json.NewDecoder(stream)
+	for {
+		var entry systemd.Log
+		if err := decoder.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("cannot decode journal entry: %v", err)
+		}
+		d := Parse(entry.Message())
+		if d == nil || d.Snap != instanceName {
+			continue
+		}
+		if t, err := entry.Time(); err == nil {
+			d.Time = t
+		}
+		denials = append(denials, d)
+	}
+	return denials, nil
+}
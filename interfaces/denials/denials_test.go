@@ -0,0 +1,180 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type denialsSuite struct {
+	testutil.BaseTest
+}
+
+var _ = Suite(&denialsSuite{})
+
+const (
+	fileDenial    = `audit: type=1400 audit(1700000000.123:42): apparmor="DENIED" operation="open" class="file" profile="snap.foo.app" name="/etc/shadow" pid=1234 comm="app" requested_mask="r" denied_mask="r" fsuid=1000 ouid=0`
+	hexFileDenial = `audit: type=1400 audit(1700000000.123:43): apparmor="DENIED" operation="mknod" class="file" profile="snap.foo.hook.configure" name=2F746D702F6120622F63 pid=1234 comm="configure" requested_mask="c" denied_mask="c" fsuid=0 ouid=0`
+	capDenial     = `audit: type=1400 audit(1700000000.123:44): apparmor="DENIED" operation="capable" class="cap" profile="snap.foo.app" pid=1234 comm="app" capability=12  capname="net_admin"`
+	netDenial     = `audit: type=1400 audit(1700000000.123:45): apparmor="DENIED" operation="create" class="net" profile="snap.foo.app" pid=1234 comm="app" family="netlink" sock_type="raw" protocol=0 requested_mask="create" denied_mask="create"`
+	dbusDenial    = `apparmor="DENIED" operation="dbus_method_call"  bus="system" path="/org/freedesktop/login1" interface="org.freedesktop.login1.Manager" member="ListSessions" mask="send" name="org.freedesktop.login1" pid=1234 label="snap.foo.app" peer_pid=567 peer_label="unconfined"`
+	seccompDenial = `audit: type=1326 audit(1700000000.123:46): auid=4294967295 uid=0 gid=0 ses=4294967295 subj=snap.foo.app pid=1234 comm="app" exe="/snap/foo/x1/app" sig=0 arch=c000003e syscall=165 compat=0 ip=0x7f0000000000 code=0x50000`
+	hostDenial    = `audit: type=1400 audit(1700000000.123:47): apparmor="DENIED" operation="open" class="file" profile="/usr/sbin/cupsd" name="/etc/shadow" pid=1 comm="cupsd" requested_mask="r" denied_mask="r" fsuid=0 ouid=0`
+)
+
+func (s *denialsSuite) TestParseFile(c *C) {
+	d := denials.Parse(fileDenial)
+	c.Assert(d, NotNil)
+	c.Check(d, DeepEquals, &denials.Denial{
+		Kind:        denials.KindAppArmor,
+		Label:       "snap.foo.app",
+		Snap:        "foo",
+		Operation:   "open",
+		Class:       "file",
+		Path:        "/etc/shadow",
+		Permissions: "r",
+		Message:     fileDenial,
+	})
+}
+
+func (s *denialsSuite) TestParseFileHexNameOwner(c *C) {
+	d := denials.Parse(hexFileDenial)
+	c.Assert(d, NotNil)
+	c.Check(d.Label, Equals, "snap.foo.hook.configure")
+	c.Check(d.Path, Equals, "/tmp/a b/c")
+	c.Check(d.Permissions, Equals, "c")
+	c.Check(d.Owner, Equals, true)
+}
+
+func (s *denialsSuite) TestParseCapability(c *C) {
+	d := denials.Parse(capDenial)
+	c.Assert(d, NotNil)
+	c.Check(d.Class, Equals, "cap")
+	c.Check(d.Capability, Equals, "net_admin")
+}
+
+func (s *denialsSuite) TestParseNetwork(c *C) {
+	d := denials.Parse(netDenial)
+	c.Assert(d, NotNil)
+	c.Check(d.Class, Equals, "net")
+	c.Check(d.Family, Equals, "netlink")
+	c.Check(d.SockType, Equals, "raw")
+}
+
+func (s *denialsSuite) TestParseDBus(c *C) {
+	d := denials.Parse(dbusDenial)
+	c.Assert(d, NotNil)
+	c.Check(d.Class, Equals, "dbus")
+	c.Check(d.Label, Equals, "snap.foo.app")
+	c.Check(d.Bus, Equals, "system")
+	c.Check(d.Path, Equals, "/org/freedesktop/login1")
+	c.Check(d.Interface, Equals, "org.freedesktop.login1.Manager")
+	c.Check(d.Member, Equals, "ListSessions")
+	c.Check(d.Name, Equals, "org.freedesktop.login1")
+	c.Check(d.Permissions, Equals, "send")
+}
+
+func (s *denialsSuite) TestParseUserProfile(c *C) {
+	d := denials.Parse(strings.Replace(fileDenial, `profile="snap.foo.app"`, `profile="snap.foo.app.user-1000"`, 1))
+	c.Assert(d, NotNil)
+	c.Check(d.Label, Equals, "snap.foo.app")
+	c.Check(d.Snap, Equals, "foo")
+}
+
+func (s *denialsSuite) TestParseSeccomp(c *C) {
+	d := denials.Parse(seccompDenial)
+	c.Assert(d, NotNil)
+	c.Check(d.Kind, Equals, denials.KindSeccomp)
+	c.Check(d.Label, Equals, "snap.foo.app")
+	c.Check(d.Snap, Equals, "foo")
+	c.Check(d.Arch, Equals, "c000003e")
+	c.Check(d.SyscallNumber, Equals, 165)
+	c.Check(d.Syscall, Equals, "mount")
+
+	// unknown syscalls are reported by number only
+	d = denials.Parse(strings.Replace(seccompDenial, "syscall=165", "syscall=9999", 1))
+	c.Assert(d, NotNil)
+	c.Check(d.SyscallNumber, Equals, 9999)
+	c.Check(d.Syscall, Equals, "")
+}
+
+func (s *denialsSuite) TestParseIgnored(c *C) {
+	for _, msg := range []string{
+		"",
+		"usb 1-1: new high-speed USB device number 2 using xhci_hcd",
+		hostDenial,
+		strings.Replace(fileDenial, `apparmor="DENIED"`, `apparmor="ALLOWED"`, 1),
+		strings.Replace(seccompDenial, "subj=snap.foo.app", "subj=unconfined", 1),
+	} {
+		c.Check(denials.Parse(msg), IsNil, Commentf("%q", msg))
+	}
+}
+
+func (s *denialsSuite) TestCollect(c *C) {
+	var cmd []string
+	journal := strings.Join([]string{
+		`{"MESSAGE": "` + strings.ReplaceAll(fileDenial, `"`, `\"`) + `", "__REALTIME_TIMESTAMP": "1700000000123000"}`,
+		`{"MESSAGE": "` + strings.ReplaceAll(hostDenial, `"`, `\"`) + `", "__REALTIME_TIMESTAMP": "1700000001000000"}`,
+		`{"MESSAGE": "` + strings.ReplaceAll(strings.ReplaceAll(capDenial, "snap.foo.", "snap.bar."), `"`, `\"`) + `", "__REALTIME_TIMESTAMP": "1700000002000000"}`,
+		`{"MESSAGE": "` + strings.ReplaceAll(seccompDenial, `"`, `\"`) + `", "__REALTIME_TIMESTAMP": "1700000003000000"}`,
+	}, "\n")
+	restore := denials.MockOsutilStreamCommand(func(name string, args ...string) (io.ReadCloser, error) {
+		cmd = append([]string{name}, args...)
+		return io.NopCloser(strings.NewReader(journal)), nil
+	})
+	defer restore()
+
+	ds, err := denials.Collect("foo", 100)
+	c.Assert(err, IsNil)
+	c.Check(cmd, DeepEquals, []string{"journalctl", "-o", "json", "--no-pager", "-b", "-n", "100", "_TRANSPORT=kernel", "_TRANSPORT=audit"})
+	c.Assert(ds, HasLen, 2)
+	c.Check(ds[0].Kind, Equals, denials.KindAppArmor)
+	c.Check(ds[0].Path, Equals, "/etc/shadow")
+	c.Check(ds[0].Time.Equal(time.UnixMicro(1700000000123000)), Equals, true)
+	c.Check(ds[1].Kind, Equals, denials.KindSeccomp)
+	c.Check(ds[1].Syscall, Equals, "mount")
+}
+
+func (s *denialsSuite) TestCollectErrors(c *C) {
+	restore := denials.MockOsutilStreamCommand(func(name string, args ...string) (io.ReadCloser, error) {
+		return nil, errors.New("boom")
+	})
+	defer restore()
+	_, err := denials.Collect("foo", 100)
+	c.Check(err, ErrorMatches, "cannot read the journal: boom")
+
+	restore = denials.MockOsutilStreamCommand(func(name string, args ...string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("{not json")), nil
+	})
+	defer restore()
+	_, err = denials.Collect("foo", 100)
+	c.Check(err, ErrorMatches, "cannot decode journal entry: .*")
+}
@@ -0,0 +1,233 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/sandbox/ebpf"
+	"github.com/snapcore/snapd/snap"
+)
+
+// DeviceAccess describes a device the device cgroup of a snap application
+// or hook denied access to.
+type DeviceAccess struct {
+	// Type is "c" for character devices and "b" for block devices.
+	Type  string `json:"type"`
+	Major uint32 `json:"major"`
+	Minor uint32 `json:"minor"`
+	// Subsystem and Kernel are the udev subsystem and kernel name of the
+	// device, if it is still present.
+	Subsystem string `json:"subsystem,omitempty"`
+	Kernel    string `json:"kernel,omitempty"`
+	// Count is the number of denied accesses.
+	Count uint64 `json:"count"`
+}
+
+// deviceAuditCounts returns the number of denied accesses per device counted
+// for the given security tag, or nil if the accesses of the tag are not
+// audited.
+var deviceAuditCounts = func(securityTag string) (map[ebpf.DeviceKey]uint64, error) {
+	if _, err := os.Stat(ebpf.SecurityTagToBPFAuditPath(securityTag)); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	m, err := ebpf.LoadDeviceAuditMap(securityTag)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+	counts := make(map[ebpf.DeviceKey]uint64)
+	err = m.Iterate(func(key ebpf.DeviceKey, count uint64) error {
+		counts[key] = count
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read device audit map of %s: %v", securityTag, err)
+	}
+	return counts, nil
+}
+
+// describeDevice fills in the udev subsystem and kernel name of the device,
+// and its path in /dev, as found in sysfs.
+func describeDevice(d *Denial) {
+	kind := "char"
+	if d.Device.Type == "b" {
+		kind = "block"
+	}
+	devDir := filepath.Join(dirs.SysfsDir, "dev", kind, fmt.Sprintf("%d:%d", d.Device.Major, d.Device.Minor))
+	target, err := os.Readlink(devDir)
+	if err != nil {
+		// the device is gone
+		return
+	}
+	d.Device.Kernel = filepath.Base(target)
+	if subsystem, err := os.Readlink(filepath.Join(devDir, "subsystem")); err == nil {
+		d.Device.Subsystem = filepath.Base(subsystem)
+	}
+
+	f, err := os.Open(filepath.Join(devDir, "uevent"))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if devName, ok := strings.CutPrefix(sc.Text(), "DEVNAME="); ok {
+			d.Path = filepath.Join("/dev", devName)
+		}
+	}
+}
+
+// CollectDevices returns the device accesses denied to the applications and
+// hooks of the given snap, as counted by the device cgroup when auditing is
+// enabled for the snap. There is one denial per device and application or
+// hook, sorted by security tag and device.
+func CollectDevices(info *snap.Info) ([]*Denial, error) {
+	var tags []string
+	for _, app := range info.Apps {
+		tags = append(tags, app.SecurityTag())
+	}
+	for _, hook := range info.Hooks {
+		tags = append(tags, hook.SecurityTag())
+	}
+	sort.Strings(tags)
+
+	var denials []*Denial
+	for _, tag := range tags {
+		counts, err := deviceAuditCounts(tag)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]ebpf.DeviceKey, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].Type != keys[j].Type {
+				return keys[i].Type < keys[j].Type
+			}
+			if keys[i].Major != keys[j].Major {
+				return keys[i].Major < keys[j].Major
+			}
+			return keys[i].Minor < keys[j].Minor
+		})
+		for _, key := range keys {
+			d := &Denial{
+				Kind:  KindDevice,
+				Label: tag,
+				Snap:  info.InstanceName(),
+				Device: &DeviceAccess{
+					Type:  string(rune(key.Type)),
+					Major: key.Major,
+					Minor: key.Minor,
+					Count: counts[key],
+				},
+			}
+			describeDevice(d)
+			d.Message = fmt.Sprintf("device cgroup denied access to %s %d:%d", d.Device.Type, key.Major, key.Minor)
+			denials = append(denials, d)
+		}
+	}
+	return denials, nil
+}
+
+// udevRule is the subset of a udev rule tagging devices for a snap
+// application or hook needed to tell whether it tags a denied device. Only
+// the SUBSYSTEM and KERNEL keys are considered, other keys are assumed to
+// match.
+type udevRule struct {
+	subsystem []udevMatch
+	kernel    []udevMatch
+}
+
+type udevMatch struct {
+	negate   bool
+	patterns []string
+}
+
+var udevRuleKey = regexp.MustCompile(`([A-Z]+)(==|!=)"([^"]*)"`)
+
+// udevTag returns the udev tag of a security tag, as used by the udev
+// backend.
+func udevTag(securityTag string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(securityTag, "+", "__"), ".", "_")
+}
+
+// parseUDevRules returns the rules of a udev snippet which tag devices for
+// the given security tag.
+func parseUDevRules(snippet, securityTag string) []*udevRule {
+	tagAssignment := fmt.Sprintf(`TAG+="%s"`, udevTag(securityTag))
+	var rules []*udevRule
+	for _, line := range strings.Split(snippet, "\n") {
+		if !strings.Contains(line, tagAssignment) {
+			continue
+		}
+		rule := &udevRule{}
+		for _, m := range udevRuleKey.FindAllStringSubmatch(line, -1) {
+			match := udevMatch{negate: m[2] == "!=", patterns: strings.Split(m[3], "|")}
+			switch m[1] {
+			case "SUBSYSTEM":
+				rule.subsystem = append(rule.subsystem, match)
+			case "KERNEL":
+				rule.kernel = append(rule.kernel, match)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func (m udevMatch) matches(value string) bool {
+	matched := false
+	for _, pattern := range m.patterns {
+		if ok, err := filepath.Match(pattern, value); err == nil && ok {
+			matched = true
+			break
+		}
+	}
+	return matched != m.negate
+}
+
+func (r *udevRule) allows(d *Denial) bool {
+	if d.Device == nil || d.Device.Subsystem == "" {
+		// nothing is known about devices which are gone
+		return false
+	}
+	for _, m := range r.subsystem {
+		if !m.matches(d.Device.Subsystem) {
+			return false
+		}
+	}
+	for _, m := range r.kernel {
+		if !m.matches(d.Device.Kernel) {
+			return false
+		}
+	}
+	return true
+}
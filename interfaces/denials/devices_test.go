@@ -0,0 +1,163 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/sandbox/ebpf"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type devicesSuite struct {
+	testutil.BaseTest
+
+	info *snap.Info
+}
+
+var _ = Suite(&devicesSuite{})
+
+func (s *devicesSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+	s.info = snaptest.MockInfo(c, matchSnapYaml, &snap.SideInfo{Revision: snap.R(1)})
+}
+
+// mockSysfsDevice mocks a device in sysfs, as found by its device number.
+func mockSysfsDevice(c *C, kind string, major, minor int, subsystem, kernel, devName string) {
+	devicePath := filepath.Join(dirs.SysfsDir, "devices/virtual", subsystem, kernel)
+	c.Assert(os.MkdirAll(devicePath, 0755), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(dirs.SysfsDir, "class", subsystem), 0755), IsNil)
+	c.Assert(os.Symlink(filepath.Join(dirs.SysfsDir, "class", subsystem), filepath.Join(devicePath, "subsystem")), IsNil)
+	uevent := fmt.Sprintf("MAJOR=%d\nMINOR=%d\nDEVNAME=%s\n", major, minor, devName)
+	c.Assert(os.WriteFile(filepath.Join(devicePath, "uevent"), []byte(uevent), 0644), IsNil)
+
+	devDir := filepath.Join(dirs.SysfsDir, "dev", kind)
+	c.Assert(os.MkdirAll(devDir, 0755), IsNil)
+	c.Assert(os.Symlink(devicePath, filepath.Join(devDir, fmt.Sprintf("%d:%d", major, minor))), IsNil)
+}
+
+func (s *devicesSuite) TestCollectDevices(c *C) {
+	mockSysfsDevice(c, "char", 81, 0, "video4linux", "video0", "video0")
+	mockSysfsDevice(c, "block", 8, 0, "block", "sda", "sda")
+
+	var tags []string
+	restore := denials.MockDeviceAuditCounts(func(tag string) (map[ebpf.DeviceKey]uint64, error) {
+		tags = append(tags, tag)
+		switch tag {
+		case "snap.foo.app":
+			return map[ebpf.DeviceKey]uint64{
+				{Type: 'c', Major: 81, Minor: 0}: 3,
+				{Type: 'b', Major: 8, Minor: 0}:  1,
+				// the device is gone
+				{Type: 'c', Major: 189, Minor: 2}: 5,
+			}, nil
+		case "snap.foo.hook.configure":
+			return map[ebpf.DeviceKey]uint64{{Type: 'c', Major: 81, Minor: 0}: 1}, nil
+		}
+		return nil, nil
+	})
+	defer restore()
+
+	ds, err := denials.CollectDevices(s.info)
+	c.Assert(err, IsNil)
+	c.Check(tags, DeepEquals, []string{"snap.foo.app", "snap.foo.hook.configure"})
+	c.Check(ds, DeepEquals, []*denials.Denial{{
+		Kind:    denials.KindDevice,
+		Label:   "snap.foo.app",
+		Snap:    "foo",
+		Path:    "/dev/sda",
+		Device:  &denials.DeviceAccess{Type: "b", Major: 8, Minor: 0, Subsystem: "block", Kernel: "sda", Count: 1},
+		Message: "device cgroup denied access to b 8:0",
+	}, {
+		Kind:    denials.KindDevice,
+		Label:   "snap.foo.app",
+		Snap:    "foo",
+		Path:    "/dev/video0",
+		Device:  &denials.DeviceAccess{Type: "c", Major: 81, Minor: 0, Subsystem: "video4linux", Kernel: "video0", Count: 3},
+		Message: "device cgroup denied access to c 81:0",
+	}, {
+		Kind:    denials.KindDevice,
+		Label:   "snap.foo.app",
+		Snap:    "foo",
+		Device:  &denials.DeviceAccess{Type: "c", Major: 189, Minor: 2, Count: 5},
+		Message: "device cgroup denied access to c 189:2",
+	}, {
+		Kind:    denials.KindDevice,
+		Label:   "snap.foo.hook.configure",
+		Snap:    "foo",
+		Path:    "/dev/video0",
+		Device:  &denials.DeviceAccess{Type: "c", Major: 81, Minor: 0, Subsystem: "video4linux", Kernel: "video0", Count: 1},
+		Message: "device cgroup denied access to c 81:0",
+	}})
+}
+
+func (s *devicesSuite) TestCollectDevicesError(c *C) {
+	restore := denials.MockDeviceAuditCounts(func(tag string) (map[ebpf.DeviceKey]uint64, error) {
+		return nil, fmt.Errorf("boom")
+	})
+	defer restore()
+
+	_, err := denials.CollectDevices(s.info)
+	c.Check(err, ErrorMatches, "boom")
+}
+
+func (s *devicesSuite) TestCollectDevicesNotAudited(c *C) {
+	// no audit maps are pinned
+	ds, err := denials.CollectDevices(s.info)
+	c.Assert(err, IsNil)
+	c.Check(ds, HasLen, 0)
+}
+
+func (s *devicesSuite) TestMatchDevice(c *C) {
+	m, err := denials.NewMatcher(s.info, builtin.Interfaces())
+	c.Assert(err, IsNil)
+
+	d := &denials.Denial{
+		Kind:   denials.KindDevice,
+		Label:  "snap.foo.app",
+		Snap:   "foo",
+		Device: &denials.DeviceAccess{Type: "c", Major: 81, Minor: 0, Subsystem: "video4linux", Kernel: "video0", Count: 1},
+	}
+	c.Check(interfaceNames(m.Match(d)), testutil.Contains, "camera")
+	c.Check(interfaceNames(m.Match(d)), Not(testutil.Contains), "network-control")
+
+	// the hook is covered by the unscoped candidate plug too
+	d.Label = "snap.foo.hook.configure"
+	c.Check(interfaceNames(m.Match(d)), testutil.Contains, "camera")
+
+	// a different kernel name does not match
+	d.Device.Kernel = "vbi0"
+	c.Check(interfaceNames(m.Match(d)), Not(testutil.Contains), "camera")
+
+	// nothing is known about devices which are gone
+	d.Device = &denials.DeviceAccess{Type: "c", Major: 81, Minor: 0, Count: 1}
+	c.Check(m.Match(d), HasLen, 0)
+}
@@ -0,0 +1,201 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+import (
+	"sort"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/seccomp"
+	"github.com/snapcore/snapd/interfaces/udev"
+	"github.com/snapcore/snapd/snap"
+)
+
+// Match describes an interface that would grant the access of a denial.
+type Match struct {
+	Interface string `json:"interface"`
+	// Plug is the name of the plug of the snap through which the interface
+	// would grant the access, if the snap has one.
+	Plug string `json:"plug,omitempty"`
+}
+
+// Matcher matches the denials of a snap against the AppArmor and seccomp
+// rules, and the udev rules tagging devices, that interfaces would grant to
+// the snap if connected.
+type Matcher struct {
+	candidates []*candidate
+}
+
+type candidate struct {
+	match    Match
+	apparmor map[string][]*apparmorRule
+	seccomp  map[string]map[string]bool
+	udev     map[string][]*udevRule
+}
+
+// systemSnap is the provider of the implicit slots the candidate plugs are
+// connected to.
+var systemSnap = &snap.Info{SuggestedName: "snapd", SnapType: snap.TypeSnapd}
+
+// NewMatcher returns a Matcher for the given snap, considering the given
+// interfaces. The plugs the snap declares are used as they are, with their
+// attributes; interfaces the snap has no plug for are considered through an
+// unscoped plug without attributes, bound to all its apps and hooks.
+// Interfaces whose rules cannot be computed this way are ignored.
+func NewMatcher(info *snap.Info, ifaces []interfaces.Interface) (*Matcher, error) {
+	appSet, err := interfaces.NewSnapAppSet(info, nil)
+	if err != nil {
+		return nil, err
+	}
+	systemAppSet, err := interfaces.NewSnapAppSet(systemSnap, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	plugsByIface := make(map[string][]*snap.PlugInfo)
+	for _, plug := range info.Plugs {
+		plugsByIface[plug.Interface] = append(plugsByIface[plug.Interface], plug)
+	}
+
+	m := &Matcher{}
+	for _, iface := range ifaces {
+		name := iface.Name()
+		plugs := plugsByIface[name]
+		sort.Slice(plugs, func(i, j int) bool { return plugs[i].Name < plugs[j].Name })
+		if len(plugs) == 0 {
+			plugInfo := &snap.PlugInfo{
+				Snap:      info,
+				Name:      name,
+				Interface: name,
+				Apps:      info.Apps,
+				Unscoped:  true,
+			}
+			if err := interfaces.BeforePreparePlug(iface, plugInfo); err != nil {
+				continue
+			}
+			plugs = []*snap.PlugInfo{plugInfo}
+		}
+
+		// interfaces whose slots need attributes cannot be considered
+		slotInfo := &snap.SlotInfo{Snap: systemSnap, Name: name, Interface: name}
+		if err := interfaces.BeforePrepareSlot(iface, slotInfo); err != nil {
+			continue
+		}
+		slot := interfaces.NewConnectedSlot(slotInfo, systemAppSet, nil, nil)
+		for _, plugInfo := range plugs {
+			plug := interfaces.NewConnectedPlug(plugInfo, appSet, nil, nil)
+			c, err := newCandidate(iface, plug, slot, appSet)
+			if err != nil {
+				continue
+			}
+			if info.Plugs[plugInfo.Name] == plugInfo {
+				c.match.Plug = plugInfo.Name
+			}
+			m.candidates = append(m.candidates, c)
+		}
+	}
+	return m, nil
+}
+
+func newCandidate(iface interfaces.Interface, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot, appSet *interfaces.SnapAppSet) (*candidate, error) {
+	apparmorSpec := apparmor.NewSpecification(appSet)
+	if err := apparmorSpec.AddConnectedPlug(iface, plug, slot); err != nil {
+		return nil, err
+	}
+	seccompSpec := seccomp.NewSpecification(appSet)
+	if err := seccompSpec.AddConnectedPlug(iface, plug, slot); err != nil {
+		return nil, err
+	}
+	udevSpec := udev.NewSpecification(appSet)
+	if err := udevSpec.AddConnectedPlug(iface, plug, slot); err != nil {
+		return nil, err
+	}
+
+	info := appSet.Info()
+	vars := map[string]string{
+		"PROC":               "/proc",
+		"HOME":               "{/home/*,/root}",
+		"HOMEDIRS":           "/home",
+		"SNAP_NAME":          info.SnapName(),
+		"SNAP_INSTANCE_NAME": info.InstanceName(),
+		"INSTALL_DIR":        "/{,var/lib/snapd/}snap",
+	}
+
+	c := &candidate{
+		match:    Match{Interface: iface.Name()},
+		apparmor: make(map[string][]*apparmorRule),
+		seccomp:  make(map[string]map[string]bool),
+		udev:     make(map[string][]*udevRule),
+	}
+	for tag, snippets := range apparmorSpec.Snippets() {
+		for _, snippet := range snippets {
+			c.apparmor[tag] = append(c.apparmor[tag], parseAppArmorRules(snippet, vars)...)
+		}
+	}
+	for _, tag := range seccompSpec.SecurityTags() {
+		c.seccomp[tag] = parseSeccompSyscalls(seccompSpec.SnippetForTag(tag))
+	}
+	for _, runnable := range appSet.Runnables() {
+		for _, snippet := range udevSpec.Snippets() {
+			tag := runnable.SecurityTag
+			c.udev[tag] = append(c.udev[tag], parseUDevRules(snippet, tag)...)
+		}
+	}
+	return c, nil
+}
+
+func (c *candidate) allows(d *Denial) bool {
+	switch d.Kind {
+	case KindAppArmor:
+		for _, rule := range c.apparmor[d.Label] {
+			if rule.allows(d) {
+				return true
+			}
+		}
+	case KindSeccomp:
+		return d.Syscall != "" && c.seccomp[d.Label][d.Syscall]
+	case KindDevice:
+		for _, rule := range c.udev[d.Label] {
+			if rule.allows(d) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Match returns the interfaces that would grant the access of the given
+// denial, sorted by interface and plug name.
+func (m *Matcher) Match(d *Denial) []Match {
+	var matches []Match
+	for _, c := range m.candidates {
+		if c.allows(d) {
+			matches = append(matches, c.match)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Interface != matches[j].Interface {
+			return matches[i].Interface < matches[j].Interface
+		}
+		return matches[i].Plug < matches[j].Plug
+	})
+	return matches
+}
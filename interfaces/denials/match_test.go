@@ -0,0 +1,162 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type matchSuite struct {
+	testutil.BaseTest
+
+	matcher *denials.Matcher
+}
+
+var _ = Suite(&matchSuite{})
+
+const matchSnapYaml = `name: foo
+version: 1
+apps:
+  app:
+    command: app
+    plugs: [netctl]
+plugs:
+  netctl:
+    interface: network-control
+hooks:
+  configure:
+`
+
+func (s *matchSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	info := snaptest.MockInfo(c, matchSnapYaml, &snap.SideInfo{Revision: snap.R(1)})
+	m, err := denials.NewMatcher(info, builtin.Interfaces())
+	c.Assert(err, IsNil)
+	s.matcher = m
+}
+
+func interfaceNames(matches []denials.Match) []string {
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, m.Interface)
+	}
+	return names
+}
+
+func (s *matchSuite) TestMatchCapabilityUsesSnapPlug(c *C) {
+	matches := s.matcher.Match(denials.Parse(capDenial))
+	c.Check(matches, testutil.DeepContains, denials.Match{Interface: "network-control", Plug: "netctl"})
+	// interfaces without a plug of the snap are reported without one
+	c.Check(matches, testutil.DeepContains, denials.Match{Interface: "firewall-control"})
+	c.Check(interfaceNames(matches), Not(testutil.Contains), "home")
+}
+
+func (s *matchSuite) TestMatchNetwork(c *C) {
+	matches := s.matcher.Match(denials.Parse(netDenial))
+	c.Check(interfaceNames(matches), testutil.Contains, "network-control")
+	c.Check(interfaceNames(matches), Not(testutil.Contains), "network")
+}
+
+func (s *matchSuite) TestMatchFile(c *C) {
+	d := denials.Parse(fileDenial)
+	d.Path = "/proc/1/net/dev"
+	c.Check(interfaceNames(s.matcher.Match(d)), testutil.Contains, "network-observe")
+
+	d.Path = "/sys/nonexistent"
+	c.Check(s.matcher.Match(d), HasLen, 0)
+}
+
+func (s *matchSuite) TestMatchDBus(c *C) {
+	matches := s.matcher.Match(denials.Parse(dbusDenial))
+	c.Check(interfaceNames(matches), testutil.Contains, "login-session-observe")
+}
+
+func (s *matchSuite) TestMatchHook(c *C) {
+	// unscoped candidate plugs cover hooks too
+	d := denials.Parse(capDenial)
+	d.Label = "snap.foo.hook.configure"
+	c.Check(interfaceNames(s.matcher.Match(d)), testutil.Contains, "firewall-control")
+	// while the plug of the snap is bound to its app only
+	c.Check(s.matcher.Match(d), Not(testutil.DeepContains), denials.Match{Interface: "network-control", Plug: "netctl"})
+}
+
+func (s *matchSuite) TestMatchSeccomp(c *C) {
+	matches := s.matcher.Match(denials.Parse(seccompDenial))
+	c.Check(interfaceNames(matches), testutil.Contains, "mount-control")
+
+	d := denials.Parse(seccompDenial)
+	d.Syscall = ""
+	c.Check(s.matcher.Match(d), HasLen, 0)
+}
+
+func (s *matchSuite) TestAareRegexp(c *C) {
+	vars := map[string]string{"PROC": "/proc", "SNAP_NAME": "foo"}
+	for _, t := range []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"/etc/shadow", "/etc/shadow", true},
+		{"/etc/*", "/etc/shadow", true},
+		{"/etc/*", "/etc/ssl/certs", false},
+		{"/etc/**", "/etc/ssl/certs", true},
+		{"/dev/tty?", "/dev/tty1", true},
+		{"/dev/tty[0-9]", "/dev/ttyS", false},
+		{"/sys/{bus,class}/net/", "/sys/class/net/", true},
+		{"/sys/{bus,class}/net/", "/sys/block/net/", false},
+		{"@{PROC}/@{pid}/net/dev", "/proc/42/net/dev", true},
+		{"/var/snap/@{SNAP_NAME}/**", "/var/snap/foo/common/x", true},
+		{"/var/snap/@{SNAP_NAME}/**", "/var/snap/bar/common/x", false},
+		{"/run//foo", "/run/foo", true},
+	} {
+		re, err := denials.AareRegexp(t.pattern, vars)
+		c.Assert(err, IsNil)
+		c.Check(re.MatchString(t.path), Equals, t.match, Commentf("%s %s", t.pattern, t.path))
+	}
+}
+
+func (s *matchSuite) TestParseAppArmorRules(c *C) {
+	rules := denials.ParseAppArmorRules(`
+# a comment
+@{HOMEDIRS}+=/mnt/home
+/etc/foo r,
+@{PROC}/@{pid}/net/** r,
+owner /etc/bar rw, # trailing comment
+deny /etc/baz w,
+capability sys_admin net_admin,
+network netlink raw,
+dbus (send)
+    bus=system
+    path=/org/freedesktop/foo
+    interface=org.freedesktop.Foo
+    member={Bar,Baz}
+    peer=(label=unconfined),
+/usr/bin/foo ixr,
+profile nested {
+}
+`, nil)
+	c.Check(rules, HasLen, 7)
+}
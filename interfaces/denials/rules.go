@@ -0,0 +1,413 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// apparmorRule is the subset of an AppArmor rule needed to tell whether it
+// allows a denied access. Only file, capability, network and D-Bus rules are
+// understood.
+type apparmorRule struct {
+	class string
+	owner bool
+	perms string
+
+	// file and D-Bus object path
+	path *regexp.Regexp
+	// capabilities, empty for all
+	caps []string
+	// network family and socket type, empty for any
+	family   string
+	sockType string
+	// D-Bus bus, interface, member and peer name, empty or nil for any
+	bus    string
+	iface  *regexp.Regexp
+	member *regexp.Regexp
+	name   *regexp.Regexp
+}
+
+var (
+	templatePlaceholder = regexp.MustCompile(`###[A-Z_]+###`)
+	variableDefinition  = regexp.MustCompile(`^@\{[A-Za-z0-9_]+\}\s*\+?=`)
+)
+
+// parseAppArmorRules parses the rules of an AppArmor snippet, expanding the
+// given variables. Rules that cannot be parsed or are not understood are
+// ignored.
+func parseAppArmorRules(snippet string, vars map[string]string) []*apparmorRule {
+	var rules []*apparmorRule
+	var buf []string
+	for _, line := range strings.Split(snippet, "\n") {
+		line = templatePlaceholder.ReplaceAllStringFunc(line, func(placeholder string) string {
+			if placeholder == "###PROMPT###" {
+				return ""
+			}
+			return "*"
+		})
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+		if strings.HasSuffix(line, "{") || line == "}" || variableDefinition.MatchString(line) {
+			// nested profiles, hats and variable definitions
+			buf = buf[:0]
+			continue
+		}
+		buf = append(buf, line)
+		if !strings.HasSuffix(line, ",") {
+			continue
+		}
+		rule := strings.TrimSuffix(strings.Join(buf, " "), ",")
+		buf = buf[:0]
+		if r := parseAppArmorRule(rule, vars); r != nil {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// stripComment removes a trailing comment from a line of an AppArmor
+// snippet.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// ruleTokens splits a rule into whitespace separated tokens, keeping
+// parenthesized groups together.
+func ruleTokens(rule string) []string {
+	var tokens []string
+	var cur strings.Builder
+	depth := 0
+	for _, r := range rule {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case unicode.IsSpace(r) && depth == 0:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
+
+func parseAppArmorRule(rule string, vars map[string]string) *apparmorRule {
+	tokens := ruleTokens(rule)
+	r := &apparmorRule{}
+	// leading qualifiers
+	for len(tokens) > 0 {
+		if tokens[0] == "deny" {
+			return nil
+		}
+		if tokens[0] == "owner" {
+			r.owner = true
+		} else if tokens[0] != "audit" && tokens[0] != "allow" {
+			break
+		}
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	switch tokens[0] {
+	case "capability":
+		r.class = "cap"
+		r.caps = tokens[1:]
+		return r
+	case "network":
+		r.class = "net"
+		if len(tokens) > 1 {
+			r.family = tokens[1]
+		}
+		if len(tokens) > 2 {
+			r.sockType = tokens[2]
+		}
+		return r
+	case "dbus":
+		return parseDBusRule(r, tokens[1:], vars)
+	case "file":
+		tokens = tokens[1:]
+	}
+
+	// file rules are either "path perms" or "perms path", possibly
+	// followed by an exec transition
+	if len(tokens) < 2 {
+		return nil
+	}
+	path, perms := tokens[0], tokens[1]
+	if !isPathPattern(path) {
+		path, perms = perms, path
+	}
+	if !isPathPattern(path) || strings.Trim(perms, "rwaxmlkiuUpPcCd") != "" {
+		return nil
+	}
+	re, err := aareRegexp(path, vars)
+	if err != nil {
+		return nil
+	}
+	r.class = "file"
+	r.path = re
+	r.perms = perms
+	return r
+}
+
+func isPathPattern(s string) bool {
+	return strings.HasPrefix(s, "/") || strings.HasPrefix(s, "@{")
+}
+
+func parseDBusRule(r *apparmorRule, tokens []string, vars map[string]string) *apparmorRule {
+	r.class = "dbus"
+	for _, tok := range tokens {
+		tok = strings.TrimSuffix(tok, ",")
+		if strings.HasPrefix(tok, "(") {
+			r.perms = strings.Trim(tok, "()")
+			continue
+		}
+		key, value, ok := strings.Cut(tok, "=")
+		if !ok {
+			// unparenthesized single permission
+			r.perms = tok
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		var err error
+		switch key {
+		case "bus":
+			r.bus = value
+		case "path":
+			r.path, err = aareRegexp(value, vars)
+		case "interface":
+			r.iface, err = aareRegexp(value, vars)
+		case "member":
+			r.member, err = aareRegexp(value, vars)
+		case "peer":
+			for _, peerTok := range ruleTokens(strings.NewReplacer(",", " ").Replace(strings.Trim(value, "()"))) {
+				if name, ok := strings.CutPrefix(peerTok, "name="); ok {
+					r.name, err = aareRegexp(strings.Trim(name, `"`), vars)
+				}
+			}
+		}
+		if err != nil {
+			return nil
+		}
+	}
+	return r
+}
+
+// aareRegexp converts an AppArmor path pattern to an anchored regular
+// expression, expanding the given variables. Unknown variables match any
+// single path component.
+func aareRegexp(pattern string, vars map[string]string) (*regexp.Regexp, error) {
+	pattern = expandVars(pattern, vars)
+	for strings.Contains(pattern, "//") {
+		pattern = strings.ReplaceAll(pattern, "//", "/")
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	depth := 0
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '{':
+			depth++
+			b.WriteString("(?:")
+		case '}':
+			if depth > 0 {
+				depth--
+				b.WriteString(")")
+			} else {
+				b.WriteString(`\}`)
+			}
+		case ',':
+			if depth > 0 {
+				b.WriteString("|")
+			} else {
+				b.WriteString(",")
+			}
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString("[")
+			b.WriteString(strings.ReplaceAll(pattern[i+1:i+1+end], `\`, `\\`))
+			b.WriteString("]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+var variableRef = regexp.MustCompile(`@\{[A-Za-z0-9_]+\}`)
+
+func expandVars(pattern string, vars map[string]string) string {
+	return variableRef.ReplaceAllStringFunc(pattern, func(ref string) string {
+		if value, ok := vars[ref[2:len(ref)-1]]; ok {
+			return value
+		}
+		return "*"
+	})
+}
+
+// allows returns whether the rule allows the access denied by the given
+// AppArmor denial.
+func (r *apparmorRule) allows(d *Denial) bool {
+	if r.class != d.Class {
+		return false
+	}
+	switch r.class {
+	case "cap":
+		if len(r.caps) == 0 {
+			return true
+		}
+		for _, c := range r.caps {
+			if c == d.Capability {
+				return true
+			}
+		}
+		return false
+	case "net":
+		return (r.family == "" || r.family == d.Family) && (r.sockType == "" || r.sockType == d.SockType)
+	case "dbus":
+		if r.bus != "" && r.bus != d.Bus {
+			return false
+		}
+		if !matchesOptional(r.path, d.Path) || !matchesOptional(r.iface, d.Interface) || !matchesOptional(r.member, d.Member) {
+			return false
+		}
+		// unique connection names cannot be matched against the
+		// well-known names in rules
+		if !strings.HasPrefix(d.Name, ":") && !matchesOptional(r.name, d.Name) {
+			return false
+		}
+		return dbusPermsAllow(r.perms, d.Permissions)
+	case "file":
+		if r.owner && !d.Owner {
+			return false
+		}
+		return r.path.MatchString(d.Path) && filePermsAllow(r.perms, d.Permissions)
+	}
+	return false
+}
+
+func matchesOptional(re *regexp.Regexp, s string) bool {
+	return re == nil || re.MatchString(s)
+}
+
+// filePermsAllow returns whether the file permissions of a rule cover all
+// the requested ones.
+func filePermsAllow(perms, requested string) bool {
+	for _, p := range requested {
+		switch p {
+		case 'c', 'd':
+			// creating and deleting files requires write
+			p = 'w'
+		case 'a':
+			// write implies append
+			if strings.ContainsRune(perms, 'a') {
+				continue
+			}
+			p = 'w'
+		case 'x':
+			// any of the exec modes
+			if strings.ContainsAny(perms, "x") {
+				continue
+			}
+			return false
+		}
+		if !strings.ContainsRune(perms, p) {
+			return false
+		}
+	}
+	return requested != ""
+}
+
+func dbusPermsAllow(perms, requested string) bool {
+	if perms == "" {
+		return true
+	}
+	for _, p := range strings.FieldsFunc(perms, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		if p == requested {
+			return true
+		}
+		switch requested {
+		case "send":
+			if p == "w" || p == "rw" || p == "write" {
+				return true
+			}
+		case "receive":
+			if p == "r" || p == "rw" || p == "read" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseSeccompSyscalls returns the syscalls allowed by a seccomp snippet,
+// regardless of any argument filtering.
+func parseSeccompSyscalls(snippet string) map[string]bool {
+	syscalls := make(map[string]bool)
+	for _, line := range strings.Split(snippet, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "~") || strings.HasPrefix(fields[0], "@") {
+			continue
+		}
+		syscalls[fields[0]] = true
+	}
+	return syscalls
+}
@@ -0,0 +1,164 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+// The kernel reports the number of a syscall denied by seccomp, which is
+// specific to the architecture of the process. The tables below only list
+// privileged syscalls that are not allowed by the default seccomp template and
+// are thus the ones usually denied. They are keyed by the audit architecture
+// as reported in the audit records.
+var syscallNames = map[string]map[int]string{
+	// x86_64
+	"c000003e": {
+		43:  "accept",
+		49:  "bind",
+		50:  "listen",
+		101: "ptrace",
+		103: "syslog",
+		105: "setuid",
+		106: "setgid",
+		113: "setreuid",
+		114: "setregid",
+		116: "setgroups",
+		117: "setresuid",
+		119: "setresgid",
+		122: "setfsuid",
+		123: "setfsgid",
+		153: "vhangup",
+		155: "pivot_root",
+		159: "adjtimex",
+		163: "acct",
+		164: "settimeofday",
+		165: "mount",
+		166: "umount2",
+		167: "swapon",
+		168: "swapoff",
+		169: "reboot",
+		170: "sethostname",
+		171: "setdomainname",
+		172: "iopl",
+		173: "ioperm",
+		175: "init_module",
+		176: "delete_module",
+		179: "quotactl",
+		212: "lookup_dcookie",
+		227: "clock_settime",
+		240: "mq_open",
+		241: "mq_unlink",
+		242: "mq_timedsend",
+		243: "mq_timedreceive",
+		244: "mq_notify",
+		245: "mq_getsetattr",
+		246: "kexec_load",
+		248: "add_key",
+		249: "request_key",
+		250: "keyctl",
+		251: "ioprio_set",
+		272: "unshare",
+		288: "accept4",
+		298: "perf_event_open",
+		300: "fanotify_init",
+		301: "fanotify_mark",
+		303: "name_to_handle_at",
+		304: "open_by_handle_at",
+		305: "clock_adjtime",
+		308: "setns",
+		310: "process_vm_readv",
+		311: "process_vm_writev",
+		313: "finit_module",
+		314: "sched_setattr",
+		320: "kexec_file_load",
+		321: "bpf",
+		457: "statmount",
+		458: "listmount",
+		459: "lsm_get_self_attr",
+		460: "lsm_set_self_attr",
+	},
+	// aarch64
+	"c00000b7": {
+		18:  "lookup_dcookie",
+		30:  "ioprio_set",
+		39:  "umount2",
+		40:  "mount",
+		41:  "pivot_root",
+		58:  "vhangup",
+		60:  "quotactl",
+		89:  "acct",
+		97:  "unshare",
+		104: "kexec_load",
+		105: "init_module",
+		106: "delete_module",
+		112: "clock_settime",
+		116: "syslog",
+		117: "ptrace",
+		142: "reboot",
+		143: "setregid",
+		144: "setgid",
+		145: "setreuid",
+		146: "setuid",
+		147: "setresuid",
+		149: "setresgid",
+		151: "setfsuid",
+		152: "setfsgid",
+		159: "setgroups",
+		161: "sethostname",
+		162: "setdomainname",
+		170: "settimeofday",
+		171: "adjtimex",
+		180: "mq_open",
+		181: "mq_unlink",
+		182: "mq_timedsend",
+		183: "mq_timedreceive",
+		184: "mq_notify",
+		185: "mq_getsetattr",
+		200: "bind",
+		201: "listen",
+		202: "accept",
+		217: "add_key",
+		218: "request_key",
+		219: "keyctl",
+		224: "swapon",
+		225: "swapoff",
+		241: "perf_event_open",
+		242: "accept4",
+		262: "fanotify_init",
+		263: "fanotify_mark",
+		264: "name_to_handle_at",
+		265: "open_by_handle_at",
+		266: "clock_adjtime",
+		268: "setns",
+		270: "process_vm_readv",
+		271: "process_vm_writev",
+		273: "finit_module",
+		274: "sched_setattr",
+		280: "bpf",
+		294: "kexec_file_load",
+		457: "statmount",
+		458: "listmount",
+		459: "lsm_get_self_attr",
+		460: "lsm_set_self_attr",
+	},
+}
+
+// syscallName returns the name of the syscall with the given number on the
+// given audit architecture, or an empty string if it is not known.
+func syscallName(arch string, nr int) string {
+	return syscallNames[arch][nr]
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/snapcore/snapd/interfaces/dbus"
 	"github.com/snapcore/snapd/interfaces/hotplug"
 	"github.com/snapcore/snapd/interfaces/kmod"
+	"github.com/snapcore/snapd/interfaces/landlock"
 	"github.com/snapcore/snapd/interfaces/ldconfig"
 	"github.com/snapcore/snapd/interfaces/mount"
 	"github.com/snapcore/snapd/interfaces/polkit"
@@ -135,6 +136,13 @@ type TestInterface struct {
 	SymlinksConnectedSlotCallback func(spec *symlinks.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
 	SymlinksPermanentPlugCallback func(spec *symlinks.Specification, plug *snap.PlugInfo) error
 	SymlinksPermanentSlotCallback func(spec *symlinks.Specification, slot *snap.SlotInfo) error
+
+	// Support for interacting with the landlock backend.
+
+	LandlockConnectedPlugCallback func(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
+	LandlockConnectedSlotCallback func(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
+	LandlockPermanentPlugCallback func(spec *landlock.Specification, plug *snap.PlugInfo) error
+	LandlockPermanentSlotCallback func(spec *landlock.Specification, slot *snap.SlotInfo) error
 }
 
 // TestHotplugInterface is an interface for various kinds of tests
@@ -592,6 +600,36 @@ func (t *TestInterface) SymlinksPermanentSlot(spec *symlinks.Specification, slot
 	return nil
 }
 
+// Support for interacting with the landlock backend.
+
+func (t *TestInterface) LandlockConnectedPlug(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	if t.LandlockConnectedPlugCallback != nil {
+		return t.LandlockConnectedPlugCallback(spec, plug, slot)
+	}
+	return nil
+}
+
+func (t *TestInterface) LandlockConnectedSlot(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	if t.LandlockConnectedSlotCallback != nil {
+		return t.LandlockConnectedSlotCallback(spec, plug, slot)
+	}
+	return nil
+}
+
+func (t *TestInterface) LandlockPermanentPlug(spec *landlock.Specification, plug *snap.PlugInfo) error {
+	if t.LandlockPermanentPlugCallback != nil {
+		return t.LandlockPermanentPlugCallback(spec, plug)
+	}
+	return nil
+}
+
+func (t *TestInterface) LandlockPermanentSlot(spec *landlock.Specification, slot *snap.SlotInfo) error {
+	if t.LandlockPermanentSlotCallback != nil {
+		return t.LandlockPermanentSlotCallback(spec, slot)
+	}
+	return nil
+}
+
 // Support for interacting with hotplug subsystem.
 
 func (t *TestHotplugInterface) HotplugKey(deviceInfo *hotplug.HotplugDeviceInfo) (snap.HotplugKey, error) {
@@ -0,0 +1,245 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package inspect renders the effective security profiles of snaps with
+// every line annotated with the origin of the rule it comes from.
+package inspect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/interfaces"
+)
+
+// Line is a line of a rendered profile.
+type Line struct {
+	Text string `json:"text"`
+	// Origins are the origins the line comes from. It is empty for blank
+	// lines and for lines that cannot be attributed to a single part of the
+	// specification, e.g. because they combine several of them.
+	Origins []interfaces.SpecificationOrigin `json:"origins,omitempty"`
+}
+
+// Profile is a security artefact of a snap, as written by a security
+// backend.
+type Profile struct {
+	Backend interfaces.SecuritySystem `json:"backend"`
+	Name    string                    `json:"name"`
+	Lines   []Line                    `json:"lines"`
+}
+
+type renderingBackend interface {
+	interfaces.SecurityBackend
+	interfaces.SecurityBackendRenderer
+}
+
+func renderingBackends(repo *interfaces.Repository) []renderingBackend {
+	var backends []renderingBackend
+	for _, b := range repo.Backends() {
+		if rb, ok := b.(renderingBackend); ok {
+			backends = append(backends, rb)
+		}
+	}
+	sort.Slice(backends, func(i, j int) bool { return backends[i].Name() < backends[j].Name() })
+	return backends
+}
+
+func render(backend renderingBackend, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, contribs []*interfaces.SpecificationContribution) (map[string][]byte, error) {
+	spec := backend.NewSpecification(appSet, opts)
+	for _, contrib := range contribs {
+		if err := contrib.Add(spec); err != nil {
+			return nil, fmt.Errorf("cannot add %s to %s specification: %v", contrib.Origin, backend.Name(), err)
+		}
+	}
+	return backend.RenderProfiles(spec, appSet, opts)
+}
+
+func profileLines(content []byte) []string {
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// lineCounts returns how many times each non-blank line occurs in the
+// profiles, by profile name.
+func lineCounts(profiles map[string][]byte) map[string]map[string]int {
+	counts := make(map[string]map[string]int, len(profiles))
+	for name, content := range profiles {
+		counts[name] = make(map[string]int)
+		for _, line := range profileLines(content) {
+			if strings.TrimSpace(line) != "" {
+				counts[name][line]++
+			}
+		}
+	}
+	return counts
+}
+
+// addedLines returns the lines occurring more often in the profiles after
+// than before, by profile name.
+func addedLines(before, after map[string]map[string]int) map[string]map[string]bool {
+	added := make(map[string]map[string]bool)
+	for name, counts := range after {
+		for line, n := range counts {
+			if n > before[name][line] {
+				if added[name] == nil {
+					added[name] = make(map[string]bool)
+				}
+				added[name][line] = true
+			}
+		}
+	}
+	return added
+}
+
+type attribution struct {
+	origin interfaces.SpecificationOrigin
+	lines  map[string]map[string]bool
+}
+
+// attribute finds the lines each contribution adds to the profiles rendered
+// from the given base contributions.
+func attribute(backend renderingBackend, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, base []*interfaces.SpecificationContribution, contribs []*interfaces.SpecificationContribution) ([]attribution, error) {
+	baseProfiles, err := render(backend, appSet, opts, base)
+	if err != nil {
+		return nil, err
+	}
+	baseCounts := lineCounts(baseProfiles)
+	attributions := make([]attribution, 0, len(contribs))
+	for _, contrib := range contribs {
+		profiles, err := render(backend, appSet, opts, append(base[:len(base):len(base)], contrib))
+		if err != nil {
+			return nil, err
+		}
+		attributions = append(attributions, attribution{
+			origin: contrib.Origin,
+			lines:  addedLines(baseCounts, lineCounts(profiles)),
+		})
+	}
+	return attributions, nil
+}
+
+func annotate(backend interfaces.SecuritySystem, name string, lines []string, attributions []attribution) *Profile {
+	profile := &Profile{Backend: backend, Name: name, Lines: make([]Line, 0, len(lines))}
+	for _, text := range lines {
+		line := Line{Text: text}
+		if strings.TrimSpace(text) != "" {
+			for _, a := range attributions {
+				if a.lines[name][text] {
+					line.Origins = append(line.Origins, a.origin)
+				}
+			}
+		}
+		profile.Lines = append(profile.Lines, line)
+	}
+	return profile
+}
+
+func sortedNames(profiles map[string][]byte) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profiles returns the effective profiles of the given snap for all the
+// security backends of the repository able to render them, with every line
+// annotated with its origin: the base template of the backend, a plug, slot
+// or connection of the snap, or the snap itself such as its layout.
+func Profiles(repo *interfaces.Repository, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) ([]*Profile, error) {
+	var result []*Profile
+	for _, backend := range renderingBackends(repo) {
+		contribs := append(repo.SnapSpecificationContributions(appSet), backend.SnapContributions(appSet, opts)...)
+		effective, err := render(backend, appSet, opts, contribs)
+		if err != nil {
+			return nil, err
+		}
+		attributions, err := attribute(backend, appSet, opts, nil, contribs)
+		if err != nil {
+			return nil, err
+		}
+		template, err := render(backend, appSet, opts, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range sortedNames(effective) {
+			profile := annotate(backend.Name(), name, profileLines(effective[name]), attributions)
+			templateCounts := lineCounts(template)[name]
+			for i := range profile.Lines {
+				line := &profile.Lines[i]
+				if len(line.Origins) == 0 && templateCounts[line.Text] > 0 {
+					line.Origins = []interfaces.SpecificationOrigin{{Kind: interfaces.OriginBaseTemplate}}
+				}
+			}
+			result = append(result, profile)
+		}
+	}
+	return result, nil
+}
+
+// ConnectionProfiles returns the lines a prospective connection would add to
+// the effective profiles of the given snap, which must be the plug or slot
+// side of it. Only profiles that would change are returned.
+func ConnectionProfiles(repo *interfaces.Repository, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, ref *interfaces.ConnRef) ([]*Profile, error) {
+	connContribs, err := repo.ConnectionContributions(ref, appSet.InstanceName())
+	if err != nil {
+		return nil, err
+	}
+	var result []*Profile
+	for _, backend := range renderingBackends(repo) {
+		contribs := append(repo.SnapSpecificationContributions(appSet), backend.SnapContributions(appSet, opts)...)
+		before, err := render(backend, appSet, opts, contribs)
+		if err != nil {
+			return nil, err
+		}
+		after, err := render(backend, appSet, opts, append(contribs, connContribs...))
+		if err != nil {
+			return nil, err
+		}
+		attributions, err := attribute(backend, appSet, opts, contribs, connContribs)
+		if err != nil {
+			return nil, err
+		}
+		beforeCounts := lineCounts(before)
+		for _, name := range sortedNames(after) {
+			// keep the added lines in their order in the profile
+			counts := beforeCounts[name]
+			if counts == nil {
+				counts = make(map[string]int)
+			}
+			var added []string
+			for _, line := range profileLines(after[name]) {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				if counts[line] > 0 {
+					counts[line]--
+					continue
+				}
+				added = append(added, line)
+			}
+			if len(added) > 0 {
+				result = append(result, annotate(backend.Name(), name, added, attributions))
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,226 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package inspect_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/interfaces/inspect"
+	"github.com/snapcore/snapd/snap"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type inspectSuite struct {
+	repo       *interfaces.Repository
+	consumer   *interfaces.SnapAppSet
+	producer   *interfaces.SnapAppSet
+	iface      *ifacetest.TestInterface
+	renderFail error
+}
+
+var _ = Suite(&inspectSuite{})
+
+// renderingBackend renders the snippets of a test specification after a
+// template into a single profile.
+type renderingBackend struct {
+	ifacetest.TestSecurityBackend
+	s *inspectSuite
+}
+
+func (b *renderingBackend) SnapContributions(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) []*interfaces.SpecificationContribution {
+	return []*interfaces.SpecificationContribution{{
+		Origin: interfaces.SpecificationOrigin{Kind: interfaces.OriginLayout},
+		Add: func(spec interfaces.Specification) error {
+			spec.(*ifacetest.Specification).AddSnippet("layout rule")
+			return nil
+		},
+	}}
+}
+
+func (b *renderingBackend) RenderProfiles(spec interfaces.Specification, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) (map[string][]byte, error) {
+	if b.s.renderFail != nil {
+		return nil, b.s.renderFail
+	}
+	content := "# template of " + appSet.InstanceName() + "\n\n" + strings.Join(spec.(*ifacetest.Specification).Snippets, "\n") + "\n"
+	return map[string][]byte{"snap." + appSet.InstanceName() + ".profile": []byte(content)}, nil
+}
+
+const consumerYaml = `name: consumer
+version: 0
+apps:
+  app:
+    plugs: [plug, other-plug]
+plugs:
+  plug:
+    interface: iface
+  other-plug:
+    interface: iface
+`
+
+const producerYaml = `name: producer
+version: 0
+apps:
+  app:
+slots:
+  slot:
+    interface: iface
+  other-slot:
+    interface: iface
+plugs:
+  plug:
+    interface: iface
+`
+
+func (s *inspectSuite) SetUpTest(c *C) {
+	s.renderFail = nil
+	s.repo = interfaces.NewRepository()
+	s.iface = &ifacetest.TestInterface{
+		InterfaceName: "iface",
+		TestPermanentPlugCallback: func(spec *ifacetest.Specification, plug *snap.PlugInfo) error {
+			spec.AddSnippet("permanent rule of " + plug.Name)
+			return nil
+		},
+		TestConnectedPlugCallback: func(spec *ifacetest.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+			spec.AddSnippet("connected rule to " + slot.Name())
+			spec.AddSnippet("shared rule")
+			return nil
+		},
+	}
+	c.Assert(s.repo.AddInterface(s.iface), IsNil)
+	c.Assert(s.repo.AddBackend(&renderingBackend{TestSecurityBackend: ifacetest.TestSecurityBackend{BackendName: "test"}, s: s}), IsNil)
+	// backends unable to render profiles are skipped
+	c.Assert(s.repo.AddBackend(&ifacetest.TestSecurityBackend{BackendName: "other"}), IsNil)
+
+	s.consumer = ifacetest.MockInfoAndAppSet(c, consumerYaml, nil, nil)
+	s.producer = ifacetest.MockInfoAndAppSet(c, producerYaml, nil, nil)
+	c.Assert(s.repo.AddAppSet(s.consumer), IsNil)
+	c.Assert(s.repo.AddAppSet(s.producer), IsNil)
+
+	_, err := s.repo.Connect(&interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+}
+
+func (s *inspectSuite) TestProfiles(c *C) {
+	profiles, err := inspect.Profiles(s.repo, s.consumer, interfaces.ConfinementOptions{})
+	c.Assert(err, IsNil)
+
+	conn := interfaces.SpecificationOrigin{
+		Kind:      interfaces.OriginConnectedPlug,
+		Interface: "iface",
+		Plug:      &interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+		Slot:      &interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}
+	plug := interfaces.SpecificationOrigin{
+		Kind:      interfaces.OriginPlug,
+		Interface: "iface",
+		Plug:      &interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+	}
+	otherPlug := interfaces.SpecificationOrigin{
+		Kind:      interfaces.OriginPlug,
+		Interface: "iface",
+		Plug:      &interfaces.PlugRef{Snap: "consumer", Name: "other-plug"},
+	}
+	c.Assert(profiles, HasLen, 1)
+	c.Check(profiles[0].Backend, Equals, interfaces.SecuritySystem("test"))
+	c.Check(profiles[0].Name, Equals, "snap.consumer.profile")
+
+	byText := make(map[string][]interfaces.SpecificationOrigin)
+	var texts []string
+	for _, line := range profiles[0].Lines {
+		texts = append(texts, line.Text)
+		byText[line.Text] = line.Origins
+	}
+	c.Check(texts, HasLen, 7)
+	c.Check(byText["# template of consumer"], DeepEquals, []interfaces.SpecificationOrigin{{Kind: interfaces.OriginBaseTemplate}})
+	c.Check(byText[""], HasLen, 0)
+	c.Check(byText["permanent rule of plug"], DeepEquals, []interfaces.SpecificationOrigin{plug})
+	c.Check(byText["permanent rule of other-plug"], DeepEquals, []interfaces.SpecificationOrigin{otherPlug})
+	c.Check(byText["connected rule to slot"], DeepEquals, []interfaces.SpecificationOrigin{conn})
+	c.Check(byText["shared rule"], DeepEquals, []interfaces.SpecificationOrigin{conn})
+	c.Check(byText["layout rule"], DeepEquals, []interfaces.SpecificationOrigin{{Kind: interfaces.OriginLayout}})
+}
+
+func (s *inspectSuite) TestConnectionProfiles(c *C) {
+	ref := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "other-plug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "other-slot"},
+	}
+	profiles, err := inspect.ConnectionProfiles(s.repo, s.consumer, interfaces.ConfinementOptions{}, ref)
+	c.Assert(err, IsNil)
+
+	conn := interfaces.SpecificationOrigin{
+		Kind:      interfaces.OriginConnectedPlug,
+		Interface: "iface",
+		Plug:      &interfaces.PlugRef{Snap: "consumer", Name: "other-plug"},
+		Slot:      &interfaces.SlotRef{Snap: "producer", Name: "other-slot"},
+	}
+	c.Assert(profiles, HasLen, 1)
+	c.Check(profiles[0].Name, Equals, "snap.consumer.profile")
+	// the shared rule is already there for the existing connection, another
+	// copy of it is added
+	c.Check(profiles[0].Lines, DeepEquals, []inspect.Line{
+		{Text: "connected rule to other-slot", Origins: []interfaces.SpecificationOrigin{conn}},
+		{Text: "shared rule", Origins: []interfaces.SpecificationOrigin{conn}},
+	})
+
+	// the slot side of the connection does not change anything
+	profiles, err = inspect.ConnectionProfiles(s.repo, s.producer, interfaces.ConfinementOptions{}, ref)
+	c.Assert(err, IsNil)
+	c.Check(profiles, HasLen, 0)
+}
+
+func (s *inspectSuite) TestConnectionProfilesErrors(c *C) {
+	for _, t := range []struct {
+		ref *interfaces.ConnRef
+		err string
+	}{{
+		ref: &interfaces.ConnRef{PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "missing"}, SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"}},
+		err: `snap "consumer" has no plug named "missing"`,
+	}, {
+		ref: &interfaces.ConnRef{PlugRef: interfaces.PlugRef{Snap: "producer", Name: "plug"}, SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"}},
+		err: `snap "consumer" is not part of connection "producer:plug producer:slot"`,
+	}} {
+		_, err := inspect.ConnectionProfiles(s.repo, s.consumer, interfaces.ConfinementOptions{}, t.ref)
+		c.Check(err, ErrorMatches, t.err)
+	}
+}
+
+func (s *inspectSuite) TestProfilesErrors(c *C) {
+	s.renderFail = errors.New("boom")
+	_, err := inspect.Profiles(s.repo, s.consumer, interfaces.ConfinementOptions{})
+	c.Check(err, ErrorMatches, "boom")
+
+	s.renderFail = nil
+	s.iface.TestConnectedPlugCallback = func(spec *ifacetest.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+		return errors.New("cannot compute snippet")
+	}
+	_, err = inspect.Profiles(s.repo, s.consumer, interfaces.ConfinementOptions{})
+	c.Check(err, ErrorMatches, `cannot add iface connected-plug consumer:plug producer:slot to test specification: cannot compute snippet`)
+}
@@ -0,0 +1,249 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package landlock
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/sandbox/landlock"
+	"github.com/snapcore/snapd/snap"
+)
+
+// appArmorFileRule matches AppArmor file rules, such as:
+//
+//	owner /run/user/[0-9]*/pulse/ r,
+//	/usr/bin/foo Px -> foo,
+var appArmorFileRule = regexp.MustCompile(`^(?:(?:audit|owner|allow|file)\s+)*("[^"]+"|[/@]\S*)\s+([rwalkmixupcPUC]+)\s*(?:->\s*\S+\s*)?,\s*(?:#.*)?$`)
+
+// appArmorVariables maps the AppArmor variables used by interfaces to the
+// variables of the landlock rulesets, or to wildcards.
+var appArmorVariables = strings.NewReplacer(
+	"@{PROC}", "/proc",
+	"@{HOME}", "$HOME",
+	"@{HOMEDIRS}", "/home",
+	"@{INSTALL_DIR}", "/{,var/lib/snapd/}snap",
+	"@{SNAP_COREUTIL_DIRS}", "/{bin/,usr/bin/,usr/bin/gnu,usr/lib/cargo/bin/coreutils/}",
+	"@{SNAP_INSTANCE_NAME}", "$SNAP_INSTANCE_NAME",
+	"@{SNAP_NAME}", "$SNAP_NAME",
+	"@{SNAP_REVISION}", "$SNAP_REVISION",
+)
+
+var appArmorOtherVariable = regexp.MustCompile(`@\{[^}]*\}`)
+
+// appArmorMarkers removes the markers which the AppArmor backend replaces
+// when writing the profiles, access is granted as if prompting was off.
+var appArmorMarkers = strings.NewReplacer(
+	"###PROMPT### ", "",
+	"###PROMPT###", "",
+	"###HOME_IX###", "",
+)
+
+// rulesFromAppArmor derives landlock rules from the file rules of the given
+// AppArmor snippet. Landlock rules apply to whole directory trees and have
+// no deny or owner rules, the derived rules are therefore more permissive
+// than the AppArmor ones: a rule ending with ** grants access to everything
+// beneath the entries matched by the rest of its last path component. Rules
+// which cannot be represented without granting more are skipped, such as
+// the rules on directories only, as granting reading a directory also grants
+// reading its content, the rules with ** in the middle of the path and the
+// rules which would apply to everything or to top-level directories matched
+// by wildcards.
+func rulesFromAppArmor(snippet string) []landlock.Rule {
+	var rules []landlock.Rule
+	depth := 0
+	for _, line := range strings.Split(snippet, "\n") {
+		line = appArmorMarkers.Replace(strings.TrimSpace(line))
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasSuffix(line, "{"):
+			// rules of other profiles or of hats
+			depth++
+			continue
+		case strings.HasPrefix(line, "}"):
+			if depth > 0 {
+				depth--
+			}
+			continue
+		case depth > 0:
+			continue
+		}
+		m := appArmorFileRule.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		access := appArmorAccess(m[2])
+		if access == 0 {
+			continue
+		}
+		pattern := appArmorVariables.Replace(strings.Trim(m[1], `"`))
+		pattern = appArmorOtherVariable.ReplaceAllString(pattern, "*")
+		for _, p := range expandAppArmorAlternations(pattern) {
+			if p = landlockPath(p); p != "" {
+				rules = append(rules, landlock.Rule{Access: access, Path: p})
+			}
+		}
+	}
+	return rules
+}
+
+func appArmorAccess(perms string) landlock.Access {
+	var access landlock.Access
+	if strings.ContainsAny(perms, "r") {
+		access |= landlock.AccessRead
+	}
+	if strings.ContainsAny(perms, "wa") {
+		access |= landlock.AccessWrite
+	}
+	if strings.ContainsAny(perms, "x") {
+		access |= landlock.AccessExecute
+	}
+	return access
+}
+
+// expandAppArmorAlternations expands the {a,b} alternations of an AppArmor
+// path pattern into the list of patterns they stand for.
+func expandAppArmorAlternations(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+	depth := 0
+	var alternatives []string
+	last := start + 1
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				alternatives = append(alternatives, pattern[last:i])
+				var expanded []string
+				for _, alt := range alternatives {
+					expanded = append(expanded, expandAppArmorAlternations(pattern[:start]+alt+pattern[i+1:])...)
+				}
+				return expanded
+			}
+		}
+	}
+	// unbalanced, leave it for the wildcard checks
+	return []string{pattern}
+}
+
+// landlockPath returns the landlock path for an AppArmor path pattern
+// without alternations, or an empty string if the rule must be skipped.
+// Hidden entries of $HOME hold the credentials and the configuration of the
+// user, the wildcards starting the first path component beneath $HOME never
+// match them.
+func landlockPath(pattern string) string {
+	tree := false
+	if idx := strings.Index(pattern, "**"); idx >= 0 {
+		if idx != len(pattern)-len("**") {
+			// wildcards across directories cannot be represented
+			return ""
+		}
+		// everything beneath the entries matching the last path
+		// component, or beneath the directory if there is none
+		pattern = pattern[:idx]
+		if !strings.HasSuffix(pattern, "/") {
+			pattern += "*"
+		}
+		tree = true
+	} else if strings.HasSuffix(pattern, "/") {
+		return ""
+	}
+	if !strings.HasPrefix(pattern, "/") && !strings.HasPrefix(pattern, "$") {
+		return ""
+	}
+	pattern = path.Clean(pattern)
+	if pattern == "/" {
+		return ""
+	}
+	if pattern == "$HOME" {
+		if !tree {
+			return ""
+		}
+		pattern = "$HOME/*"
+	}
+	if rest := strings.TrimPrefix(pattern, "$HOME/"); rest != pattern {
+		switch rest[0] {
+		case '*':
+			pattern = "$HOME/[^.]" + rest
+		case '?':
+			pattern = "$HOME/[^.]" + rest[1:]
+		}
+	}
+	if strings.HasPrefix(pattern, "/") {
+		top := strings.SplitN(pattern[1:], "/", 2)[0]
+		if strings.ContainsAny(top, "*?[") {
+			return ""
+		}
+	}
+	return pattern
+}
+
+// addAppArmorRules grants the access derived from the AppArmor snippets
+// that the given function adds to an AppArmor specification of the snap.
+func (spec *Specification) addAppArmorRules(add func(aspec *apparmor.Specification) error) error {
+	aspec := apparmor.NewSpecification(spec.appSet)
+	if err := add(aspec); err != nil {
+		return err
+	}
+	for _, tag := range aspec.SecurityTags() {
+		spec.securityTags = []string{tag}
+		spec.AddRules(rulesFromAppArmor(aspec.SnippetForTag(tag)))
+	}
+	spec.securityTags = nil
+	return nil
+}
+
+func (spec *Specification) addAppArmorConnectedPlug(iface interfaces.Interface, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	return spec.addAppArmorRules(func(aspec *apparmor.Specification) error {
+		return aspec.AddConnectedPlug(iface, plug, slot)
+	})
+}
+
+func (spec *Specification) addAppArmorConnectedSlot(iface interfaces.Interface, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	return spec.addAppArmorRules(func(aspec *apparmor.Specification) error {
+		return aspec.AddConnectedSlot(iface, plug, slot)
+	})
+}
+
+func (spec *Specification) addAppArmorPermanentPlug(iface interfaces.Interface, plug *snap.PlugInfo) error {
+	return spec.addAppArmorRules(func(aspec *apparmor.Specification) error {
+		return aspec.AddPermanentPlug(iface, plug)
+	})
+}
+
+func (spec *Specification) addAppArmorPermanentSlot(iface interfaces.Interface, slot *snap.SlotInfo) error {
+	return spec.addAppArmorRules(func(aspec *apparmor.Specification) error {
+		return aspec.AddPermanentSlot(iface, slot)
+	})
+}
@@ -0,0 +1,200 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package landlock_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/interfaces/landlock"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
+	"github.com/snapcore/snapd/snap"
+)
+
+type appArmorSuite struct{}
+
+var _ = Suite(&appArmorSuite{})
+
+const (
+	r   = landlock_sandbox.AccessRead
+	rw  = landlock_sandbox.AccessRead | landlock_sandbox.AccessWrite
+	rwx = landlock_sandbox.AccessRead | landlock_sandbox.AccessWrite | landlock_sandbox.AccessExecute
+)
+
+func (s *appArmorSuite) TestRulesFromAppArmor(c *C) {
+	const snippet = `
+# Description: some interface
+/dev/ttyUSB[0-9]* rw,
+owner @{HOME}/.config/foo/** rwk,
+/{,run/}media/*/** mrwklix,
+"/srv/some dir/file" r,
+/usr/bin/foo Px -> foo,
+@{PROC}/@{pid}/mounts r,
+/run/ r,
+/** r,
+/*/foo r,
+/var/lib/foo/ r,
+deny /etc/shadow r,
+dbus (send) bus=system,
+profile foo {
+  /etc/secret r,
+}
+/sys/class/foo/ r, # trailing comment
+/sys/devices/**/foo r, # trailing comment
+###PROMPT### /dev/video[0-9]* rwk,
+###PROMPT### owner @{HOME}/[^s.]** rwkl###HOME_IX###,
+owner @{HOME}/** r,
+`
+	c.Check(landlock.RulesFromAppArmor(snippet), DeepEquals, []landlock_sandbox.Rule{
+		{Access: rw, Path: "/dev/ttyUSB[0-9]*"},
+		{Access: rw, Path: "$HOME/.config/foo"},
+		{Access: rwx, Path: "/media/*"},
+		{Access: rwx, Path: "/run/media/*"},
+		{Access: r, Path: "/srv/some dir/file"},
+		{Access: landlock_sandbox.AccessExecute, Path: "/usr/bin/foo"},
+		{Access: r, Path: "/proc/*/mounts"},
+		{Access: rw, Path: "/dev/video[0-9]*"},
+		{Access: rw, Path: "$HOME/[^s.]*"},
+		{Access: r, Path: "$HOME/[^.]*"},
+	})
+}
+
+func (s *appArmorSuite) TestExpandAppArmorAlternations(c *C) {
+	for _, t := range []struct {
+		pattern  string
+		expanded []string
+	}{
+		{"/foo", []string{"/foo"}},
+		{"/{a,b}/c", []string{"/a/c", "/b/c"}},
+		{"/{,var/lib/snapd/}snap", []string{"/snap", "/var/lib/snapd/snap"}},
+		{"/{a,b{c,d}}/{e,f}", []string{"/a/e", "/a/f", "/bc/e", "/bc/f", "/bd/e", "/bd/f"}},
+		{"/{a,b", []string{"/{a,b"}},
+	} {
+		c.Check(landlock.ExpandAppArmorAlternations(t.pattern), DeepEquals, t.expanded, Commentf("%q", t.pattern))
+	}
+}
+
+func (s *appArmorSuite) TestLandlockPath(c *C) {
+	for _, t := range []struct {
+		pattern string
+		path    string
+	}{
+		{"/etc/foo", "/etc/foo"},
+		{"/etc/foo/**", "/etc/foo"},
+		{"/etc/foo/bar**", "/etc/foo/bar*"},
+		{"/etc/foo/*/**", "/etc/foo/*"},
+		{"/sys/devices/**/foo", ""},
+		{"/sys/devices/pci**/usb[0-9]**", ""},
+		{"$HOME/foo/", ""},
+		{"$HOME/foo", "$HOME/foo"},
+		{"$HOME/**", "$HOME/[^.]*"},
+		{"$HOME/", ""},
+		{"$HOME", ""},
+		{"$HOME/*", "$HOME/[^.]*"},
+		{"$HOME/?oo", "$HOME/[^.]oo"},
+		{"$HOME/[^s.]**", "$HOME/[^s.]*"},
+		{"$HOME/.ssh/**", "$HOME/.ssh"},
+		{"/etc/", ""},
+		{"/**", ""},
+		{"/", ""},
+		{"/*/foo", ""},
+		{"/[a-z]*/foo", ""},
+		{"foo", ""},
+	} {
+		c.Check(landlock.LandlockPath(t.pattern), Equals, t.path, Commentf("%q", t.pattern))
+	}
+}
+
+func (s *appArmorSuite) TestSpecificationFallsBackToAppArmor(c *C) {
+	iface := &appArmorOnlyInterface{}
+	const plugYaml = `name: snap1
+version: 1
+plugs:
+ name:
+  interface: apparmor-only
+apps:
+ app1:
+  plugs: [name]
+`
+	plug, plugInfo := ifacetest.MockConnectedPlug(c, plugYaml, nil, "name")
+	const slotYaml = `name: snap2
+version: 1
+slots:
+ name:
+  interface: apparmor-only
+apps:
+ app2:
+`
+	slot, slotInfo := ifacetest.MockConnectedSlot(c, slotYaml, nil, "name")
+
+	appSet, err := interfaces.NewSnapAppSet(plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec := landlock.NewSpecification(appSet)
+	c.Assert(spec.AddConnectedPlug(iface, plug, slot), IsNil)
+	c.Assert(spec.AddPermanentPlug(iface, plugInfo), IsNil)
+	c.Check(spec.SecurityTags(), DeepEquals, []string{"snap.snap1.app1"})
+	c.Check(spec.RulesForTag("snap.snap1.app1"), DeepEquals, []landlock_sandbox.Rule{
+		{Access: r, Path: "/etc/connected-plug"},
+		{Access: r, Path: "/etc/permanent-plug"},
+	})
+
+	appSet, err = interfaces.NewSnapAppSet(slot.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec = landlock.NewSpecification(appSet)
+	c.Assert(spec.AddConnectedSlot(iface, plug, slot), IsNil)
+	c.Assert(spec.AddPermanentSlot(iface, slotInfo), IsNil)
+	c.Check(spec.RulesForTag("snap.snap2.app2"), DeepEquals, []landlock_sandbox.Rule{
+		{Access: rw, Path: "/srv/connected-slot"},
+		{Access: rw, Path: "/srv/permanent-slot"},
+	})
+}
+
+// appArmorOnlyInterface is an interface which only has AppArmor rules.
+type appArmorOnlyInterface struct{}
+
+func (iface *appArmorOnlyInterface) Name() string {
+	return "apparmor-only"
+}
+
+func (iface *appArmorOnlyInterface) AutoConnect(*snap.PlugInfo, *snap.SlotInfo) bool {
+	return true
+}
+
+func (iface *appArmorOnlyInterface) AppArmorConnectedPlug(spec *apparmor.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	spec.AddSnippet("/etc/connected-plug r,")
+	return nil
+}
+
+func (iface *appArmorOnlyInterface) AppArmorConnectedSlot(spec *apparmor.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	spec.AddSnippet("/srv/connected-slot rw,")
+	return nil
+}
+
+func (iface *appArmorOnlyInterface) AppArmorPermanentPlug(spec *apparmor.Specification, plug *snap.PlugInfo) error {
+	spec.AddSnippet("/etc/permanent-plug r,")
+	return nil
+}
+
+func (iface *appArmorOnlyInterface) AppArmorPermanentSlot(spec *apparmor.Specification, slot *snap.SlotInfo) error {
+	spec.AddSnippet("/srv/permanent-slot rw,")
+	return nil
+}
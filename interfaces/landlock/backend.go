@@ -0,0 +1,165 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package landlock implements integration between snapd and the Landlock
+// Linux security module.
+//
+// Interfaces grant access to paths by calling AddPath on the landlock
+// Specification. The backend combines the default rules with the rules of
+// the interfaces into a ruleset per security tag, stored in
+// /var/lib/snapd/landlock/rulesets. The ruleset is applied by snap-exec
+// right before executing the application or hook.
+//
+// The backend is only used on systems where AppArmor is not available, as
+// a fallback providing filesystem confinement to strictly confined snaps.
+package landlock
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/sandbox/landlock"
+	"github.com/snapcore/snapd/timings"
+)
+
+// Backend is responsible for maintaining landlock rulesets for snap
+// applications and hooks.
+type Backend struct{}
+
+// Initialize does nothing.
+func (b *Backend) Initialize(*interfaces.SecurityBackendOptions) error {
+	return nil
+}
+
+// Name returns the name of the backend.
+func (b *Backend) Name() interfaces.SecuritySystem {
+	return interfaces.SecurityLandlock
+}
+
+func (b *Backend) Prepare(_ *interfaces.SnapAppSet) error {
+	// No preparation required.
+	return nil
+}
+
+// Setup creates landlock rulesets specific to a given snap.
+//
+// No rulesets are written for snaps in developer mode or with classic
+// confinement, as Landlock cannot log violations without enforcing them.
+//
+// This method should be called after changing plug, slots, connections between
+// them or application present in the snap.
+func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, sctx interfaces.SetupContext, repo *interfaces.Repository, tm timings.Measurer) error {
+	snapName := appSet.InstanceName()
+	spec, err := repo.SnapSpecification(b.Name(), appSet, opts)
+	if err != nil {
+		return fmt.Errorf("cannot obtain landlock specification for snap %q: %s", snapName, err)
+	}
+
+	content, err := deriveContent(spec.(*Specification), opts, appSet)
+	if err != nil {
+		return fmt.Errorf("cannot obtain expected landlock rulesets for snap %q: %s", snapName, err)
+	}
+
+	dir := dirs.SnapLandlockDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create directory for landlock rulesets %q: %s", dir, err)
+	}
+
+	globs := interfaces.SecurityTagGlobs(snapName)
+	if _, _, err := osutil.EnsureDirStateGlobs(dir, globs, content); err != nil {
+		return fmt.Errorf("cannot synchronize landlock rulesets for snap %q: %s", snapName, err)
+	}
+	return nil
+}
+
+// Remove removes landlock rulesets of a given snap.
+func (b *Backend) Remove(snapName string) error {
+	globs := interfaces.SecurityTagGlobs(snapName)
+	_, _, err := osutil.EnsureDirStateGlobs(dirs.SnapLandlockDir, globs, nil)
+	if err != nil {
+		return fmt.Errorf("cannot synchronize landlock rulesets for snap %q: %s", snapName, err)
+	}
+	return nil
+}
+
+// deriveContent combines the default rules with the rules collected from all
+// the interfaces affecting a given snap into a content map applicable to
+// EnsureDirState.
+func deriveContent(spec *Specification, opts interfaces.ConfinementOptions, appSet *interfaces.SnapAppSet) (content map[string]osutil.FileState, err error) {
+	if (opts.DevMode || opts.Classic) && !opts.JailMode {
+		return nil, nil
+	}
+	for _, r := range appSet.Runnables() {
+		if content == nil {
+			content = make(map[string]osutil.FileState)
+		}
+		var buffer bytes.Buffer
+		buffer.WriteString("# This file is automatically generated.\n")
+		if err := landlock.FormatRuleset(&buffer, defaultRules); err != nil {
+			return nil, err
+		}
+		if err := landlock.FormatRuleset(&buffer, spec.RulesForTag(r.SecurityTag)); err != nil {
+			return nil, err
+		}
+		content[r.SecurityTag] = &osutil.MemoryFileState{
+			Content: buffer.Bytes(),
+			Mode:    0644,
+		}
+	}
+	return content, nil
+}
+
+// SnapContributions returns nil, landlock rulesets are only derived from
+// interfaces and the default rules.
+func (b *Backend) SnapContributions(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) []*interfaces.SpecificationContribution {
+	return nil
+}
+
+// RenderProfiles returns the landlock rulesets of the snap derived from the
+// given specification, without writing them.
+func (b *Backend) RenderProfiles(spec interfaces.Specification, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) (map[string][]byte, error) {
+	content, err := deriveContent(spec.(*Specification), opts, appSet)
+	if err != nil {
+		return nil, err
+	}
+	rendered := make(map[string][]byte, len(content))
+	for name, state := range content {
+		rendered[name] = state.(*osutil.MemoryFileState).Content
+	}
+	return rendered, nil
+}
+
+// NewSpecification returns an empty landlock specification.
+func (b *Backend) NewSpecification(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) interfaces.Specification {
+	return &Specification{appSet: appSet}
+}
+
+// SandboxFeatures returns the list of landlock features supported by the
+// kernel.
+func (b *Backend) SandboxFeatures() []string {
+	abi := landlock.ProbedABI()
+	if abi == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("abi:%d", abi)}
+}
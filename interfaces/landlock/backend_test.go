@@ -0,0 +1,185 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package landlock_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/interfaces/landlock"
+	"github.com/snapcore/snapd/osutil"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type backendSuite struct {
+	ifacetest.BackendSuite
+}
+
+var _ = Suite(&backendSuite{})
+
+const defaultRuleset = `# This file is automatically generated.
+rx /bin
+rx /lib
+rx /lib32
+rx /lib64
+rx /libx32
+rx /opt
+rx /sbin
+rx /snap
+rx /usr
+rx /var/lib/snapd/lib
+rx /var/lib/snapd/snap
+r /etc
+r /proc/self
+r /proc/cpuinfo
+r /proc/filesystems
+r /proc/loadavg
+r /proc/meminfo
+r /proc/stat
+r /proc/uptime
+r /proc/version
+r /proc/sys/fs/file-max
+r /proc/sys/fs/file-nr
+r /proc/sys/fs/nr_open
+r /proc/sys/fs/pipe-max-size
+r /proc/sys/kernel/cap_last_cap
+r /proc/sys/kernel/hostname
+r /proc/sys/kernel/osrelease
+r /proc/sys/kernel/ostype
+r /proc/sys/kernel/pid_max
+r /proc/sys/kernel/random/boot_id
+r /proc/sys/kernel/random/uuid
+r /proc/sys/vm/overcommit_memory
+r /sys/devices/system/cpu
+r /sys/devices/system/node
+r /sys/kernel/mm/transparent_hugepage
+r /sys/module/apparmor/parameters/enabled
+rw /run/snapd.socket
+rw /run/snapd-snap.socket
+r /dev/random
+r /dev/urandom
+rw /dev/full
+rw /dev/null
+rw /dev/ptmx
+rw /dev/pts
+rw /dev/shm
+rw /dev/tty
+rw /dev/zero
+rw /run/lock/snap.$SNAP_INSTANCE_NAME
+rw /run/snap.$SNAP_INSTANCE_NAME
+rw /run/uuidd/request
+rw /tmp
+rw /var/tmp
+rw $SNAP_DATA
+rw $SNAP_COMMON
+rw $SNAP_USER_DATA
+rw $SNAP_USER_COMMON
+rw $XDG_RUNTIME_DIR
+`
+
+func (s *backendSuite) SetUpTest(c *C) {
+	s.Backend = &landlock.Backend{}
+	s.BackendSuite.SetUpTest(c)
+	c.Assert(s.Repo.AddBackend(s.Backend), IsNil)
+}
+
+func (s *backendSuite) TearDownTest(c *C) {
+	s.BackendSuite.TearDownTest(c)
+}
+
+func (s *backendSuite) TestName(c *C) {
+	c.Check(s.Backend.Name(), Equals, interfaces.SecurityLandlock)
+}
+
+func (s *backendSuite) TestInstallingSnapWritesRulesets(c *C) {
+	s.Iface.LandlockPermanentSlotCallback = func(spec *landlock.Specification, slot *snap.SlotInfo) error {
+		spec.AddPath("/srv/samba", landlock_sandbox.AccessRead|landlock_sandbox.AccessWrite)
+		return nil
+	}
+
+	for _, opts := range []interfaces.ConfinementOptions{{}, {DevMode: true, JailMode: true}} {
+		snapInfo := s.InstallSnap(c, opts, "", ifacetest.SambaYamlV1, 0)
+		path := filepath.Join(dirs.SnapLandlockDir, "snap.samba.smbd")
+		c.Check(path, testutil.FileEquals, defaultRuleset+"rw /srv/samba\n")
+		s.RemoveSnap(c, snapInfo)
+		c.Check(path, testutil.FileAbsent)
+	}
+}
+
+func (s *backendSuite) TestNoRulesetsForNonStrictSnaps(c *C) {
+	for _, opts := range []interfaces.ConfinementOptions{{DevMode: true}, {Classic: true}} {
+		snapInfo := s.InstallSnap(c, opts, "", ifacetest.SambaYamlV1, 0)
+		c.Check(filepath.Join(dirs.SnapLandlockDir, "snap.samba.smbd"), testutil.FileAbsent)
+		s.RemoveSnap(c, snapInfo)
+	}
+}
+
+func (s *backendSuite) TestUpdatingSnapRemovesStaleRulesets(c *C) {
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1WithNmbd, 0)
+	c.Check(filepath.Join(dirs.SnapLandlockDir, "snap.samba.nmbd"), testutil.FilePresent)
+	s.UpdateSnap(c, snapInfo, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1, 0)
+	c.Check(filepath.Join(dirs.SnapLandlockDir, "snap.samba.nmbd"), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapLandlockDir, "snap.samba.smbd"), testutil.FilePresent)
+}
+
+func (s *backendSuite) TestRenderProfiles(c *C) {
+	snapInfo := snaptest.MockInfo(c, ifacetest.SambaYamlV1, nil)
+	appSet, err := interfaces.NewSnapAppSet(snapInfo, nil)
+	c.Assert(err, IsNil)
+	spec := s.Backend.NewSpecification(appSet, interfaces.ConfinementOptions{})
+
+	renderer := s.Backend.(interfaces.SecurityBackendRenderer)
+	profiles, err := renderer.RenderProfiles(spec, appSet, interfaces.ConfinementOptions{})
+	c.Assert(err, IsNil)
+	c.Check(profiles, DeepEquals, map[string][]byte{
+		"snap.samba.smbd": []byte(defaultRuleset),
+	})
+	c.Check(renderer.SnapContributions(appSet, interfaces.ConfinementOptions{}), IsNil)
+	// nothing was written
+	c.Check(osutil.IsDirectory(dirs.SnapLandlockDir), Equals, false)
+}
+
+func (s *backendSuite) TestSandboxFeatures(c *C) {
+	restore := landlock_sandbox.MockABI(3)
+	defer restore()
+	c.Check(s.Backend.SandboxFeatures(), DeepEquals, []string{"abi:3"})
+
+	landlock_sandbox.MockABI(0)
+	c.Check(s.Backend.SandboxFeatures(), HasLen, 0)
+}
+
+func (s *backendSuite) TestRulesetsCanBeParsed(c *C) {
+	rules, err := landlock_sandbox.ParseRuleset(strings.NewReader(defaultRuleset))
+	c.Assert(err, IsNil)
+	c.Check(rules, HasLen, 57)
+}
@@ -0,0 +1,211 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package landlock
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/sandbox/landlock"
+	"github.com/snapcore/snapd/snap"
+)
+
+// Specification keeps the filesystem access granted by interfaces, indexed
+// by security tag.
+type Specification struct {
+	appSet *interfaces.SnapAppSet
+	// access is indexed by security tag and rule key.
+	access       map[string]map[ruleKey]landlock.Access
+	securityTags []string
+	// users are the users the connection in scope is limited to
+	users []int
+	// usersByKey maps the keys of the user lists back to the lists
+	usersByKey map[string][]int
+}
+
+// ruleKey identifies the rules for a path which apply to the same users.
+type ruleKey struct {
+	path  string
+	users string
+}
+
+func NewSpecification(appSet *interfaces.SnapAppSet) *Specification {
+	return &Specification{
+		appSet: appSet,
+	}
+}
+
+func (spec *Specification) SnapAppSet() *interfaces.SnapAppSet {
+	return spec.appSet
+}
+
+// AddPath grants the given access to the path and everything beneath it.
+// The path may start with a variable of the snap environment, such as
+// $SNAP_DATA or $HOME, which is expanded when the ruleset is applied.
+//
+// The access is only granted to the users the connection in scope is
+// limited to, if any.
+func (spec *Specification) AddPath(path string, access landlock.Access) {
+	if len(spec.securityTags) == 0 || access == 0 {
+		return
+	}
+	if spec.access == nil {
+		spec.access = make(map[string]map[ruleKey]landlock.Access)
+	}
+	key := ruleKey{path: path}
+	if len(spec.users) > 0 {
+		key.users = fmt.Sprint(spec.users)
+		if spec.usersByKey == nil {
+			spec.usersByKey = make(map[string][]int)
+		}
+		spec.usersByKey[key.users] = spec.users
+	}
+	for _, tag := range spec.securityTags {
+		if spec.access[tag] == nil {
+			spec.access[tag] = make(map[ruleKey]landlock.Access)
+		}
+		spec.access[tag][key] |= access
+	}
+}
+
+// AddRules grants the access of all the given rules.
+func (spec *Specification) AddRules(rules []landlock.Rule) {
+	for _, r := range rules {
+		spec.AddPath(r.Path, r.Access)
+	}
+}
+
+// RulesForTag returns the rules for the given security tag, sorted by path.
+// The rules for all the users come before the ones limited to some users.
+func (spec *Specification) RulesForTag(tag string) []landlock.Rule {
+	keys := make([]ruleKey, 0, len(spec.access[tag]))
+	for key := range spec.access[tag] {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].users != keys[j].users {
+			return keys[i].users < keys[j].users
+		}
+		return keys[i].path < keys[j].path
+	})
+	rules := make([]landlock.Rule, 0, len(keys))
+	for _, key := range keys {
+		rules = append(rules, landlock.Rule{
+			Access: spec.access[tag][key],
+			Path:   key.path,
+			Users:  spec.usersByKey[key.users],
+		})
+	}
+	return rules
+}
+
+// SecurityTags returns a list of security tags which have rules.
+func (spec *Specification) SecurityTags() []string {
+	tags := make([]string, 0, len(spec.access))
+	for t := range spec.access {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Implementation of methods required by interfaces.Specification
+//
+// Interfaces which do not define their landlock rules get the ones derived
+// from their AppArmor rules.
+
+// AddConnectedPlug records landlock-specific side-effects of having a connected plug.
+func (spec *Specification) AddConnectedPlug(iface interfaces.Interface, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	type definer interface {
+		LandlockConnectedPlug(spec *Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
+	}
+	if iface, ok := iface.(definer); ok {
+		tags, err := spec.appSet.SecurityTagsForConnectedPlug(plug)
+		if err != nil {
+			return err
+		}
+
+		spec.securityTags = tags
+		spec.users = plug.Users()
+		defer func() {
+			spec.securityTags = nil
+			spec.users = nil
+		}()
+		return iface.LandlockConnectedPlug(spec, plug, slot)
+	}
+	spec.users = plug.Users()
+	defer func() { spec.users = nil }()
+	return spec.addAppArmorConnectedPlug(iface, plug, slot)
+}
+
+// AddConnectedSlot records landlock-specific side-effects of having a connected slot.
+func (spec *Specification) AddConnectedSlot(iface interfaces.Interface, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	type definer interface {
+		LandlockConnectedSlot(spec *Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
+	}
+	if iface, ok := iface.(definer); ok {
+		tags, err := spec.appSet.SecurityTagsForConnectedSlot(slot)
+		if err != nil {
+			return err
+		}
+
+		spec.securityTags = tags
+		defer func() { spec.securityTags = nil }()
+		return iface.LandlockConnectedSlot(spec, plug, slot)
+	}
+	return spec.addAppArmorConnectedSlot(iface, plug, slot)
+}
+
+// AddPermanentPlug records landlock-specific side-effects of having a plug.
+func (spec *Specification) AddPermanentPlug(iface interfaces.Interface, plug *snap.PlugInfo) error {
+	type definer interface {
+		LandlockPermanentPlug(spec *Specification, plug *snap.PlugInfo) error
+	}
+	if iface, ok := iface.(definer); ok {
+		tags, err := spec.appSet.SecurityTagsForPlug(plug)
+		if err != nil {
+			return err
+		}
+
+		spec.securityTags = tags
+		defer func() { spec.securityTags = nil }()
+		return iface.LandlockPermanentPlug(spec, plug)
+	}
+	return spec.addAppArmorPermanentPlug(iface, plug)
+}
+
+// AddPermanentSlot records landlock-specific side-effects of having a slot.
+func (spec *Specification) AddPermanentSlot(iface interfaces.Interface, slot *snap.SlotInfo) error {
+	type definer interface {
+		LandlockPermanentSlot(spec *Specification, slot *snap.SlotInfo) error
+	}
+	if iface, ok := iface.(definer); ok {
+		tags, err := spec.appSet.SecurityTagsForSlot(slot)
+		if err != nil {
+			return err
+		}
+
+		spec.securityTags = tags
+		defer func() { spec.securityTags = nil }()
+		return iface.LandlockPermanentSlot(spec, slot)
+	}
+	return spec.addAppArmorPermanentSlot(iface, slot)
+}
@@ -0,0 +1,156 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package landlock_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/interfaces/landlock"
+	landlock_sandbox "github.com/snapcore/snapd/sandbox/landlock"
+	"github.com/snapcore/snapd/snap"
+)
+
+type specSuite struct {
+	iface    *ifacetest.TestInterface
+	plugInfo *snap.PlugInfo
+	plug     *interfaces.ConnectedPlug
+	slotInfo *snap.SlotInfo
+	slot     *interfaces.ConnectedSlot
+}
+
+var _ = Suite(&specSuite{
+	iface: &ifacetest.TestInterface{
+		InterfaceName: "test",
+		LandlockConnectedPlugCallback: func(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+			spec.AddPath("/media", landlock_sandbox.AccessRead)
+			return nil
+		},
+		LandlockConnectedSlotCallback: func(spec *landlock.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+			spec.AddPath("/srv", landlock_sandbox.AccessRead|landlock_sandbox.AccessWrite)
+			return nil
+		},
+		LandlockPermanentPlugCallback: func(spec *landlock.Specification, plug *snap.PlugInfo) error {
+			spec.AddPath("/media", landlock_sandbox.AccessWrite)
+			spec.AddPath("$HOME", landlock_sandbox.AccessRead)
+			return nil
+		},
+		LandlockPermanentSlotCallback: func(spec *landlock.Specification, slot *snap.SlotInfo) error {
+			spec.AddRules([]landlock_sandbox.Rule{{Access: landlock_sandbox.AccessExecute, Path: "/opt"}})
+			return nil
+		},
+	},
+})
+
+func (s *specSuite) SetUpTest(c *C) {
+	const plugYaml = `name: snap1
+version: 1
+apps:
+ app1:
+  plugs: [name]
+`
+	s.plug, s.plugInfo = ifacetest.MockConnectedPlug(c, plugYaml, nil, "name")
+
+	const slotYaml = `name: snap2
+version: 1
+slots:
+ name:
+  interface: test
+apps:
+ app2:
+`
+	s.slot, s.slotInfo = ifacetest.MockConnectedSlot(c, slotYaml, nil, "name")
+}
+
+// The landlock.Specification can be used through the interfaces.Specification interface
+func (s *specSuite) TestSpecificationIface(c *C) {
+	appSet, err := interfaces.NewSnapAppSet(s.plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec := landlock.NewSpecification(appSet)
+	var r interfaces.Specification = spec
+	c.Assert(r.AddConnectedPlug(s.iface, s.plug, s.slot), IsNil)
+	c.Assert(r.AddPermanentPlug(s.iface, s.plugInfo), IsNil)
+	c.Assert(spec.SecurityTags(), DeepEquals, []string{"snap.snap1.app1"})
+	c.Assert(spec.RulesForTag("snap.snap1.app1"), DeepEquals, []landlock_sandbox.Rule{
+		{Access: landlock_sandbox.AccessRead, Path: "$HOME"},
+		{Access: landlock_sandbox.AccessRead | landlock_sandbox.AccessWrite, Path: "/media"},
+	})
+
+	appSet, err = interfaces.NewSnapAppSet(s.slot.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec = landlock.NewSpecification(appSet)
+	r = spec
+	c.Assert(r.AddConnectedSlot(s.iface, s.plug, s.slot), IsNil)
+	c.Assert(r.AddPermanentSlot(s.iface, s.slotInfo), IsNil)
+	c.Assert(spec.SecurityTags(), DeepEquals, []string{"snap.snap2.app2"})
+	c.Assert(spec.RulesForTag("snap.snap2.app2"), DeepEquals, []landlock_sandbox.Rule{
+		{Access: landlock_sandbox.AccessExecute, Path: "/opt"},
+		{Access: landlock_sandbox.AccessRead | landlock_sandbox.AccessWrite, Path: "/srv"},
+	})
+
+	c.Assert(spec.RulesForTag("non-existing"), HasLen, 0)
+}
+
+func (s *specSuite) TestConnectedPlugLimitedToUsers(c *C) {
+	const plugYaml = `name: snap1
+version: 1
+plugs:
+ name:
+  interface: test
+apps:
+ app1:
+  plugs: [name]
+`
+	plug, plugInfo := ifacetest.MockConnectedPlug(c, plugYaml, nil, "name")
+	repo := interfaces.NewRepository()
+	c.Assert(repo.AddInterface(s.iface), IsNil)
+	plugAppSet, err := interfaces.NewSnapAppSet(plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.AddAppSet(plugAppSet), IsNil)
+	slotAppSet, err := interfaces.NewSnapAppSet(s.slot.Snap(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.AddAppSet(slotAppSet), IsNil)
+	connRef := interfaces.NewConnRef(plugInfo, s.slotInfo)
+	_, err = repo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.SetConnectionUsers(connRef, []int{1001, 1000}), IsNil)
+	conn, err := repo.Connection(connRef)
+	c.Assert(err, IsNil)
+
+	spec := landlock.NewSpecification(plugAppSet)
+	c.Assert(spec.AddConnectedPlug(s.iface, conn.Plug, conn.Slot), IsNil)
+	c.Assert(spec.AddPermanentPlug(s.iface, plugInfo), IsNil)
+	// the access granted by the connection is limited to its users
+	c.Assert(spec.RulesForTag("snap.snap1.app1"), DeepEquals, []landlock_sandbox.Rule{
+		{Access: landlock_sandbox.AccessRead, Path: "$HOME"},
+		{Access: landlock_sandbox.AccessWrite, Path: "/media"},
+		{Access: landlock_sandbox.AccessRead, Path: "/media", Users: []int{1000, 1001}},
+	})
+}
+
+func (s *specSuite) TestAddPathOutsideOfDefiner(c *C) {
+	appSet, err := interfaces.NewSnapAppSet(s.plug.Snap(), nil)
+	c.Assert(err, IsNil)
+	spec := landlock.NewSpecification(appSet)
+	// without security tags in scope nothing is recorded
+	spec.AddPath("/media", landlock_sandbox.AccessRead)
+	c.Assert(spec.SecurityTags(), HasLen, 0)
+}
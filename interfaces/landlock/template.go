@@ -0,0 +1,98 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package landlock
+
+import (
+	"github.com/snapcore/snapd/sandbox/landlock"
+)
+
+// defaultRules is the base ruleset of every snap application. It allows the
+// snap to run programs from the system and from snaps, to read the system
+// configuration and state, and to write its own data directories. It follows
+// the default AppArmor template, access to other locations, such as
+// /media, /mnt, /srv or /var/log, is granted by interfaces.
+//
+// Unlike AppArmor, Landlock only mediates access to paths and cannot tell
+// apart files beneath an allowed directory, the rules therefore list the
+// files of /proc, /sys and /run the template allows rather than their
+// directories, which would expose the state of the other processes and
+// snaps. In particular /dev cannot be listed, as reading the directory would
+// grant reading every device, and all of /dev/shm is writable rather than
+// the snap's own files only. The paths are resolved when the ruleset is
+// applied, /proc/self is the directory of the process of the application.
+var defaultRules = []landlock.Rule{
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/bin"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/lib"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/lib32"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/lib64"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/libx32"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/opt"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/sbin"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/snap"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/usr"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/var/lib/snapd/lib"},
+	{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/var/lib/snapd/snap"},
+	{Access: landlock.AccessRead, Path: "/etc"},
+	{Access: landlock.AccessRead, Path: "/proc/self"},
+	{Access: landlock.AccessRead, Path: "/proc/cpuinfo"},
+	{Access: landlock.AccessRead, Path: "/proc/filesystems"},
+	{Access: landlock.AccessRead, Path: "/proc/loadavg"},
+	{Access: landlock.AccessRead, Path: "/proc/meminfo"},
+	{Access: landlock.AccessRead, Path: "/proc/stat"},
+	{Access: landlock.AccessRead, Path: "/proc/uptime"},
+	{Access: landlock.AccessRead, Path: "/proc/version"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/fs/file-max"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/fs/file-nr"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/fs/nr_open"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/fs/pipe-max-size"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/kernel/cap_last_cap"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/kernel/hostname"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/kernel/osrelease"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/kernel/ostype"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/kernel/pid_max"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/kernel/random/boot_id"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/kernel/random/uuid"},
+	{Access: landlock.AccessRead, Path: "/proc/sys/vm/overcommit_memory"},
+	{Access: landlock.AccessRead, Path: "/sys/devices/system/cpu"},
+	{Access: landlock.AccessRead, Path: "/sys/devices/system/node"},
+	{Access: landlock.AccessRead, Path: "/sys/kernel/mm/transparent_hugepage"},
+	{Access: landlock.AccessRead, Path: "/sys/module/apparmor/parameters/enabled"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/run/snapd.socket"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/run/snapd-snap.socket"},
+	{Access: landlock.AccessRead, Path: "/dev/random"},
+	{Access: landlock.AccessRead, Path: "/dev/urandom"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/dev/full"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/dev/null"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/dev/ptmx"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/dev/pts"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/dev/shm"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/dev/tty"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/dev/zero"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/run/lock/snap.$SNAP_INSTANCE_NAME"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/run/snap.$SNAP_INSTANCE_NAME"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/run/uuidd/request"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/tmp"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/var/tmp"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$SNAP_DATA"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$SNAP_COMMON"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$SNAP_USER_DATA"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$SNAP_USER_COMMON"},
+	{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$XDG_RUNTIME_DIR"},
+}
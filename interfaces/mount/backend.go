@@ -82,10 +82,9 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 
 	snapInfo := appSet.Info()
 
-	ms := spec.(*Specification)
-	ms.AddOvername(snapInfo)
-	ms.AddLayout(snapInfo)
-	ms.AddExtraLayouts(opts.ExtraLayouts)
+	for _, contrib := range b.SnapContributions(appSet, opts) {
+		contrib.Add(spec)
+	}
 	content := deriveContent(spec.(*Specification), snapInfo)
 	// synchronize the content with the filesystem
 	glob := fmt.Sprintf("snap.%s.*fstab", snapName)
@@ -196,6 +195,39 @@ func deriveContent(spec *Specification, snapInfo *snap.Info) map[string]osutil.F
 	return content
 }
 
+// SnapContributions returns the contributions to the specification of the
+// snap that are derived from the snap itself: the mount entries of parallel
+// instances and the ones for its layout.
+func (b *Backend) SnapContributions(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) []*interfaces.SpecificationContribution {
+	snapInfo := appSet.Info()
+	return []*interfaces.SpecificationContribution{{
+		Origin: interfaces.SpecificationOrigin{Kind: interfaces.OriginParallelInstance},
+		Add: func(spec interfaces.Specification) error {
+			spec.(*Specification).AddOvername(snapInfo)
+			return nil
+		},
+	}, {
+		Origin: interfaces.SpecificationOrigin{Kind: interfaces.OriginLayout},
+		Add: func(spec interfaces.Specification) error {
+			spec.(*Specification).AddLayout(snapInfo)
+			spec.(*Specification).AddExtraLayouts(opts.ExtraLayouts)
+			return nil
+		},
+	}}
+}
+
+// RenderProfiles returns the mount profiles of the snap derived from the
+// given specification, without writing them or updating the mount
+// namespace of the snap.
+func (b *Backend) RenderProfiles(spec interfaces.Specification, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) (map[string][]byte, error) {
+	content := deriveContent(spec.(*Specification), appSet.Info())
+	rendered := make(map[string][]byte, len(content))
+	for name, state := range content {
+		rendered[name] = state.(*osutil.MemoryFileState).Content
+	}
+	return rendered, nil
+}
+
 // NewSpecification returns a new mount specification.
 func (b *Backend) NewSpecification(*interfaces.SnapAppSet, interfaces.ConfinementOptions) interfaces.Specification {
 	return &Specification{}
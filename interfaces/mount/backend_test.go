@@ -1002,3 +1002,38 @@ func (s *backendSuite) mockCgroup(c *C, dir string) {
 	finalPath := filepath.Join(path, "cgroup.procs")
 	c.Assert(os.WriteFile(finalPath, []byte("222222\n33333\n"), 0644), IsNil)
 }
+
+func (s *backendSuite) TestRenderProfiles(c *C) {
+	old := dirs.SnapDataDir
+	defer func() {
+		dirs.SnapDataDir = old
+	}()
+	dirs.SnapDataDir = "/var/snap"
+	fsEntry := osutil.MountEntry{Name: "/src-1", Dir: "/dst-1", Type: "none", Options: []string{"bind", "ro"}}
+	s.Iface.MountPermanentPlugCallback = func(spec *mount.Specification, plug *snap.PlugInfo) error {
+		return spec.AddMountEntry(fsEntry)
+	}
+
+	appSet := s.AddSnap(c, "snap-name_instance", mockSnapYaml, 0)
+	opts := interfaces.ConfinementOptions{}
+	renderer := s.Backend.(interfaces.SecurityBackendRenderer)
+	spec, err := s.Repo.SnapSpecification(s.Backend.Name(), appSet, opts)
+	c.Assert(err, IsNil)
+	contribs := renderer.SnapContributions(appSet, opts)
+	c.Assert(contribs, HasLen, 2)
+	c.Check(contribs[0].Origin.Kind, Equals, interfaces.OriginParallelInstance)
+	c.Check(contribs[1].Origin.Kind, Equals, interfaces.OriginLayout)
+	for _, contrib := range contribs {
+		c.Assert(contrib.Add(spec), IsNil)
+	}
+
+	profiles, err := renderer.RenderProfiles(spec, appSet, opts)
+	c.Assert(err, IsNil)
+	snapEntry := osutil.MountEntry{Name: "/snap/snap-name_instance", Dir: "/snap/snap-name", Type: "none", Options: []string{"rbind", osutil.XSnapdOriginOvername()}}
+	dataEntry := osutil.MountEntry{Name: "/var/snap/snap-name_instance", Dir: "/var/snap/snap-name", Type: "none", Options: []string{"rbind", osutil.XSnapdOriginOvername()}}
+	c.Check(profiles, DeepEquals, map[string][]byte{
+		"snap.snap-name_instance.fstab": []byte(strings.Join([]string{snapEntry.String(), dataEntry.String(), fsEntry.String()}, "\n") + "\n"),
+	})
+	// nothing is written
+	c.Check(filepath.Join(dirs.SnapMountPolicyDir, "snap.snap-name_instance.fstab"), testutil.FileAbsent)
+}
@@ -1119,6 +1119,7 @@ func (s *baseDeclSuite) TestPlugInstallation(c *C) {
 		"sd-control":                       true,
 		"shutdown":                         true,
 		"snap-interfaces-requests-control": true,
+		"snap-notices-observe":             true,
 		"snap-refresh-control":             true,
 		"snap-refresh-observe":             true,
 		"snap-themes-control":              true,
@@ -1446,6 +1447,7 @@ func (s *baseDeclSuite) TestValidity(c *C) {
 		"shutdown":                         true,
 		"shared-memory":                    true,
 		"snap-interfaces-requests-control": true,
+		"snap-notices-observe":             true,
 		"snap-refresh-control":             true,
 		"snap-refresh-observe":             true,
 		"snap-themes-control":              true,
@@ -30,6 +30,56 @@ import (
 	"github.com/snapcore/snapd/snap"
 )
 
+// RuleOrigin describes the declaration rule a policy decision is based on.
+type RuleOrigin struct {
+	// Declaration is the type of the assertion carrying the rule,
+	// either "snap-declaration" or "base-declaration". It is empty if
+	// no rule applies, in which case everything is allowed.
+	Declaration string
+	// SnapName is the name of the snap of the snap-declaration.
+	SnapName string
+	// Side is either "plug" or "slot", it is empty if no rule applies
+	// to a connection.
+	Side string
+	// Interface is the interface of the rule.
+	Interface string
+}
+
+func snapDeclarationOrigin(snapDecl *asserts.SnapDeclaration, side, iface string) RuleOrigin {
+	return RuleOrigin{
+		Declaration: asserts.SnapDeclarationType.Name,
+		SnapName:    snapDecl.SnapName(),
+		Side:        side,
+		Interface:   iface,
+	}
+}
+
+func baseDeclarationOrigin(side, iface string) RuleOrigin {
+	return RuleOrigin{
+		Declaration: asserts.BaseDeclarationType.Name,
+		Side:        side,
+		Interface:   iface,
+	}
+}
+
+func (o RuleOrigin) fromSnapDeclaration() bool {
+	return o.Declaration == asserts.SnapDeclarationType.Name
+}
+
+func (o RuleOrigin) String() string {
+	switch o.Declaration {
+	case "":
+		if o.Side != "" {
+			return fmt.Sprintf("no %s rule for interface %q", o.Side, o.Interface)
+		}
+		return fmt.Sprintf("no rule for interface %q", o.Interface)
+	case asserts.SnapDeclarationType.Name:
+		return fmt.Sprintf("%s rule of interface %q in the snap-declaration of %q", o.Side, o.Interface, o.SnapName)
+	default:
+		return fmt.Sprintf("%s rule of interface %q in the %s", o.Side, o.Interface, o.Declaration)
+	}
+}
+
 // InstallCandidate represents a candidate snap for installation.
 type InstallCandidate struct {
 	Snap            *snap.Info
@@ -76,30 +126,68 @@ func (ic *InstallCandidate) checkPlugRule(plug *snap.PlugInfo, rule *asserts.Plu
 	return nil
 }
 
-func (ic *InstallCandidate) checkSlot(slot *snap.SlotInfo) error {
+func (ic *InstallCandidate) slotRule(slot *snap.SlotInfo) (*asserts.SlotRule, RuleOrigin) {
 	iface := slot.Interface
 	if snapDecl := ic.SnapDeclaration; snapDecl != nil {
 		if rule := snapDecl.SlotRule(iface); rule != nil {
-			return ic.checkSlotRule(slot, rule, true)
+			return rule, snapDeclarationOrigin(snapDecl, "slot", iface)
 		}
 	}
 	if rule := ic.BaseDeclaration.SlotRule(iface); rule != nil {
-		return ic.checkSlotRule(slot, rule, false)
+		return rule, baseDeclarationOrigin("slot", iface)
 	}
-	return nil
+	return nil, RuleOrigin{Side: "slot", Interface: iface}
 }
 
-func (ic *InstallCandidate) checkPlug(plug *snap.PlugInfo) error {
+func (ic *InstallCandidate) plugRule(plug *snap.PlugInfo) (*asserts.PlugRule, RuleOrigin) {
 	iface := plug.Interface
 	if snapDecl := ic.SnapDeclaration; snapDecl != nil {
 		if rule := snapDecl.PlugRule(iface); rule != nil {
-			return ic.checkPlugRule(plug, rule, true)
+			return rule, snapDeclarationOrigin(snapDecl, "plug", iface)
 		}
 	}
 	if rule := ic.BaseDeclaration.PlugRule(iface); rule != nil {
-		return ic.checkPlugRule(plug, rule, false)
+		return rule, baseDeclarationOrigin("plug", iface)
 	}
-	return nil
+	return nil, RuleOrigin{Side: "plug", Interface: iface}
+}
+
+func (ic *InstallCandidate) checkSlot(slot *snap.SlotInfo) error {
+	rule, origin := ic.slotRule(slot)
+	if rule == nil {
+		return nil
+	}
+	return ic.checkSlotRule(slot, rule, origin.fromSnapDeclaration())
+}
+
+func (ic *InstallCandidate) checkPlug(plug *snap.PlugInfo) error {
+	rule, origin := ic.plugRule(plug)
+	if rule == nil {
+		return nil
+	}
+	return ic.checkPlugRule(plug, rule, origin.fromSnapDeclaration())
+}
+
+// CheckSlot checks whether the installation is allowed as far as the
+// given slot of the snap is concerned. It also returns the origin of the
+// rule that was used for the decision.
+func (ic *InstallCandidate) CheckSlot(slot *snap.SlotInfo) (RuleOrigin, error) {
+	if ic.BaseDeclaration == nil {
+		return RuleOrigin{}, fmt.Errorf("internal error: improperly initialized InstallCandidate")
+	}
+	_, origin := ic.slotRule(slot)
+	return origin, ic.checkSlot(slot)
+}
+
+// CheckPlug checks whether the installation is allowed as far as the
+// given plug of the snap is concerned. It also returns the origin of the
+// rule that was used for the decision.
+func (ic *InstallCandidate) CheckPlug(plug *snap.PlugInfo) (RuleOrigin, error) {
+	if ic.BaseDeclaration == nil {
+		return RuleOrigin{}, fmt.Errorf("internal error: improperly initialized InstallCandidate")
+	}
+	_, origin := ic.plugRule(plug)
+	return origin, ic.checkPlug(plug)
 }
 
 // Check checks whether the installation is allowed.
@@ -246,23 +334,47 @@ func (connc *ConnectCandidate) check(kind string) (interfaces.SideArity, error)
 		return nil, fmt.Errorf("cannot connect mismatched plug interface %q to slot interface %q", iface, connc.Slot.Interface())
 	}
 
+	plugRule, slotRule, origin := connc.rule()
+	switch {
+	case plugRule != nil:
+		return connc.checkPlugRule(kind, plugRule, origin.fromSnapDeclaration())
+	case slotRule != nil:
+		return connc.checkSlotRule(kind, slotRule, origin.fromSnapDeclaration())
+	}
+	return nil, nil
+}
+
+// rule returns the rule deciding about the connection, either a plug or a
+// slot one, together with its origin.
+func (connc *ConnectCandidate) rule() (*asserts.PlugRule, *asserts.SlotRule, RuleOrigin) {
+	iface := connc.Plug.Interface()
 	if plugDecl := connc.PlugSnapDeclaration; plugDecl != nil {
 		if rule := plugDecl.PlugRule(iface); rule != nil {
-			return connc.checkPlugRule(kind, rule, true)
+			return rule, nil, snapDeclarationOrigin(plugDecl, "plug", iface)
 		}
 	}
 	if slotDecl := connc.SlotSnapDeclaration; slotDecl != nil {
 		if rule := slotDecl.SlotRule(iface); rule != nil {
-			return connc.checkSlotRule(kind, rule, true)
+			return nil, rule, snapDeclarationOrigin(slotDecl, "slot", iface)
 		}
 	}
-	if rule := baseDecl.PlugRule(iface); rule != nil {
-		return connc.checkPlugRule(kind, rule, false)
+	if rule := connc.BaseDeclaration.PlugRule(iface); rule != nil {
+		return rule, nil, baseDeclarationOrigin("plug", iface)
 	}
-	if rule := baseDecl.SlotRule(iface); rule != nil {
-		return connc.checkSlotRule(kind, rule, false)
+	if rule := connc.BaseDeclaration.SlotRule(iface); rule != nil {
+		return nil, rule, baseDeclarationOrigin("slot", iface)
 	}
-	return nil, nil
+	return nil, nil, RuleOrigin{Interface: iface}
+}
+
+// RuleOrigin returns the origin of the rule that Check and CheckAutoConnect
+// use to decide about the connection.
+func (connc *ConnectCandidate) RuleOrigin() RuleOrigin {
+	if connc.BaseDeclaration == nil {
+		return RuleOrigin{Interface: connc.Plug.Interface()}
+	}
+	_, _, origin := connc.rule()
+	return origin
 }
 
 // Check checks whether the connection is allowed.
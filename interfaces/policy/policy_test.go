@@ -1485,6 +1485,33 @@ AXNpZw==`))
 	c.Check(cand.Check(), IsNil)
 }
 
+func (s *policySuite) TestConnectRuleOrigin(c *C) {
+	tests := []struct {
+		iface    string
+		expected string
+	}{
+		{"random", `no rule for interface "random"`},
+		{"base-plug-deny", `plug rule of interface "base-plug-deny" in the base-declaration`},
+		{"base-slot-allow", `slot rule of interface "base-slot-allow" in the base-declaration`},
+		{"snap-plug-allow", `plug rule of interface "snap-plug-allow" in the snap-declaration of "plug-snap"`},
+		{"base-deny-snap-slot-allow", `slot rule of interface "base-deny-snap-slot-allow" in the snap-declaration of "slot-snap"`},
+		// the plug snap-declaration rule takes precedence
+		{"snap-slot-deny-snap-plug-allow", `plug rule of interface "snap-slot-deny-snap-plug-allow" in the snap-declaration of "plug-snap"`},
+	}
+
+	for _, t := range tests {
+		cand := policy.ConnectCandidate{
+			Plug:                interfaces.NewConnectedPlug(s.plugSnap.Plugs[t.iface], s.plugAppSet, nil, nil),
+			Slot:                interfaces.NewConnectedSlot(s.slotSnap.Slots[t.iface], s.slotAppSet, nil, nil),
+			PlugSnapDeclaration: s.plugDecl,
+			SlotSnapDeclaration: s.slotDecl,
+			BaseDeclaration:     s.baseDecl,
+		}
+
+		c.Check(cand.RuleOrigin().String(), Equals, t.expected, Commentf(t.iface))
+	}
+}
+
 func (s *policySuite) TestBaselineDefaultIsAllowInstallation(c *C) {
 	installSnap := snaptest.MockInfo(c, `
 name: install-slot-snap
@@ -1706,6 +1733,68 @@ AXNpZw==`, "@plugsSlots@", strings.TrimSpace(t.plugsSlots), 1)))
 	}
 }
 
+func (s *policySuite) TestCheckPlugSlotInstallation(c *C) {
+	installSnap := snaptest.MockInfo(c, `
+name: install-snap
+version: 0
+slots:
+  random1:
+  install-slot-coreonly:
+plugs:
+  install-plug-base-deny-snap-allow:
+    attr: attrvalue
+`, nil)
+
+	a, err := asserts.Decode([]byte(`type: snap-declaration
+authority-id: canonical
+series: 16
+snap-name: install-snap
+snap-id: installsnap6idididididididididid
+publisher-id: publisher
+plugs:
+  install-plug-base-deny-snap-allow:
+    allow-installation:
+      plug-attributes:
+        attr: attrvalue
+timestamp: 2016-09-30T12:00:00Z
+sign-key-sha3-384: Jv8_JiHiIzJVcO9M55pPdqSDWUvuhfDIBJUS-3VW7F_idjix7Ffn5qMxB21ZQuij
+
+AXNpZw==`))
+	c.Assert(err, IsNil)
+
+	cand := policy.InstallCandidate{
+		Snap:            installSnap,
+		SnapDeclaration: a.(*asserts.SnapDeclaration),
+		BaseDeclaration: s.baseDecl,
+	}
+
+	origin, err := cand.CheckSlot(installSnap.Slots["random1"])
+	c.Check(err, IsNil)
+	c.Check(origin, DeepEquals, policy.RuleOrigin{Side: "slot", Interface: "random1"})
+	c.Check(origin.String(), Equals, `no slot rule for interface "random1"`)
+
+	origin, err = cand.CheckSlot(installSnap.Slots["install-slot-coreonly"])
+	c.Check(err, ErrorMatches, `installation not allowed by "install-slot-coreonly" slot rule of interface "install-slot-coreonly"`)
+	c.Check(origin, DeepEquals, policy.RuleOrigin{
+		Declaration: "base-declaration",
+		Side:        "slot",
+		Interface:   "install-slot-coreonly",
+	})
+
+	origin, err = cand.CheckPlug(installSnap.Plugs["install-plug-base-deny-snap-allow"])
+	c.Check(err, IsNil)
+	c.Check(origin, DeepEquals, policy.RuleOrigin{
+		Declaration: "snap-declaration",
+		SnapName:    "install-snap",
+		Side:        "plug",
+		Interface:   "install-plug-base-deny-snap-allow",
+	})
+	c.Check(origin.String(), Equals, `plug rule of interface "install-plug-base-deny-snap-allow" in the snap-declaration of "install-snap"`)
+
+	// the overall check fails because of the slot
+	c.Check(cand.Check(), ErrorMatches, `installation not allowed by "install-slot-coreonly" slot rule.*`)
+}
+
 func (s *policySuite) TestBaseDeclAllowDenyInstallationMinimalCheck(c *C) {
 	tests := []struct {
 		installYaml string
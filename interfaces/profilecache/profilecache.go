@@ -0,0 +1,228 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package profilecache implements a cache of compiled security profiles
+// shared by all snaps and revisions.
+//
+// Compiled profiles are keyed by the hash of their source and of everything
+// else the output of the compiler depends on, so that a profile is compiled
+// only once even when the same source is generated again, e.g. for the same
+// application across revisions or when regenerating all profiles after a
+// refresh of snapd. The package also offers helpers to compile many profiles
+// in parallel, with a number of workers bounded by the CPUs and the memory of
+// the system.
+package profilecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/timings"
+)
+
+var (
+	runtimeNumCPU        = runtime.NumCPU
+	osutilTotalUsableMem = osutil.TotalUsableMemory
+	timeNow              = time.Now
+)
+
+// MaxEntries is the number of compiled profiles kept in a cache.
+const MaxEntries = 1024
+
+// Cache holds compiled profiles in a directory, in files named after their
+// key.
+type Cache struct {
+	dir string
+}
+
+// New returns a cache of compiled profiles stored in the given directory.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Key returns the key of the compiled profile of the given source. The salt
+// must capture everything else the compiled profile depends on, such as the
+// version of the compiler or the features of the kernel.
+func Key(salt string, source []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%s\x00", len(salt), salt)
+	h.Write(source)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// Fetch copies the compiled profile with the given key to dst. It returns
+// false if there is no such profile in the cache.
+func (c *Cache) Fetch(key, dst string) (bool, error) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := osutil.AtomicWriteFile(dst, data, 0644, 0); err != nil {
+		return false, err
+	}
+	// keep track of the use of the entry for pruning
+	now := timeNow()
+	if err := os.Chtimes(path, now, now); err != nil {
+		logger.Debugf("cannot update time of cached profile %s: %v", key, err)
+	}
+	return true, nil
+}
+
+// Store adds the compiled profile in src to the cache with the given key.
+func (c *Cache) Store(key, src string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	return osutil.AtomicWriteFile(c.path(key), data, 0644, 0)
+}
+
+// Prune removes the least recently used compiled profiles until at most
+// maxEntries are left.
+func (c *Cache) Prune(maxEntries int) error {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) <= maxEntries {
+		return nil
+	}
+	type entry struct {
+		name  string
+		mtime int64
+	}
+	files := make([]entry, 0, len(entries))
+	for _, e := range entries {
+		fi, err := e.Info()
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, entry{name: e.Name(), mtime: fi.ModTime().UnixNano()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].mtime != files[j].mtime {
+			return files[i].mtime < files[j].mtime
+		}
+		return files[i].name < files[j].name
+	})
+	for len(files) > maxEntries {
+		if err := os.Remove(c.path(files[0].name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// Workers returns the number of workers to use for compiling the given
+// number of profiles in parallel, when a single compilation is expected to
+// use up to memPerJob bytes of memory. One CPU is spared for the rest of the
+// system and the compilations can use up to a quarter of the memory.
+func Workers(jobs int, memPerJob uint64) int {
+	workers := runtimeNumCPU()
+	if workers >= 2 {
+		workers -= 1
+	}
+	if memPerJob > 0 {
+		if mem, err := osutilTotalUsableMem(); err == nil {
+			byMem := int(mem / 4 / memPerJob)
+			if byMem < 1 {
+				byMem = 1
+			}
+			if byMem < workers {
+				workers = byMem
+			}
+		}
+	}
+	if workers > jobs {
+		workers = jobs
+	}
+	return workers
+}
+
+// Parallel calls compile for every profile, from at most the given number
+// of workers, and returns the errors of the calls, indexed like profiles.
+// A timing span with the given label is recorded in tm for every profile,
+// which may be nil.
+func Parallel(tm timings.Measurer, label string, workers int, profiles []string, compile func(profile string) error) []error {
+	errs := make([]error, len(profiles))
+	if len(profiles) == 0 {
+		return errs
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	// spans are not safe for concurrent use
+	var spanLock sync.Mutex
+	startSpan := func(profile string) *timings.Span {
+		if tm == nil {
+			return nil
+		}
+		spanLock.Lock()
+		defer spanLock.Unlock()
+		return tm.StartSpan(label, fmt.Sprintf("compile profile %s", filepath.Base(profile)))
+	}
+
+	queue := make(chan int, len(profiles))
+	for i := range profiles {
+		queue <- i
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				span := startSpan(profiles[i])
+				errs[i] = compile(profiles[i])
+				if span != nil {
+					span.Stop()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,188 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package profilecache_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces/profilecache"
+	"github.com/snapcore/snapd/testutil"
+	"github.com/snapcore/snapd/timings"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type cacheSuite struct {
+	testutil.BaseTest
+	dir string
+}
+
+var _ = Suite(&cacheSuite{})
+
+func (s *cacheSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	s.dir = c.MkDir()
+}
+
+func (s *cacheSuite) TestKey(c *C) {
+	k1 := profilecache.Key("v1", []byte("source"))
+	c.Check(k1, HasLen, 64)
+	c.Check(profilecache.Key("v1", []byte("source")), Equals, k1)
+	c.Check(profilecache.Key("v2", []byte("source")), Not(Equals), k1)
+	c.Check(profilecache.Key("v1", []byte("other")), Not(Equals), k1)
+	// the salt cannot be confused with the source
+	c.Check(profilecache.Key("v1s", []byte("ource")), Not(Equals), k1)
+}
+
+func (s *cacheSuite) TestStoreFetch(c *C) {
+	cache := profilecache.New(filepath.Join(s.dir, "cache"))
+	compiled := filepath.Join(s.dir, "compiled")
+	c.Assert(os.WriteFile(compiled, []byte("binary"), 0644), IsNil)
+
+	dst := filepath.Join(s.dir, "dst")
+	ok, err := cache.Fetch("key", dst)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	c.Check(dst, testutil.FileAbsent)
+
+	c.Assert(cache.Store("key", compiled), IsNil)
+	ok, err = cache.Fetch("key", dst)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(dst, testutil.FileEquals, "binary")
+
+	c.Check(cache.Store("key", filepath.Join(s.dir, "missing")), ErrorMatches, "open .*/missing: no such file or directory")
+}
+
+func (s *cacheSuite) TestPrune(c *C) {
+	cache := profilecache.New(filepath.Join(s.dir, "cache"))
+	// nothing to prune yet
+	c.Assert(cache.Prune(2), IsNil)
+
+	compiled := filepath.Join(s.dir, "compiled")
+	c.Assert(os.WriteFile(compiled, []byte("binary"), 0644), IsNil)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, key := range []string{"k1", "k2", "k3"} {
+		c.Assert(cache.Store(key, compiled), IsNil)
+		mtime := base.Add(time.Duration(i) * time.Hour)
+		c.Assert(os.Chtimes(filepath.Join(s.dir, "cache", key), mtime, mtime), IsNil)
+	}
+	// using k1 makes it the most recently used entry
+	s.AddCleanup(profilecache.MockTimeNow(func() time.Time { return base.Add(10 * time.Hour) }))
+	ok, err := cache.Fetch("k1", filepath.Join(s.dir, "dst"))
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	c.Assert(cache.Prune(2), IsNil)
+	c.Check(filepath.Join(s.dir, "cache", "k1"), testutil.FilePresent)
+	c.Check(filepath.Join(s.dir, "cache", "k2"), testutil.FileAbsent)
+	c.Check(filepath.Join(s.dir, "cache", "k3"), testutil.FilePresent)
+}
+
+func (s *cacheSuite) TestWorkers(c *C) {
+	const MiB = 1024 * 1024
+	for _, t := range []struct {
+		cpus      int
+		mem       uint64
+		memErr    error
+		jobs      int
+		memPerJob uint64
+		workers   int
+	}{
+		{cpus: 1, mem: 1024 * MiB, jobs: 10, memPerJob: 32 * MiB, workers: 1},
+		{cpus: 2, mem: 1024 * MiB, jobs: 10, memPerJob: 32 * MiB, workers: 1},
+		{cpus: 8, mem: 8192 * MiB, jobs: 10, memPerJob: 32 * MiB, workers: 7},
+		{cpus: 8, mem: 8192 * MiB, jobs: 3, memPerJob: 32 * MiB, workers: 3},
+		// memory bound
+		{cpus: 8, mem: 1024 * MiB, jobs: 10, memPerJob: 128 * MiB, workers: 2},
+		{cpus: 8, mem: 256 * MiB, jobs: 10, memPerJob: 128 * MiB, workers: 1},
+		// unknown memory
+		{cpus: 8, memErr: errors.New("boom"), jobs: 10, memPerJob: 128 * MiB, workers: 7},
+		{cpus: 8, mem: 256 * MiB, jobs: 10, memPerJob: 0, workers: 7},
+	} {
+		restore := profilecache.MockRuntimeNumCPU(func() int { return t.cpus })
+		defer restore()
+		restore = profilecache.MockTotalUsableMemory(func() (uint64, error) { return t.mem, t.memErr })
+		defer restore()
+		c.Check(profilecache.Workers(t.jobs, t.memPerJob), Equals, t.workers, Commentf("%+v", t))
+	}
+}
+
+type recordingMeasurer struct {
+	span   *timings.Span
+	labels []string
+}
+
+func (m *recordingMeasurer) StartSpan(label, summary string) *timings.Span {
+	m.labels = append(m.labels, label+": "+summary)
+	return m.span.StartSpan(label, summary)
+}
+
+func (s *cacheSuite) TestParallel(c *C) {
+	profiles := make([]string, 20)
+	for i := range profiles {
+		profiles[i] = fmt.Sprintf("/dir/profile-%02d", i)
+	}
+	var running, maxRunning int32
+	tm := &recordingMeasurer{span: timings.New(nil).StartSpan("setup", "")}
+	errs := profilecache.Parallel(tm, "compile-profile", 3, profiles, func(profile string) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if profile == "/dir/profile-07" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	c.Assert(errs, HasLen, len(profiles))
+	for i, err := range errs {
+		if i == 7 {
+			c.Check(err, ErrorMatches, "boom")
+		} else {
+			c.Check(err, IsNil)
+		}
+	}
+	c.Check(maxRunning <= 3, Equals, true)
+
+	c.Assert(tm.labels, HasLen, len(profiles))
+	sort.Strings(tm.labels)
+	c.Check(tm.labels[0], Equals, "compile-profile: compile profile profile-00")
+
+	// no measurer
+	errs = profilecache.Parallel(nil, "compile-profile", 0, profiles[:2], func(string) error { return nil })
+	c.Check(errs, DeepEquals, []error{nil, nil})
+}
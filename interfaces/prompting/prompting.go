@@ -33,6 +33,7 @@ import (
 	"github.com/snapcore/snapd/interfaces/builtin"
 	prompting_errors "github.com/snapcore/snapd/interfaces/prompting/errors"
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/sandbox/apparmor"
 	"github.com/snapcore/snapd/sandbox/apparmor/notify"
 	"github.com/snapcore/snapd/sandbox/apparmor/notify/listener"
 	"github.com/snapcore/snapd/sandbox/cgroup"
@@ -84,7 +85,7 @@ func NewRequestFromListener(msg notify.MsgNotificationGeneric, sendResponse list
 	// XXX: we get the snap name from the process label in the message, but we
 	// could try to get it from the cgroup path instead.
 	snap := msg.ProcessLabel() // default to apparmor label, in case process is not a snap
-	if tag, err := naming.ParseSecurityTag(msg.ProcessLabel()); err == nil {
+	if tag, err := naming.ParseSecurityTag(apparmor.SecurityTagFromLabel(msg.ProcessLabel())); err == nil {
 		// the triggering process is a snap, so use instance name as snap field
 		snap = tag.InstanceName()
 	}
@@ -373,6 +373,26 @@ func (r *Repository) Connection(connRef *ConnRef) (*Connection, error) {
 	return conn, nil
 }
 
+// SetConnectionUsers limits an existing connection to the users with the
+// given IDs. An empty list makes the connection apply to all the users
+// again. The security of the plug side must be set up again afterwards.
+func (r *Repository) SetConnectionUsers(connRef *ConnRef, users []int) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	conn, err := r.Connection(connRef)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		conn.Plug.users = nil
+		return nil
+	}
+	conn.Plug.users = append([]int(nil), users...)
+	sort.Ints(conn.Plug.users)
+	return nil
+}
+
 // AllSlots returns all slots of the given interface.
 // If interfaceName is the empty string, all slots are returned.
 func (r *Repository) AllSlots(interfaceName string) []*snap.SlotInfo {
@@ -976,29 +996,10 @@ func (r *Repository) SnapSpecification(securitySystem SecuritySystem, appSet *Sn
 	// if the error is transient so we also don't want to infinitely loop trying
 	// to add a connected plug that will never work.
 
-	// slot side
-	for _, slotInfo := range r.slots[snapName] {
-		iface := r.ifaces[slotInfo.Interface]
-		if err := spec.AddPermanentSlot(iface, slotInfo); err != nil {
-			return nil, err
-		}
-		for _, conn := range r.slotPlugs[slotInfo] {
-			if err := spec.AddConnectedSlot(iface, conn.Plug, conn.Slot); err != nil {
-				return nil, err
-			}
-		}
-	}
-	// plug side
-	for _, plugInfo := range r.plugs[snapName] {
-		iface := r.ifaces[plugInfo.Interface]
-		if err := spec.AddPermanentPlug(iface, plugInfo); err != nil {
+	for _, contrib := range r.snapSpecificationContributions(snapName) {
+		if err := contrib.Add(spec); err != nil {
 			return nil, err
 		}
-		for _, conn := range r.plugSlots[plugInfo] {
-			if err := spec.AddConnectedPlug(iface, conn.Plug, conn.Slot); err != nil {
-				return nil, err
-			}
-		}
 	}
 	return spec, nil
 }
@@ -1480,6 +1480,68 @@ func (s *RepositorySuite) TestSnapSpecification(c *C) {
 	})
 }
 
+func (s *RepositorySuite) TestSnapSpecificationContributions(c *C) {
+	repo := s.emptyRepo
+	c.Assert(repo.AddInterface(testInterface), IsNil)
+	c.Assert(repo.AddAppSet(s.consumer), IsNil)
+	c.Assert(repo.AddAppSet(s.producer), IsNil)
+	connRef := NewConnRef(s.consumerPlug, s.producerSlot)
+	_, err := repo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+
+	var origins []string
+	spec := &ifacetest.Specification{}
+	for _, contrib := range repo.SnapSpecificationContributions(s.producer) {
+		origins = append(origins, contrib.Origin.String())
+		c.Assert(contrib.Add(spec), IsNil)
+	}
+	c.Check(origins, DeepEquals, []string{
+		"interface slot producer:slot",
+		"interface connected-slot consumer:plug producer:slot",
+		"interface plug producer:self",
+	})
+	c.Check(spec.Snippets, DeepEquals, []string{
+		"static slot snippet",
+		"connection-specific slot snippet",
+		"static plug snippet",
+	})
+}
+
+func (s *RepositorySuite) TestConnectionContributions(c *C) {
+	repo := s.emptyRepo
+	c.Assert(repo.AddInterface(testInterface), IsNil)
+	c.Assert(repo.AddAppSet(s.consumer), IsNil)
+	c.Assert(repo.AddAppSet(s.producer), IsNil)
+
+	// the connection is not made
+	connRef := NewConnRef(s.consumerPlug, s.producerSlot)
+	contribs, err := repo.ConnectionContributions(connRef, "consumer")
+	c.Assert(err, IsNil)
+	c.Assert(contribs, HasLen, 1)
+	c.Check(contribs[0].Origin, DeepEquals, SpecificationOrigin{
+		Kind:      OriginConnectedPlug,
+		Interface: "interface",
+		Plug:      &PlugRef{Snap: "consumer", Name: "plug"},
+		Slot:      &SlotRef{Snap: "producer", Name: "slot"},
+	})
+	spec := &ifacetest.Specification{}
+	c.Assert(contribs[0].Add(spec), IsNil)
+	c.Check(spec.Snippets, DeepEquals, []string{"connection-specific plug snippet"})
+	c.Check(repo.Interfaces().Connections, HasLen, 0)
+
+	// both sides of a connection of a snap to itself
+	contribs, err = repo.ConnectionContributions(NewConnRef(s.producerSelfPlug, s.producerSlot), "producer")
+	c.Assert(err, IsNil)
+	c.Assert(contribs, HasLen, 2)
+	c.Check(contribs[0].Origin.Kind, Equals, OriginConnectedSlot)
+	c.Check(contribs[1].Origin.Kind, Equals, OriginConnectedPlug)
+
+	_, err = repo.ConnectionContributions(connRef, "other")
+	c.Check(err, ErrorMatches, `snap "other" is not part of connection "consumer:plug producer:slot"`)
+	_, err = repo.ConnectionContributions(&ConnRef{PlugRef: PlugRef{Snap: "consumer", Name: "plug"}, SlotRef: SlotRef{Snap: "producer", Name: "missing"}}, "consumer")
+	c.Check(err, ErrorMatches, `snap "producer" has no slot named "missing"`)
+}
+
 func (s *RepositorySuite) TestSnapSpecificationFailureWithConnectionSnippets(c *C) {
 	var testSecurity SecuritySystem = "security"
 	backend := &ifacetest.TestSecurityBackend{BackendName: testSecurity}
@@ -2322,6 +2384,27 @@ func (s *RepositorySuite) TestConnection(c *C) {
 	c.Check(e, NotNil)
 }
 
+func (s *RepositorySuite) TestSetConnectionUsers(c *C) {
+	c.Assert(s.testRepo.AddAppSet(s.consumer), IsNil)
+	c.Assert(s.testRepo.AddAppSet(s.producer), IsNil)
+
+	connRef := NewConnRef(s.consumerPlug, s.producerSlot)
+	err := s.testRepo.SetConnectionUsers(connRef, []int{1000})
+	c.Assert(err, ErrorMatches, `no connection from consumer:plug to producer:slot`)
+
+	conn, err := s.testRepo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+	c.Check(conn.Plug.Users(), IsNil)
+
+	c.Assert(s.testRepo.SetConnectionUsers(connRef, []int{1001, 1000}), IsNil)
+	conn, err = s.testRepo.Connection(connRef)
+	c.Assert(err, IsNil)
+	c.Check(conn.Plug.Users(), DeepEquals, []int{1000, 1001})
+
+	c.Assert(s.testRepo.SetConnectionUsers(connRef, nil), IsNil)
+	c.Check(conn.Plug.Users(), IsNil)
+}
+
 func (s *RepositorySuite) TestConnectWithStaticAttrs(c *C) {
 	c.Assert(s.testRepo.AddAppSet(s.consumer), IsNil)
 	c.Assert(s.testRepo.AddAppSet(s.producer), IsNil)
@@ -25,8 +25,10 @@
 // profile is read and "compiled" to an eBPF program and injected into the
 // kernel for the duration of the execution of the process.
 //
-// There is no binary cache for seccomp, each time the launcher starts an
-// application the profile is parsed and re-compiled.
+// Profiles are compiled by snap-seccomp when they change. Compiled profiles
+// are also kept in a cache keyed by the hash of their source, so that
+// identical profiles, e.g. of the same application across revisions, are
+// only compiled once.
 //
 // The actual profiles are stored in /var/lib/snappy/seccomp/bpf/*.{src,bin}.
 // This directory is hard-coded in snap-confine.
@@ -37,12 +39,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/snapcore/snapd/arch"
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/profilecache"
+	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/sandbox/apparmor"
@@ -118,72 +121,80 @@ func bpfBinPath(srcName string) string {
 	return filepath.Join(dirs.SnapSeccompDir, strings.TrimSuffix(srcName, ".src")+".bin2")
 }
 
-func parallelCompile(compiler Compiler, profiles []string) error {
-	if len(profiles) == 0 {
-		// no profiles, nothing to do
+// seccompCompileMemory is the amount of memory snap-seccomp is expected to
+// use at most for compiling a single profile.
+const seccompCompileMemory = 32 * 1024 * 1024
+
+// profileCache returns the cache of compiled profiles, or nil if profiles
+// cannot be cached as the version of snap-seccomp is not known.
+func (b *Backend) profileCache() *profilecache.Cache {
+	if b.versionInfo == "" {
 		return nil
 	}
+	return profilecache.New(filepath.Join(dirs.SnapProfileCacheDir, "seccomp"))
+}
 
-	profilesQueue := make(chan string, len(profiles))
-	numWorkers := runtime.NumCPU()
-	if numWorkers >= 2 {
-		numWorkers -= 1
-	}
-	if numWorkers > len(profiles) {
-		numWorkers = len(profiles)
-	}
-	resultsBufferSize := numWorkers * 2
-	if resultsBufferSize > len(profiles) {
-		resultsBufferSize = len(profiles)
-	}
-	res := make(chan error, resultsBufferSize)
-
-	// launch as many workers as we have CPUs
-	for i := 0; i < numWorkers; i++ {
-		go func() {
-			for {
-				profile, ok := <-profilesQueue
-				if !ok {
-					break
-				}
-				in := bpfSrcPath(profile)
-				out := bpfBinPath(profile)
-				// remove the old profile first so that we are
-				// not loading it accidentally should the
-				// compilation fail
-				if err := os.Remove(out); err != nil && !os.IsNotExist(err) {
-					res <- err
-					continue
-				}
-
-				// snap-seccomp uses AtomicWriteFile internally, on failure the
-				// output file is unlinked
-				if err := compiler.Compile(in, out); err != nil {
-					res <- fmt.Errorf("cannot compile %s: %v", in, err)
-				} else {
-					res <- nil
-				}
-			}
-		}()
+// compileProfile compiles a single profile, reusing a compiled profile of
+// the same source from the cache if there is one.
+func compileProfile(compiler Compiler, cache *profilecache.Cache, salt, profile string) error {
+	in := bpfSrcPath(profile)
+	out := bpfBinPath(profile)
+	// remove the old profile first so that we are not loading it
+	// accidentally should the compilation fail
+	if err := os.Remove(out); err != nil && !os.IsNotExist(err) {
+		return err
 	}
 
-	for _, p := range profiles {
-		profilesQueue <- p
+	var key string
+	if cache != nil {
+		source, err := os.ReadFile(in)
+		if err != nil {
+			return err
+		}
+		key = profilecache.Key(salt, source)
+		ok, err := cache.Fetch(key, out)
+		if err != nil {
+			logger.Noticef("cannot use cached seccomp profile for %s: %v", in, err)
+		}
+		if ok {
+			return nil
+		}
 	}
-	// signal workers to exit
-	close(profilesQueue)
 
-	var firstErr error
-	for i := 0; i < len(profiles); i++ {
-		maybeErr := <-res
-		if maybeErr != nil && firstErr == nil {
-			firstErr = maybeErr
+	// snap-seccomp uses AtomicWriteFile internally, on failure the output
+	// file is unlinked
+	if err := compiler.Compile(in, out); err != nil {
+		return fmt.Errorf("cannot compile %s: %v", in, err)
+	}
+	if cache != nil {
+		if err := cache.Store(key, out); err != nil {
+			logger.Noticef("cannot cache compiled seccomp profile %s: %v", in, err)
 		}
+	}
+	return nil
+}
 
+// parallelCompile compiles the given profiles in parallel, using the cache
+// of compiled profiles if not nil. A timing span is recorded in tm, which
+// may be nil, for every profile.
+func parallelCompile(compiler Compiler, cache *profilecache.Cache, salt string, profiles []string, tm timings.Measurer) error {
+	if len(profiles) == 0 {
+		// no profiles, nothing to do
+		return nil
 	}
 
-	// not expecting any more results
-	close(res)
+	workers := profilecache.Workers(len(profiles), seccompCompileMemory)
+	errs := profilecache.Parallel(tm, "compile-seccomp-profile", workers, profiles, func(profile string) error {
+		return compileProfile(compiler, cache, salt, profile)
+	})
+
+	var firstErr error
+	for _, err := range errs {
+		if err != nil {
+			firstErr = err
+			break
+		}
+	}
 
 	if firstErr != nil {
 		for _, p := range profiles {
@@ -192,7 +203,11 @@ func parallelCompile(compiler Compiler, profiles []string) error {
 			// compiled
 			os.Remove(out)
 		}
-
+	}
+	if cache != nil {
+		if err := cache.Prune(profilecache.MaxEntries); err != nil {
+			logger.Noticef("cannot prune cache of compiled seccomp profiles: %v", err)
+		}
 	}
 	return firstErr
 }
@@ -247,7 +262,7 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 		}
 	}
 
-	return parallelCompile(b.snapSeccomp, changed)
+	return parallelCompile(b.snapSeccomp, b.profileCache(), string(b.versionInfo), changed, tm)
 }
 
 // Remove removes seccomp profiles of a given snap.
@@ -341,6 +356,26 @@ func generateContent(opts interfaces.ConfinementOptions, snippetForTag string, a
 	return buffer.Bytes()
 }
 
+// SnapContributions returns nil, seccomp profiles are only derived from
+// interfaces and the base template.
+func (b *Backend) SnapContributions(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) []*interfaces.SpecificationContribution {
+	return nil
+}
+
+// RenderProfiles returns the seccomp profile sources of the snap derived from
+// the given specification, without writing or compiling them.
+func (b *Backend) RenderProfiles(spec interfaces.Specification, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) (map[string][]byte, error) {
+	content, err := b.deriveContent(spec.(*Specification), opts, appSet)
+	if err != nil {
+		return nil, err
+	}
+	rendered := make(map[string][]byte, len(content))
+	for name, state := range content {
+		rendered[name] = state.(*osutil.MemoryFileState).Content
+	}
+	return rendered, nil
+}
+
 // NewSpecification returns an empty seccomp specification.
 func (b *Backend) NewSpecification(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) interfaces.Specification {
 	return &Specification{appSet: appSet}
@@ -34,6 +34,7 @@ import (
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/interfaces/profilecache"
 	"github.com/snapcore/snapd/interfaces/seccomp"
 	"github.com/snapcore/snapd/osutil"
 	apparmor_sandbox "github.com/snapcore/snapd/sandbox/apparmor"
@@ -1024,7 +1025,7 @@ func (s *backendSuite) TestParallelCompileHappy(c *C) {
 	for i := range profiles {
 		profiles[i] = fmt.Sprintf("profile-%03d", i)
 	}
-	err := seccomp.ParallelCompile(&m, profiles)
+	err := seccomp.ParallelCompile(&m, nil, "", profiles, nil)
 	c.Assert(err, IsNil)
 
 	sort.Strings(m.profiles)
@@ -1059,7 +1060,7 @@ func (s *backendSuite) TestParallelCompileError(c *C) {
 		// pretend compilation of those 2 fails
 		whichFail: []string{"profile-005.bin2", "profile-009.bin2"},
 	}
-	err = seccomp.ParallelCompile(&m, profiles)
+	err = seccomp.ParallelCompile(&m, nil, "", profiles, nil)
 	c.Assert(err, ErrorMatches, "cannot compile .*/bpf/profile-00[59]: failed profile-00[59].bin2")
 
 	// make sure all compiled profiles were removed
@@ -1087,6 +1088,91 @@ func (s *backendSuite) TestParallelCompileRemovesFirst(c *C) {
 	defer os.Chmod(dirs.SnapSeccompDir, 0755)
 
 	m := mockedSyncedCompiler{}
-	err = seccomp.ParallelCompile(&m, []string{"profile-001"})
+	err = seccomp.ParallelCompile(&m, nil, "", []string{"profile-001"}, nil)
 	c.Assert(err, ErrorMatches, "remove .*/profile-001.bin2: permission denied")
 }
+
+type recordingMeasurer struct {
+	span   *timings.Span
+	labels []string
+}
+
+func (m *recordingMeasurer) StartSpan(label, summary string) *timings.Span {
+	m.labels = append(m.labels, label)
+	return m.span.StartSpan(label, summary)
+}
+
+func (s *backendSuite) TestParallelCompileUsesCache(c *C) {
+	cache := profilecache.New(filepath.Join(c.MkDir(), "cache"))
+	for _, p := range []string{"profile-a", "profile-b", "profile-c"} {
+		source := "same source"
+		if p == "profile-c" {
+			source = "other source"
+		}
+		c.Assert(os.WriteFile(filepath.Join(dirs.SnapSeccompDir, p+".src"), []byte(source), 0644), IsNil)
+	}
+
+	m := mockedSyncedCompiler{}
+	meas := &recordingMeasurer{span: timings.New(nil).StartSpan("", "")}
+	err := seccomp.ParallelCompile(&m, cache, "v1", []string{"profile-a.src"}, meas)
+	c.Assert(err, IsNil)
+	c.Check(m.profiles, DeepEquals, []string{"profile-a.src"})
+	c.Check(meas.labels, DeepEquals, []string{"compile-seccomp-profile"})
+
+	// profile-b has the same source, its compiled profile is reused
+	err = seccomp.ParallelCompile(&m, cache, "v1", []string{"profile-b.src", "profile-c.src"}, meas)
+	c.Assert(err, IsNil)
+	c.Check(m.profiles, DeepEquals, []string{"profile-a.src", "profile-c.src"})
+	c.Check(filepath.Join(dirs.SnapSeccompDir, "profile-b.bin2"), testutil.FileEquals, "done profile-a.bin2")
+	c.Check(filepath.Join(dirs.SnapSeccompDir, "profile-c.bin2"), testutil.FileEquals, "done profile-c.bin2")
+
+	// a different compiler version does not reuse the compiled profiles
+	err = seccomp.ParallelCompile(&m, cache, "v2", []string{"profile-b.src"}, meas)
+	c.Assert(err, IsNil)
+	c.Check(m.profiles, DeepEquals, []string{"profile-a.src", "profile-c.src", "profile-b.src"})
+}
+
+func (s *backendSuite) TestSetupReusesCompiledProfiles(c *C) {
+	snapSeccomp := testutil.MockLockedCommand(c, filepath.Join(dirs.DistroLibExecDir, "snap-seccomp"), `
+if [ "$1" = "version-info" ]; then
+    echo "abcdef 1.2.3 1234abcd -"
+    exit 0
+fi
+echo compiled > "$3"
+`)
+	defer snapSeccomp.Restore()
+	c.Assert(s.Backend.Initialize(nil), IsNil)
+	snapSeccomp.ForgetCalls()
+
+	profile := filepath.Join(dirs.SnapSeccompDir, "snap.samba.smbd")
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 1)
+	c.Check(snapSeccomp.Calls(), DeepEquals, [][]string{
+		{"snap-seccomp", "compile", profile + ".src", profile + ".bin2"},
+	})
+	s.RemoveSnap(c, snapInfo)
+	c.Check(profile+".bin2", testutil.FileAbsent)
+
+	// the profile is identical, the compiled one is taken from the cache
+	snapSeccomp.ForgetCalls()
+	s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 1)
+	c.Check(snapSeccomp.Calls(), HasLen, 0)
+	c.Check(profile+".bin2", testutil.FileEquals, "compiled\n")
+}
+
+func (s *backendSuite) TestRenderProfiles(c *C) {
+	appSet := s.AddSnap(c, "", ifacetest.SambaYamlV1, 0)
+	opts := interfaces.ConfinementOptions{}
+	renderer := s.Backend.(interfaces.SecurityBackendRenderer)
+	c.Check(renderer.SnapContributions(appSet, opts), HasLen, 0)
+
+	spec, err := s.Repo.SnapSpecification(s.Backend.Name(), appSet, opts)
+	c.Assert(err, IsNil)
+	profiles, err := renderer.RenderProfiles(spec, appSet, opts)
+	c.Assert(err, IsNil)
+	c.Check(profiles, HasLen, 1)
+	c.Check(string(profiles["snap.samba.smbd.src"]), testutil.Contains, "# - create_module, init_module, finit_module, delete_module (kernel modules)\n")
+
+	// nothing is written nor compiled
+	c.Check(filepath.Join(dirs.SnapSeccompDir, "snap.samba.smbd.src"), testutil.FileAbsent)
+	c.Check(s.snapSeccomp.Calls(), HasLen, 0)
+}
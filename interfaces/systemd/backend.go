@@ -75,6 +75,11 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 		return fmt.Errorf("cannot obtain systemd services for snap %q: %s", snapName, err)
 	}
 	content := deriveContent(spec.(*Specification), appSet)
+	// services may be ordered after services of other snaps through
+	// their connections, which must not lead to an ordering cycle
+	if err := checkOrderingCycles(repo, appSet.Info()); err != nil {
+		return fmt.Errorf("cannot order services of snap %q: %v", snapName, err)
+	}
 	// synchronize the content with the filesystem
 	dir := dirs.SnapServicesDir
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -96,8 +101,12 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 		logger.Noticef("cannot stop removed services: %s", err)
 	}
 	changed, removed, errEnsure := osutil.EnsureDirState(dir, glob, content)
+	orderingChanged, err := ensureOrderingDropIns(snapName, orderingDropIns(spec.(*Specification), appSet))
+	if err != nil {
+		return fmt.Errorf("cannot update ordering of services of snap %q: %v", snapName, err)
+	}
 	// Reload systemd whenever something is added or removed
-	if !b.preseed && (len(changed) > 0 || len(removed) > 0) {
+	if !b.preseed && (len(changed) > 0 || len(removed) > 0 || orderingChanged) {
 		err := systemd.DaemonReload()
 		if err != nil {
 			logger.Noticef("cannot reload systemd state: %s", err)
@@ -137,6 +146,10 @@ func (b *Backend) Remove(snapName string) error {
 	// Remove all the files matching snap glob
 	glob := serviceName(snapName, "*")
 	_, removed, errEnsure := osutil.EnsureDirState(dirs.SnapServicesDir, glob, nil)
+	orderingChanged, err := ensureOrderingDropIns(snapName, nil)
+	if err != nil {
+		logger.Noticef("cannot remove ordering of services of snap %q: %s", snapName, err)
+	}
 
 	if len(removed) > 0 {
 		logger.Noticef("systemd-backend: Disable: removed services: %q", removed)
@@ -150,7 +163,7 @@ func (b *Backend) Remove(snapName string) error {
 		}
 	}
 	// Reload systemd whenever something is removed
-	if !b.preseed && len(removed) > 0 {
+	if !b.preseed && (len(removed) > 0 || orderingChanged) {
 		err := systemd.DaemonReload()
 		if err != nil {
 			logger.Noticef("cannot reload systemd state: %s", err)
@@ -22,6 +22,7 @@ package systemd_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	. "gopkg.in/check.v1"
 
@@ -223,3 +224,129 @@ func (s *backendSuite) TestRemovingSnapWhenPreseeding(c *C) {
 		})
 	}
 }
+
+const orderingConsumerYaml = `name: app
+version: 1
+plugs:
+  db:
+    interface: iface
+slots:
+  api:
+    interface: iface
+apps:
+  web:
+    command: bin/web
+    daemon: simple
+    after-plugs: [db]
+  cli:
+    command: bin/cli
+`
+
+const orderingProviderYaml = `name: database
+version: 1
+slots:
+  db:
+    interface: iface
+plugs:
+  api:
+    interface: iface
+apps:
+  server:
+    command: bin/server
+    daemon: simple
+  backup:
+    command: bin/backup
+    daemon: oneshot
+  tool:
+    command: bin/tool
+`
+
+func (s *backendSuite) TestConnectionOrdering(c *C) {
+	appSet := s.AddSnap(c, "", orderingConsumerYaml, 1)
+	s.AddSnap(c, "", orderingProviderYaml, 1)
+
+	connRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "app", Name: "db"},
+		SlotRef: interfaces.SlotRef{Snap: "database", Name: "db"},
+	}
+	_, err := s.Repo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+
+	sctx := interfaces.SetupContext{Reason: interfaces.SnapSetupReasonOther}
+	err = s.Backend.Setup(appSet, interfaces.ConfinementOptions{}, sctx, s.Repo, nil)
+	c.Assert(err, IsNil)
+
+	dropInDir := filepath.Join(dirs.SnapServicesDir, "snap.app.web.service.d")
+	dropIn := filepath.Join(dropInDir, "40-snapd-connections.conf")
+	c.Check(dropIn, testutil.FileEquals, `# Auto-generated, DO NOT EDIT
+[Unit]
+Wants=snap.database.backup.service snap.database.server.service
+After=snap.database.backup.service snap.database.server.service
+`)
+	c.Check(filepath.Join(dirs.SnapServicesDir, "snap.app.cli.service.d"), testutil.FileAbsent)
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"systemctl", "daemon-reload"},
+	})
+
+	// setting up again without changes does not reload systemd
+	s.systemctlArgs = nil
+	err = s.Backend.Setup(appSet, interfaces.ConfinementOptions{}, sctx, s.Repo, nil)
+	c.Assert(err, IsNil)
+	c.Check(s.systemctlArgs, HasLen, 0)
+
+	// disconnecting removes the ordering
+	c.Assert(s.Repo.Disconnect("app", "db", "database", "db"), IsNil)
+	err = s.Backend.Setup(appSet, interfaces.ConfinementOptions{}, sctx, s.Repo, nil)
+	c.Assert(err, IsNil)
+	c.Check(dropInDir, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"systemctl", "daemon-reload"},
+	})
+}
+
+func (s *backendSuite) TestConnectionOrderingRemovedWithSnap(c *C) {
+	appSet := s.AddSnap(c, "", orderingConsumerYaml, 1)
+	s.AddSnap(c, "", orderingProviderYaml, 1)
+
+	connRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "app", Name: "db"},
+		SlotRef: interfaces.SlotRef{Snap: "database", Name: "db"},
+	}
+	_, err := s.Repo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+	sctx := interfaces.SetupContext{Reason: interfaces.SnapSetupReasonOther}
+	err = s.Backend.Setup(appSet, interfaces.ConfinementOptions{}, sctx, s.Repo, nil)
+	c.Assert(err, IsNil)
+
+	dropInDir := filepath.Join(dirs.SnapServicesDir, "snap.app.web.service.d")
+	c.Check(filepath.Join(dropInDir, "40-snapd-connections.conf"), testutil.FilePresent)
+
+	s.systemctlArgs = nil
+	err = s.Backend.Remove("app")
+	c.Assert(err, IsNil)
+	c.Check(dropInDir, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"systemctl", "daemon-reload"},
+	})
+}
+
+func (s *backendSuite) TestConnectionOrderingCycle(c *C) {
+	appSet := s.AddSnap(c, "", orderingConsumerYaml, 1)
+	s.AddSnap(c, "", strings.Replace(orderingProviderYaml, "    daemon: simple\n", "    daemon: simple\n    after-plugs: [api]\n", 1), 1)
+
+	for _, connRef := range []*interfaces.ConnRef{{
+		PlugRef: interfaces.PlugRef{Snap: "app", Name: "db"},
+		SlotRef: interfaces.SlotRef{Snap: "database", Name: "db"},
+	}, {
+		PlugRef: interfaces.PlugRef{Snap: "database", Name: "api"},
+		SlotRef: interfaces.SlotRef{Snap: "app", Name: "api"},
+	}} {
+		_, err := s.Repo.Connect(connRef, nil, nil, nil, nil, nil)
+		c.Assert(err, IsNil)
+	}
+
+	sctx := interfaces.SetupContext{Reason: interfaces.SnapSetupReasonOther}
+	err := s.Backend.Setup(appSet, interfaces.ConfinementOptions{}, sctx, s.Repo, nil)
+	c.Assert(err, ErrorMatches, `cannot order services of snap "app": services are part of an ordering cycle: snap.app.web.service -> snap.database.server.service -> snap.app.web.service`)
+	c.Check(filepath.Join(dirs.SnapServicesDir, "snap.app.web.service.d"), testutil.FileAbsent)
+}
@@ -0,0 +1,247 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package systemd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+// orderingDropInName is the name of the drop-in carrying the ordering of a
+// snap service against the services backing its connected slots.
+const orderingDropInName = "40-snapd-connections.conf"
+
+// providerUnits returns the names of the system service units backing the
+// given connected slot.
+func providerUnits(slot *interfaces.ConnectedSlot) []string {
+	var units []string
+	for _, app := range slot.Apps() {
+		if app.IsService() && app.DaemonScope == snap.SystemDaemon {
+			units = append(units, app.ServiceName())
+		}
+	}
+	sort.Strings(units)
+	return units
+}
+
+// afterPlugApps returns the services of the plug side of the connection which
+// asked to be started after the services backing the slot.
+func afterPlugApps(plug *interfaces.ConnectedPlug) []*snap.AppInfo {
+	var apps []*snap.AppInfo
+	for _, app := range plug.Snap().Apps {
+		if app.IsService() && strutil.ListContains(app.AfterPlugs, plug.Name()) {
+			apps = append(apps, app)
+		}
+	}
+	return apps
+}
+
+// orderingDropIns computes the ordering drop-ins of the services of a snap,
+// keyed by their path.
+func orderingDropIns(spec *Specification, appSet *interfaces.SnapAppSet) map[string][]byte {
+	ordering := spec.ServiceOrdering()
+	if len(ordering) == 0 {
+		return nil
+	}
+	dropIns := make(map[string][]byte, len(ordering))
+	for appName, units := range ordering {
+		app := appSet.Info().Apps[appName]
+		if app == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		buf.WriteString("# Auto-generated, DO NOT EDIT\n[Unit]\n")
+		fmt.Fprintf(&buf, "Wants=%s\n", strings.Join(units, " "))
+		fmt.Fprintf(&buf, "After=%s\n", strings.Join(units, " "))
+		dropIns[filepath.Join(app.ServiceFile()+".d", orderingDropInName)] = buf.Bytes()
+	}
+	return dropIns
+}
+
+// ensureOrderingDropIns synchronizes the ordering drop-ins of the services of
+// the given snap with the desired content and reports whether anything
+// changed.
+func ensureOrderingDropIns(snapName string, content map[string][]byte) (changed bool, err error) {
+	existing, err := filepath.Glob(filepath.Join(dirs.SnapServicesDir, fmt.Sprintf("snap.%s.*.service.d", snapName), orderingDropInName))
+	if err != nil {
+		return false, err
+	}
+	for _, path := range existing {
+		if _, ok := content[path]; ok {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return changed, err
+		}
+		// the directory is kept if it is used by other drop-ins
+		os.Remove(filepath.Dir(path))
+		changed = true
+	}
+
+	paths := make([]string, 0, len(content))
+	for path := range content {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		state := &osutil.MemoryFileState{Content: content[path], Mode: 0644}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return changed, err
+		}
+		if err := osutil.EnsureFileState(path, state); err == osutil.ErrSameState {
+			continue
+		} else if err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// orderingGraph is the graph of the "starts after" relation between snap
+// services, built lazily from the snaps and connections in the repository.
+type orderingGraph struct {
+	repo *interfaces.Repository
+	// after maps a service unit to the units it starts after
+	after map[string][]string
+	// snaps maps a service unit to the snap it belongs to
+	snaps  map[string]*snap.Info
+	loaded map[string]bool
+}
+
+func (g *orderingGraph) addEdge(unit, afterUnit string, afterSnap *snap.Info) {
+	g.after[unit] = append(g.after[unit], afterUnit)
+	if _, ok := g.snaps[afterUnit]; !ok {
+		g.snaps[afterUnit] = afterSnap
+	}
+}
+
+func (g *orderingGraph) load(info *snap.Info) error {
+	snapName := info.InstanceName()
+	if g.loaded[snapName] {
+		return nil
+	}
+	g.loaded[snapName] = true
+
+	for _, app := range info.Services() {
+		for _, other := range app.After {
+			if dep := info.Apps[other]; dep != nil {
+				g.addEdge(app.ServiceName(), dep.ServiceName(), info)
+			}
+		}
+		for _, other := range app.Before {
+			if dep := info.Apps[other]; dep != nil {
+				g.addEdge(dep.ServiceName(), app.ServiceName(), info)
+			}
+		}
+	}
+
+	connRefs, err := g.repo.Connections(snapName)
+	if err != nil {
+		return err
+	}
+	for _, connRef := range connRefs {
+		if connRef.PlugRef.Snap != snapName {
+			continue
+		}
+		conn, err := g.repo.Connection(connRef)
+		if err != nil {
+			return err
+		}
+		units := providerUnits(conn.Slot)
+		for _, app := range afterPlugApps(conn.Plug) {
+			for _, unit := range units {
+				g.addEdge(app.ServiceName(), unit, conn.Slot.Snap())
+			}
+		}
+	}
+	return nil
+}
+
+// checkOrderingCycles returns an error if the services of the given snap are
+// part of a cycle of the ordering across snaps.
+func checkOrderingCycles(repo *interfaces.Repository, info *snap.Info) error {
+	g := &orderingGraph{
+		repo:   repo,
+		after:  make(map[string][]string),
+		snaps:  make(map[string]*snap.Info),
+		loaded: make(map[string]bool),
+	}
+	if err := g.load(info); err != nil {
+		return err
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var stack []string
+	var visit func(unit string) error
+	visit = func(unit string) error {
+		switch state[unit] {
+		case visiting:
+			// the stack, from the first occurrence of the unit on, is
+			// the cycle
+			for i, u := range stack {
+				if u == unit {
+					return fmt.Errorf("services are part of an ordering cycle: %s", strings.Join(append(stack[i:], unit), " -> "))
+				}
+			}
+		case visited:
+			return nil
+		}
+		if other := g.snaps[unit]; other != nil {
+			if err := g.load(other); err != nil {
+				return err
+			}
+		}
+		state[unit] = visiting
+		stack = append(stack, unit)
+		for _, next := range g.after[unit] {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[unit] = visited
+		return nil
+	}
+
+	svcs := info.Services()
+	sort.Slice(svcs, func(i, j int) bool { return svcs[i].Name < svcs[j].Name })
+	for _, app := range svcs {
+		if err := visit(app.ServiceName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
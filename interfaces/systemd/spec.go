@@ -21,9 +21,11 @@ package systemd
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
 )
 
 type addedService struct {
@@ -39,6 +41,9 @@ type addedService struct {
 type Specification struct {
 	curIface string
 	services map[string]*addedService
+	// ordering maps the names of the services of the snap to the units
+	// backing their connected slots which they start after
+	ordering map[string][]string
 }
 
 // AddService adds a new systemd service unit.
@@ -77,10 +82,42 @@ func (spec *Specification) Services() map[string]*Service {
 	return result
 }
 
+// ServiceOrdering returns the units which the services of the snap start
+// after, keyed by the name of the service.
+func (spec *Specification) ServiceOrdering() map[string][]string {
+	if spec.ordering == nil {
+		return nil
+	}
+	result := make(map[string][]string, len(spec.ordering))
+	for appName, units := range spec.ordering {
+		units = strutil.Deduplicate(units)
+		sort.Strings(units)
+		result[appName] = units
+	}
+	return result
+}
+
+func (spec *Specification) addServiceOrdering(plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) {
+	units := providerUnits(slot)
+	if len(units) == 0 {
+		return
+	}
+	for _, app := range afterPlugApps(plug) {
+		if spec.ordering == nil {
+			spec.ordering = make(map[string][]string)
+		}
+		spec.ordering[app.Name] = append(spec.ordering[app.Name], units...)
+	}
+}
+
 // Implementation of methods required by interfaces.Specification
 
 // AddConnectedPlug records systemd-specific side-effects of having a connected plug.
 func (spec *Specification) AddConnectedPlug(iface interfaces.Interface, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	// ordering against the services backing the slot applies to
+	// connections of any interface
+	spec.addServiceOrdering(plug, slot)
+
 	type definer interface {
 		interfaces.Interface
 		SystemdConnectedPlug(spec *Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error
@@ -133,22 +133,8 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 			needReload = true
 		}
 	} else {
-		var rulesBuf bytes.Buffer
-		rulesBuf.WriteString("# This file is automatically generated.\n")
-		if (opts.DevMode || opts.Classic) && !opts.JailMode {
-			rulesBuf.WriteString("# udev tagging/device cgroups disabled with non-strict mode snaps\n")
-		}
-		for _, snippet := range content {
-			if (opts.DevMode || opts.Classic) && !opts.JailMode {
-				rulesBuf.WriteRune('#')
-				snippet = strings.Replace(snippet, "\n", "\n#", -1)
-			}
-			rulesBuf.WriteString(snippet)
-			rulesBuf.WriteByte('\n')
-		}
-
 		rulesFileState := &osutil.MemoryFileState{
-			Content: rulesBuf.Bytes(),
+			Content: renderRules(content, opts),
 			Mode:    0644,
 		}
 
@@ -186,6 +172,11 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 		// and block devices based on their major:minor numbers.
 		devCgroupOpts.NonStrict = true
 	}
+	if opts.DeviceAudit {
+		// Have snap-confine count the device accesses denied by the
+		// device cgroup.
+		devCgroupOpts.Audit = true
+	}
 
 	cgroupOptsBytes, err := devCgroupOpts.MarshalText()
 	if err != nil {
@@ -234,6 +225,42 @@ func (b *Backend) Remove(snapName string) error {
 	return nil
 }
 
+func renderRules(content []string, opts interfaces.ConfinementOptions) []byte {
+	var rulesBuf bytes.Buffer
+	rulesBuf.WriteString("# This file is automatically generated.\n")
+	if (opts.DevMode || opts.Classic) && !opts.JailMode {
+		rulesBuf.WriteString("# udev tagging/device cgroups disabled with non-strict mode snaps\n")
+	}
+	for _, snippet := range content {
+		if (opts.DevMode || opts.Classic) && !opts.JailMode {
+			rulesBuf.WriteRune('#')
+			snippet = strings.Replace(snippet, "\n", "\n#", -1)
+		}
+		rulesBuf.WriteString(snippet)
+		rulesBuf.WriteByte('\n')
+	}
+	return rulesBuf.Bytes()
+}
+
+// SnapContributions returns nil, udev rules are only derived from
+// interfaces.
+func (b *Backend) SnapContributions(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) []*interfaces.SpecificationContribution {
+	return nil
+}
+
+// RenderProfiles returns the udev rules of the snap derived from the given
+// specification, without writing them or reloading udev. No rules are
+// returned when there are none or the snap controls its device cgroup.
+func (b *Backend) RenderProfiles(spec interfaces.Specification, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) (map[string][]byte, error) {
+	udevSpec := spec.(*Specification)
+	content := b.deriveContent(udevSpec)
+	if len(content) == 0 || udevSpec.ControlsDeviceCgroup() {
+		return nil, nil
+	}
+	name := filepath.Base(snapRulesFilePath(appSet.InstanceName()))
+	return map[string][]byte{name: renderRules(content, opts)}, nil
+}
+
 func (b *Backend) deriveContent(spec *Specification) (content []string) {
 	content = append(content, spec.Snippets()...)
 	return content
@@ -415,6 +415,22 @@ func (s *backendSuite) TestControlsDeviceCgroupCleansUpRules(c *C) {
 	c.Check(s.udevadmCmd.Calls(), HasLen, 0)
 }
 
+func (s *backendSuite) TestDeviceCgroupAudit(c *C) {
+	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
+		spec.AddSnippet("sample")
+		return nil
+	}
+	cgroupFname := filepath.Join(dirs.SnapCgroupPolicyDir, "snap.samba.device")
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{DeviceAudit: true}, "", ifacetest.SambaYamlV1, 0)
+	c.Check(cgroupFname, testutil.FileEquals, "# This file is automatically generated.\n"+
+		"# denied device accesses are audited.\n"+
+		"audit=true\n")
+
+	// the flag is dropped when auditing is disabled
+	s.UpdateSnap(c, snapInfo, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1, 0)
+	c.Check(cgroupFname, testutil.FileEquals, "# This file is automatically generated.\n")
+}
+
 func (s *backendSuite) TestDeviceCgroupAlwaysPresent(c *C) {
 	// NOTE: Hand out a permanent snippet so that .rules file is generated.
 	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
@@ -659,3 +675,37 @@ func (s *backendSuite) TestPreseed(c *C) {
 
 	c.Check(s.udevadmCmd.Calls(), HasLen, 0)
 }
+
+func (s *backendSuite) TestRenderProfiles(c *C) {
+	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
+		spec.AddSnippet("sample")
+		return nil
+	}
+	renderer := s.Backend.(interfaces.SecurityBackendRenderer)
+
+	for _, opts := range testedConfinementOpts {
+		snapInfo := s.InstallSnap(c, opts, "", ifacetest.SambaYamlV1, 0)
+		appSet, err := interfaces.NewSnapAppSet(snapInfo, nil)
+		c.Assert(err, IsNil)
+		c.Check(renderer.SnapContributions(appSet, opts), HasLen, 0)
+
+		spec, err := s.Repo.SnapSpecification(s.Backend.Name(), appSet, opts)
+		c.Assert(err, IsNil)
+		profiles, err := renderer.RenderProfiles(spec, appSet, opts)
+		c.Assert(err, IsNil)
+		// the rules are the ones Setup writes
+		fname := filepath.Join(dirs.SnapUdevRulesDir, "70-snap.samba.rules")
+		c.Check(fname, testutil.FileEquals, string(profiles["70-snap.samba.rules"]))
+		c.Check(profiles, HasLen, 1)
+		s.RemoveSnap(c, snapInfo)
+	}
+
+	// no rules without snippets
+	s.Iface.UDevPermanentSlotCallback = nil
+	appSet := s.AddSnap(c, "", ifacetest.SambaYamlV1, 0)
+	spec, err := s.Repo.SnapSpecification(s.Backend.Name(), appSet, interfaces.ConfinementOptions{})
+	c.Assert(err, IsNil)
+	profiles, err := renderer.RenderProfiles(spec, appSet, interfaces.ConfinementOptions{})
+	c.Assert(err, IsNil)
+	c.Check(profiles, HasLen, 0)
+}
@@ -0,0 +1,105 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package kernel
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Modules holds the names of the modules provided by a kernel.
+type Modules struct {
+	loadable map[string]bool
+	builtin  map[string]bool
+}
+
+// NormalizeModuleName returns the canonical name of a kernel module, as with
+// modprobe dashes and underscores are interchangeable in module names.
+func NormalizeModuleName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// moduleNameFromPath returns the name of the module in the given path as
+// found in modules.dep or modules.builtin, e.g. "kernel/fs/fat/vfat.ko.zst".
+func moduleNameFromPath(modPath string) string {
+	name := path.Base(modPath)
+	if idx := strings.Index(name, ".ko"); idx > 0 {
+		name = name[:idx]
+	}
+	return NormalizeModuleName(name)
+}
+
+func readModulesList(listPath string, lineToPath func(line string) string) (map[string]bool, error) {
+	f, err := os.Open(listPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	modules := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		modPath := lineToPath(scanner.Text())
+		if modPath == "" {
+			continue
+		}
+		modules[moduleNameFromPath(modPath)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read %q: %v", listPath, err)
+	}
+	return modules, nil
+}
+
+// ReadModules returns the modules listed in the modules.dep and
+// modules.builtin files found in modsDir, which is the modules directory of
+// a kernel, e.g. /lib/modules/$(uname -r) for the running kernel.
+func ReadModules(modsDir string) (*Modules, error) {
+	// lines in modules.dep look like "<module path>: <dependencies>"
+	loadable, err := readModulesList(filepath.Join(modsDir, "modules.dep"), func(line string) string {
+		modPath, _, _ := strings.Cut(line, ":")
+		return strings.TrimSpace(modPath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// modules.builtin has one module path per line, it is not
+	// shipped by kernels that have no built-in modules
+	builtin, err := readModulesList(filepath.Join(modsDir, "modules.builtin"), strings.TrimSpace)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return &Modules{loadable: loadable, builtin: builtin}, nil
+}
+
+// IsLoadable returns whether the named module can be loaded with modprobe.
+func (m *Modules) IsLoadable(name string) bool {
+	return m.loadable[NormalizeModuleName(name)]
+}
+
+// IsBuiltin returns whether the named module is built into the kernel.
+func (m *Modules) IsBuiltin(name string) bool {
+	return m.builtin[NormalizeModuleName(name)]
+}
@@ -0,0 +1,80 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package kernel_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/kernel"
+)
+
+type modulesSuite struct{}
+
+var _ = Suite(&modulesSuite{})
+
+func (s *modulesSuite) TestNormalizeModuleName(c *C) {
+	c.Check(kernel.NormalizeModuleName("snd-hda-intel"), Equals, "snd_hda_intel")
+	c.Check(kernel.NormalizeModuleName("vfat"), Equals, "vfat")
+}
+
+func (s *modulesSuite) TestReadModules(c *C) {
+	modsDir := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(modsDir, "modules.dep"), []byte(`kernel/fs/fat/vfat.ko.zst: kernel/fs/fat/fat.ko.zst
+kernel/fs/fat/fat.ko.zst:
+kernel/sound/pci/hda/snd-hda-intel.ko: kernel/sound/core/snd.ko
+
+kernel/sound/core/snd.ko.xz:
+`), 0644), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modsDir, "modules.builtin"), []byte(`kernel/fs/ext4/ext4.ko
+kernel/drivers/block/virtio_blk.ko
+`), 0644), IsNil)
+
+	mods, err := kernel.ReadModules(modsDir)
+	c.Assert(err, IsNil)
+
+	for _, name := range []string{"vfat", "fat", "snd-hda-intel", "snd_hda_intel", "snd"} {
+		c.Check(mods.IsLoadable(name), Equals, true, Commentf(name))
+		c.Check(mods.IsBuiltin(name), Equals, false, Commentf(name))
+	}
+	for _, name := range []string{"ext4", "virtio-blk", "virtio_blk"} {
+		c.Check(mods.IsLoadable(name), Equals, false, Commentf(name))
+		c.Check(mods.IsBuiltin(name), Equals, true, Commentf(name))
+	}
+	c.Check(mods.IsLoadable("nvidia"), Equals, false)
+	c.Check(mods.IsBuiltin("nvidia"), Equals, false)
+}
+
+func (s *modulesSuite) TestReadModulesNoBuiltin(c *C) {
+	modsDir := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(modsDir, "modules.dep"), []byte("kernel/fs/fat/vfat.ko:\n"), 0644), IsNil)
+
+	mods, err := kernel.ReadModules(modsDir)
+	c.Assert(err, IsNil)
+	c.Check(mods.IsLoadable("vfat"), Equals, true)
+	c.Check(mods.IsBuiltin("ext4"), Equals, false)
+}
+
+func (s *modulesSuite) TestReadModulesNoModulesDep(c *C) {
+	_, err := kernel.ReadModules(c.MkDir())
+	c.Assert(err, ErrorMatches, `open .*/modules.dep: no such file or directory`)
+}
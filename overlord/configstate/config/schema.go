@@ -0,0 +1,252 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/snapcore/snapd/confdb"
+	"github.com/snapcore/snapd/jsonutil"
+	"github.com/snapcore/snapd/snap"
+)
+
+// SchemaFile is the path, relative to the root of a snap, of the file
+// describing the configuration options accepted by the snap.
+const SchemaFile = "meta/config-schema.json"
+
+// Schema describes the configuration options accepted by a snap. It uses the
+// syntax of confdb storage schemas (types, choices, ranges and "secret"
+// visibility) and additionally allows map entries to declare a "default"
+// value.
+type Schema struct {
+	storage  *confdb.StorageSchema
+	defaults map[string]any
+}
+
+// ParseSchema parses a snap configuration schema.
+func ParseSchema(raw []byte) (*Schema, error) {
+	storage, err := confdb.ParseStorageSchema(raw)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse configuration schema: %v", err)
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &top); err != nil {
+		return nil, fmt.Errorf("cannot parse configuration schema: %v", err)
+	}
+
+	schema := &Schema{storage: storage}
+	schema.defaults, err = schema.parseDefaults(nil, top["schema"])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse configuration schema: %v", err)
+	}
+	return schema, nil
+}
+
+// ReadSchema reads the configuration schema shipped by the given snap. It
+// returns a nil schema if the snap does not ship one.
+func ReadSchema(info *snap.Info) (*Schema, error) {
+	raw, err := os.ReadFile(filepath.Join(info.MountDir(), SchemaFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(raw)
+}
+
+func (s *Schema) parseDefaults(path []string, rawEntries json.RawMessage) (map[string]any, error) {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(rawEntries, &entries); err != nil {
+		return nil, err
+	}
+
+	var defaults map[string]any
+	for key, rawDef := range entries {
+		var def map[string]json.RawMessage
+		if err := json.Unmarshal(rawDef, &def); err != nil {
+			// type names and alternatives cannot carry defaults
+			continue
+		}
+
+		entryPath := append(path[:len(path):len(path)], key)
+		var value any
+		if rawDefault, ok := def["default"]; ok {
+			if err := s.validateAt(entryPath, rawDefault); err != nil {
+				return nil, fmt.Errorf("invalid default for %q: %v", strings.Join(entryPath, "."), err)
+			}
+			if err := jsonutil.DecodeWithNumber(bytes.NewReader(rawDefault), &value); err != nil {
+				return nil, err
+			}
+		} else if rawSchema, ok := def["schema"]; ok {
+			nested, err := s.parseDefaults(entryPath, rawSchema)
+			if err != nil {
+				return nil, err
+			}
+			if nested != nil {
+				value = nested
+			}
+		}
+
+		if value == nil {
+			continue
+		}
+		if defaults == nil {
+			defaults = make(map[string]any)
+		}
+		defaults[key] = value
+	}
+	return defaults, nil
+}
+
+func pathAccessors(path []string) ([]confdb.Accessor, error) {
+	return confdb.ParsePathIntoAccessors(strings.Join(path, "."), confdb.ParseOptions{})
+}
+
+// validateAt checks that the raw value is accepted by one of the types that
+// the schema allows at the given path.
+func (s *Schema) validateAt(path []string, raw []byte) error {
+	accessors, err := pathAccessors(path)
+	if err != nil {
+		return err
+	}
+	schemas, err := s.storage.SchemaAt(accessors)
+	if err != nil {
+		return err
+	}
+	for _, schema := range schemas {
+		if err = schema.Validate(raw); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Validate checks that the given configuration of the snap, with defaults
+// applied, is accepted by the schema.
+func (s *Schema) Validate(value any) error {
+	value = s.WithDefaults("", value)
+	if value == nil {
+		value = map[string]any{}
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.storage.Validate(raw)
+}
+
+// WithDefaults returns the value of the given configuration key with any
+// defaults declared by the schema filled in for unset options. The empty key
+// refers to the whole configuration of the snap. Maps in the given value may
+// be modified.
+func (s *Schema) WithDefaults(key string, value any) any {
+	var def any = s.defaults
+	if key != "" {
+		for _, subkey := range strings.Split(key, ".") {
+			m, ok := def.(map[string]any)
+			if !ok {
+				return value
+			}
+			def = m[subkey]
+		}
+	}
+	return mergeDefaults(value, def)
+}
+
+func mergeDefaults(value, def any) any {
+	if def == nil {
+		return value
+	}
+	defMap, ok := def.(map[string]any)
+	if value == nil {
+		if !ok {
+			return def
+		}
+		value = make(map[string]any, len(defMap))
+	}
+	valueMap, valueIsMap := value.(map[string]any)
+	if !ok || !valueIsMap {
+		return value
+	}
+	for k, v := range defMap {
+		if merged := mergeDefaults(valueMap[k], v); merged != nil {
+			valueMap[k] = merged
+		}
+	}
+	return valueMap
+}
+
+// Redact returns the value of the given configuration key without the
+// options that the schema declares as secret. If the key itself refers to
+// a secret option, it reports it and returns no value.
+func (s *Schema) Redact(key string, value any) (redacted any, secret bool) {
+	var path []string
+	if key != "" {
+		path = strings.Split(key, ".")
+	}
+	for i := range path {
+		if s.isSecret(path[:i+1]) {
+			return nil, true
+		}
+	}
+	return s.redact(path, value), false
+}
+
+func (s *Schema) redact(path []string, value any) any {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	redacted := make(map[string]any, len(m))
+	for k, v := range m {
+		subpath := append(path[:len(path):len(path)], k)
+		if s.isSecret(subpath) {
+			continue
+		}
+		redacted[k] = s.redact(subpath, v)
+	}
+	return redacted
+}
+
+func (s *Schema) isSecret(path []string) bool {
+	accessors, err := pathAccessors(path)
+	if err != nil {
+		return false
+	}
+	schemas, err := s.storage.SchemaAt(accessors)
+	if err != nil {
+		return false
+	}
+	for _, schema := range schemas {
+		if schema.Visibility() == confdb.SecretVisibility {
+			return true
+		}
+	}
+	return false
+}
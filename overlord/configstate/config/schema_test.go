@@ -0,0 +1,168 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+)
+
+type schemaSuite struct{}
+
+var _ = Suite(&schemaSuite{})
+
+const testConfigSchema = `{
+	"schema": {
+		"port": {"type": "int", "min": 1, "max": 65535, "default": 8080},
+		"mode": {"type": "string", "choices": ["fast", "safe"], "default": "safe"},
+		"verbose": "bool",
+		"password": {"type": "string", "visibility": "secret"},
+		"db": {
+			"schema": {
+				"host": {"type": "string", "default": "localhost"},
+				"token": {"type": "string", "visibility": "secret"}
+			}
+		}
+	}
+}`
+
+func (s *schemaSuite) TestParseSchemaErrors(c *C) {
+	for _, t := range []struct {
+		schema string
+		err    string
+	}{
+		{`[]`, `cannot parse configuration schema: cannot parse top level schema as map: .*`},
+		{`{}`, `cannot parse configuration schema: cannot parse top level schema: must have a "schema" constraint`},
+		{`{"schema": {"port": {"type": "int", "max": 10, "default": 11}}}`, `cannot parse configuration schema: invalid default for "port": .*`},
+		{`{"schema": {"db": {"schema": {"host": {"type": "string", "default": 1}}}}}`, `cannot parse configuration schema: invalid default for "db.host": .*`},
+	} {
+		_, err := config.ParseSchema([]byte(t.schema))
+		c.Check(err, ErrorMatches, t.err, Commentf("%s", t.schema))
+	}
+}
+
+func (s *schemaSuite) TestValidate(c *C) {
+	schema, err := config.ParseSchema([]byte(testConfigSchema))
+	c.Assert(err, IsNil)
+
+	c.Check(schema.Validate(nil), IsNil)
+	c.Check(schema.Validate(map[string]any{"port": 443, "db": map[string]any{"token": "x"}}), IsNil)
+
+	for _, t := range []struct {
+		cfg map[string]any
+		err string
+	}{
+		{map[string]any{"port": 0}, `cannot accept element in "port": 0 is less than the allowed minimum 1`},
+		{map[string]any{"port": "80"}, `cannot accept element in "port": expected int type but value was string`},
+		{map[string]any{"mode": "slow"}, `cannot accept element in "mode": string "slow" is not one of the allowed choices`},
+		{map[string]any{"other": true}, `cannot accept top level element: map contains unexpected key "other"`},
+		{map[string]any{"db": map[string]any{"port": 1}}, `cannot accept element in "db": map contains unexpected key "port"`},
+	} {
+		c.Check(schema.Validate(t.cfg), ErrorMatches, t.err, Commentf("%v", t.cfg))
+	}
+}
+
+func (s *schemaSuite) TestWithDefaults(c *C) {
+	schema, err := config.ParseSchema([]byte(testConfigSchema))
+	c.Assert(err, IsNil)
+
+	c.Check(schema.WithDefaults("", nil), DeepEquals, map[string]any{
+		"port": json.Number("8080"),
+		"mode": "safe",
+		"db":   map[string]any{"host": "localhost"},
+	})
+	c.Check(schema.WithDefaults("", map[string]any{"port": 22, "db": map[string]any{"token": "x"}}), DeepEquals, map[string]any{
+		"port": 22,
+		"mode": "safe",
+		"db":   map[string]any{"host": "localhost", "token": "x"},
+	})
+	c.Check(schema.WithDefaults("port", nil), Equals, json.Number("8080"))
+	c.Check(schema.WithDefaults("port", 22), Equals, 22)
+	c.Check(schema.WithDefaults("db.host", nil), Equals, "localhost")
+	c.Check(schema.WithDefaults("db", nil), DeepEquals, map[string]any{"host": "localhost"})
+	c.Check(schema.WithDefaults("verbose", nil), IsNil)
+	c.Check(schema.WithDefaults("port.foo", nil), IsNil)
+
+	// defaults are not shared between calls
+	cfg := schema.WithDefaults("", nil).(map[string]any)
+	cfg["db"].(map[string]any)["host"] = "remote"
+	c.Check(schema.WithDefaults("db.host", nil), Equals, "localhost")
+}
+
+func (s *schemaSuite) TestRedact(c *C) {
+	schema, err := config.ParseSchema([]byte(testConfigSchema))
+	c.Assert(err, IsNil)
+
+	cfg := map[string]any{
+		"port":     22,
+		"password": "hunter2",
+		"db":       map[string]any{"host": "remote", "token": "abc"},
+	}
+	redacted, secret := schema.Redact("", cfg)
+	c.Check(secret, Equals, false)
+	c.Check(redacted, DeepEquals, map[string]any{
+		"port": 22,
+		"db":   map[string]any{"host": "remote"},
+	})
+
+	redacted, secret = schema.Redact("db", cfg["db"])
+	c.Check(secret, Equals, false)
+	c.Check(redacted, DeepEquals, map[string]any{"host": "remote"})
+
+	for _, key := range []string{"password", "db.token"} {
+		redacted, secret = schema.Redact(key, "value")
+		c.Check(secret, Equals, true)
+		c.Check(redacted, IsNil)
+	}
+
+	// options unknown to the schema are kept
+	redacted, secret = schema.Redact("", map[string]any{"other": 1})
+	c.Check(secret, Equals, false)
+	c.Check(redacted, DeepEquals, map[string]any{"other": 1})
+}
+
+func (s *schemaSuite) TestReadSchema(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	defer dirs.SetRootDir("")
+
+	info := snaptest.MockSnap(c, "name: foo\nversion: 1\n", &snap.SideInfo{Revision: snap.R(1)})
+	schema, err := config.ReadSchema(info)
+	c.Assert(err, IsNil)
+	c.Check(schema, IsNil)
+
+	schemaFile := filepath.Join(info.MountDir(), "meta", "config-schema.json")
+	c.Assert(os.WriteFile(schemaFile, []byte(testConfigSchema), 0644), IsNil)
+	schema, err = config.ReadSchema(info)
+	c.Assert(err, IsNil)
+	c.Assert(schema, NotNil)
+	c.Check(schema.WithDefaults("mode", nil), Equals, "safe")
+
+	c.Assert(os.WriteFile(schemaFile, []byte(`{}`), 0644), IsNil)
+	_, err = config.ReadSchema(info)
+	c.Check(err, ErrorMatches, `cannot parse configuration schema: .*`)
+}
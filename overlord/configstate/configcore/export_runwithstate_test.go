@@ -56,3 +56,11 @@ func MockDevicestateResetSession(f func(*state.State) error) (restore func()) {
 	devicestateResetSession = f
 	return restore
 }
+
+func MockFirewallConfirmTimeout(d time.Duration) (restore func()) {
+	return testutil.Mock(&firewallConfirmTimeout, d)
+}
+
+func MockTimeNow(f func() time.Time) (restore func()) {
+	return testutil.Mock(&timeNow, f)
+}
@@ -107,3 +107,7 @@ func MockEnvPath(newEnvPath string) func() {
 	envFilePath = newEnvPath
 	return func() { envFilePath = oldEnvPath }
 }
+
+func MockKmodLoadModule(f func(module string, options []string) error) func() {
+	return testutil.Mock(&kmodLoadModule, f)
+}
@@ -0,0 +1,584 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/overlord/swfeats"
+	"github.com/snapcore/snapd/systemd"
+)
+
+const (
+	optionFirewallDefaultPolicy = "system.firewall.default-policy"
+	optionFirewallAllow         = "system.firewall.allow"
+
+	firewallTable = "snapd-firewall"
+	// not snapd.firewall.service, the snapd.* units are owned by the
+	// snapd snap, see wrappers.AddSnapdSnapServices
+	firewallUnit = "snapd-firewall.service"
+
+	firewallPendingKey = "firewall-pending-confirmation"
+)
+
+var (
+	firewallInterfaceRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,14}$`)
+
+	// firewallConfirmTimeout is how long new firewall rules stay in place
+	// before they are rolled back unless confirmed
+	firewallConfirmTimeout = 2 * time.Minute
+	// firewallRevertRetry is how long to wait before trying again to
+	// restore the previous rules
+	firewallRevertRetry = 10 * time.Second
+
+	revertFirewallChangeKind = swfeats.RegisterChangeKind("revert-firewall")
+
+	timeNow = time.Now
+)
+
+// ErrNoFirewallConfirmation is returned when confirming firewall rules while
+// no rules are waiting for confirmation.
+var ErrNoFirewallConfirmation = errors.New("no firewall changes are waiting for confirmation")
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core."+optionFirewallDefaultPolicy] = true
+	supportedConfigurations["core."+optionFirewallAllow] = true
+}
+
+func firewallRulesetPath() string {
+	return filepath.Join(dirs.SnapdStateDir(dirs.GlobalRootDir), "firewall", "ruleset.nft")
+}
+
+// firewallRule is an inbound allow rule, written as
+// "<port>[-<port>]/<tcp|udp> [from=<cidr>] [on=<interface>]".
+type firewallRule struct {
+	protocol string
+	portLow  int
+	portHigh int
+	source   *net.IPNet
+	iface    string
+}
+
+func parseFirewallPort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("port must be a number: %v", err)
+	}
+	if port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port %v must be in the range 1-65535", port)
+	}
+	return port, nil
+}
+
+func parseFirewallRule(s string) (*firewallRule, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty rule")
+	}
+
+	var rule firewallRule
+	ports, protocol, ok := strings.Cut(fields[0], "/")
+	if !ok {
+		return nil, fmt.Errorf("rule %q must start with <port>/<protocol>", s)
+	}
+	switch protocol {
+	case "tcp", "udp":
+		rule.protocol = protocol
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+	low, high, isRange := strings.Cut(ports, "-")
+	var err error
+	if rule.portLow, err = parseFirewallPort(low); err != nil {
+		return nil, err
+	}
+	rule.portHigh = rule.portLow
+	if isRange {
+		if rule.portHigh, err = parseFirewallPort(high); err != nil {
+			return nil, err
+		}
+		if rule.portHigh <= rule.portLow {
+			return nil, fmt.Errorf("invalid port range %q", ports)
+		}
+	}
+
+	for _, field := range fields[1:] {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "from":
+			if rule.source != nil {
+				return nil, fmt.Errorf("source specified more than once in rule %q", s)
+			}
+			if _, rule.source, err = net.ParseCIDR(value); err != nil {
+				ip := net.ParseIP(value)
+				if ip == nil {
+					return nil, fmt.Errorf("invalid source %q", value)
+				}
+				bits := 8 * net.IPv6len
+				if ip.To4() != nil {
+					ip = ip.To4()
+					bits = 8 * net.IPv4len
+				}
+				rule.source = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+			}
+		case "on":
+			if rule.iface != "" {
+				return nil, fmt.Errorf("interface specified more than once in rule %q", s)
+			}
+			if !firewallInterfaceRegexp.MatchString(value) {
+				return nil, fmt.Errorf("invalid interface name %q", value)
+			}
+			rule.iface = value
+		default:
+			return nil, fmt.Errorf("unsupported rule parameter %q", field)
+		}
+	}
+	return &rule, nil
+}
+
+func (rule *firewallRule) String() string {
+	var matches []string
+	if rule.iface != "" {
+		matches = append(matches, fmt.Sprintf("iifname %q", rule.iface))
+	}
+	if rule.source != nil {
+		family := "ip6"
+		if rule.source.IP.To4() != nil {
+			family = "ip"
+		}
+		matches = append(matches, fmt.Sprintf("%s saddr %s", family, rule.source))
+	}
+	dport := strconv.Itoa(rule.portLow)
+	if rule.portHigh != rule.portLow {
+		dport = fmt.Sprintf("%d-%d", rule.portLow, rule.portHigh)
+	}
+	matches = append(matches, fmt.Sprintf("%s dport %s", rule.protocol, dport))
+	return strings.Join(matches, " ") + " accept"
+}
+
+type firewallConfig struct {
+	defaultPolicy string
+	rules         []*firewallRule
+}
+
+func firewallSettings(tr ConfGetter) (*firewallConfig, error) {
+	var cfg firewallConfig
+	var err error
+	if cfg.defaultPolicy, err = coreCfg(tr, optionFirewallDefaultPolicy); err != nil {
+		return nil, err
+	}
+	switch cfg.defaultPolicy {
+	case "", "accept", "drop":
+		// valid
+	default:
+		return nil, fmt.Errorf("cannot set %q: unsupported policy %q", optionFirewallDefaultPolicy, cfg.defaultPolicy)
+	}
+
+	allow, err := coreCfg(tr, optionFirewallAllow)
+	if err != nil {
+		return nil, err
+	}
+	if allow == "" {
+		return &cfg, nil
+	}
+	for _, s := range strings.Split(allow, ",") {
+		rule, err := parseFirewallRule(s)
+		if err != nil {
+			return nil, fmt.Errorf("cannot set %q: %v", optionFirewallAllow, err)
+		}
+		cfg.rules = append(cfg.rules, rule)
+	}
+	if cfg.defaultPolicy != "drop" {
+		return nil, fmt.Errorf("cannot set %q: rules have no effect unless %q is \"drop\"", optionFirewallAllow, optionFirewallDefaultPolicy)
+	}
+	return &cfg, nil
+}
+
+// ruleset returns the nftables ruleset for the configuration, or an empty
+// string when no firewall is configured. Loading the ruleset replaces the
+// previous rules of the snapd table atomically.
+func (cfg *firewallConfig) ruleset() string {
+	if cfg.defaultPolicy != "drop" {
+		return ""
+	}
+
+	var buf strings.Builder
+	buf.WriteString("# Generated by snapd from the system.firewall configuration, do not edit.\n")
+	fmt.Fprintf(&buf, "table inet %s\n", firewallTable)
+	fmt.Fprintf(&buf, "delete table inet %s\n", firewallTable)
+	fmt.Fprintf(&buf, "table inet %s {\n", firewallTable)
+	buf.WriteString("\tchain input {\n")
+	buf.WriteString("\t\ttype filter hook input priority 0; policy drop;\n")
+	buf.WriteString("\t\tct state established,related accept\n")
+	buf.WriteString("\t\tct state invalid drop\n")
+	buf.WriteString("\t\tiif \"lo\" accept\n")
+	// ICMPv6 is needed for neighbour discovery
+	buf.WriteString("\t\tmeta l4proto { icmp, ipv6-icmp } accept\n")
+	for _, rule := range cfg.rules {
+		fmt.Fprintf(&buf, "\t\t%s\n", rule)
+	}
+	buf.WriteString("\t}\n")
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func validateFirewallSettings(tr RunTransaction) error {
+	_, err := firewallSettings(tr)
+	return err
+}
+
+func hasFirewallChanges(tr RunTransaction) bool {
+	for _, chg := range tr.Changes() {
+		if strings.HasPrefix(chg, "core.system.firewall.") {
+			return true
+		}
+	}
+	return false
+}
+
+// loadFirewallRuleset loads the given ruleset in a single nft transaction,
+// an empty ruleset removes the snapd table.
+func loadFirewallRuleset(ruleset string) error {
+	if ruleset == "" {
+		ruleset = fmt.Sprintf("table inet %[1]s\ndelete table inet %[1]s\n", firewallTable)
+	}
+	cmd := exec.Command("nft", "-f", "-")
+	cmd.Stdin = strings.NewReader(ruleset)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return osutil.OutputErr(output, err)
+	}
+	return nil
+}
+
+const firewallUnitTemplate = `[Unit]
+Description=Host firewall from the snapd system configuration
+DefaultDependencies=no
+After=local-fs.target
+Before=network-pre.target shutdown.target
+Wants=network-pre.target
+Conflicts=shutdown.target
+ConditionPathExists=%[1]s
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/sbin/nft -f %[1]s
+
+[Install]
+WantedBy=sysinit.target
+`
+
+// persistFirewallRuleset writes the ruleset and the unit loading it at boot,
+// or removes both when no firewall is configured.
+func persistFirewallRuleset(ruleset string) error {
+	sysd := systemd.New(systemd.SystemMode, &sysdLogger{})
+	rulesetPath := firewallRulesetPath()
+	unitPath := filepath.Join(dirs.SnapServicesDir, firewallUnit)
+
+	if ruleset == "" {
+		if !osutil.FileExists(unitPath) {
+			return os.RemoveAll(filepath.Dir(rulesetPath))
+		}
+		if err := sysd.DisableNoReload([]string{firewallUnit}); err != nil {
+			return err
+		}
+		if err := os.Remove(unitPath); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(filepath.Dir(rulesetPath)); err != nil {
+			return err
+		}
+		return sysd.DaemonReload()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(rulesetPath), 0755); err != nil {
+		return err
+	}
+	if err := osutil.AtomicWriteFile(rulesetPath, []byte(ruleset), 0600, 0); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dirs.SnapServicesDir, 0755); err != nil {
+		return err
+	}
+	err := osutil.EnsureFileState(unitPath, &osutil.MemoryFileState{
+		Content: []byte(fmt.Sprintf(firewallUnitTemplate, rulesetPath)),
+		Mode:    0644,
+	})
+	if errors.Is(err, osutil.ErrSameState) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := sysd.DaemonReload(); err != nil {
+		return err
+	}
+	return sysd.EnableNoReload([]string{firewallUnit})
+}
+
+// firewallPending records firewall rules which were loaded but not made
+// persistent yet. Unless confirmed before the deadline, the rules and the
+// configuration are rolled back by the task of the recorded change, which
+// also happens after a restart of snapd.
+type firewallPending struct {
+	Ruleset  string         `json:"ruleset"`
+	Previous map[string]any `json:"previous"`
+	ChangeID string         `json:"change-id"`
+	Deadline time.Time      `json:"deadline"`
+}
+
+func firewallPendingConfirmation(st *state.State) (*firewallPending, error) {
+	var pending firewallPending
+	if err := st.Get(firewallPendingKey, &pending); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// firewallPristineValues returns the firewall options of the applied
+// configuration, nil values are unset options.
+func firewallPristineValues(tr ConfGetter) (map[string]any, error) {
+	previous := make(map[string]any, 2)
+	for _, option := range []string{optionFirewallDefaultPolicy, optionFirewallAllow} {
+		var value any
+		if err := tr.GetPristine("core", option, &value); err != nil && !config.IsNoOption(err) {
+			return nil, err
+		}
+		previous[option] = value
+	}
+	return previous, nil
+}
+
+// scheduleFirewallRevert records the loaded ruleset as waiting for
+// confirmation and creates the change rolling it back at the deadline, which
+// is returned.
+func scheduleFirewallRevert(st *state.State, ruleset string, previous map[string]any) time.Time {
+	deadline := timeNow().Add(firewallConfirmTimeout)
+	chg := st.NewChange(revertFirewallChangeKind,
+		i18n.G("Revert firewall configuration unless confirmed"))
+	t := st.NewTask("revert-firewall",
+		fmt.Sprintf("Revert firewall configuration unless confirmed before %s", deadline.Format(time.RFC3339)))
+	t.At(deadline)
+	chg.AddTask(t)
+
+	st.Set(firewallPendingKey, &firewallPending{
+		Ruleset:  ruleset,
+		Previous: previous,
+		ChangeID: chg.ID(),
+		Deadline: deadline,
+	})
+	return deadline
+}
+
+// noteFirewallConfirmation tells through the task applying the configuration
+// until when the new firewall rules must be confirmed, the deadline is also
+// exposed in the data of its change for snap set to report it.
+func noteFirewallConfirmation(task *state.Task, deadline time.Time) error {
+	task.Logf("firewall configuration must be confirmed with \"snap firewall confirm\" before %s, otherwise it is reverted", deadline.Format(time.RFC3339))
+
+	chg := task.Change()
+	if chg == nil {
+		return nil
+	}
+	var apiData map[string]any
+	if err := chg.Get("api-data", &apiData); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	if apiData == nil {
+		apiData = make(map[string]any, 1)
+	}
+	apiData["firewall-confirm-before"] = deadline
+	chg.Set("api-data", apiData)
+	return nil
+}
+
+// FirewallConfirmationDeadline returns the time at which the firewall rules
+// waiting for confirmation are rolled back, or the zero time if there are
+// none. The state must be locked by the caller.
+func FirewallConfirmationDeadline(st *state.State) (time.Time, error) {
+	pending, err := firewallPendingConfirmation(st)
+	if err != nil || pending == nil {
+		return time.Time{}, err
+	}
+	return pending.Deadline, nil
+}
+
+// ConfirmFirewall makes the firewall rules waiting for confirmation
+// persistent, so that they are not rolled back. The state must be locked by
+// the caller.
+func ConfirmFirewall(st *state.State) error {
+	pending, err := firewallPendingConfirmation(st)
+	if err != nil {
+		return err
+	}
+	if pending == nil {
+		return ErrNoFirewallConfirmation
+	}
+	if err := persistFirewallRuleset(pending.Ruleset); err != nil {
+		return fmt.Errorf("cannot confirm firewall configuration: %v", err)
+	}
+	st.Set(firewallPendingKey, nil)
+
+	// the revert task has nothing to do anymore, if it is already
+	// running it notices the confirmation once it retries
+	if chg := st.Change(pending.ChangeID); chg != nil {
+		for _, t := range chg.Tasks() {
+			if t.Status() == state.DoStatus {
+				t.Logf("firewall configuration was confirmed")
+				t.SetStatus(state.DoneStatus)
+			}
+		}
+	}
+	return nil
+}
+
+// RevertUnconfirmedFirewall restores the firewall rules and configuration
+// which were in place before the rules recorded as waiting for confirmation
+// by the change of the given task, unless they were confirmed meanwhile. The
+// state must be locked by the caller.
+func RevertUnconfirmedFirewall(t *state.Task) error {
+	st := t.State()
+	pending, err := firewallPendingConfirmation(st)
+	if err != nil {
+		return err
+	}
+	if pending == nil || pending.ChangeID != t.Change().ID() {
+		t.Logf("firewall configuration was confirmed")
+		return nil
+	}
+
+	// the persisted ruleset is the one loaded at boot, it is only
+	// replaced once the new rules are confirmed
+	oldRuleset, err := os.ReadFile(firewallRulesetPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := loadFirewallRuleset(string(oldRuleset)); err != nil {
+		logger.Noticef("cannot restore previous firewall rules: %v", err)
+		return &state.Retry{After: firewallRevertRetry, Reason: "cannot restore previous firewall rules"}
+	}
+
+	tr := config.NewTransaction(st)
+	for option, value := range pending.Previous {
+		if err := tr.Set("core", option, value); err != nil {
+			return err
+		}
+	}
+	tr.Commit()
+	st.Set(firewallPendingKey, nil)
+
+	msg := fmt.Sprintf("firewall configuration was not confirmed before %s, previous rules restored", pending.Deadline.Format(time.RFC3339))
+	t.Logf("%s", msg)
+	st.Warnf("%s", msg)
+	return nil
+}
+
+func handleFirewallConfiguration(tr RunTransaction, opts *fsOnlyContext) error {
+	if !hasFirewallChanges(tr) {
+		return nil
+	}
+
+	cfg, err := firewallSettings(tr)
+	if err != nil {
+		return err
+	}
+	ruleset := cfg.ruleset()
+
+	st := tr.State()
+	st.Lock()
+	pending, err := firewallPendingConfirmation(st)
+	st.Unlock()
+	if err != nil {
+		return err
+	}
+	if pending != nil {
+		return fmt.Errorf("cannot set firewall configuration: previous changes are waiting for confirmation until %s", pending.Deadline.Format(time.RFC3339))
+	}
+
+	oldRuleset, err := os.ReadFile(firewallRulesetPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if bytes.Equal(oldRuleset, []byte(ruleset)) {
+		return nil
+	}
+
+	// Gadget defaults are applied while seeding, there is nobody to
+	// confirm them and they are the factory settings to fall back to.
+	seeded, err := alreadySeeded(tr)
+	if err != nil {
+		return err
+	}
+	var previous map[string]any
+	if seeded {
+		if previous, err = firewallPristineValues(tr); err != nil {
+			return err
+		}
+	}
+
+	if err := loadFirewallRuleset(ruleset); err != nil {
+		return fmt.Errorf("cannot apply firewall rules: %v", err)
+	}
+
+	if seeded {
+		// The new rules may lock out whoever is managing the device,
+		// which cannot be detected from here. They are only made
+		// persistent once confirmed through the API, otherwise they
+		// are rolled back at the deadline. Until then the previous
+		// rules are still the ones loaded at boot.
+		st.Lock()
+		defer st.Unlock()
+		deadline := scheduleFirewallRevert(st, ruleset, previous)
+		if task := tr.Task(); task != nil {
+			return noteFirewallConfirmation(task, deadline)
+		}
+		return nil
+	}
+
+	if err := persistFirewallRuleset(ruleset); err != nil {
+		if e := loadFirewallRuleset(string(oldRuleset)); e != nil {
+			logger.Noticef("cannot restore previous firewall rules: %v", e)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,453 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type firewallSuite struct {
+	configcoreSuite
+
+	now      time.Time
+	mockNft  *testutil.MockCmd
+	nftInput string
+
+	rulesetPath string
+	unitPath    string
+}
+
+var _ = Suite(&firewallSuite{})
+
+const firewallDropRuleset = `# Generated by snapd from the system.firewall configuration, do not edit.
+table inet snapd-firewall
+delete table inet snapd-firewall
+table inet snapd-firewall {
+	chain input {
+		type filter hook input priority 0; policy drop;
+		ct state established,related accept
+		ct state invalid drop
+		iif "lo" accept
+		meta l4proto { icmp, ipv6-icmp } accept
+%s	}
+}
+`
+
+const firewallRemoveRuleset = `table inet snapd-firewall
+delete table inet snapd-firewall
+`
+
+func (s *firewallSuite) SetUpTest(c *C) {
+	s.configcoreSuite.SetUpTest(c)
+
+	s.now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s.AddCleanup(configcore.MockTimeNow(func() time.Time { return s.now }))
+	s.AddCleanup(configcore.MockFirewallConfirmTimeout(time.Minute))
+
+	s.nftInput = filepath.Join(c.MkDir(), "nft-input")
+	s.mockNft = testutil.MockCommand(c, "nft", fmt.Sprintf(`cat >> %[1]s; echo "--" >> %[1]s`, s.nftInput))
+	s.AddCleanup(s.mockNft.Restore)
+
+	s.state.Lock()
+	s.state.Set("seeded", true)
+	s.state.Unlock()
+
+	// needed by the proxy handler
+	c.Assert(os.MkdirAll(filepath.Join(dirs.GlobalRootDir, "/etc"), 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dirs.GlobalRootDir, "/etc/environment"), nil, 0644), IsNil)
+
+	s.rulesetPath = filepath.Join(dirs.SnapdStateDir(dirs.GlobalRootDir), "firewall/ruleset.nft")
+	s.unitPath = filepath.Join(dirs.SnapServicesDir, "snapd-firewall.service")
+}
+
+func (s *firewallSuite) TestConfigureFirewallNoChanges(c *C) {
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.mockNft.Calls(), HasLen, 0)
+	c.Check(s.rulesetPath, testutil.FileAbsent)
+}
+
+func (s *firewallSuite) revertTask(c *C) *state.Task {
+	s.state.Lock()
+	defer s.state.Unlock()
+	var chgs []*state.Change
+	for _, chg := range s.state.Changes() {
+		if chg.Kind() == "revert-firewall" {
+			chgs = append(chgs, chg)
+		}
+	}
+	c.Assert(chgs, HasLen, 1)
+	tasks := chgs[0].Tasks()
+	c.Assert(tasks, HasLen, 1)
+	c.Assert(tasks[0].Kind(), Equals, "revert-firewall")
+	return tasks[0]
+}
+
+func (s *firewallSuite) TestConfigureFirewall(c *C) {
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.firewall.default-policy": "drop",
+			"system.firewall.allow":          "22/tcp from=10.0.0.0/8 on=eth0, 8000-8080/tcp, 53/udp from=fd00::1, 443/tcp from=192.168.1.7",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	ruleset := fmt.Sprintf(firewallDropRuleset, `		iifname "eth0" ip saddr 10.0.0.0/8 tcp dport 22 accept
+		tcp dport 8000-8080 accept
+		ip6 saddr fd00::1/128 udp dport 53 accept
+		ip saddr 192.168.1.7/32 tcp dport 443 accept
+`)
+	c.Check(s.mockNft.Calls(), DeepEquals, [][]string{{"nft", "-f", "-"}})
+	c.Check(s.nftInput, testutil.FileEquals, ruleset+"--\n")
+	// the rules are only made persistent once confirmed
+	c.Check(s.rulesetPath, testutil.FileAbsent)
+	c.Check(s.unitPath, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, HasLen, 0)
+
+	deadline := s.now.Add(time.Minute)
+	t := s.revertTask(c)
+	s.state.Lock()
+	c.Check(t.AtTime().Equal(deadline), Equals, true)
+	pendingDeadline, err := configcore.FirewallConfirmationDeadline(s.state)
+	c.Assert(err, IsNil)
+	c.Check(pendingDeadline.Equal(deadline), Equals, true)
+
+	err = configcore.ConfirmFirewall(s.state)
+	s.state.Unlock()
+	c.Assert(err, IsNil)
+
+	c.Check(s.rulesetPath, testutil.FileEquals, ruleset)
+	c.Check(s.unitPath, testutil.FileContains, fmt.Sprintf("ExecStart=/usr/sbin/nft -f %s\n", s.rulesetPath))
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"daemon-reload"},
+		{"--no-reload", "enable", "snapd-firewall.service"},
+	})
+
+	s.state.Lock()
+	// the revert task is not needed anymore
+	c.Check(t.Status(), Equals, state.DoneStatus)
+	c.Check(t.Change().Status(), Equals, state.DoneStatus)
+	c.Check(configcore.RevertUnconfirmedFirewall(t), IsNil)
+	pendingDeadline, err = configcore.FirewallConfirmationDeadline(s.state)
+	c.Assert(err, IsNil)
+	c.Check(pendingDeadline.IsZero(), Equals, true)
+	c.Check(configcore.ConfirmFirewall(s.state), Equals, configcore.ErrNoFirewallConfirmation)
+	s.state.Unlock()
+
+	// unsetting the policy removes the rules once confirmed
+	s.mockNft.ForgetCalls()
+	c.Assert(os.Remove(s.nftInput), IsNil)
+	s.systemctlArgs = nil
+	err = configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+		changes: map[string]any{
+			"system.firewall.default-policy": "",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.mockNft.Calls(), DeepEquals, [][]string{{"nft", "-f", "-"}})
+	c.Check(s.nftInput, testutil.FileEquals, firewallRemoveRuleset+"--\n")
+	c.Check(s.rulesetPath, testutil.FileEquals, ruleset)
+
+	s.state.Lock()
+	err = configcore.ConfirmFirewall(s.state)
+	s.state.Unlock()
+	c.Assert(err, IsNil)
+	c.Check(s.rulesetPath, testutil.FileAbsent)
+	c.Check(s.unitPath, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"--no-reload", "disable", "snapd-firewall.service"},
+		{"daemon-reload"},
+	})
+}
+
+func (s *firewallSuite) TestConfigureFirewallNotesConfirmation(c *C) {
+	s.state.Lock()
+	chg := s.state.NewChange("configure-snap", "...")
+	task := s.state.NewTask("run-hook", "...")
+	chg.AddTask(task)
+	chg.Set("api-data", map[string]any{"snap-names": []string{"core"}})
+	s.state.Unlock()
+
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		task:  task,
+		changes: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(task.Log(), HasLen, 1)
+	c.Check(task.Log()[0], Matches, `.* firewall configuration must be confirmed with "snap firewall confirm" before 2026-10-19T12:01:00Z, otherwise it is reverted`)
+	var apiData map[string]any
+	c.Assert(chg.Get("api-data", &apiData), IsNil)
+	c.Check(apiData, DeepEquals, map[string]any{
+		"snap-names":              []any{"core"},
+		"firewall-confirm-before": "2026-10-19T12:01:00Z",
+	})
+}
+
+func (s *firewallSuite) TestConfigureFirewallSameRuleset(c *C) {
+	c.Assert(os.MkdirAll(filepath.Dir(s.rulesetPath), 0755), IsNil)
+	c.Assert(os.WriteFile(s.rulesetPath, []byte(fmt.Sprintf(firewallDropRuleset, "")), 0600), IsNil)
+
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.mockNft.Calls(), HasLen, 0)
+	c.Check(s.systemctlArgs, HasLen, 0)
+	s.state.Lock()
+	c.Check(s.state.Changes(), HasLen, 0)
+	s.state.Unlock()
+}
+
+func (s *firewallSuite) TestConfigureFirewallWaitingForConfirmation(c *C) {
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	err = configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+		changes: map[string]any{
+			"system.firewall.allow": "22/tcp",
+		},
+	})
+	c.Assert(err, ErrorMatches, "cannot set firewall configuration: previous changes are waiting for confirmation until 2026-10-19T12:01:00Z")
+	c.Check(s.mockNft.Calls(), HasLen, 1)
+}
+
+func (s *firewallSuite) TestRevertUnconfirmedFirewall(c *C) {
+	oldRuleset := fmt.Sprintf(firewallDropRuleset, "\t\ttcp dport 22 accept\n")
+	c.Assert(os.MkdirAll(filepath.Dir(s.rulesetPath), 0755), IsNil)
+	c.Assert(os.WriteFile(s.rulesetPath, []byte(oldRuleset), 0600), IsNil)
+
+	s.state.Lock()
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "system.firewall.default-policy", "drop"), IsNil)
+	c.Assert(tr.Set("core", "system.firewall.allow", "22/tcp"), IsNil)
+	tr.Commit()
+	s.state.Unlock()
+
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.firewall.default-policy": "drop",
+			"system.firewall.allow":          "22/tcp",
+		},
+		changes: map[string]any{
+			"system.firewall.allow": "2222/tcp",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	// the configuration change is committed
+	s.state.Lock()
+	tr = config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "system.firewall.allow", "2222/tcp"), IsNil)
+	tr.Commit()
+	s.state.Unlock()
+
+	t := s.revertTask(c)
+	s.state.Lock()
+	err = configcore.RevertUnconfirmedFirewall(t)
+	s.state.Unlock()
+	c.Assert(err, IsNil)
+
+	// the new rules were loaded and then replaced by the previous ones
+	newRuleset := fmt.Sprintf(firewallDropRuleset, "\t\ttcp dport 2222 accept\n")
+	c.Check(s.mockNft.Calls(), DeepEquals, [][]string{{"nft", "-f", "-"}, {"nft", "-f", "-"}})
+	c.Check(s.nftInput, testutil.FileEquals, newRuleset+"--\n"+oldRuleset+"--\n")
+	c.Check(s.rulesetPath, testutil.FileEquals, oldRuleset)
+	c.Check(s.systemctlArgs, HasLen, 0)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	tr = config.NewTransaction(s.state)
+	var allow, policy string
+	c.Assert(tr.Get("core", "system.firewall.allow", &allow), IsNil)
+	c.Check(allow, Equals, "22/tcp")
+	c.Assert(tr.Get("core", "system.firewall.default-policy", &policy), IsNil)
+	c.Check(policy, Equals, "drop")
+	deadline, err := configcore.FirewallConfirmationDeadline(s.state)
+	c.Assert(err, IsNil)
+	c.Check(deadline.IsZero(), Equals, true)
+
+	// the revert is reported
+	const msg = "firewall configuration was not confirmed before 2026-10-19T12:01:00Z, previous rules restored"
+	c.Assert(t.Log(), HasLen, 1)
+	c.Check(t.Log()[0], Matches, ".* "+msg)
+	warnings := s.state.AllWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Check(warnings[0].String(), Equals, msg)
+}
+
+func (s *firewallSuite) TestRevertUnconfirmedFirewallUnsetsOptions(c *C) {
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	s.state.Lock()
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "system.firewall.default-policy", "drop"), IsNil)
+	tr.Commit()
+	s.state.Unlock()
+
+	t := s.revertTask(c)
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(configcore.RevertUnconfirmedFirewall(t), IsNil)
+	c.Check(s.nftInput, testutil.FileEquals, fmt.Sprintf(firewallDropRuleset, "")+"--\n"+firewallRemoveRuleset+"--\n")
+
+	var policy string
+	err = config.NewTransaction(s.state).Get("core", "system.firewall.default-policy", &policy)
+	c.Check(config.IsNoOption(err), Equals, true)
+}
+
+func (s *firewallSuite) TestRevertUnconfirmedFirewallRetry(c *C) {
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	mockNft := testutil.MockCommand(c, "nft", `echo "Error: busy"; exit 1`)
+	defer mockNft.Restore()
+
+	t := s.revertTask(c)
+	s.state.Lock()
+	defer s.state.Unlock()
+	err = configcore.RevertUnconfirmedFirewall(t)
+	c.Assert(err, FitsTypeOf, &state.Retry{})
+	// still waiting to be rolled back
+	deadline, err := configcore.FirewallConfirmationDeadline(s.state)
+	c.Assert(err, IsNil)
+	c.Check(deadline.IsZero(), Equals, false)
+}
+
+func (s *firewallSuite) TestConfigureFirewallNotSeeded(c *C) {
+	s.state.Lock()
+	s.state.Set("seeded", false)
+	s.state.Unlock()
+
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.firewall.default-policy": "drop",
+			"system.firewall.allow":          "22/tcp",
+		},
+	})
+	c.Assert(err, IsNil)
+	// gadget defaults need no confirmation
+	c.Check(s.rulesetPath, testutil.FileEquals, fmt.Sprintf(firewallDropRuleset, "\t\ttcp dport 22 accept\n"))
+	c.Check(s.unitPath, testutil.FilePresent)
+	s.state.Lock()
+	c.Check(s.state.Changes(), HasLen, 0)
+	s.state.Unlock()
+}
+
+func (s *firewallSuite) TestConfigureFirewallNftFails(c *C) {
+	mockNft := testutil.MockCommand(c, "nft", `echo "Error: syntax error"; exit 1`)
+	defer mockNft.Restore()
+
+	err := configcore.Run(coreDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.firewall.default-policy": "drop",
+		},
+	})
+	c.Assert(err, ErrorMatches, "cannot apply firewall rules: Error: syntax error")
+	c.Check(s.rulesetPath, testutil.FileAbsent)
+	c.Check(s.unitPath, testutil.FileAbsent)
+	s.state.Lock()
+	c.Check(s.state.Changes(), HasLen, 0)
+	s.state.Unlock()
+}
+
+func (s *firewallSuite) TestConfigureFirewallInvalid(c *C) {
+	for _, tc := range []struct {
+		conf   map[string]any
+		errStr string
+	}{
+		{map[string]any{"system.firewall.default-policy": "reject"}, `cannot set "system.firewall.default-policy": unsupported policy "reject"`},
+		{map[string]any{"system.firewall.allow": "22/tcp"}, `cannot set "system.firewall.allow": rules have no effect unless "system.firewall.default-policy" is "drop"`},
+		{map[string]any{"system.firewall.default-policy": "accept", "system.firewall.allow": "22/tcp"}, `cannot set "system.firewall.allow": rules have no effect unless "system.firewall.default-policy" is "drop"`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "22"}, `cannot set "system.firewall.allow": rule "22" must start with <port>/<protocol>`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "22/sctp"}, `cannot set "system.firewall.allow": unsupported protocol "sctp"`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "ssh/tcp"}, `cannot set "system.firewall.allow": port must be a number: .*`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "0/tcp"}, `cannot set "system.firewall.allow": port 0 must be in the range 1-65535`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "90-80/tcp"}, `cannot set "system.firewall.allow": invalid port range "90-80"`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "22/tcp,"}, `cannot set "system.firewall.allow": empty rule`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "22/tcp from=10.0.0.300"}, `cannot set "system.firewall.allow": invalid source "10.0.0.300"`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "22/tcp from=10.0.0.0/8 from=10.0.0.1"}, `cannot set "system.firewall.allow": source specified more than once in rule "22/tcp from=10.0.0.0/8 from=10.0.0.1"`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": `22/tcp on=eth0"`}, `cannot set "system.firewall.allow": invalid interface name "eth0\\""`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "22/tcp on=averyveryverylongname"}, `cannot set "system.firewall.allow": invalid interface name "averyveryverylongname"`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "22/tcp on=eth0 on=eth1"}, `cannot set "system.firewall.allow": interface specified more than once in rule "22/tcp on=eth0 on=eth1"`},
+		{map[string]any{"system.firewall.default-policy": "drop", "system.firewall.allow": "22/tcp to=10.0.0.1"}, `cannot set "system.firewall.allow": unsupported rule parameter "to=10.0.0.1"`},
+	} {
+		err := configcore.Run(coreDev, &mockConf{
+			state:   s.state,
+			changes: tc.conf,
+		})
+		c.Check(err, ErrorMatches, tc.errStr, Commentf("%v", tc.conf))
+	}
+	c.Check(s.mockNft.Calls(), HasLen, 0)
+}
@@ -96,12 +96,18 @@ func init() {
 	// system.kernel.printk.console-loglevel
 	addFSOnlyHandler(validateSysctlOptions, handleSysctlConfiguration, coreOnly)
 
+	// system.kernel.modules.{load,blacklist}
+	addFSOnlyHandler(validateKernelModulesSettings, handleKernelModulesConfiguration, coreOnly)
+
 	// journal.persistent
 	addFSOnlyHandler(validateJournalSettings, handleJournalConfiguration, coreOnly)
 
 	// system.timezone
 	addFSOnlyHandler(validateTimezoneSettings, handleTimezoneConfiguration, coreOnly)
 
+	// system.time.{ntp-servers,fallback-ntp-servers,poll-interval-min,poll-interval-max}
+	addFSOnlyHandler(validateTimesyncdSettings, handleTimesyncdConfiguration, coreOnly)
+
 	// system.hostname - note that the validation is done via hostnamectl
 	// when applying so there is no validation handler, see LP:1952740
 	addFSOnlyHandler(nil, handleHostnameConfiguration, coreOnly)
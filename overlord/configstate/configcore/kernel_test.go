@@ -139,7 +139,7 @@ func (s *kernelSuite) SetUpTest(c *C) {
 	// we need a device context for this option
 	hookMgr, err := hookstate.Manager(s.state, s.overlord.TaskRunner())
 	c.Assert(err, IsNil)
-	err = configstate.Init(s.state, hookMgr)
+	err = configstate.Init(s.state, hookMgr, s.overlord.TaskRunner())
 	c.Assert(err, IsNil)
 	deviceMgr, err := devicestate.Manager(s.state, hookMgr, s.overlord.TaskRunner(), nil)
 	c.Assert(err, IsNil)
@@ -0,0 +1,316 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/kernel"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/osutil/kmod"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/sysconfig"
+)
+
+const (
+	optionKernelModulesLoad      = "system.kernel.modules.load"
+	optionKernelModulesBlacklist = "system.kernel.modules.blacklist"
+
+	kernelModulesCfgFile = "ubuntu-core.conf"
+)
+
+var (
+	kernelModuleNameRegexp = regexp.MustCompile(`^[-a-zA-Z0-9_]+$`)
+
+	kmodLoadModule = kmod.LoadModule
+)
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core."+optionKernelModulesLoad] = true
+	supportedConfigurations["core."+optionKernelModulesBlacklist] = true
+}
+
+func validateKernelModulesList(tr ConfGetter, option string) ([]string, error) {
+	value, err := coreCfg(tr, option)
+	if err != nil {
+		return nil, err
+	}
+	modules := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	for _, module := range modules {
+		if !kernelModuleNameRegexp.MatchString(module) {
+			return nil, fmt.Errorf("cannot set %q: invalid kernel module name %q", option, module)
+		}
+	}
+	return modules, nil
+}
+
+type kernelModulesConfig struct {
+	load      []string
+	blacklist []string
+}
+
+func kernelModulesSettings(tr ConfGetter) (*kernelModulesConfig, error) {
+	var cfg kernelModulesConfig
+	var err error
+	if cfg.load, err = validateKernelModulesList(tr, optionKernelModulesLoad); err != nil {
+		return nil, err
+	}
+	if cfg.blacklist, err = validateKernelModulesList(tr, optionKernelModulesBlacklist); err != nil {
+		return nil, err
+	}
+	blacklisted := make(map[string]bool, len(cfg.blacklist))
+	for _, module := range cfg.blacklist {
+		blacklisted[kernel.NormalizeModuleName(module)] = true
+	}
+	for _, module := range cfg.load {
+		if blacklisted[kernel.NormalizeModuleName(module)] {
+			return nil, fmt.Errorf("cannot set %q: kernel module %q is also blacklisted", optionKernelModulesLoad, module)
+		}
+	}
+	return &cfg, nil
+}
+
+func validateKernelModulesSettings(tr ConfGetter) error {
+	_, err := kernelModulesSettings(tr)
+	return err
+}
+
+// kernelModulesOptionsChanged returns the kernel modules options whose value
+// differs from the one of the applied configuration.
+func kernelModulesOptionsChanged(tr ConfGetter) (map[string]bool, error) {
+	changed := make(map[string]bool, 2)
+	for _, option := range []string{optionKernelModulesLoad, optionKernelModulesBlacklist} {
+		value, err := coreCfg(tr, option)
+		if err != nil {
+			return nil, err
+		}
+		var prev any = ""
+		if err := tr.GetPristine("core", option, &prev); err != nil && !config.IsNoOption(err) {
+			return nil, err
+		}
+		if fmt.Sprintf("%v", prev) != value {
+			changed[option] = true
+		}
+	}
+	return changed, nil
+}
+
+// kernelSnapModulesDir returns the modules directory of the current revision
+// of the kernel snap of the device. The drivers tree of the kernel is
+// preferred, as it also carries the modules of the kernel components.
+func kernelSnapModulesDir(dev sysconfig.Device) (string, error) {
+	kernelName := dev.Kernel()
+	if kernelName == "" {
+		return "", fmt.Errorf("cannot read kernel modules: device has no kernel snap")
+	}
+	currentDir := filepath.Join(dirs.SnapMountDir, kernelName, "current")
+	target, err := os.Readlink(currentDir)
+	if err != nil {
+		return "", fmt.Errorf("cannot read kernel modules: %v", err)
+	}
+	rev, err := snap.ParseRevision(filepath.Base(target))
+	if err != nil {
+		return "", fmt.Errorf("cannot read kernel modules: %v", err)
+	}
+	kversion, err := kernel.KernelVersionFromModulesDir(currentDir)
+	if err != nil {
+		return "", fmt.Errorf("cannot read kernel modules: %v", err)
+	}
+	treeModsDir := filepath.Join(kernel.DriversTreeDir(dirs.GlobalRootDir, kernelName, rev), "lib", "modules", kversion)
+	if osutil.IsDirectory(treeModsDir) {
+		return treeModsDir, nil
+	}
+	return filepath.Join(currentDir, "modules", kversion), nil
+}
+
+// checkKernelModulesAvailable checks the modules of the given options against
+// the modules provided by the kernel snap of the device.
+func checkKernelModulesAvailable(dev sysconfig.Device, cfg *kernelModulesConfig, options map[string]bool) (*kernel.Modules, error) {
+	modsDir, err := kernelSnapModulesDir(dev)
+	if err != nil {
+		return nil, err
+	}
+	mods, err := kernel.ReadModules(modsDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read kernel modules: %v", err)
+	}
+	if options[optionKernelModulesLoad] {
+		for _, module := range cfg.load {
+			if !mods.IsLoadable(module) && !mods.IsBuiltin(module) {
+				return nil, fmt.Errorf("cannot set %q: kernel module %q is not provided by the kernel", optionKernelModulesLoad, module)
+			}
+		}
+	}
+	if options[optionKernelModulesBlacklist] {
+		for _, module := range cfg.blacklist {
+			if mods.IsBuiltin(module) {
+				return nil, fmt.Errorf("cannot set %q: kernel module %q is built into the kernel", optionKernelModulesBlacklist, module)
+			}
+			if !mods.IsLoadable(module) {
+				return nil, fmt.Errorf("cannot set %q: kernel module %q is not provided by the kernel", optionKernelModulesBlacklist, module)
+			}
+		}
+	}
+	return mods, nil
+}
+
+func isKernelModuleLoaded(module string) bool {
+	// only loadable modules have an initstate
+	return osutil.FileExists(filepath.Join(dirs.GlobalRootDir, "/sys/module", kernel.NormalizeModuleName(module), "initstate"))
+}
+
+// stateTransaction is implemented by the transaction used when the
+// configuration is applied by the configure hook of the running system.
+type stateTransaction interface {
+	State() *state.State
+	Task() *state.Task
+}
+
+// warnRebootRequired lets the user know that the new configuration is only
+// fully applied after a reboot.
+func warnRebootRequired(tr ConfGetter, msg string) {
+	logger.Noticef("%s", msg)
+	cfg, ok := tr.(stateTransaction)
+	if !ok {
+		return
+	}
+	st := cfg.State()
+	st.Lock()
+	defer st.Unlock()
+
+	if task := cfg.Task(); task != nil {
+		task.Logf("%s", msg)
+	}
+	st.Warnf("%s", msg)
+}
+
+func handleKernelModulesConfiguration(dev sysconfig.Device, tr ConfGetter, opts *fsOnlyContext) error {
+	cfg, err := kernelModulesSettings(tr)
+	if err != nil {
+		return err
+	}
+
+	var mods *kernel.Modules
+	modulesLoadDir := dirs.SnapKModModulesDir
+	modprobeDir := dirs.SnapKModModprobeDir
+	if opts == nil {
+		// only the options being changed are checked, modules which
+		// went away with a refresh of the kernel must not prevent
+		// other changes of the configuration
+		changed, err := kernelModulesOptionsChanged(tr)
+		if err != nil {
+			return err
+		}
+		if (changed[optionKernelModulesLoad] && len(cfg.load) != 0) || (changed[optionKernelModulesBlacklist] && len(cfg.blacklist) != 0) {
+			if mods, err = checkKernelModulesAvailable(dev, cfg, changed); err != nil {
+				return err
+			}
+		}
+	} else {
+		// The kernel that runs the image is not necessarily the one
+		// that applies the defaults (e.g. when preseeding), so the
+		// modules are only checked by name here.
+		modulesLoadDir = filepath.Join(opts.RootDir, "/etc/modules-load.d")
+		modprobeDir = filepath.Join(opts.RootDir, "/etc/modprobe.d")
+	}
+
+	// Ensure content of configuration files (paths are
+	// /etc/modules-load.d/ubuntu-core.conf and
+	// /etc/modprobe.d/ubuntu-core.conf), the files are removed when the
+	// options are not set
+	var loadContent, blacklistContent strings.Builder
+	for _, module := range cfg.load {
+		fmt.Fprintf(&loadContent, "%s\n", module)
+	}
+	for _, module := range cfg.blacklist {
+		// blacklist only prevents loading the module through its
+		// aliases, the install command also prevents loading it
+		// explicitly or as a dependency of another module
+		fmt.Fprintf(&blacklistContent, "blacklist %s\ninstall %s /bin/false\n", module, module)
+	}
+	ensureCfg := func(dir, content string) (bool, error) {
+		dirContent := map[string]osutil.FileState{}
+		if content != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return false, err
+			}
+			dirContent[kernelModulesCfgFile] = &osutil.MemoryFileState{
+				Content: []byte(content),
+				Mode:    0644,
+			}
+		}
+		changed, removed, err := osutil.EnsureDirState(dir, kernelModulesCfgFile, dirContent)
+		if err != nil {
+			return false, err
+		}
+		return len(changed) != 0 || len(removed) != 0, nil
+	}
+	loadChanged, err := ensureCfg(modulesLoadDir, loadContent.String())
+	if err != nil {
+		return err
+	}
+	blacklistChanged, err := ensureCfg(modprobeDir, blacklistContent.String())
+	if err != nil {
+		return err
+	}
+
+	if opts != nil {
+		// the configuration is used on the first boot
+		return nil
+	}
+
+	if loadChanged {
+		for _, module := range cfg.load {
+			if mods != nil && mods.IsBuiltin(module) {
+				continue
+			}
+			// like systemd-modules-load a failure to load one
+			// module does not prevent loading the others
+			if err := kmodLoadModule(module, nil); err != nil {
+				logger.Noticef("cannot load kernel module %q: %v", module, err)
+			}
+		}
+	}
+	if blacklistChanged {
+		var loaded []string
+		for _, module := range cfg.blacklist {
+			if isKernelModuleLoaded(module) {
+				loaded = append(loaded, module)
+			}
+		}
+		if len(loaded) != 0 {
+			warnRebootRequired(tr, fmt.Sprintf("blacklisted kernel modules %s are loaded, a reboot is required to unload them", strings.Join(loaded, ", ")))
+		}
+	}
+
+	return nil
+}
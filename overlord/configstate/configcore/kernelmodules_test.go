@@ -0,0 +1,255 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type kernelModulesSuite struct {
+	configcoreSuite
+
+	loaded []string
+
+	kernelModulesDir string
+	modulesLoadPath  string
+	modprobePath     string
+}
+
+var _ = Suite(&kernelModulesSuite{})
+
+func (s *kernelModulesSuite) SetUpTest(c *C) {
+	s.configcoreSuite.SetUpTest(c)
+
+	s.kernelModulesDir = filepath.Join(dirs.SnapMountDir, "pc-kernel/42/modules/6.8.0-40-generic")
+	writeKernelModules(c, s.kernelModulesDir)
+	c.Assert(os.Symlink("42", filepath.Join(dirs.SnapMountDir, "pc-kernel/current")), IsNil)
+
+	s.loaded = nil
+	s.AddCleanup(configcore.MockKmodLoadModule(func(module string, options []string) error {
+		c.Check(options, HasLen, 0)
+		s.loaded = append(s.loaded, module)
+		if module == "usbserial" {
+			return fmt.Errorf("modprobe failed")
+		}
+		return nil
+	}))
+
+	s.modulesLoadPath = filepath.Join(dirs.GlobalRootDir, "/etc/modules-load.d/ubuntu-core.conf")
+	s.modprobePath = filepath.Join(dirs.GlobalRootDir, "/etc/modprobe.d/ubuntu-core.conf")
+}
+
+func writeKernelModules(c *C, modsDir string) {
+	c.Assert(os.MkdirAll(modsDir, 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modsDir, "modules.dep"), []byte(`kernel/drivers/net/wireguard/wireguard.ko.zst:
+kernel/drivers/net/wireless/realtek/rtw88/rtw88_core.ko.zst:
+kernel/drivers/usb/serial/usbserial.ko.zst:
+kernel/sound/pci/hda/snd-hda-intel.ko.zst:
+`), 0644), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modsDir, "modules.builtin"), []byte("kernel/fs/ext4/ext4.ko\n"), 0644), IsNil)
+}
+
+func (s *kernelModulesSuite) TestConfigureKernelModulesUnset(c *C) {
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf:  map[string]any{},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.modulesLoadPath, testutil.FileAbsent)
+	c.Check(s.modprobePath, testutil.FileAbsent)
+	c.Check(s.loaded, HasLen, 0)
+}
+
+func (s *kernelModulesSuite) TestConfigureKernelModules(c *C) {
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.kernel.modules.load":      "wireguard, usbserial ext4",
+			"system.kernel.modules.blacklist": "rtw88_core,snd_hda_intel",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.modulesLoadPath, testutil.FileEquals, "wireguard\nusbserial\next4\n")
+	c.Check(s.modprobePath, testutil.FileEquals, "blacklist rtw88_core\ninstall rtw88_core /bin/false\nblacklist snd_hda_intel\ninstall snd_hda_intel /bin/false\n")
+	// built-in modules are not loaded and a failure to load one module
+	// does not prevent loading the others
+	c.Check(s.loaded, DeepEquals, []string{"wireguard", "usbserial"})
+
+	// the blacklisted modules were not loaded, no reboot is needed
+	s.state.Lock()
+	c.Check(s.state.AllWarnings(), HasLen, 0)
+	s.state.Unlock()
+
+	// nothing is loaded when the configuration does not change
+	s.loaded = nil
+	err = configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.kernel.modules.load":      "wireguard, usbserial ext4",
+			"system.kernel.modules.blacklist": "rtw88_core,snd_hda_intel",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.loaded, HasLen, 0)
+
+	// unsetting the options removes the files
+	err = configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf:  map[string]any{},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.modulesLoadPath, testutil.FileAbsent)
+	c.Check(s.modprobePath, testutil.FileAbsent)
+}
+
+func (s *kernelModulesSuite) TestConfigureKernelModulesBlacklistLoadedNeedsReboot(c *C) {
+	for _, module := range []string{"rtw88_core", "snd_hda_intel", "ext4"} {
+		initstate := filepath.Join(dirs.GlobalRootDir, "/sys/module", module, "initstate")
+		if module == "ext4" {
+			// built-in modules have no initstate
+			initstate = filepath.Join(filepath.Dir(initstate), "parameters")
+		}
+		c.Assert(os.MkdirAll(filepath.Dir(initstate), 0755), IsNil)
+		c.Assert(os.WriteFile(initstate, []byte("live\n"), 0644), IsNil)
+	}
+
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.kernel.modules.blacklist": "rtw88-core snd-hda-intel wireguard",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.modprobePath, testutil.FileEquals, "blacklist rtw88-core\ninstall rtw88-core /bin/false\nblacklist snd-hda-intel\ninstall snd-hda-intel /bin/false\nblacklist wireguard\ninstall wireguard /bin/false\n")
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	warnings := s.state.AllWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Check(warnings[0].String(), Equals, "blacklisted kernel modules rtw88-core, snd-hda-intel are loaded, a reboot is required to unload them")
+}
+
+func (s *kernelModulesSuite) TestConfigureKernelModulesInvalid(c *C) {
+	for _, tc := range []struct {
+		conf   map[string]any
+		errStr string
+	}{
+		{map[string]any{"system.kernel.modules.load": "wireguard,../evil"}, `cannot set "system.kernel.modules.load": invalid kernel module name "../evil"`},
+		{map[string]any{"system.kernel.modules.blacklist": "foo;bar"}, `cannot set "system.kernel.modules.blacklist": invalid kernel module name "foo;bar"`},
+		{map[string]any{
+			"system.kernel.modules.load":      "snd-hda-intel",
+			"system.kernel.modules.blacklist": "snd_hda_intel",
+		}, `cannot set "system.kernel.modules.load": kernel module "snd-hda-intel" is also blacklisted`},
+		{map[string]any{"system.kernel.modules.load": "nvidia"}, `cannot set "system.kernel.modules.load": kernel module "nvidia" is not provided by the kernel`},
+		{map[string]any{"system.kernel.modules.blacklist": "nvidia"}, `cannot set "system.kernel.modules.blacklist": kernel module "nvidia" is not provided by the kernel`},
+		{map[string]any{"system.kernel.modules.blacklist": "ext4"}, `cannot set "system.kernel.modules.blacklist": kernel module "ext4" is built into the kernel`},
+	} {
+		err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+			state:   s.state,
+			changes: tc.conf,
+		})
+		c.Check(err, ErrorMatches, tc.errStr, Commentf("%v", tc.conf))
+	}
+
+	c.Check(s.modulesLoadPath, testutil.FileAbsent)
+	c.Check(s.modprobePath, testutil.FileAbsent)
+	c.Check(s.loaded, HasLen, 0)
+}
+
+func (s *kernelModulesSuite) TestConfigureKernelModulesUnchangedNotChecked(c *C) {
+	// nvidia went away with a refresh of the kernel, this does not prevent
+	// changing the other option
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.kernel.modules.load": "nvidia",
+		},
+		changes: map[string]any{
+			"system.kernel.modules.blacklist": "rtw88_core",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.modprobePath, testutil.FileEquals, "blacklist rtw88_core\ninstall rtw88_core /bin/false\n")
+
+	// but the changed option is still checked
+	err = configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.kernel.modules.load": "nvidia",
+		},
+		changes: map[string]any{
+			"system.kernel.modules.blacklist": "nouveau",
+		},
+	})
+	c.Assert(err, ErrorMatches, `cannot set "system.kernel.modules.blacklist": kernel module "nouveau" is not provided by the kernel`)
+}
+
+func (s *kernelModulesSuite) TestConfigureKernelModulesDriversTree(c *C) {
+	// modules of kernel components are only in the drivers tree
+	treeModsDir := filepath.Join(dirs.SnapKernelDriversTreesDirUnder(dirs.GlobalRootDir), "pc-kernel/42/lib/modules/6.8.0-40-generic")
+	writeKernelModules(c, treeModsDir)
+	c.Assert(os.WriteFile(filepath.Join(treeModsDir, "modules.dep"), []byte("updates/nvidia/nvidia.ko.zst:\n"), 0644), IsNil)
+
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.kernel.modules.load": "nvidia",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.modulesLoadPath, testutil.FileEquals, "nvidia\n")
+	c.Check(s.loaded, DeepEquals, []string{"nvidia"})
+}
+
+func (s *kernelModulesSuite) TestConfigureKernelModulesNoModulesDir(c *C) {
+	c.Assert(os.RemoveAll(filepath.Join(s.kernelModulesDir, "modules.dep")), IsNil)
+
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"system.kernel.modules.load": "wireguard",
+		},
+	})
+	c.Assert(err, ErrorMatches, `cannot read kernel modules: open .*/pc-kernel/current/modules/6.8.0-40-generic/modules.dep: no such file or directory`)
+}
+
+func (s *kernelModulesSuite) TestFilesystemOnlyApplyKernelModules(c *C) {
+	tmpDir := c.MkDir()
+	conf := configcore.PlainCoreConfig(map[string]any{
+		// modules are not checked against the running kernel
+		"system.kernel.modules.load":      "nvidia",
+		"system.kernel.modules.blacklist": "nouveau",
+	})
+	c.Assert(configcore.FilesystemOnlyApply(core20Dev, tmpDir, conf), IsNil)
+
+	c.Check(filepath.Join(tmpDir, "/etc/modules-load.d/ubuntu-core.conf"), testutil.FileEquals, "nvidia\n")
+	c.Check(filepath.Join(tmpDir, "/etc/modprobe.d/ubuntu-core.conf"), testutil.FileEquals, "blacklist nouveau\ninstall nouveau /bin/false\n")
+	c.Check(s.loaded, HasLen, 0)
+}
@@ -22,10 +22,13 @@ package configcore
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/snap/naming"
 	"github.com/snapcore/snapd/strutil"
 	"github.com/snapcore/snapd/timeutil"
 )
@@ -33,6 +36,9 @@ import (
 const (
 	minInhibitionDays = 1
 	maxInhibitionDays = 21
+
+	minRefreshStaging = time.Hour
+	maxRefreshStaging = 30 * 24 * time.Hour
 )
 
 func init() {
@@ -43,6 +49,132 @@ func init() {
 	supportedConfigurations["core.refresh.retain"] = true
 	supportedConfigurations["core.refresh.rate-limit"] = true
 	supportedConfigurations["core.refresh.max-inhibition-days"] = true
+	supportedConfigurations["core.refresh.blackout"] = true
+}
+
+// isRefreshSnapTimerChange returns whether the option is a per-snap refresh
+// timer, i.e. refresh.snap-timer.<snap>.
+func isRefreshSnapTimerChange(opt string) bool {
+	return strings.HasPrefix(opt, "core.refresh.snap-timer.")
+}
+
+// isRefreshStagingChange returns whether the option is a per-snap refresh
+// staging delay, i.e. refresh.staging.<snap>.
+func isRefreshStagingChange(opt string) bool {
+	return strings.HasPrefix(opt, "core.refresh.staging.")
+}
+
+func validateRefreshStaging(tr RunTransaction) error {
+	for _, name := range tr.Changes() {
+		if !isRefreshStagingChange(name) {
+			continue
+		}
+
+		// core.refresh.staging.<snap>
+		tokens := strings.SplitN(name, ".", 4)
+		if len(tokens) < 4 || tokens[3] == "" {
+			return fmt.Errorf("snap name must be specified for %q", name)
+		}
+		snapName := tokens[3]
+		if err := naming.ValidateInstance(snapName); err != nil {
+			return fmt.Errorf("cannot set refresh staging for snap %q: %v", snapName, err)
+		}
+
+		nameWithoutSnap := strings.SplitN(name, ".", 2)[1]
+		delayStr, err := coreCfg(tr, nameWithoutSnap)
+		if err != nil {
+			return fmt.Errorf("internal error: cannot get data for %s: %v", nameWithoutSnap, err)
+		}
+		if delayStr == "" {
+			// unset
+			continue
+		}
+		delay, err := time.ParseDuration(delayStr)
+		if err != nil || delay < minRefreshStaging || delay > maxRefreshStaging {
+			return fmt.Errorf("refresh staging for snap %q must be a duration between %v and %v, not %q", snapName, minRefreshStaging, maxRefreshStaging, delayStr)
+		}
+	}
+	return nil
+}
+
+func validateRefreshSnapTimers(tr RunTransaction) error {
+	for _, name := range tr.Changes() {
+		if !isRefreshSnapTimerChange(name) {
+			continue
+		}
+
+		// core.refresh.snap-timer.<snap>
+		tokens := strings.SplitN(name, ".", 4)
+		if len(tokens) < 4 || tokens[3] == "" {
+			return fmt.Errorf("snap name must be specified for %q", name)
+		}
+		snapName := tokens[3]
+		if err := naming.ValidateInstance(snapName); err != nil {
+			return fmt.Errorf("cannot set refresh timer for snap %q: %v", snapName, err)
+		}
+
+		nameWithoutSnap := strings.SplitN(name, ".", 2)[1]
+		timerStr, err := coreCfg(tr, nameWithoutSnap)
+		if err != nil {
+			return fmt.Errorf("internal error: cannot get data for %s: %v", nameWithoutSnap, err)
+		}
+		if timerStr == "" {
+			// unset
+			continue
+		}
+		if _, err := timeutil.ParseSchedule(timerStr); err != nil {
+			return fmt.Errorf("cannot use refresh timer for snap %q: %v", snapName, err)
+		}
+	}
+	return nil
+}
+
+// isRefreshGroupChange returns whether the option defines a refresh group,
+// i.e. refresh.group-timer.<group> or refresh.group-snaps.<group>.
+func isRefreshGroupChange(opt string) bool {
+	return strings.HasPrefix(opt, "core.refresh.group-timer.") || strings.HasPrefix(opt, "core.refresh.group-snaps.")
+}
+
+var validRefreshGroup = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func validateRefreshGroups(tr RunTransaction) error {
+	for _, name := range tr.Changes() {
+		if !isRefreshGroupChange(name) {
+			continue
+		}
+
+		// core.refresh.group-{timer,snaps}.<group>
+		tokens := strings.SplitN(name, ".", 4)
+		if len(tokens) < 4 || tokens[3] == "" {
+			return fmt.Errorf("group name must be specified for %q", name)
+		}
+		group := tokens[3]
+		if !validRefreshGroup.MatchString(group) {
+			return fmt.Errorf("invalid refresh group name: %q", group)
+		}
+
+		nameWithoutSnap := strings.SplitN(name, ".", 2)[1]
+		value, err := coreCfg(tr, nameWithoutSnap)
+		if err != nil {
+			return fmt.Errorf("internal error: cannot get data for %s: %v", nameWithoutSnap, err)
+		}
+		if value == "" {
+			// unset
+			continue
+		}
+		if tokens[2] == "group-timer" {
+			if _, err := timeutil.ParseSchedule(value); err != nil {
+				return fmt.Errorf("cannot use refresh timer for group %q: %v", group, err)
+			}
+			continue
+		}
+		for _, snapName := range strutil.CommaSeparatedList(value) {
+			if err := naming.ValidateInstance(snapName); err != nil {
+				return fmt.Errorf("cannot add snap %q to refresh group %q: %v", snapName, group, err)
+			}
+		}
+	}
+	return nil
 }
 
 func reportOrIgnoreInvalidManageRefreshes(tr RunTransaction, optName string) error {
@@ -99,6 +231,27 @@ func validateRefreshSchedule(tr RunTransaction) error {
 		return fmt.Errorf("refresh.metered value %q is invalid", refreshOnMeteredStr)
 	}
 
+	refreshBlackoutStr, err := coreCfg(tr, "refresh.blackout")
+	if err != nil {
+		return err
+	}
+	if refreshBlackoutStr != "" {
+		if _, err := timeutil.ParseDateRanges(refreshBlackoutStr); err != nil {
+			return fmt.Errorf("refresh.blackout cannot be parsed: %v", err)
+		}
+	}
+
+	if err := validateRefreshSnapTimers(tr); err != nil {
+		return err
+	}
+	if err := validateRefreshGroups(tr); err != nil {
+		return err
+	}
+
+	if err := validateRefreshStaging(tr); err != nil {
+		return err
+	}
+
 	// check (new) refresh.timer
 	refreshTimerStr, err := coreCfg(tr, "refresh.timer")
 	if err != nil {
@@ -252,3 +252,111 @@ func (s *refreshSuite) TestConfigureRefreshMaxInhibitionDays(c *C) {
 		}
 	}
 }
+
+func (s *refreshSuite) TestConfigureRefreshBlackoutHappy(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"refresh.blackout": "2026-12-20..2027-01-05,2027-03-01",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *refreshSuite) TestConfigureRefreshBlackoutInvalid(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"refresh.blackout": "2027-01-05..2026-12-20",
+		},
+	})
+	c.Assert(err, ErrorMatches, `refresh\.blackout cannot be parsed: cannot parse "2027-01-05..2026-12-20": range ends before it starts`)
+}
+
+func (s *refreshSuite) TestConfigureRefreshSnapTimerHappy(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"refresh.snap-timer.db":        "sun,02:00-04:00",
+			"refresh.snap-timer.other_foo": "",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *refreshSuite) TestConfigureRefreshSnapTimerInvalid(c *C) {
+	for _, t := range []struct {
+		changes map[string]any
+		err     string
+	}{
+		{map[string]any{"refresh.snap-timer.db": "invalid"}, `cannot use refresh timer for snap "db": cannot parse "invalid": "invalid" is not a valid weekday`},
+		{map[string]any{"refresh.snap-timer.Not-Valid": "sun"}, `cannot set refresh timer for snap "Not-Valid": invalid snap name: "Not-Valid"`},
+		{map[string]any{"refresh.snap-timer.": "sun"}, `snap name must be specified for "core.refresh.snap-timer."`},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state:   s.state,
+			changes: t.changes,
+		})
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.changes))
+	}
+}
+
+func (s *refreshSuite) TestConfigureRefreshStagingHappy(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"refresh.staging.db":      "72h",
+			"refresh.staging.foo_bar": "",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *refreshSuite) TestConfigureRefreshStagingInvalid(c *C) {
+	for _, t := range []struct {
+		changes map[string]any
+		err     string
+	}{
+		{map[string]any{"refresh.staging.db": "invalid"}, `refresh staging for snap "db" must be a duration between 1h0m0s and 720h0m0s, not "invalid"`},
+		{map[string]any{"refresh.staging.db": "10m"}, `refresh staging for snap "db" must be a duration between 1h0m0s and 720h0m0s, not "10m"`},
+		{map[string]any{"refresh.staging.db": "721h"}, `refresh staging for snap "db" must be a duration between 1h0m0s and 720h0m0s, not "721h"`},
+		{map[string]any{"refresh.staging.Not-Valid": "72h"}, `cannot set refresh staging for snap "Not-Valid": invalid snap name: "Not-Valid"`},
+		{map[string]any{"refresh.staging.": "72h"}, `snap name must be specified for "core.refresh.staging."`},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state:   s.state,
+			changes: t.changes,
+		})
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.changes))
+	}
+}
+
+func (s *refreshSuite) TestConfigureRefreshGroupHappy(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]any{
+			"refresh.group-timer.db":    "sun,02:00-04:00",
+			"refresh.group-snaps.db":    "postgresql,redis_foo",
+			"refresh.group-timer.other": "",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *refreshSuite) TestConfigureRefreshGroupInvalid(c *C) {
+	for _, t := range []struct {
+		changes map[string]any
+		err     string
+	}{
+		{map[string]any{"refresh.group-timer.db": "invalid"}, `cannot use refresh timer for group "db": cannot parse "invalid": "invalid" is not a valid weekday`},
+		{map[string]any{"refresh.group-snaps.db": "foo,Not-Valid"}, `cannot add snap "Not-Valid" to refresh group "db": invalid snap name: "Not-Valid"`},
+		{map[string]any{"refresh.group-timer.Not_Valid": "sun"}, `invalid refresh group name: "Not_Valid"`},
+		{map[string]any{"refresh.group-snaps.": "foo"}, `group name must be specified for "core.refresh.group-snaps."`},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state:   s.state,
+			changes: t.changes,
+		})
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.changes))
+	}
+}
@@ -48,6 +48,9 @@ func init() {
 	// resilience.vitality-hint
 	addWithStateHandler(validateVitalitySettings, handleVitalityConfiguration, nil)
 
+	// service-overrides.<snap>.<app>.*
+	addWithStateHandler(validateServiceOverrides, handleServiceOverridesConfiguration, nil)
+
 	// XXX: this should become a FSOnlyHandler. We need to
 	// add/implement Changes() to the ConfGetter interface
 	// store-certs.*
@@ -64,6 +67,9 @@ func init() {
 	// netplan.*
 	addWithStateHandler(validateNetplanSettings, handleNetplanConfiguration, coreOnly)
 
+	// system.firewall.{default-policy,allow}
+	addWithStateHandler(validateFirewallSettings, handleFirewallConfiguration, coreOnly)
+
 	// kernel.{,dangerous-}cmdline-append
 	addWithStateHandler(validateCmdlineAppend, handleCmdlineAppend, &flags{modeenvOnlyConfig: true})
 
@@ -173,10 +179,18 @@ func applyHandlers(dev sysconfig.Device, cfg RunTransaction, handlers []configHa
 			}
 		case strings.HasPrefix(k, "core."+customCertPrefix+"."):
 			// validated by validateCustomCertificateRequest
+		case isRefreshSnapTimerChange(k):
+			// validated by validateRefreshSnapTimers
+		case isRefreshGroupChange(k):
+			// validated by validateRefreshGroups
+		case isRefreshStagingChange(k):
+			// validated by validateRefreshStaging
 		case isNetplanChange(k):
 			if release.OnClassic {
 				return fmt.Errorf("cannot set netplan configuration on classic")
 			}
+		case isServiceOverridesChange(k):
+			// validated by validateServiceOverrides
 		case isInterfaceChange(k):
 			if err := validateInterfaceChange(k); err != nil {
 				return err
@@ -0,0 +1,297 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/servicestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/progress"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+	"github.com/snapcore/snapd/snap/quota"
+	"github.com/snapcore/snapd/timings"
+	"github.com/snapcore/snapd/wrappers"
+)
+
+const serviceOverridesOpt = "service-overrides"
+
+// serviceOverrideSettings is the allowlist of the service properties which
+// can be overridden with service-overrides.<snap>.<app>.<setting>.
+var serviceOverrideSettings = map[string]bool{
+	"environment":            true,
+	"restart-condition":      true,
+	"restart-delay":          true,
+	"start-timeout":          true,
+	"stop-timeout":           true,
+	"nice":                   true,
+	"io-scheduling-class":    true,
+	"io-scheduling-priority": true,
+	"memory-max":             true,
+	"after":                  true,
+}
+
+func isServiceOverridesChange(opt string) bool {
+	return opt == "core."+serviceOverridesOpt || strings.HasPrefix(opt, "core."+serviceOverridesOpt+".")
+}
+
+type serviceOverridesMap map[string]map[string]*wrappers.ServiceOverride
+
+func getServiceOverrides(tr RunTransaction, pristine bool) (serviceOverridesMap, error) {
+	var overrides serviceOverridesMap
+	var err error
+	if pristine {
+		err = tr.GetPristine("core", serviceOverridesOpt, &overrides)
+	} else {
+		err = tr.Get("core", serviceOverridesOpt, &overrides)
+	}
+	if err != nil && !config.IsNoOption(err) {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func validateServiceOverrides(tr RunTransaction) error {
+	// only the services touched by this transaction are validated, so that
+	// overrides of services which went away with a refresh do not block
+	// further configuration changes
+	touched := make(map[string]map[string]bool)
+	for _, name := range tr.Changes() {
+		if !isServiceOverridesChange(name) {
+			continue
+		}
+		// core.service-overrides.<snap>.<app>.<setting>
+		tokens := strings.SplitN(name, ".", 5)
+		if len(tokens) < 4 {
+			continue
+		}
+		if touched[tokens[2]] == nil {
+			touched[tokens[2]] = make(map[string]bool)
+		}
+		touched[tokens[2]][tokens[3]] = true
+	}
+	if len(touched) == 0 {
+		return nil
+	}
+
+	var raw map[string]map[string]map[string]json.RawMessage
+	if err := tr.Get("core", serviceOverridesOpt, &raw); err != nil && !config.IsNoOption(err) {
+		return fmt.Errorf("cannot set %q: %v", serviceOverridesOpt, err)
+	}
+
+	snapNames := make([]string, 0, len(touched))
+	for snapName := range touched {
+		snapNames = append(snapNames, snapName)
+	}
+	sort.Strings(snapNames)
+
+	for _, snapName := range snapNames {
+		if err := naming.ValidateInstance(snapName); err != nil {
+			return fmt.Errorf("cannot set %q: %v", serviceOverridesOpt, err)
+		}
+		info, err := installedSnapInfo(tr.State(), snapName)
+		if err != nil {
+			return err
+		}
+
+		appNames := make([]string, 0, len(touched[snapName]))
+		for appName := range touched[snapName] {
+			appNames = append(appNames, appName)
+		}
+		sort.Strings(appNames)
+
+		for _, appName := range appNames {
+			settings, ok := raw[snapName][appName]
+			if !ok {
+				// the overrides were removed
+				continue
+			}
+			opt := fmt.Sprintf("%s.%s.%s", serviceOverridesOpt, snapName, appName)
+			if err := naming.ValidateApp(appName); err != nil {
+				return fmt.Errorf("cannot set %q: %v", opt, err)
+			}
+			if info != nil {
+				if app := info.Apps[appName]; app == nil || !app.IsService() {
+					return fmt.Errorf("cannot set %q: snap %q has no service %q", opt, snapName, appName)
+				}
+			}
+			for setting := range settings {
+				if !serviceOverrideSettings[setting] {
+					return fmt.Errorf("cannot set %q: unsupported service override %q", opt, setting)
+				}
+			}
+
+			b, err := json.Marshal(settings)
+			if err != nil {
+				return err
+			}
+			var ov wrappers.ServiceOverride
+			if err := json.Unmarshal(b, &ov); err != nil {
+				return fmt.Errorf("cannot set %q: %v", opt, err)
+			}
+			if err := ov.Validate(); err != nil {
+				return fmt.Errorf("cannot set %q: %v", opt, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// installedSnapInfo returns the info of the current revision of the given
+// snap, or nil if the snap is not installed.
+func installedSnapInfo(st *state.State, instanceName string) (*snap.Info, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	var snapst snapstate.SnapState
+	err := snapstate.Get(st, instanceName, &snapst)
+	if errors.Is(err, state.ErrNoState) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snapst.CurrentInfo()
+}
+
+func handleServiceOverridesConfiguration(tr RunTransaction, opts *fsOnlyContext) error {
+	pristine, err := getServiceOverrides(tr, true)
+	if err != nil {
+		return err
+	}
+	overrides, err := getServiceOverrides(tr, false)
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+	for instanceName := range pristine {
+		if !reflect.DeepEqual(pristine[instanceName], overrides[instanceName]) {
+			changed = append(changed, instanceName)
+		}
+	}
+	for instanceName := range overrides {
+		if _, ok := pristine[instanceName]; !ok {
+			changed = append(changed, instanceName)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+
+	overridden, err := ensureOverriddenServices(tr.State(), changed, overrides)
+	if err != nil {
+		return err
+	}
+	if len(overridden) == 0 {
+		return nil
+	}
+
+	// the overrides take effect once the running services are restarted,
+	// which is done without holding the state lock
+	tm := timings.New(nil)
+	return wrappers.RestartServices(overridden, nil, nil, progress.Null, tm)
+}
+
+// ensureOverriddenServices writes the units of the services of the given
+// snaps with the given overrides, and returns the services whose overrides
+// changed.
+func ensureOverriddenServices(st *state.State, changed []string, overrides serviceOverridesMap) ([]*snap.AppInfo, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	var overridden []*snap.AppInfo
+	var grps map[string]*quota.Group
+	for _, instanceName := range changed {
+		var snapst snapstate.SnapState
+		err := snapstate.Get(st, instanceName, &snapst)
+		// not installed, the overrides will be applied when the snap gets
+		// installed
+		if errors.Is(err, state.ErrNoState) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// not active, the overrides will be applied when the snap becomes
+		// active
+		if !snapst.Active {
+			continue
+		}
+		info, err := snapst.CurrentInfo()
+		if err != nil {
+			return nil, err
+		}
+
+		if grps == nil {
+			// use a single cache of the quota groups for calculating the
+			// quota groups that services should be in
+			grps, err = servicestate.AllQuotas(st)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// TODO: use sysconfig.Device instead
+		deviceCtx, err := snapstate.DeviceCtx(st, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		ensureOpts := &wrappers.EnsureSnapServicesOptions{}
+		// we need the snapd snap mounted whenever in order for services to
+		// start for all services on UC18+
+		if !deviceCtx.Classic() && deviceCtx.Model().Base() != "" {
+			ensureOpts.RequireMountedSnapdSnap = true
+		}
+
+		snapSvcOpts, err := servicestate.SnapServiceOptions(st, info, grps)
+		if err != nil {
+			return nil, err
+		}
+		// use the overrides of this transaction rather than the committed
+		// ones returned by SnapServiceOptions
+		snapSvcOpts.ServiceOverrides = overrides[instanceName]
+
+		observeChange := func(app *snap.AppInfo, _ *quota.Group, unitType, name, old, new string) {
+			if unitType == "override" {
+				overridden = append(overridden, app)
+			}
+		}
+		m := map[*snap.Info]*wrappers.SnapServiceOptions{
+			info: snapSvcOpts,
+		}
+		if err := wrappers.EnsureSnapServices(m, ensureOpts, observeChange, progress.Null); err != nil {
+			return nil, err
+		}
+	}
+
+	return overridden, nil
+}
@@ -0,0 +1,243 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2020 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package configcore_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/asserts/assertstest"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type serviceOverridesSuite struct {
+	configcoreSuite
+}
+
+var _ = Suite(&serviceOverridesSuite{})
+
+func (s *serviceOverridesSuite) SetUpTest(c *C) {
+	s.configcoreSuite.SetUpTest(c)
+
+	uc18model := assertstest.FakeAssertion(map[string]any{
+		"type":         "model",
+		"authority-id": "canonical",
+		"series":       "16",
+		"brand-id":     "canonical",
+		"model":        "pc",
+		"gadget":       "pc",
+		"kernel":       "kernel",
+		"architecture": "amd64",
+		"base":         "core18",
+	}).(*asserts.Model)
+
+	s.AddCleanup(snapstatetest.MockDeviceModel(uc18model))
+}
+
+func (s *serviceOverridesSuite) mockSnap(c *C) {
+	si := &snap.SideInfo{RealName: "test-snap", Revision: snap.R(1)}
+	snaptest.MockSnap(c, mockSnapWithService+` bar:
+  command: bin/bar
+`, si)
+	s.state.Lock()
+	snapstate.Set(s.state, "test-snap", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  snap.R(1),
+		Active:   true,
+		SnapType: "app",
+	})
+	s.state.Unlock()
+}
+
+func (s *serviceOverridesSuite) runWith(c *C, values map[string]any) error {
+	s.state.Lock()
+	tr := config.NewTransaction(s.state)
+	s.state.Unlock()
+	for k, v := range values {
+		c.Assert(tr.Set("core", k, v), IsNil)
+	}
+	err := configcore.Run(classicDev, configcore.NewRunTransaction(tr, nil))
+	if err == nil {
+		s.state.Lock()
+		tr.Commit()
+		s.state.Unlock()
+	}
+	return err
+}
+
+func (s *serviceOverridesSuite) TestValidateUnhappy(c *C) {
+	s.mockSnap(c)
+
+	for _, tc := range []struct {
+		values map[string]any
+		err    string
+	}{
+		{map[string]any{"service-overrides.test-snap.foo.memory-high": "1G"}, `cannot set "service-overrides.test-snap.foo": unsupported service override "memory-high"`},
+		{map[string]any{"service-overrides.test-snap.foo.memory-max": "1GB"}, `cannot set "service-overrides.test-snap.foo": invalid memory size "1GB": must be a number of bytes with an optional K, M, G or T suffix, a percentage or infinity`},
+		{map[string]any{"service-overrides.test-snap.foo.memory-max": "-1"}, `cannot set "service-overrides.test-snap.foo": invalid memory size "-1": .*`},
+		{map[string]any{"service-overrides.test-snap.foo.memory-max": 1.5}, `cannot set "service-overrides.test-snap.foo": invalid memory size "1.5": .*`},
+		{map[string]any{"service-overrides.test-snap.foo.memory-max": "150%"}, `cannot set "service-overrides.test-snap.foo": invalid memory size "150%": percentage must not exceed 100%`},
+		{map[string]any{"service-overrides.test-snap.foo.memory-max": "99999999999999999999"}, `cannot set "service-overrides.test-snap.foo": invalid memory size "99999999999999999999": out of range`},
+		{map[string]any{"service-overrides.test-snap.foo.nice": 20}, `cannot set "service-overrides.test-snap.foo": invalid nice value 20: must be between -20 and 19`},
+		{map[string]any{"service-overrides.test-snap.foo.io-scheduling-class": "fast"}, `cannot set "service-overrides.test-snap.foo": invalid IO scheduling class "fast": .*`},
+		{map[string]any{"service-overrides.test-snap.foo.io-scheduling-priority": 8}, `cannot set "service-overrides.test-snap.foo": invalid IO scheduling priority 8: must be between 0 and 7`},
+		{map[string]any{"service-overrides.test-snap.foo.restart-condition": "sometimes"}, `cannot set "service-overrides.test-snap.foo": invalid restart condition "sometimes"`},
+		{map[string]any{"service-overrides.test-snap.foo.restart-delay": "soon"}, `cannot set "service-overrides.test-snap.foo": time: invalid duration "soon"`},
+		{map[string]any{"service-overrides.test-snap.foo.stop-timeout": "-1s"}, `cannot set "service-overrides.test-snap.foo": stop-timeout cannot be negative`},
+		{map[string]any{"service-overrides.test-snap.foo.environment": []any{"FOO"}}, `cannot set "service-overrides.test-snap.foo": invalid environment entry "FOO": expected NAME=value`},
+		{map[string]any{"service-overrides.test-snap.foo.environment": []any{"1FOO=bar"}}, `cannot set "service-overrides.test-snap.foo": invalid environment variable name "1FOO"`},
+		{map[string]any{"service-overrides.test-snap.foo.environment": []any{"FOO=a\nb"}}, `cannot set "service-overrides.test-snap.foo": invalid value of environment variable "FOO": cannot contain newlines`},
+		{map[string]any{"service-overrides.test-snap.foo.after": []any{"foo bar.service"}}, `cannot set "service-overrides.test-snap.foo": invalid unit name "foo bar.service"`},
+		{map[string]any{"service-overrides.test-snap.foo.nice": "high"}, `cannot set "service-overrides.test-snap.foo": json: cannot unmarshal string .*`},
+		{map[string]any{"service-overrides.test-snap.bar.nice": 1}, `cannot set "service-overrides.test-snap.bar": snap "test-snap" has no service "bar"`},
+		{map[string]any{"service-overrides.test-snap.baz.nice": 1}, `cannot set "service-overrides.test-snap.baz": snap "test-snap" has no service "baz"`},
+	} {
+		err := s.runWith(c, tc.values)
+		c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.values))
+	}
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
+
+func (s *serviceOverridesSuite) TestSnapNotInstalled(c *C) {
+	err := s.runWith(c, map[string]any{
+		"service-overrides.other-snap.svc.nice": 5,
+	})
+	c.Assert(err, IsNil)
+	// nothing to apply the overrides to yet
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
+
+func (s *serviceOverridesSuite) TestApplyAndRemove(c *C) {
+	s.mockSnap(c)
+
+	svcName := "snap.test-snap.foo.service"
+	s.systemctlOutput = func(args ...string) ([]byte, error) {
+		if args[0] == "stop" {
+			// the services are restarted without holding the state
+			// lock
+			unlocked := make(chan struct{})
+			go func() {
+				s.state.Lock()
+				s.state.Unlock()
+				close(unlocked)
+			}()
+			select {
+			case <-unlocked:
+			case <-time.After(5 * time.Second):
+				c.Errorf("state is locked while restarting services")
+			}
+		}
+		if args[0] == "show" && args[1] == "--property=ActiveState" {
+			// stopped as part of the restart
+			return []byte("ActiveState=inactive\n"), nil
+		}
+		if args[0] == "show" {
+			return []byte(fmt.Sprintf(`Type=simple
+Id=%[1]s
+Names=%[1]s
+ActiveState=active
+UnitFileState=enabled
+NeedDaemonReload=no
+`, args[len(args)-1])), nil
+		}
+		return nil, nil
+	}
+
+	err := s.runWith(c, map[string]any{
+		"service-overrides.test-snap.foo.environment":            []any{`GREETING=hello "world" 100%`},
+		"service-overrides.test-snap.foo.restart-condition":      "always",
+		"service-overrides.test-snap.foo.restart-delay":          "10s",
+		"service-overrides.test-snap.foo.start-timeout":          "1m",
+		"service-overrides.test-snap.foo.stop-timeout":           "5s",
+		"service-overrides.test-snap.foo.nice":                   -5,
+		"service-overrides.test-snap.foo.io-scheduling-class":    "idle",
+		"service-overrides.test-snap.foo.io-scheduling-priority": 7,
+		"service-overrides.test-snap.foo.memory-max":             "512M",
+		"service-overrides.test-snap.foo.after":                  []any{"network-online.target", "other.service"},
+	})
+	c.Assert(err, IsNil)
+
+	dropIn := filepath.Join(dirs.SnapServicesDir, svcName+".d", "50-snapd-override.conf")
+	c.Check(dropIn, testutil.FileEquals, `# Auto-generated, DO NOT EDIT
+[Unit]
+After=network-online.target other.service
+
+[Service]
+Environment="GREETING=hello \"world\" 100%%"
+Restart=always
+RestartSec=10s
+TimeoutStartSec=1m0s
+TimeoutStopSec=5s
+Nice=-5
+IOSchedulingClass=idle
+IOSchedulingPriority=7
+MemoryMax=512M
+`)
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"daemon-reload"},
+		{"show", "--property=Id,ActiveState,UnitFileState,Type,Names,NeedDaemonReload", svcName},
+		{"stop", svcName},
+		{"show", "--property=ActiveState", svcName},
+		{"start", svcName},
+	})
+
+	// setting the same overrides again is a no-op
+	s.systemctlArgs = nil
+	err = s.runWith(c, map[string]any{
+		"service-overrides.test-snap.foo.nice": -5,
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.systemctlArgs, HasLen, 0)
+
+	// the memory size may be a plain number of bytes
+	err = s.runWith(c, map[string]any{
+		"service-overrides.test-snap.foo.memory-max": 1073741824,
+	})
+	c.Assert(err, IsNil)
+	c.Check(dropIn, testutil.FileContains, "\nMemoryMax=1073741824\n")
+	s.systemctlArgs = nil
+
+	// removing the overrides removes the drop-in
+	err = s.runWith(c, map[string]any{
+		"service-overrides.test-snap.foo": nil,
+	})
+	c.Assert(err, IsNil)
+	c.Check(dropIn, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"daemon-reload"},
+		{"show", "--property=Id,ActiveState,UnitFileState,Type,Names,NeedDaemonReload", svcName},
+		{"stop", svcName},
+		{"show", "--property=ActiveState", svcName},
+		{"start", svcName},
+	})
+	// but the service unit stays in place
+	c.Check(filepath.Join(dirs.SnapServicesDir, svcName), testutil.FilePresent)
+}
@@ -254,9 +254,18 @@ func handleServiceConfiguration(dev sysconfig.Device, tr ConfGetter, opts *fsOnl
 		}
 	}
 	// configure ssh ports
-	if err := handleServiceConfigSSHListen(dev, tr, opts); err != nil {
+	listenChanged, err := handleServiceConfigSSHListen(dev, tr, opts)
+	if err != nil {
+		return err
+	}
+	// configure ssh policy
+	policyChanged, err := handleSSHPolicyConfiguration(dev, tr, opts)
+	if err != nil {
 		return err
 	}
+	if opts == nil && (listenChanged || policyChanged) {
+		return reloadSSHConfiguration()
+	}
 
 	return nil
 }
@@ -318,28 +327,29 @@ func validateServiceConfiguration(tr ConfGetter) error {
 	if err != nil {
 		return err
 	}
-	if output == "" {
-		return nil
-	}
-	if _, err := parseSSHListenCfg(output); err != nil {
-		return fmt.Errorf("cannot validate ssh configuration: %v", err)
+	if output != "" {
+		if _, err := parseSSHListenCfg(output); err != nil {
+			return fmt.Errorf("cannot validate ssh configuration: %v", err)
+		}
 	}
-
-	return nil
+	// validate the ssh policy settings
+	return validateSSHPolicySettings(tr)
 }
 
-func handleServiceConfigSSHListen(dev sysconfig.Device, tr ConfGetter, opts *fsOnlyContext) error {
+// handleServiceConfigSSHListen writes the ssh listen address configuration
+// and reports whether it changed.
+func handleServiceConfigSSHListen(dev sysconfig.Device, tr ConfGetter, opts *fsOnlyContext) (bool, error) {
 	// see if anything needs to happen
 	var pristineSSHListen, newSSHListen any
 
 	if err := tr.GetPristine("core", sshListenOpt, &pristineSSHListen); err != nil && !config.IsNoOption(err) {
-		return err
+		return false, err
 	}
 	if err := tr.Get("core", sshListenOpt, &newSSHListen); err != nil && !config.IsNoOption(err) {
-		return err
+		return false, err
 	}
 	if pristineSSHListen == newSSHListen {
-		return nil
+		return false, nil
 	}
 
 	// ssh.port config has changed, write new config
@@ -353,7 +363,7 @@ func handleServiceConfigSSHListen(dev sysconfig.Device, tr ConfGetter, opts *fsO
 	// would have to merge somehow the UC16 and UC18 config in a
 	// fsOnlyContext
 	if !dev.HasModeenv() {
-		return fmt.Errorf("cannot set ssh listen address configuration on systems older than UC20")
+		return false, fmt.Errorf("cannot set ssh listen address configuration on systems older than UC20")
 	}
 
 	name := "listen.conf"
@@ -361,13 +371,13 @@ func handleServiceConfigSSHListen(dev sysconfig.Device, tr ConfGetter, opts *fsO
 	if newSSHListen != nil && newSSHListen != "" {
 		listenAddrs, err := parseSSHListenCfg(fmt.Sprintf("%v", newSSHListen))
 		if err != nil {
-			return fmt.Errorf("cannot set ssh configuration: %v", err)
+			return false, fmt.Errorf("cannot set ssh configuration: %v", err)
 		}
 
 		var buf bytes.Buffer
 		for _, s := range listenAddrs {
 			if _, err := fmt.Fprintf(&buf, "ListenAddress %v\n", s); err != nil {
-				return fmt.Errorf("cannot create ssh option buffer: %v", err)
+				return false, fmt.Errorf("cannot create ssh option buffer: %v", err)
 			}
 		}
 
@@ -378,36 +388,11 @@ func handleServiceConfigSSHListen(dev sysconfig.Device, tr ConfGetter, opts *fsO
 	}
 	dir := filepath.Join(root, "/etc/ssh/sshd_config.d/")
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
+		return false, err
 	}
-	_, _, err := osutil.EnsureDirState(dir, name, dirContent)
-	if err != nil {
-		return err
-	}
-
-	if opts == nil {
-		sysd := systemd.New(systemd.SystemMode, &sysdLogger{})
-		// From 22.10 sshd now uses socket based activation. This changes how to reload ssh configuration
-		// Discussion here: https://discourse.ubuntu.com/t/sshd-now-uses-socket-based-activation-ubuntu-22-10-and-later/30189/9
-		// Interestingly, the ssh.socket unit has always been in UC, but it was
-		// disabled in UC16-22 and instead ssh.service was enabled by default.
-		// On UC24 the socket unit is enabled, so we check for that to know the
-		// unit we need to act on. Note that as the unit has a condition on the
-		// sshd_not_to_be_run file, the status is always enabled even if the
-		// unit is not active, so we can rely on that for the check.
-		if enabled, err := sysd.IsEnabled("ssh.socket"); err == nil && enabled {
-			if err := sysd.DaemonReload(); err != nil {
-				return err
-			}
-			if err := sysd.Restart([]string{"ssh.socket"}); err != nil {
-				return err
-			}
-		} else {
-			if err := sysd.ReloadOrRestart([]string{"ssh.service"}); err != nil {
-				return err
-			}
-		}
+	if _, _, err := osutil.EnsureDirState(dir, name, dirContent); err != nil {
+		return false, err
 	}
 
-	return nil
+	return true, nil
 }
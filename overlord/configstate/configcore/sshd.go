@@ -0,0 +1,329 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/strutil"
+	"github.com/snapcore/snapd/sysconfig"
+	"github.com/snapcore/snapd/systemd"
+)
+
+const (
+	optionSSHPasswordAuthentication = "service.ssh.password-authentication"
+	optionSSHPermitRootLogin        = "service.ssh.permit-root-login"
+	optionSSHPort                   = "service.ssh.port"
+	optionSSHCiphers                = "service.ssh.ciphers"
+	optionSSHBanner                 = "service.ssh.banner"
+
+	// sshd uses the first value it reads for most keywords and the
+	// drop-ins are read in lexical order, make sure ours wins over
+	// e.g. 50-cloud-init.conf
+	sshPolicyCfgFile = "00-snapd-policy.conf"
+	sshBannerFile    = "snapd-banner"
+
+	sshBannerMaxSize = 4096
+)
+
+var (
+	sshPermitRootLoginValues = []string{"yes", "no", "prohibit-password", "forced-commands-only"}
+
+	// ciphers supported by the OpenSSH server shipped in the base
+	// snaps, see "ssh -Q cipher"
+	sshSupportedCiphers = []string{
+		"3des-cbc",
+		"aes128-cbc",
+		"aes192-cbc",
+		"aes256-cbc",
+		"aes128-ctr",
+		"aes192-ctr",
+		"aes256-ctr",
+		"aes128-gcm@openssh.com",
+		"aes256-gcm@openssh.com",
+		"chacha20-poly1305@openssh.com",
+	}
+)
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core."+optionSSHPasswordAuthentication] = true
+	supportedConfigurations["core."+optionSSHPermitRootLogin] = true
+	supportedConfigurations["core."+optionSSHPort] = true
+	supportedConfigurations["core."+optionSSHCiphers] = true
+	supportedConfigurations["core."+optionSSHBanner] = true
+}
+
+type sshPolicyConfig struct {
+	passwordAuthentication string
+	permitRootLogin        string
+	port                   int
+	ciphers                []string
+	banner                 string
+}
+
+func (cfg *sshPolicyConfig) empty() bool {
+	return cfg.passwordAuthentication == "" && cfg.permitRootLogin == "" &&
+		cfg.port == 0 && len(cfg.ciphers) == 0 && cfg.banner == ""
+}
+
+func sshPolicySettings(tr ConfGetter) (*sshPolicyConfig, error) {
+	var cfg sshPolicyConfig
+
+	if err := validateBoolFlag(tr, optionSSHPasswordAuthentication); err != nil {
+		return nil, err
+	}
+	passwordAuth, err := coreCfg(tr, optionSSHPasswordAuthentication)
+	if err != nil {
+		return nil, err
+	}
+	switch passwordAuth {
+	case "true":
+		cfg.passwordAuthentication = "yes"
+	case "false":
+		cfg.passwordAuthentication = "no"
+	}
+
+	if cfg.permitRootLogin, err = coreCfg(tr, optionSSHPermitRootLogin); err != nil {
+		return nil, err
+	}
+	if cfg.permitRootLogin != "" && !strutil.ListContains(sshPermitRootLoginValues, cfg.permitRootLogin) {
+		return nil, fmt.Errorf("cannot set %q: value must be one of %s", optionSSHPermitRootLogin, strings.Join(sshPermitRootLoginValues, ", "))
+	}
+
+	portStr, err := coreCfg(tr, optionSSHPort)
+	if err != nil {
+		return nil, err
+	}
+	if portStr != "" {
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot set %q: port must be a number: %v", optionSSHPort, err)
+		}
+		if port < 1 || port > 65535 {
+			return nil, fmt.Errorf("cannot set %q: port %v must be in the range 1-65535", optionSSHPort, port)
+		}
+		cfg.port = port
+	}
+
+	ciphers, err := coreCfg(tr, optionSSHCiphers)
+	if err != nil {
+		return nil, err
+	}
+	if ciphers != "" {
+		for _, cipher := range strings.Split(ciphers, ",") {
+			if !strutil.ListContains(sshSupportedCiphers, cipher) {
+				return nil, fmt.Errorf("cannot set %q: unsupported cipher %q", optionSSHCiphers, cipher)
+			}
+			cfg.ciphers = append(cfg.ciphers, cipher)
+		}
+	}
+
+	if cfg.banner, err = coreCfg(tr, optionSSHBanner); err != nil {
+		return nil, err
+	}
+	if len(cfg.banner) > sshBannerMaxSize {
+		return nil, fmt.Errorf("cannot set %q: banner must not be longer than %d bytes", optionSSHBanner, sshBannerMaxSize)
+	}
+	if !utf8.ValidString(cfg.banner) || strings.ContainsRune(cfg.banner, 0) {
+		return nil, fmt.Errorf("cannot set %q: banner must be valid UTF-8 text", optionSSHBanner)
+	}
+	if cfg.banner != "" && !strings.HasSuffix(cfg.banner, "\n") {
+		cfg.banner += "\n"
+	}
+
+	return &cfg, nil
+}
+
+func validateSSHPolicySettings(tr ConfGetter) error {
+	_, err := sshPolicySettings(tr)
+	return err
+}
+
+// sshConfigFile remembers the content of a file so that it can be restored
+// if the new ssh configuration is rejected by sshd.
+type sshConfigFile struct {
+	path    string
+	content []byte
+	mode    os.FileMode
+}
+
+func saveSSHConfigFile(path string) (*sshConfigFile, error) {
+	saved := &sshConfigFile{path: path}
+	fi, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if saved.content, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+	saved.mode = fi.Mode().Perm()
+	return saved, nil
+}
+
+func (f *sshConfigFile) restore() error {
+	if f.content == nil {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return osutil.AtomicWriteFile(f.path, f.content, f.mode, 0)
+}
+
+// handleSSHPolicyConfiguration writes the ssh policy options into a
+// sshd_config.d drop-in and reports whether the configuration of sshd
+// changed. On a running system the new configuration is checked with
+// "sshd -t" and the previous one is restored if sshd rejects it.
+func handleSSHPolicyConfiguration(dev sysconfig.Device, tr ConfGetter, opts *fsOnlyContext) (bool, error) {
+	cfg, err := sshPolicySettings(tr)
+	if err != nil {
+		return false, err
+	}
+
+	root := dirs.GlobalRootDir
+	if opts != nil {
+		root = opts.RootDir
+	}
+	sshDir := filepath.Join(root, "/etc/ssh")
+	cfgDir := filepath.Join(sshDir, "sshd_config.d")
+	bannerPath := filepath.Join(sshDir, sshBannerFile)
+
+	if !cfg.empty() {
+		// see handleServiceConfigSSHListen
+		if !dev.HasModeenv() {
+			return false, fmt.Errorf("cannot set ssh configuration on systems older than UC20")
+		}
+		if err := os.MkdirAll(cfgDir, 0755); err != nil {
+			return false, err
+		}
+	} else if !osutil.IsDirectory(cfgDir) {
+		// nothing was ever written
+		return false, nil
+	}
+
+	var saved []*sshConfigFile
+	for _, path := range []string{filepath.Join(cfgDir, sshPolicyCfgFile), bannerPath} {
+		f, err := saveSSHConfigFile(path)
+		if err != nil {
+			return false, err
+		}
+		saved = append(saved, f)
+	}
+
+	var buf strings.Builder
+	if cfg.passwordAuthentication != "" {
+		fmt.Fprintf(&buf, "PasswordAuthentication %s\n", cfg.passwordAuthentication)
+	}
+	if cfg.permitRootLogin != "" {
+		fmt.Fprintf(&buf, "PermitRootLogin %s\n", cfg.permitRootLogin)
+	}
+	if cfg.port != 0 {
+		fmt.Fprintf(&buf, "Port %d\n", cfg.port)
+	}
+	if len(cfg.ciphers) != 0 {
+		fmt.Fprintf(&buf, "Ciphers %s\n", strings.Join(cfg.ciphers, ","))
+	}
+	if cfg.banner != "" {
+		// the path as seen by sshd on the running system
+		fmt.Fprintf(&buf, "Banner %s\n", filepath.Join("/etc/ssh", sshBannerFile))
+	}
+
+	bannerChanged := false
+	if cfg.banner != "" {
+		err := osutil.EnsureFileState(bannerPath, &osutil.MemoryFileState{
+			Content: []byte(cfg.banner),
+			Mode:    0644,
+		})
+		switch {
+		case err == nil:
+			bannerChanged = true
+		case err != osutil.ErrSameState:
+			return false, err
+		}
+	} else if osutil.FileExists(bannerPath) {
+		if err := os.Remove(bannerPath); err != nil {
+			return false, err
+		}
+		bannerChanged = true
+	}
+
+	dirContent := map[string]osutil.FileState{}
+	if buf.Len() != 0 {
+		dirContent[sshPolicyCfgFile] = &osutil.MemoryFileState{
+			Content: []byte(buf.String()),
+			Mode:    0600,
+		}
+	}
+	changed, removed, err := osutil.EnsureDirState(cfgDir, sshPolicyCfgFile, dirContent)
+	if err != nil {
+		return false, err
+	}
+	policyChanged := bannerChanged || len(changed) != 0 || len(removed) != 0
+
+	// When the configuration is applied from gadget defaults sshd only
+	// runs with it on the next boot, it cannot be checked here as the
+	// includes in sshd_config refer to the running system.
+	if opts == nil && policyChanged {
+		if output, err := exec.Command("sshd", "-t").CombinedOutput(); err != nil {
+			for _, f := range saved {
+				if err := f.restore(); err != nil {
+					logger.Noticef("cannot restore %q: %v", f.path, err)
+				}
+			}
+			return false, fmt.Errorf("cannot apply ssh configuration: %v", osutil.OutputErr(output, err))
+		}
+	}
+
+	return policyChanged, nil
+}
+
+// reloadSSHConfiguration makes the running sshd pick up a new configuration.
+func reloadSSHConfiguration() error {
+	sysd := systemd.New(systemd.SystemMode, &sysdLogger{})
+	// From 22.10 sshd now uses socket based activation. This changes how to reload ssh configuration
+	// Discussion here: https://discourse.ubuntu.com/t/sshd-now-uses-socket-based-activation-ubuntu-22-10-and-later/30189/9
+	// Interestingly, the ssh.socket unit has always been in UC, but it was
+	// disabled in UC16-22 and instead ssh.service was enabled by default.
+	// On UC24 the socket unit is enabled, so we check for that to know the
+	// unit we need to act on. Note that as the unit has a condition on the
+	// sshd_not_to_be_run file, the status is always enabled even if the
+	// unit is not active, so we can rely on that for the check.
+	if enabled, err := sysd.IsEnabled("ssh.socket"); err == nil && enabled {
+		if err := sysd.DaemonReload(); err != nil {
+			return err
+		}
+		return sysd.Restart([]string{"ssh.socket"})
+	}
+	return sysd.ReloadOrRestart([]string{"ssh.service"})
+}
@@ -0,0 +1,211 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type sshdSuite struct {
+	configcoreSuite
+
+	mockSshd *testutil.MockCmd
+
+	sshPolicyCfgPath string
+	sshBannerPath    string
+}
+
+var _ = Suite(&sshdSuite{})
+
+func (s *sshdSuite) SetUpTest(c *C) {
+	s.configcoreSuite.SetUpTest(c)
+
+	s.mockSshd = testutil.MockCommand(c, "sshd", "")
+	s.AddCleanup(s.mockSshd.Restore)
+
+	s.sshPolicyCfgPath = filepath.Join(dirs.GlobalRootDir, "/etc/ssh/sshd_config.d/00-snapd-policy.conf")
+	s.sshBannerPath = filepath.Join(dirs.GlobalRootDir, "/etc/ssh/snapd-banner")
+}
+
+func (s *sshdSuite) reloadCalls() [][]string {
+	return [][]string{
+		{"is-enabled", "ssh.socket"},
+		{"daemon-reload"},
+		{"stop", "ssh.socket"},
+		{"show", "--property=ActiveState", "ssh.socket"},
+		{"start", "ssh.socket"},
+	}
+}
+
+func (s *sshdSuite) TestConfigureSSHPolicyUnset(c *C) {
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf:  map[string]any{},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.sshPolicyCfgPath, testutil.FileAbsent)
+	c.Check(s.sshBannerPath, testutil.FileAbsent)
+	c.Check(s.mockSshd.Calls(), HasLen, 0)
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
+
+func (s *sshdSuite) TestConfigureSSHPolicy(c *C) {
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"service.ssh.password-authentication": false,
+			"service.ssh.permit-root-login":       "prohibit-password",
+			"service.ssh.port":                    2222,
+			"service.ssh.ciphers":                 "aes256-gcm@openssh.com,chacha20-poly1305@openssh.com",
+			"service.ssh.banner":                  "Authorized access only",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.sshPolicyCfgPath, testutil.FileEquals, `PasswordAuthentication no
+PermitRootLogin prohibit-password
+Port 2222
+Ciphers aes256-gcm@openssh.com,chacha20-poly1305@openssh.com
+Banner /etc/ssh/snapd-banner
+`)
+	c.Check(s.sshBannerPath, testutil.FileEquals, "Authorized access only\n")
+	c.Check(s.mockSshd.Calls(), DeepEquals, [][]string{{"sshd", "-t"}})
+	c.Check(s.systemctlArgs, DeepEquals, s.reloadCalls())
+
+	// nothing happens when applying the same configuration again
+	s.mockSshd.ForgetCalls()
+	s.systemctlArgs = nil
+	err = configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"service.ssh.password-authentication": false,
+			"service.ssh.permit-root-login":       "prohibit-password",
+			"service.ssh.port":                    2222,
+			"service.ssh.ciphers":                 "aes256-gcm@openssh.com,chacha20-poly1305@openssh.com",
+			"service.ssh.banner":                  "Authorized access only",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.mockSshd.Calls(), HasLen, 0)
+	c.Check(s.systemctlArgs, HasLen, 0)
+
+	// unsetting everything removes the drop-in and the banner
+	err = configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf:  map[string]any{},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.sshPolicyCfgPath, testutil.FileAbsent)
+	c.Check(s.sshBannerPath, testutil.FileAbsent)
+	c.Check(s.mockSshd.Calls(), DeepEquals, [][]string{{"sshd", "-t"}})
+	c.Check(s.systemctlArgs, DeepEquals, s.reloadCalls())
+}
+
+func (s *sshdSuite) TestConfigureSSHPolicySshdRejects(c *C) {
+	c.Assert(os.MkdirAll(filepath.Dir(s.sshPolicyCfgPath), 0755), IsNil)
+	c.Assert(os.WriteFile(s.sshPolicyCfgPath, []byte("PasswordAuthentication no\n"), 0600), IsNil)
+
+	mockSshd := testutil.MockCommand(c, "sshd", `echo "Bad configuration"; exit 255`)
+	defer mockSshd.Restore()
+
+	err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"service.ssh.password-authentication": false,
+			"service.ssh.banner":                  "hello",
+		},
+	})
+	c.Assert(err, ErrorMatches, `cannot apply ssh configuration: Bad configuration`)
+	c.Check(mockSshd.Calls(), DeepEquals, [][]string{{"sshd", "-t"}})
+
+	// the previous configuration is restored and sshd is not reloaded
+	c.Check(s.sshPolicyCfgPath, testutil.FileEquals, "PasswordAuthentication no\n")
+	c.Check(s.sshBannerPath, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
+
+func (s *sshdSuite) TestConfigureSSHPolicyInvalid(c *C) {
+	for _, tc := range []struct {
+		option string
+		value  any
+		errStr string
+	}{
+		{"service.ssh.password-authentication", "maybe", `service.ssh.password-authentication can only be set to 'true' or 'false'`},
+		{"service.ssh.permit-root-login", "without-password", `cannot set "service.ssh.permit-root-login": value must be one of yes, no, prohibit-password, forced-commands-only`},
+		{"service.ssh.port", "ssh", `cannot set "service.ssh.port": port must be a number: .*`},
+		{"service.ssh.port", 0, `cannot set "service.ssh.port": port 0 must be in the range 1-65535`},
+		{"service.ssh.port", 65536, `cannot set "service.ssh.port": port 65536 must be in the range 1-65535`},
+		{"service.ssh.ciphers", "aes256-ctr,rc4", `cannot set "service.ssh.ciphers": unsupported cipher "rc4"`},
+		{"service.ssh.ciphers", "aes256-ctr,", `cannot set "service.ssh.ciphers": unsupported cipher ""`},
+		{"service.ssh.ciphers", "+aes256-ctr", `cannot set "service.ssh.ciphers": unsupported cipher "\+aes256-ctr"`},
+		{"service.ssh.banner", strings.Repeat("x", 4097), `cannot set "service.ssh.banner": banner must not be longer than 4096 bytes`},
+		{"service.ssh.banner", "a\x00b", `cannot set "service.ssh.banner": banner must be valid UTF-8 text`},
+		{"service.ssh.banner", "\xff", `cannot set "service.ssh.banner": banner must be valid UTF-8 text`},
+	} {
+		err := configcore.FilesystemOnlyRun(core20Dev, &mockConf{
+			state: s.state,
+			conf: map[string]any{
+				tc.option: tc.value,
+			},
+		})
+		c.Check(err, ErrorMatches, tc.errStr, Commentf("%s=%v", tc.option, tc.value))
+	}
+
+	c.Check(s.sshPolicyCfgPath, testutil.FileAbsent)
+	c.Check(s.mockSshd.Calls(), HasLen, 0)
+}
+
+func (s *sshdSuite) TestConfigureSSHPolicyOlderThanUC20(c *C) {
+	err := configcore.FilesystemOnlyRun(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"service.ssh.password-authentication": false,
+		},
+	})
+	c.Assert(err, ErrorMatches, "cannot set ssh configuration on systems older than UC20")
+}
+
+func (s *sshdSuite) TestFilesystemOnlyApplySSHPolicy(c *C) {
+	tmpDir := c.MkDir()
+	conf := configcore.PlainCoreConfig(map[string]any{
+		"service.ssh.password-authentication": "false",
+		"service.ssh.permit-root-login":       "no",
+		"service.ssh.banner":                  "Authorized access only\n",
+	})
+	c.Assert(configcore.FilesystemOnlyApply(core20Dev, tmpDir, conf), IsNil)
+
+	c.Check(filepath.Join(tmpDir, "/etc/ssh/sshd_config.d/00-snapd-policy.conf"), testutil.FileEquals, `PasswordAuthentication no
+PermitRootLogin no
+Banner /etc/ssh/snapd-banner
+`)
+	c.Check(filepath.Join(tmpDir, "/etc/ssh/snapd-banner"), testutil.FileEquals, "Authorized access only\n")
+	// sshd is only run with the configuration on the next boot
+	c.Check(s.mockSshd.Calls(), HasLen, 0)
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
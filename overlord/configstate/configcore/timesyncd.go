@@ -0,0 +1,191 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/sysconfig"
+	"github.com/snapcore/snapd/systemd"
+)
+
+const (
+	optionTimeNTPServers         = "system.time.ntp-servers"
+	optionTimeFallbackNTPServers = "system.time.fallback-ntp-servers"
+	optionTimePollIntervalMin    = "system.time.poll-interval-min"
+	optionTimePollIntervalMax    = "system.time.poll-interval-max"
+
+	timesyncdCfgSubdir = "timesyncd.conf.d"
+	timesyncdCfgFile   = "ubuntu-core.conf"
+
+	// systemd-timesyncd refuses poll intervals shorter than this
+	timesyncdMinPollInterval = 16 * time.Second
+)
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core."+optionTimeNTPServers] = true
+	supportedConfigurations["core."+optionTimeFallbackNTPServers] = true
+	supportedConfigurations["core."+optionTimePollIntervalMin] = true
+	supportedConfigurations["core."+optionTimePollIntervalMax] = true
+}
+
+// ntpServers splits a list of NTP servers separated by commas or
+// whitespace.
+func ntpServers(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+func validateNTPServers(tr ConfGetter, option string) ([]string, error) {
+	value, err := coreCfg(tr, option)
+	if err != nil {
+		return nil, err
+	}
+	servers := ntpServers(value)
+	for _, server := range servers {
+		if net.ParseIP(server) == nil && !validHostnameRegexp(server) {
+			return nil, fmt.Errorf("cannot set %q: invalid NTP server %q", option, server)
+		}
+	}
+	return servers, nil
+}
+
+func validatePollInterval(tr ConfGetter, option string) (time.Duration, error) {
+	value, err := coreCfg(tr, option)
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("cannot set %q: %v", option, err)
+	}
+	if interval < timesyncdMinPollInterval {
+		return 0, fmt.Errorf("cannot set %q: poll interval must be at least %v", option, timesyncdMinPollInterval)
+	}
+	if interval%time.Second != 0 {
+		return 0, fmt.Errorf("cannot set %q: poll interval must be a whole number of seconds", option)
+	}
+	return interval, nil
+}
+
+type timesyncdConfig struct {
+	ntpServers         []string
+	fallbackNTPServers []string
+	pollIntervalMin    time.Duration
+	pollIntervalMax    time.Duration
+}
+
+func timesyncdSettings(tr ConfGetter) (*timesyncdConfig, error) {
+	var cfg timesyncdConfig
+	var err error
+	if cfg.ntpServers, err = validateNTPServers(tr, optionTimeNTPServers); err != nil {
+		return nil, err
+	}
+	if cfg.fallbackNTPServers, err = validateNTPServers(tr, optionTimeFallbackNTPServers); err != nil {
+		return nil, err
+	}
+	if cfg.pollIntervalMin, err = validatePollInterval(tr, optionTimePollIntervalMin); err != nil {
+		return nil, err
+	}
+	if cfg.pollIntervalMax, err = validatePollInterval(tr, optionTimePollIntervalMax); err != nil {
+		return nil, err
+	}
+	if cfg.pollIntervalMin != 0 && cfg.pollIntervalMax != 0 && cfg.pollIntervalMax < cfg.pollIntervalMin {
+		return nil, fmt.Errorf("cannot set %q: maximum poll interval cannot be shorter than the minimum of %v", optionTimePollIntervalMax, cfg.pollIntervalMin)
+	}
+	return &cfg, nil
+}
+
+func validateTimesyncdSettings(tr ConfGetter) error {
+	_, err := timesyncdSettings(tr)
+	return err
+}
+
+func handleTimesyncdConfiguration(_ sysconfig.Device, tr ConfGetter, opts *fsOnlyContext) error {
+	cfg, err := timesyncdSettings(tr)
+	if err != nil {
+		return err
+	}
+
+	var buf strings.Builder
+	if len(cfg.ntpServers) != 0 {
+		fmt.Fprintf(&buf, "NTP=%s\n", strings.Join(cfg.ntpServers, " "))
+	}
+	if len(cfg.fallbackNTPServers) != 0 {
+		fmt.Fprintf(&buf, "FallbackNTP=%s\n", strings.Join(cfg.fallbackNTPServers, " "))
+	}
+	if cfg.pollIntervalMin != 0 {
+		fmt.Fprintf(&buf, "PollIntervalMinSec=%d\n", cfg.pollIntervalMin/time.Second)
+	}
+	if cfg.pollIntervalMax != 0 {
+		fmt.Fprintf(&buf, "PollIntervalMaxSec=%d\n", cfg.pollIntervalMax/time.Second)
+	}
+
+	var timesyncdCfgDir string
+	if opts == nil {
+		// runtime system
+		timesyncdCfgDir = dirs.SnapSystemdDir
+	} else {
+		timesyncdCfgDir = dirs.SnapSystemdDirUnder(opts.RootDir)
+	}
+	timesyncdCfgDir = filepath.Join(timesyncdCfgDir, timesyncdCfgSubdir)
+
+	// Ensure content of configuration file (path is
+	// /etc/systemd/timesyncd.conf.d/ubuntu-core.conf), the file is removed
+	// when none of the options are set
+	dirContent := map[string]osutil.FileState{}
+	if buf.Len() != 0 {
+		if err := os.MkdirAll(timesyncdCfgDir, 0755); err != nil {
+			return err
+		}
+		dirContent[timesyncdCfgFile] = &osutil.MemoryFileState{
+			Content: []byte("[Time]\n" + buf.String()),
+			Mode:    0644,
+		}
+	}
+	changed, removed, err := osutil.EnsureDirState(timesyncdCfgDir, timesyncdCfgFile, dirContent)
+	if err != nil {
+		return err
+	}
+
+	if opts == nil && (len(changed) != 0 || len(removed) != 0) {
+		// timesyncd may be disabled in favour of another NTP client, only
+		// restart it if it is running
+		sysd := systemd.NewUnderRoot(dirs.GlobalRootDir, systemd.SystemMode, &sysdLogger{})
+		if err := sysd.TryRestart([]string{"systemd-timesyncd.service"}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type timesyncdSuite struct {
+	configcoreSuite
+
+	timesyncdCfgPath string
+}
+
+var _ = Suite(&timesyncdSuite{})
+
+func (s *timesyncdSuite) SetUpTest(c *C) {
+	s.configcoreSuite.SetUpTest(c)
+
+	s.timesyncdCfgPath = filepath.Join(dirs.SnapSystemdDir, "timesyncd.conf.d", "ubuntu-core.conf")
+}
+
+func (s *timesyncdSuite) restartCalls() [][]string {
+	return [][]string{
+		{"try-restart", "systemd-timesyncd.service"},
+	}
+}
+
+func (s *timesyncdSuite) TestConfigureTimesyncdUnset(c *C) {
+	err := configcore.FilesystemOnlyRun(coreDev, &mockConf{
+		state: s.state,
+		conf:  map[string]any{},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.timesyncdCfgPath, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
+
+func (s *timesyncdSuite) TestConfigureTimesyncd(c *C) {
+	err := configcore.FilesystemOnlyRun(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.time.ntp-servers":          "ntp1.example.com, 10.0.0.1",
+			"system.time.fallback-ntp-servers": "ntp.ubuntu.com fd00::1",
+			"system.time.poll-interval-min":    "1m",
+			"system.time.poll-interval-max":    "2h",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.timesyncdCfgPath, testutil.FileEquals, `[Time]
+NTP=ntp1.example.com 10.0.0.1
+FallbackNTP=ntp.ubuntu.com fd00::1
+PollIntervalMinSec=60
+PollIntervalMaxSec=7200
+`)
+	c.Check(s.systemctlArgs, DeepEquals, s.restartCalls())
+
+	// applying the same configuration again does not restart timesyncd
+	s.systemctlArgs = nil
+	err = configcore.FilesystemOnlyRun(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.time.ntp-servers":          "ntp1.example.com 10.0.0.1",
+			"system.time.fallback-ntp-servers": "ntp.ubuntu.com,fd00::1",
+			"system.time.poll-interval-min":    "60s",
+			"system.time.poll-interval-max":    "120m",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.systemctlArgs, HasLen, 0)
+
+	// unsetting all options removes the drop-in
+	err = configcore.FilesystemOnlyRun(coreDev, &mockConf{
+		state: s.state,
+		conf:  map[string]any{},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.timesyncdCfgPath, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, DeepEquals, s.restartCalls())
+}
+
+func (s *timesyncdSuite) TestConfigureTimesyncdInactive(c *C) {
+	// the mocked systemctl reports timesyncd as inactive
+	err := configcore.FilesystemOnlyRun(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.time.ntp-servers": "ntp1.example.com",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.timesyncdCfgPath, testutil.FileEquals, "[Time]\nNTP=ntp1.example.com\n")
+	// an inactive timesyncd is not started
+	c.Check(s.systemctlArgs, DeepEquals, s.restartCalls())
+	for _, args := range s.systemctlArgs {
+		c.Check(args[0], Not(Equals), "start")
+	}
+}
+
+func (s *timesyncdSuite) TestConfigureTimesyncdInvalid(c *C) {
+	for _, t := range []struct {
+		conf map[string]any
+		err  string
+	}{
+		{map[string]any{"system.time.ntp-servers": "ntp.example.com bad_server"}, `cannot set "system.time.ntp-servers": invalid NTP server "bad_server"`},
+		{map[string]any{"system.time.fallback-ntp-servers": "-ntp"}, `cannot set "system.time.fallback-ntp-servers": invalid NTP server "-ntp"`},
+		{map[string]any{"system.time.poll-interval-min": "32"}, `cannot set "system.time.poll-interval-min": time: missing unit in duration "32"`},
+		{map[string]any{"system.time.poll-interval-min": "15s"}, `cannot set "system.time.poll-interval-min": poll interval must be at least 16s`},
+		{map[string]any{"system.time.poll-interval-max": "20.5s"}, `cannot set "system.time.poll-interval-max": poll interval must be a whole number of seconds`},
+		{map[string]any{"system.time.poll-interval-min": "2m", "system.time.poll-interval-max": "1m"}, `cannot set "system.time.poll-interval-max": maximum poll interval cannot be shorter than the minimum of 2m0s`},
+	} {
+		err := configcore.FilesystemOnlyRun(coreDev, &mockConf{
+			state: s.state,
+			conf:  t.conf,
+		})
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.conf))
+	}
+	c.Check(s.timesyncdCfgPath, testutil.FileAbsent)
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
+
+func (s *timesyncdSuite) TestFilesystemOnlyApply(c *C) {
+	conf := configcore.PlainCoreConfig(map[string]any{
+		"system.time.ntp-servers": "ntp.example.com",
+	})
+	tmpDir := c.MkDir()
+	c.Assert(configcore.FilesystemOnlyApply(coreDev, tmpDir, conf), IsNil)
+
+	c.Check(filepath.Join(tmpDir, "/etc/systemd/timesyncd.conf.d/ubuntu-core.conf"), testutil.FileEquals, "[Time]\nNTP=ntp.example.com\n")
+	// nothing is restarted when preparing the filesystem
+	c.Check(s.systemctlArgs, HasLen, 0)
+	c.Check(s.timesyncdCfgPath, testutil.FileAbsent)
+}
+
+func (s *timesyncdSuite) TestConfigureTimesyncdClassic(c *C) {
+	err := configcore.FilesystemOnlyRun(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]any{
+			"system.time.ntp-servers": "ntp.example.com",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.timesyncdCfgPath, testutil.FileAbsent)
+}
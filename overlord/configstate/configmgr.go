@@ -23,6 +23,8 @@ package configstate
 import (
 	"regexp"
 
+	"gopkg.in/tomb.v2"
+
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/configstate/configcore"
@@ -42,7 +44,7 @@ func MockConfigcoreRun(f func(sysconfig.Device, configcore.RunTransaction) error
 	}
 }
 
-func Init(st *state.State, hookManager *hookstate.HookManager) error {
+func Init(st *state.State, hookManager *hookstate.HookManager, runner *state.TaskRunner) error {
 	delayedCrossMgrInit()
 
 	// Retrieve home directories
@@ -90,5 +92,16 @@ func Init(st *state.State, hookManager *hookstate.HookManager) error {
 		return configcoreRun(dev, tr)
 	})
 
+	// Firewall rules which are not confirmed in time are rolled back by
+	// a task scheduled at the deadline
+	runner.AddHandler("revert-firewall", doRevertFirewall, nil)
+
 	return nil
 }
+
+func doRevertFirewall(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+	return configcore.RevertUnconfirmedFirewall(t)
+}
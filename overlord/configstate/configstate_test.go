@@ -300,7 +300,7 @@ func (s *configcoreHijackSuite) SetUpTest(c *C) {
 	})
 	s.AddCleanup(r)
 
-	err = configstate.Init(s.state, hookMgr)
+	err = configstate.Init(s.state, hookMgr, s.o.TaskRunner())
 	c.Assert(err, IsNil)
 	s.o.AddManager(s.o.TaskRunner())
 
@@ -319,7 +319,7 @@ func (s *configcoreHijackSuite) TestConfigMngrInitHomeDirs(c *C) {
 	c.Assert(t.Set("core", "homedirs", "/home,/home/department,/users,/users/seniors"), IsNil)
 	t.Commit()
 	s.state.Unlock()
-	err = configstate.Init(s.state, hookMgr)
+	err = configstate.Init(s.state, hookMgr, s.o.TaskRunner())
 	c.Assert(err, IsNil)
 	snapHomeDirs := []string{"/home", "/home/department", "/users", "/users/seniors"}
 	c.Check(dirs.SnapHomeDirs(), DeepEquals, snapHomeDirs)
@@ -338,7 +338,7 @@ func (s *configcoreHijackSuite) TestConfigMngrInitPrunesGraduatedExperimentalFea
 	t.Commit()
 	s.state.Unlock()
 
-	c.Assert(configstate.Init(s.state, hookMgr), IsNil)
+	c.Assert(configstate.Init(s.state, hookMgr, s.o.TaskRunner()), IsNil)
 
 	s.state.Lock()
 	defer s.state.Unlock()
@@ -417,6 +417,25 @@ func (s *configcoreHijackSuite) TestHijack(c *C) {
 	c.Check(configcoreRan, Equals, true)
 }
 
+func (s *configcoreHijackSuite) TestRevertFirewallHandler(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// no firewall rules are waiting for confirmation
+	chg := s.state.NewChange("revert-firewall", "...")
+	t := s.state.NewTask("revert-firewall", "...")
+	chg.AddTask(t)
+
+	s.state.Unlock()
+	err := s.o.Settle(5 * time.Second)
+	s.state.Lock()
+	c.Assert(err, IsNil)
+
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+	c.Assert(t.Log(), HasLen, 1)
+	c.Check(t.Log()[0], Matches, ".* firewall configuration was confirmed")
+}
+
 type miscSuite struct{}
 
 func (s *miscSuite) TestRemappingFuncs(c *C) {
@@ -119,6 +119,39 @@ func (s *configureHandlerSuite) TestBeforeInitializesTransaction(c *C) {
 	c.Check(value, Equals, "bar")
 }
 
+func (s *configureHandlerSuite) TestBeforeValidatesConfigSchema(c *C) {
+	info := snaptest.MockSnap(c, "name: test-snap\nversion: 1\nhooks:\n  configure:\n", &snap.SideInfo{Revision: snap.R(1)})
+	err := os.WriteFile(filepath.Join(info.MountDir(), "meta", "config-schema.json"), []byte(`{
+	"schema": {
+		"port": {"type": "int", "min": 1, "default": 8080}
+	}
+}`), 0644)
+	c.Assert(err, IsNil)
+
+	s.state.Lock()
+	snapstate.Set(s.state, "test-snap", &snapstate.SnapState{
+		Active: true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "test-snap", Revision: snap.R(1)},
+		}),
+		Current:  snap.R(1),
+		SnapType: "app",
+	})
+	s.state.Unlock()
+
+	s.context.Lock()
+	s.context.Set("patch", map[string]any{"port": 0})
+	s.context.Unlock()
+
+	err = s.handler.Before()
+	c.Check(err, ErrorMatches, `invalid configuration for snap "test-snap": cannot accept element in "port": 0 is less than the allowed minimum 1`)
+
+	s.context.Lock()
+	s.context.Set("patch", map[string]any{"port": 22})
+	s.context.Unlock()
+	c.Check(s.handler.Before(), IsNil)
+}
+
 func makeModel(override map[string]any) *asserts.Model {
 	model := map[string]any{
 		"type":         "model",
@@ -457,7 +490,7 @@ func (s *configcoreHandlerSuite) SetUpTest(c *C) {
 	})
 	s.AddCleanup(r)
 
-	err = configstate.Init(s.state, hookMgr)
+	err = configstate.Init(s.state, hookMgr, s.o.TaskRunner())
 
 	c.Assert(err, IsNil)
 	s.o.AddManager(s.o.TaskRunner())
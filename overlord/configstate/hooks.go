@@ -94,7 +94,8 @@ func (h *configureHandler) Before() error {
 		return err
 	}
 
-	return nil
+	// catch invalid configuration before the hook sees it
+	return ValidateSnapConfig(tr, instanceName)
 }
 
 // Done is called by the HookManager after the configure hook has exited
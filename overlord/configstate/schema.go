@@ -0,0 +1,69 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configstate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// SnapConfigSchema returns the configuration schema shipped by the given
+// installed snap, or nil if the snap does not ship one or is not installed.
+func SnapConfigSchema(st *state.State, instanceName string) (*config.Schema, error) {
+	// the "core" snap/pseudonym is configured internally
+	if instanceName == "core" {
+		return nil, nil
+	}
+	info, err := snapstate.CurrentInfo(st, instanceName)
+	if err != nil {
+		var notInstalled *snap.NotInstalledError
+		if errors.As(err, &notInstalled) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return config.ReadSchema(info)
+}
+
+// ValidateSnapConfig checks the configuration of the given snap in the
+// transaction against the configuration schema shipped by the snap, if any.
+func ValidateSnapConfig(tr *config.Transaction, instanceName string) error {
+	schema, err := SnapConfigSchema(tr.State(), instanceName)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+
+	var cfg map[string]any
+	if err := tr.Get(instanceName, "", &cfg); err != nil && !config.IsNoOption(err) {
+		return err
+	}
+	if err := schema.Validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration for snap %q: %v", instanceName, err)
+	}
+	return nil
+}
@@ -139,8 +139,9 @@ func findSerial(st *state.State, device *auth.DeviceState) (*asserts.Serial, err
 
 // Serial returns the device's serial assertion.
 //
-// XXX: This is currently only used by clusterstate. Consumers should be
-// reworked so that this function isn't needed.
+// XXX: This is currently only used by clusterstate and by snapstate to
+// derive refresh staging delays. Consumers should be reworked so that this
+// function isn't needed.
 func Serial(st *state.State) (*asserts.Serial, error) {
 	return findSerial(st, nil)
 }
@@ -297,6 +298,7 @@ func delayedCrossMgrInit() {
 	snapstate.CanAutoRefresh = canAutoRefresh
 	snapstate.IsOnMeteredConnection = netutil.IsOnMeteredConnection
 	snapstate.DeviceCtx = DeviceCtx
+	snapstate.DeviceSerial = Serial
 	snapstate.RemodelingChange = RemodelingChange
 	snapstate.CreateSeedRefreshTasks = SeedRefreshTasks
 	snapstate.PendingSeedRefreshTasks = PendingSeedRefreshTasks
@@ -1318,6 +1320,16 @@ func remodelTasks(ctx context.Context, st *state.State, current, new *asserts.Mo
 		recoverySetupTaskID = createRecoveryTasks.Tasks()[0].ID()
 	}
 
+	// Establish the connections declared by the new model that are
+	// still missing once all the snaps are in place.
+	if len(new.Connections()) > 0 {
+		connectModel := st.NewTask("connect-model-interfaces", i18n.G("Connect interfaces declared by the model"))
+		for _, tsPrev := range tss {
+			connectModel.WaitAll(tsPrev)
+		}
+		tss = append(tss, state.NewTaskSet(connectModel))
+	}
+
 	// Set the new model assertion - this *must* be the last thing done
 	// by the change.
 	setModel := st.NewTask("set-model", i18n.G("Set new model assertion"))
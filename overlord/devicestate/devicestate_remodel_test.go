@@ -994,6 +994,53 @@ func (s *deviceMgrRemodelSuite) TestRemodelLessRequiredSnaps(c *C) {
 	c.Assert(tSetModel.Summary(), Equals, "Set new model assertion")
 }
 
+func (s *deviceMgrRemodelSuite) TestRemodelModelConnections(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	s.state.Set("seeded", true)
+	s.state.Set("refresh-privacy-key", "some-privacy-key")
+
+	snapstatetest.InstallEssentialSnaps(c, s.state, "core18", nil, nil)
+
+	// set a model assertion
+	s.makeModelAssertionInState(c, "canonical", "pc-model", map[string]any{
+		"architecture":   "amd64",
+		"kernel":         "pc-kernel",
+		"gadget":         "pc",
+		"base":           "core18",
+		"required-snaps": []any{"some-required-snap"},
+	})
+	s.makeSerialAssertionInState(c, "canonical", "pc-model", "1234")
+	devicestatetest.SetDevice(s.state, &auth.DeviceState{
+		Brand:  "canonical",
+		Model:  "pc-model",
+		Serial: "1234",
+	})
+
+	new := s.brands.Model("canonical", "pc-model", map[string]any{
+		"architecture": "amd64",
+		"kernel":       "pc-kernel",
+		"gadget":       "pc",
+		"base":         "core18",
+		"format":       "1",
+		"connections": []any{
+			map[string]any{"plug": "pc:camera", "slot": "system:camera"},
+		},
+		"revision": "1",
+	})
+	chg, err := devicestate.Remodel(s.state, new, devicestate.RemodelOptions{})
+	c.Assert(err, IsNil)
+
+	tl := chg.Tasks()
+	c.Assert(tl, HasLen, 2)
+	tConnectModel := tl[0]
+	tSetModel := tl[1]
+	c.Assert(tConnectModel.Kind(), Equals, "connect-model-interfaces")
+	c.Assert(tConnectModel.Summary(), Equals, "Connect interfaces declared by the model")
+	c.Assert(tSetModel.Kind(), Equals, "set-model")
+	c.Assert(tSetModel.WaitTasks(), DeepEquals, []*state.Task{tConnectModel})
+}
+
 type freshSessionStore struct {
 	storetest.Store
 
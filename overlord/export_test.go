@@ -94,7 +94,7 @@ func MockStoreNew(new func(*store.Config, store.DeviceAndAuthContext) *store.Sto
 	}
 }
 
-func MockConfigstateInit(new func(*state.State, *hookstate.HookManager) error) (restore func()) {
+func MockConfigstateInit(new func(*state.State, *hookstate.HookManager, *state.TaskRunner) error) (restore func()) {
 	configstateInit = new
 	return func() {
 		configstateInit = configstate.Init
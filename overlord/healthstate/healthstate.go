@@ -165,8 +165,12 @@ func (h *healthHandler) appendHealth(health *HealthState) error {
 }
 
 func appendHealth(ctx *hookstate.Context, health *HealthState) error {
-	st := ctx.State()
+	return Set(ctx.State(), ctx.InstanceName(), health)
+}
 
+// Set saves the given health of a snap in snapd's state.
+// Must be called with the state lock held.
+func Set(st *state.State, snap string, health *HealthState) error {
 	var hs map[string]*HealthState
 	if err := st.Get("health", &hs); err != nil {
 		if !errors.Is(err, state.ErrNoState) {
@@ -174,7 +178,7 @@ func appendHealth(ctx *hookstate.Context, health *HealthState) error {
 		}
 		hs = map[string]*HealthState{}
 	}
-	hs[ctx.InstanceName()] = health
+	hs[snap] = health
 	st.Set("health", hs)
 
 	return nil
@@ -253,3 +253,21 @@ func (s *healthSuite) TestSetFromHookContextEmpty(c *check.C) {
 	// no health in the context -> no health in state
 	c.Check(s.state.Get("health", &hs), testutil.ErrorIs, state.ErrNoState)
 }
+
+func (s *healthSuite) TestSet(c *check.C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	err := healthstate.Set(s.state, "foo", &healthstate.HealthState{Status: healthstate.ErrorStatus, Code: "foo-code"})
+	c.Assert(err, check.IsNil)
+	err = healthstate.Set(s.state, "bar", &healthstate.HealthState{Status: healthstate.OkayStatus})
+	c.Assert(err, check.IsNil)
+
+	health, err := healthstate.Get(s.state, "foo")
+	c.Assert(err, check.IsNil)
+	c.Check(health, check.DeepEquals, &healthstate.HealthState{Status: healthstate.ErrorStatus, Code: "foo-code"})
+
+	hs, err := healthstate.All(s.state)
+	c.Check(err, check.IsNil)
+	c.Check(hs, check.HasLen, 2)
+}
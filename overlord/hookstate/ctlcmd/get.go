@@ -261,11 +261,22 @@ func (c *getCommand) getConfigSetting(context *hookstate.Context) error {
 
 	context.Lock()
 	transaction := configstate.ContextTransaction(context)
+	schema, err := configstate.SnapConfigSchema(context.State(), context.InstanceName())
 	context.Unlock()
+	if err != nil {
+		return err
+	}
 
 	return c.printValues(func(key string) (any, bool, error) {
 		var value any
 		err := transaction.Get(c.context().InstanceName(), key, &value)
+		if schema != nil && (err == nil || config.IsNoOption(err)) {
+			// options which are not set take the defaults declared
+			// in the configuration schema of the snap
+			if withDefaults := schema.WithDefaults(key, value); withDefaults != nil {
+				return withDefaults, true, nil
+			}
+		}
 		if err == nil {
 			return value, true, nil
 		}
@@ -1447,3 +1447,30 @@ func (s *confdbSuite) TestConfdbNegativeWaitFor(c *C) {
 		c.Check(stderr, IsNil, cmt)
 	}
 }
+
+func (s *getSuite) TestGetConfigSchemaDefaults(c *C) {
+	defer dirs.SetRootDir("")
+	mockInstalledSnapWithConfigSchema(c, s.mockContext.State(), "test-snap", testConfigSchema)
+
+	s.mockContext.State().Lock()
+	tr := config.NewTransaction(s.mockContext.State())
+	tr.Set("test-snap", "initial-key", nil)
+	tr.Set("test-snap", "port", 22)
+	tr.Commit()
+	s.mockContext.State().Unlock()
+
+	for _, t := range []struct {
+		args, stdout string
+	}{
+		{"get port", "22\n"},
+		{"get db.host", "localhost\n"},
+		{"get mode", "\n"},
+		{"get -d db", "{\n\t\"db\": {\n\t\t\"host\": \"localhost\"\n\t}\n}\n"},
+		{"get port db.host", "{\n\t\"db.host\": \"localhost\",\n\t\"port\": 22\n}\n"},
+	} {
+		stdout, stderr, _, err := ctlcmd.Run(s.mockContext, strings.Fields(t.args), 0, nil)
+		c.Assert(err, IsNil, Commentf("%s", t.args))
+		c.Check(string(stdout), Equals, t.stdout, Commentf("%s", t.args))
+		c.Check(string(stderr), Equals, "")
+	}
+}
@@ -0,0 +1,160 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ctlcmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var (
+	shortNoticeHelp = i18n.G("Record a custom notice")
+	longNoticeHelp  = i18n.G(`
+The notice command records an occurrence of a custom notice on behalf of the
+snap, so that other snaps and desktop agents can be told about events such as
+"model downloaded" or "firmware staged".
+
+The notice key is namespaced by the snap: a notice recorded with the key
+"model-downloaded" by the snap "my-snap" is visible as a "snap-custom" notice
+with the key "my-snap/model-downloaded".
+
+Optional data can be attached to the occurrence as key=value pairs:
+
+    $ snapctl notice model-downloaded model=large size=1G
+
+A snap can have up to 64 notice keys at a time, and the data of an
+occurrence is limited to 4096 bytes.
+
+If --repeat-after is given, the notice is only reported as repeated if the
+given duration has passed since it was last repeated.
+
+Snaps connected to the snap-notices-observe interface can read and wait on
+these notices through the /v2/notices API.
+
+The command prints the ID of the recorded notice. It must be run as root.
+`)
+)
+
+func init() {
+	addCommand("notice", shortNoticeHelp, longNoticeHelp, func() command { return &noticeCommand{} })
+}
+
+type noticeCommand struct {
+	baseCommand
+
+	RepeatAfter time.Duration `long:"repeat-after" value-name:"<duration>" description:"Only report the notice as repeated if this much time has passed since it was last repeated"`
+	Positional  struct {
+		Key  string   `positional-arg-name:"<key>" required:"yes" description:"Key of the notice, without the snap name prefix"`
+		Data []string `positional-arg-name:"<key=value>" description:"Data for this occurrence of the notice"`
+	} `positional-args:"yes"`
+}
+
+const (
+	// maxSnapNoticeKeys is the number of distinct notice keys a snap may
+	// have at any time; notices expire after a week when not repeated.
+	maxSnapNoticeKeys = 64
+	// maxNoticeDataSize is the size in bytes the keys and values of the
+	// data of a notice occurrence may take.
+	maxNoticeDataSize = 4096
+)
+
+var (
+	validNoticeKey     = regexp.MustCompile(`^[a-z0-9]+(?:[.-][a-z0-9]+)*(?:/[a-z0-9]+(?:[.-][a-z0-9]+)*)*$`).MatchString
+	validNoticeDataKey = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`).MatchString
+)
+
+func (c *noticeCommand) Execute([]string) error {
+	if !validNoticeKey(c.Positional.Key) {
+		return fmt.Errorf("invalid notice key %q (key must consist of lowercase ASCII letters and numbers, separated by single dots, dashes or slashes)", c.Positional.Key)
+	}
+	if c.RepeatAfter < 0 {
+		return fmt.Errorf("repeat-after duration cannot be negative")
+	}
+
+	var data map[string]string
+	dataSize := 0
+	for _, kv := range c.Positional.Data {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("invalid notice data %q: must be of the form <key>=<value>", kv)
+		}
+		if !validNoticeDataKey(k) {
+			return fmt.Errorf("invalid notice data key %q", k)
+		}
+		dataSize += len(k) + len(v)
+		if dataSize > maxNoticeDataSize {
+			return fmt.Errorf("cannot record notice: data must be %d bytes or less", maxNoticeDataSize)
+		}
+		if data == nil {
+			data = make(map[string]string, len(c.Positional.Data))
+		}
+		data[k] = v
+	}
+
+	ctx, err := c.ensureContext()
+	if err != nil {
+		return err
+	}
+	ctx.Lock()
+	defer ctx.Unlock()
+
+	key := ctx.InstanceName() + "/" + c.Positional.Key
+	if err := state.ValidateNotice(state.SnapCustomNotice, key, nil); err != nil {
+		return err
+	}
+	if err := checkSnapNoticeKeys(ctx.State(), ctx.InstanceName(), key); err != nil {
+		return err
+	}
+
+	id, err := ctx.State().AddNotice(nil, state.SnapCustomNotice, key, &state.AddNoticeOptions{
+		Data:        data,
+		RepeatAfter: c.RepeatAfter,
+	})
+	if err != nil {
+		return err
+	}
+	c.printf("%s\n", id)
+	return nil
+}
+
+// checkSnapNoticeKeys checks that the snap can record a notice with the
+// given key without exceeding the number of keys it may have.
+func checkSnapNoticeKeys(st *state.State, instanceName, key string) error {
+	keys := 0
+	for _, n := range st.Notices(&state.NoticeFilter{Types: []state.NoticeType{state.SnapCustomNotice}}) {
+		if !strings.HasPrefix(n.Key(), instanceName+"/") {
+			continue
+		}
+		if n.Key() == key {
+			// repeating an existing notice
+			return nil
+		}
+		keys++
+	}
+	if keys >= maxSnapNoticeKeys {
+		return fmt.Errorf("cannot record notice: snap %q already has %d notice keys", instanceName, maxSnapNoticeKeys)
+	}
+	return nil
+}
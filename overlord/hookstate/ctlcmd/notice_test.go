@@ -0,0 +1,155 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ctlcmd_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/hookstate"
+	"github.com/snapcore/snapd/overlord/hookstate/ctlcmd"
+	"github.com/snapcore/snapd/overlord/hookstate/hooktest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+type noticeSuite struct {
+	state       *state.State
+	mockContext *hookstate.Context
+}
+
+var _ = Suite(&noticeSuite{})
+
+func (s *noticeSuite) SetUpTest(c *C) {
+	s.state = state.New(nil)
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	task := s.state.NewTask("test-task", "my test task")
+	setup := &hookstate.HookSetup{Snap: "test-snap", Revision: snap.R(1), Hook: "configure"}
+	ctx, err := hookstate.NewContext(task, s.state, setup, hooktest.NewMockHandler(), "")
+	c.Assert(err, IsNil)
+	s.mockContext = ctx
+}
+
+func (s *noticeSuite) notices(c *C) []*state.Notice {
+	s.state.Lock()
+	defer s.state.Unlock()
+	return s.state.Notices(&state.NoticeFilter{Types: []state.NoticeType{state.SnapCustomNotice}})
+}
+
+func (s *noticeSuite) TestNotice(c *C) {
+	stdout, stderr, _, err := ctlcmd.Run(s.mockContext, []string{"notice", "model-downloaded", "model=large", "path=/a=b"}, 0, nil)
+	c.Assert(err, IsNil)
+	c.Check(string(stderr), Equals, "")
+
+	notices := s.notices(c)
+	c.Assert(notices, HasLen, 1)
+	n := notices[0]
+	c.Check(string(stdout), Equals, n.ID()+"\n")
+	c.Check(n.Key(), Equals, "test-snap/model-downloaded")
+	_, hasUserID := n.UserID()
+	c.Check(hasUserID, Equals, false)
+	c.Check(n.LastData(), DeepEquals, map[string]string{"model": "large", "path": "/a=b"})
+
+	// repeating updates the same notice
+	stdout, _, _, err = ctlcmd.Run(s.mockContext, []string{"notice", "model-downloaded"}, 0, nil)
+	c.Assert(err, IsNil)
+	c.Check(string(stdout), Equals, n.ID()+"\n")
+	notices = s.notices(c)
+	c.Assert(notices, HasLen, 1)
+	c.Check(notices[0].LastData(), HasLen, 0)
+}
+
+func (s *noticeSuite) TestNoticeRepeatAfter(c *C) {
+	_, _, _, err := ctlcmd.Run(s.mockContext, []string{"notice", "firmware.staged", "version=1"}, 0, nil)
+	c.Assert(err, IsNil)
+	first := s.notices(c)[0].LastRepeated()
+
+	_, _, _, err = ctlcmd.Run(s.mockContext, []string{"notice", "--repeat-after=1h", "firmware.staged", "version=2"}, 0, nil)
+	c.Assert(err, IsNil)
+
+	notices := s.notices(c)
+	c.Assert(notices, HasLen, 1)
+	// not repeated, but the data of the latest occurrence is kept
+	c.Check(notices[0].LastRepeated().Equal(first), Equals, true)
+	c.Check(notices[0].LastData(), DeepEquals, map[string]string{"version": "2"})
+	buf, err := json.Marshal(notices[0])
+	c.Assert(err, IsNil)
+	c.Check(string(buf), Matches, `.*"repeat-after":"1h0m0s".*`)
+}
+
+func (s *noticeSuite) TestNoticeErrors(c *C) {
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"notice"}, "the required argument `<key>` was not provided"},
+		{[]string{"notice", "Bad"}, `invalid notice key "Bad" \(key must .*\)`},
+		{[]string{"notice", "a//b"}, `invalid notice key "a//b" .*`},
+		{[]string{"notice", "-foo"}, `unknown flag .*`},
+		{[]string{"notice", "foo", "bar"}, `invalid notice data "bar": must be of the form <key>=<value>`},
+		{[]string{"notice", "foo", "Bar=1"}, `invalid notice data key "Bar"`},
+		{[]string{"notice", "--repeat-after=-1s", "foo"}, `repeat-after duration cannot be negative`},
+		{[]string{"notice", "--repeat-after=soon", "foo"}, `invalid argument for flag .*`},
+		{[]string{"notice", strings.Repeat("x", 250)}, `cannot add snap-custom notice with invalid key: key must be 256 bytes or less`},
+		{[]string{"notice", "foo", "a=" + strings.Repeat("x", 4000), "b=" + strings.Repeat("x", 100)}, `cannot record notice: data must be 4096 bytes or less`},
+	} {
+		_, _, _, err := ctlcmd.Run(s.mockContext, t.args, 0, nil)
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.args))
+	}
+	c.Check(s.notices(c), HasLen, 0)
+}
+
+func (s *noticeSuite) TestNoticeTooManyKeys(c *C) {
+	s.state.Lock()
+	// notices of other snaps do not count
+	for i := 0; i < 10; i++ {
+		_, err := s.state.AddNotice(nil, state.SnapCustomNotice, fmt.Sprintf("other-snap/key%d", i), nil)
+		c.Assert(err, IsNil)
+	}
+	for i := 0; i < 64; i++ {
+		_, err := s.state.AddNotice(nil, state.SnapCustomNotice, fmt.Sprintf("test-snap/key%d", i), nil)
+		c.Assert(err, IsNil)
+	}
+	s.state.Unlock()
+
+	_, _, _, err := ctlcmd.Run(s.mockContext, []string{"notice", "one-too-many"}, 0, nil)
+	c.Check(err, ErrorMatches, `cannot record notice: snap "test-snap" already has 64 notice keys`)
+
+	// existing notices can still be repeated
+	_, _, _, err = ctlcmd.Run(s.mockContext, []string{"notice", "key3", "a=b"}, 0, nil)
+	c.Assert(err, IsNil)
+	c.Check(s.notices(c), HasLen, 74)
+}
+
+func (s *noticeSuite) TestNoticeNoContext(c *C) {
+	_, _, _, err := ctlcmd.Run(nil, []string{"notice", "foo"}, 0, nil)
+	c.Check(err, ErrorMatches, `cannot invoke snapctl operation commands \(here "notice"\) from outside of a snap`)
+}
+
+func (s *noticeSuite) TestNoticeNonRoot(c *C) {
+	_, _, _, err := ctlcmd.Run(s.mockContext, []string{"notice", "model-downloaded"}, 1000, nil)
+	c.Check(err, ErrorMatches, `cannot use "notice" with uid 1000, try with sudo`)
+	c.Check(s.notices(c), HasLen, 0)
+}
@@ -163,12 +163,39 @@ func (s *setCommand) setConfigSetting(context *hookstate.Context) error {
 		return err
 	}
 
-	for _, key := range confKeys {
-		if err := tr.Set(s.context().InstanceName(), key, confValues[key]); err != nil {
+	return setSnapConfig(tr, context.InstanceName(), confKeys, confValues)
+}
+
+// setSnapConfig sets the given options of the snap in the transaction. The
+// transaction is left unchanged if the resulting configuration is not
+// accepted by the configuration schema shipped by the snap.
+func setSnapConfig(tr *config.Transaction, instanceName string, keys []string, values map[string]any) error {
+	previous := make(map[string]any, len(keys))
+	for _, key := range keys {
+		var value any
+		if err := tr.Get(instanceName, key, &value); err != nil && !config.IsNoOption(err) {
+			return err
+		}
+		previous[key] = value
+	}
+
+	restore := func() {
+		for i := len(keys) - 1; i >= 0; i-- {
+			tr.Set(instanceName, keys[i], previous[keys[i]])
+		}
+	}
+
+	for _, key := range keys {
+		if err := tr.Set(instanceName, key, values[key]); err != nil {
+			restore()
 			return err
 		}
 	}
 
+	if err := configstate.ValidateSnapConfig(tr, instanceName); err != nil {
+		restore()
+		return err
+	}
 	return nil
 }
 
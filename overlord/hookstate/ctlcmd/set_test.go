@@ -22,19 +22,25 @@ package ctlcmd_test
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/confdb"
+	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/hookstate"
 	"github.com/snapcore/snapd/overlord/hookstate/ctlcmd"
 	"github.com/snapcore/snapd/overlord/hookstate/hooktest"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
 )
 
 type setSuite struct {
@@ -530,3 +536,68 @@ func (s *confdbSuite) TestConfdbModifyHooks(c *C) {
 		c.Check(stderr, IsNil)
 	}
 }
+
+const testConfigSchema = `{
+	"schema": {
+		"port": {"type": "int", "min": 1, "default": 8080},
+		"mode": {"type": "string", "choices": ["fast", "safe"]},
+		"db": {
+			"schema": {
+				"host": {"type": "string", "default": "localhost"}
+			},
+			"required": ["host"]
+		}
+	}
+}`
+
+func mockInstalledSnapWithConfigSchema(c *C, st *state.State, instanceName, configSchema string) {
+	dirs.SetRootDir(c.MkDir())
+	si := &snap.SideInfo{RealName: instanceName, Revision: snap.R(1)}
+	info := snaptest.MockSnap(c, fmt.Sprintf("name: %s\nversion: 1\n", instanceName), si)
+	err := os.WriteFile(filepath.Join(info.MountDir(), "meta", "config-schema.json"), []byte(configSchema), 0644)
+	c.Assert(err, IsNil)
+
+	st.Lock()
+	defer st.Unlock()
+	snapstate.Set(st, instanceName, &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  si.Revision,
+		SnapType: "app",
+	})
+}
+
+func (s *setSuite) TestSetConfigSchema(c *C) {
+	defer dirs.SetRootDir("")
+	mockInstalledSnapWithConfigSchema(c, s.mockContext.State(), "test-snap", testConfigSchema)
+
+	_, _, _, err := ctlcmd.Run(s.mockContext, []string{"set", "port=22", "mode=fast", "db.host=remote"}, 0, nil)
+	c.Assert(err, IsNil)
+	// required options may be unset when the schema declares a default
+	_, _, _, err = ctlcmd.Run(s.mockContext, []string{"unset", "db.host"}, 0, nil)
+	c.Assert(err, IsNil)
+
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"set", "mode=safe", "port=0"}, `invalid configuration for snap "test-snap": cannot accept element in "port": 0 is less than the allowed minimum 1`},
+		{[]string{"set", "port=2", "mode=slow"}, `invalid configuration for snap "test-snap": cannot accept element in "mode": string "slow" is not one of the allowed choices`},
+		{[]string{"set", "other=1"}, `invalid configuration for snap "test-snap": cannot accept top level element: map contains unexpected key "other"`},
+		{[]string{"set", "db.port=1"}, `invalid configuration for snap "test-snap": cannot accept element in "db": map contains unexpected key "port"`},
+		{[]string{"set", "db.host=1"}, `invalid configuration for snap "test-snap": cannot accept element in "db.host": expected string type but value was number`},
+	} {
+		_, _, _, err := ctlcmd.Run(s.mockContext, t.args, 0, nil)
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.args))
+	}
+
+	// the rejected changes were not applied
+	s.mockContext.Lock()
+	defer s.mockContext.Unlock()
+	c.Check(s.mockContext.Done(), IsNil)
+
+	var cfg map[string]any
+	tr := config.NewTransaction(s.mockContext.State())
+	c.Assert(tr.Get("test-snap", "", &cfg), IsNil)
+	c.Check(cfg, DeepEquals, map[string]any{"port": json.Number("22"), "mode": "fast", "db": map[string]any{}})
+}
@@ -85,10 +85,7 @@ func (s *unsetCommand) Execute(args []string) error {
 		tr := configstate.ContextTransaction(context)
 
 		// unsetting options
-		for _, confKey := range s.Positional.ConfKeys {
-			tr.Set(context.InstanceName(), confKey, nil)
-		}
-		return nil
+		return setSnapConfig(tr, context.InstanceName(), s.Positional.ConfKeys, nil)
 	}
 
 	if err := validateConfdbFeatureFlag(context.State()); err != nil {
@@ -0,0 +1,238 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/sandbox/ebpf"
+	"github.com/snapcore/snapd/snap"
+)
+
+// Auditing of device accesses is enabled per snap. When enabled, the device
+// cgroup set up by snap-confine for the snap counts the device accesses it
+// denies, without changing what is allowed. Applications pick up the change
+// when they are started next.
+
+var ebpfRemoveDeviceAuditMaps = ebpf.RemoveDeviceAuditMaps
+
+func getDeviceAudit(st *state.State) (map[string]bool, error) {
+	var audited map[string]bool
+	if err := st.Get("device-audit", &audited); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, fmt.Errorf("cannot obtain device audit state: %v", err)
+	}
+	return audited, nil
+}
+
+func setDeviceAudit(st *state.State, audited map[string]bool) {
+	if len(audited) == 0 {
+		st.Set("device-audit", nil)
+		return
+	}
+	st.Set("device-audit", audited)
+}
+
+// DeviceAuditEnabled returns whether the device accesses denied to the given
+// snap are audited.
+func DeviceAuditEnabled(st *state.State, instanceName string) (bool, error) {
+	audited, err := getDeviceAudit(st)
+	if err != nil {
+		return false, err
+	}
+	return audited[instanceName], nil
+}
+
+// SetDeviceAudit returns a set of tasks enabling or disabling the auditing of
+// the device accesses denied to the given snap. Disabling auditing drops the
+// accesses counted so far.
+func SetDeviceAudit(st *state.State, instanceName string, enable bool) (*state.TaskSet, error) {
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil, &snap.NotInstalledError{Snap: instanceName}
+		}
+		return nil, err
+	}
+	if enable && (snapst.DevMode || snapst.Classic) && !snapst.JailMode {
+		// there is no device cgroup to audit
+		return nil, fmt.Errorf("cannot audit device accesses of snap %q: snap is not strictly confined", instanceName)
+	}
+	if err := snapstate.CheckChangeConflict(st, instanceName, nil); err != nil {
+		return nil, err
+	}
+
+	var summary string
+	if enable {
+		summary = fmt.Sprintf(i18n.G("Enable auditing of device accesses of snap %q"), instanceName)
+	} else {
+		summary = fmt.Sprintf(i18n.G("Disable auditing of device accesses of snap %q"), instanceName)
+	}
+	task := st.NewTask("set-device-audit", summary)
+	task.Set("snap-name", instanceName)
+	task.Set("enable", enable)
+	return state.NewTaskSet(task), nil
+}
+
+func deviceAuditAffectedSnaps(t *state.Task) ([]string, error) {
+	var instanceName string
+	if err := t.Get("snap-name", &instanceName); err != nil {
+		return nil, fmt.Errorf("internal error: cannot obtain snap name from task: %s", t.Summary())
+	}
+	return []string{instanceName}, nil
+}
+
+func (m *InterfaceManager) doSetDeviceAudit(task *state.Task, _ *tomb.Tomb) error {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var enable bool
+	if err := task.Get("enable", &enable); err != nil {
+		return err
+	}
+	wasEnabled, err := m.setDeviceAudit(task, enable)
+	if err != nil {
+		return err
+	}
+	task.Set("old-enable", wasEnabled)
+	return nil
+}
+
+func (m *InterfaceManager) undoSetDeviceAudit(task *state.Task, _ *tomb.Tomb) error {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var wasEnabled bool
+	if err := task.Get("old-enable", &wasEnabled); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	_, err := m.setDeviceAudit(task, wasEnabled)
+	return err
+}
+
+// setDeviceAudit enables or disables auditing of device accesses of the
+// snap of the task and sets up its security again. It returns whether
+// auditing was enabled before.
+func (m *InterfaceManager) setDeviceAudit(task *state.Task, enable bool) (wasEnabled bool, err error) {
+	st := task.State()
+	perfTimings := state.TimingsForTask(task)
+	defer perfTimings.Save(st)
+
+	var instanceName string
+	if err := task.Get("snap-name", &instanceName); err != nil {
+		return false, err
+	}
+	audited, err := getDeviceAudit(st)
+	if err != nil {
+		return false, err
+	}
+	wasEnabled = audited[instanceName]
+	if wasEnabled == enable {
+		return wasEnabled, nil
+	}
+
+	if audited == nil {
+		audited = make(map[string]bool)
+	}
+	if enable {
+		audited[instanceName] = true
+	} else {
+		delete(audited, instanceName)
+	}
+	// the confinement options are built from the state
+	setDeviceAudit(st, audited)
+	defer func() {
+		if err != nil {
+			if wasEnabled {
+				audited[instanceName] = true
+			} else {
+				delete(audited, instanceName)
+			}
+			setDeviceAudit(st, audited)
+		}
+	}()
+
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, instanceName, &snapst); err != nil {
+		return false, err
+	}
+	snapInfo, err := snapst.CurrentInfo()
+	if err != nil {
+		return false, err
+	}
+	appSet, err := appSetForSnapRevision(st, snapInfo)
+	if err != nil {
+		return false, fmt.Errorf("building app set for snap %q: %v", snapInfo.InstanceName(), err)
+	}
+	opts, err := m.buildConfinementOptions(st, task, snapInfo, snapst.Flags)
+	if err != nil {
+		return false, err
+	}
+	if err := m.setupSnapSecurity(task, appSet, opts, perfTimings); err != nil {
+		return false, err
+	}
+
+	if !enable {
+		if err := ebpfRemoveDeviceAuditMaps(instanceName); err != nil {
+			// the maps are only used for reporting
+			logger.Noticef("cannot remove device audit maps of snap %q: %v", instanceName, err)
+		}
+	}
+	return wasEnabled, nil
+}
+
+// discardDeviceAudit forgets about the auditing of device accesses of a snap
+// that is removed, returning whether it was enabled.
+func discardDeviceAudit(st *state.State, instanceName string) (bool, error) {
+	audited, err := getDeviceAudit(st)
+	if err != nil {
+		return false, err
+	}
+	if !audited[instanceName] {
+		return false, nil
+	}
+	delete(audited, instanceName)
+	setDeviceAudit(st, audited)
+	if err := ebpfRemoveDeviceAuditMaps(instanceName); err != nil {
+		logger.Noticef("cannot remove device audit maps of snap %q: %v", instanceName, err)
+	}
+	return true, nil
+}
+
+func restoreDeviceAudit(st *state.State, instanceName string) error {
+	audited, err := getDeviceAudit(st)
+	if err != nil {
+		return err
+	}
+	if audited == nil {
+		audited = make(map[string]bool)
+	}
+	audited[instanceName] = true
+	setDeviceAudit(st, audited)
+	return nil
+}
@@ -0,0 +1,179 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func (s *interfaceManagerSuite) runDeviceAuditChange(c *C, enable, fail bool) *state.Change {
+	s.state.Lock()
+	ts, err := ifacestate.SetDeviceAudit(s.state, "consumer", enable)
+	c.Assert(err, IsNil)
+	c.Assert(ts.Tasks(), HasLen, 1)
+	c.Check(ts.Tasks()[0].Kind(), Equals, "set-device-audit")
+	change := s.state.NewChange("set-device-audit", "...")
+	change.AddAll(ts)
+	if fail {
+		terr := s.state.NewTask("error-trigger", "provoking total undo")
+		terr.WaitAll(ts)
+		change.AddTask(terr)
+	}
+	s.state.Unlock()
+
+	s.settle(c)
+	return change
+}
+
+func (s *interfaceManagerSuite) TestSetDeviceAudit(c *C) {
+	var removed []string
+	restore := ifacestate.MockEbpfRemoveDeviceAuditMaps(func(instanceName string) error {
+		removed = append(removed, instanceName)
+		return nil
+	})
+	defer restore()
+
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	_ = s.manager(c)
+
+	change := s.runDeviceAuditChange(c, true, false)
+
+	s.state.Lock()
+	c.Assert(change.Err(), IsNil)
+	enabled, err := ifacestate.DeviceAuditEnabled(s.state, "consumer")
+	c.Assert(err, IsNil)
+	c.Check(enabled, Equals, true)
+	s.state.Unlock()
+
+	c.Assert(s.secBackend.SetupCalls, HasLen, 1)
+	c.Check(s.secBackend.SetupCalls[0].AppSet.InstanceName(), Equals, "consumer")
+	c.Check(s.secBackend.SetupCalls[0].Options, DeepEquals, interfaces.ConfinementOptions{KernelSnap: "krnl", DeviceAudit: true})
+	c.Check(removed, HasLen, 0)
+
+	s.secBackend.SetupCalls = nil
+	change = s.runDeviceAuditChange(c, false, false)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(change.Err(), IsNil)
+	enabled, err = ifacestate.DeviceAuditEnabled(s.state, "consumer")
+	c.Assert(err, IsNil)
+	c.Check(enabled, Equals, false)
+	var audited map[string]bool
+	c.Check(s.state.Get("device-audit", &audited), testutil.ErrorIs, state.ErrNoState)
+
+	c.Assert(s.secBackend.SetupCalls, HasLen, 1)
+	c.Check(s.secBackend.SetupCalls[0].Options, DeepEquals, interfaces.ConfinementOptions{KernelSnap: "krnl"})
+	c.Check(removed, DeepEquals, []string{"consumer"})
+}
+
+func (s *interfaceManagerSuite) TestSetDeviceAuditUndo(c *C) {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	_ = s.manager(c)
+
+	change := s.runDeviceAuditChange(c, true, true)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(change.Status(), Equals, state.ErrorStatus)
+	c.Check(change.Tasks()[0].Status(), Equals, state.UndoneStatus)
+	enabled, err := ifacestate.DeviceAuditEnabled(s.state, "consumer")
+	c.Assert(err, IsNil)
+	c.Check(enabled, Equals, false)
+
+	// set up with auditing and then without it again
+	c.Assert(s.secBackend.SetupCalls, HasLen, 2)
+	c.Check(s.secBackend.SetupCalls[0].Options, DeepEquals, interfaces.ConfinementOptions{KernelSnap: "krnl", DeviceAudit: true})
+	c.Check(s.secBackend.SetupCalls[1].Options, DeepEquals, interfaces.ConfinementOptions{KernelSnap: "krnl"})
+}
+
+func (s *interfaceManagerSuite) TestSetDeviceAuditErrors(c *C) {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	_ = s.manager(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := ifacestate.SetDeviceAudit(s.state, "missing", true)
+	c.Check(err, ErrorMatches, `snap "missing" is not installed`)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "consumer", &snapst), IsNil)
+	snapst.DevMode = true
+	snapstate.Set(s.state, "consumer", &snapst)
+	_, err = ifacestate.SetDeviceAudit(s.state, "consumer", true)
+	c.Check(err, ErrorMatches, `cannot audit device accesses of snap "consumer": snap is not strictly confined`)
+	// but it can always be disabled
+	_, err = ifacestate.SetDeviceAudit(s.state, "consumer", false)
+	c.Check(err, IsNil)
+
+	snapst.DevMode = false
+	snapstate.Set(s.state, "consumer", &snapst)
+	chg := s.state.NewChange("other", "...")
+	t := s.state.NewTask("link-snap", "...")
+	t.Set("snap-setup", &snapstate.SnapSetup{SideInfo: snapst.Sequence.Revisions[0].Snap})
+	chg.AddTask(t)
+	_, err = ifacestate.SetDeviceAudit(s.state, "consumer", true)
+	c.Check(err, ErrorMatches, `snap "consumer" has "other" change in progress`)
+}
+
+func (s *interfaceManagerSuite) TestDoDiscardConnsDeviceAudit(c *C) {
+	var removed []string
+	restore := ifacestate.MockEbpfRemoveDeviceAuditMaps(func(instanceName string) error {
+		removed = append(removed, instanceName)
+		return nil
+	})
+	defer restore()
+
+	s.manager(c)
+
+	s.state.Lock()
+	s.state.Set("device-audit", map[string]bool{"consumer": true, "producer": true})
+	snapstate.Set(s.state, "consumer", &snapstate.SnapState{})
+	s.state.Unlock()
+
+	change, t := s.addDiscardConnsChange("consumer")
+	s.state.Lock()
+	terr := s.state.NewTask("error-trigger", "provoking undo")
+	terr.WaitFor(t)
+	change.AddTask(terr)
+	s.state.Unlock()
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(t.Status(), Equals, state.UndoneStatus)
+	c.Check(removed, DeepEquals, []string{"consumer"})
+	// auditing is enabled again on undo
+	var audited map[string]bool
+	c.Assert(s.state.Get("device-audit", &audited), IsNil)
+	c.Check(audited, DeepEquals, map[string]bool{"consumer": true, "producer": true})
+}
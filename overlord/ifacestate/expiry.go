@@ -0,0 +1,120 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/swfeats"
+)
+
+var disconnectExpiredChangeKind = swfeats.RegisterChangeKind("disconnect-expired")
+
+func init() {
+	swfeats.RegisterEnsure("InterfaceManager", "ensureExpiredConnections")
+}
+
+var timeNow = time.Now
+
+// ensureExpiredConnections disconnects the time-limited connections whose
+// expiry time has been reached, and makes sure the manager is woken up again
+// in time for the next one to expire.
+func (m *InterfaceManager) ensureExpiredConnections() error {
+	st := m.state
+	st.Lock()
+	defer st.Unlock()
+
+	conns, err := getConns(st)
+	if err != nil {
+		return err
+	}
+
+	now := timeNow()
+	var next time.Time
+	var expired []string
+	for id, connState := range conns {
+		if connState.Expiry.IsZero() || connState.Undesired || connState.HotplugGone {
+			continue
+		}
+		if now.Before(connState.Expiry) {
+			if next.IsZero() || connState.Expiry.Before(next) {
+				next = connState.Expiry
+			}
+			continue
+		}
+		expired = append(expired, id)
+	}
+	if !next.IsZero() {
+		st.EnsureBefore(next.Sub(now))
+	}
+	if len(expired) == 0 {
+		// nothing to do
+		return nil
+	}
+	sort.Strings(expired)
+
+	logger.Trace("ensure", "manager", "InterfaceManager", "func", "ensureExpiredConnections")
+
+	for _, id := range expired {
+		connRef, err := interfaces.ParseConnRef(id)
+		if err != nil {
+			return err
+		}
+		conn, err := m.repo.Connection(connRef)
+		if err != nil {
+			// the plug or slot is not around at the moment, e.g.
+			// because the snap is disabled, the connection is
+			// disconnected once it is restored
+			continue
+		}
+		err = snapstate.CheckChangeConflictMany(st, []string{connRef.PlugRef.Snap, connRef.SlotRef.Snap}, "")
+		if err != nil {
+			var conflictErr *snapstate.ChangeConflictError
+			if errors.As(err, &conflictErr) {
+				// try again on the next ensure
+				logger.Debugf("cannot disconnect expired connection %s yet: %v", id, err)
+				continue
+			}
+			return err
+		}
+		ts, err := disconnectTasks(st, conn, disconnectOpts{
+			// Similarly to explicit Disconnect(), failing hooks
+			// shall not block disconnecting the interface.
+			IgnoreHookError: true,
+		})
+		if err != nil {
+			return err
+		}
+		summary := fmt.Sprintf(i18n.G("Disconnect expired connection %s:%s from %s:%s"),
+			connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name)
+		chg := st.NewChange(disconnectExpiredChangeKind, summary)
+		chg.AddAll(ts)
+		logger.Noticef("Connection %s expired at %s, disconnecting", id, conns[id].Expiry.Format(time.RFC3339))
+		st.EnsureBefore(0)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var expiryTestNow = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func (s *interfaceManagerSuite) TestConnectWithExpiryTracksExpiryInState(c *C) {
+	restore := ifacestate.MockTimeNow(func() time.Time { return expiryTestNow })
+	defer restore()
+
+	s.MockModel(c, nil)
+
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	_ = s.manager(c)
+
+	s.state.Lock()
+
+	expiry := expiryTestNow.Add(time.Hour)
+	ts, err := ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{Expiry: expiry})
+	c.Assert(err, IsNil)
+	c.Assert(ts.Tasks(), HasLen, 5)
+
+	connectTask := ts.Tasks()[2]
+	c.Assert(connectTask.Kind(), Equals, "connect")
+	var taskExpiry time.Time
+	c.Assert(connectTask.Get("expiry", &taskExpiry), IsNil)
+	c.Check(taskExpiry.Equal(expiry), Equals, true)
+
+	connectTask.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "consumer",
+		},
+	})
+
+	change := s.state.NewChange("connect", "")
+	change.AddAll(ts)
+	s.state.Unlock()
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(change.Err(), IsNil)
+	c.Check(change.Status(), Equals, state.DoneStatus)
+	var conns map[string]any
+	err = s.state.Get("conns", &conns)
+	c.Assert(err, IsNil)
+	c.Check(conns, DeepEquals, map[string]any{
+		"consumer:plug producer:slot": map[string]any{
+			"interface":   "test",
+			"plug-static": map[string]any{"attr1": "value1"},
+			"slot-static": map[string]any{"attr2": "value2"},
+			"expiry":      "2026-10-19T13:00:00Z",
+		},
+	})
+
+	connStates, err := ifacestate.ConnectionStates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(connStates["consumer:plug producer:slot"].Expiry.Equal(expiry), Equals, true)
+
+	// the connection did not expire yet
+	for _, chg := range s.state.Changes() {
+		c.Check(chg.Kind(), Not(Equals), "disconnect-expired")
+	}
+}
+
+func (s *interfaceManagerSuite) TestConnectWithExpiryInThePast(c *C) {
+	restore := ifacestate.MockTimeNow(func() time.Time { return expiryTestNow })
+	defer restore()
+
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+	_ = s.manager(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{Expiry: expiryTestNow})
+	c.Assert(err, ErrorMatches, `cannot connect consumer:plug to producer:slot: expiry time 2026-10-19T12:00:00Z is in the past`)
+}
+
+func (s *interfaceManagerSuite) mockTimeLimitedConnection(c *C, expiry time.Time) {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	s.state.Lock()
+	s.state.Set("conns", map[string]any{
+		"consumer:plug producer:slot": map[string]any{
+			"interface": "test",
+			"expiry":    expiry.Format(time.RFC3339),
+		},
+	})
+	s.state.Unlock()
+}
+
+func (s *interfaceManagerSuite) TestEnsureDisconnectsExpiredConnections(c *C) {
+	restore := ifacestate.MockTimeNow(func() time.Time { return expiryTestNow })
+	defer restore()
+
+	s.mockTimeLimitedConnection(c, expiryTestNow.Add(-time.Minute))
+	mgr := s.manager(c)
+
+	// the connection is restored on startup
+	c.Assert(mgr.Repository().Interfaces().Connections, HasLen, 1)
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	var expired []*state.Change
+	for _, chg := range s.state.Changes() {
+		if chg.Kind() == "disconnect-expired" {
+			expired = append(expired, chg)
+		}
+	}
+	c.Assert(expired, HasLen, 1)
+	chg := expired[0]
+	c.Check(chg.Summary(), Equals, "Disconnect expired connection consumer:plug from producer:slot")
+	c.Assert(chg.Err(), IsNil)
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+
+	var kinds []string
+	for _, t := range chg.Tasks() {
+		kinds = append(kinds, t.Kind())
+	}
+	// the disconnect hooks run as for a regular disconnect
+	c.Check(kinds, DeepEquals, []string{"run-hook", "run-hook", "disconnect"})
+
+	var conns map[string]any
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, HasLen, 0)
+	c.Check(mgr.Repository().Interfaces().Connections, HasLen, 0)
+}
+
+func (s *interfaceManagerSuite) TestEnsureKeepsUnexpiredConnections(c *C) {
+	restore := ifacestate.MockTimeNow(func() time.Time { return expiryTestNow })
+	defer restore()
+
+	s.mockTimeLimitedConnection(c, expiryTestNow.Add(time.Minute))
+	mgr := s.manager(c)
+
+	c.Assert(mgr.Ensure(), IsNil)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Check(s.state.Changes(), HasLen, 0)
+	var conns map[string]any
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, HasLen, 1)
+	c.Check(mgr.Repository().Interfaces().Connections, HasLen, 1)
+}
+
+func (s *interfaceManagerSuite) TestEnsureExpiredConnectionWaitsForConflicts(c *C) {
+	restore := ifacestate.MockTimeNow(func() time.Time { return expiryTestNow })
+	defer restore()
+
+	s.mockTimeLimitedConnection(c, expiryTestNow.Add(-time.Minute))
+	mgr := s.manager(c)
+
+	s.state.Lock()
+	chg := s.state.NewChange("other-chg", "...")
+	t := s.state.NewTask("link-snap", "...")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "consumer",
+		},
+	})
+	chg.AddTask(t)
+	s.state.Unlock()
+
+	c.Assert(mgr.Ensure(), IsNil)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// nothing is done until the conflicting change is over
+	c.Check(s.state.Changes(), HasLen, 1)
+	var conns map[string]any
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, HasLen, 1)
+
+	chg.SetStatus(state.DoneStatus)
+	s.state.Unlock()
+	c.Assert(mgr.Ensure(), IsNil)
+	s.state.Lock()
+
+	c.Check(s.state.Changes(), HasLen, 2)
+}
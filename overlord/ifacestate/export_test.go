@@ -122,6 +122,10 @@ func MockContentLinkRetryTimeout(d time.Duration) (restore func()) {
 	return func() { contentLinkRetryTimeout = old }
 }
 
+func MockTimeNow(f func() time.Time) (restore func()) {
+	return testutil.Mock(&timeNow, f)
+}
+
 func MockHotplugRetryTimeout(d time.Duration) (restore func()) {
 	old := hotplugRetryTimeout
 	hotplugRetryTimeout = d
@@ -236,3 +240,7 @@ func (m *InterfaceManager) SetupSecurityByBackend(task *state.Task, appSets []*i
 func MockIsSnapVerified(new func(st *state.State, snapID string) bool) (restore func()) {
 	return testutil.Mock(&isSnapVerified, new)
 }
+
+func MockEbpfRemoveDeviceAuditMaps(f func(instanceName string) error) (restore func()) {
+	return testutil.Mock(&ebpfRemoveDeviceAuditMaps, f)
+}
@@ -101,6 +101,11 @@ func (m *InterfaceManager) buildConfinementOptions(st *state.State, task *state.
 		kernelSnap = deviceCtx.Kernel()
 	}
 
+	deviceAudit, err := DeviceAuditEnabled(st, snapInfo.InstanceName())
+	if err != nil {
+		return interfaces.ConfinementOptions{}, err
+	}
+
 	return interfaces.ConfinementOptions{
 		DevMode:           flags.DevMode,
 		JailMode:          flags.JailMode,
@@ -108,6 +113,7 @@ func (m *InterfaceManager) buildConfinementOptions(st *state.State, task *state.
 		ExtraLayouts:      extraLayouts,
 		AppArmorPrompting: m.useAppArmorPrompting,
 		KernelSnap:        kernelSnap,
+		DeviceAudit:       deviceAudit,
 	}, nil
 }
 
@@ -828,6 +834,14 @@ func (m *InterfaceManager) doDiscardConns(task *state.Task, _ *tomb.Tomb) error
 	}
 	task.Set("removed", removed)
 	setConns(st, conns)
+
+	audited, err := discardDeviceAudit(st, instanceName)
+	if err != nil {
+		return err
+	}
+	if audited {
+		task.Set("removed-device-audit", true)
+	}
 	return nil
 }
 
@@ -852,6 +866,21 @@ func (m *InterfaceManager) undoDiscardConns(task *state.Task, _ *tomb.Tomb) erro
 	}
 	setConns(st, conns)
 	task.Set("removed", nil)
+
+	var audited bool
+	if err := task.Get("removed-device-audit", &audited); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	if audited {
+		snapSetup, err := snapstate.TaskSnapSetup(task)
+		if err != nil {
+			return err
+		}
+		if err := restoreDeviceAudit(st, snapSetup.InstanceName()); err != nil {
+			return err
+		}
+		task.Set("removed-device-audit", nil)
+	}
 	return nil
 }
 
@@ -898,10 +927,22 @@ func (m *InterfaceManager) doConnect(task *state.Task, _ *tomb.Tomb) (err error)
 	if err := task.Get("by-gadget", &byGadget); err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
 	}
+	var byModel bool
+	if err := task.Get("by-model", &byModel); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
 	var delayedSetupProfiles bool
 	if err := task.Get("delayed-setup-profiles", &delayedSetupProfiles); err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
 	}
+	var expiry time.Time
+	if err := task.Get("expiry", &expiry); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	var users []int
+	if err := task.Get("users", &users); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
 
 	deviceCtx, err := snapstate.DeviceCtx(st, task, nil)
 	if err != nil {
@@ -963,10 +1004,10 @@ func (m *InterfaceManager) doConnect(task *state.Task, _ *tomb.Tomb) (err error)
 
 	var policyChecker interfaces.PolicyFunc
 
-	// manual connections and connections by the gadget obey the
-	// policy "connection" rules, other auto-connections obey the
-	// "auto-connection" rules
-	if autoConnect && !byGadget {
+	// manual connections and connections by the gadget or the model
+	// obey the policy "connection" rules, other auto-connections obey
+	// the "auto-connection" rules
+	if autoConnect && !byGadget && !byModel {
 		autochecker, err := newAutoConnectChecker(st, m.repo, deviceCtx)
 		if err != nil {
 			return err
@@ -995,6 +1036,11 @@ func (m *InterfaceManager) doConnect(task *state.Task, _ *tomb.Tomb) (err error)
 			}
 		}
 	}()
+	if len(users) > 0 {
+		if err := m.repo.SetConnectionUsers(connRef, users); err != nil {
+			return err
+		}
+	}
 
 	if !delayedSetupProfiles {
 		slotSnapInfo, err := slotSnapst.CurrentInfo()
@@ -1041,7 +1087,10 @@ func (m *InterfaceManager) doConnect(task *state.Task, _ *tomb.Tomb) (err error)
 		DynamicSlotAttrs: conn.Slot.DynamicAttrs(),
 		Auto:             autoConnect,
 		ByGadget:         byGadget,
+		ByModel:          byModel,
 		HotplugKey:       slot.HotplugKey,
+		Expiry:           expiry,
+		Users:            users,
 	}
 	setConns(st, conns)
 
@@ -1242,6 +1291,11 @@ func (m *InterfaceManager) undoDisconnect(task *state.Task, _ *tomb.Tomb) error
 	if err != nil {
 		return err
 	}
+	if len(oldconn.Users) > 0 {
+		if err := m.repo.SetConnectionUsers(connRef, oldconn.Users); err != nil {
+			return err
+		}
+	}
 
 	slotSnapInfo, err := slotSnapst.CurrentInfo()
 	if err != nil {
@@ -1851,9 +1905,9 @@ func (m *InterfaceManager) doAutoConnect(task *state.Task, _ *tomb.Tomb) error {
 		return fmt.Errorf("auto-connect conflict check failed: %v", err)
 	}
 
-	// Consider gadget connections, we want to remember them in
-	// any case with "by-gadget" set, so they should be processed
-	// before the auto-connection ones.
+	// Consider gadget and model connections, we want to remember
+	// them in any case with "by-gadget" or "by-model" set, so they
+	// should be processed before the auto-connection ones.
 	if err := gadgectConnect.addGadgetConnections(newconns, conns, conflictError); err != nil {
 		return err
 	}
@@ -1864,6 +1918,20 @@ func (m *InterfaceManager) doAutoConnect(task *state.Task, _ *tomb.Tomb) error {
 			connOpts[key] = byGadgetOpts
 		}
 	}
+	if err := gadgectConnect.addModelConnections(newconns, conns, conflictError); err != nil {
+		return err
+	}
+	if len(newconns) > len(connOpts) {
+		if connOpts == nil {
+			connOpts = make(map[string]*connectOpts, len(newconns))
+		}
+		byModelOpts := &connectOpts{AutoConnect: true, ByModel: true}
+		for key := range newconns {
+			if _, ok := connOpts[key]; !ok {
+				connOpts[key] = byModelOpts
+			}
+		}
+	}
 
 	// Auto-connect all the plugs unless specifically disallowed
 	checkAutoConnectAllowed := func(css []*snap.SlotInfo) []*snap.SlotInfo {
@@ -2007,6 +2075,140 @@ func (m *InterfaceManager) doAutoDisconnect(task *state.Task, _ *tomb.Tomb) erro
 	return nil
 }
 
+// doRestoreConnections puts the connections of a set of snaps back as they
+// were recorded in the task, disconnecting what was not there and
+// reconnecting what went away. It is used when reverting a snap set
+// generation.
+func (m *InterfaceManager) doRestoreConnections(task *state.Task, _ *tomb.Tomb) error {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var snaps []string
+	if err := task.Get("snaps", &snaps); err != nil {
+		return err
+	}
+	var wanted []snapstate.SnapSetConnection
+	if err := task.Get("connections", &wanted); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+
+	current, err := snapSetConnections(st, snaps)
+	if err != nil {
+		return err
+	}
+	// remembered so that undo can check the injected tasks put them back
+	task.Set("old-connections", current)
+
+	wantedIDs := make(map[string]bool, len(wanted))
+	for _, c := range wanted {
+		wantedIDs[c.ID] = true
+	}
+	currentIDs := make(map[string]bool, len(current))
+	for _, c := range current {
+		currentIDs[c.ID] = true
+	}
+
+	ts := state.NewTaskSet()
+	for _, c := range current {
+		if wantedIDs[c.ID] {
+			continue
+		}
+		connRef, err := interfaces.ParseConnRef(c.ID)
+		if err != nil {
+			return err
+		}
+		conn, err := m.repo.Connection(connRef)
+		if err != nil {
+			// not active, nothing to disconnect
+			continue
+		}
+		// forget the connection rather than marking it undesired, it
+		// did not exist before
+		dts, err := disconnectTasks(st, conn, disconnectOpts{Forget: true})
+		if err != nil {
+			return err
+		}
+		ts.AddAll(dts)
+	}
+	for _, c := range wanted {
+		if currentIDs[c.ID] {
+			continue
+		}
+		connRef, err := interfaces.ParseConnRef(c.ID)
+		if err != nil {
+			return err
+		}
+		if m.repo.Plug(connRef.PlugRef.Snap, connRef.PlugRef.Name) == nil || m.repo.Slot(connRef.SlotRef.Snap, connRef.SlotRef.Name) == nil {
+			task.Logf("Cannot restore connection %s: plug or slot is no longer available", c.ID)
+			continue
+		}
+		cts, err := connect(st, connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name, connectOpts{
+			AutoConnect: c.Auto,
+			ByGadget:    c.ByGadget,
+			ByModel:     c.ByModel,
+		})
+		if err != nil {
+			return err
+		}
+		ts.AddAll(cts)
+	}
+
+	if len(ts.Tasks()) > 0 {
+		snapstate.InjectTasks(task, ts)
+		st.EnsureBefore(0)
+	}
+
+	// make sure that we add tasks and mark this task done in the same atomic write, otherwise there is a risk of re-adding tasks again
+	task.SetStatus(state.DoneStatus)
+	return nil
+}
+
+// undoRestoreConnections checks that the connections that were active before
+// the task ran are back. The connect and disconnect tasks injected by
+// doRestoreConnections run after the task and are undone before it, so
+// there is nothing to revert here, only what could not be brought back is
+// reported.
+func (m *InterfaceManager) undoRestoreConnections(task *state.Task, _ *tomb.Tomb) error {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var snaps []string
+	if err := task.Get("snaps", &snaps); err != nil {
+		return err
+	}
+	var old []snapstate.SnapSetConnection
+	if err := task.Get("old-connections", &old); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil
+		}
+		return err
+	}
+
+	current, err := snapSetConnections(st, snaps)
+	if err != nil {
+		return err
+	}
+	currentIDs := make(map[string]bool, len(current))
+	for _, c := range current {
+		currentIDs[c.ID] = true
+	}
+	oldIDs := make(map[string]bool, len(old))
+	for _, c := range old {
+		oldIDs[c.ID] = true
+		if !currentIDs[c.ID] {
+			task.Logf("Cannot restore connection %s", c.ID)
+		}
+	}
+	for _, c := range current {
+		if !oldIDs[c.ID] {
+			task.Logf("Cannot remove connection %s", c.ID)
+		}
+	}
+	return nil
+}
+
 func (m *InterfaceManager) undoAutoConnect(task *state.Task, _ *tomb.Tomb) error {
 	// TODO Introduce disconnection hooks, and run them here as well to give a chance
 	// for the snap to undo whatever it did when the connection was established.
@@ -49,6 +49,7 @@ import (
 
 func init() {
 	snapstate.HasActiveConnection = hasActiveConnection
+	snapstate.SnapSetConnections = snapSetConnections
 }
 
 var (
@@ -579,7 +580,7 @@ ConnsLoop:
 			// automatic connection can simply be removed (it will be re-created automatically if needed)
 			// as long as it wasn't disconnected manually; note that undesired flag is taken care of at
 			// the beginning of the loop.
-			if connState.Auto && !connState.ByGadget && connState.Interface != "core-support" {
+			if connState.Auto && !connState.ByGadget && !connState.ByModel && connState.Interface != "core-support" {
 				// only do anything about this connection if snap isn't in a broken state, otherwise
 				// leave the connection untouched.
 				for _, snapName := range []string{connRef.PlugRef.Snap, connRef.SlotRef.Snap} {
@@ -609,7 +610,7 @@ ConnsLoop:
 		// if the interface was originally autoconnected, update the static attrs if it would
 		// still be allowed to autoconnect. Otherwise, update the static attrs if it would still
 		// be allowed to regular connect.
-		if connState.Auto && !connState.ByGadget {
+		if connState.Auto && !connState.ByGadget && !connState.ByModel {
 			policyChecker = func(cplug *interfaces.ConnectedPlug, cslot *interfaces.ConnectedSlot) (bool, error) {
 				iface, err := interfaces.ByName(cplug.Interface())
 				if err != nil {
@@ -652,6 +653,11 @@ ConnsLoop:
 		if _, err := m.repo.Connect(connRef, staticPlugAttrs, connState.DynamicPlugAttrs, staticSlotAttrs, connState.DynamicSlotAttrs, nil); err != nil {
 			logger.Noticef("%s", err)
 		} else {
+			if len(connState.Users) > 0 {
+				if err := m.repo.SetConnectionUsers(connRef, connState.Users); err != nil {
+					return nil, nil, err
+				}
+			}
 			// If the connection succeeded update the connection state and keep
 			// track of the snaps that were affected.
 			reloadedConnections = append(reloadedConnections, connId)
@@ -1574,6 +1580,14 @@ func systemSnapInfo(st *state.State) (*snap.Info, error) {
 	return snapstate.CurrentInfo(st, SystemSnapName())
 }
 
+func restoreConnectionsAffectedSnaps(t *state.Task) ([]string, error) {
+	var snaps []string
+	if err := t.Get("snaps", &snaps); err != nil {
+		return nil, fmt.Errorf("internal error: cannot obtain snaps from task: %s", t.Summary())
+	}
+	return snaps, nil
+}
+
 func connectDisconnectAffectedSnaps(t *state.Task) ([]string, error) {
 	plugRef, slotRef, err := getPlugAndSlotRefs(t)
 	if err != nil {
@@ -1718,6 +1732,42 @@ func hasActiveConnection(st *state.State, iface string) (bool, error) {
 	return false, nil
 }
 
+// snapSetConnections returns the active connections involving any of the
+// given snaps.
+func snapSetConnections(st *state.State, snaps []string) ([]snapstate.SnapSetConnection, error) {
+	conns, err := getConns(st)
+	if err != nil {
+		return nil, err
+	}
+	inSet := make(map[string]bool, len(snaps))
+	for _, name := range snaps {
+		inSet[name] = true
+	}
+
+	var res []snapstate.SnapSetConnection
+	for id, cstate := range conns {
+		if cstate.Undesired || cstate.HotplugGone {
+			continue
+		}
+		connRef, err := interfaces.ParseConnRef(id)
+		if err != nil {
+			return nil, err
+		}
+		if !inSet[connRef.PlugRef.Snap] && !inSet[connRef.SlotRef.Snap] {
+			continue
+		}
+		res = append(res, snapstate.SnapSetConnection{
+			ID:        id,
+			Interface: cstate.Interface,
+			Auto:      cstate.Auto,
+			ByGadget:  cstate.ByGadget,
+			ByModel:   cstate.ByModel,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
 func appSetForTask(t *state.Task, info *snap.Info) (*interfaces.SnapAppSet, error) {
 	compsups, err := snapstate.ComponentSetupsForTask(t)
 	if err != nil {
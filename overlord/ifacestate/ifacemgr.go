@@ -114,11 +114,15 @@ func Manager(s *state.State, hookManager *hookstate.HookManager, noticeManager *
 
 	addHandler("connect", m.doConnect, m.undoConnect)
 	addHandler("disconnect", m.doDisconnect, m.undoDisconnect)
+	addHandler("set-connection-users", m.doSetConnectionUsers, m.undoSetConnectionUsers)
+	addHandler("set-device-audit", m.doSetDeviceAudit, m.undoSetDeviceAudit)
 	addHandler("setup-profiles", m.doSetupProfiles, m.undoSetupProfiles)
 	addHandler("remove-profiles", m.doRemoveProfiles, m.doSetupProfiles)
 	addHandler("discard-conns", m.doDiscardConns, m.undoDiscardConns)
 	addHandler("auto-connect", m.doAutoConnect, m.undoAutoConnect)
 	addHandler("auto-disconnect", m.doAutoDisconnect, nil)
+	addHandler("connect-model-interfaces", m.doConnectModelInterfaces, nil)
+	addHandler("restore-connections", m.doRestoreConnections, m.undoRestoreConnections)
 	addHandler("hotplug-add-slot", m.doHotplugAddSlot, nil)
 	addHandler("hotplug-connect", m.doHotplugConnect, nil)
 	addHandler("hotplug-update-slot", m.doHotplugUpdateSlot, nil)
@@ -297,11 +301,16 @@ Run "systemctl enable --now snapd.apparmor" to correct this.`)
 
 // Ensure implements StateManager.Ensure.
 func (m *InterfaceManager) Ensure() error {
-	// do not worry about udev monitor in preseeding mode
+	// do not worry about udev monitor or expiring connections in
+	// preseeding mode
 	if m.preseed {
 		return nil
 	}
 
+	if err := m.ensureExpiredConnections(); err != nil {
+		logger.Noticef("Cannot disconnect expired connections: %v", err)
+	}
+
 	if m.udevMonitorDisabled {
 		return nil
 	}
@@ -415,6 +424,8 @@ type ConnectionState struct {
 	Auto bool
 	// ByGadget indicates whether the connection was trigged by the gadget
 	ByGadget bool
+	// ByModel indicates whether the connection was declared by the model
+	ByModel bool
 	// Interface name of the connection
 	Interface string
 	// Undesired indicates whether the connection, otherwise established
@@ -425,6 +436,12 @@ type ConnectionState struct {
 	StaticSlotAttrs  map[string]any
 	DynamicSlotAttrs map[string]any
 	HotplugGone      bool
+	// Expiry is the time after which the connection is automatically
+	// disconnected, if it's time-limited
+	Expiry time.Time
+	// Users holds the IDs of the users the connection is limited to, if
+	// any
+	Users []int
 }
 
 // Active returns true if connection is not undesired and not removed by
@@ -449,6 +466,7 @@ func ConnectionStates(st *state.State) (connStateByRef map[string]ConnectionStat
 		connStateByRef[cref] = ConnectionState{
 			Auto:             cstate.Auto,
 			ByGadget:         cstate.ByGadget,
+			ByModel:          cstate.ByModel,
 			Interface:        cstate.Interface,
 			Undesired:        cstate.Undesired,
 			StaticPlugAttrs:  cstate.StaticPlugAttrs,
@@ -456,6 +474,8 @@ func ConnectionStates(st *state.State) (connStateByRef map[string]ConnectionStat
 			StaticSlotAttrs:  cstate.StaticSlotAttrs,
 			DynamicSlotAttrs: cstate.DynamicSlotAttrs,
 			HotplugGone:      cstate.HotplugGone,
+			Expiry:           cstate.Expiry,
+			Users:            cstate.Users,
 		}
 	}
 	return connStateByRef, nil
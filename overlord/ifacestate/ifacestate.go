@@ -86,18 +86,68 @@ func findSymmetricAutoconnectTask(st *state.State, plugSnap, slotSnap string, in
 
 type connectOpts struct {
 	ByGadget    bool
+	ByModel     bool
 	AutoConnect bool
 
 	DelayedSetupProfiles bool
+
+	// Expiry is the time after which the connection is disconnected
+	// automatically.
+	Expiry time.Time
+
+	// Users holds the IDs of the users the connection is limited to.
+	Users []int
+}
+
+// ConnectOptions holds optional parameters of a manual connection.
+type ConnectOptions struct {
+	// Expiry, if set, makes the connection time-limited: it is
+	// disconnected automatically, running the regular disconnect hooks,
+	// once the expiry time is reached.
+	Expiry time.Time
+	// Users, if set, limits the connection to the users with the given
+	// IDs. Connecting a connection limited to other users adds the users
+	// to it, connecting it without users makes it apply to all the users.
+	Users []int
 }
 
 // Connect returns a set of tasks for connecting an interface.
 func Connect(st *state.State, plugSnap, plugName, slotSnap, slotName string) (*state.TaskSet, error) {
+	return ConnectWithOptions(st, plugSnap, plugName, slotSnap, slotName, nil)
+}
+
+// ConnectWithOptions returns a set of tasks for connecting an interface with
+// the given options.
+func ConnectWithOptions(st *state.State, plugSnap, plugName, slotSnap, slotName string, opts *ConnectOptions) (*state.TaskSet, error) {
+	if opts == nil {
+		opts = &ConnectOptions{}
+	}
+	if !opts.Expiry.IsZero() && !opts.Expiry.After(timeNow()) {
+		return nil, fmt.Errorf("cannot connect %s:%s to %s:%s: expiry time %s is in the past",
+			plugSnap, plugName, slotSnap, slotName, opts.Expiry.Format(time.RFC3339))
+	}
+
+	users, err := normalizeUsers(opts.Users)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) > 0 {
+		if err := checkConnectionUsersSupported(st, plugSnap, plugName); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := snapstate.CheckChangeConflictMany(st, []string{plugSnap, slotSnap}, ""); err != nil {
 		return nil, err
 	}
 
-	return connect(st, plugSnap, plugName, slotSnap, slotName, connectOpts{})
+	connRef := &interfaces.ConnRef{PlugRef: interfaces.PlugRef{Snap: plugSnap, Name: plugName}, SlotRef: interfaces.SlotRef{Snap: slotSnap, Name: slotName}}
+	ts, err := updateConnectionUsers(st, connRef, users, opts.Expiry)
+	if ts != nil || err != nil {
+		return ts, err
+	}
+
+	return connect(st, plugSnap, plugName, slotSnap, slotName, connectOpts{Expiry: opts.Expiry, Users: users})
 }
 
 func connect(st *state.State, plugSnap, plugName, slotSnap, slotName string, flags connectOpts) (*state.TaskSet, error) {
@@ -209,9 +259,18 @@ func connect(st *state.State, plugSnap, plugName, slotSnap, slotName string, fla
 	if flags.ByGadget {
 		connectInterface.Set("by-gadget", true)
 	}
+	if flags.ByModel {
+		connectInterface.Set("by-model", true)
+	}
 	if flags.DelayedSetupProfiles {
 		connectInterface.Set("delayed-setup-profiles", true)
 	}
+	if !flags.Expiry.IsZero() {
+		connectInterface.Set("expiry", flags.Expiry)
+	}
+	if len(flags.Users) > 0 {
+		connectInterface.Set("users", flags.Users)
+	}
 
 	// Expose a copy of all plug and slot attributes coming from yaml to interface hooks. The hooks will be able
 	// to modify them but all attributes will be checked against assertions after the hooks are run.
@@ -559,6 +618,9 @@ func delayedCrossMgrInit() {
 		// hook into conflict checks mechanisms
 		snapstate.RegisterAffectedSnapsByKind("connect", connectDisconnectAffectedSnaps)
 		snapstate.RegisterAffectedSnapsByKind("disconnect", connectDisconnectAffectedSnaps)
+		snapstate.RegisterAffectedSnapsByKind("set-connection-users", connectDisconnectAffectedSnaps)
+		snapstate.RegisterAffectedSnapsByKind("set-device-audit", deviceAuditAffectedSnaps)
+		snapstate.RegisterAffectedSnapsByKind("restore-connections", restoreConnectionsAffectedSnaps)
 
 		// hook into snap linking/unlinking and activation state changes
 		snapstate.AddLinkSnapParticipant(snapstate.LinkSnapParticipantFunc(OnSnapLinkageChanged))
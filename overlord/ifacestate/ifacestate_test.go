@@ -41,6 +41,7 @@ import (
 	"github.com/snapcore/snapd/interfaces/builtin"
 	"github.com/snapcore/snapd/interfaces/hotplug"
 	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/interfaces/inspect"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/overlord"
@@ -1979,6 +1980,111 @@ components:
 	})
 }
 
+func (s *interfaceManagerSuite) TestRestoreConnections(c *C) {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, consumer2Yaml)
+	s.mockSnap(c, producerYaml)
+
+	// consumer2 got connected since the snap set was recorded, while
+	// the connection of consumer went away
+	s.state.Lock()
+	s.state.Set("conns", map[string]any{
+		"consumer2:plug producer:slot": map[string]any{"interface": "test", "auto": true},
+	})
+	s.state.Unlock()
+
+	mgr := s.manager(c)
+
+	s.state.Lock()
+	chg := s.state.NewChange("revert-snap-set", "...")
+	t := s.state.NewTask("restore-connections", "...")
+	t.Set("snaps", []string{"consumer", "consumer2"})
+	t.Set("connections", []snapstate.SnapSetConnection{
+		{ID: "consumer:plug producer:slot", Interface: "test"},
+		{ID: "consumer:otherplug gone:slot", Interface: "test2"},
+	})
+	chg.AddTask(t)
+	s.state.Unlock()
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(chg.Err(), IsNil)
+	c.Check(t.Status(), Equals, state.DoneStatus)
+	c.Check(strings.Join(t.Log(), ""), Matches, `.*Cannot restore connection consumer:otherplug gone:slot: plug or slot is no longer available`)
+
+	var conns map[string]any
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, DeepEquals, map[string]any{
+		"consumer:plug producer:slot": map[string]any{
+			"interface":   "test",
+			"plug-static": map[string]any{"attr1": "value1"},
+			"slot-static": map[string]any{"attr2": "value2"},
+		},
+	})
+
+	ifaces := mgr.Repository().Interfaces()
+	c.Assert(ifaces.Connections, HasLen, 1)
+	c.Check(ifaces.Connections[0].PlugRef.Snap, Equals, "consumer")
+}
+
+func (s *interfaceManagerSuite) TestRestoreConnectionsUndo(c *C) {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, consumer2Yaml)
+	s.mockSnap(c, producerYaml)
+
+	connState := map[string]any{
+		"consumer2:plug producer:slot": map[string]any{"interface": "test", "auto": true},
+	}
+	s.state.Lock()
+	s.state.Set("conns", connState)
+	s.state.Unlock()
+
+	mgr := s.manager(c)
+
+	s.state.Lock()
+	chg := s.state.NewChange("revert-snap-set", "...")
+	lane := s.state.NewLane()
+	t := s.state.NewTask("restore-connections", "...")
+	t.Set("snaps", []string{"consumer", "consumer2"})
+	t.Set("connections", []snapstate.SnapSetConnection{
+		{ID: "consumer:plug producer:slot", Interface: "test"},
+	})
+	t.JoinLane(lane)
+	chg.AddTask(t)
+	terr := s.state.NewTask("error-trigger", "provoking total undo")
+	terr.WaitFor(t)
+	terr.JoinLane(lane)
+	chg.AddTask(terr)
+	s.state.Unlock()
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(chg.Err(), NotNil)
+	c.Check(t.Status(), Equals, state.UndoneStatus)
+	var old []snapstate.SnapSetConnection
+	c.Assert(t.Get("old-connections", &old), IsNil)
+	c.Check(old, DeepEquals, []snapstate.SnapSetConnection{
+		{ID: "consumer2:plug producer:slot", Interface: "test", Auto: true},
+	})
+
+	var conns map[string]any
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, HasLen, 1)
+	c.Check(conns["consumer2:plug producer:slot"], NotNil)
+
+	ifaces := mgr.Repository().Interfaces()
+	c.Assert(ifaces.Connections, HasLen, 1)
+	c.Check(ifaces.Connections[0].PlugRef.Snap, Equals, "consumer2")
+}
+
 func (s *interfaceManagerSuite) TestForgetUndo(c *C) {
 	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
 
@@ -12820,7 +12926,7 @@ func (s *interfaceManagerSuite) TestSystemKeyMismatchCompat(c *C) {
 }
 
 func (s *interfaceManagerSuite) TestEnsureLoopLogging(c *C) {
-	swfeatstest.CheckEnsureLoopLogging("ifacemgr.go", c, false)
+	swfeatstest.CheckEnsureLoopLogging("ifacemgr.go", c, true)
 }
 
 func (s *interfaceManagerSuite) setCompatEnabledFeature(c *C) {
@@ -15081,3 +15187,83 @@ func (s *interfaceManagerSuite) TestDelayedEffectsHandlingOfRestartRequestsNotBr
 	c.Check(chg.Err(), ErrorMatches, `cannot perform the following tasks:\n.*inject error for "producer".*`)
 	c.Check(processTask.Status(), Equals, state.DoneStatus)
 }
+
+// renderingSecurityBackend renders the snippets of a test specification as
+// a single profile.
+type renderingSecurityBackend struct {
+	ifacetest.TestSecurityBackend
+}
+
+func (b *renderingSecurityBackend) SnapContributions(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) []*interfaces.SpecificationContribution {
+	return nil
+}
+
+func (b *renderingSecurityBackend) RenderProfiles(spec interfaces.Specification, appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions) (map[string][]byte, error) {
+	content := fmt.Sprintf("# devmode: %v\n%s\n", opts.DevMode, strings.Join(spec.(*ifacetest.Specification).Snippets, "\n"))
+	return map[string][]byte{appSet.InstanceName(): []byte(content)}, nil
+}
+
+func (s *interfaceManagerSuite) TestSnapProfiles(c *C) {
+	s.mockIfaces(&ifacetest.TestInterface{
+		InterfaceName: "test",
+		TestPermanentPlugCallback: func(spec *ifacetest.Specification, plug *snap.PlugInfo) error {
+			spec.AddSnippet("permanent " + plug.Name)
+			return nil
+		},
+		TestConnectedPlugCallback: func(spec *ifacetest.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+			spec.AddSnippet("connected " + plug.Name())
+			return nil
+		},
+	}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.extraBackends = []interfaces.SecurityBackend{&renderingSecurityBackend{
+		TestSecurityBackend: ifacetest.TestSecurityBackend{BackendName: "rendering"},
+	}}
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+	mgr := s.manager(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	plugOrigin := interfaces.SpecificationOrigin{
+		Kind:      interfaces.OriginPlug,
+		Interface: "test",
+		Plug:      &interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+	}
+	profiles, err := mgr.SnapProfiles("consumer", nil)
+	c.Assert(err, IsNil)
+	c.Check(profiles, DeepEquals, []*inspect.Profile{{
+		Backend: "rendering",
+		Name:    "consumer",
+		Lines: []inspect.Line{
+			{Text: "# devmode: false", Origins: []interfaces.SpecificationOrigin{{Kind: interfaces.OriginBaseTemplate}}},
+			{Text: "permanent plug", Origins: []interfaces.SpecificationOrigin{plugOrigin}},
+		},
+	}})
+
+	// a prospective connection
+	ref := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}
+	profiles, err = mgr.SnapProfiles("consumer", ref)
+	c.Assert(err, IsNil)
+	c.Check(profiles, DeepEquals, []*inspect.Profile{{
+		Backend: "rendering",
+		Name:    "consumer",
+		Lines: []inspect.Line{{
+			Text: "connected plug",
+			Origins: []interfaces.SpecificationOrigin{{
+				Kind:      interfaces.OriginConnectedPlug,
+				Interface: "test",
+				Plug:      &interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+				Slot:      &interfaces.SlotRef{Snap: "producer", Name: "slot"},
+			}},
+		}},
+	}})
+	// which is not made
+	c.Check(mgr.Repository().Interfaces().Connections, HasLen, 0)
+
+	_, err = mgr.SnapProfiles("missing", nil)
+	c.Check(errors.Is(err, state.ErrNoState), Equals, true)
+}
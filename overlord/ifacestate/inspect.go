@@ -0,0 +1,56 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate
+
+import (
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/inspect"
+	"github.com/snapcore/snapd/overlord/snapstate"
+)
+
+// SnapProfiles returns the effective security profiles of the current
+// revision of the given snap, with every line annotated with its origin. If
+// a connection is given, only the lines that connecting it would add are
+// returned, without connecting it.
+//
+// The state must be locked by the caller.
+func (m *InterfaceManager) SnapProfiles(instanceName string, conn *interfaces.ConnRef) ([]*inspect.Profile, error) {
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(m.state, instanceName, &snapst); err != nil {
+		return nil, err
+	}
+	snapInfo, err := snapst.CurrentInfo()
+	if err != nil {
+		return nil, err
+	}
+	appSet, err := appSetForSnapRevision(m.state, snapInfo)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := m.buildConfinementOptions(m.state, nil, snapInfo, snapst.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn != nil {
+		return inspect.ConnectionProfiles(m.repo, appSet, opts, conn)
+	}
+	return inspect.Profiles(m.repo, appSet, opts)
+}
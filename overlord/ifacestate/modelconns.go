@@ -0,0 +1,227 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/overlord/ifacestate/schema"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+// The model can declare interface connections that are required on the
+// device. They are established like the connections from the gadget:
+// during seeding and remodeling, obeying the "connection" rules of the
+// snap declarations. Connections that cannot be made are reported with a
+// warning instead of failing the change.
+
+// errModelConnection is returned when a connection declared by the model
+// cannot be made.
+type errModelConnection struct {
+	conn   *asserts.ModelConnection
+	reason string
+}
+
+func (e *errModelConnection) Error() string {
+	return fmt.Sprintf("cannot connect %s as declared by the model: %s", e.conn, e.reason)
+}
+
+func modelConnectionSnaps(mconn *asserts.ModelConnection) (plugSnap, slotSnap string) {
+	slotSnap = mconn.SlotSnap
+	if slotSnap == "system" {
+		slotSnap = SystemSnapName()
+	}
+	return mconn.PlugSnap, slotSnap
+}
+
+// resolveModelConnection returns the reference of the connection declared
+// by the model, after checking that it can be made.
+func resolveModelConnection(st *state.State, repo *interfaces.Repository, checker *connectChecker, mconn *asserts.ModelConnection) (*interfaces.ConnRef, error) {
+	plugSnap, slotSnap := modelConnectionSnaps(mconn)
+	plug := repo.Plug(plugSnap, mconn.Plug)
+	if plug == nil {
+		return nil, &errModelConnection{conn: mconn, reason: fmt.Sprintf("snap %q has no plug named %q", plugSnap, mconn.Plug)}
+	}
+	slot := repo.Slot(slotSnap, mconn.Slot)
+	if slot == nil {
+		return nil, &errModelConnection{conn: mconn, reason: fmt.Sprintf("snap %q has no slot named %q", slotSnap, mconn.Slot)}
+	}
+	if plug.Interface != slot.Interface {
+		return nil, &errModelConnection{conn: mconn, reason: fmt.Sprintf("plug interface %q does not match slot interface %q", plug.Interface, slot.Interface)}
+	}
+
+	plugAppSet, err := appSetForSnapRevision(st, plug.Snap)
+	if err != nil {
+		return nil, fmt.Errorf("building app set for snap %q: %v", plugSnap, err)
+	}
+	slotAppSet, err := appSetForSnapRevision(st, slot.Snap)
+	if err != nil {
+		return nil, fmt.Errorf("building app set for snap %q: %v", slotSnap, err)
+	}
+	cplug := interfaces.NewConnectedPlug(plug, plugAppSet, nil, nil)
+	cslot := interfaces.NewConnectedSlot(slot, slotAppSet, nil, nil)
+	if ok, err := checker.check(cplug, cslot); !ok || err != nil {
+		reason := "not allowed"
+		if err != nil {
+			reason = err.Error()
+		}
+		return nil, &errModelConnection{conn: mconn, reason: reason}
+	}
+	return interfaces.NewConnRef(plug, slot), nil
+}
+
+func warnModelConnection(st *state.State, task *state.Task, err *errModelConnection) {
+	task.Logf("%s", err)
+	st.Warnf("%s", err)
+}
+
+// addModelConnections adds to newconns any applicable connections
+// declared by the model that involve the snap.
+// conflictError is called to handle checkAutoconnectConflicts errors.
+func (gc *gadgetConnect) addModelConnections(newconns map[string]*interfaces.ConnRef, conns map[string]*schema.ConnState, conflictError func(*state.Retry, error) error) error {
+	mconns := gc.deviceCtx.Model().Connections()
+	if len(mconns) == 0 {
+		return nil
+	}
+
+	var seeded bool
+	err := gc.st.Get("seeded", &seeded)
+	if err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	// like the gadget ones, model connections are applied only during
+	// seeding or a remodeling
+	if seeded && !gc.deviceCtx.ForRemodeling() {
+		return nil
+	}
+
+	var checker *connectChecker
+	for _, mconn := range mconns {
+		plugSnap, slotSnap := modelConnectionSnaps(mconn)
+		var otherSnap string
+		switch gc.instanceName {
+		case plugSnap:
+			otherSnap = slotSnap
+		case slotSnap:
+			otherSnap = plugSnap
+		default:
+			continue
+		}
+		// the connection is considered again once the other snap is
+		// installed
+		var snapst snapstate.SnapState
+		if err := snapstate.Get(gc.st, otherSnap, &snapst); errors.Is(err, state.ErrNoState) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if checker == nil {
+			checker, err = newConnectChecker(gc.st, gc.deviceCtx)
+			if err != nil {
+				return err
+			}
+		}
+		connRef, err := resolveModelConnection(gc.st, gc.repo, checker, mconn)
+		if err != nil {
+			var mcErr *errModelConnection
+			if errors.As(err, &mcErr) {
+				warnModelConnection(gc.st, gc.task, mcErr)
+				continue
+			}
+			return err
+		}
+		plug := gc.repo.Plug(connRef.PlugRef.Snap, connRef.PlugRef.Name)
+		slot := gc.repo.Slot(connRef.SlotRef.Snap, connRef.SlotRef.Name)
+		if err := addNewConnection(gc.st, gc.task, newconns, conns, plug, slot, conflictError); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// doConnectModelInterfaces establishes the connections declared by the
+// model that are still missing, it runs at the end of a remodel for the
+// snaps that were not installed or refreshed by it.
+func (m *InterfaceManager) doConnectModelInterfaces(task *state.Task, _ *tomb.Tomb) error {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	deviceCtx, err := snapstate.DeviceCtx(st, task, nil)
+	if err != nil {
+		return err
+	}
+	mconns := deviceCtx.Model().Connections()
+	if len(mconns) == 0 {
+		return nil
+	}
+
+	conns, err := getConns(st)
+	if err != nil {
+		return err
+	}
+	checker, err := newConnectChecker(st, deviceCtx)
+	if err != nil {
+		return err
+	}
+
+	ts := state.NewTaskSet()
+	for _, mconn := range mconns {
+		connRef, err := resolveModelConnection(st, m.repo, checker, mconn)
+		if err != nil {
+			var mcErr *errModelConnection
+			if errors.As(err, &mcErr) {
+				warnModelConnection(st, task, mcErr)
+				continue
+			}
+			return err
+		}
+		if _, ok := conns[connRef.ID()]; ok {
+			// already connected, or disconnected on purpose
+			continue
+		}
+		if err := checkAutoconnectConflicts(st, task, connRef.PlugRef.Snap, connRef.SlotRef.Snap); err != nil {
+			if retry, ok := err.(*state.Retry); ok {
+				task.Logf("Waiting for conflicting change in progress: %s", retry.Reason)
+				return retry
+			}
+			return fmt.Errorf("model connections conflict check failed: %v", err)
+		}
+		connectTs, err := connect(st, connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name, connectOpts{AutoConnect: true, ByModel: true})
+		if err != nil {
+			return fmt.Errorf("internal error: connect of %q failed: %s", connRef, err)
+		}
+		ts.AddAll(connectTs)
+	}
+
+	if len(ts.Tasks()) > 0 {
+		snapstate.InjectTasks(task, ts)
+		st.EnsureBefore(0)
+	}
+	task.SetStatus(state.DoneStatus)
+	return nil
+}
@@ -0,0 +1,312 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/snap"
+)
+
+func modelConnectionsHeaders(plug, slot string) map[string]any {
+	return map[string]any{
+		"format":         "1",
+		"required-snaps": []any{"consumer", "producer"},
+		"connections": []any{
+			map[string]any{"plug": plug, "slot": slot},
+		},
+	}
+}
+
+func (s *interfaceManagerSuite) setupModelConnections(c *C, baseDecl string) {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"})
+
+	r := s.mockBaseDeclaration(c, s.state, []byte(baseDecl))
+	s.AddCleanup(r)
+
+	s.MockSnapDecl(c, "consumer", "publisher1", nil)
+	s.mockSnap(c, consumerYaml)
+	s.MockSnapDecl(c, "producer", "publisher2", nil)
+	s.mockSnap(c, producerYaml)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	s.state.Set("seeded", nil)
+}
+
+const denyAutoConnectionBaseDecl = `
+type: base-declaration
+account-id: system
+authority-id: canonical
+series: 16
+slots:
+  test:
+    deny-auto-connection: true
+`
+
+func (s *interfaceManagerSuite) runAutoConnect(c *C, snapName string) *state.Change {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chg := s.state.NewChange("setting-up", "...")
+	t := s.state.NewTask("auto-connect", "model connections")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: snapName,
+			Revision: snap.R(1),
+		},
+	})
+	chg.AddTask(t)
+
+	s.state.Unlock()
+	s.se.Ensure()
+	s.se.Wait()
+	s.state.Lock()
+	return chg
+}
+
+func checkAutoConnectModelTasks(c *C, tasks []*state.Task) {
+	gotConnect := false
+	for _, t := range tasks {
+		if t.Kind() != "connect" {
+			continue
+		}
+		gotConnect = true
+		var autoConnect, byModel bool
+		c.Assert(t.Get("auto", &autoConnect), IsNil)
+		c.Assert(t.Get("by-model", &byModel), IsNil)
+		c.Check(autoConnect, Equals, true)
+		c.Check(byModel, Equals, true)
+		// model connections are told apart from the gadget ones
+		c.Check(t.Has("by-gadget"), Equals, false)
+
+		var plug interfaces.PlugRef
+		c.Assert(t.Get("plug", &plug), IsNil)
+		c.Check(plug, Equals, interfaces.PlugRef{Snap: "consumer", Name: "plug"})
+		var slot interfaces.SlotRef
+		c.Assert(t.Get("slot", &slot), IsNil)
+		c.Check(slot, Equals, interfaces.SlotRef{Snap: "producer", Name: "slot"})
+	}
+	c.Assert(gotConnect, Equals, true)
+}
+
+func (s *interfaceManagerSuite) testAutoConnectModelConnections(c *C, snapName string) {
+	r1 := release.MockOnClassic(false)
+	defer r1()
+
+	s.setupModelConnections(c, denyAutoConnectionBaseDecl)
+	s.MockModel(c, modelConnectionsHeaders("consumer:plug", "producer:slot"))
+	s.manager(c)
+
+	chg := s.runAutoConnect(c, snapName)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(chg.Err(), IsNil)
+	tasks := chg.Tasks()
+	c.Assert(tasks, HasLen, 7)
+	checkAutoConnectModelTasks(c, tasks)
+	c.Check(s.state.AllWarnings(), HasLen, 0)
+
+	s.state.Unlock()
+	s.settle(c)
+	s.state.Lock()
+
+	c.Assert(chg.Err(), IsNil)
+	connStates, err := ifacestate.ConnectionStates(s.state)
+	c.Assert(err, IsNil)
+	connState := connStates["consumer:plug producer:slot"]
+	c.Check(connState.Auto, Equals, true)
+	c.Check(connState.ByModel, Equals, true)
+	c.Check(connState.ByGadget, Equals, false)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectModelConnectionsPlugSnap(c *C) {
+	s.testAutoConnectModelConnections(c, "consumer")
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectModelConnectionsSlotSnap(c *C) {
+	s.testAutoConnectModelConnections(c, "producer")
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectModelConnectionsSeededNoop(c *C) {
+	r1 := release.MockOnClassic(false)
+	defer r1()
+
+	s.setupModelConnections(c, denyAutoConnectionBaseDecl)
+	s.MockModel(c, modelConnectionsHeaders("consumer:plug", "producer:slot"))
+	s.manager(c)
+
+	s.state.Lock()
+	s.state.Set("seeded", true)
+	s.state.Unlock()
+
+	chg := s.runAutoConnect(c, "consumer")
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(chg.Err(), IsNil)
+	// only setup-profiles was injected
+	c.Check(chg.Tasks(), HasLen, 2)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectModelConnectionsWaitsForOtherSnap(c *C) {
+	r1 := release.MockOnClassic(false)
+	defer r1()
+
+	s.setupModelConnections(c, denyAutoConnectionBaseDecl)
+	s.MockModel(c, modelConnectionsHeaders("consumer:plug", "producer:slot"))
+	s.manager(c)
+
+	// the producer is not installed yet
+	s.state.Lock()
+	snapstate.Set(s.state, "producer", nil)
+	s.state.Unlock()
+
+	chg := s.runAutoConnect(c, "consumer")
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(chg.Err(), IsNil)
+	c.Check(chg.Tasks(), HasLen, 2)
+	c.Check(s.state.AllWarnings(), HasLen, 0)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectModelConnectionsWarnings(c *C) {
+	r1 := release.MockOnClassic(false)
+	defer r1()
+
+	s.setupModelConnections(c, `
+type: base-declaration
+account-id: system
+authority-id: canonical
+series: 16
+slots:
+  test:
+    deny-auto-connection: true
+    deny-connection: true
+`)
+	s.manager(c)
+
+	for _, tc := range []struct {
+		plug, slot string
+		warning    string
+	}{{
+		plug:    "consumer:plug",
+		slot:    "producer:missing",
+		warning: `cannot connect consumer:plug producer:missing as declared by the model: snap "producer" has no slot named "missing"`,
+	}, {
+		plug:    "consumer:missing",
+		slot:    "producer:slot",
+		warning: `cannot connect consumer:missing producer:slot as declared by the model: snap "consumer" has no plug named "missing"`,
+	}, {
+		plug:    "consumer:plug",
+		slot:    "producer:slot",
+		warning: `cannot connect consumer:plug producer:slot as declared by the model: connection denied by slot rule of interface "test"`,
+	}} {
+		restore := snapstatetest.MockDeviceModel(s.mockModel(modelConnectionsHeaders(tc.plug, tc.slot)))
+
+		chg := s.runAutoConnect(c, "consumer")
+
+		s.state.Lock()
+		c.Assert(chg.Err(), IsNil)
+		c.Check(chg.Tasks(), HasLen, 2)
+		warnings := s.state.AllWarnings()
+		c.Assert(warnings, HasLen, 1)
+		c.Check(warnings[0].String(), Equals, tc.warning)
+		for _, w := range warnings {
+			s.state.RemoveWarning(w.String())
+		}
+		// let the next change proceed
+		for _, t := range chg.Tasks() {
+			t.SetStatus(state.DoneStatus)
+		}
+		s.state.Unlock()
+		restore()
+	}
+}
+
+func (s *interfaceManagerSuite) TestConnectModelInterfaces(c *C) {
+	r1 := release.MockOnClassic(false)
+	defer r1()
+
+	s.setupModelConnections(c, denyAutoConnectionBaseDecl)
+	s.mockSnap(c, `name: other
+version: 1
+plugs:
+  plug:
+    interface: test
+`)
+	s.MockSnapDecl(c, "other", "publisher1", nil)
+	s.manager(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	s.state.Set("seeded", true)
+
+	// the connection of the other snap exists already
+	s.state.Set("conns", map[string]any{
+		"other:plug producer:slot": map[string]any{"interface": "test", "auto": true},
+	})
+
+	headers := modelConnectionsHeaders("consumer:plug", "producer:slot")
+	headers["required-snaps"] = []any{"consumer", "producer", "other"}
+	headers["connections"] = append(headers["connections"].([]any), map[string]any{
+		"plug": "other:plug", "slot": "producer:slot",
+	})
+	remodCtx := s.TrivialDeviceContext(c, headers)
+	remodCtx.Remodeling = true
+	r2 := snapstatetest.MockDeviceContext(remodCtx)
+	defer r2()
+
+	chg := s.state.NewChange("remodel", "...")
+	t := s.state.NewTask("connect-model-interfaces", "...")
+	chg.AddTask(t)
+
+	s.state.Unlock()
+	s.se.Ensure()
+	s.se.Wait()
+	s.state.Lock()
+
+	c.Assert(chg.Err(), IsNil)
+	c.Check(t.Status(), Equals, state.DoneStatus)
+
+	var connects []*state.Task
+	for _, ct := range chg.Tasks() {
+		if ct.Kind() == "connect" {
+			connects = append(connects, ct)
+		}
+	}
+	c.Assert(connects, HasLen, 1)
+	var plug interfaces.PlugRef
+	c.Assert(connects[0].Get("plug", &plug), IsNil)
+	c.Check(plug, Equals, interfaces.PlugRef{Snap: "consumer", Name: "plug"})
+	var byModel bool
+	c.Assert(connects[0].Get("by-model", &byModel), IsNil)
+	c.Check(byModel, Equals, true)
+	c.Check(connects[0].Has("by-gadget"), Equals, false)
+}
@@ -20,7 +20,11 @@
 // Package schema holds structs for reading and writing interface-related state data.
 package schema
 
-import "github.com/snapcore/snapd/snap"
+import (
+	"time"
+
+	"github.com/snapcore/snapd/snap"
+)
 
 // ConnState holds properties of an interface connection.
 //
@@ -30,6 +34,7 @@ import "github.com/snapcore/snapd/snap"
 type ConnState struct {
 	Auto      bool   `json:"auto,omitempty" yaml:"auto"`
 	ByGadget  bool   `json:"by-gadget,omitempty" yaml:"by-gadget"`
+	ByModel   bool   `json:"by-model,omitempty" yaml:"by-model"`
 	Interface string `json:"interface,omitempty" yaml:"interface"`
 	// Undesired tracks connections that were manually disconnected after being auto-connected,
 	// so that they are not automatically reconnected again in the future.
@@ -45,4 +50,10 @@ type ConnState struct {
 	// slots.
 	HotplugGone bool            `json:"hotplug-gone,omitempty" yaml:"hotplug-gone,omitempty"`
 	HotplugKey  snap.HotplugKey `json:"hotplug-key,omitempty" yaml:"hotplug-key,omitempty"`
+	// Expiry is the time after which a time-limited connection is
+	// automatically disconnected; it's zero for regular connections.
+	Expiry time.Time `json:"expiry,omitzero" yaml:"expiry,omitempty"`
+	// Users holds the IDs of the users a connection is limited to; it's
+	// empty for connections of all the users.
+	Users []int `json:"users,omitempty" yaml:"users,omitempty"`
 }
@@ -0,0 +1,293 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/ifacestate/ifacerepo"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+// Connections can be limited to some users. On AppArmor systems the grant
+// of such connections is only part of the variants of the profiles of the
+// plug side for these users, snap-confine picks the variant of the user
+// running the application. The rules of other snaps naming the exact label
+// of an application, not a pattern, do not match its variants. On Landlock
+// systems the access granted by the connection is only kept in the rulesets
+// of its users when the application starts. This only holds for interfaces
+// granting nothing but AppArmor and Landlock rules for accessing files, the
+// seccomp profiles being shared by all the users of a snap.
+
+// userLimitableInterfaces are the interfaces whose whole grant is expressed
+// by AppArmor and Landlock rules for accessing files.
+var userLimitableInterfaces = map[string]bool{
+	"home":            true,
+	"removable-media": true,
+	"system-files":    true,
+}
+
+// MockUserLimitableInterfaces mocks the interfaces whose connections can be
+// limited to some users.
+//
+// This function is public because it is referenced in the daemon
+func MockUserLimitableInterfaces(names ...string) (restore func()) {
+	old := userLimitableInterfaces
+	userLimitableInterfaces = make(map[string]bool, len(names))
+	for _, name := range names {
+		userLimitableInterfaces[name] = true
+	}
+	return func() { userLimitableInterfaces = old }
+}
+
+// checkConnectionUsersSupported checks that connections of the given plug
+// can be limited to some users.
+func checkConnectionUsersSupported(st *state.State, plugSnap, plugName string) error {
+	plug := ifacerepo.Get(st).Plug(plugSnap, plugName)
+	if plug == nil {
+		return fmt.Errorf("snap %q has no plug named %q", plugSnap, plugName)
+	}
+	if !userLimitableInterfaces[plug.Interface] {
+		return fmt.Errorf("cannot limit connection to users: interface %q does not support it", plug.Interface)
+	}
+	return nil
+}
+
+// normalizeUsers returns the sorted list of the given user IDs without
+// duplicates.
+func normalizeUsers(users []int) ([]int, error) {
+	if len(users) == 0 {
+		return nil, nil
+	}
+	seen := make(map[int]bool, len(users))
+	normalized := make([]int, 0, len(users))
+	for _, uid := range users {
+		if uid < 0 {
+			return nil, fmt.Errorf("invalid user ID %d", uid)
+		}
+		if !seen[uid] {
+			seen[uid] = true
+			normalized = append(normalized, uid)
+		}
+	}
+	sort.Ints(normalized)
+	return normalized, nil
+}
+
+func formatUsers(users []int) string {
+	uids := make([]string, len(users))
+	for i, uid := range users {
+		uids[i] = strconv.Itoa(uid)
+	}
+	return strings.Join(uids, ",")
+}
+
+func sameUsers(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// updateConnectionUsers returns the tasks to change the users of an existing
+// connection limited to some users, if the given users are not already all
+// covered. It returns nil tasks and no error if the connection does not
+// exist or applies to all the users already.
+func updateConnectionUsers(st *state.State, connRef *interfaces.ConnRef, users []int, expiry time.Time) (*state.TaskSet, error) {
+	conns, err := getConns(st)
+	if err != nil {
+		return nil, err
+	}
+	connState, ok := conns[connRef.ID()]
+	if !ok || connState.Undesired || connState.HotplugGone || len(connState.Users) == 0 {
+		return nil, nil
+	}
+
+	var newUsers []int
+	if len(users) > 0 {
+		newUsers, err = normalizeUsers(append(append([]int(nil), connState.Users...), users...))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sameUsers(newUsers, connState.Users) {
+		return nil, &ErrAlreadyConnected{Connection: *connRef}
+	}
+	if !expiry.IsZero() {
+		return nil, fmt.Errorf("cannot set expiry of existing connection %s", connRef.ID())
+	}
+	return setConnectionUsersTasks(st, connRef, newUsers), nil
+}
+
+func setConnectionUsersTasks(st *state.State, connRef *interfaces.ConnRef, users []int) *state.TaskSet {
+	var summary string
+	if len(users) == 0 {
+		summary = fmt.Sprintf(i18n.G("Connect %s:%s to %s:%s for all users"),
+			connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name)
+	} else {
+		summary = fmt.Sprintf(i18n.G("Limit connection of %s:%s to %s:%s to users %s"),
+			connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name, formatUsers(users))
+	}
+	task := st.NewTask("set-connection-users", summary)
+	task.Set("plug", connRef.PlugRef)
+	task.Set("slot", connRef.SlotRef)
+	task.Set("users", users)
+	return state.NewTaskSet(task)
+}
+
+// DisconnectForUsers returns a set of tasks for disconnecting an interface
+// for the given users only. The connection must be limited to some users,
+// it is disconnected completely once it's no longer connected for any user.
+func DisconnectForUsers(st *state.State, conn *interfaces.Connection, users []int) (*state.TaskSet, error) {
+	connRef := &interfaces.ConnRef{PlugRef: *conn.Plug.Ref(), SlotRef: *conn.Slot.Ref()}
+	current := conn.Plug.Users()
+	if len(current) == 0 {
+		return nil, fmt.Errorf("cannot disconnect %s for some users only: connection is not limited to users", connRef.ID())
+	}
+
+	drop := make(map[int]bool, len(users))
+	for _, uid := range users {
+		drop[uid] = true
+	}
+	var remaining []int
+	for _, uid := range current {
+		if !drop[uid] {
+			remaining = append(remaining, uid)
+		}
+	}
+	if len(remaining) == len(current) {
+		return nil, fmt.Errorf("cannot disconnect %s: not connected for users %s", connRef.ID(), formatUsers(users))
+	}
+	if len(remaining) == 0 {
+		return Disconnect(st, conn)
+	}
+
+	if err := snapstate.CheckChangeConflictMany(st, []string{connRef.PlugRef.Snap, connRef.SlotRef.Snap}, ""); err != nil {
+		return nil, err
+	}
+	return setConnectionUsersTasks(st, connRef, remaining), nil
+}
+
+func (m *InterfaceManager) doSetConnectionUsers(task *state.Task, _ *tomb.Tomb) error {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var users []int
+	if err := task.Get("users", &users); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	oldUsers, err := m.setConnectionUsers(task, users)
+	if err != nil {
+		return err
+	}
+	task.Set("old-users", oldUsers)
+	return nil
+}
+
+func (m *InterfaceManager) undoSetConnectionUsers(task *state.Task, _ *tomb.Tomb) error {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var oldUsers []int
+	if err := task.Get("old-users", &oldUsers); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	_, err := m.setConnectionUsers(task, oldUsers)
+	return err
+}
+
+// setConnectionUsers limits the connection of the task to the given users
+// and sets up the security of the plug side again. It returns the users the
+// connection was limited to before.
+func (m *InterfaceManager) setConnectionUsers(task *state.Task, users []int) (oldUsers []int, err error) {
+	st := task.State()
+	perfTimings := state.TimingsForTask(task)
+	defer perfTimings.Save(st)
+
+	plugRef, slotRef, err := getPlugAndSlotRefs(task)
+	if err != nil {
+		return nil, err
+	}
+	connRef := &interfaces.ConnRef{PlugRef: plugRef, SlotRef: slotRef}
+
+	conns, err := getConns(st)
+	if err != nil {
+		return nil, err
+	}
+	connState, ok := conns[connRef.ID()]
+	if !ok || connState.Undesired || connState.HotplugGone {
+		return nil, fmt.Errorf("internal error: connection %q not found in state", connRef.ID())
+	}
+	oldUsers = connState.Users
+
+	if err := m.repo.SetConnectionUsers(connRef, users); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if err := m.repo.SetConnectionUsers(connRef, oldUsers); err != nil {
+				logger.Noticef("cannot restore users of connection %s: %v", connRef.ID(), err)
+			}
+		}
+	}()
+
+	// only the security of the plug side depends on the users
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, plugRef.Snap, &snapst); err != nil {
+		return nil, err
+	}
+	snapInfo, err := snapst.CurrentInfo()
+	if err != nil {
+		return nil, err
+	}
+	appSet, err := appSetForSnapRevision(st, snapInfo)
+	if err != nil {
+		return nil, fmt.Errorf("building app set for snap %q: %v", snapInfo.InstanceName(), err)
+	}
+	opts, err := m.buildConfinementOptions(st, task, snapInfo, snapst.Flags)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.setupSnapSecurity(task, appSet, opts, perfTimings); err != nil {
+		return nil, err
+	}
+
+	connState.Users = users
+	setConns(st, conns)
+	return oldUsers, nil
+}
@@ -0,0 +1,274 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces/ifacetest"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+func (s *interfaceManagerSuite) TestConnectForUsers(c *C) {
+	restore := ifacestate.MockUserLimitableInterfaces("test")
+	defer restore()
+
+	s.MockModel(c, nil)
+
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+	mgr := s.manager(c)
+
+	s.state.Lock()
+
+	ts, err := ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{
+		Users: []int{1001, 1000, 1001},
+	})
+	c.Assert(err, IsNil)
+	connectTask := ts.Tasks()[2]
+	c.Assert(connectTask.Kind(), Equals, "connect")
+	var users []int
+	c.Assert(connectTask.Get("users", &users), IsNil)
+	c.Check(users, DeepEquals, []int{1000, 1001})
+
+	connectTask.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "consumer",
+		},
+	})
+	change := s.state.NewChange("connect", "")
+	change.AddAll(ts)
+	s.state.Unlock()
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(change.Err(), IsNil)
+	var conns map[string]any
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, DeepEquals, map[string]any{
+		"consumer:plug producer:slot": map[string]any{
+			"interface":   "test",
+			"plug-static": map[string]any{"attr1": "value1"},
+			"slot-static": map[string]any{"attr2": "value2"},
+			"users":       []any{1000.0, 1001.0},
+		},
+	})
+	connStates, err := ifacestate.ConnectionStates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(connStates["consumer:plug producer:slot"].Users, DeepEquals, []int{1000, 1001})
+
+	conn := s.getConnection(c, "consumer", "plug", "producer", "slot")
+	c.Check(conn.Plug.Users(), DeepEquals, []int{1000, 1001})
+	c.Check(mgr.Repository().Interfaces().Connections, HasLen, 1)
+}
+
+func (s *interfaceManagerSuite) TestConnectForUsersUnsupportedInterface(c *C) {
+	restore := ifacestate.MockUserLimitableInterfaces("test2")
+	defer restore()
+
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+	_ = s.manager(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// the grant of the interface is not limited to rules for accessing files
+	_, err := ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{Users: []int{1000}})
+	c.Check(err, ErrorMatches, `cannot limit connection to users: interface "test" does not support it`)
+	_, err = ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{Users: []int{-1}})
+	c.Check(err, ErrorMatches, `invalid user ID -1`)
+	_, err = ifacestate.ConnectWithOptions(s.state, "consumer", "missing", "producer", "slot", &ifacestate.ConnectOptions{Users: []int{1000}})
+	c.Check(err, ErrorMatches, `snap "consumer" has no plug named "missing"`)
+}
+
+func (s *interfaceManagerSuite) mockConnectionForUsers(c *C, users []int) {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	s.state.Lock()
+	s.state.Set("conns", map[string]any{
+		"consumer:plug producer:slot": map[string]any{
+			"interface": "test",
+			"users":     users,
+		},
+	})
+	s.state.Unlock()
+}
+
+func (s *interfaceManagerSuite) runUsersChange(c *C, ts *state.TaskSet, fail bool) *state.Change {
+	s.state.Lock()
+	change := s.state.NewChange("connect", "...")
+	change.AddAll(ts)
+	if fail {
+		terr := s.state.NewTask("error-trigger", "provoking total undo")
+		terr.WaitAll(ts)
+		change.AddTask(terr)
+	}
+	s.state.Unlock()
+
+	s.settle(c)
+	return change
+}
+
+func (s *interfaceManagerSuite) TestConnectForMoreUsers(c *C) {
+	restore := ifacestate.MockUserLimitableInterfaces("test")
+	defer restore()
+
+	s.mockConnectionForUsers(c, []int{1000})
+	_ = s.manager(c)
+
+	// the connection is restored with its users
+	conn := s.getConnection(c, "consumer", "plug", "producer", "slot")
+	c.Check(conn.Plug.Users(), DeepEquals, []int{1000})
+
+	s.state.Lock()
+	_, err := ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{Users: []int{1000}})
+	c.Check(err, FitsTypeOf, &ifacestate.ErrAlreadyConnected{})
+
+	ts, err := ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{Users: []int{1001}})
+	c.Assert(err, IsNil)
+	c.Assert(ts.Tasks(), HasLen, 1)
+	task := ts.Tasks()[0]
+	c.Check(task.Kind(), Equals, "set-connection-users")
+	c.Check(task.Summary(), Equals, "Limit connection of consumer:plug to producer:slot to users 1000,1001")
+	s.state.Unlock()
+
+	change := s.runUsersChange(c, ts, false)
+
+	s.state.Lock()
+	c.Assert(change.Err(), IsNil)
+	connStates, err := ifacestate.ConnectionStates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(connStates["consumer:plug producer:slot"].Users, DeepEquals, []int{1000, 1001})
+	// the security of the plug side is set up again
+	c.Assert(s.secBackend.SetupCalls, HasLen, 1)
+	c.Check(s.secBackend.SetupCalls[0].AppSet.InstanceName(), Equals, "consumer")
+	c.Check(conn.Plug.Users(), DeepEquals, []int{1000, 1001})
+
+	// connecting without users makes the connection apply to everybody
+	ts, err = ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", nil)
+	c.Assert(err, IsNil)
+	c.Check(ts.Tasks()[0].Summary(), Equals, "Connect consumer:plug to producer:slot for all users")
+	s.state.Unlock()
+
+	change = s.runUsersChange(c, ts, false)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(change.Err(), IsNil)
+	connStates, err = ifacestate.ConnectionStates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(connStates["consumer:plug producer:slot"].Users, IsNil)
+	c.Check(conn.Plug.Users(), IsNil)
+
+	// and then users cannot be added anymore
+	_, err = ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{Users: []int{1002}})
+	c.Check(err, FitsTypeOf, &ifacestate.ErrAlreadyConnected{})
+}
+
+func (s *interfaceManagerSuite) TestSetConnectionUsersUndo(c *C) {
+	restore := ifacestate.MockUserLimitableInterfaces("test")
+	defer restore()
+
+	s.mockConnectionForUsers(c, []int{1000})
+	_ = s.manager(c)
+
+	s.state.Lock()
+	ts, err := ifacestate.ConnectWithOptions(s.state, "consumer", "plug", "producer", "slot", &ifacestate.ConnectOptions{Users: []int{1001}})
+	c.Assert(err, IsNil)
+	s.state.Unlock()
+
+	change := s.runUsersChange(c, ts, true)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(change.Status(), Equals, state.ErrorStatus)
+	c.Check(ts.Tasks()[0].Status(), Equals, state.UndoneStatus)
+	connStates, err := ifacestate.ConnectionStates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(connStates["consumer:plug producer:slot"].Users, DeepEquals, []int{1000})
+	conn := s.getConnection(c, "consumer", "plug", "producer", "slot")
+	c.Check(conn.Plug.Users(), DeepEquals, []int{1000})
+	c.Check(s.secBackend.SetupCalls, HasLen, 2)
+}
+
+func (s *interfaceManagerSuite) TestDisconnectForUsers(c *C) {
+	s.mockConnectionForUsers(c, []int{1000, 1001})
+	_ = s.manager(c)
+
+	conn := s.getConnection(c, "consumer", "plug", "producer", "slot")
+
+	s.state.Lock()
+	_, err := ifacestate.DisconnectForUsers(s.state, conn, []int{1002})
+	c.Check(err, ErrorMatches, `cannot disconnect consumer:plug producer:slot: not connected for users 1002`)
+
+	ts, err := ifacestate.DisconnectForUsers(s.state, conn, []int{1000})
+	c.Assert(err, IsNil)
+	c.Assert(ts.Tasks(), HasLen, 1)
+	c.Check(ts.Tasks()[0].Kind(), Equals, "set-connection-users")
+	s.state.Unlock()
+
+	change := s.runUsersChange(c, ts, false)
+
+	s.state.Lock()
+	c.Assert(change.Err(), IsNil)
+	c.Check(conn.Plug.Users(), DeepEquals, []int{1001})
+
+	// disconnecting the last user disconnects completely
+	ts, err = ifacestate.DisconnectForUsers(s.state, conn, []int{1001})
+	c.Assert(err, IsNil)
+	var kinds []string
+	for _, t := range ts.Tasks() {
+		kinds = append(kinds, t.Kind())
+	}
+	c.Check(kinds, DeepEquals, []string{"run-hook", "run-hook", "disconnect"})
+	s.state.Unlock()
+
+	change = s.runUsersChange(c, ts, false)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(change.Err(), IsNil)
+	var conns map[string]any
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, HasLen, 0)
+}
+
+func (s *interfaceManagerSuite) TestDisconnectForUsersNotLimited(c *C) {
+	s.mockConnectionForUsers(c, nil)
+	_ = s.manager(c)
+
+	conn := s.getConnection(c, "consumer", "plug", "producer", "slot")
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	_, err := ifacestate.DisconnectForUsers(s.state, conn, []int{1000})
+	c.Check(err, ErrorMatches, `cannot disconnect consumer:plug producer:slot for some users only: connection is not limited to users`)
+}
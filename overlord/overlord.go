@@ -213,7 +213,7 @@ func New(restartHandler restart.Handler) (*Overlord, error) {
 	o.addManager(confdbstate.Manager(s, hookMgr, o.runner))
 	o.addManager(certstate.Manager(s, o.runner))
 
-	if err := configstateInit(s, hookMgr); err != nil {
+	if err := configstateInit(s, hookMgr, o.runner); err != nil {
 		return nil, err
 	}
 	healthstate.Init(hookMgr)
@@ -108,7 +108,7 @@ func (ovs *overlordSuite) TestNew(c *C) {
 	defer restore()
 
 	var configstateInitCalled bool
-	overlord.MockConfigstateInit(func(*state.State, *hookstate.HookManager) error {
+	overlord.MockConfigstateInit(func(*state.State, *hookstate.HookManager, *state.TaskRunner) error {
 		configstateInitCalled = true
 		return nil
 	})
@@ -321,7 +321,7 @@ func (ovs *overlordSuite) TestNewFailedConfigstate(c *C) {
 	defer restore()
 
 	var configstateInitCalled bool
-	restore = overlord.MockConfigstateInit(func(*state.State, *hookstate.HookManager) error {
+	restore = overlord.MockConfigstateInit(func(*state.State, *hookstate.HookManager, *state.TaskRunner) error {
 		configstateInitCalled = true
 		return fmt.Errorf("bad bad")
 	})
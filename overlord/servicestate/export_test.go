@@ -20,9 +20,14 @@
 package servicestate
 
 import (
+	"context"
+	"os/exec"
+	"time"
+
 	tomb "gopkg.in/tomb.v2"
 
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/quota"
 	"github.com/snapcore/snapd/testutil"
 )
@@ -63,3 +68,29 @@ func MockResourcesCheckFeatureRequirements(f func(*quota.Resources) error) (rest
 	resourcesCheckFeatureRequirements = f
 	return r
 }
+
+var RunProbeOnce = runProbeOnce
+
+func MockTimeNow(f func() time.Time) (restore func()) {
+	return testutil.Mock(&timeNow, f)
+}
+
+func MockRunProbe(f func(ctx context.Context, probe *snap.ProbeInfo) error) (restore func()) {
+	return testutil.Mock(&runProbe, f)
+}
+
+func MockProbeExecCommand(f func(ctx context.Context, name string, args ...string) *exec.Cmd) (restore func()) {
+	return testutil.Mock(&probeExecCommand, f)
+}
+
+func MockProbeServiceActive(f func(serviceName string) (bool, error)) (restore func()) {
+	return testutil.Mock(&probeServiceActive, f)
+}
+
+func MockProbeRestartService(f func(serviceName string) error) (restore func()) {
+	return testutil.Mock(&probeRestartService, f)
+}
+
+func MockNewProbeTimer(f func(d time.Duration) (<-chan time.Time, func() bool)) (restore func()) {
+	return testutil.Mock(&newProbeTimer, f)
+}
@@ -0,0 +1,397 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package servicestate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/healthstate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/progress"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/systemd"
+)
+
+const (
+	livenessProbeFailedCode  = "snapd-liveness-probe-failed"
+	readinessProbeFailedCode = "snapd-readiness-probe-failed"
+)
+
+// probeStatus is the status of a probe of a service as kept in the state.
+// It is only updated when the probe starts or stops failing, or when the
+// service is restarted.
+type probeStatus struct {
+	Failing   bool      `json:"failing,omitempty"`
+	Since     time.Time `json:"since"`
+	LastError string    `json:"last-error,omitempty"`
+	Restarts  int       `json:"restarts,omitempty"`
+}
+
+// trackedProbe tracks the runs of a probe of a service. Each probe is run
+// on its own timer by a dedicated goroutine, the failures are only accessed
+// from it.
+type trackedProbe struct {
+	probe    *snap.ProbeInfo
+	failures int
+	// cancel stops the runs of the probe
+	cancel func()
+}
+
+// probeRunner runs the probes declared by the services of the installed
+// snaps.
+type probeRunner struct {
+	runs map[string]*trackedProbe
+	// infos caches the information of the current revision of the snaps
+	// with probes, indexed by instance name
+	infos map[string]*snap.Info
+
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+}
+
+func newProbeRunner() *probeRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &probeRunner{
+		runs:   make(map[string]*trackedProbe),
+		infos:  make(map[string]*snap.Info),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (r *probeRunner) stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+var (
+	timeNow = time.Now
+
+	// newProbeTimer returns a channel receiving the time once the given
+	// duration elapsed, and a function stopping the timer
+	newProbeTimer = func(d time.Duration) (<-chan time.Time, func() bool) {
+		timer := time.NewTimer(d)
+		return timer.C, timer.Stop
+	}
+
+	runProbe         = runProbeOnce
+	probeExecCommand = exec.CommandContext
+	probeHTTPClient  = &http.Client{
+		// redirects are not followed, the probes only ever talk to
+		// the service on localhost; a redirect counts as a response
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	probeServiceActive = func(serviceName string) (bool, error) {
+		sysd := systemd.New(systemd.SystemMode, progress.Null)
+		sts, err := sysd.Status([]string{serviceName})
+		if err != nil {
+			return false, err
+		}
+		return len(sts) == 1 && sts[0].Active, nil
+	}
+	// probeRestartService only restarts the service if it is still
+	// running, a service stopped meanwhile must stay stopped
+	probeRestartService = func(serviceName string) error {
+		sysd := systemd.New(systemd.SystemMode, progress.Null)
+		return sysd.TryRestart([]string{serviceName})
+	}
+)
+
+func probeKey(probe *snap.ProbeInfo) string {
+	return fmt.Sprintf("%s.%s:%s", probe.App.Snap.InstanceName(), probe.App.Name, probe.Kind)
+}
+
+func probeStatuses(st *state.State) (map[string]*probeStatus, error) {
+	var statuses map[string]*probeStatus
+	if err := st.Get("probes", &statuses); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if statuses == nil {
+		statuses = make(map[string]*probeStatus)
+	}
+	return statuses, nil
+}
+
+func setProbeStatuses(st *state.State, statuses map[string]*probeStatus) {
+	if len(statuses) == 0 {
+		st.Set("probes", nil)
+		return
+	}
+	st.Set("probes", statuses)
+}
+
+// runProbeOnce runs the given probe once, returning an error if it failed.
+func runProbeOnce(ctx context.Context, probe *snap.ProbeInfo) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(probe.Timeout))
+	defer cancel()
+
+	switch probe.Type() {
+	case "exec":
+		args := strings.Fields(probe.App.LauncherProbeCommand(probe.Kind))
+		output, err := probeExecCommand(ctx, args[0], args[1:]...).CombinedOutput()
+		if err != nil {
+			return osutil.OutputErr(output, err)
+		}
+	case "tcp":
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort("localhost", strconv.Itoa(probe.TCPPort)))
+		if err != nil {
+			return err
+		}
+		conn.Close()
+	case "http":
+		url := fmt.Sprintf("http://%s%s", net.JoinHostPort("localhost", strconv.Itoa(probe.HTTPPort)), probe.HTTPPath)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return err
+		}
+		rsp, err := probeHTTPClient.Do(req)
+		if err != nil {
+			return err
+		}
+		rsp.Body.Close()
+		if rsp.StatusCode < 200 || rsp.StatusCode >= 400 {
+			return fmt.Errorf("unexpected HTTP status %q", rsp.Status)
+		}
+	default:
+		return fmt.Errorf("internal error: unknown probe type of %s", probeKey(probe))
+	}
+	return nil
+}
+
+// startProbe starts running the given probe every interval, the first run
+// happening after an interval to give the service time to come up.
+func (m *ServiceManager) startProbe(key string, probe *snap.ProbeInfo) *trackedProbe {
+	ctx, cancel := context.WithCancel(m.probes.ctx)
+	run := &trackedProbe{probe: probe, cancel: cancel}
+	// the timer is armed before returning so that the schedule of the
+	// probe starts now
+	timer, stopTimer := newProbeTimer(time.Duration(probe.Interval))
+	m.probes.wg.Add(1)
+	go func() {
+		defer m.probes.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				stopTimer()
+				return
+			case <-timer:
+			}
+			if !m.probeOnce(ctx, key, run) {
+				return
+			}
+			timer, stopTimer = newProbeTimer(time.Duration(probe.Interval))
+		}
+	}()
+	return run
+}
+
+// probeOnce runs the probe once if its service is active, records the
+// result and restarts the service if needed. It returns false if the probe
+// was stopped meanwhile.
+func (m *ServiceManager) probeOnce(ctx context.Context, key string, run *trackedProbe) bool {
+	probe := run.probe
+	serviceName := probe.App.ServiceName()
+	active, err := probeServiceActive(serviceName)
+	if err != nil {
+		logger.Noticef("cannot get status of service %q: %v", serviceName, err)
+	}
+	if active {
+		err = runProbe(ctx, probe)
+	}
+
+	m.state.Lock()
+	if ctx.Err() != nil {
+		// stopped, possibly because the snap is being removed
+		m.state.Unlock()
+		return false
+	}
+	restart := m.probeDone(key, run, active, err)
+	m.state.Unlock()
+
+	if restart {
+		logger.Noticef("restarting service %q: %s probe failed %d times: %v", serviceName, probe.Kind, probe.FailureThreshold, err)
+		if err := probeRestartService(serviceName); err != nil {
+			logger.Noticef("cannot restart service %q: %v", serviceName, err)
+		}
+	}
+	return true
+}
+
+// probeDone records the result of a run of a probe and returns whether the
+// service needs to be restarted.
+func (m *ServiceManager) probeDone(key string, run *trackedProbe, active bool, probeErr error) (restart bool) {
+	probe := run.probe
+	if !active {
+		// only probe running services
+		run.failures = 0
+		return false
+	}
+
+	statuses, err := probeStatuses(m.state)
+	if err != nil {
+		logger.Noticef("cannot get status of probes: %v", err)
+		return false
+	}
+	status := statuses[key]
+	changed := false
+	if probeErr == nil {
+		run.failures = 0
+		if status == nil || status.Failing {
+			restarts := 0
+			if status != nil {
+				restarts = status.Restarts
+			}
+			status = &probeStatus{Since: timeNow(), Restarts: restarts}
+			changed = true
+		}
+	} else {
+		run.failures++
+		logger.Debugf("%s probe of service %q failed (%d/%d): %v", probe.Kind, probe.App.ServiceName(), run.failures, probe.FailureThreshold, probeErr)
+		if run.failures >= probe.FailureThreshold {
+			if status == nil {
+				status = &probeStatus{}
+			}
+			if !status.Failing {
+				status.Failing = true
+				status.Since = timeNow()
+			}
+			status.LastError = probeErr.Error()
+			if probe.Kind == snap.LivenessProbe {
+				run.failures = 0
+				// leave the services of snaps being changed
+				// alone, they may be stopped or restarted by
+				// the change
+				if err := snapstate.CheckChangeConflict(m.state, probe.App.Snap.InstanceName(), nil); err != nil {
+					logger.Noticef("not restarting service %q: %v", probe.App.ServiceName(), err)
+				} else {
+					status.Restarts++
+					restart = true
+				}
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return restart
+	}
+
+	statuses[key] = status
+	setProbeStatuses(m.state, statuses)
+	if err := updateProbeHealth(m.state, probe.App.Snap, statuses); err != nil {
+		logger.Noticef("cannot update health of snap %q: %v", probe.App.Snap.InstanceName(), err)
+	}
+	return restart
+}
+
+// updateProbeHealth updates the health of the snap from the status of the
+// probes of its services. Health that was not set because of a failing probe
+// is only replaced when a probe is failing.
+func updateProbeHealth(st *state.State, info *snap.Info, statuses map[string]*probeStatus) error {
+	var failing *snap.ProbeInfo
+	var failingStatus *probeStatus
+	for _, app := range info.Apps {
+		for _, probe := range app.Probes() {
+			status := statuses[probeKey(probe)]
+			if status == nil || !status.Failing {
+				continue
+			}
+			// failing liveness probes take precedence
+			if failing == nil || (failing.Kind != snap.LivenessProbe && probe.Kind == snap.LivenessProbe) {
+				failing, failingStatus = probe, status
+			}
+		}
+	}
+
+	current, err := healthstate.Get(st, info.InstanceName())
+	if err != nil {
+		return err
+	}
+	if failing == nil {
+		if current == nil || (current.Code != livenessProbeFailedCode && current.Code != readinessProbeFailedCode) {
+			return nil
+		}
+		return healthstate.Set(st, info.InstanceName(), &healthstate.HealthState{
+			Revision:  info.Revision,
+			Timestamp: timeNow(),
+			Status:    healthstate.OkayStatus,
+		})
+	}
+
+	health := &healthstate.HealthState{
+		Revision:  info.Revision,
+		Timestamp: timeNow(),
+		Message:   fmt.Sprintf("%s probe of service %q is failing: %s", failing.Kind, failing.App.Name, failingStatus.LastError),
+	}
+	if failing.Kind == snap.LivenessProbe {
+		health.Status = healthstate.ErrorStatus
+		health.Code = livenessProbeFailedCode
+	} else {
+		health.Status = healthstate.WaitingStatus
+		health.Code = readinessProbeFailedCode
+	}
+	return healthstate.Set(st, info.InstanceName(), health)
+}
+
+// DecorateWithProbeStatus adds the status of the probes of the given
+// services.
+func DecorateWithProbeStatus(st *state.State, apps []client.AppInfo) error {
+	statuses, err := probeStatuses(st)
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		for j := range apps[i].Probes {
+			p := &apps[i].Probes[j]
+			status := statuses[fmt.Sprintf("%s.%s:%s", apps[i].Snap, apps[i].Name, p.Kind)]
+			switch {
+			case status == nil:
+				p.Status = client.ProbeStatusUnknown
+			case status.Failing:
+				p.Status = client.ProbeStatusFailing
+			default:
+				p.Status = client.ProbeStatusPassing
+			}
+			if status != nil {
+				p.Since = status.Since
+				p.Restarts = status.Restarts
+				p.Message = status.LastError
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,512 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package servicestate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/healthstate"
+	"github.com/snapcore/snapd/overlord/servicestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/sequence"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type probesSuite struct {
+	testutil.BaseTest
+
+	state *state.State
+	mgr   *servicestate.ServiceManager
+
+	now       time.Time
+	results   map[string]error
+	active    bool
+	restarted []string
+
+	timersLock sync.Mutex
+	timers     []*probeTimer
+	armed      chan struct{}
+}
+
+type probeTimer struct {
+	when time.Time
+	c    chan time.Time
+}
+
+var _ = Suite(&probesSuite{})
+
+const probesSnapYaml = `name: test-snap
+version: 1
+apps:
+  web:
+    daemon: simple
+    liveness-probe:
+      http:
+        port: 8080
+      interval: 10s
+      failure-threshold: 2
+    readiness-probe:
+      tcp:
+        port: 8080
+      interval: 30s
+      failure-threshold: 1
+  other:
+    daemon: simple
+`
+
+func (s *probesSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	s.state = state.New(nil)
+	s.mgr = servicestate.Manager(s.state, state.NewTaskRunner(s.state))
+	s.AddCleanup(s.mgr.Stop)
+
+	s.now = time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	s.AddCleanup(servicestate.MockTimeNow(func() time.Time { return s.now }))
+
+	s.results = make(map[string]error)
+	s.AddCleanup(servicestate.MockRunProbe(func(ctx context.Context, probe *snap.ProbeInfo) error {
+		return s.results[string(probe.Kind)]
+	}))
+	s.active = true
+	s.AddCleanup(servicestate.MockProbeServiceActive(func(serviceName string) (bool, error) {
+		c.Check(serviceName, Equals, "snap.test-snap.web.service")
+		return s.active, nil
+	}))
+	s.restarted = nil
+	s.AddCleanup(servicestate.MockProbeRestartService(func(serviceName string) error {
+		s.restarted = append(s.restarted, serviceName)
+		return nil
+	}))
+	s.timers = nil
+	s.armed = make(chan struct{}, 100)
+	s.AddCleanup(servicestate.MockNewProbeTimer(func(d time.Duration) (<-chan time.Time, func() bool) {
+		t := &probeTimer{when: s.now.Add(d), c: make(chan time.Time, 1)}
+		s.timersLock.Lock()
+		s.timers = append(s.timers, t)
+		s.timersLock.Unlock()
+		s.armed <- struct{}{}
+		return t.c, func() bool { return s.removeTimer(t) }
+	}))
+
+	si := &snap.SideInfo{RealName: "test-snap", Revision: snap.R(1)}
+	snaptest.MockSnap(c, probesSnapYaml, si)
+	s.state.Lock()
+	snapstate.Set(s.state, "test-snap", &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  si.Revision,
+		SnapType: "app",
+	})
+	s.state.Unlock()
+
+	// start tracking the probes
+	s.ensure(c)
+}
+
+func (s *probesSuite) removeTimer(t *probeTimer) bool {
+	s.timersLock.Lock()
+	defer s.timersLock.Unlock()
+	for i, other := range s.timers {
+		if other == t {
+			s.timers = append(s.timers[:i], s.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// waitTimers waits for the probes to have the given number of armed timers.
+func (s *probesSuite) waitTimers(c *C, n int) {
+	for i := 0; i < 1000; i++ {
+		s.timersLock.Lock()
+		armed := len(s.timers)
+		s.timersLock.Unlock()
+		if armed == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Fatalf("probes do not have %d armed timers", n)
+}
+
+func (s *probesSuite) nextDueTimer() *probeTimer {
+	s.timersLock.Lock()
+	defer s.timersLock.Unlock()
+	var due *probeTimer
+	for _, t := range s.timers {
+		if !t.when.After(s.now) && (due == nil || t.when.Before(due.when)) {
+			due = t
+		}
+	}
+	return due
+}
+
+// ensure runs the ensure of the manager, which starts and stops the probes
+// but does not run them.
+func (s *probesSuite) ensure(c *C) {
+	c.Assert(s.mgr.Ensure(), IsNil)
+	// the timers of the probes that were started are armed already
+	for len(s.armed) > 0 {
+		<-s.armed
+	}
+}
+
+// advance advances the time by the given duration and waits for the
+// probes that are due to run.
+func (s *probesSuite) advance(c *C, d time.Duration) {
+	s.now = s.now.Add(d)
+	for {
+		t := s.nextDueTimer()
+		if t == nil {
+			return
+		}
+		s.removeTimer(t)
+		t.c <- s.now
+		// the probe is scheduled again once it ran
+		select {
+		case <-s.armed:
+		case <-time.After(10 * time.Second):
+			c.Fatalf("probe was not scheduled again")
+		}
+	}
+}
+
+func (s *probesSuite) appProbes(c *C) []client.AppProbe {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	apps := []client.AppInfo{{
+		Snap: "test-snap",
+		Name: "web",
+		Probes: []client.AppProbe{
+			{Kind: "liveness", Type: "http"},
+			{Kind: "readiness", Type: "tcp"},
+		},
+	}}
+	c.Assert(servicestate.DecorateWithProbeStatus(s.state, apps), IsNil)
+	return apps[0].Probes
+}
+
+func (s *probesSuite) health(c *C) *healthstate.HealthState {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	health, err := healthstate.Get(s.state, "test-snap")
+	c.Assert(err, IsNil)
+	return health
+}
+
+func (s *probesSuite) TestProbesPassing(c *C) {
+	// nothing runs before the first interval
+	s.advance(c, 5*time.Second)
+	c.Check(s.appProbes(c)[0].Status, Equals, client.ProbeStatusUnknown)
+	c.Check(s.appProbes(c)[1].Status, Equals, client.ProbeStatusUnknown)
+
+	// the liveness probe is due after 10s
+	s.advance(c, 5*time.Second)
+	probes := s.appProbes(c)
+	c.Check(probes[0].Status, Equals, client.ProbeStatusPassing)
+	c.Check(probes[0].Since, Equals, s.now)
+	c.Check(probes[1].Status, Equals, client.ProbeStatusUnknown)
+
+	// and the readiness probe after 30s
+	s.advance(c, 20*time.Second)
+	probes = s.appProbes(c)
+	c.Check(probes[0].Status, Equals, client.ProbeStatusPassing)
+	c.Check(probes[1].Status, Equals, client.ProbeStatusPassing)
+
+	c.Check(s.restarted, HasLen, 0)
+	c.Check(s.health(c), IsNil)
+}
+
+func (s *probesSuite) TestLivenessProbeFailingRestarts(c *C) {
+	s.results["liveness"] = errors.New("connection refused")
+
+	// one failure is below the threshold
+	s.advance(c, 10*time.Second)
+	c.Check(s.appProbes(c)[0].Status, Equals, client.ProbeStatusUnknown)
+	c.Check(s.restarted, HasLen, 0)
+
+	s.advance(c, 10*time.Second)
+	probes := s.appProbes(c)
+	c.Check(probes[0], DeepEquals, client.AppProbe{
+		Kind:     "liveness",
+		Type:     "http",
+		Status:   client.ProbeStatusFailing,
+		Since:    s.now,
+		Message:  "connection refused",
+		Restarts: 1,
+	})
+	c.Check(s.restarted, DeepEquals, []string{"snap.test-snap.web.service"})
+	health := s.health(c)
+	c.Assert(health, NotNil)
+	c.Check(health.Status, Equals, healthstate.ErrorStatus)
+	c.Check(health.Code, Equals, "snapd-liveness-probe-failed")
+	c.Check(health.Message, Equals, `liveness probe of service "web" is failing: connection refused`)
+	c.Check(health.Revision, Equals, snap.R(1))
+
+	// the failures are counted anew after the restart
+	s.advance(c, 10*time.Second)
+	c.Check(s.restarted, HasLen, 1)
+	s.advance(c, 10*time.Second)
+	c.Check(s.restarted, HasLen, 2)
+	c.Check(s.appProbes(c)[0].Restarts, Equals, 2)
+
+	// recovering restores the health
+	delete(s.results, "liveness")
+	s.advance(c, 10*time.Second)
+	probes = s.appProbes(c)
+	c.Check(probes[0].Status, Equals, client.ProbeStatusPassing)
+	c.Check(probes[0].Restarts, Equals, 2)
+	c.Check(probes[0].Message, Equals, "")
+	health = s.health(c)
+	c.Assert(health, NotNil)
+	c.Check(health.Status, Equals, healthstate.OkayStatus)
+	c.Check(health.Code, Equals, "")
+}
+
+func (s *probesSuite) TestReadinessProbeFailing(c *C) {
+	s.results["readiness"] = errors.New("connection refused")
+
+	s.advance(c, 30*time.Second)
+	probes := s.appProbes(c)
+	c.Check(probes[0].Status, Equals, client.ProbeStatusPassing)
+	c.Check(probes[1].Status, Equals, client.ProbeStatusFailing)
+	// readiness probes do not restart the service
+	c.Check(s.restarted, HasLen, 0)
+	health := s.health(c)
+	c.Assert(health, NotNil)
+	c.Check(health.Status, Equals, healthstate.WaitingStatus)
+	c.Check(health.Code, Equals, "snapd-readiness-probe-failed")
+}
+
+func (s *probesSuite) TestProbesKeepHealthSetBySnap(c *C) {
+	s.state.Lock()
+	healthstate.Set(s.state, "test-snap", &healthstate.HealthState{Status: healthstate.BlockedStatus, Code: "snap-code"})
+	s.state.Unlock()
+
+	s.advance(c, 30*time.Second)
+	c.Check(s.appProbes(c)[1].Status, Equals, client.ProbeStatusPassing)
+	c.Check(s.health(c), DeepEquals, &healthstate.HealthState{Status: healthstate.BlockedStatus, Code: "snap-code"})
+}
+
+func (s *probesSuite) TestProbesInactiveService(c *C) {
+	s.active = false
+	s.results["liveness"] = errors.New("connection refused")
+
+	s.advance(c, 10*time.Second)
+	s.advance(c, 10*time.Second)
+	c.Check(s.appProbes(c)[0].Status, Equals, client.ProbeStatusUnknown)
+	c.Check(s.restarted, HasLen, 0)
+}
+
+func (s *probesSuite) TestLivenessProbeFailingConflictingChange(c *C) {
+	s.results["liveness"] = errors.New("connection refused")
+
+	s.state.Lock()
+	chg := s.state.NewChange("refresh-snap", "...")
+	t := s.state.NewTask("link-snap", "...")
+	t.Set("snap-setup", &snapstate.SnapSetup{SideInfo: &snap.SideInfo{RealName: "test-snap"}})
+	chg.AddTask(t)
+	s.state.Unlock()
+
+	// the service of a snap being changed is not restarted
+	s.advance(c, 10*time.Second)
+	s.advance(c, 10*time.Second)
+	probes := s.appProbes(c)
+	c.Check(probes[0].Status, Equals, client.ProbeStatusFailing)
+	c.Check(probes[0].Restarts, Equals, 0)
+	c.Check(s.restarted, HasLen, 0)
+
+	s.state.Lock()
+	t.SetStatus(state.DoneStatus)
+	s.state.Unlock()
+
+	s.advance(c, 10*time.Second)
+	s.advance(c, 10*time.Second)
+	c.Check(s.appProbes(c)[0].Restarts, Equals, 1)
+	c.Check(s.restarted, DeepEquals, []string{"snap.test-snap.web.service"})
+}
+
+func (s *probesSuite) TestProbesRemovedSnap(c *C) {
+	s.advance(c, 30*time.Second)
+	c.Check(s.appProbes(c)[0].Status, Equals, client.ProbeStatusPassing)
+
+	s.state.Lock()
+	snapstate.Set(s.state, "test-snap", nil)
+	s.state.Unlock()
+
+	// the probes are stopped
+	s.ensure(c)
+	s.waitTimers(c, 0)
+	s.advance(c, 10*time.Second)
+	c.Check(s.appProbes(c)[0].Status, Equals, client.ProbeStatusUnknown)
+	s.state.Lock()
+	defer s.state.Unlock()
+	var statuses map[string]any
+	c.Check(s.state.Get("probes", &statuses), testutil.ErrorIs, state.ErrNoState)
+}
+
+func (s *probesSuite) TestProbesInfoCached(c *C) {
+	// the information of the snap is not read again for the same revision
+	info, err := snap.ReadInfo("test-snap", &snap.SideInfo{Revision: snap.R(1)})
+	c.Assert(err, IsNil)
+	c.Assert(os.Remove(filepath.Join(info.MountDir(), "meta", "snap.yaml")), IsNil)
+
+	c.Assert(s.mgr.Ensure(), IsNil)
+	// the probes keep their schedule
+	c.Check(s.armed, HasLen, 0)
+	s.advance(c, 10*time.Second)
+	c.Check(s.appProbes(c)[0].Status, Equals, client.ProbeStatusPassing)
+}
+
+func (s *probesSuite) TestProbesNewRevision(c *C) {
+	si := &snap.SideInfo{RealName: "test-snap", Revision: snap.R(2)}
+	snaptest.MockSnap(c, strings.Replace(probesSnapYaml, "interval: 10s", "interval: 20s", 1), si)
+	s.state.Lock()
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "test-snap", &snapst), IsNil)
+	snapst.Sequence.Revisions = append(snapst.Sequence.Revisions, sequence.NewRevisionSideState(si, nil))
+	snapst.Current = si.Revision
+	snapstate.Set(s.state, "test-snap", &snapst)
+	s.state.Unlock()
+
+	// the probes of the new revision replace the old ones
+	s.advance(c, 5*time.Second)
+	s.ensure(c)
+	s.waitTimers(c, 2)
+	s.advance(c, 10*time.Second)
+	c.Check(s.appProbes(c)[0].Status, Equals, client.ProbeStatusUnknown)
+	s.advance(c, 10*time.Second)
+	c.Check(s.appProbes(c)[0].Status, Equals, client.ProbeStatusPassing)
+}
+
+func (s *probesSuite) probe(c *C, probeYaml string) *snap.ProbeInfo {
+	info := snaptest.MockInfo(c, fmt.Sprintf(`name: test-snap
+version: 1
+apps:
+  web:
+    daemon: simple
+    liveness-probe:
+%s`, probeYaml), nil)
+	return info.Apps["web"].LivenessProbe
+}
+
+func (s *probesSuite) TestRunProbeOnceTCP(c *C) {
+	l, err := net.Listen("tcp", "localhost:0")
+	c.Assert(err, IsNil)
+	port := l.Addr().(*net.TCPAddr).Port
+
+	probe := s.probe(c, fmt.Sprintf("      tcp:\n        port: %d\n", port))
+	c.Check(servicestate.RunProbeOnce(context.Background(), probe), IsNil)
+
+	l.Close()
+	c.Check(servicestate.RunProbeOnce(context.Background(), probe), ErrorMatches, ".*connection refused")
+}
+
+func (s *probesSuite) TestRunProbeOnceHTTP(c *C) {
+	var status int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/healthz")
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	port := srv.Listener.Addr().(*net.TCPAddr).Port
+
+	probe := s.probe(c, fmt.Sprintf("      http:\n        port: %d\n        path: /healthz\n", port))
+	for _, status = range []int{200, 204, 301} {
+		c.Check(servicestate.RunProbeOnce(context.Background(), probe), IsNil)
+	}
+	status = 503
+	c.Check(servicestate.RunProbeOnce(context.Background(), probe), ErrorMatches, `unexpected HTTP status "503 Service Unavailable"`)
+}
+
+func (s *probesSuite) TestRunProbeOnceHTTPRedirectNotFollowed(c *C) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Errorf("unexpected request to %s", r.URL)
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/elsewhere", http.StatusFound)
+	}))
+	defer srv.Close()
+	port := srv.Listener.Addr().(*net.TCPAddr).Port
+
+	probe := s.probe(c, "      http:\n        port: "+strconv.Itoa(port)+"\n")
+	c.Check(servicestate.RunProbeOnce(context.Background(), probe), IsNil)
+}
+
+func (s *probesSuite) TestRunProbeOnceHTTPTimeout(c *C) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+	port := srv.Listener.Addr().(*net.TCPAddr).Port
+
+	probe := s.probe(c, "      http:\n        port: "+strconv.Itoa(port)+"\n")
+	probe.Timeout = 1
+	c.Check(servicestate.RunProbeOnce(context.Background(), probe), ErrorMatches, ".*context deadline exceeded.*")
+}
+
+func (s *probesSuite) TestRunProbeOnceExec(c *C) {
+	var exitCode string
+	var calls [][]string
+	s.AddCleanup(servicestate.MockProbeExecCommand(func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		calls = append(calls, append([]string{name}, args...))
+		return exec.CommandContext(ctx, "sh", "-c", "echo checking; exit "+exitCode)
+	}))
+
+	probe := s.probe(c, "      exec: bin/check\n")
+	exitCode = "0"
+	c.Check(servicestate.RunProbeOnce(context.Background(), probe), IsNil)
+	exitCode = "1"
+	c.Check(servicestate.RunProbeOnce(context.Background(), probe), ErrorMatches, "checking")
+	c.Check(calls, DeepEquals, [][]string{
+		{"/usr/bin/snap", "run", "--command=liveness-probe", "test-snap.web"},
+		{"/usr/bin/snap", "run", "--command=liveness-probe", "test-snap.web"},
+	})
+}
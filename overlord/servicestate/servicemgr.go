@@ -46,6 +46,7 @@ import (
 
 func init() {
 	swfeats.RegisterEnsure("ServiceManager", "ensureSnapServicesUpdated")
+	swfeats.RegisterEnsure("ServiceManager", "ensureProbes")
 }
 
 // ServiceManager is responsible for starting and stopping snap services.
@@ -53,13 +54,16 @@ type ServiceManager struct {
 	state *state.State
 
 	ensuredSnapSvcs bool
+
+	probes *probeRunner
 }
 
 // Manager returns a new service manager.
 func Manager(st *state.State, runner *state.TaskRunner) *ServiceManager {
 	delayedCrossMgrInit()
 	m := &ServiceManager{
-		state: st,
+		state:  st,
+		probes: newProbeRunner(),
 	}
 	// TODO: undo handler
 	runner.AddHandler("service-control", m.doServiceControl, nil)
@@ -206,14 +210,99 @@ func (m *ServiceManager) ensureSnapServicesUpdated() (err error) {
 	return nil
 }
 
+// ensureProbes starts running the probes of the active snap services and
+// stops the ones of the services that went away. The probes run on their
+// own timers, only taking the state lock to record their results.
+func (m *ServiceManager) ensureProbes() error {
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	logger.Trace("ensure", "manager", "ServiceManager", "func", "ensureProbes")
+
+	allStates, err := snapstate.All(m.state)
+	if err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+
+	known := make(map[string]bool)
+	for instanceName, snapst := range allStates {
+		if !snapst.Active {
+			continue
+		}
+		// the information of the snap is only read again once its
+		// revision changed
+		info := m.probes.infos[instanceName]
+		if info == nil || info.Revision != snapst.Current {
+			info, err = snapst.CurrentInfo()
+			if err != nil {
+				return err
+			}
+			m.probes.infos[instanceName] = info
+		}
+		for _, app := range info.Apps {
+			for _, probe := range app.Probes() {
+				key := probeKey(probe)
+				known[key] = true
+
+				run := m.probes.runs[key]
+				if run != nil && run.probe == probe {
+					continue
+				}
+				if run != nil {
+					// the probe of another revision
+					run.cancel()
+				}
+				m.probes.runs[key] = m.startProbe(key, probe)
+			}
+		}
+	}
+	for instanceName := range m.probes.infos {
+		if snapst := allStates[instanceName]; snapst == nil || !snapst.Active {
+			delete(m.probes.infos, instanceName)
+		}
+	}
+
+	// forget about the probes of removed snaps or apps
+	statuses, err := probeStatuses(m.state)
+	if err != nil {
+		return err
+	}
+	for key, run := range m.probes.runs {
+		if !known[key] {
+			run.cancel()
+			delete(m.probes.runs, key)
+		}
+	}
+	pruned := false
+	for key := range statuses {
+		if !known[key] {
+			delete(statuses, key)
+			pruned = true
+		}
+	}
+	if pruned {
+		setProbeStatuses(m.state, statuses)
+	}
+	return nil
+}
+
 // Ensure implements StateManager.Ensure.
 func (m *ServiceManager) Ensure() error {
 	if err := m.ensureSnapServicesUpdated(); err != nil {
 		return err
 	}
+	if err := m.ensureProbes(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// Stop implements StateStopper. It waits for the running probes of snap
+// services to finish.
+func (m *ServiceManager) Stop() {
+	m.probes.stop()
+}
+
 func delayedCrossMgrInit() {
 	// hook into conflict checks mechanisms
 	snapstate.RegisterAffectedSnapsByAttr("service-action", serviceControlAffectedSnaps)
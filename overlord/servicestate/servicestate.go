@@ -478,6 +478,9 @@ func (sd *StatusDecorator) DecorateWithStatus(appInfo *client.AppInfo, snapApp *
 	if snapApp.Timer != nil {
 		extra++
 	}
+	if snapApp.Watch != nil {
+		extra++
+	}
 	serviceNames := make([]string, 0, 1+extra)
 	serviceNames = append(serviceNames, snapApp.ServiceName())
 
@@ -491,6 +494,10 @@ func (sd *StatusDecorator) DecorateWithStatus(appInfo *client.AppInfo, snapApp *
 		timerUnit := filepath.Base(snapApp.Timer.File())
 		serviceNames = append(serviceNames, timerUnit)
 	}
+	if snapApp.Watch != nil {
+		pathUnit := filepath.Base(snapApp.Watch.File())
+		serviceNames = append(serviceNames, pathUnit)
+	}
 
 	sts, err := sd.queryServiceStatus(snapApp.DaemonScope, serviceNames)
 	if err != nil {
@@ -509,6 +516,13 @@ func (sd *StatusDecorator) DecorateWithStatus(appInfo *client.AppInfo, snapApp *
 				Active:  st.Active,
 				Type:    "timer",
 			})
+		case ".path":
+			appInfo.Activators = append(appInfo.Activators, client.AppActivator{
+				Name:    snapApp.Name,
+				Enabled: st.Enabled,
+				Active:  st.Active,
+				Type:    "path",
+			})
 		case ".socket":
 			appInfo.Activators = append(appInfo.Activators, client.AppActivator{
 				Name:    sockSvcFileToName[st.Name],
@@ -575,6 +589,14 @@ func SnapServiceOptions(st *state.State, snapInfo *snap.Info, quotaGroups map[st
 		}
 	}
 
+	// the overrides are kept in the system configuration rather than in the
+	// configuration of the snap so that they survive refreshes and reverts
+	var overrides map[string]map[string]*wrappers.ServiceOverride
+	if err := tr.GetMaybe("core", "service-overrides", &overrides); err != nil {
+		return nil, err
+	}
+	opts.ServiceOverrides = overrides[snapInfo.InstanceName()]
+
 	// also check for quota group for this instance name
 	for _, grp := range quotaGroups {
 		if strutil.ListContains(grp.Snaps, snapInfo.InstanceName()) {
@@ -28,6 +28,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	. "gopkg.in/check.v1"
 
@@ -47,6 +48,7 @@ import (
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/systemd"
 	"github.com/snapcore/snapd/testutil"
+	"github.com/snapcore/snapd/timeout"
 	"github.com/snapcore/snapd/usersession/agent"
 	"github.com/snapcore/snapd/wrappers"
 )
@@ -104,7 +106,7 @@ func (s *statusDecoratorSuite) TestDecorateWithStatus(c *C) {
 				activeState = "inactive"
 				unitState = "disabled"
 			}
-			if strings.HasSuffix(unit, ".timer") || strings.HasSuffix(unit, ".socket") || strings.HasSuffix(unit, ".target") {
+			if strings.HasSuffix(unit, ".timer") || strings.HasSuffix(unit, ".socket") || strings.HasSuffix(unit, ".path") || strings.HasSuffix(unit, ".target") {
 				// Units using the baseProperties query
 				return []byte(fmt.Sprintf(`Id=%s
 Names=%[1]s
@@ -194,6 +196,31 @@ NeedDaemonReload=no
 			{Name: "svc", Type: "timer", Active: enabled, Enabled: enabled},
 		})
 
+		// service + path
+		app = &client.AppInfo{
+			Snap:   snp.InstanceName(),
+			Name:   "svc",
+			Daemon: "simple",
+		}
+		snapApp = &snap.AppInfo{
+			Snap:        snp,
+			Name:        "svc",
+			Daemon:      "simple",
+			DaemonScope: snap.SystemDaemon,
+		}
+		snapApp.Watch = &snap.WatchInfo{
+			App:         snapApp,
+			PathChanged: []string{"$SNAP_COMMON/config"},
+		}
+
+		err = sd.DecorateWithStatus(app, snapApp)
+		c.Assert(err, IsNil)
+		c.Check(app.Active, Equals, enabled)
+		c.Check(app.Enabled, Equals, enabled)
+		c.Check(app.Activators, DeepEquals, []client.AppActivator{
+			{Name: "svc", Type: "path", Active: enabled, Enabled: enabled},
+		})
+
 		// service with socket
 		app = &client.AppInfo{
 			Snap:   snp.InstanceName(),
@@ -327,7 +354,7 @@ func (s *statusDecoratorSuite) TestUserServiceDecorateWithStatus(c *C) {
 			unitState = "disabled"
 		}
 
-		if strings.HasSuffix(unit, ".timer") || strings.HasSuffix(unit, ".socket") || strings.HasSuffix(unit, ".target") {
+		if strings.HasSuffix(unit, ".timer") || strings.HasSuffix(unit, ".socket") || strings.HasSuffix(unit, ".path") || strings.HasSuffix(unit, ".target") {
 			// Units using the baseProperties query
 			return []byte(fmt.Sprintf(`Id=%s
 Names=%[1]s
@@ -627,6 +654,39 @@ func (s *snapServiceOptionsSuite) TestSnapServiceOptionsVitalityRank(c *C) {
 	})
 }
 
+func (s *snapServiceOptionsSuite) TestSnapServiceOptionsServiceOverrides(c *C) {
+	st := s.state
+	st.Lock()
+	defer st.Unlock()
+	t := config.NewTransaction(st)
+	err := t.Set("core", "service-overrides.foo.svc.restart-delay", "10s")
+	c.Assert(err, IsNil)
+	err = t.Set("core", "service-overrides.foo.svc.nice", 5)
+	c.Assert(err, IsNil)
+	err = t.Set("core", "service-overrides.foo.svc.environment", []any{"FOO=bar"})
+	c.Assert(err, IsNil)
+	t.Commit()
+
+	fooInfo := snaptest.MockInfo(c, "name: foo\nversion: 0", nil)
+	barInfo := snaptest.MockInfo(c, "name: bar\nversion: 0", nil)
+
+	nice := 5
+	opts, err := servicestate.SnapServiceOptions(st, fooInfo, nil)
+	c.Assert(err, IsNil)
+	c.Check(opts, DeepEquals, &wrappers.SnapServiceOptions{
+		ServiceOverrides: map[string]*wrappers.ServiceOverride{
+			"svc": {
+				Environment:  []string{"FOO=bar"},
+				RestartDelay: timeout.Timeout(10 * time.Second),
+				Nice:         &nice,
+			},
+		},
+	})
+	opts, err = servicestate.SnapServiceOptions(st, barInfo, nil)
+	c.Assert(err, IsNil)
+	c.Check(opts, DeepEquals, &wrappers.SnapServiceOptions{})
+}
+
 func (s *snapServiceOptionsSuite) TestSnapServiceOptionsQuotaGroups(c *C) {
 	st := s.state
 	st.Lock()
@@ -138,9 +138,14 @@ func (rc *refreshCandidate) SnapSetupForUpdate(st *state.State, globalFlags *Fla
 type autoRefresh struct {
 	state *state.State
 
-	lastRefreshSchedule string
-	nextRefresh         time.Time
-	lastRefreshAttempt  time.Time
+	lastRefreshSchedule  string
+	lastRefreshSnapTimer string
+	nextRefresh          time.Time
+	lastRefreshAttempt   time.Time
+	// snapTimerOnly is set when nextRefresh was brought forward by a
+	// per-snap refresh timer, in which case only snaps whose refresh
+	// window is open get refreshed.
+	snapTimerOnly bool
 
 	restoredMonitoring bool
 }
@@ -338,15 +343,20 @@ func (m *autoRefresh) Ensure() (err error) {
 		m.nextRefresh = time.Time{}
 		return nil
 	}
+	snapTimers, snapTimerStr, err := refreshSnapTimers(m.state)
+	if err != nil {
+		return err
+	}
 	// we already have a refresh time, check if we got a new config
 	if !m.nextRefresh.IsZero() {
-		if m.lastRefreshSchedule != refreshScheduleStr {
+		if m.lastRefreshSchedule != refreshScheduleStr || m.lastRefreshSnapTimer != snapTimerStr {
 			// the refresh schedule has changed
 			logger.Debugf("Refresh timer changed.")
 			m.nextRefresh = time.Time{}
 		}
 	}
 	m.lastRefreshSchedule = refreshScheduleStr
+	m.lastRefreshSnapTimer = snapTimerStr
 
 	// ensure nothing is in flight already
 	if autoRefreshInFlight(m.state) {
@@ -359,10 +369,12 @@ func (m *autoRefresh) Ensure() (err error) {
 	// compute next refresh attempt time (if needed)
 	if m.nextRefresh.IsZero() {
 		// store attempts in memory so that we can backoff
+		m.snapTimerOnly = false
 		if !lastRefresh.IsZero() {
-			delta := timeutil.Next(refreshSchedule, lastRefresh, maxPostponement)
+			if err := m.scheduleNextRefresh(refreshSchedule, snapTimers, lastRefresh); err != nil {
+				return err
+			}
 			now = time.Now()
-			m.nextRefresh = now.Add(delta)
 		} else {
 			// make sure either seed-time or last-refresh
 			// are set for hold code below
@@ -385,9 +397,10 @@ func (m *autoRefresh) Ensure() (err error) {
 			m.clearRefreshHold()
 			if m.nextRefresh.Before(holdTime) {
 				// next refresh is obsolete, compute the next one
-				delta := timeutil.Next(refreshSchedule, holdTime, maxPostponement)
+				if err := m.scheduleNextRefresh(refreshSchedule, snapTimers, holdTime); err != nil {
+					return err
+				}
 				now = time.Now()
-				m.nextRefresh = now.Add(delta)
 			}
 		}
 
@@ -397,6 +410,18 @@ func (m *autoRefresh) Ensure() (err error) {
 		// before now, and the next refresh is equal to now without requiring an
 		// or operation
 		if !m.nextRefresh.After(now) {
+			var blackoutEnd time.Time
+			blackoutEnd, err = m.refreshBlackoutEnd(now, lastRefresh)
+			if err != nil {
+				return err
+			}
+			if !blackoutEnd.IsZero() {
+				// postpone to the first refresh window after the
+				// blackout period
+				logger.Debugf("Auto-refresh blocked by refresh.blackout until %s.", blackoutEnd.Format(time.RFC3339))
+				return m.scheduleNextRefresh(refreshSchedule, snapTimers, blackoutEnd)
+			}
+
 			var can bool
 			can, err = m.canRefreshRespectingMetered(now, lastRefresh)
 			if err != nil {
@@ -425,6 +450,33 @@ func (m *autoRefresh) Ensure() (err error) {
 	return err
 }
 
+// scheduleNextRefresh sets nextRefresh to the first refresh window after
+// the given anchor, taking into account both the system-wide schedule and
+// the refresh timers of installed snaps. Snaps with their own refresh timer
+// may need to be refreshed before the system-wide schedule comes up, in
+// which case snapTimerOnly is set.
+func (m *autoRefresh) scheduleNextRefresh(refreshSchedule []*timeutil.Schedule, snapTimers map[string][]*timeutil.Schedule, anchor time.Time) error {
+	delta := timeutil.Next(refreshSchedule, anchor, maxPostponement)
+	now := time.Now()
+	m.nextRefresh = now.Add(delta)
+	m.snapTimerOnly = false
+	for name, sched := range snapTimers {
+		var snapst SnapState
+		if err := Get(m.state, name, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+			return err
+		}
+		if !snapst.IsInstalled() {
+			continue
+		}
+		delta := timeutil.Next(sched, anchor, maxPostponement)
+		if next := now.Add(delta); next.Before(m.nextRefresh) {
+			m.nextRefresh = next
+			m.snapTimerOnly = true
+		}
+	}
+	return nil
+}
+
 func (m *autoRefresh) restoreMonitoring() error {
 	if m.restoredMonitoring {
 		return nil
@@ -527,6 +579,214 @@ func getRefreshScheduleConf(st *state.State) (confStr string, legacy bool, err e
 	return confStr, legacy, nil
 }
 
+// refreshSnapTimers returns the per-snap refresh schedules set via
+// refresh.snap-timer.<snap>, along with a stable string representation of
+// the configuration. Snaps listed in refresh.group-snaps.<group> follow the
+// schedule set via refresh.group-timer.<group> unless they have their own
+// refresh timer. Schedules that cannot be parsed are logged and the affected
+// snaps otherwise follow the system-wide refresh schedule.
+func refreshSnapTimers(st *state.State) (timers map[string][]*timeutil.Schedule, confStr string, err error) {
+	tr := config.NewTransaction(st)
+
+	var conf map[string]any
+	if err := tr.GetMaybe("core", "refresh.snap-timer", &conf); err != nil {
+		return nil, "", err
+	}
+	var groupTimers, groupSnaps map[string]any
+	if err := tr.GetMaybe("core", "refresh.group-timer", &groupTimers); err != nil {
+		return nil, "", err
+	}
+	if err := tr.GetMaybe("core", "refresh.group-snaps", &groupSnaps); err != nil {
+		return nil, "", err
+	}
+	if len(conf) == 0 && len(groupTimers) == 0 {
+		return nil, "", nil
+	}
+
+	timers = make(map[string][]*timeutil.Schedule, len(conf))
+	var parts []string
+	for _, name := range sortedKeys(conf) {
+		schedStr, ok := conf[name].(string)
+		if !ok || schedStr == "" {
+			continue
+		}
+		sched, err := timeutil.ParseSchedule(schedStr)
+		if err != nil {
+			// log instead of fail in order not to prevent auto-refreshes
+			logger.Noticef("cannot use refresh timer for snap %q: %v", name, err)
+			continue
+		}
+		timers[name] = sched
+		parts = append(parts, name+"="+schedStr)
+	}
+
+	for _, group := range sortedKeys(groupTimers) {
+		schedStr, ok := groupTimers[group].(string)
+		if !ok || schedStr == "" {
+			continue
+		}
+		snapsStr, _ := groupSnaps[group].(string)
+		if snapsStr == "" {
+			continue
+		}
+		sched, err := timeutil.ParseSchedule(schedStr)
+		if err != nil {
+			// log instead of fail in order not to prevent auto-refreshes
+			logger.Noticef("cannot use refresh timer for group %q: %v", group, err)
+			continue
+		}
+		for _, name := range strutil.CommaSeparatedList(snapsStr) {
+			if _, ok := timers[name]; ok {
+				// the snap's own refresh timer, or that of an
+				// earlier group, takes precedence
+				continue
+			}
+			timers[name] = sched
+		}
+		parts = append(parts, "group:"+group+"="+schedStr+":"+snapsStr)
+	}
+
+	return timers, strings.Join(parts, ";"), nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RefreshSnapTimers returns the per-snap refresh schedules, keyed by snap
+// instance name.
+func (m *autoRefresh) RefreshSnapTimers() (map[string]string, error) {
+	timers, _, err := refreshSnapTimers(m.state)
+	if err != nil {
+		return nil, err
+	}
+	if len(timers) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(timers))
+	for name, sched := range timers {
+		schedStrs := make([]string, 0, len(sched))
+		for _, s := range sched {
+			schedStrs = append(schedStrs, s.String())
+		}
+		out[name] = strings.Join(schedStrs, ",")
+	}
+	return out, nil
+}
+
+// refreshBlackouts returns the date ranges during which no auto-refresh
+// may happen, as set via refresh.blackout.
+func refreshBlackouts(st *state.State) ([]timeutil.DateRange, error) {
+	tr := config.NewTransaction(st)
+
+	var blackoutStr string
+	if err := tr.GetMaybe("core", "refresh.blackout", &blackoutStr); err != nil {
+		return nil, err
+	}
+	if blackoutStr == "" {
+		return nil, nil
+	}
+
+	blackouts, err := timeutil.ParseDateRanges(blackoutStr)
+	if err != nil {
+		// log instead of fail in order not to prevent auto-refreshes
+		logger.Noticef("cannot use refresh.blackout configuration: %v", err)
+		return nil, nil
+	}
+	return blackouts, nil
+}
+
+// RefreshBlackout returns the date ranges during which auto-refreshes are
+// blocked in a user visible form.
+func (m *autoRefresh) RefreshBlackout() (string, error) {
+	blackouts, err := refreshBlackouts(m.state)
+	if err != nil {
+		return "", err
+	}
+	blackoutStrs := make([]string, 0, len(blackouts))
+	for _, b := range blackouts {
+		blackoutStrs = append(blackoutStrs, b.String())
+	}
+	return strings.Join(blackoutStrs, ","), nil
+}
+
+// refreshBlackoutEnd returns the end of the blackout period that now falls
+// in, or the zero time if auto-refreshes are not blocked. Blackouts are
+// ignored once refreshes have been postponed for too long.
+func (m *autoRefresh) refreshBlackoutEnd(now, lastRefresh time.Time) (time.Time, error) {
+	blackouts, err := refreshBlackouts(m.state)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	end := timeutil.DateRangesEnd(blackouts, now)
+	if end.IsZero() {
+		return time.Time{}, nil
+	}
+
+	if !lastRefresh.IsZero() && now.Sub(lastRefresh) >= maxPostponement {
+		logger.Noticef("Auto refresh blocked by refresh.blackout, but pending for too long (%d days). Trying to refresh now.", int(maxPostponement.Hours()/24))
+		return time.Time{}, nil
+	}
+
+	return end, nil
+}
+
+// refreshWindowFilter returns a filter for the auto-refresh candidates that
+// only lets through snaps with a per-snap refresh timer if now is inside one
+// of their refresh windows. Snaps without a refresh timer follow the
+// system-wide schedule and are filtered out if snapTimerOnly is set.
+func refreshWindowFilter(st *state.State, now time.Time, snapTimerOnly bool) (updateFilter, error) {
+	timers, _, err := refreshSnapTimers(st)
+	if err != nil {
+		return nil, err
+	}
+	if len(timers) == 0 && !snapTimerOnly {
+		return nil, nil
+	}
+
+	return func(info *snap.Info, _ *SnapState) bool {
+		sched, ok := timers[info.InstanceName()]
+		if !ok {
+			return !snapTimerOnly
+		}
+		if !timeutil.Includes(sched, now) {
+			logger.Debugf("Skipping auto-refresh of snap %q outside of its refresh window.", info.InstanceName())
+			return false
+		}
+		return true
+	}, nil
+}
+
+// autoRefreshFilter returns the filter for the auto-refresh candidates that
+// applies both the per-snap refresh windows and the refresh staging delays.
+func autoRefreshFilter(st *state.State, now time.Time, snapTimerOnly bool) (updateFilter, error) {
+	windowFilter, err := refreshWindowFilter(st, now, snapTimerOnly)
+	if err != nil {
+		return nil, err
+	}
+	stagingFilter, err := refreshStagingFilter(st, now)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case windowFilter == nil:
+		return stagingFilter, nil
+	case stagingFilter == nil:
+		return windowFilter, nil
+	}
+	return func(info *snap.Info, snapst *SnapState) bool {
+		// only consider staging snaps which are inside their window
+		return windowFilter(info, snapst) && stagingFilter(info, snapst)
+	}, nil
+}
+
 // refreshScheduleWithDefaultsFallback returns the current refresh schedule
 // and refresh string.
 func (m *autoRefresh) refreshScheduleWithDefaultsFallback() (sched []*timeutil.Schedule, scheduleConf string, legacy bool, err error) {
@@ -614,8 +874,13 @@ func (m *autoRefresh) launchAutoRefresh() error {
 		perfTimings.Save(m.state)
 	}()
 
+	filter, err := autoRefreshFilter(m.state, now, m.snapTimerOnly)
+	if err != nil {
+		return err
+	}
+
 	// NOTE: this will unlock and re-lock state for network ops
-	updated, updateTss, err := AutoRefresh(auth.EnsureContextTODO(), m.state)
+	updated, updateTss, err := autoRefreshFiltered(auth.EnsureContextTODO(), m.state, filter)
 
 	// TODO: we should have some way to lock just creating and starting changes,
 	//       as that would alleviate this race condition we are guarding against
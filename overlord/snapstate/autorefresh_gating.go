@@ -810,7 +810,7 @@ func AutoRefreshForGatingSnap(st *state.State, gatingSnap string) error {
 
 	// NOTE: this will unlock and re-lock state for network ops
 	// XXX: should we refresh assertions (just call AutoRefresh()?)
-	updated, tasksets, err := autoRefreshPhase1(auth.EnsureContextTODO(), st, gatingSnap)
+	updated, tasksets, err := autoRefreshPhase1(auth.EnsureContextTODO(), st, gatingSnap, nil)
 	if err != nil {
 		return err
 	}
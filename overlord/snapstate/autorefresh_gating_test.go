@@ -1566,7 +1566,7 @@ func (s *autorefreshGatingSuite) TestAutoRefreshPhase1(c *C) {
 			Monitored: true,
 		},
 	})
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"base-snap-b", "snap-a", "snap-c", "snap-f"})
 	c.Assert(tss, HasLen, 2)
@@ -1702,7 +1702,7 @@ func (s *autorefreshGatingSuite) TestAffectedByRefreshUsesCurrentSnapInfo(c *C)
 	restore := snapstatetest.MockDeviceModel(DefaultModel())
 	defer restore()
 
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"base-snap-b", "snap-b"})
 	c.Assert(tss, HasLen, 2)
@@ -1795,7 +1795,7 @@ func (s *autorefreshGatingSuite) TestAutoRefreshPhase1ConflictsFilteredOut(c *C)
 	logbuf, restoreLogger := logger.MockLogger()
 	defer restoreLogger()
 
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"snap-a"})
 	c.Assert(tss, HasLen, 2)
@@ -1864,7 +1864,7 @@ func (s *autorefreshGatingSuite) TestAutoRefreshPhase1NoHooks(c *C) {
 	restore := snapstatetest.MockDeviceModel(DefaultModel())
 	defer restore()
 
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"base-snap-b", "snap-c"})
 	c.Assert(tss, HasLen, 1)
@@ -2024,7 +2024,7 @@ func (s *snapmgrTestSuite) testAutoRefreshPhase2(c *C, beforePhase1 func(), gate
 		beforePhase1()
 	}
 
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"base-snap-b", "snap-a"})
 
@@ -2328,7 +2328,7 @@ func (s *snapmgrTestSuite) testAutoRefreshPhase2DiskSpaceCheck(c *C, fail bool)
 
 	snapstate.MockSnapReadInfo(fakeReadInfo)
 
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"base-snap-b", "snap-a"})
 
@@ -2393,7 +2393,7 @@ func (s *snapmgrTestSuite) TestAutoRefreshPhase2Conflict(c *C) {
 	restore := snapstatetest.MockDeviceModel(DefaultModel())
 	defer restore()
 
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"base-snap-b", "snap-a"})
 
@@ -2472,7 +2472,7 @@ func (s *snapmgrTestSuite) TestAutoRefreshPhase2ConflictOtherSnapOp(c *C) {
 	restore := snapstatetest.MockDeviceModel(DefaultModel())
 	defer restore()
 
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"snap-a"})
 
@@ -2571,7 +2571,7 @@ func (s *snapmgrTestSuite) TestAutoRefreshPhase2GatedSnaps(c *C) {
 	restoreModel := snapstatetest.MockDeviceModel(DefaultModel())
 	defer restoreModel()
 
-	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, tss, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"base-snap-b", "snap-a"})
 
@@ -3182,7 +3182,7 @@ func (s *validationSetsSuite) TestAutoRefreshPhase1WithValidationSets(c *C) {
 
 	refreshedDate := fakeRevDateEpoch.AddDate(0, 0, 1)
 	requiredRevision = "1"
-	names, _, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, _, err := snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	// some-snap is already at the required revision 1, so not refreshed
 	c.Check(names, DeepEquals, []string{"snap-c", "some-other-snap"})
@@ -3240,7 +3240,7 @@ func (s *validationSetsSuite) TestAutoRefreshPhase1WithValidationSets(c *C) {
 
 	s.fakeBackend.ops = nil
 	requiredRevision = "11"
-	names, _, err = snapstate.AutoRefreshPhase1(context.TODO(), st, "")
+	names, _, err = snapstate.AutoRefreshPhase1(context.TODO(), st, "", nil)
 	c.Assert(err, IsNil)
 	c.Check(names, DeepEquals, []string{"snap-c", "some-other-snap", "some-snap"})
 
@@ -0,0 +1,251 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// hook setup by devicestate
+var DeviceSerial func(st *state.State) (*asserts.Serial, error)
+
+// refreshStagingBucket is the granularity of the per-device staging delay.
+const refreshStagingBucket = time.Hour
+
+// stagedRevision records when a new revision of a snap with a staging delay
+// was first seen as an auto-refresh candidate.
+type stagedRevision struct {
+	Revision  snap.Revision `json:"revision"`
+	FirstSeen time.Time     `json:"first-seen"`
+}
+
+// RefreshStaging carries the staging state of auto-refreshes for a snap.
+type RefreshStaging struct {
+	// MaxDelay is the configured refresh.staging delay for the snap.
+	MaxDelay time.Duration
+	// Delay is the deterministic share of MaxDelay that applies to this
+	// device.
+	Delay time.Duration
+	// Revision is the revision currently being staged, if any.
+	Revision snap.Revision
+	// Until is the time after which Revision is accepted by
+	// auto-refreshes.
+	Until time.Time
+}
+
+// refreshStagingDelays returns the maximum staging delay for each snap, as
+// set via refresh.staging.<snap>. Invalid values are logged and ignored.
+func refreshStagingDelays(st *state.State) (map[string]time.Duration, error) {
+	tr := config.NewTransaction(st)
+
+	var conf map[string]any
+	if err := tr.GetMaybe("core", "refresh.staging", &conf); err != nil {
+		return nil, err
+	}
+	if len(conf) == 0 {
+		return nil, nil
+	}
+
+	delays := make(map[string]time.Duration, len(conf))
+	for name, v := range conf {
+		delayStr, ok := v.(string)
+		if !ok || delayStr == "" {
+			continue
+		}
+		delay, err := time.ParseDuration(delayStr)
+		if err != nil || delay <= 0 {
+			logger.Noticef("cannot use refresh staging delay %q for snap %q", delayStr, name)
+			continue
+		}
+		delays[name] = delay
+	}
+	return delays, nil
+}
+
+// deviceStagingDelay returns the share of maxDelay that applies to this
+// device for the given snap. The delay is derived from the serial assertion
+// so that it is stable for a device but spread evenly over a fleet, in
+// buckets of refreshStagingBucket. Without a serial the full delay applies.
+func deviceStagingDelay(st *state.State, snapName string, maxDelay time.Duration) (time.Duration, error) {
+	if DeviceSerial == nil {
+		return maxDelay, nil
+	}
+	serial, err := DeviceSerial(st)
+	if errors.Is(err, state.ErrNoState) {
+		return maxDelay, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	buckets := uint64(maxDelay/refreshStagingBucket) + 1
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s/%s", serial.BrandID(), serial.Model(), serial.Serial(), snapName)))
+	bucket := binary.BigEndian.Uint64(h[:8]) % buckets
+
+	delay := time.Duration(bucket) * refreshStagingBucket
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay, nil
+}
+
+// pending returns whether the staged revision is still waiting to be
+// installed, i.e. the snap was not refreshed since the revision was first
+// seen.
+func (sr *stagedRevision) pending(snapst *SnapState) bool {
+	if snapst == nil {
+		return true
+	}
+	if snapst.Current == sr.Revision {
+		return false
+	}
+	return snapst.LastRefreshTime == nil || snapst.LastRefreshTime.Before(sr.FirstSeen)
+}
+
+func stagedRevisions(st *state.State) (map[string]*stagedRevision, error) {
+	var staged map[string]*stagedRevision
+	if err := st.Get("refresh-staging", &staged); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if staged == nil {
+		staged = make(map[string]*stagedRevision)
+	}
+	return staged, nil
+}
+
+// refreshStagingFilter returns a filter for the auto-refresh candidates that
+// holds back new revisions of snaps with a staging delay until the delay for
+// this device has passed since the revision was first seen. It records the
+// first time each new revision is seen in the state.
+func refreshStagingFilter(st *state.State, now time.Time) (updateFilter, error) {
+	delays, err := refreshStagingDelays(st)
+	if err != nil {
+		return nil, err
+	}
+
+	staged, err := stagedRevisions(st)
+	if err != nil {
+		return nil, err
+	}
+	if len(delays) == 0 {
+		if len(staged) > 0 {
+			st.Set("refresh-staging", nil)
+		}
+		return nil, nil
+	}
+	// forget about snaps that are no longer staged
+	for name := range staged {
+		if _, ok := delays[name]; !ok {
+			delete(staged, name)
+		}
+	}
+
+	deviceDelays := make(map[string]time.Duration, len(delays))
+	for name, maxDelay := range delays {
+		delay, err := deviceStagingDelay(st, name, maxDelay)
+		if err != nil {
+			return nil, err
+		}
+		deviceDelays[name] = delay
+	}
+	st.Set("refresh-staging", staged)
+
+	return func(info *snap.Info, snapst *SnapState) bool {
+		name := info.InstanceName()
+		delay, ok := deviceDelays[name]
+		if !ok {
+			return true
+		}
+
+		sr := staged[name]
+		switch {
+		case sr == nil || !sr.pending(snapst):
+			sr = &stagedRevision{Revision: info.Revision, FirstSeen: now}
+			staged[name] = sr
+			st.Set("refresh-staging", staged)
+		case sr.Revision != info.Revision:
+			// a newer revision superseded the one being staged, keep
+			// staging from when the first revision not yet installed
+			// was seen so that frequent releases cannot hold back
+			// the snap forever
+			sr.Revision = info.Revision
+			st.Set("refresh-staging", staged)
+		}
+
+		until := sr.FirstSeen.Add(delay)
+		if now.Before(until) {
+			logger.Debugf("Staging auto-refresh of snap %q to revision %s until %s.", name, info.Revision, until.Format(time.RFC3339))
+			return false
+		}
+		return true
+	}, nil
+}
+
+// RefreshStaging returns the staging state of auto-refreshes for all snaps
+// with a staging delay, keyed by snap instance name.
+func (m *autoRefresh) RefreshStaging() (map[string]*RefreshStaging, error) {
+	delays, err := refreshStagingDelays(m.state)
+	if err != nil {
+		return nil, err
+	}
+	if len(delays) == 0 {
+		return nil, nil
+	}
+
+	staged, err := stagedRevisions(m.state)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]*RefreshStaging, len(delays))
+	for name, maxDelay := range delays {
+		delay, err := deviceStagingDelay(m.state, name, maxDelay)
+		if err != nil {
+			return nil, err
+		}
+		rs := &RefreshStaging{
+			MaxDelay: maxDelay,
+			Delay:    delay,
+		}
+		if sr := staged[name]; sr != nil {
+			var snapst SnapState
+			if err := Get(m.state, name, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+				return nil, err
+			}
+			// only report revisions that are yet to be installed
+			if snapst.Current != sr.Revision {
+				rs.Revision = sr.Revision
+				rs.Until = sr.FirstSeen.Add(delay)
+			}
+		}
+		out[name] = rs
+	}
+	return out, nil
+}
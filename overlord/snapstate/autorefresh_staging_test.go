@@ -0,0 +1,281 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"fmt"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/asserts/assertstest"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func mockSerial(c *C, serial string) *asserts.Serial {
+	privKey, _ := assertstest.GenerateKey(752)
+	encDevKey, err := asserts.EncodePublicKey(privKey.PublicKey())
+	c.Assert(err, IsNil)
+	a := assertstest.FakeAssertion(map[string]any{
+		"type":                "serial",
+		"authority-id":        "my-brand",
+		"brand-id":            "my-brand",
+		"model":               "my-model",
+		"serial":              serial,
+		"device-key":          string(encDevKey),
+		"device-key-sha3-384": privKey.PublicKey().ID(),
+	})
+	return a.(*asserts.Serial)
+}
+
+func (s *autoRefreshTestSuite) setRefreshStaging(c *C, snapName string, delay any) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "refresh.staging."+snapName, delay), IsNil)
+	tr.Commit()
+}
+
+func (s *autoRefreshTestSuite) autoRefreshedSnapNames(c *C) []string {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	for _, chg := range s.state.Changes() {
+		if chg.Kind() != "auto-refresh" {
+			continue
+		}
+		var names []string
+		c.Assert(chg.Get("snap-names", &names), IsNil)
+		return names
+	}
+	return nil
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshStagingHoldsNewRevision(c *C) {
+	s.addRefreshableSnap("foo", "bar")
+	s.setRefreshStaging(c, "foo", "72h")
+
+	s.AddCleanup(snapstate.MockProcessDelayedSecurityBackendEffects(func(st *state.State, lanes []int, joinLane int) (ts *state.TaskSet) {
+		return state.NewTaskSet(st.NewTask("process-delayed-security-backend-effects", "Process delayed backend effects"))
+	}))
+
+	now := time.Now()
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+
+	// without a serial the full delay applies to foo
+	c.Check(s.autoRefreshedSnapNames(c), DeepEquals, []string{"bar"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	staging, err := af.RefreshStaging()
+	c.Assert(err, IsNil)
+	c.Assert(staging, HasLen, 1)
+	c.Assert(staging["foo"], NotNil)
+	c.Check(staging["foo"].MaxDelay, Equals, 72*time.Hour)
+	c.Check(staging["foo"].Delay, Equals, 72*time.Hour)
+	c.Check(staging["foo"].Revision, Equals, snap.R(8))
+	c.Check(staging["foo"].Until.Before(now.Add(72*time.Hour)), Equals, false)
+	c.Check(staging["foo"].Until.After(time.Now().Add(72*time.Hour)), Equals, false)
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshStagingDelayPassed(c *C) {
+	s.addRefreshableSnap("foo", "bar")
+	s.setRefreshStaging(c, "foo", "72h")
+
+	s.AddCleanup(snapstate.MockProcessDelayedSecurityBackendEffects(func(st *state.State, lanes []int, joinLane int) (ts *state.TaskSet) {
+		return state.NewTaskSet(st.NewTask("process-delayed-security-backend-effects", "Process delayed backend effects"))
+	}))
+
+	s.state.Lock()
+	s.state.Set("refresh-staging", map[string]any{
+		"foo": map[string]any{
+			"revision":   "8",
+			"first-seen": time.Now().Add(-73 * time.Hour),
+		},
+		// not staged anymore
+		"baz": map[string]any{
+			"revision":   "3",
+			"first-seen": time.Now(),
+		},
+	})
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+	c.Check(s.autoRefreshedSnapNames(c), DeepEquals, []string{"bar", "foo"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	var staged map[string]any
+	c.Assert(s.state.Get("refresh-staging", &staged), IsNil)
+	c.Check(staged, HasLen, 1)
+	c.Check(staged["foo"], NotNil)
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshStagingNewerRevisionKeepsStageStart(c *C) {
+	s.addRefreshableSnap("foo")
+	s.setRefreshStaging(c, "foo", "72h")
+
+	s.AddCleanup(snapstate.MockProcessDelayedSecurityBackendEffects(func(st *state.State, lanes []int, joinLane int) (ts *state.TaskSet) {
+		return state.NewTaskSet(st.NewTask("process-delayed-security-backend-effects", "Process delayed backend effects"))
+	}))
+
+	firstSeen := time.Now().Add(-50 * time.Hour)
+	s.state.Lock()
+	// revision 5 was never installed and got superseded by revision 8
+	s.state.Set("refresh-staging", map[string]any{
+		"foo": map[string]any{
+			"revision":   "5",
+			"first-seen": firstSeen,
+		},
+	})
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.autoRefreshedSnapNames(c), HasLen, 0)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	staging, err := af.RefreshStaging()
+	c.Assert(err, IsNil)
+	c.Assert(staging["foo"], NotNil)
+	c.Check(staging["foo"].Revision, Equals, snap.R(8))
+	c.Check(staging["foo"].Until.Equal(firstSeen.Add(72*time.Hour)), Equals, true)
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshStagingRestartsAfterInstall(c *C) {
+	s.addRefreshableSnap("foo")
+	s.setRefreshStaging(c, "foo", "72h")
+
+	s.AddCleanup(snapstate.MockProcessDelayedSecurityBackendEffects(func(st *state.State, lanes []int, joinLane int) (ts *state.TaskSet) {
+		return state.NewTaskSet(st.NewTask("process-delayed-security-backend-effects", "Process delayed backend effects"))
+	}))
+
+	s.state.Lock()
+	// the previously staged revision is the installed one
+	s.state.Set("refresh-staging", map[string]any{
+		"foo": map[string]any{
+			"revision":   "1",
+			"first-seen": time.Now().Add(-100 * time.Hour),
+		},
+	})
+	s.state.Unlock()
+
+	now := time.Now()
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.autoRefreshedSnapNames(c), HasLen, 0)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	staging, err := af.RefreshStaging()
+	c.Assert(err, IsNil)
+	c.Assert(staging["foo"], NotNil)
+	c.Check(staging["foo"].Revision, Equals, snap.R(8))
+	c.Check(staging["foo"].Until.Before(now.Add(72*time.Hour)), Equals, false)
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshStagingUnsetClearsState(c *C) {
+	s.addRefreshableSnap("foo")
+
+	s.AddCleanup(snapstate.MockProcessDelayedSecurityBackendEffects(func(st *state.State, lanes []int, joinLane int) (ts *state.TaskSet) {
+		return state.NewTaskSet(st.NewTask("process-delayed-security-backend-effects", "Process delayed backend effects"))
+	}))
+
+	s.state.Lock()
+	s.state.Set("refresh-staging", map[string]any{
+		"foo": map[string]any{
+			"revision":   "8",
+			"first-seen": time.Now(),
+		},
+	})
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.autoRefreshedSnapNames(c), DeepEquals, []string{"foo"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	var staged map[string]any
+	c.Check(s.state.Get("refresh-staging", &staged), testutil.ErrorIs, state.ErrNoState)
+
+	staging, err := af.RefreshStaging()
+	c.Assert(err, IsNil)
+	c.Check(staging, HasLen, 0)
+}
+
+func (s *autoRefreshTestSuite) TestDeviceStagingDelayFromSerial(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	serials := make(map[string]*asserts.Serial)
+	restore := snapstate.MockDeviceSerial(func(st *state.State) (*asserts.Serial, error) {
+		var serial string
+		c.Assert(st.Get("test-serial", &serial), IsNil)
+		return serials[serial], nil
+	})
+	defer restore()
+
+	delays := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		serial := fmt.Sprintf("serial-%d", i)
+		serials[serial] = mockSerial(c, serial)
+		s.state.Set("test-serial", serial)
+
+		delay, err := snapstate.DeviceStagingDelay(s.state, "foo", 72*time.Hour)
+		c.Assert(err, IsNil)
+		c.Check(delay >= 0 && delay <= 72*time.Hour, Equals, true)
+		c.Check(delay%time.Hour, Equals, time.Duration(0))
+		delays[delay] = true
+
+		// stable for the same device and snap
+		again, err := snapstate.DeviceStagingDelay(s.state, "foo", 72*time.Hour)
+		c.Assert(err, IsNil)
+		c.Check(again, Equals, delay)
+	}
+	// the delays are spread over the devices
+	c.Check(len(delays) > 1, Equals, true)
+
+	// without a serial the full delay applies
+	restore = snapstate.MockDeviceSerial(func(st *state.State) (*asserts.Serial, error) {
+		return nil, state.ErrNoState
+	})
+	defer restore()
+	delay, err := snapstate.DeviceStagingDelay(s.state, "foo", 72*time.Hour)
+	c.Assert(err, IsNil)
+	c.Check(delay, Equals, 72*time.Hour)
+}
@@ -1158,6 +1158,194 @@ func (s *autoRefreshTestSuite) TestAutoRefreshCreatesBothRefreshAndPreDownload(c
 	c.Check(names, DeepEquals, []string{"bar"})
 }
 
+func weekdayStr(t time.Time) string {
+	return strings.ToLower(t.Weekday().String()[:3])
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshSnapTimerWindows(c *C) {
+	s.addRefreshableSnap("foo", "bar", "baz")
+
+	s.AddCleanup(snapstate.MockProcessDelayedSecurityBackendEffects(func(st *state.State, lanes []int, joinLane int) (ts *state.TaskSet) {
+		return state.NewTaskSet(st.NewTask("process-delayed-security-backend-effects", "Process delayed backend effects"))
+	}))
+
+	now := time.Now()
+	s.state.Lock()
+	tr := config.NewTransaction(s.state)
+	// foo only refreshes on a day that is not today, bar refreshes any time
+	tr.Set("core", "refresh.snap-timer.foo", weekdayStr(now.AddDate(0, 0, 1))+",00:00-23:59")
+	tr.Set("core", "refresh.snap-timer.bar", weekdayStr(now)+",00:00-23:59")
+	tr.Commit()
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chgs := s.state.Changes()
+	c.Assert(chgs, HasLen, 1)
+	c.Assert(chgs[0].Kind(), Equals, "auto-refresh")
+	var names []string
+	err = chgs[0].Get("snap-names", &names)
+	c.Assert(err, IsNil)
+	c.Check(names, DeepEquals, []string{"bar", "baz"})
+
+	snapTimers, err := af.RefreshSnapTimers()
+	c.Assert(err, IsNil)
+	c.Check(snapTimers, DeepEquals, map[string]string{
+		"foo": weekdayStr(now.AddDate(0, 0, 1)) + ",00:00-23:59",
+		"bar": weekdayStr(now) + ",00:00-23:59",
+	})
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshGroupTimerWindows(c *C) {
+	s.addRefreshableSnap("foo", "bar", "baz")
+
+	s.AddCleanup(snapstate.MockProcessDelayedSecurityBackendEffects(func(st *state.State, lanes []int, joinLane int) (ts *state.TaskSet) {
+		return state.NewTaskSet(st.NewTask("process-delayed-security-backend-effects", "Process delayed backend effects"))
+	}))
+
+	now := time.Now()
+	tomorrow := weekdayStr(now.AddDate(0, 0, 1)) + ",00:00-23:59"
+	today := weekdayStr(now) + ",00:00-23:59"
+	s.state.Lock()
+	tr := config.NewTransaction(s.state)
+	// the db group only refreshes on a day that is not today
+	tr.Set("core", "refresh.group-timer.db", tomorrow)
+	tr.Set("core", "refresh.group-snaps.db", "foo,bar")
+	// the snap's own timer takes precedence over its group
+	tr.Set("core", "refresh.snap-timer.bar", today)
+	tr.Commit()
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chgs := s.state.Changes()
+	c.Assert(chgs, HasLen, 1)
+	c.Assert(chgs[0].Kind(), Equals, "auto-refresh")
+	var names []string
+	err = chgs[0].Get("snap-names", &names)
+	c.Assert(err, IsNil)
+	c.Check(names, DeepEquals, []string{"bar", "baz"})
+
+	snapTimers, err := af.RefreshSnapTimers()
+	c.Assert(err, IsNil)
+	c.Check(snapTimers, DeepEquals, map[string]string{
+		"foo": tomorrow,
+		"bar": today,
+	})
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshSnapTimerBringsNextRefreshForward(c *C) {
+	now := time.Now()
+	s.state.Lock()
+	s.state.Set("last-refresh", now)
+	tr := config.NewTransaction(s.state)
+	tr.Set("core", "refresh.timer", weekdayStr(now.AddDate(0, 0, 3))+",12:00-13:00")
+	tr.Set("core", "refresh.snap-timer.some-snap", weekdayStr(now.AddDate(0, 0, 1))+",12:00-13:00")
+	// not installed, ignored
+	tr.Set("core", "refresh.snap-timer.other-snap", weekdayStr(now)+",00:00-23:59")
+	tr.Commit()
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, HasLen, 0)
+
+	// the per-snap window of some-snap comes first
+	next := af.NextRefresh()
+	c.Check(weekdayStr(next), Equals, weekdayStr(now.AddDate(0, 0, 1)))
+	c.Check(next.Before(now.Add(49*time.Hour)), Equals, true)
+
+	// changing the per-snap timer resets the next refresh
+	s.state.Lock()
+	tr = config.NewTransaction(s.state)
+	tr.Set("core", "refresh.snap-timer.some-snap", nil)
+	tr.Commit()
+	s.state.Unlock()
+
+	err = af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, HasLen, 0)
+	c.Check(weekdayStr(af.NextRefresh()), Equals, weekdayStr(now.AddDate(0, 0, 3)))
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshBlackout(c *C) {
+	now := time.Now()
+	s.state.Lock()
+	s.state.Set("last-refresh", now.Add(-12*time.Hour))
+	tr := config.NewTransaction(s.state)
+	tr.Set("core", "refresh.blackout", now.Format("2006-01-02"))
+	tr.Commit()
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, HasLen, 0)
+
+	y, m, d := now.Date()
+	blackoutEnd := time.Date(y, m, d+1, 0, 0, 0, 0, time.Local)
+	c.Check(af.NextRefresh().Before(blackoutEnd), Equals, false)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	blackout, err := af.RefreshBlackout()
+	c.Assert(err, IsNil)
+	c.Check(blackout, Equals, now.Format("2006-01-02")+".."+now.Format("2006-01-02"))
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshBlackoutHonoursSnapTimers(c *C) {
+	now := time.Now()
+	s.state.Lock()
+	s.state.Set("last-refresh", now.Add(-48*time.Hour))
+	tr := config.NewTransaction(s.state)
+	tr.Set("core", "refresh.blackout", now.Format("2006-01-02"))
+	tr.Set("core", "refresh.timer", weekdayStr(now.AddDate(0, 0, 3))+",12:00-13:00")
+	// some-snap may refresh any day
+	tr.Set("core", "refresh.snap-timer.some-snap", "00:00-23:59")
+	tr.Commit()
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, HasLen, 0)
+
+	// after the blackout the per-snap window of some-snap comes first
+	y, m, d := now.Date()
+	blackoutEnd := time.Date(y, m, d+1, 0, 0, 0, 0, time.Local)
+	next := af.NextRefresh()
+	c.Check(next.Before(blackoutEnd), Equals, false)
+	c.Check(weekdayStr(next), Equals, weekdayStr(now.AddDate(0, 0, 1)))
+}
+
+func (s *autoRefreshTestSuite) TestAutoRefreshBlackoutIgnoredWhenOverdue(c *C) {
+	now := time.Now()
+	s.state.Lock()
+	s.state.Set("last-refresh", now.Add(-snapstate.MaxPostponement))
+	tr := config.NewTransaction(s.state)
+	tr.Set("core", "refresh.blackout", now.Format("2006-01-02"))
+	tr.Commit()
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+}
+
 func checkPreDownloadChange(c *C, chg *state.Change, name string, rev snap.Revision) {
 	c.Assert(chg.Kind(), Equals, "pre-download")
 	c.Assert(chg.Summary(), Equals, fmt.Sprintf(`Pre-download "%s" for auto-refresh`, name))
@@ -0,0 +1,172 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"sort"
+
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// AutoremoveCandidates returns the sorted instance names of the snaps that
+// were installed only as prerequisites of other snaps (as their base or as
+// the default provider of one of their content plugs) and that are no longer
+// needed by any installed snap. It is meant to preview what Autoremove would
+// remove.
+// Note that the state must be locked by the caller.
+func AutoremoveCandidates(st *state.State) ([]string, error) {
+	snapStates, err := All(st)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceCtx, err := DeviceCtxFromState(st, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// snaps that are known to be needed, either because they were
+	// installed explicitly or because something else needs them
+	needed := make(map[string]bool, len(snapStates))
+	for name, snapst := range snapStates {
+		if !snapst.Prerequisite || snapst.Required || !isAutoremovableType(snapst) {
+			needed[name] = true
+		}
+	}
+
+	for {
+		if err := markNeededPrerequisites(st, snapStates, needed); err != nil {
+			return nil, err
+		}
+
+		candidates := make(map[string]bool, len(snapStates))
+		for name := range snapStates {
+			if !needed[name] {
+				candidates[name] = true
+			}
+		}
+
+		// a candidate may still be protected by policy (e.g. the boot
+		// base of the model) or by validation sets, in which case it and
+		// everything it depends on must be kept
+		kept := false
+		for name := range candidates {
+			snapst := snapStates[name]
+			info, err := snapst.CurrentInfo()
+			if err != nil {
+				return nil, err
+			}
+			if err := canRemove(st, info, snapst, true, deviceCtx, candidates); err != nil {
+				needed[name] = true
+				kept = true
+			}
+		}
+		if kept {
+			continue
+		}
+
+		names := make([]string, 0, len(candidates))
+		for name := range candidates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+}
+
+// Autoremove removes the snaps that were installed only as prerequisites of
+// other snaps and that are no longer needed by any installed snap, as
+// returned by AutoremoveCandidates. It returns the names of the snaps being
+// removed and the task sets to do so, or nothing if there is nothing to
+// remove.
+// Note that the state must be locked by the caller.
+func Autoremove(st *state.State, flags *RemoveFlags) ([]string, []*state.TaskSet, error) {
+	names, err := AutoremoveCandidates(st)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil, nil
+	}
+
+	return RemoveMany(st, names, flags)
+}
+
+// isAutoremovableType returns whether a snap of the given type may ever be
+// removed automatically. Essential snaps are never removed this way, even if
+// they were originally pulled in as prerequisites.
+func isAutoremovableType(snapst *SnapState) bool {
+	typ, err := snapst.Type()
+	if err != nil {
+		return false
+	}
+	switch typ {
+	case snap.TypeSnapd, snap.TypeOS, snap.TypeKernel, snap.TypeGadget:
+		return false
+	}
+	return true
+}
+
+// markNeededPrerequisites extends needed with all the snaps that the already
+// needed snaps depend on, transitively, through the bases of any of their
+// revisions or the default providers of their content plugs.
+func markNeededPrerequisites(st *state.State, snapStates map[string]*SnapState, needed map[string]bool) error {
+	queue := make([]string, 0, len(needed))
+	for name := range needed {
+		queue = append(queue, name)
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		snapst := snapStates[name]
+		if snapst == nil {
+			continue
+		}
+
+		deps, err := basesInUseForSequence(st, snapst)
+		if err != nil {
+			return err
+		}
+		for _, si := range snapst.Sequence.SideInfos() {
+			info, err := snap.ReadInfo(name, si)
+			if err != nil {
+				// like for bases, broken revisions cannot
+				// tell us what they need
+				continue
+			}
+			for provider := range snap.NeededDefaultProviders(info) {
+				deps = append(deps, provider)
+			}
+		}
+
+		for _, dep := range deps {
+			if needed[dep] {
+				continue
+			}
+			needed[dep] = true
+			queue = append(queue, dep)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func (s *snapmgrTestSuite) mockAutoremoveSnap(c *C, snapYaml string, prereq bool) {
+	name := snaptest.MockInfo(c, snapYaml, nil).SnapName()
+	info := snaptest.MockSnapCurrent(c, snapYaml, &snap.SideInfo{RealName: name, Revision: snap.R(1)})
+	snapstate.Set(s.state, info.InstanceName(), &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{&info.SideInfo}),
+		Current:  info.Revision,
+		Active:   true,
+		SnapType: string(info.Type()),
+		Flags:    snapstate.Flags{Prerequisite: prereq},
+	})
+}
+
+func (s *snapmgrTestSuite) mockAutoremoveSnaps(c *C) {
+	// explicitly installed app, using a base and a content provider
+	s.mockAutoremoveSnap(c, `name: some-snap
+version: 1
+base: some-base
+plugs:
+  foo:
+    interface: content
+    content: foo
+    default-provider: provider-snap
+`, false)
+	// prerequisites still in use
+	s.mockAutoremoveSnap(c, "name: some-base\nversion: 1\ntype: base\n", true)
+	s.mockAutoremoveSnap(c, `name: provider-snap
+version: 1
+base: some-base
+slots:
+  foo:
+    interface: content
+    content: foo
+`, true)
+	// prerequisites of snaps that are gone, including the base of an
+	// unused content provider
+	s.mockAutoremoveSnap(c, "name: unused-base\nversion: 1\ntype: base\n", true)
+	s.mockAutoremoveSnap(c, "name: other-base\nversion: 1\ntype: base\n", true)
+	s.mockAutoremoveSnap(c, `name: unused-provider
+version: 1
+base: other-base
+slots:
+  bar:
+    interface: content
+    content: bar
+`, true)
+	// essential snaps are never removed automatically
+	s.mockAutoremoveSnap(c, "name: snapd\nversion: 1\ntype: snapd\n", true)
+	s.mockAutoremoveSnap(c, "name: core\nversion: 1\ntype: os\n", true)
+}
+
+func (s *snapmgrTestSuite) TestAutoremoveCandidates(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockAutoremoveSnaps(c)
+
+	names, err := snapstate.AutoremoveCandidates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(names, DeepEquals, []string{"other-base", "unused-base", "unused-provider"})
+}
+
+func (s *snapmgrTestSuite) TestAutoremoveCandidatesExplicitUser(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockAutoremoveSnaps(c)
+	// unused-provider is now needed by an explicitly installed snap
+	s.mockAutoremoveSnap(c, `name: other-snap
+version: 1
+base: unused-base
+plugs:
+  bar:
+    interface: content
+    content: bar
+    default-provider: unused-provider:bar
+`, false)
+
+	names, err := snapstate.AutoremoveCandidates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(names, HasLen, 0)
+}
+
+func (s *snapmgrTestSuite) TestAutoremoveCandidatesRequired(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockAutoremoveSnaps(c)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "unused-provider", &snapst), IsNil)
+	snapst.Required = true
+	snapstate.Set(s.state, "unused-provider", &snapst)
+
+	names, err := snapstate.AutoremoveCandidates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(names, DeepEquals, []string{"unused-base"})
+}
+
+func (s *snapmgrTestSuite) TestAutoremoveCandidatesKeepsBootBase(c *C) {
+	s.AddCleanup(snapstatetest.MockDeviceModel(ModelWithBase("core16")))
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockAutoremoveSnaps(c)
+	s.mockAutoremoveSnap(c, "name: core16\nversion: 1\ntype: base\n", true)
+
+	names, err := snapstate.AutoremoveCandidates(s.state)
+	c.Assert(err, IsNil)
+	c.Check(names, DeepEquals, []string{"other-base", "unused-base", "unused-provider"})
+}
+
+func (s *snapmgrTestSuite) TestAutoremove(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockAutoremoveSnaps(c)
+
+	removed, tss, err := snapstate.Autoremove(s.state, nil)
+	c.Assert(err, IsNil)
+	c.Check(removed, testutil.DeepUnsortedMatches, []string{"other-base", "unused-base", "unused-provider"})
+	c.Assert(tss, HasLen, 3)
+
+	// the base of the content provider is removed after it
+	var otherBaseTS, providerTS int
+	for i, name := range removed {
+		switch name {
+		case "other-base":
+			otherBaseTS = i
+		case "unused-provider":
+			providerTS = i
+		}
+	}
+	baseFirstTask := tss[otherBaseTS].Tasks()[0]
+	providerLastTask := tss[providerTS].Tasks()[len(tss[providerTS].Tasks())-1]
+	c.Check(baseFirstTask.WaitTasks(), testutil.Contains, providerLastTask)
+}
+
+func (s *snapmgrTestSuite) TestAutoremoveNothingToDo(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockAutoremoveSnap(c, "name: some-snap\nversion: 1\n", false)
+	s.mockAutoremoveSnap(c, "name: core\nversion: 1\ntype: os\n", true)
+
+	removed, tss, err := snapstate.Autoremove(s.state, nil)
+	c.Assert(err, IsNil)
+	c.Check(removed, IsNil)
+	c.Check(tss, IsNil)
+}
@@ -193,6 +193,8 @@ var (
 	NewAutoRefresh                = newAutoRefresh
 	NewRefreshHints               = newRefreshHints
 	CanRefreshOnMeteredConnection = canRefreshOnMeteredConnection
+	MaxPostponement               = maxPostponement
+	DeviceStagingDelay            = deviceStagingDelay
 
 	NewCatalogRefresh       = newCatalogRefresh
 	CatalogRefreshDelayBase = catalogRefreshDelayBase
@@ -469,6 +471,14 @@ var (
 	ExcludeFromRefreshAppAwareness = excludeFromRefreshAppAwareness
 )
 
+func MockDeviceSerial(f func(st *state.State) (*asserts.Serial, error)) (restore func()) {
+	old := DeviceSerial
+	DeviceSerial = f
+	return func() {
+		DeviceSerial = old
+	}
+}
+
 func MockTimeNow(f func() time.Time) (restore func()) {
 	old := timeNow
 	timeNow = f
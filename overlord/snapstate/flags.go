@@ -88,6 +88,16 @@ type Flags struct {
 	// long as refresh assets are cached.
 	NoReRefresh bool `json:"no-rerefresh,omitempty"`
 
+	// Prerequisite is set when a snap is installed only because another
+	// snap needs it, either as its base or as the default provider of one
+	// of its content plugs. It is recorded on first install so that such
+	// snaps can be removed again once nothing needs them anymore.
+	Prerequisite bool `json:"prerequisite,omitempty"`
+
+	// Explicit is set when the user asked for the snap by name. Linking
+	// the snap then clears Prerequisite, as it is wanted from now on.
+	Explicit bool `json:"explicit,omitempty"`
+
 	// RequireTypeBase is set to mark that a snap needs to be of type: base,
 	// otherwise installation fails.
 	RequireTypeBase bool `json:"require-base-type,omitempty"`
@@ -0,0 +1,449 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// maxSnapSetGenerations is the number of snap set generations that are
+// remembered, older ones are dropped.
+const maxSnapSetGenerations = 10
+
+// SnapSetGeneration records the effect of a successful change that
+// installed or refreshed a set of snaps with an "all-snaps" transaction,
+// so that the whole set can later be reverted in one go.
+type SnapSetGeneration struct {
+	ID       int       `json:"id"`
+	ChangeID string    `json:"change-id"`
+	Time     time.Time `json:"time"`
+
+	Snaps []*SnapSetGenerationSnap `json:"snaps"`
+
+	// ConnectionsBefore and ConnectionsAfter are the interface
+	// connections involving the snaps of the set, before and after the
+	// change.
+	ConnectionsBefore []SnapSetConnection `json:"connections-before,omitempty"`
+	ConnectionsAfter  []SnapSetConnection `json:"connections-after,omitempty"`
+}
+
+// SnapSetGenerationSnap is the state of a single snap of a snap set
+// generation before and after the change.
+type SnapSetGenerationSnap struct {
+	InstanceName string `json:"name"`
+	// Before is unset if the snap was installed by the change.
+	Before       snap.Revision    `json:"before"`
+	After        snap.Revision    `json:"after"`
+	ConfigBefore *json.RawMessage `json:"config-before,omitempty"`
+	ConfigAfter  *json.RawMessage `json:"config-after,omitempty"`
+}
+
+// SnapSetConnection is an interface connection recorded as part of a snap
+// set generation.
+type SnapSetConnection struct {
+	// ID is the connection reference, as in "plug-snap:plug slot-snap:slot".
+	ID        string `json:"id"`
+	Interface string `json:"interface"`
+	Auto      bool   `json:"auto,omitempty"`
+	ByGadget  bool   `json:"by-gadget,omitempty"`
+	ByModel   bool   `json:"by-model,omitempty"`
+}
+
+// SnapSetConnections returns the active interface connections involving
+// any of the given snaps, sorted by connection reference. It is set by the
+// interfaces manager.
+var SnapSetConnections = func(st *state.State, snaps []string) ([]SnapSetConnection, error) {
+	panic("internal error: snapstate.SnapSetConnections is unset")
+}
+
+// snapSetBefore is what is captured in the change when its first task runs,
+// before anything is modified.
+type snapSetBefore struct {
+	Configs     map[string]*json.RawMessage `json:"configs,omitempty"`
+	Connections []SnapSetConnection         `json:"connections,omitempty"`
+}
+
+// captureSnapSetBefore records the configuration and interface connections
+// of all the snaps of an "all-snaps" transaction in its change, the first
+// time it is called for the change. It must be called before the change
+// modifies any of them.
+func captureSnapSetBefore(t *state.Task, snapsup *SnapSetup) error {
+	if snapsup.Transaction != client.TransactionAllSnaps || snapsup.Revert {
+		return nil
+	}
+	chg := t.Change()
+	// seeding is not something that can be reverted
+	if chg == nil || chg.Kind() == "seed" || chg.Has("snap-set-before") {
+		return nil
+	}
+
+	st := t.State()
+	names, err := transactionSnapNames(chg)
+	if err != nil {
+		return err
+	}
+
+	before := snapSetBefore{
+		Configs: make(map[string]*json.RawMessage, len(names)),
+	}
+	for _, name := range names {
+		cfg, err := config.GetSnapConfig(st, name)
+		if err != nil {
+			return err
+		}
+		if cfg != nil {
+			before.Configs[name] = cfg
+		}
+	}
+	before.Connections, err = SnapSetConnections(st, names)
+	if err != nil {
+		return err
+	}
+
+	chg.Set("snap-set-before", before)
+	return nil
+}
+
+// transactionSnapNames returns the sorted names of the snaps installed or
+// refreshed by the "all-snaps" transaction of the given change.
+func transactionSnapNames(chg *state.Change) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range chg.Tasks() {
+		if t.Kind() != "link-snap" {
+			continue
+		}
+		snapsup, err := TaskSnapSetup(t)
+		if err != nil {
+			return nil, err
+		}
+		if snapsup.Transaction != client.TransactionAllSnaps || snapsup.Revert {
+			continue
+		}
+		name := snapsup.InstanceName()
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// processSnapSetGeneration records a new snap set generation when a change
+// carrying an "all-snaps" transaction completes successfully.
+func processSnapSetGeneration(chg *state.Change, _ state.Status, new state.Status) {
+	if new != state.DoneStatus || !chg.Has("snap-set-before") {
+		return
+	}
+
+	if err := recordSnapSetGeneration(chg); err != nil {
+		logger.Noticef("cannot record snap set generation for change %s: %v", chg.ID(), err)
+	}
+}
+
+func recordSnapSetGeneration(chg *state.Change) error {
+	st := chg.State()
+
+	var before snapSetBefore
+	if err := chg.Get("snap-set-before", &before); err != nil {
+		return err
+	}
+
+	gen := &SnapSetGeneration{
+		ChangeID:          chg.ID(),
+		Time:              timeNow(),
+		ConnectionsBefore: before.Connections,
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range chg.Tasks() {
+		if t.Kind() != "link-snap" || t.Status() != state.DoneStatus {
+			continue
+		}
+		snapsup, err := TaskSnapSetup(t)
+		if err != nil {
+			return err
+		}
+		name := snapsup.InstanceName()
+		if snapsup.Transaction != client.TransactionAllSnaps || snapsup.Revert || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		var oldCurrent snap.Revision
+		if err := t.Get("old-current", &oldCurrent); err != nil && !errors.Is(err, state.ErrNoState) {
+			return err
+		}
+		cfg, err := config.GetSnapConfig(st, name)
+		if err != nil {
+			return err
+		}
+		gen.Snaps = append(gen.Snaps, &SnapSetGenerationSnap{
+			InstanceName: name,
+			Before:       oldCurrent,
+			After:        snapsup.Revision(),
+			ConfigBefore: before.Configs[name],
+			ConfigAfter:  cfg,
+		})
+		names = append(names, name)
+	}
+	if len(gen.Snaps) == 0 {
+		return nil
+	}
+	sort.Slice(gen.Snaps, func(i, j int) bool {
+		return gen.Snaps[i].InstanceName < gen.Snaps[j].InstanceName
+	})
+
+	var err error
+	gen.ConnectionsAfter, err = SnapSetConnections(st, names)
+	if err != nil {
+		return err
+	}
+
+	gens, err := SnapSetGenerations(st)
+	if err != nil {
+		return err
+	}
+	gen.ID = 1
+	if len(gens) > 0 {
+		gen.ID = gens[len(gens)-1].ID + 1
+	}
+	gens = append(gens, gen)
+	if len(gens) > maxSnapSetGenerations {
+		gens = gens[len(gens)-maxSnapSetGenerations:]
+	}
+	st.Set("snap-set-generations", gens)
+	return nil
+}
+
+// SnapSetGenerations returns the remembered snap set generations, oldest
+// first.
+func SnapSetGenerations(st *state.State) ([]*SnapSetGeneration, error) {
+	var gens []*SnapSetGeneration
+	if err := st.Get("snap-set-generations", &gens); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	return gens, nil
+}
+
+// RevertSnapSetGeneration returns the task sets to revert all the snaps of
+// the given snap set generation to the revisions they had before it, as a
+// single transaction. The configuration the reverted snaps had before the
+// generation and the interface connections of the snaps are put back as
+// they were, and the snaps that were installed by the generation are then
+// removed according to flags. All the snaps must still be at the revisions
+// the generation left them at.
+// Note that the state must be locked by the caller.
+func RevertSnapSetGeneration(st *state.State, id int, flags *RemoveFlags, fromChange string) ([]*state.TaskSet, error) {
+	gens, err := SnapSetGenerations(st)
+	if err != nil {
+		return nil, err
+	}
+	var gen *SnapSetGeneration
+	for _, g := range gens {
+		if g.ID == id {
+			gen = g
+			break
+		}
+	}
+	if gen == nil {
+		return nil, fmt.Errorf("cannot find snap set generation %d", id)
+	}
+
+	names := make([]string, 0, len(gen.Snaps))
+	for _, gs := range gen.Snaps {
+		var snapst SnapState
+		if err := Get(st, gs.InstanceName, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+			return nil, err
+		}
+		if !snapst.IsInstalled() {
+			return nil, fmt.Errorf("cannot revert snap set generation %d: snap %q is no longer installed", id, gs.InstanceName)
+		}
+		if snapst.Current != gs.After {
+			return nil, fmt.Errorf("cannot revert snap set generation %d: snap %q is at revision %s instead of %s", id, gs.InstanceName, snapst.Current, gs.After)
+		}
+		names = append(names, gs.InstanceName)
+	}
+
+	lane := st.NewLane()
+	revertFlags := Flags{
+		Transaction: client.TransactionAllSnaps,
+		Lane:        lane,
+	}
+
+	var tss []*state.TaskSet
+	var installed, changed []string
+	configs := make(map[string]*json.RawMessage)
+	for _, gs := range gen.Snaps {
+		if gs.Before.Unset() {
+			installed = append(installed, gs.InstanceName)
+			continue
+		}
+		cfg, err := config.GetSnapConfig(st, gs.InstanceName)
+		if err != nil {
+			return nil, err
+		}
+		if !sameSnapConfig(cfg, gs.ConfigAfter) {
+			changed = append(changed, gs.InstanceName)
+		}
+		configs[gs.InstanceName] = gs.ConfigBefore
+
+		ts, err := RevertToRevision(st, gs.InstanceName, gs.Before, revertFlags, fromChange)
+		if err != nil {
+			return nil, err
+		}
+		ts.JoinLane(lane)
+		tss = append(tss, ts)
+	}
+	reverts := tss
+
+	// the reverted revisions bring back the configuration they were last
+	// used with, which is not necessarily the one the snaps had right
+	// before the generation
+	restoreConfig := st.NewTask("restore-snap-set-config", fmt.Sprintf(i18n.G("Restore configuration of snap set generation %d"), id))
+	restoreConfig.Set("generation", id)
+	restoreConfig.Set("configs", configs)
+	if len(changed) > 0 {
+		restoreConfig.Set("changed-snaps", changed)
+	}
+	for _, ts := range reverts {
+		restoreConfig.WaitAll(ts)
+	}
+	restoreConfigTS := state.NewTaskSet(restoreConfig)
+	restoreConfigTS.JoinLane(lane)
+	tss = append(tss, restoreConfigTS)
+
+	restoreConns := st.NewTask("restore-connections", fmt.Sprintf(i18n.G("Restore interface connections of snap set generation %d"), id))
+	restoreConns.Set("snaps", names)
+	restoreConns.Set("connections", gen.ConnectionsBefore)
+	restoreConns.WaitFor(restoreConfig)
+	restoreConnsTS := state.NewTaskSet(restoreConns)
+	restoreConnsTS.JoinLane(lane)
+	tss = append(tss, restoreConnsTS)
+
+	if len(installed) > 0 {
+		// snaps that did not exist before are removed last, once
+		// everything else is back in place, as discarding them cannot
+		// be undone
+		_, removals, err := RemoveMany(st, installed, flags)
+		if err != nil {
+			return nil, err
+		}
+		for _, ts := range removals {
+			ts.WaitAll(restoreConnsTS)
+			ts.JoinLane(lane)
+		}
+		tss = append(tss, removals...)
+	}
+
+	return tss, nil
+}
+
+func sameSnapConfig(a, b *json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return bytes.Equal(*a, *b)
+}
+
+func restoreSnapSetConfigAffectedSnaps(t *state.Task) ([]string, error) {
+	var configs map[string]*json.RawMessage
+	if err := t.Get("configs", &configs); err != nil {
+		return nil, fmt.Errorf("internal error: cannot get snaps of %s task %s", t.Kind(), t.ID())
+	}
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (m *SnapManager) doRestoreSnapSetConfig(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var id int
+	if err := t.Get("generation", &id); err != nil {
+		return err
+	}
+	var configs map[string]*json.RawMessage
+	if err := t.Get("configs", &configs); err != nil {
+		return err
+	}
+	var changed []string
+	if err := t.Get("changed-snaps", &changed); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	for _, name := range changed {
+		t.Logf("Configuration of snap %q was modified after snap set generation %d, the modifications are discarded", name, id)
+	}
+
+	old := make(map[string]*json.RawMessage, len(configs))
+	for name, cfg := range configs {
+		cur, err := config.GetSnapConfig(st, name)
+		if err != nil {
+			return err
+		}
+		old[name] = cur
+		if err := config.SetSnapConfig(st, name, cfg); err != nil {
+			return err
+		}
+	}
+	t.Set("old-configs", old)
+	return nil
+}
+
+func (m *SnapManager) undoRestoreSnapSetConfig(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var old map[string]*json.RawMessage
+	if err := t.Get("old-configs", &old); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil
+		}
+		return err
+	}
+	for name, cfg := range old {
+		if err := config.SetSnapConfig(st, name, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func (s *snapmgrTestSuite) mockSnapSetSnaps(c *C, revs ...snap.Revision) {
+	for _, name := range []string{"some-snap", "some-other-snap"} {
+		var sis []*snap.SideInfo
+		for _, rev := range revs {
+			si := &snap.SideInfo{
+				RealName: name,
+				Revision: rev,
+				SnapID:   name + "-id",
+			}
+			snaptest.MockSnap(c, "name: "+name, si)
+			sis = append(sis, si)
+		}
+		snapstate.Set(s.state, name, &snapstate.SnapState{
+			Active:          true,
+			Sequence:        snapstatetest.NewSequenceFromSnapSideInfos(sis),
+			Current:         revs[len(revs)-1],
+			SnapType:        "app",
+			TrackingChannel: "latest/stable",
+		})
+	}
+}
+
+func (s *snapmgrTestSuite) TestSnapSetGenerationRecorded(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapSetSnaps(c, snap.R(1))
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("some-snap", "foo", "bar"), IsNil)
+	tr.Commit()
+
+	chg := s.state.NewChange("refresh", "refresh some snaps")
+	updated, tss, err := snapstate.UpdateMany(context.Background(), s.state,
+		[]string{"some-snap", "some-other-snap"}, nil, 0,
+		&snapstate.Flags{Transaction: client.TransactionAllSnaps})
+	c.Assert(err, IsNil)
+	c.Assert(updated, HasLen, 2)
+	for _, ts := range tss {
+		chg.AddAll(ts)
+	}
+
+	s.settle(c)
+	c.Assert(chg.Err(), IsNil)
+
+	gens, err := snapstate.SnapSetGenerations(s.state)
+	c.Assert(err, IsNil)
+	c.Assert(gens, HasLen, 1)
+	gen := gens[0]
+	c.Check(gen.ID, Equals, 1)
+	c.Check(gen.ChangeID, Equals, chg.ID())
+	c.Assert(gen.Snaps, HasLen, 2)
+	c.Check(gen.Snaps[0].InstanceName, Equals, "some-other-snap")
+	c.Check(gen.Snaps[0].Before, Equals, snap.R(1))
+	c.Check(gen.Snaps[0].After, Equals, snap.R(11))
+	c.Check(gen.Snaps[0].ConfigBefore, IsNil)
+	c.Check(gen.Snaps[1].InstanceName, Equals, "some-snap")
+	c.Check(gen.Snaps[1].Before, Equals, snap.R(1))
+	c.Check(gen.Snaps[1].After, Equals, snap.R(11))
+	c.Assert(gen.Snaps[1].ConfigBefore, NotNil)
+	c.Check(string(*gen.Snaps[1].ConfigBefore), Equals, `{"foo":"bar"}`)
+}
+
+func (s *snapmgrTestSuite) TestSnapSetGenerationNotRecordedPerSnap(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapSetSnaps(c, snap.R(1))
+
+	chg := s.state.NewChange("refresh", "refresh some snaps")
+	_, tss, err := snapstate.UpdateMany(context.Background(), s.state,
+		[]string{"some-snap", "some-other-snap"}, nil, 0,
+		&snapstate.Flags{Transaction: client.TransactionPerSnap})
+	c.Assert(err, IsNil)
+	for _, ts := range tss {
+		chg.AddAll(ts)
+	}
+
+	s.settle(c)
+	c.Assert(chg.Err(), IsNil)
+
+	gens, err := snapstate.SnapSetGenerations(s.state)
+	c.Assert(err, IsNil)
+	c.Check(gens, HasLen, 0)
+}
+
+func (s *snapmgrTestSuite) TestSnapSetGenerationNotRecordedOnFailure(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapSetSnaps(c, snap.R(1))
+	s.fakeStore.downloadError["some-other-snap"] = fmt.Errorf("boom")
+
+	chg := s.state.NewChange("refresh", "refresh some snaps")
+	_, tss, err := snapstate.UpdateMany(context.Background(), s.state,
+		[]string{"some-snap", "some-other-snap"}, nil, 0,
+		&snapstate.Flags{Transaction: client.TransactionAllSnaps})
+	c.Assert(err, IsNil)
+	for _, ts := range tss {
+		chg.AddAll(ts)
+	}
+
+	s.settle(c)
+	c.Assert(chg.Err(), NotNil)
+
+	gens, err := snapstate.SnapSetGenerations(s.state)
+	c.Assert(err, IsNil)
+	c.Check(gens, HasLen, 0)
+}
+
+func (s *snapmgrTestSuite) TestRevertSnapSetGeneration(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapSetSnaps(c, snap.R(1), snap.R(2))
+	si := &snap.SideInfo{RealName: "new-snap", Revision: snap.R(3), SnapID: "new-snap-id"}
+	snaptest.MockSnap(c, "name: new-snap", si)
+	snapstate.Set(s.state, "new-snap", &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  si.Revision,
+		SnapType: "app",
+	})
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("some-snap", "foo", "baz"), IsNil)
+	c.Assert(tr.Set("some-other-snap", "foo", "qux"), IsNil)
+	tr.Commit()
+
+	conns := []snapstate.SnapSetConnection{{
+		ID:        "some-snap:network core:network",
+		Interface: "network",
+		Auto:      true,
+	}}
+	before := json.RawMessage(`{"foo":"bar"}`)
+	after := json.RawMessage(`{"foo":"baz"}`)
+	s.state.Set("snap-set-generations", []*snapstate.SnapSetGeneration{{
+		ID: 4,
+		Snaps: []*snapstate.SnapSetGenerationSnap{
+			{InstanceName: "new-snap", After: snap.R(3)},
+			{InstanceName: "some-other-snap", Before: snap.R(1), After: snap.R(2)},
+			{InstanceName: "some-snap", Before: snap.R(1), After: snap.R(2), ConfigBefore: &before, ConfigAfter: &after},
+		},
+		ConnectionsBefore: conns,
+	}})
+
+	tss, err := snapstate.RevertSnapSetGeneration(s.state, 4, &snapstate.RemoveFlags{Purge: true}, "")
+	c.Assert(err, IsNil)
+	c.Assert(tss, HasLen, 5)
+
+	// everything shares a single lane
+	lanes := tss[0].Tasks()[0].Lanes()
+	c.Assert(lanes, HasLen, 1)
+	for _, ts := range tss {
+		for _, t := range ts.Tasks() {
+			c.Check(t.Lanes(), testutil.Contains, lanes[0])
+		}
+	}
+
+	for i, name := range []string{"some-other-snap", "some-snap"} {
+		snapsup, err := snapstate.TaskSnapSetup(tss[i].Tasks()[0])
+		c.Assert(err, IsNil)
+		c.Check(snapsup.InstanceName(), Equals, name)
+		c.Check(snapsup.Revert, Equals, true)
+		c.Check(snapsup.Transaction, Equals, client.TransactionAllSnaps)
+		c.Check(snapsup.Revision(), Equals, snap.R(1))
+	}
+
+	// the configuration is restored after the reverts
+	restoreConfig := tss[2].Tasks()[0]
+	c.Check(restoreConfig.Kind(), Equals, "restore-snap-set-config")
+	c.Check(restoreConfig.Summary(), Equals, "Restore configuration of snap set generation 4")
+	var configs map[string]*json.RawMessage
+	c.Assert(restoreConfig.Get("configs", &configs), IsNil)
+	c.Check(configs, HasLen, 2)
+	c.Check(configs["some-other-snap"], IsNil)
+	c.Assert(configs["some-snap"], NotNil)
+	c.Check(string(*configs["some-snap"]), Equals, `{"foo":"bar"}`)
+	// the configuration of some-other-snap changed since
+	var changed []string
+	c.Assert(restoreConfig.Get("changed-snaps", &changed), IsNil)
+	c.Check(changed, DeepEquals, []string{"some-other-snap"})
+	c.Check(restoreConfig.WaitTasks(), HasLen, len(tss[0].Tasks())+len(tss[1].Tasks()))
+
+	// then connections
+	restore := tss[3].Tasks()[0]
+	c.Check(restore.Kind(), Equals, "restore-connections")
+	c.Check(restore.Summary(), Equals, "Restore interface connections of snap set generation 4")
+	var snaps []string
+	c.Assert(restore.Get("snaps", &snaps), IsNil)
+	c.Check(snaps, DeepEquals, []string{"new-snap", "some-other-snap", "some-snap"})
+	var restoreConns []snapstate.SnapSetConnection
+	c.Assert(restore.Get("connections", &restoreConns), IsNil)
+	c.Check(restoreConns, DeepEquals, conns)
+	c.Check(restore.WaitTasks(), DeepEquals, []*state.Task{restoreConfig})
+
+	// and finally the newly installed snap is removed, as requested
+	removeFirst := tss[4].Tasks()[0]
+	c.Check(removeFirst.Kind(), Equals, "stop-snap-services")
+	snapsup, err := snapstate.TaskSnapSetup(removeFirst)
+	c.Assert(err, IsNil)
+	c.Check(snapsup.InstanceName(), Equals, "new-snap")
+	c.Check(removeFirst.WaitTasks(), DeepEquals, []*state.Task{restore})
+	for _, t := range tss[4].Tasks() {
+		c.Check(t.Kind(), Not(Equals), "save-snapshot")
+	}
+}
+
+func (s *snapmgrTestSuite) TestRevertSnapSetGenerationRestoresConfig(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapSetSnaps(c, snap.R(1), snap.R(2))
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("some-snap", "foo", "baz"), IsNil)
+	tr.Commit()
+
+	before := json.RawMessage(`{"foo":"bar"}`)
+	after := json.RawMessage(`{"foo":"baz"}`)
+	s.state.Set("snap-set-generations", []*snapstate.SnapSetGeneration{{
+		ID: 1,
+		Snaps: []*snapstate.SnapSetGenerationSnap{
+			{InstanceName: "some-other-snap", Before: snap.R(1), After: snap.R(2)},
+			{InstanceName: "some-snap", Before: snap.R(1), After: snap.R(2), ConfigBefore: &before, ConfigAfter: &after},
+		},
+	}})
+
+	tss, err := snapstate.RevertSnapSetGeneration(s.state, 1, nil, "")
+	c.Assert(err, IsNil)
+	chg := s.state.NewChange("revert-snap-set-generation", "...")
+	for _, ts := range tss {
+		chg.AddAll(ts)
+	}
+
+	s.settle(c)
+	c.Assert(chg.Err(), IsNil)
+
+	for _, name := range []string{"some-snap", "some-other-snap"} {
+		var snapst snapstate.SnapState
+		c.Assert(snapstate.Get(s.state, name, &snapst), IsNil)
+		c.Check(snapst.Current, Equals, snap.R(1))
+	}
+	cfg, err := config.GetSnapConfig(s.state, "some-snap")
+	c.Assert(err, IsNil)
+	c.Assert(cfg, NotNil)
+	c.Check(string(*cfg), Equals, `{"foo":"bar"}`)
+}
+
+func (s *snapmgrTestSuite) TestRestoreSnapSetConfig(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("some-snap", "foo", "baz"), IsNil)
+	c.Assert(tr.Set("some-other-snap", "foo", "qux"), IsNil)
+	tr.Commit()
+
+	before := json.RawMessage(`{"foo":"bar"}`)
+	chg := s.state.NewChange("revert-snap-set-generation", "...")
+	t := s.state.NewTask("restore-snap-set-config", "...")
+	t.Set("generation", 4)
+	t.Set("configs", map[string]*json.RawMessage{
+		"some-snap":       &before,
+		"some-other-snap": nil,
+	})
+	t.Set("changed-snaps", []string{"some-snap"})
+	chg.AddTask(t)
+	terr := s.state.NewTask("error-trigger", "provoking undo")
+	terr.WaitFor(t)
+	chg.AddTask(terr)
+
+	s.settle(c)
+
+	c.Assert(chg.Err(), NotNil)
+	c.Check(t.Status(), Equals, state.UndoneStatus)
+	c.Check(strings.Join(t.Log(), ""), Matches, `.*Configuration of snap "some-snap" was modified after snap set generation 4, the modifications are discarded`)
+
+	// the configuration was restored, and put back on undo
+	var old map[string]*json.RawMessage
+	c.Assert(t.Get("old-configs", &old), IsNil)
+	c.Check(string(*old["some-snap"]), Equals, `{"foo":"baz"}`)
+	c.Check(string(*old["some-other-snap"]), Equals, `{"foo":"qux"}`)
+
+	cfg, err := config.GetSnapConfig(s.state, "some-snap")
+	c.Assert(err, IsNil)
+	c.Check(string(*cfg), Equals, `{"foo":"baz"}`)
+	cfg, err = config.GetSnapConfig(s.state, "some-other-snap")
+	c.Assert(err, IsNil)
+	c.Check(string(*cfg), Equals, `{"foo":"qux"}`)
+}
+
+func (s *snapmgrTestSuite) TestRevertSnapSetGenerationErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapSetSnaps(c, snap.R(1), snap.R(2))
+	s.state.Set("snap-set-generations", []*snapstate.SnapSetGeneration{{
+		ID: 1,
+		Snaps: []*snapstate.SnapSetGenerationSnap{
+			{InstanceName: "some-snap", Before: snap.R(1), After: snap.R(3)},
+		},
+	}, {
+		ID: 2,
+		Snaps: []*snapstate.SnapSetGenerationSnap{
+			{InstanceName: "gone-snap", Before: snap.R(1), After: snap.R(3)},
+		},
+	}})
+
+	_, err := snapstate.RevertSnapSetGeneration(s.state, 1, nil, "")
+	c.Check(err, ErrorMatches, `cannot revert snap set generation 1: snap "some-snap" is at revision 2 instead of 3`)
+	_, err = snapstate.RevertSnapSetGeneration(s.state, 2, nil, "")
+	c.Check(err, ErrorMatches, `cannot revert snap set generation 2: snap "gone-snap" is no longer installed`)
+	_, err = snapstate.RevertSnapSetGeneration(s.state, 3, nil, "")
+	c.Check(err, ErrorMatches, `cannot find snap set generation 3`)
+}
+
+func (s *snapmgrTestSuite) TestSnapSetGenerationsCapped(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapSetSnaps(c, snap.R(1))
+
+	var gens []*snapstate.SnapSetGeneration
+	for i := 1; i <= 10; i++ {
+		gens = append(gens, &snapstate.SnapSetGeneration{ID: i})
+	}
+	s.state.Set("snap-set-generations", gens)
+
+	chg := s.state.NewChange("refresh", "refresh some snaps")
+	_, tss, err := snapstate.UpdateMany(context.Background(), s.state,
+		[]string{"some-snap", "some-other-snap"}, nil, 0,
+		&snapstate.Flags{Transaction: client.TransactionAllSnaps})
+	c.Assert(err, IsNil)
+	for _, ts := range tss {
+		chg.AddAll(ts)
+	}
+
+	s.settle(c)
+	c.Assert(chg.Err(), IsNil)
+
+	gens, err = snapstate.SnapSetGenerations(s.state)
+	c.Assert(err, IsNil)
+	c.Assert(gens, HasLen, 10)
+	c.Check(gens[0].ID, Equals, 2)
+	c.Check(gens[9].ID, Equals, 11)
+	c.Check(gens[9].ChangeID, Equals, chg.ID())
+}
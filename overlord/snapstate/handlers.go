@@ -1898,6 +1898,13 @@ func (m *SnapManager) doLinkSnap(t *state.Task, _ *tomb.Tomb) (retErr error) {
 	if snapsup.Required { // set only on install and left alone on refresh
 		snapst.Required = true
 	}
+	oldPrerequisite := snapst.Prerequisite
+	if snapsup.Prerequisite && oldCurrent.Unset() { // set only on first install
+		snapst.Prerequisite = true
+	}
+	if snapsup.Explicit {
+		snapst.Prerequisite = false
+	}
 	oldRefreshInhibitedTime := snapst.RefreshInhibitedTime
 	oldLastRefreshTime := snapst.LastRefreshTime
 	// only set userID if unset or logged out in snapst and if we
@@ -2084,6 +2091,7 @@ func (m *SnapManager) doLinkSnap(t *state.Task, _ *tomb.Tomb) (retErr error) {
 	t.Set("old-candidate-index", oldCandidateIndex)
 	t.Set("old-refresh-inhibited-time", oldRefreshInhibitedTime)
 	t.Set("old-cohort-key", oldCohortKey)
+	t.Set("old-prerequisite", oldPrerequisite)
 	t.Set("old-last-refresh-time", oldLastRefreshTime)
 	t.Set("old-revs-before-cand", oldRevsBeforeCand)
 	if snapsup.Revert {
@@ -2520,6 +2528,10 @@ func (m *SnapManager) undoLinkSnap(t *state.Task, _ *tomb.Tomb) error {
 	if err := t.Get("old-cohort-key", &oldCohortKey); err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
 	}
+	var oldPrerequisite *bool
+	if err := t.Get("old-prerequisite", &oldPrerequisite); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
 	var oldRevsBeforeCand []snap.Revision
 	if err := t.Get("old-revs-before-cand", &oldRevsBeforeCand); err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
@@ -2589,6 +2601,9 @@ func (m *SnapManager) undoLinkSnap(t *state.Task, _ *tomb.Tomb) error {
 	snapst.RefreshInhibitedTime = oldRefreshInhibitedTime
 	snapst.LastRefreshTime = oldLastRefreshTime
 	snapst.CohortKey = oldCohortKey
+	if oldPrerequisite != nil {
+		snapst.Prerequisite = *oldPrerequisite
+	}
 
 	if isRevert {
 		var oldRevertStatus map[int]RevertStatus
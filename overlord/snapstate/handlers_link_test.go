@@ -205,6 +205,139 @@ func (s *linkSnapSuite) TestDoLinkSnapSuccessWithCohort(c *C) {
 	c.Check(backend.AuxStoreInfoFilename("foo-id"), testutil.FilePresent)
 }
 
+func (s *linkSnapSuite) TestDoLinkSnapSetsPrerequisite(c *C) {
+	s.state.Lock()
+	t := s.state.NewTask("link-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(33),
+		},
+		Flags: snapstate.Flags{Prerequisite: true},
+	})
+	s.state.NewChange("sample", "...").AddTask(t)
+
+	s.state.Unlock()
+	s.se.Ensure()
+	s.se.Wait()
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	var snapst snapstate.SnapState
+	err := snapstate.Get(s.state, "foo", &snapst)
+	c.Assert(err, IsNil)
+	c.Check(snapst.Prerequisite, Equals, true)
+}
+
+func (s *linkSnapSuite) TestDoLinkSnapPrerequisiteOnlyOnFirstInstall(c *C) {
+	s.state.Lock()
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "foo", Revision: snap.R(1)},
+		}),
+		Current: snap.R(1),
+	})
+
+	t := s.state.NewTask("link-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(33),
+		},
+		Flags: snapstate.Flags{Prerequisite: true},
+	})
+	s.state.NewChange("sample", "...").AddTask(t)
+
+	s.state.Unlock()
+	s.se.Ensure()
+	s.se.Wait()
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// a snap that was installed explicitly stays explicit when it gets
+	// refreshed as a prerequisite of another snap
+	var snapst snapstate.SnapState
+	err := snapstate.Get(s.state, "foo", &snapst)
+	c.Assert(err, IsNil)
+	c.Check(snapst.Current, Equals, snap.R(33))
+	c.Check(snapst.Prerequisite, Equals, false)
+}
+
+func (s *linkSnapSuite) mockPrerequisiteFoo(c *C) {
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "foo", Revision: snap.R(1)},
+		}),
+		Current: snap.R(1),
+		Active:  true,
+		Flags:   snapstate.Flags{Prerequisite: true},
+	})
+}
+
+func (s *linkSnapSuite) TestDoLinkSnapExplicitClearsPrerequisite(c *C) {
+	s.state.Lock()
+	s.mockPrerequisiteFoo(c)
+
+	t := s.state.NewTask("link-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(33),
+		},
+		Flags: snapstate.Flags{Explicit: true},
+	})
+	s.state.NewChange("sample", "...").AddTask(t)
+
+	s.state.Unlock()
+	s.se.Ensure()
+	s.se.Wait()
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// the snap was asked for by name and is wanted from now on
+	var snapst snapstate.SnapState
+	err := snapstate.Get(s.state, "foo", &snapst)
+	c.Assert(err, IsNil)
+	c.Check(t.Status(), Equals, state.DoneStatus)
+	c.Check(snapst.Current, Equals, snap.R(33))
+	c.Check(snapst.Prerequisite, Equals, false)
+}
+
+func (s *linkSnapSuite) TestDoUndoLinkSnapExplicitKeepsPrerequisite(c *C) {
+	s.state.Lock()
+	s.mockPrerequisiteFoo(c)
+
+	t := s.state.NewTask("link-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(33),
+		},
+		Flags: snapstate.Flags{Explicit: true},
+	})
+	chg := s.state.NewChange("sample", "...")
+	chg.AddTask(t)
+	terr := s.state.NewTask("error-trigger", "provoking total undo")
+	terr.WaitFor(t)
+	chg.AddTask(terr)
+
+	s.state.Unlock()
+	for i := 0; i < 6; i++ {
+		s.se.Ensure()
+		s.se.Wait()
+	}
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// the change failed, the snap is still only a prerequisite
+	var snapst snapstate.SnapState
+	err := snapstate.Get(s.state, "foo", &snapst)
+	c.Assert(err, IsNil)
+	c.Check(t.Status(), Equals, state.UndoneStatus)
+	c.Check(snapst.Current, Equals, snap.R(1))
+	c.Check(snapst.Prerequisite, Equals, true)
+}
+
 func (s *linkSnapSuite) TestDoLinkSnapSuccessNoUserID(c *C) {
 	s.state.Lock()
 	t := s.state.NewTask("link-snap", "test")
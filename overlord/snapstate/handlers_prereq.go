@@ -53,6 +53,12 @@ func (m *SnapManager) doPrerequisites(t *state.Task, _ *tomb.Tomb) error {
 		return err
 	}
 
+	// the first prerequisites task of an all-snaps transaction runs before
+	// any of its snaps is touched, remember how things were
+	if err := captureSnapSetBefore(t, snapsup); err != nil {
+		return err
+	}
+
 	// snapd/os/base/kernel/gadget cannot have prerequisites other than the
 	// models default base (or core) which is installed anyway
 	switch snapsup.Type {
@@ -158,6 +164,7 @@ func installPrereqs(t *state.Task, snapsup *SnapSetup, dctx DeviceContext, tm ti
 				Transaction:     transaction,
 				Lane:            lane,
 				RequireTypeBase: prereqName == base,
+				Prerequisite:    true,
 
 				// TODO: as a temporary workaround for a bug that occurs when a
 				// snap updates a prereq, we disable rerefreshes.
@@ -194,6 +194,8 @@ func (s *prereqSuite) TestDoPrereqWithBaseNone(c *C) {
 			c.Assert(err, IsNil)
 			// prerequisites are installed with sanitized flags
 			c.Check(snapsup.DevMode, Equals, false)
+			// and are marked as such
+			c.Check(snapsup.Prerequisite, Equals, true)
 			linkedSnaps = append(linkedSnaps, snapsup.InstanceName())
 		} else if t.Kind() == "prerequisites" {
 			c.Assert(t.Lanes(), HasLen, 1)
@@ -864,6 +864,9 @@ func Manager(st *state.State, runner *state.TaskRunner) (*SnapManager, error) {
 	runner.AddHandler("prepare-kernel-snap", m.doPrepareKernelSnap, m.undoPrepareKernelSnap)
 	runner.AddHandler("discard-old-kernel-snap-setup", m.doDiscardOldKernelSnapSetup, m.undoDiscardOldKernelSnapSetup)
 
+	// reverting snap set generations
+	runner.AddHandler("restore-snap-set-config", m.doRestoreSnapSetConfig, m.undoRestoreSnapSetConfig)
+
 	// FIXME: drop the task entirely after a while
 	// (having this wart here avoids yet-another-patch)
 	runner.AddHandler("cleanup", func(*state.Task, *tomb.Tomb) error { return nil }, nil)
@@ -918,6 +921,7 @@ func Manager(st *state.State, runner *state.TaskRunner) (*SnapManager, error) {
 	runner.AddBlocked(resealingTaskBlocked)
 
 	RegisterAffectedSnapsByKind("conditional-auto-refresh", conditionalAutoRefreshAffectedSnaps)
+	RegisterAffectedSnapsByKind("restore-snap-set-config", restoreSnapSetConfigAffectedSnaps)
 
 	return m, nil
 }
@@ -937,6 +941,8 @@ func (m *SnapManager) StartUp() error {
 		processInhibitedAutoRefresh(chg, old, new)
 		// This handler implements marks failed snaps auto-refresh attempts for backoff.
 		processFailedAutoRefresh(chg, old, new)
+		// This handler records snap set generations of successful all-snaps transactions.
+		processSnapSetGeneration(chg, old, new)
 	})
 
 	if CheckExpectedRestart(m.state) == ErrUnexpectedRuntimeRestart {
@@ -1097,6 +1103,27 @@ func (m *SnapManager) RefreshSchedule() (string, bool, error) {
 	return m.autoRefresh.RefreshSchedule()
 }
 
+// RefreshSnapTimers returns the per-snap refresh schedules, keyed by snap
+// instance name, as strings suitable for display to a user.
+// The caller should be holding the state lock.
+func (m *SnapManager) RefreshSnapTimers() (map[string]string, error) {
+	return m.autoRefresh.RefreshSnapTimers()
+}
+
+// RefreshStaging returns the staging state of auto-refreshes for all snaps
+// with a refresh staging delay, keyed by snap instance name.
+// The caller should be holding the state lock.
+func (m *SnapManager) RefreshStaging() (map[string]*RefreshStaging, error) {
+	return m.autoRefresh.RefreshStaging()
+}
+
+// RefreshBlackout returns the date ranges during which auto-refreshes are
+// blocked as a string suitable for display to a user.
+// The caller should be holding the state lock.
+func (m *SnapManager) RefreshBlackout() (string, error) {
+	return m.autoRefresh.RefreshBlackout()
+}
+
 // EnsureAutoRefreshesAreDelayed will delay refreshes for the specified amount
 // of time, as well as return any active auto-refresh changes that are currently
 // not ready so that the client can wait for those.
@@ -2244,6 +2244,12 @@ type AutoRefreshOptions struct {
 // snaps on the system. In addition to that it will also refresh important
 // assertions.
 func AutoRefresh(ctx context.Context, st *state.State) ([]string, *UpdateTaskSets, error) {
+	return autoRefreshFiltered(ctx, st, nil)
+}
+
+// autoRefreshFiltered is like AutoRefresh but only considers snaps that pass
+// the optional filter.
+func autoRefreshFiltered(ctx context.Context, st *state.State, filter updateFilter) ([]string, *UpdateTaskSets, error) {
 	userID := 0
 
 	if AutoRefreshAssertions != nil {
@@ -2261,14 +2267,14 @@ func AutoRefresh(ctx context.Context, st *state.State) ([]string, *UpdateTaskSet
 	}
 	if !gateAutoRefreshHook {
 		// old-style refresh (gate-auto-refresh-hook feature disabled)
-		return updateManyFiltered(ctx, st, nil, nil, userID, nil, &Flags{IsAutoRefresh: true}, "")
+		return updateManyFiltered(ctx, st, nil, nil, userID, filter, &Flags{IsAutoRefresh: true}, "")
 	}
 
 	// TODO: rename to autoRefreshTasks when old auto refresh logic gets removed.
 	// TODO2: pass "IsContinuedAutoRefresh" so that the SnapSetup of
 	//        gate-auto-refresh contains this field (required so that
 	//        the update-finished notifications work)
-	updated, tss, err := autoRefreshPhase1(ctx, st, "")
+	updated, tss, err := autoRefreshPhase1(ctx, st, "", filter)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -2279,8 +2285,9 @@ func AutoRefresh(ctx context.Context, st *state.State) ([]string, *UpdateTaskSet
 // autoRefreshPhase1 creates gate-auto-refresh hooks and conditional-auto-refresh
 // task that initiates actual refresh. forGatingSnap is optional and limits auto-refresh
 // to the snaps affecting the given snap only; it defaults to all snaps if nil.
+// The optional filter further restricts the snaps considered for refresh.
 // The state needs to be locked by the caller.
-func autoRefreshPhase1(ctx context.Context, st *state.State, forGatingSnap string) ([]string, []*state.TaskSet, error) {
+func autoRefreshPhase1(ctx context.Context, st *state.State, forGatingSnap string, filter updateFilter) ([]string, []*state.TaskSet, error) {
 	user, err := userFromUserID(st, 0)
 	if err != nil {
 		return nil, nil, err
@@ -2299,6 +2306,11 @@ func autoRefreshPhase1(ctx context.Context, st *state.State, forGatingSnap strin
 		// of errors?
 		return nil, nil, err
 	}
+	if filter != nil {
+		plan.filter(func(t target) (bool, error) {
+			return filter(t.info, &t.snapst), nil
+		})
+	}
 	deviceCtx, err := DeviceCtxFromState(st, nil)
 	if err != nil {
 		return nil, nil, err
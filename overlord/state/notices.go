@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -307,11 +308,16 @@ const (
 	// expired. The key for interfaces-requests-rule-update notices is the
 	// rule ID.
 	InterfacesRequestsRuleUpdateNotice NoticeType = "interfaces-requests-rule-update"
+
+	// Recorded by snaps through "snapctl notice". The key for snap-custom
+	// notices is the instance name of the recording snap followed by a slash
+	// and the snap-chosen key, e.g. "my-snap/model-downloaded".
+	SnapCustomNotice NoticeType = "snap-custom"
 )
 
 func (t NoticeType) Valid() bool {
 	switch t {
-	case ChangeUpdateNotice, WarningNotice, RefreshInhibitNotice, SnapRunInhibitNotice, InterfacesRequestsPromptNotice, InterfacesRequestsRuleUpdateNotice:
+	case ChangeUpdateNotice, WarningNotice, RefreshInhibitNotice, SnapRunInhibitNotice, InterfacesRequestsPromptNotice, InterfacesRequestsRuleUpdateNotice, SnapCustomNotice:
 		return true
 	}
 	return false
@@ -432,6 +438,12 @@ func ValidateNotice(noticeType NoticeType, key string, options *AddNoticeOptions
 	if noticeType == RefreshInhibitNotice && key != "-" {
 		return fmt.Errorf(`cannot add %s notice with invalid key %q: only "-" key is supported`, noticeType, key)
 	}
+	if noticeType == SnapCustomNotice {
+		snapName, snapKey, ok := strings.Cut(key, "/")
+		if !ok || snapName == "" || snapKey == "" {
+			return fmt.Errorf(`cannot add %s notice with invalid key %q: key must be of the form "<snap>/<key>"`, noticeType, key)
+		}
+	}
 	return nil
 }
 
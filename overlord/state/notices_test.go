@@ -980,6 +980,16 @@ func (s *noticesSuite) TestValidateNotice(c *C) {
 	id, err = st.AddNotice(nil, state.RefreshInhibitNotice, "123", nil)
 	c.Check(err, ErrorMatches, `internal error: cannot add refresh-inhibit notice with invalid key "123": only "-" key is supported`)
 	c.Check(id, Equals, "")
+
+	// Snap custom notices must be namespaced by snap
+	for _, key := range []string{"foo", "foo/", "/foo"} {
+		id, err = st.AddNotice(nil, state.SnapCustomNotice, key, nil)
+		c.Check(err, ErrorMatches, fmt.Sprintf(`internal error: cannot add snap-custom notice with invalid key %q: key must be of the form "<snap>/<key>"`, key))
+		c.Check(id, Equals, "")
+	}
+	id, err = st.AddNotice(nil, state.SnapCustomNotice, "foo/model-downloaded", nil)
+	c.Check(err, IsNil)
+	c.Check(id, Not(Equals), "")
 }
 
 func (s *noticesSuite) TestNextNoticeTimestamp(c *C) {
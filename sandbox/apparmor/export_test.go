@@ -41,6 +41,10 @@ func MockRuntimeNumCPU(new func() int) (restore func()) {
 	}
 }
 
+func MockTotalUsableMemory(f func() (uint64, error)) (restore func()) {
+	return testutil.Mock(&osutilTotalUsableMemory, f)
+}
+
 func MockMkdirAll(f func(string, os.FileMode) error) func() {
 	r := testutil.Backup(&osMkdirAll)
 	osMkdirAll = f
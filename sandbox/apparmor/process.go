@@ -23,10 +23,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/snap/naming"
 )
 
 func labelFromPid(pid int) (string, error) {
@@ -53,8 +55,28 @@ func labelFromPid(pid int) (string, error) {
 	return label, nil
 }
 
+// UserProfileName returns the name of the variant of the given profile for
+// the user with the given ID. The variant adds the policy of the connections
+// limited to the user, snap-confine uses the variant of the user running the
+// application if there is one.
+func UserProfileName(profile string, uid int) string {
+	return fmt.Sprintf("%s.user-%d", profile, uid)
+}
+
+var userProfileSuffix = regexp.MustCompile(`\.user-[0-9]+$`)
+
+// SecurityTagFromLabel returns the security tag of the snap application or
+// hook with the given label, which is either the security tag itself or the
+// name of the variant of its profile for a user.
+func SecurityTagFromLabel(label string) string {
+	if _, err := naming.ParseSecurityTag(label); err == nil {
+		return label
+	}
+	return userProfileSuffix.ReplaceAllString(label, "")
+}
+
 func DecodeLabel(label string) (snap, app, hook string, err error) {
-	parts := strings.Split(label, ".")
+	parts := strings.Split(SecurityTagFromLabel(label), ".")
 	if parts[0] != "snap" {
 		return "", "", "", fmt.Errorf("security label %q does not belong to a snap", label)
 	}
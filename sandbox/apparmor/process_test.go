@@ -51,6 +51,39 @@ func (s *apparmorSuite) TestDecodeLabel(c *C) {
 	c.Assert(err, ErrorMatches, `security label "/usr/bin/ntpd" does not belong to a snap`)
 }
 
+func (s *apparmorSuite) TestDecodeLabelUserProfile(c *C) {
+	label := apparmor.UserProfileName(snap.AppSecurityTag("snap_name", "my-app"), 1000)
+	c.Check(label, Equals, "snap.snap_name.my-app.user-1000")
+	snapName, appName, hookName, err := apparmor.DecodeLabel(label)
+	c.Assert(err, IsNil)
+	c.Check(snapName, Equals, "snap_name")
+	c.Check(appName, Equals, "my-app")
+	c.Check(hookName, Equals, "")
+
+	label = apparmor.UserProfileName(snap.HookSecurityTag("snap_name", "my-hook"), 1000)
+	snapName, appName, hookName, err = apparmor.DecodeLabel(label)
+	c.Assert(err, IsNil)
+	c.Check(snapName, Equals, "snap_name")
+	c.Check(appName, Equals, "")
+	c.Check(hookName, Equals, "my-hook")
+}
+
+func (s *apparmorSuite) TestSecurityTagFromLabel(c *C) {
+	for _, t := range []struct {
+		label, tag string
+	}{
+		{"snap.foo.app", "snap.foo.app"},
+		{"snap.foo.app.user-1000", "snap.foo.app"},
+		{"snap.foo.hook.configure.user-0", "snap.foo.hook.configure"},
+		// an application named like the suffix of a variant
+		{"snap.foo.user-1", "snap.foo.user-1"},
+		{"snap.foo.user-1.user-1000", "snap.foo.user-1"},
+		{"unconfined", "unconfined"},
+	} {
+		c.Check(apparmor.SecurityTagFromLabel(t.label), Equals, t.tag, Commentf("%s", t.label))
+	}
+}
+
 func (s *apparmorSuite) TestDecodeLabelUnrecognisedSnapLabel(c *C) {
 	_, _, _, err := apparmor.DecodeLabel("snap.weird")
 	c.Assert(err, ErrorMatches, `unknown snap related security label "snap.weird"`)
@@ -55,7 +55,8 @@ const (
 )
 
 var (
-	runtimeNumCPU = runtime.NumCPU
+	runtimeNumCPU           = runtime.NumCPU
+	osutilTotalUsableMemory = osutil.TotalUsableMemory
 
 	osutilIsHomeUsingRemoteFS   = osutil.IsHomeUsingRemoteFS
 	osutilIsRootWritableOverlay = osutil.IsRootWritableOverlay
@@ -105,10 +106,25 @@ func numberOfJobsParam() string {
 		// -jauto) and 3.x (compile everything in the main process).
 		cpus = 1
 	}
+	// Compiling complex profiles takes a lot of memory, do not use more
+	// than a quarter of the memory of the system.
+	if mem, err := osutilTotalUsableMemory(); err == nil {
+		byMem := int(mem / 4 / parserJobMemory)
+		if byMem < 1 {
+			byMem = 1
+		}
+		if byMem < cpus {
+			cpus = byMem
+		}
+	}
 
 	return fmt.Sprintf("-j%d", cpus)
 }
 
+// parserJobMemory is the amount of memory a single apparmor_parser job is
+// expected to use at most for compiling a profile.
+const parserJobMemory = 256 * 1024 * 1024
+
 // LoadProfiles loads apparmor profiles from the given files.
 //
 // If no such profiles were previously loaded then they are simply added to the kernel.
@@ -152,6 +168,26 @@ var LoadProfiles = func(fnames []string, cacheDir string, flags AaParserFlags) e
 	return nil
 }
 
+// CacheDirForFeatures returns the directory of the cache forest in cacheDir
+// where apparmor_parser writes the compiled profiles for the features of the
+// running kernel. It requires apparmor 2.13 or newer.
+var CacheDirForFeatures = func(cacheDir string) (string, error) {
+	cmd, _, err := AppArmorParser()
+	if err != nil {
+		return "", err
+	}
+	cmd.Args = append(cmd.Args, "--print-cache-dir", fmt.Sprintf("--cache-loc=%s", cacheDir))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot obtain apparmor cache directory: %v", osutil.OutputErr(output, err))
+	}
+	dir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("cannot obtain apparmor cache directory: unexpected output %q", dir)
+	}
+	return dir, nil
+}
+
 // Remove any of the AppArmor profiles in names from the AppArmor cache in
 // cacheDir
 func RemoveCachedProfiles(names []string, cacheDir string) error {
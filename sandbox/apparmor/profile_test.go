@@ -333,6 +333,10 @@ func (s *appArmorSuite) TestMaybeSetNumberOfJobs(c *C) {
 		return cpus
 	})
 	defer restore()
+	restore = apparmor.MockTotalUsableMemory(func() (uint64, error) {
+		return 64 * 1024 * 1024 * 1024, nil
+	})
+	defer restore()
 
 	cpus = 10
 	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j8")
@@ -344,6 +348,29 @@ func (s *appArmorSuite) TestMaybeSetNumberOfJobs(c *C) {
 	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j1")
 }
 
+func (s *appArmorSuite) TestNumberOfJobsBoundByMemory(c *C) {
+	restore := apparmor.MockRuntimeNumCPU(func() int { return 10 })
+	defer restore()
+	var mem uint64
+	var memErr error
+	restore = apparmor.MockTotalUsableMemory(func() (uint64, error) { return mem, memErr })
+	defer restore()
+
+	// 1GiB allows for a single job
+	mem = 1024 * 1024 * 1024
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j1")
+	mem = 512 * 1024 * 1024
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j1")
+	mem = 4 * 1024 * 1024 * 1024
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j4")
+	mem = 64 * 1024 * 1024 * 1024
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j8")
+
+	memErr = fmt.Errorf("boom")
+	mem = 0
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j8")
+}
+
 func (s *appArmorSuite) TestSnapConfineDistroProfilePath(c *C) {
 	baseDir := c.MkDir()
 	restore := testutil.Backup(&apparmor.ConfDir)
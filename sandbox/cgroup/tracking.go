@@ -496,6 +496,7 @@ var randomUUID = randutil.RandomKernelUUID
 type SnapDeviceCgroupOptions struct {
 	NonStrict   bool // NonStrict being true implies the application is installed with --devmode
 	SelfManaged bool // SelfManaged ensures that no device management eBPF program is loaded for this snap.
+	Audit       bool // Audit makes the device management eBPF program count denied device accesses.
 }
 
 // SnapDeviceFile returns the path of the per-snap configuration file that governs device access.
@@ -532,6 +533,11 @@ func (opts *SnapDeviceCgroupOptions) MarshalText() ([]byte, error) {
 		buf.WriteString("non-strict=true\n")
 	}
 
+	if opts.Audit {
+		buf.WriteString("# denied device accesses are audited.\n")
+		buf.WriteString("audit=true\n")
+	}
+
 	return buf.Bytes(), nil
 }
 
@@ -556,6 +562,8 @@ func (opts *SnapDeviceCgroupOptions) UnmarshalText(b []byte) error {
 			bp = &opts.SelfManaged
 		case bytes.Equal(left, []byte("non-strict")):
 			bp = &opts.NonStrict
+		case bytes.Equal(left, []byte("audit")):
+			bp = &opts.Audit
 		default:
 			continue
 		}
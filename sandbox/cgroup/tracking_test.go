@@ -1101,6 +1101,13 @@ func (SnapDeviceCgroupOptionsSuite) TestMarshalText(c *C) {
 		"self-managed=true\n"+
 		"# snap uses non-strict confinement.\n"+
 		"non-strict=true\n")
+
+	opts = cgroup.SnapDeviceCgroupOptions{Audit: true}
+	text, err = opts.MarshalText()
+	c.Assert(err, IsNil)
+	c.Assert(string(text), Equals, "# This file is automatically generated.\n"+
+		"# denied device accesses are audited.\n"+
+		"audit=true\n")
 }
 
 func (SnapDeviceCgroupOptionsSuite) TestUnmarshalText(c *C) {
@@ -1135,6 +1142,12 @@ func (SnapDeviceCgroupOptionsSuite) TestUnmarshalText(c *C) {
 	c.Assert(opts.NonStrict, Equals, true)
 	c.Assert(opts.SelfManaged, Equals, true)
 
+	// We can parse the "audit" option.
+	opts = cgroup.SnapDeviceCgroupOptions{}
+	err = opts.UnmarshalText([]byte("audit=true\n"))
+	c.Assert(err, IsNil)
+	c.Check(opts, Equals, cgroup.SnapDeviceCgroupOptions{Audit: true})
+
 	// Comments are ignored.
 	opts = cgroup.SnapDeviceCgroupOptions{}
 	err = opts.UnmarshalText([]byte("# comments are ignored\nnon-strict=true\nself-managed=true\n"))
@@ -0,0 +1,117 @@
+// -*- Mode: Go; indent-tabs-mode: t; tab-width: 4 -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ebpf
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cilium/ebpf"
+
+	"github.com/snapcore/snapd/arch"
+	"github.com/snapcore/snapd/dirs"
+)
+
+// When device access auditing is enabled for a snap, the device cgroup
+// program set up by snap-confine counts the accesses it denies in an audit
+// map, keyed by the denied device. The audit maps are pinned in a directory
+// of their own, and persist until auditing is disabled.
+
+// DeviceAuditValueSize is the size of a value of the audit map, which is
+// the number of denied accesses to the device.
+const DeviceAuditValueSize = 8
+
+func deviceAuditDir() string {
+	return filepath.Join(dirs.SnapBPFFSDir, "audit")
+}
+
+// SecurityTagToBPFAuditPath converts a snap security tag to the
+// corresponding BPF audit map pin path.
+func SecurityTagToBPFAuditPath(securityTag string) string {
+	return filepath.Join(deviceAuditDir(), strings.ReplaceAll(securityTag, ".", "_"))
+}
+
+// DeviceAuditMap wraps the underlying eBPF map counting denied device
+// accesses.
+type DeviceAuditMap struct {
+	m *ebpf.Map
+}
+
+// LoadDeviceAuditMap opens the pinned BPF audit map for the given security
+// tag. The caller is responsible for closing the returned map.
+func LoadDeviceAuditMap(securityTag string) (*DeviceAuditMap, error) {
+	path := SecurityTagToBPFAuditPath(securityTag)
+	m, err := ebpf.LoadPinnedMap(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load device audit map at %s: %v", path, err)
+	}
+	return &DeviceAuditMap{m: m}, nil
+}
+
+// Close the map.
+func (d *DeviceAuditMap) Close() error {
+	return d.m.Close()
+}
+
+// Iterate over all entries in the BPF audit map and calls fn for each denied
+// device with the number of denied accesses. The iteration stops if fn
+// returns an error.
+func (d *DeviceAuditMap) Iterate(fn func(key DeviceKey, count uint64) error) error {
+	iter := d.m.Iterate()
+	keyBuf := make([]byte, DeviceKeySize)
+	valBuf := make([]byte, DeviceAuditValueSize)
+	for iter.Next(&keyBuf, &valBuf) {
+		var key DeviceKey
+		if err := key.UnmarshalBinary(keyBuf); err != nil {
+			return err
+		}
+		// TODO:GOVERSION:use binary.NativeEndian
+		if err := fn(key, arch.Endian().Uint64(valBuf)); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// FindDeviceAuditMapsForSnap returns the security tags of the applications
+// and hooks of the given snap which have an audit map pinned in bpffs.
+func FindDeviceAuditMapsForSnap(instanceName string) (tags []string, err error) {
+	return findPinnedMapsForSnap(deviceAuditDir(), instanceName)
+}
+
+// RemoveDeviceAuditMaps removes the audit maps of the given snap, dropping
+// the counted denials. Programs which are still running keep counting in
+// their map until the applications are restarted.
+func RemoveDeviceAuditMaps(instanceName string) error {
+	tags, err := FindDeviceAuditMapsForSnap(instanceName)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := os.Remove(SecurityTagToBPFAuditPath(tag)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("cannot remove device audit map: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// -*- Mode: Go; indent-tabs-mode: t; tab-width: 4 -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ebpf_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/sandbox/ebpf"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func (s *ebpfSuite) TestSecurityTagToBPFAuditPath(c *C) {
+	c.Check(ebpf.SecurityTagToBPFAuditPath("snap.foo.bar"), Equals,
+		filepath.Join(dirs.SnapBPFFSDir, "audit/snap_foo_bar"))
+}
+
+func (s *ebpfSuite) TestFindAndRemoveDeviceAuditMaps(c *C) {
+	auditDir := filepath.Join(dirs.SnapBPFFSDir, "audit")
+	c.Assert(os.MkdirAll(auditDir, 0755), IsNil)
+	for _, name := range []string{
+		"snap_mysnap_app1",
+		"snap_mysnap_app3",
+		"snap_other_thing",
+	} {
+		c.Assert(os.WriteFile(filepath.Join(auditDir, name), nil, 0644), IsNil)
+	}
+	// device maps are not audit maps
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapBPFFSDir, "snap_mysnap_app2"), nil, 0644), IsNil)
+
+	tags, err := ebpf.FindDeviceAuditMapsForSnap("mysnap")
+	c.Assert(err, IsNil)
+	c.Check(tags, DeepEquals, []string{"snap.mysnap.app1", "snap.mysnap.app3"})
+
+	// the audit directory is not mistaken for a device map
+	tags, err = ebpf.FindActiveDeviceMapsForSnap("mysnap")
+	c.Assert(err, IsNil)
+	c.Check(tags, DeepEquals, []string{"snap.mysnap.app2"})
+
+	c.Assert(ebpf.RemoveDeviceAuditMaps("mysnap"), IsNil)
+	c.Check(filepath.Join(auditDir, "snap_mysnap_app1"), Not(testutil.FilePresent))
+	c.Check(filepath.Join(auditDir, "snap_other_thing"), testutil.FilePresent)
+	c.Check(filepath.Join(dirs.SnapBPFFSDir, "snap_mysnap_app2"), testutil.FilePresent)
+
+	// nothing to remove
+	c.Assert(ebpf.RemoveDeviceAuditMaps("mysnap"), IsNil)
+}
+
+func (s *ebpfSuite) TestFindDeviceAuditMapsForSnapNoDir(c *C) {
+	tags, err := ebpf.FindDeviceAuditMapsForSnap("anything")
+	c.Assert(err, IsNil)
+	c.Check(tags, IsNil)
+}
@@ -141,19 +141,25 @@ func bpffsPinnedNameToSecurityTag(name string) (tag string, err error) {
 	return name[:first] + "." + name[first+1:last] + "." + name[last+1:], nil
 }
 
+// FindActiveDeviceMapsForSnap returns the security tags of the applications
+// and hooks of the given snap which have a device map pinned in bpffs.
 func FindActiveDeviceMapsForSnap(instanceName string) (tags []string, err error) {
+	return findPinnedMapsForSnap(dirs.SnapBPFFSDir, instanceName)
+}
+
+func findPinnedMapsForSnap(dir, instanceName string) (tags []string, err error) {
 	// Security tags in bpffs have dots replaced with underscores.
 	// Pattern: snap_<name>_<app>
 	// This also matches parallel installs (e.g. snap_foo_inst_bar for
 	// instance "foo_inst") since snap and app names cannot contain
 	// underscores.
 	prefix := fmt.Sprintf("snap_%s_", instanceName)
-	entries, err := os.ReadDir(dirs.SnapBPFFSDir)
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("cannot read %s: %v", dirs.SnapBPFFSDir, err)
+		return nil, fmt.Errorf("cannot read %s: %v", dir, err)
 	}
 
 	for _, e := range entries {
@@ -0,0 +1,312 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package landlock implements support for the Landlock Linux security
+// module, which lets unprivileged processes restrict their own access to
+// the filesystem.
+//
+// Rulesets are stored in a simple line based format, every line holds the
+// access rights followed by the path the rights apply to, e.g.:
+//
+//	rx /usr
+//	rw $SNAP_DATA
+//
+// The path is the rest of the line and may contain spaces. Paths may
+// contain variables and the wildcards of filepath.Match, which are expanded
+// when the ruleset is applied. Rules that only apply to some users start
+// with the list of their user IDs, e.g.:
+//
+//	uid=1000,1001 rwx $HOME
+package landlock
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Access is a set of filesystem access rights.
+type Access uint8
+
+const (
+	// AccessRead allows reading files and listing directories.
+	AccessRead Access = 1 << iota
+	// AccessWrite allows writing, creating, renaming and removing files
+	// and directories.
+	AccessWrite
+	// AccessExecute allows executing files.
+	AccessExecute
+)
+
+var accessChars = []struct {
+	access Access
+	char   byte
+}{
+	{AccessRead, 'r'},
+	{AccessWrite, 'w'},
+	{AccessExecute, 'x'},
+}
+
+func (a Access) String() string {
+	var buf bytes.Buffer
+	for _, ac := range accessChars {
+		if a&ac.access != 0 {
+			buf.WriteByte(ac.char)
+		}
+	}
+	return buf.String()
+}
+
+// ParseAccess parses access rights in the "rwx" notation.
+func ParseAccess(s string) (Access, error) {
+	if s == "" {
+		return 0, fmt.Errorf("access cannot be empty")
+	}
+	var a Access
+	for i := 0; i < len(s); i++ {
+		found := false
+		for _, ac := range accessChars {
+			if s[i] == ac.char {
+				a |= ac.access
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid access %q", s)
+		}
+	}
+	return a, nil
+}
+
+// Rule grants access to a path and everything beneath it.
+type Rule struct {
+	Access Access
+	Path   string
+	// Users holds the IDs of the users the rule applies to, the rule
+	// applies to all the users if it's empty.
+	Users []int
+}
+
+func (r Rule) String() string {
+	if len(r.Users) == 0 {
+		return fmt.Sprintf("%s %s", r.Access, r.Path)
+	}
+	uids := make([]string, len(r.Users))
+	for i, uid := range r.Users {
+		uids[i] = strconv.Itoa(uid)
+	}
+	return fmt.Sprintf("uid=%s %s %s", strings.Join(uids, ","), r.Access, r.Path)
+}
+
+func parseUsers(s string) ([]int, error) {
+	var users []int
+	for _, field := range strings.Split(s, ",") {
+		uid, err := strconv.Atoi(field)
+		if err != nil || uid < 0 {
+			return nil, fmt.Errorf("invalid user ID %q", field)
+		}
+		users = append(users, uid)
+	}
+	return users, nil
+}
+
+// FormatRuleset writes the rules in the ruleset format.
+func FormatRuleset(w io.Writer, rules []Rule) error {
+	for _, r := range rules {
+		if _, err := fmt.Fprintln(w, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseRuleset reads rules in the ruleset format. Empty lines and comments
+// starting with # are ignored.
+func ParseRuleset(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var users []int
+		if strings.HasPrefix(line, "uid=") {
+			fields := strings.SplitN(line, " ", 2)
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("cannot parse line %d: expected access and path", lineno)
+			}
+			var err error
+			users, err = parseUsers(strings.TrimPrefix(fields[0], "uid="))
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse line %d: %v", lineno, err)
+			}
+			line = strings.TrimSpace(fields[1])
+		}
+		// the path is the rest of the line, it may contain spaces
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("cannot parse line %d: expected access and path", lineno)
+		}
+		access, err := ParseAccess(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse line %d: %v", lineno, err)
+		}
+		path := strings.TrimSpace(fields[1])
+		if !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "$") {
+			return nil, fmt.Errorf("cannot parse line %d: path %q is not absolute", lineno, path)
+		}
+		rules = append(rules, Rule{Access: access, Path: path, Users: users})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ReadRuleset reads the ruleset from the given file.
+func ReadRuleset(path string) ([]Rule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rules, err := ParseRuleset(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read landlock ruleset %q: %v", path, err)
+	}
+	return rules, nil
+}
+
+// RulesForUser returns the rules which apply to the user with the given ID.
+func RulesForUser(rules []Rule, uid int) []Rule {
+	var userRules []Rule
+	for _, r := range rules {
+		if len(r.Users) == 0 {
+			userRules = append(userRules, r)
+			continue
+		}
+		for _, u := range r.Users {
+			if u == uid {
+				userRules = append(userRules, Rule{Access: r.Access, Path: r.Path})
+				break
+			}
+		}
+	}
+	return userRules
+}
+
+// ExpandRules expands the variables in the paths of the rules using the
+// given mapping and merges the rules for the same path. Rules with
+// variables that expand to nothing are dropped. Paths with the wildcards of
+// filepath.Match are replaced by the existing paths they match, paths
+// created later are not covered. The rules of other users must have been
+// dropped with RulesForUser beforehand.
+func ExpandRules(rules []Rule, mapping func(string) string) []Rule {
+	// the values of the variables are never wildcards
+	escapedMapping := func(name string) string {
+		return globEscaper.Replace(mapping(name))
+	}
+	byPath := make(map[string]Access, len(rules))
+	for _, r := range rules {
+		pattern := os.Expand(r.Path, escapedMapping)
+		if !strings.HasPrefix(pattern, "/") {
+			continue
+		}
+		if !hasGlobMeta(pattern) {
+			byPath[filepath.Clean(globUnescaper.Replace(pattern))] |= r.Access
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, path := range matches {
+			byPath[filepath.Clean(path)] |= r.Access
+		}
+	}
+	expanded := make([]Rule, 0, len(byPath))
+	for path, access := range byPath {
+		expanded = append(expanded, Rule{Access: access, Path: path})
+	}
+	sort.Slice(expanded, func(i, j int) bool { return expanded[i].Path < expanded[j].Path })
+	return expanded
+}
+
+var (
+	globEscaper   = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`)
+	globUnescaper = strings.NewReplacer(`\\`, `\`, `\*`, `*`, `\?`, `?`, `\[`, `[`)
+)
+
+// hasGlobMeta returns whether the pattern has unescaped wildcards.
+func hasGlobMeta(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '*', '?', '[':
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	probeOnce sync.Once
+	probedABI int
+)
+
+// ProbedABI returns the version of the Landlock ABI supported by the
+// kernel, 0 if Landlock is not supported.
+func ProbedABI() int {
+	probeOnce.Do(func() {
+		probedABI = probeABI()
+	})
+	return probedABI
+}
+
+// Summary describes the Landlock support of the system.
+func Summary() string {
+	abi := ProbedABI()
+	if abi == 0 {
+		return "Landlock is not supported"
+	}
+	return fmt.Sprintf("Landlock ABI version %d is supported", abi)
+}
+
+// MockABI makes the system believe the given Landlock ABI version is
+// supported.
+func MockABI(abi int) (restore func()) {
+	probeOnce.Do(func() {})
+	old := probedABI
+	probedABI = abi
+	return func() {
+		probedABI = old
+	}
+}
@@ -0,0 +1,175 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package landlock
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// landlock_create_ruleset(2) and friends are not wrapped by x/sys/unix
+func landlockCreateRuleset(attr *unix.LandlockRulesetAttr, size uintptr, flags uintptr) (int, error) {
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(attr)), size, flags)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(fd), nil
+}
+
+func landlockAddPathBeneathRule(rulesetFd int, attr *unix.LandlockPathBeneathAttr) error {
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd), unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(attr)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func landlockRestrictSelf(rulesetFd int) error {
+	_, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(rulesetFd), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func probeABI() int {
+	abi, err := landlockCreateRuleset(nil, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if err != nil {
+		return 0
+	}
+	return abi
+}
+
+// fileAccess is the set of rights that apply to files rather than
+// directories.
+const fileAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+	unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+	unix.LANDLOCK_ACCESS_FS_READ_FILE |
+	unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+// handledAccess returns the filesystem rights known to the given ABI.
+func handledAccess(abi int) uint64 {
+	handled := uint64(unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM)
+	if abi >= 2 {
+		handled |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		handled |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	return handled
+}
+
+// kernelAccess maps the access rights to the Landlock filesystem rights.
+func kernelAccess(a Access) uint64 {
+	var access uint64
+	if a&AccessRead != 0 {
+		access |= unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+	}
+	if a&AccessWrite != 0 {
+		access |= unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+			unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+			unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+			unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+			unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+			unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+			unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+			unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+			unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+			unix.LANDLOCK_ACCESS_FS_MAKE_SYM |
+			unix.LANDLOCK_ACCESS_FS_REFER |
+			unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	if a&AccessExecute != 0 {
+		access |= unix.LANDLOCK_ACCESS_FS_EXECUTE
+	}
+	return access
+}
+
+// RestrictSelf restricts the filesystem access of the calling thread, and
+// of all the processes it executes, to the given rules. Paths of rules
+// that do not exist are skipped. The rules must already be expanded.
+//
+// The Go runtime applies the restriction only to the calling thread, it
+// must therefore be called right before executing a new program from a
+// thread locked with runtime.LockOSThread.
+func RestrictSelf(rules []Rule) error {
+	abi := ProbedABI()
+	if abi == 0 {
+		return fmt.Errorf("cannot use landlock: not supported by the kernel")
+	}
+	handled := handledAccess(abi)
+	rulesetAttr := unix.LandlockRulesetAttr{Access_fs: handled}
+	// the network rights are not handled, use the size of the first
+	// version of the structure
+	rulesetFd, err := landlockCreateRuleset(&rulesetAttr, unsafe.Sizeof(rulesetAttr.Access_fs), 0)
+	if err != nil {
+		return fmt.Errorf("cannot create landlock ruleset: %v", err)
+	}
+	defer unix.Close(rulesetFd)
+
+	for _, r := range rules {
+		fd, err := unix.Open(r.Path, unix.O_PATH|unix.O_CLOEXEC, 0)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("cannot open %q for landlock rule: %v", r.Path, err)
+		}
+		allowed := kernelAccess(r.Access) & handled
+		var st unix.Stat_t
+		if err := unix.Fstat(fd, &st); err == nil && st.Mode&unix.S_IFMT != unix.S_IFDIR {
+			allowed &= fileAccess
+		}
+		pathBeneath := unix.LandlockPathBeneathAttr{
+			Allowed_access: allowed,
+			Parent_fd:      int32(fd),
+		}
+		err = landlockAddPathBeneathRule(rulesetFd, &pathBeneath)
+		unix.Close(fd)
+		if err != nil {
+			return fmt.Errorf("cannot add landlock rule %q: %v", r, err)
+		}
+	}
+
+	// landlock_restrict_self(2) requires no_new_privs unless the process
+	// has CAP_SYS_ADMIN in its user namespace
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("cannot set no_new_privs: %v", err)
+	}
+	if err := landlockRestrictSelf(rulesetFd); err != nil {
+		return fmt.Errorf("cannot enforce landlock ruleset: %v", err)
+	}
+	return nil
+}
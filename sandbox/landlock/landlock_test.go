@@ -0,0 +1,190 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package landlock_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/sandbox/landlock"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type landlockSuite struct{}
+
+var _ = Suite(&landlockSuite{})
+
+func (s *landlockSuite) TestAccessString(c *C) {
+	c.Check(landlock.Access(0).String(), Equals, "")
+	c.Check(landlock.AccessRead.String(), Equals, "r")
+	c.Check((landlock.AccessRead | landlock.AccessWrite).String(), Equals, "rw")
+	c.Check((landlock.AccessExecute | landlock.AccessRead).String(), Equals, "rx")
+	c.Check((landlock.AccessRead | landlock.AccessWrite | landlock.AccessExecute).String(), Equals, "rwx")
+}
+
+func (s *landlockSuite) TestParseAccess(c *C) {
+	for _, t := range []struct {
+		in     string
+		access landlock.Access
+		err    string
+	}{
+		{"r", landlock.AccessRead, ""},
+		{"rw", landlock.AccessRead | landlock.AccessWrite, ""},
+		{"xr", landlock.AccessRead | landlock.AccessExecute, ""},
+		{"rwx", landlock.AccessRead | landlock.AccessWrite | landlock.AccessExecute, ""},
+		{"", 0, "access cannot be empty"},
+		{"rz", 0, `invalid access "rz"`},
+	} {
+		access, err := landlock.ParseAccess(t.in)
+		if t.err != "" {
+			c.Check(err, ErrorMatches, t.err, Commentf("%q", t.in))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Check(access, Equals, t.access, Commentf("%q", t.in))
+	}
+}
+
+func (s *landlockSuite) TestFormatParseRulesetRoundtrip(c *C) {
+	rules := []landlock.Rule{
+		{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/usr"},
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$SNAP_DATA"},
+		{Access: landlock.AccessRead, Path: "$HOME/My Documents"},
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$HOME", Users: []int{1000, 1001}},
+	}
+	var buf bytes.Buffer
+	c.Assert(landlock.FormatRuleset(&buf, rules), IsNil)
+	c.Check(buf.String(), Equals, "rx /usr\nrw $SNAP_DATA\nr $HOME/My Documents\nuid=1000,1001 rw $HOME\n")
+
+	parsed, err := landlock.ParseRuleset(strings.NewReader("# comment\n\n" + buf.String()))
+	c.Assert(err, IsNil)
+	c.Check(parsed, DeepEquals, rules)
+}
+
+func (s *landlockSuite) TestParseRulesetErrors(c *C) {
+	for _, t := range []struct {
+		in  string
+		err string
+	}{
+		{"rw\n", "cannot parse line 1: expected access and path"},
+		{"r /usr\nrw\n", "cannot parse line 2: expected access and path"},
+		{"q /usr\n", `cannot parse line 1: invalid access "q"`},
+		{"r usr\n", `cannot parse line 1: path "usr" is not absolute`},
+		{"uid=1000\n", "cannot parse line 1: expected access and path"},
+		{"uid=1000,x r /usr\n", `cannot parse line 1: invalid user ID "x"`},
+		{"uid= r /usr\n", `cannot parse line 1: invalid user ID ""`},
+	} {
+		_, err := landlock.ParseRuleset(strings.NewReader(t.in))
+		c.Check(err, ErrorMatches, t.err)
+	}
+}
+
+func (s *landlockSuite) TestReadRuleset(c *C) {
+	path := filepath.Join(c.MkDir(), "snap.foo.app")
+	c.Assert(os.WriteFile(path, []byte("r /etc\nbad\n"), 0644), IsNil)
+	_, err := landlock.ReadRuleset(path)
+	c.Check(err, ErrorMatches, `cannot read landlock ruleset ".*/snap.foo.app": cannot parse line 2: expected access and path`)
+
+	_, err = landlock.ReadRuleset(filepath.Join(c.MkDir(), "missing"))
+	c.Check(os.IsNotExist(err), Equals, true)
+}
+
+func (s *landlockSuite) TestExpandRules(c *C) {
+	env := map[string]string{
+		"SNAP_DATA": "/var/snap/foo/1",
+		"HOME":      "/home/user/",
+	}
+	rules := []landlock.Rule{
+		{Access: landlock.AccessRead, Path: "/usr"},
+		{Access: landlock.AccessRead, Path: "$SNAP_DATA"},
+		{Access: landlock.AccessWrite, Path: "$SNAP_DATA/"},
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$HOME/.config/foo"},
+		{Access: landlock.AccessRead, Path: "$XDG_RUNTIME_DIR"},
+		{Access: landlock.AccessExecute, Path: "/usr"},
+	}
+	expanded := landlock.ExpandRules(rules, func(name string) string { return env[name] })
+	c.Check(expanded, DeepEquals, []landlock.Rule{
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/home/user/.config/foo"},
+		{Access: landlock.AccessRead | landlock.AccessExecute, Path: "/usr"},
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: "/var/snap/foo/1"},
+	})
+}
+
+func (s *landlockSuite) TestExpandRulesWildcards(c *C) {
+	root := c.MkDir()
+	for _, dir := range []string{"dev", "media/user/usb", "media/other", "run/media", "w*ld"} {
+		c.Assert(os.MkdirAll(filepath.Join(root, dir), 0755), IsNil)
+	}
+	for _, f := range []string{"dev/video0", "dev/video12", "dev/sda"} {
+		c.Assert(os.WriteFile(filepath.Join(root, f), nil, 0644), IsNil)
+	}
+	env := map[string]string{
+		"ROOT": root,
+		"WILD": root + "/w*ld",
+	}
+	rules := []landlock.Rule{
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$ROOT/dev/video[0-9]*"},
+		{Access: landlock.AccessRead, Path: "$ROOT/media/*"},
+		{Access: landlock.AccessWrite, Path: "$ROOT/media/user"},
+		{Access: landlock.AccessRead, Path: "$ROOT/run/media/*"},
+		{Access: landlock.AccessRead, Path: "$ROOT/dev/[bad"},
+		{Access: landlock.AccessRead, Path: "$WILD"},
+	}
+	expanded := landlock.ExpandRules(rules, func(name string) string { return env[name] })
+	c.Check(expanded, DeepEquals, []landlock.Rule{
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: root + "/dev/video0"},
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: root + "/dev/video12"},
+		{Access: landlock.AccessRead, Path: root + "/media/other"},
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: root + "/media/user"},
+		{Access: landlock.AccessRead, Path: root + "/w*ld"},
+	})
+}
+
+func (s *landlockSuite) TestRulesForUser(c *C) {
+	rules := []landlock.Rule{
+		{Access: landlock.AccessRead, Path: "/usr"},
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$HOME", Users: []int{1000, 1001}},
+		{Access: landlock.AccessRead, Path: "/media", Users: []int{1002}},
+	}
+	c.Check(landlock.RulesForUser(rules, 1001), DeepEquals, []landlock.Rule{
+		{Access: landlock.AccessRead, Path: "/usr"},
+		{Access: landlock.AccessRead | landlock.AccessWrite, Path: "$HOME"},
+	})
+	c.Check(landlock.RulesForUser(rules, 0), DeepEquals, []landlock.Rule{
+		{Access: landlock.AccessRead, Path: "/usr"},
+	})
+}
+
+func (s *landlockSuite) TestMockABI(c *C) {
+	restore := landlock.MockABI(2)
+	c.Check(landlock.ProbedABI(), Equals, 2)
+	c.Check(landlock.Summary(), Equals, "Landlock ABI version 2 is supported")
+	landlock.MockABI(0)
+	c.Check(landlock.Summary(), Equals, "Landlock is not supported")
+	restore()
+}
@@ -352,6 +352,40 @@ func (ss *SeedSnaps) AssertedComponentInfos(snapName string) []*snap.ComponentIn
 	return ss.compInfos[snapName]
 }
 
+// MakeSnapMirror populates dir as a local snap mirror, as consumed by
+// tooling.NewToolingStoreFromMirror, with the given asserted snaps and
+// their components, all the assertions from StoreSigning and, unless
+// empty, a channels.yaml with the given content.
+func (ss *SeedSnaps) MakeSnapMirror(c *C, dir, channelsYaml string, snapNames ...string) {
+	for _, snapName := range snapNames {
+		info := ss.infos[snapName]
+		c.Assert(info, NotNil, Commentf("unknown asserted snap %q", snapName))
+		err := osutil.CopyFile(ss.snaps[snapName], filepath.Join(dir, info.Filename()), 0)
+		c.Assert(err, IsNil)
+		for _, ci := range ss.compInfos[snapName] {
+			fn := fmt.Sprintf("%s_%s.comp", ci.Component, ci.Revision)
+			err := osutil.CopyFile(ss.snaps[ci.Component.String()], filepath.Join(dir, fn), 0)
+			c.Assert(err, IsNil)
+		}
+	}
+
+	var all []asserts.Assertion
+	for _, typeName := range asserts.TypeNames() {
+		as, err := ss.StoreSigning.FindMany(asserts.Type(typeName), nil)
+		if errors.Is(err, &asserts.NotFoundError{}) {
+			continue
+		}
+		c.Assert(err, IsNil)
+		all = append(all, as...)
+	}
+	WriteAssertions(filepath.Join(dir, "store.assert"), all...)
+
+	if channelsYaml != "" {
+		err := os.WriteFile(filepath.Join(dir, "channels.yaml"), []byte(channelsYaml), 0644)
+		c.Assert(err, IsNil)
+	}
+}
+
 // TestingSeed16 helps setting up a populated Core 16/18 testing seed.
 type TestingSeed16 struct {
 	SeedSnaps
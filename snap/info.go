@@ -1314,6 +1314,91 @@ type TimerInfo struct {
 	Timer string
 }
 
+// WatchInfo provides information on the paths that activate an application
+// when they change.
+type WatchInfo struct {
+	App *AppInfo
+
+	PathExists        []string
+	PathExistsGlob    []string
+	PathChanged       []string
+	PathModified      []string
+	DirectoryNotEmpty []string
+}
+
+// ProbeKind is the kind of a probe of a snap service.
+type ProbeKind string
+
+const (
+	// LivenessProbe probes whether the service is still working; the
+	// service is restarted when the probe keeps failing.
+	LivenessProbe ProbeKind = "liveness"
+	// ReadinessProbe probes whether the service is ready to do its work.
+	ReadinessProbe ProbeKind = "readiness"
+)
+
+const (
+	// DefaultProbeInterval is the interval between two runs of a probe
+	// when none is declared.
+	DefaultProbeInterval = 10 * time.Second
+	// DefaultProbeTimeout is the time a probe may take when none is
+	// declared.
+	DefaultProbeTimeout = 5 * time.Second
+	// DefaultExecProbeInterval and DefaultExecProbeTimeout are the
+	// defaults for exec probes, which run a command under the
+	// confinement of the app.
+	DefaultExecProbeInterval = 30 * time.Second
+	DefaultExecProbeTimeout  = 10 * time.Second
+	// DefaultProbeFailureThreshold is the number of consecutive failures
+	// of a probe after which the service is considered to be failing when
+	// none is declared.
+	DefaultProbeFailureThreshold = 3
+
+	// MinProbeInterval and MinProbeTimeout are the shortest interval and
+	// timeout a probe may declare.
+	MinProbeInterval = time.Second
+	MinProbeTimeout  = time.Second
+	// MinExecProbeInterval and MinExecProbeTimeout are the shortest
+	// interval and timeout an exec probe may declare.
+	MinExecProbeInterval = 10 * time.Second
+	MinExecProbeTimeout  = 5 * time.Second
+)
+
+// ProbeInfo provides information on a liveness or readiness probe of an
+// application. Exactly one of Exec, TCPPort or HTTPPort is set.
+type ProbeInfo struct {
+	App *AppInfo
+
+	Kind ProbeKind
+
+	// Exec is a command of the snap, run under the confinement of the
+	// app.
+	Exec string
+	// TCPPort is a port on localhost a connection is established to.
+	TCPPort int
+	// HTTPPort and HTTPPath describe a resource on localhost that is
+	// fetched with an HTTP GET request.
+	HTTPPort int
+	HTTPPath string
+
+	Interval         timeout.Timeout
+	Timeout          timeout.Timeout
+	FailureThreshold int
+}
+
+// Type returns the type of the probe, one of "exec", "tcp" or "http".
+func (probe *ProbeInfo) Type() string {
+	switch {
+	case probe.Exec != "":
+		return "exec"
+	case probe.TCPPort != 0:
+		return "tcp"
+	case probe.HTTPPort != 0:
+		return "http"
+	}
+	return ""
+}
+
 // StopModeType is the type for the "stop-mode:" of a snap app
 type StopModeType string
 
@@ -1397,8 +1482,17 @@ type AppInfo struct {
 	After  []string
 	Before []string
 
+	// list of plugs of this service, for which the service will start
+	// after the services backing the connected slots
+	AfterPlugs []string
+
 	Timer *TimerInfo
 
+	Watch *WatchInfo
+
+	LivenessProbe  *ProbeInfo
+	ReadinessProbe *ProbeInfo
+
 	Autostart string
 }
 
@@ -1499,6 +1593,11 @@ func (timer *TimerInfo) File() string {
 	return filepath.Join(timer.App.serviceDir(), timer.App.SecurityTag()+".timer")
 }
 
+// File returns the systemd path unit file path for the application watch.
+func (watch *WatchInfo) File() string {
+	return filepath.Join(watch.App.serviceDir(), watch.App.SecurityTag()+".path")
+}
+
 func (app *AppInfo) String() string {
 	return JoinSnapApp(app.Snap.InstanceName(), app.Name)
 }
@@ -1615,6 +1714,23 @@ func (app *AppInfo) LauncherPostStopCommand() string {
 	return app.launcherCommand("--command=post-stop")
 }
 
+// LauncherProbeCommand returns the launcher command line to use when invoking
+// the exec command of the given probe of the app.
+func (app *AppInfo) LauncherProbeCommand(kind ProbeKind) string {
+	return app.launcherCommand(fmt.Sprintf("--command=%s-probe", kind))
+}
+
+// Probes returns the liveness and readiness probes declared by the app.
+func (app *AppInfo) Probes() []*ProbeInfo {
+	var probes []*ProbeInfo
+	for _, probe := range []*ProbeInfo{app.LivenessProbe, app.ReadinessProbe} {
+		if probe != nil {
+			probes = append(probes, probe)
+		}
+	}
+	return probes
+}
+
 // ServiceName returns the systemd service name for the daemon app.
 func (app *AppInfo) ServiceName() string {
 	return app.SecurityTag() + ".service"
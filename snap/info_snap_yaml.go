@@ -107,11 +107,41 @@ type appYaml struct {
 	After  []string `yaml:"after,omitempty"`
 	Before []string `yaml:"before,omitempty"`
 
+	AfterPlugs []string `yaml:"after-plugs,omitempty"`
+
 	Timer string `yaml:"timer,omitempty"`
 
+	Watch *watchYaml `yaml:"watch,omitempty"`
+
+	LivenessProbe  *probeYaml `yaml:"liveness-probe,omitempty"`
+	ReadinessProbe *probeYaml `yaml:"readiness-probe,omitempty"`
+
 	Autostart string `yaml:"autostart,omitempty"`
 }
 
+type watchYaml struct {
+	PathExists        []string `yaml:"path-exists,omitempty"`
+	PathExistsGlob    []string `yaml:"path-exists-glob,omitempty"`
+	PathChanged       []string `yaml:"path-changed,omitempty"`
+	PathModified      []string `yaml:"path-modified,omitempty"`
+	DirectoryNotEmpty []string `yaml:"directory-not-empty,omitempty"`
+}
+
+type probeYaml struct {
+	Exec string `yaml:"exec,omitempty"`
+	TCP  *struct {
+		Port int `yaml:"port"`
+	} `yaml:"tcp,omitempty"`
+	HTTP *struct {
+		Port int    `yaml:"port"`
+		Path string `yaml:"path,omitempty"`
+	} `yaml:"http,omitempty"`
+
+	Interval         timeout.Timeout `yaml:"interval,omitempty"`
+	Timeout          timeout.Timeout `yaml:"timeout,omitempty"`
+	FailureThreshold int             `yaml:"failure-threshold,omitempty"`
+}
+
 type hookYaml struct {
 	PlugNames    []string           `yaml:"plugs,omitempty"`
 	SlotNames    []string           `yaml:"slots,omitempty"`
@@ -457,6 +487,7 @@ func setAppsFromSnapYaml(y snapYaml, snap *Info, strk *scopedTracker) error {
 			InstallMode:       yApp.InstallMode,
 			Before:            yApp.Before,
 			After:             yApp.After,
+			AfterPlugs:        yApp.AfterPlugs,
 			Autostart:         yApp.Autostart,
 			WatchdogTimeout:   yApp.WatchdogTimeout,
 		}
@@ -543,6 +574,18 @@ func setAppsFromSnapYaml(y snapYaml, snap *Info, strk *scopedTracker) error {
 				Timer: yApp.Timer,
 			}
 		}
+		if yApp.Watch != nil {
+			app.Watch = &WatchInfo{
+				App:               app,
+				PathExists:        yApp.Watch.PathExists,
+				PathExistsGlob:    yApp.Watch.PathExistsGlob,
+				PathChanged:       yApp.Watch.PathChanged,
+				PathModified:      yApp.Watch.PathModified,
+				DirectoryNotEmpty: yApp.Watch.DirectoryNotEmpty,
+			}
+		}
+		app.LivenessProbe = probeFromYaml(app, LivenessProbe, yApp.LivenessProbe)
+		app.ReadinessProbe = probeFromYaml(app, ReadinessProbe, yApp.ReadinessProbe)
 		// collect all common IDs
 		if app.CommonID != "" {
 			snap.CommonIDs = append(snap.CommonIDs, app.CommonID)
@@ -551,6 +594,44 @@ func setAppsFromSnapYaml(y snapYaml, snap *Info, strk *scopedTracker) error {
 	return nil
 }
 
+func probeFromYaml(app *AppInfo, kind ProbeKind, y *probeYaml) *ProbeInfo {
+	if y == nil {
+		return nil
+	}
+	probe := &ProbeInfo{
+		App:              app,
+		Kind:             kind,
+		Exec:             y.Exec,
+		Interval:         y.Interval,
+		Timeout:          y.Timeout,
+		FailureThreshold: y.FailureThreshold,
+	}
+	if y.TCP != nil {
+		probe.TCPPort = y.TCP.Port
+	}
+	if y.HTTP != nil {
+		probe.HTTPPort = y.HTTP.Port
+		probe.HTTPPath = y.HTTP.Path
+		if probe.HTTPPath == "" {
+			probe.HTTPPath = "/"
+		}
+	}
+	defInterval, defTimeout := DefaultProbeInterval, DefaultProbeTimeout
+	if probe.Exec != "" {
+		defInterval, defTimeout = DefaultExecProbeInterval, DefaultExecProbeTimeout
+	}
+	if probe.Interval == 0 {
+		probe.Interval = timeout.Timeout(defInterval)
+	}
+	if probe.Timeout == 0 {
+		probe.Timeout = timeout.Timeout(defTimeout)
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = DefaultProbeFailureThreshold
+	}
+	return probe
+}
+
 func setHooksFromSnapYaml(y snapYaml, snap *Info, strk *scopedTracker) {
 	for hookName, yHook := range y.Hooks {
 		if !IsHookSupported(hookName) {
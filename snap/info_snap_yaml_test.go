@@ -1912,6 +1912,117 @@ apps:
 	c.Check(app.Timer, DeepEquals, &snap.TimerInfo{App: app, Timer: "mon,10:00-12:00"})
 }
 
+func (s *YamlSuite) TestSnapYamlAppWatch(c *C) {
+	y := []byte(`name: wat
+version: 42
+apps:
+ foo:
+   daemon: oneshot
+   watch:
+     directory-not-empty: [$SNAP_COMMON/incoming]
+     path-exists-glob: [$SNAP_DATA/*.trigger]
+     path-changed:
+       - $SNAP_DATA/config.yaml
+       - $SNAP_DATA/other.yaml
+ bar:
+   daemon: simple
+`)
+	info, err := snap.InfoFromSnapYaml(y)
+	c.Assert(err, IsNil)
+	app := info.Apps["foo"]
+	c.Check(app.Watch, DeepEquals, &snap.WatchInfo{
+		App:               app,
+		PathExistsGlob:    []string{"$SNAP_DATA/*.trigger"},
+		PathChanged:       []string{"$SNAP_DATA/config.yaml", "$SNAP_DATA/other.yaml"},
+		DirectoryNotEmpty: []string{"$SNAP_COMMON/incoming"},
+	})
+	c.Check(app.Watch.File(), Matches, ".*/etc/systemd/system/snap.wat.foo.path")
+	c.Check(info.Apps["bar"].Watch, IsNil)
+}
+
+func (s *YamlSuite) TestSnapYamlAppAfterPlugs(c *C) {
+	y := []byte(`name: wat
+version: 42
+apps:
+ foo:
+   daemon: simple
+   plugs: [db, network]
+   after-plugs: [db]
+ bar:
+   daemon: simple
+`)
+	info, err := snap.InfoFromSnapYaml(y)
+	c.Assert(err, IsNil)
+	c.Check(info.Apps["foo"].AfterPlugs, DeepEquals, []string{"db"})
+	c.Check(info.Apps["bar"].AfterPlugs, IsNil)
+}
+
+func (s *YamlSuite) TestSnapYamlAppProbes(c *C) {
+	y := []byte(`name: wat
+version: 42
+apps:
+ foo:
+   daemon: simple
+   liveness-probe:
+     http:
+       port: 8080
+       path: /healthz
+     interval: 30s
+     timeout: 5s
+     failure-threshold: 5
+   readiness-probe:
+     tcp:
+       port: 8080
+ bar:
+   daemon: simple
+   liveness-probe:
+     exec: bin/check
+     http:
+       port: 80
+ baz:
+   daemon: simple
+`)
+	info, err := snap.InfoFromSnapYaml(y)
+	c.Assert(err, IsNil)
+
+	app := info.Apps["foo"]
+	c.Check(app.LivenessProbe, DeepEquals, &snap.ProbeInfo{
+		App:              app,
+		Kind:             snap.LivenessProbe,
+		HTTPPort:         8080,
+		HTTPPath:         "/healthz",
+		Interval:         timeout.Timeout(30 * time.Second),
+		Timeout:          timeout.Timeout(5 * time.Second),
+		FailureThreshold: 5,
+	})
+	c.Check(app.LivenessProbe.Type(), Equals, "http")
+	c.Check(app.ReadinessProbe, DeepEquals, &snap.ProbeInfo{
+		App:              app,
+		Kind:             snap.ReadinessProbe,
+		TCPPort:          8080,
+		Interval:         timeout.Timeout(snap.DefaultProbeInterval),
+		Timeout:          timeout.Timeout(snap.DefaultProbeTimeout),
+		FailureThreshold: snap.DefaultProbeFailureThreshold,
+	})
+	c.Check(app.ReadinessProbe.Type(), Equals, "tcp")
+	c.Check(app.Probes(), DeepEquals, []*snap.ProbeInfo{app.LivenessProbe, app.ReadinessProbe})
+	c.Check(app.LauncherProbeCommand(snap.LivenessProbe), Equals, "/usr/bin/snap run --command=liveness-probe wat.foo")
+
+	app = info.Apps["bar"]
+	c.Check(app.LivenessProbe.Exec, Equals, "bin/check")
+	c.Check(app.LivenessProbe.HTTPPort, Equals, 80)
+	c.Check(app.LivenessProbe.HTTPPath, Equals, "/")
+	// exec probes have longer defaults
+	c.Check(app.LivenessProbe.Interval, Equals, timeout.Timeout(snap.DefaultExecProbeInterval))
+	c.Check(app.LivenessProbe.Timeout, Equals, timeout.Timeout(snap.DefaultExecProbeTimeout))
+	c.Check(app.ReadinessProbe, IsNil)
+
+	app = info.Apps["baz"]
+	c.Check(app.LivenessProbe, IsNil)
+	c.Check(app.ReadinessProbe, IsNil)
+	c.Check(app.Probes(), HasLen, 0)
+}
+
 func (s *YamlSuite) TestSnapYamlAppAutostart(c *C) {
 	yAutostart := []byte(`name: wat
 version: 42
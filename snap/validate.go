@@ -29,6 +29,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/snapcore/snapd/osutil"
@@ -249,23 +250,29 @@ func validateSocketAddr(socket *SocketInfo, fieldName string, address string) er
 }
 
 func validateSocketAddrPath(socket *SocketInfo, fieldName string, path string) error {
+	return validateDaemonPath(socket.App, "sockets", fieldName, path)
+}
+
+// validateDaemonPath checks that a path used by a daemon, such as the path
+// of a socket or of a watch, is within the writable areas of the snap.
+func validateDaemonPath(app *AppInfo, what, fieldName, path string) error {
 	if clean := filepath.Clean(path); clean != path {
 		return fmt.Errorf("invalid %q: %q should be written as %q", fieldName, path, clean)
 	}
 
-	switch socket.App.DaemonScope {
+	switch app.DaemonScope {
 	case SystemDaemon:
 		if !(strings.HasPrefix(path, "$SNAP_DATA/") || strings.HasPrefix(path, "$SNAP_COMMON/") || strings.HasPrefix(path, "$XDG_RUNTIME_DIR/")) {
 			return fmt.Errorf(
-				"invalid %q: system daemon sockets must have a prefix of $SNAP_DATA, $SNAP_COMMON or $XDG_RUNTIME_DIR", fieldName)
+				"invalid %q: system daemon %s must have a prefix of $SNAP_DATA, $SNAP_COMMON or $XDG_RUNTIME_DIR", fieldName, what)
 		}
 	case UserDaemon:
 		if !(strings.HasPrefix(path, "$SNAP_USER_DATA/") || strings.HasPrefix(path, "$SNAP_USER_COMMON/") || strings.HasPrefix(path, "$XDG_RUNTIME_DIR/")) {
 			return fmt.Errorf(
-				"invalid %q: user daemon sockets must have a prefix of $SNAP_USER_DATA, $SNAP_USER_COMMON, or $XDG_RUNTIME_DIR", fieldName)
+				"invalid %q: user daemon %s must have a prefix of $SNAP_USER_DATA, $SNAP_USER_COMMON, or $XDG_RUNTIME_DIR", fieldName, what)
 		}
 	default:
-		return fmt.Errorf("invalid %q: cannot validate sockets for daemon-scope %q", fieldName, socket.App.DaemonScope)
+		return fmt.Errorf("invalid %q: cannot validate %s for daemon-scope %q", fieldName, what, app.DaemonScope)
 	}
 
 	return nil
@@ -749,6 +756,24 @@ func validateAppOrderNames(app *AppInfo, dependencies []string) error {
 	return nil
 }
 
+func validateAppAfterPlugs(app *AppInfo) error {
+	if len(app.AfterPlugs) == 0 {
+		return nil
+	}
+	if !app.IsService() {
+		return errors.New("must be a service to define after-plugs ordering")
+	}
+	if app.DaemonScope != SystemDaemon {
+		return errors.New("after-plugs ordering is only supported for system services")
+	}
+	for _, plugName := range app.AfterPlugs {
+		if _, ok := app.Plugs[plugName]; !ok {
+			return fmt.Errorf("after-plugs references plug %q which is not bound to the application", plugName)
+		}
+	}
+	return nil
+}
+
 func validateAppTimeouts(app *AppInfo) error {
 	type T struct {
 		desc    string
@@ -788,6 +813,115 @@ func validateAppTimer(app *AppInfo) error {
 	return nil
 }
 
+func validateAppWatch(app *AppInfo) error {
+	if app.Watch == nil {
+		return nil
+	}
+
+	if !app.IsService() {
+		return errors.New("watch is only applicable to services")
+	}
+
+	watch := app.Watch
+	n := 0
+	for _, w := range []struct {
+		fieldName string
+		paths     []string
+	}{
+		{"path-exists", watch.PathExists},
+		{"path-exists-glob", watch.PathExistsGlob},
+		{"path-changed", watch.PathChanged},
+		{"path-modified", watch.PathModified},
+		{"directory-not-empty", watch.DirectoryNotEmpty},
+	} {
+		for _, path := range w.paths {
+			n++
+			if err := validateField(w.fieldName, path, watchPathWhitelist); err != nil {
+				return err
+			}
+			if w.fieldName != "path-exists-glob" && strings.ContainsAny(path, "*?[") {
+				return fmt.Errorf("invalid %q: %q cannot contain wildcards", w.fieldName, path)
+			}
+			if strings.Count(path, "$") > 1 {
+				return fmt.Errorf("invalid %q: %q can only use a variable as prefix", w.fieldName, path)
+			}
+			if err := validateDaemonPath(app, "watches", w.fieldName, path); err != nil {
+				return err
+			}
+		}
+	}
+	if n == 0 {
+		return errors.New("watch must define at least one path")
+	}
+	return nil
+}
+
+func validateAppProbe(app *AppInfo, probe *ProbeInfo) error {
+	if probe == nil {
+		return nil
+	}
+	name := string(probe.Kind) + "-probe"
+	if !app.IsService() {
+		return fmt.Errorf("%s is only applicable to services", name)
+	}
+	if app.DaemonScope != SystemDaemon {
+		return fmt.Errorf("%s is only applicable to system services", name)
+	}
+
+	types := 0
+	for _, set := range []bool{probe.Exec != "", probe.TCPPort != 0, probe.HTTPPort != 0} {
+		if set {
+			types++
+		}
+	}
+	if types != 1 {
+		return fmt.Errorf(`%s must define exactly one of "exec", "tcp" or "http"`, name)
+	}
+
+	switch probe.Type() {
+	case "exec":
+		if err := validateField(name, probe.Exec, appContentWhitelist); err != nil {
+			return err
+		}
+	case "tcp":
+		if probe.TCPPort < 1 || probe.TCPPort > 65535 {
+			return fmt.Errorf("%s has invalid port %d", name, probe.TCPPort)
+		}
+	case "http":
+		if probe.HTTPPort < 1 || probe.HTTPPort > 65535 {
+			return fmt.Errorf("%s has invalid port %d", name, probe.HTTPPort)
+		}
+		if !strings.HasPrefix(probe.HTTPPath, "/") || strings.ContainsAny(probe.HTTPPath, " \t\n\r#") {
+			return fmt.Errorf("%s has invalid path %q", name, probe.HTTPPath)
+		}
+	}
+
+	if probe.Interval < 0 {
+		return fmt.Errorf("%s interval cannot be negative", name)
+	}
+	if probe.Timeout < 0 {
+		return fmt.Errorf("%s timeout cannot be negative", name)
+	}
+	minInterval, minTimeout := MinProbeInterval, MinProbeTimeout
+	if probe.Type() == "exec" {
+		// running a command under confinement takes a while
+		minInterval, minTimeout = MinExecProbeInterval, MinExecProbeTimeout
+	}
+	if time.Duration(probe.Interval) < minInterval {
+		return fmt.Errorf("%s interval cannot be shorter than %s", name, minInterval)
+	}
+	if time.Duration(probe.Timeout) < minTimeout {
+		return fmt.Errorf("%s timeout cannot be shorter than %s", name, minTimeout)
+	}
+	if probe.Timeout > probe.Interval {
+		return fmt.Errorf("%s timeout cannot be longer than its interval", name)
+	}
+	if probe.FailureThreshold < 0 {
+		return fmt.Errorf("%s failure-threshold cannot be negative", name)
+	}
+	return nil
+}
+
 func validateAppRestart(app *AppInfo) error {
 	// app.RestartCond value is validated when unmarshalling
 
@@ -873,6 +1007,7 @@ func validateAppActivatesOn(app *AppInfo) error {
 // will get confused. chainContentWhitelist is the same, but for the
 // command-chain, which also doesn't allow whitespace.
 var appContentWhitelist = regexp.MustCompile(`^[A-Za-z0-9/. _#:$-]*$`)
+var watchPathWhitelist = regexp.MustCompile(`^[A-Za-z0-9/._:$@+*?\[\]-]*$`)
 var commandChainContentWhitelist = regexp.MustCompile(`^[A-Za-z0-9/._#:$-]*$`)
 
 // ValidAppName tells whether a string is a valid application name.
@@ -955,6 +1090,9 @@ func ValidateApp(app *AppInfo) error {
 	if err := validateAppOrderNames(app, app.After); err != nil {
 		return err
 	}
+	if err := validateAppAfterPlugs(app); err != nil {
+		return err
+	}
 
 	if err := validateAppTimeouts(app); err != nil {
 		return err
@@ -964,6 +1102,17 @@ func ValidateApp(app *AppInfo) error {
 		return err
 	}
 
+	if err := validateAppWatch(app); err != nil {
+		return err
+	}
+
+	if err := validateAppProbe(app, app.LivenessProbe); err != nil {
+		return err
+	}
+	if err := validateAppProbe(app, app.ReadinessProbe); err != nil {
+		return err
+	}
+
 	// validate stop-mode
 	if err := app.StopMode.Validate(); err != nil {
 		return err
@@ -1695,6 +1695,334 @@ apps:
 	}
 }
 
+func (s *ValidateSuite) TestValidateAppWatch(c *C) {
+	meta := []byte(`
+name: foo
+version: 1.0
+apps:
+  foo:
+`)
+	for _, tc := range []struct {
+		desc string
+		err  string
+	}{{
+		desc: `
+    daemon: oneshot
+    watch:
+      path-exists: [$SNAP_DATA/trigger]
+      path-exists-glob: [$SNAP_COMMON/incoming/*.csv]
+      path-changed: [$SNAP_DATA/config.yaml]
+      path-modified: [$XDG_RUNTIME_DIR/state]
+      directory-not-empty: [$SNAP_COMMON/spool]
+`,
+	}, {
+		desc: `
+    daemon: simple
+    daemon-scope: user
+    watch:
+      directory-not-empty: [$SNAP_USER_COMMON/spool]
+`,
+	}, {
+		desc: `
+    watch:
+      path-exists: [$SNAP_DATA/trigger]
+`,
+		err: `watch is only applicable to services`,
+	}, {
+		desc: `
+    daemon: simple
+    watch: {}
+`,
+		err: `watch must define at least one path`,
+	}, {
+		desc: `
+    daemon: simple
+    watch:
+      path-exists: [/etc/passwd]
+`,
+		err: `invalid "path-exists": system daemon watches must have a prefix of \$SNAP_DATA, \$SNAP_COMMON or \$XDG_RUNTIME_DIR`,
+	}, {
+		desc: `
+    daemon: simple
+    watch:
+      path-changed: [$SNAP/meta/snap.yaml]
+`,
+		err: `invalid "path-changed": system daemon watches must have a prefix of .*`,
+	}, {
+		desc: `
+    daemon: simple
+    daemon-scope: user
+    watch:
+      path-changed: [$SNAP_DATA/config]
+`,
+		err: `invalid "path-changed": user daemon watches must have a prefix of \$SNAP_USER_DATA, \$SNAP_USER_COMMON, or \$XDG_RUNTIME_DIR`,
+	}, {
+		desc: `
+    daemon: simple
+    watch:
+      directory-not-empty: [$SNAP_DATA/../../etc]
+`,
+		err: `invalid "directory-not-empty": "\$SNAP_DATA/../../etc" should be written as "../etc"`,
+	}, {
+		desc: `
+    daemon: simple
+    watch:
+      path-exists: [$SNAP_DATA/*.trigger]
+`,
+		err: `invalid "path-exists": "\$SNAP_DATA/\*.trigger" cannot contain wildcards`,
+	}, {
+		desc: `
+    daemon: simple
+    watch:
+      path-exists: [$SNAP_DATA/$HOME]
+`,
+		err: `invalid "path-exists": "\$SNAP_DATA/\$HOME" can only use a variable as prefix`,
+	}, {
+		desc: `
+    daemon: simple
+    watch:
+      path-exists: ["$SNAP_DATA/with space"]
+`,
+		err: `app description field 'path-exists' contains illegal .*`,
+	}} {
+		info, err := InfoFromSnapYaml(append(meta, tc.desc...))
+		c.Assert(err, IsNil)
+
+		err = Validate(info)
+		if tc.err != "" {
+			c.Check(err, ErrorMatches, `invalid definition of application "foo": `+tc.err, Commentf(tc.desc))
+		} else {
+			c.Check(err, IsNil, Commentf(tc.desc))
+		}
+	}
+}
+
+func (s *ValidateSuite) TestValidateAppAfterPlugs(c *C) {
+	meta := []byte(`
+name: foo
+version: 1.0
+plugs:
+  db:
+    interface: content
+    target: $SNAP_DATA/db
+apps:
+  other:
+    daemon: simple
+  foo:
+`)
+	for _, tc := range []struct {
+		desc string
+		err  string
+	}{{
+		desc: `
+    daemon: simple
+    after-plugs: [db]
+`,
+	}, {
+		desc: `
+    daemon: simple
+    plugs: [db, network]
+    after-plugs: [db, network]
+`,
+	}, {
+		desc: `
+    after-plugs: [db]
+`,
+		err: `must be a service to define after-plugs ordering`,
+	}, {
+		desc: `
+    daemon: simple
+    daemon-scope: user
+    after-plugs: [db]
+`,
+		err: `after-plugs ordering is only supported for system services`,
+	}, {
+		desc: `
+    daemon: simple
+    after-plugs: [missing]
+`,
+		err: `after-plugs references plug "missing" which is not bound to the application`,
+	}} {
+		info, err := InfoFromSnapYaml(append(meta, tc.desc...))
+		c.Assert(err, IsNil)
+
+		err = Validate(info)
+		if tc.err != "" {
+			c.Check(err, ErrorMatches, `invalid definition of application "foo": `+tc.err, Commentf(tc.desc))
+		} else {
+			c.Check(err, IsNil, Commentf(tc.desc))
+		}
+	}
+}
+
+func (s *ValidateSuite) TestValidateAppProbes(c *C) {
+	meta := []byte(`
+name: foo
+version: 1.0
+apps:
+  foo:
+`)
+	for _, tc := range []struct {
+		desc string
+		err  string
+	}{{
+		desc: `
+    daemon: simple
+    liveness-probe:
+      http:
+        port: 8080
+        path: /healthz?full=1
+    readiness-probe:
+      exec: bin/ready --quiet
+`,
+	}, {
+		desc: `
+    liveness-probe:
+      tcp:
+        port: 80
+`,
+		err: `liveness-probe is only applicable to services`,
+	}, {
+		desc: `
+    daemon: simple
+    daemon-scope: user
+    readiness-probe:
+      tcp:
+        port: 80
+`,
+		err: `readiness-probe is only applicable to system services`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      interval: 5s
+`,
+		err: `liveness-probe must define exactly one of "exec", "tcp" or "http"`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      exec: bin/check
+      tcp:
+        port: 80
+`,
+		err: `liveness-probe must define exactly one of "exec", "tcp" or "http"`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      exec: bin/check;rm
+`,
+		err: `app description field 'liveness-probe' contains illegal .*`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      tcp:
+        port: 65536
+`,
+		err: `liveness-probe has invalid port 65536`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      http:
+        port: -1
+`,
+		err: `liveness-probe has invalid port -1`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      http:
+        port: 80
+        path: healthz
+`,
+		err: `liveness-probe has invalid path "healthz"`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      tcp:
+        port: 80
+      interval: -1s
+`,
+		err: `liveness-probe interval cannot be negative`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      tcp:
+        port: 80
+      timeout: -1s
+`,
+		err: `liveness-probe timeout cannot be negative`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      tcp:
+        port: 80
+      interval: 500ms
+`,
+		err: `liveness-probe interval cannot be shorter than 1s`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      http:
+        port: 80
+      timeout: 100ms
+`,
+		err: `liveness-probe timeout cannot be shorter than 1s`,
+	}, {
+		desc: `
+    daemon: simple
+    readiness-probe:
+      exec: bin/ready
+      interval: 5s
+`,
+		err: `readiness-probe interval cannot be shorter than 10s`,
+	}, {
+		desc: `
+    daemon: simple
+    readiness-probe:
+      exec: bin/ready
+      timeout: 2s
+`,
+		err: `readiness-probe timeout cannot be shorter than 5s`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      tcp:
+        port: 80
+      interval: 5s
+      timeout: 10s
+`,
+		err: `liveness-probe timeout cannot be longer than its interval`,
+	}, {
+		desc: `
+    daemon: simple
+    liveness-probe:
+      tcp:
+        port: 80
+      failure-threshold: -3
+`,
+		err: `liveness-probe failure-threshold cannot be negative`,
+	}} {
+		info, err := InfoFromSnapYaml(append(meta, tc.desc...))
+		c.Assert(err, IsNil)
+
+		err = Validate(info)
+		if tc.err != "" {
+			c.Check(err, ErrorMatches, `invalid definition of application "foo": `+tc.err, Commentf(tc.desc))
+		} else {
+			c.Check(err, IsNil, Commentf(tc.desc))
+		}
+	}
+}
+
 func (s *YamlSuite) TestValidateAppTimer(c *C) {
 	meta := []byte(`
 name: foo
@@ -0,0 +1,497 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package tooling
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/progress"
+	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/channel"
+	"github.com/snapcore/snapd/snap/snapfile"
+	"github.com/snapcore/snapd/store"
+)
+
+// MirrorChannelsFile is the name of the file in a snap mirror directory
+// that maps snap names and channels to revisions.
+const MirrorChannelsFile = "channels.yaml"
+
+// mirrorRisks are the channel risks ordered from the most to the least
+// stable, as for the store, a channel falls back to more stable risks.
+var mirrorRisks = []string{"stable", "candidate", "beta", "edge"}
+
+type mirrorSnap struct {
+	path     string
+	name     string
+	snapID   string
+	revision snap.Revision
+	size     int64
+	sha3_384 string
+}
+
+type mirrorComponent struct {
+	path     string
+	name     string
+	snapID   string
+	revision int
+	compType snap.ComponentType
+	version  string
+	size     int64
+	sha3_384 string
+}
+
+// mirrorStore implements StoreImpl on top of a local directory mirroring
+// snaps, components and assertions from the store, it never accesses the
+// network.
+type mirrorStore struct {
+	dir string
+	bs  asserts.Backstore
+
+	snaps    map[string][]*mirrorSnap
+	comps    map[string][]*mirrorComponent
+	channels map[string]map[string]snap.Revision
+
+	maxFormats map[string]int
+}
+
+// NewToolingStoreFromMirror creates a ToolingStore that serves snaps,
+// components and assertions from the given local mirror directory
+// instead of the store. The directory is expected to contain:
+//
+//   - *.snap and *.comp files, each with snap-revision or
+//     snap-resource-revision assertions identifying them
+//   - *.assert files with assertion bundles, including everything
+//     needed to check the snaps and the model's validation sets
+//   - a channels.yaml file mapping snap names to channels and the
+//     revision each channel points to, eg. "pc: {20/stable: 148}"
+//
+// Snaps are resolved only against the mirror, anything missing from it
+// results in an error.
+func NewToolingStoreFromMirror(dir string) (*ToolingStore, error) {
+	ms, err := newMirrorStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolingStore{sto: ms}, nil
+}
+
+func newMirrorStore(dir string) (*mirrorStore, error) {
+	// os.ReadDir returns the entries sorted by name, which makes
+	// loading the mirror deterministic
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read snap mirror: %v", err)
+	}
+
+	ms := &mirrorStore{
+		dir:   dir,
+		bs:    asserts.NewMemoryBackstore(),
+		snaps: make(map[string][]*mirrorSnap),
+		comps: make(map[string][]*mirrorComponent),
+	}
+
+	var snapPaths, compPaths []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		switch filepath.Ext(entry.Name()) {
+		case ".assert":
+			if err := ms.addAssertions(path); err != nil {
+				return nil, err
+			}
+		case ".snap":
+			snapPaths = append(snapPaths, path)
+		case ".comp":
+			compPaths = append(compPaths, path)
+		}
+	}
+
+	for _, path := range snapPaths {
+		if err := ms.addSnap(path); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range compPaths {
+		if err := ms.addComponent(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ms.readChannels(); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+func (ms *mirrorStore) addAssertions(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot read snap mirror assertions: %v", err)
+	}
+	defer f.Close()
+
+	dec := asserts.NewDecoder(f)
+	for {
+		a, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot decode snap mirror assertions from %q: %v", path, err)
+		}
+		if err := ms.bs.Put(a.Type(), a); err != nil {
+			// keep the latest revision
+			if _, ok := err.(*asserts.RevisionError); ok {
+				continue
+			}
+			return fmt.Errorf("cannot add snap mirror assertion %v: %v", a.Ref(), err)
+		}
+	}
+}
+
+// searchOne returns the assertion of the given type matching headers, when
+// more than one matches, the first one by unique reference is returned.
+func (ms *mirrorStore) searchOne(assertType *asserts.AssertionType, headers map[string]string) (asserts.Assertion, error) {
+	var found []asserts.Assertion
+	err := ms.bs.Search(assertType, headers, func(a asserts.Assertion) {
+		found = append(found, a)
+	}, assertType.MaxSupportedFormat())
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, &asserts.NotFoundError{Type: assertType, Headers: headers}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Ref().Unique() < found[j].Ref().Unique()
+	})
+	return found[0], nil
+}
+
+func fileSHA3_384(path string) (encoded, hexDigest string, size int64, err error) {
+	dgst, sz, err := osutil.FileDigest(path, crypto.SHA3_384)
+	if err != nil {
+		return "", "", 0, err
+	}
+	encoded, err = asserts.EncodeDigest(crypto.SHA3_384, dgst)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return encoded, fmt.Sprintf("%x", dgst), int64(sz), nil
+}
+
+func (ms *mirrorStore) addSnap(path string) error {
+	encDigest, hexDigest, size, err := fileSHA3_384(path)
+	if err != nil {
+		return fmt.Errorf("cannot compute digest of snap mirror file %q: %v", path, err)
+	}
+
+	a, err := ms.searchOne(asserts.SnapRevisionType, map[string]string{
+		"snap-sha3-384": encDigest,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot use %q from snap mirror: missing snap-revision assertion", path)
+	}
+	snapRev := a.(*asserts.SnapRevision)
+
+	a, err = ms.bs.Get(asserts.SnapDeclarationType, []string{release.Series, snapRev.SnapID()}, asserts.SnapDeclarationType.MaxSupportedFormat())
+	if err != nil {
+		return fmt.Errorf("cannot use %q from snap mirror: missing snap-declaration assertion for snap id %q", path, snapRev.SnapID())
+	}
+	snapDecl := a.(*asserts.SnapDeclaration)
+
+	name := snapDecl.SnapName()
+	revision := snap.R(snapRev.SnapRevision())
+	for _, other := range ms.snaps[name] {
+		if other.revision == revision {
+			return fmt.Errorf("cannot use %q from snap mirror: revision %s of snap %q is already provided by %q", path, revision, name, other.path)
+		}
+	}
+	ms.snaps[name] = append(ms.snaps[name], &mirrorSnap{
+		path:     path,
+		name:     name,
+		snapID:   snapRev.SnapID(),
+		revision: revision,
+		size:     size,
+		sha3_384: hexDigest,
+	})
+	return nil
+}
+
+func (ms *mirrorStore) addComponent(path string) error {
+	encDigest, hexDigest, size, err := fileSHA3_384(path)
+	if err != nil {
+		return fmt.Errorf("cannot compute digest of snap mirror file %q: %v", path, err)
+	}
+
+	a, err := ms.searchOne(asserts.SnapResourceRevisionType, map[string]string{
+		"resource-sha3-384": encDigest,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot use %q from snap mirror: missing snap-resource-revision assertion", path)
+	}
+	resRev := a.(*asserts.SnapResourceRevision)
+
+	compf, err := snapfile.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot use %q from snap mirror: %v", path, err)
+	}
+	ci, err := snap.ReadComponentInfoFromContainer(compf, nil, nil)
+	if err != nil {
+		return fmt.Errorf("cannot use %q from snap mirror: %v", path, err)
+	}
+
+	ms.comps[resRev.SnapID()] = append(ms.comps[resRev.SnapID()], &mirrorComponent{
+		path:     path,
+		name:     resRev.ResourceName(),
+		snapID:   resRev.SnapID(),
+		revision: resRev.ResourceRevision(),
+		compType: ci.Type,
+		version:  ci.Version(""),
+		size:     size,
+		sha3_384: hexDigest,
+	})
+	return nil
+}
+
+func (ms *mirrorStore) readChannels() error {
+	data, err := os.ReadFile(filepath.Join(ms.dir, MirrorChannelsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read snap mirror channels: %v", err)
+	}
+
+	var channels map[string]map[string]snap.Revision
+	if err := yaml.Unmarshal(data, &channels); err != nil {
+		return fmt.Errorf("cannot parse snap mirror channels: %v", err)
+	}
+
+	ms.channels = make(map[string]map[string]snap.Revision, len(channels))
+	for name, byChannel := range channels {
+		ms.channels[name] = make(map[string]snap.Revision, len(byChannel))
+		for ch, rev := range byChannel {
+			full, err := channel.Full(ch)
+			if err != nil || full == "" {
+				return fmt.Errorf("cannot parse snap mirror channels: invalid channel %q for snap %q", ch, name)
+			}
+			ms.channels[name][full] = rev
+		}
+	}
+	return nil
+}
+
+// channelRevision returns the revision of the snap in the given channel,
+// falling back to more stable risks of the same track like the store does.
+func (ms *mirrorStore) channelRevision(name, ch string) (snap.Revision, error) {
+	if ch == "" {
+		ch = "stable"
+	}
+	parsed, err := channel.Parse(ch, "")
+	if err != nil {
+		return snap.Revision{}, err
+	}
+
+	var candidates []string
+	if parsed.Branch != "" {
+		candidates = append(candidates, parsed.Full())
+	}
+	for i := riskLevel(parsed.Risk); i >= 0; i-- {
+		cand := channel.Channel{Track: parsed.Track, Risk: mirrorRisks[i]}.Clean()
+		candidates = append(candidates, cand.Full())
+	}
+
+	for _, cand := range candidates {
+		if rev, ok := ms.channels[name][cand]; ok {
+			return rev, nil
+		}
+	}
+	return snap.Revision{}, fmt.Errorf("no revision in channel %q", ch)
+}
+
+func riskLevel(risk string) int {
+	for i, r := range mirrorRisks {
+		if r == risk {
+			return i
+		}
+	}
+	return -1
+}
+
+func (ms *mirrorStore) snapRevision(name string, rev snap.Revision) *mirrorSnap {
+	for _, msnap := range ms.snaps[name] {
+		if msnap.revision == rev {
+			return msnap
+		}
+	}
+	return nil
+}
+
+// resources returns the components in the mirror that are paired with
+// the given snap revision, picking the highest revision of each.
+func (ms *mirrorStore) resources(msnap *mirrorSnap) []store.SnapResourceResult {
+	byName := make(map[string]*mirrorComponent)
+	for _, mcomp := range ms.comps[msnap.snapID] {
+		_, err := ms.searchOne(asserts.SnapResourcePairType, map[string]string{
+			"snap-id":           msnap.snapID,
+			"resource-name":     mcomp.name,
+			"resource-revision": strconv.Itoa(mcomp.revision),
+			"snap-revision":     msnap.revision.String(),
+		})
+		if err != nil {
+			continue
+		}
+		if cur := byName[mcomp.name]; cur == nil || cur.revision < mcomp.revision {
+			byName[mcomp.name] = mcomp
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make([]store.SnapResourceResult, 0, len(names))
+	for _, name := range names {
+		mcomp := byName[name]
+		res = append(res, store.SnapResourceResult{
+			DownloadInfo: snap.DownloadInfo{
+				DownloadURL: mcomp.path,
+				Size:        mcomp.size,
+				Sha3_384:    mcomp.sha3_384,
+			},
+			Type:     "component/" + string(mcomp.compType),
+			Name:     mcomp.name,
+			Revision: mcomp.revision,
+			Version:  mcomp.version,
+		})
+	}
+	return res
+}
+
+func (ms *mirrorStore) SnapAction(_ context.Context, _ []*store.CurrentSnap, actions []*store.SnapAction, assertQuery store.AssertionQuery, _ *auth.UserState, _ *store.RefreshOptions) ([]store.SnapActionResult, []store.AssertionResult, error) {
+	if assertQuery != nil {
+		return nil, nil, fmt.Errorf("internal error: snap mirror does not support assertion queries")
+	}
+
+	sars := make([]store.SnapActionResult, 0, len(actions))
+	for _, a := range actions {
+		if a.Action != "download" {
+			return nil, nil, fmt.Errorf("internal error: snap mirror does not support %q actions", a.Action)
+		}
+		name := a.InstanceName
+
+		rev := a.Revision
+		if rev.Unset() {
+			var err error
+			rev, err = ms.channelRevision(name, a.Channel)
+			if err != nil {
+				return nil, nil, fmt.Errorf("cannot download snap %q from snap mirror: %v", name, err)
+			}
+		}
+		msnap := ms.snapRevision(name, rev)
+		if msnap == nil {
+			return nil, nil, fmt.Errorf("cannot download snap %q from snap mirror: revision %s not found", name, rev)
+		}
+
+		snapf, err := snapfile.Open(msnap.path)
+		if err != nil {
+			return nil, nil, err
+		}
+		info, err := snap.ReadInfoFromSnapFile(snapf, &snap.SideInfo{
+			RealName: name,
+			SnapID:   msnap.snapID,
+			Revision: msnap.revision,
+			Channel:  a.Channel,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read snap %q from snap mirror: %v", name, err)
+		}
+		info.DownloadInfo = snap.DownloadInfo{
+			DownloadURL: msnap.path,
+			Size:        msnap.size,
+			Sha3_384:    msnap.sha3_384,
+		}
+
+		sars = append(sars, store.SnapActionResult{
+			Info:      info,
+			Resources: ms.resources(msnap),
+		})
+	}
+	return sars, nil, nil
+}
+
+func (ms *mirrorStore) Download(_ context.Context, name, targetFn string, downloadInfo *snap.DownloadInfo, _ progress.Meter, _ *auth.UserState, _ *store.DownloadOptions) error {
+	return osutil.CopyFile(downloadInfo.DownloadURL, targetFn, osutil.CopyFlagOverwrite)
+}
+
+func (ms *mirrorStore) maxFormat(assertType *asserts.AssertionType) int {
+	if maxFormat, ok := ms.maxFormats[assertType.Name]; ok {
+		return maxFormat
+	}
+	return assertType.MaxSupportedFormat()
+}
+
+func (ms *mirrorStore) Assertion(assertType *asserts.AssertionType, primaryKey []string, _ *auth.UserState) (asserts.Assertion, error) {
+	a, err := ms.bs.Get(assertType, primaryKey, ms.maxFormat(assertType))
+	if errors.Is(err, &asserts.NotFoundError{}) {
+		headers, _ := asserts.HeadersFromPrimaryKey(assertType, primaryKey)
+		return nil, &asserts.NotFoundError{Type: assertType, Headers: headers}
+	}
+	return a, err
+}
+
+func (ms *mirrorStore) SeqFormingAssertion(assertType *asserts.AssertionType, sequenceKey []string, sequence int, _ *auth.UserState) (asserts.Assertion, error) {
+	if !assertType.SequenceForming() {
+		return nil, fmt.Errorf("internal error: requested non sequence-forming assertion type %q", assertType.Name)
+	}
+	if sequence > 0 {
+		return ms.Assertion(assertType, append(sequenceKey, strconv.Itoa(sequence)), nil)
+	}
+
+	a, err := ms.bs.SequenceMemberAfter(assertType, sequenceKey, -1, ms.maxFormat(assertType))
+	if errors.Is(err, &asserts.NotFoundError{}) {
+		headers, _ := asserts.HeadersFromSequenceKey(assertType, sequenceKey)
+		return nil, &asserts.NotFoundError{Type: assertType, Headers: headers}
+	}
+	return a, err
+}
+
+func (ms *mirrorStore) SetAssertionMaxFormats(maxFormats map[string]int) {
+	ms.maxFormats = maxFormats
+}
@@ -0,0 +1,230 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package tooling_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/seed/seedtest"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+	"github.com/snapcore/snapd/store/tooling"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func (s *toolingSuite) makeMirror(c *C, channelsYaml string) string {
+	comRevs := map[string]snap.Revision{
+		"comp1": snap.R(22),
+		"comp2": snap.R(33),
+	}
+	s.SeedSnaps.MakeAssertedSnapWithComps(c, seedtest.SampleSnapYaml["required20"], nil,
+		snap.R(21), comRevs, "other", s.StoreSigning.Database)
+	s.setupSnaps(c, map[string]string{"core": "canonical"}, "")
+	s.setupSequenceFormingAssertion(c)
+
+	mirrorDir := c.MkDir()
+	s.SeedSnaps.MakeSnapMirror(c, mirrorDir, channelsYaml, "core", "required20")
+	return mirrorDir
+}
+
+func (s *toolingSuite) fileContent(c *C, path string) []byte {
+	content, err := os.ReadFile(path)
+	c.Assert(err, IsNil)
+	return content
+}
+
+func (s *toolingSuite) TestMirrorDownloadMany(c *C) {
+	mirrorDir := s.makeMirror(c, `
+core:
+  stable: 3
+required20:
+  latest/candidate: 21
+`)
+
+	tsto, err := tooling.NewToolingStoreFromMirror(mirrorDir)
+	c.Assert(err, IsNil)
+	tsto.Stdout = &bytes.Buffer{}
+
+	// env shenanigans
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	dlDir := c.MkDir()
+	bdf := func(si *snap.Info, cinfos map[string]*snap.ComponentInfo) (string, map[string]string, error) {
+		compPaths := make(map[string]string, len(cinfos))
+		for name, ci := range cinfos {
+			compPaths[name] = filepath.Join(dlDir, ci.Component.String()+".comp")
+		}
+		return filepath.Join(dlDir, si.SnapName()+".snap"), compPaths, nil
+	}
+	snapsToDownld := []tooling.SnapToDownload{
+		{Snap: naming.Snap("core"), Channel: "stable"},
+		// falls back to the more stable candidate risk
+		{Snap: naming.Snap("required20"), Channel: "edge", CompsToDownload: []string{"comp1"}},
+	}
+	dss, err := tsto.DownloadMany(snapsToDownld, nil, tooling.DownloadManyOptions{
+		BeforeDownloadFunc: bdf,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(dss, HasLen, 2)
+
+	c.Check(dss["core"].Info.SnapName(), Equals, "core")
+	c.Check(dss["core"].Info.SnapID, Equals, s.AssertedSnapID("core"))
+	c.Check(dss["core"].Info.Revision, Equals, snap.R(3))
+	c.Check(dss["core"].Path, testutil.FileEquals, s.fileContent(c, s.AssertedSnap("core")))
+
+	c.Check(dss["required20"].Info.Revision, Equals, snap.R(21))
+	c.Check(dss["required20"].Info.Channel, Equals, "edge")
+	c.Assert(dss["required20"].Components, HasLen, 1)
+	comp := dss["required20"].Components[0]
+	c.Check(comp.Info.Component, Equals, naming.NewComponentRef("required20", "comp1"))
+	c.Check(comp.Info.Revision, Equals, snap.R(22))
+	c.Check(comp.Path, testutil.FileEquals, s.fileContent(c, s.AssertedSnap("required20+comp1")))
+}
+
+func (s *toolingSuite) TestMirrorDownloadManyRevision(c *C) {
+	mirrorDir := s.makeMirror(c, "")
+
+	tsto, err := tooling.NewToolingStoreFromMirror(mirrorDir)
+	c.Assert(err, IsNil)
+	tsto.Stdout = &bytes.Buffer{}
+
+	// env shenanigans
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	dlDir := c.MkDir()
+	bdf := func(si *snap.Info, cinfos map[string]*snap.ComponentInfo) (string, map[string]string, error) {
+		return filepath.Join(dlDir, si.SnapName()+".snap"), nil, nil
+	}
+	dss, err := tsto.DownloadMany([]tooling.SnapToDownload{
+		{Snap: naming.Snap("core"), Revision: snap.R(3)},
+	}, nil, tooling.DownloadManyOptions{BeforeDownloadFunc: bdf})
+	c.Assert(err, IsNil)
+	c.Check(dss["core"].Info.Revision, Equals, snap.R(3))
+}
+
+func (s *toolingSuite) TestMirrorDownloadManyMissing(c *C) {
+	mirrorDir := s.makeMirror(c, `
+core:
+  18/stable: 3
+`)
+
+	tsto, err := tooling.NewToolingStoreFromMirror(mirrorDir)
+	c.Assert(err, IsNil)
+
+	bdf := func(si *snap.Info, cinfos map[string]*snap.ComponentInfo) (string, map[string]string, error) {
+		c.Fatalf("unexpected download of %q", si.SnapName())
+		return "", nil, nil
+	}
+	for _, t := range []struct {
+		toDownload tooling.SnapToDownload
+		err        string
+	}{
+		{tooling.SnapToDownload{Snap: naming.Snap("core"), Channel: "stable"}, `cannot download snap "core" from snap mirror: no revision in channel "stable"`},
+		{tooling.SnapToDownload{Snap: naming.Snap("core"), Channel: "20/stable"}, `cannot download snap "core" from snap mirror: no revision in channel "20/stable"`},
+		{tooling.SnapToDownload{Snap: naming.Snap("core"), Revision: snap.R(4)}, `cannot download snap "core" from snap mirror: revision 4 not found`},
+		{tooling.SnapToDownload{Snap: naming.Snap("pc"), Channel: "stable"}, `cannot download snap "pc" from snap mirror: no revision in channel "stable"`},
+	} {
+		_, err := tsto.DownloadMany([]tooling.SnapToDownload{t.toDownload}, nil, tooling.DownloadManyOptions{
+			BeforeDownloadFunc: bdf,
+		})
+		c.Check(err, ErrorMatches, t.err)
+	}
+}
+
+func (s *toolingSuite) TestMirrorAssertions(c *C) {
+	mirrorDir := s.makeMirror(c, "")
+
+	tsto, err := tooling.NewToolingStoreFromMirror(mirrorDir)
+	c.Assert(err, IsNil)
+
+	a, err := tsto.Find(asserts.SnapDeclarationType, map[string]string{
+		"series":  "16",
+		"snap-id": s.AssertedSnapID("core"),
+	})
+	c.Assert(err, IsNil)
+	c.Check(a.(*asserts.SnapDeclaration).SnapName(), Equals, "core")
+
+	_, err = tsto.Find(asserts.SnapDeclarationType, map[string]string{
+		"series":  "16",
+		"snap-id": "unknownunknownunknownunknownunkn",
+	})
+	c.Check(err, testutil.ErrorIs, &asserts.NotFoundError{})
+
+	db, err := asserts.OpenDatabase(&asserts.DatabaseConfig{
+		Backstore: asserts.NewMemoryBackstore(),
+		Trusted:   s.StoreSigning.Trusted,
+	})
+	c.Assert(err, IsNil)
+	sf := tsto.AssertionSequenceFormingFetcher(db, func(a asserts.Assertion) error {
+		return nil
+	})
+	// latest in the sequence
+	seq := &asserts.AtSequence{
+		Type:        asserts.ValidationSetType,
+		SequenceKey: []string{"16", "canonical", "base-set"},
+		Sequence:    0,
+		Revision:    asserts.RevisionNotKnown,
+	}
+	c.Assert(sf.FetchSequence(seq), IsNil)
+	vsa, err := db.Find(asserts.ValidationSetType, map[string]string{
+		"series":     "16",
+		"account-id": "canonical",
+		"name":       "base-set",
+		"sequence":   "1",
+	})
+	c.Assert(err, IsNil)
+	c.Check(vsa.(*asserts.ValidationSet).Sequence(), Equals, 1)
+
+	seq = &asserts.AtSequence{
+		Type:        asserts.ValidationSetType,
+		SequenceKey: []string{"16", "canonical", "other-set"},
+		Sequence:    1,
+	}
+	c.Check(sf.FetchSequence(seq), ErrorMatches, `validation-set.* not found`)
+}
+
+func (s *toolingSuite) TestMirrorUnassertedSnap(c *C) {
+	mirrorDir := s.makeMirror(c, "")
+	err := os.WriteFile(filepath.Join(mirrorDir, "local.snap"), []byte("not a snap"), 0644)
+	c.Assert(err, IsNil)
+
+	_, err = tooling.NewToolingStoreFromMirror(mirrorDir)
+	c.Check(err, ErrorMatches, `cannot use ".*/local.snap" from snap mirror: missing snap-revision assertion`)
+}
+
+func (s *toolingSuite) TestMirrorInvalidChannels(c *C) {
+	mirrorDir := s.makeMirror(c, `
+core:
+  a/b/c/d: 3
+`)
+	_, err := tooling.NewToolingStoreFromMirror(mirrorDir)
+	c.Check(err, ErrorMatches, `cannot parse snap mirror channels: invalid channel "a/b/c/d" for snap "core"`)
+
+	_, err = tooling.NewToolingStoreFromMirror(filepath.Join(mirrorDir, "missing"))
+	c.Check(err, ErrorMatches, `cannot read snap mirror: .*`)
+}
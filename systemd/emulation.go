@@ -96,6 +96,10 @@ func (s *emulation) RestartNoWaitForStop(services []string) error {
 	return &notImplementedError{"RestartNoWaitForStop"}
 }
 
+func (s *emulation) TryRestart(services []string) error {
+	return &notImplementedError{"TryRestart"}
+}
+
 func (s *emulation) Status(units []string) ([]*UnitStatus, error) {
 	return nil, &notImplementedError{"Status"}
 }
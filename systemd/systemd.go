@@ -448,6 +448,9 @@ type Systemd interface {
 	// RestartNoWaitForStop restarts the given services using systemctl restart,
 	// with no snapd specific logic to wait for the services to stop.
 	RestartNoWaitForStop(services []string) error
+	// TryRestart restarts the given services via 'systemctl try-restart',
+	// services which are not running are left alone.
+	TryRestart(services []string) error
 	// Status fetches the status of given units. Statuses are
 	// returned in the same order as unit names passed in
 	// argument.
@@ -555,6 +558,9 @@ const (
 	// the default target for systemd timer units that we generate
 	TimersTarget = "timers.target"
 
+	// the default target for systemd path units that we generate
+	PathsTarget = "paths.target"
+
 	// the target for systemd user session units that we generate
 	UserServicesTarget = "default.target"
 )
@@ -783,6 +789,7 @@ var extendedProperties = []string{"Id", "ActiveState", "UnitFileState", "Type",
 var unitProperties = map[string][]string{
 	".timer":  baseProperties,
 	".socket": baseProperties,
+	".path":   baseProperties,
 	".target": baseProperties,
 	// in service units, Type is the daemon type
 	".service": extendedProperties,
@@ -1030,7 +1037,7 @@ func (s *systemd) Status(unitNames []string) ([]*UnitStatus, error) {
 	for _, name := range unitNames {
 		// Group units with the same query string together to
 		// optimize the number of 'systemctl' invocations.
-		if strings.HasSuffix(name, ".timer") || strings.HasSuffix(name, ".socket") || strings.HasSuffix(name, ".target") {
+		if strings.HasSuffix(name, ".timer") || strings.HasSuffix(name, ".socket") || strings.HasSuffix(name, ".path") || strings.HasSuffix(name, ".target") {
 			// Units using the baseProperties query
 			limitedUnits = append(limitedUnits, name)
 		} else {
@@ -1258,6 +1265,14 @@ func (s *systemd) RestartNoWaitForStop(services []string) error {
 	return err
 }
 
+func (s *systemd) TryRestart(services []string) error {
+	if s.mode == GlobalUserMode {
+		panic("cannot call restart with GlobalUserMode")
+	}
+	_, err := s.systemctl(append([]string{"try-restart"}, services...)...)
+	return err
+}
+
 type systemctlError interface {
 	Msg() []byte
 	ExitCode() int
@@ -902,6 +902,12 @@ func (s *SystemdTestSuite) TestRestart(c *C) {
 	c.Check(s.argses[1], DeepEquals, []string{"start", "foo"})
 }
 
+func (s *SystemdTestSuite) TestTryRestart(c *C) {
+	err := New(SystemMode, s.rep).TryRestart([]string{"foo", "bar"})
+	c.Assert(err, IsNil)
+	c.Check(s.argses, DeepEquals, [][]string{{"try-restart", "foo", "bar"}})
+}
+
 func (s *SystemdTestSuite) TestRestartMany(c *C) {
 	restore := MockStopDelays(2*time.Millisecond, 4*time.Millisecond)
 	defer restore()
@@ -0,0 +1,103 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package timeutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// DateRange represents a range of whole days, in local time. Start is the
+// beginning of the first day and End is the beginning of the day following
+// the last one.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (dr DateRange) String() string {
+	return fmt.Sprintf("%s..%s", dr.Start.Format(dateLayout), dr.End.AddDate(0, 0, -1).Format(dateLayout))
+}
+
+// Includes returns whether t is inside the date range.
+func (dr DateRange) Includes(t time.Time) bool {
+	return !t.Before(dr.Start) && t.Before(dr.End)
+}
+
+// ParseDateRanges parses a comma-separated list of date ranges, each of the
+// form YYYY-MM-DD..YYYY-MM-DD or a single YYYY-MM-DD day. Both ends of
+// a range are inclusive, eg. "2026-12-24..2026-12-26" spans three days.
+func ParseDateRanges(spec string) ([]DateRange, error) {
+	var ranges []DateRange
+
+	for _, s := range strings.Split(spec, ",") {
+		dr, err := parseDateRange(s)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, dr)
+	}
+
+	return ranges, nil
+}
+
+func parseDateRange(s string) (DateRange, error) {
+	startStr, endStr, isRange := strings.Cut(s, "..")
+	if !isRange {
+		endStr = startStr
+	}
+
+	start, err := time.ParseInLocation(dateLayout, startStr, time.Local)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("cannot parse %q: not a valid date", startStr)
+	}
+	end, err := time.ParseInLocation(dateLayout, endStr, time.Local)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("cannot parse %q: not a valid date", endStr)
+	}
+	if end.Before(start) {
+		return DateRange{}, fmt.Errorf("cannot parse %q: range ends before it starts", s)
+	}
+
+	return DateRange{Start: start, End: end.AddDate(0, 0, 1)}, nil
+}
+
+// DateRangesEnd returns the end of the date range that includes t, or the zero
+// time if t is not inside any of the ranges. Overlapping or adjacent ranges
+// are followed through so that the returned time is outside all of them.
+func DateRangesEnd(ranges []DateRange, t time.Time) time.Time {
+	var end time.Time
+	for {
+		found := false
+		for _, dr := range ranges {
+			if dr.Includes(t) && dr.End.After(end) {
+				end = dr.End
+				found = true
+			}
+		}
+		if !found {
+			return end
+		}
+		t = end
+	}
+}
@@ -0,0 +1,85 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package timeutil_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/timeutil"
+)
+
+type dateRangeSuite struct{}
+
+var _ = Suite(&dateRangeSuite{})
+
+func (s *dateRangeSuite) TestParseDateRangesHappy(c *C) {
+	ranges, err := timeutil.ParseDateRanges("2026-12-24..2026-12-26,2027-01-01")
+	c.Assert(err, IsNil)
+	c.Assert(ranges, HasLen, 2)
+
+	c.Check(ranges[0].Start, Equals, time.Date(2026, 12, 24, 0, 0, 0, 0, time.Local))
+	c.Check(ranges[0].End, Equals, time.Date(2026, 12, 27, 0, 0, 0, 0, time.Local))
+	c.Check(ranges[0].String(), Equals, "2026-12-24..2026-12-26")
+	c.Check(ranges[1].Start, Equals, time.Date(2027, 1, 1, 0, 0, 0, 0, time.Local))
+	c.Check(ranges[1].End, Equals, time.Date(2027, 1, 2, 0, 0, 0, 0, time.Local))
+	c.Check(ranges[1].String(), Equals, "2027-01-01..2027-01-01")
+}
+
+func (s *dateRangeSuite) TestParseDateRangesUnhappy(c *C) {
+	for _, t := range []struct {
+		in  string
+		err string
+	}{
+		{"", `cannot parse "": not a valid date`},
+		{"2026-12-24..", `cannot parse "": not a valid date`},
+		{"2026-12-24..2026-13-01", `cannot parse "2026-13-01": not a valid date`},
+		{"2026-12-24..2026-12-01", `cannot parse "2026-12-24..2026-12-01": range ends before it starts`},
+		{"2026-12-24,,2026-12-26", `cannot parse "": not a valid date`},
+		{"monday", `cannot parse "monday": not a valid date`},
+	} {
+		_, err := timeutil.ParseDateRanges(t.in)
+		c.Check(err, ErrorMatches, t.err, Commentf("input: %q", t.in))
+	}
+}
+
+func (s *dateRangeSuite) TestDateRangeIncludes(c *C) {
+	ranges, err := timeutil.ParseDateRanges("2026-12-24..2026-12-26")
+	c.Assert(err, IsNil)
+	dr := ranges[0]
+
+	c.Check(dr.Includes(time.Date(2026, 12, 23, 23, 59, 0, 0, time.Local)), Equals, false)
+	c.Check(dr.Includes(time.Date(2026, 12, 24, 0, 0, 0, 0, time.Local)), Equals, true)
+	c.Check(dr.Includes(time.Date(2026, 12, 26, 23, 59, 0, 0, time.Local)), Equals, true)
+	c.Check(dr.Includes(time.Date(2026, 12, 27, 0, 0, 0, 0, time.Local)), Equals, false)
+}
+
+func (s *dateRangeSuite) TestDateRangesEnd(c *C) {
+	ranges, err := timeutil.ParseDateRanges("2026-12-24..2026-12-26,2026-12-27,2027-01-01..2027-01-02,2026-12-25")
+	c.Assert(err, IsNil)
+
+	// outside all ranges
+	c.Check(timeutil.DateRangesEnd(ranges, time.Date(2026, 12, 20, 10, 0, 0, 0, time.Local)).IsZero(), Equals, true)
+	// adjacent ranges are followed through
+	c.Check(timeutil.DateRangesEnd(ranges, time.Date(2026, 12, 25, 10, 0, 0, 0, time.Local)), Equals, time.Date(2026, 12, 28, 0, 0, 0, 0, time.Local))
+	c.Check(timeutil.DateRangesEnd(ranges, time.Date(2027, 1, 1, 10, 0, 0, 0, time.Local)), Equals, time.Date(2027, 1, 3, 0, 0, 0, 0, time.Local))
+	c.Check(timeutil.DateRangesEnd(nil, time.Date(2027, 1, 1, 10, 0, 0, 0, time.Local)).IsZero(), Equals, true)
+}
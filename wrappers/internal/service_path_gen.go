@@ -0,0 +1,102 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package internal
+
+import (
+	"bytes"
+	"path/filepath"
+	"text/template"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/systemd"
+)
+
+type pathDirective struct {
+	Name string
+	Path string
+}
+
+// GenerateSnapServicePathUnitFile generates the systemd path unit activating
+// the service of the given app when one of its watched paths changes.
+func GenerateSnapServicePathUnitFile(app *snap.AppInfo) []byte {
+	pathTemplate := `[Unit]
+# Auto-generated, DO NOT EDIT
+Description=Path watch for snap application {{.App.Snap.InstanceName}}.{{.App.Name}}
+{{- if .MountUnit}}
+Requires={{.MountUnit}}
+After={{.MountUnit}}
+{{- end}}
+X-Snappy=yes
+
+[Path]
+Unit={{.ServiceFileName}}
+{{ range .Directives }}{{ .Name }}={{ .Path }}
+{{ end }}
+[Install]
+WantedBy={{.PathsTarget}}
+`
+	var templateOut bytes.Buffer
+	t := template.Must(template.New("path-wrapper").Parse(pathTemplate))
+
+	var directives []pathDirective
+	for _, d := range []struct {
+		name  string
+		paths []string
+	}{
+		{"PathExists", app.Watch.PathExists},
+		{"PathExistsGlob", app.Watch.PathExistsGlob},
+		{"PathChanged", app.Watch.PathChanged},
+		{"PathModified", app.Watch.PathModified},
+		{"DirectoryNotEmpty", app.Watch.DirectoryNotEmpty},
+	} {
+		for _, path := range d.paths {
+			directives = append(directives, pathDirective{Name: d.name, Path: renderDaemonPath(app, path)})
+		}
+	}
+
+	wrapperData := struct {
+		App             *snap.AppInfo
+		ServiceFileName string
+		PathsTarget     string
+		MountUnit       string
+		Directives      []pathDirective
+	}{
+		App:             app,
+		ServiceFileName: filepath.Base(app.ServiceFile()),
+		PathsTarget:     systemd.PathsTarget,
+		Directives:      directives,
+	}
+	switch app.DaemonScope {
+	case snap.SystemDaemon:
+		wrapperData.MountUnit = filepath.Base(systemd.MountUnitPath(app.Snap.MountDir()))
+	case snap.UserDaemon:
+		// nothing
+	default:
+		panic("unknown snap.DaemonScope")
+	}
+
+	if err := t.Execute(&templateOut, wrapperData); err != nil {
+		// this can never happen, except we forget a variable
+		logger.Panicf("Unable to execute template: %v", err)
+	}
+
+	return templateOut.Bytes()
+}
@@ -0,0 +1,147 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2014-2016 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package internal_test
+
+import (
+	"fmt"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+	"github.com/snapcore/snapd/timeout"
+	"github.com/snapcore/snapd/wrappers/internal"
+)
+
+type servicePathUnitGenSuite struct {
+	testutil.BaseTest
+}
+
+var _ = Suite(&servicePathUnitGenSuite{})
+
+func (s *servicePathUnitGenSuite) TestServicePathUnit(c *C) {
+	const expectedServiceFmt = `[Unit]
+# Auto-generated, DO NOT EDIT
+Description=Path watch for snap application snap.app
+Requires=%s-snap-44.mount
+After=%s-snap-44.mount
+X-Snappy=yes
+
+[Path]
+Unit=snap.snap.app.service
+PathExists=/var/snap/snap/44/ready
+PathExistsGlob=/var/snap/snap/common/inbox/*.json
+PathChanged=/var/snap/snap/common/config
+PathChanged=/run/user/0/snap.snap/trigger
+PathModified=/var/snap/snap/44/log
+DirectoryNotEmpty=/var/snap/snap/common/spool
+
+[Install]
+WantedBy=paths.target
+`
+
+	expectedService := fmt.Sprintf(expectedServiceFmt, mountUnitPrefix, mountUnitPrefix)
+	service := &snap.AppInfo{
+		Snap: &snap.Info{
+			SuggestedName: "snap",
+			Version:       "0.3.4",
+			SideInfo:      snap.SideInfo{Revision: snap.R(44)},
+		},
+		Name:        "app",
+		Command:     "bin/foo start",
+		Daemon:      "simple",
+		DaemonScope: snap.SystemDaemon,
+		StopTimeout: timeout.DefaultTimeout,
+		Watch: &snap.WatchInfo{
+			PathExists:        []string{"$SNAP_DATA/ready"},
+			PathExistsGlob:    []string{"$SNAP_COMMON/inbox/*.json"},
+			PathChanged:       []string{"$SNAP_COMMON/config", "$XDG_RUNTIME_DIR/trigger"},
+			PathModified:      []string{"$SNAP_DATA/log"},
+			DirectoryNotEmpty: []string{"$SNAP_COMMON/spool"},
+		},
+	}
+	service.Watch.App = service
+
+	generatedWrapper := internal.GenerateSnapServicePathUnitFile(service)
+	c.Assert(string(generatedWrapper), Equals, expectedService)
+}
+
+func (s *servicePathUnitGenSuite) TestServicePathUnitUserDaemon(c *C) {
+	const expectedService = `[Unit]
+# Auto-generated, DO NOT EDIT
+Description=Path watch for snap application snap.app
+X-Snappy=yes
+
+[Path]
+Unit=snap.snap.app.service
+PathExists=%t/snap.snap/ready
+PathChanged=%h/snap/snap/44/config
+DirectoryNotEmpty=%h/snap/snap/common/spool
+
+[Install]
+WantedBy=paths.target
+`
+
+	service := &snap.AppInfo{
+		Snap: &snap.Info{
+			SuggestedName: "snap",
+			Version:       "0.3.4",
+			SideInfo:      snap.SideInfo{Revision: snap.R(44)},
+		},
+		Name:        "app",
+		Command:     "bin/foo start",
+		Daemon:      "simple",
+		DaemonScope: snap.UserDaemon,
+		StopTimeout: timeout.DefaultTimeout,
+		Watch: &snap.WatchInfo{
+			PathExists:        []string{"$XDG_RUNTIME_DIR/ready"},
+			PathChanged:       []string{"$SNAP_USER_DATA/config"},
+			DirectoryNotEmpty: []string{"$SNAP_USER_COMMON/spool"},
+		},
+	}
+	service.Watch.App = service
+
+	generatedWrapper := internal.GenerateSnapServicePathUnitFile(service)
+	c.Assert(string(generatedWrapper), Equals, expectedService)
+}
+
+func (s *servicePathUnitGenSuite) TestServicePathServiceUnitHasNoInstall(c *C) {
+	service := &snap.AppInfo{
+		Snap: &snap.Info{
+			SuggestedName: "snap",
+			Version:       "0.3.4",
+			SideInfo:      snap.SideInfo{Revision: snap.R(44)},
+		},
+		Name:        "app",
+		Command:     "bin/foo start",
+		Daemon:      "simple",
+		DaemonScope: snap.SystemDaemon,
+		StopTimeout: timeout.DefaultTimeout,
+		Watch: &snap.WatchInfo{
+			PathChanged: []string{"$SNAP_COMMON/config"},
+		},
+	}
+	service.Watch.App = service
+
+	generatedWrapper, err := internal.GenerateSnapServiceUnitFile(service, nil)
+	c.Assert(err, IsNil)
+	// the service is activated by the path unit
+	c.Check(string(generatedWrapper), Not(testutil.Contains), "[Install]")
+}
@@ -33,28 +33,33 @@ import (
 )
 
 func renderListenStream(socket *snap.SocketInfo) string {
-	s := socket.App.Snap
-	listenStream := socket.ListenStream
-	switch socket.App.DaemonScope {
+	return renderDaemonPath(socket.App, socket.ListenStream)
+}
+
+// renderDaemonPath expands the variables of a path used by the units of a
+// daemon.
+func renderDaemonPath(app *snap.AppInfo, path string) string {
+	s := app.Snap
+	switch app.DaemonScope {
 	case snap.SystemDaemon:
-		listenStream = strings.Replace(listenStream, "$SNAP_DATA", s.DataDir(), -1)
+		path = strings.Replace(path, "$SNAP_DATA", s.DataDir(), -1)
 		// TODO: when we support User/Group in the generated
 		// systemd unit, adjust this accordingly
 		serviceUserUid := sys.UserID(0)
 		runtimeDir := s.UserXdgRuntimeDir(serviceUserUid)
-		listenStream = strings.Replace(listenStream, "$XDG_RUNTIME_DIR", runtimeDir, -1)
-		listenStream = strings.Replace(listenStream, "$SNAP_COMMON", s.CommonDataDir(), -1)
+		path = strings.Replace(path, "$XDG_RUNTIME_DIR", runtimeDir, -1)
+		path = strings.Replace(path, "$SNAP_COMMON", s.CommonDataDir(), -1)
 	case snap.UserDaemon:
 		// TODO: use SnapDirOpts here. User daemons are also an experimental
 		// feature so, for simplicity, we can not pass opts here for now
-		listenStream = strings.Replace(listenStream, "$SNAP_USER_DATA", s.UserDataDir("%h", nil), -1)
-		listenStream = strings.Replace(listenStream, "$SNAP_USER_COMMON", s.UserCommonDataDir("%h", nil), -1)
+		path = strings.Replace(path, "$SNAP_USER_DATA", s.UserDataDir("%h", nil), -1)
+		path = strings.Replace(path, "$SNAP_USER_COMMON", s.UserCommonDataDir("%h", nil), -1)
 		// FIXME: find some way to share code with snap.UserXdgRuntimeDir()
-		listenStream = strings.Replace(listenStream, "$XDG_RUNTIME_DIR", fmt.Sprintf("%%t/snap.%s", s.InstanceName()), -1)
+		path = strings.Replace(path, "$XDG_RUNTIME_DIR", fmt.Sprintf("%%t/snap.%s", s.InstanceName()), -1)
 	default:
 		panic("unknown snap.DaemonScope")
 	}
-	return listenStream
+	return path
 }
 
 func generateSnapServiceSocketUnitFile(appInfo *snap.AppInfo, socketName string) []byte {
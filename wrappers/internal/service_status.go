@@ -255,5 +255,9 @@ func SnapServiceUnits(app *snap.AppInfo) (service string, activators []string) {
 	if app.Timer != nil {
 		activators = append(activators, filepath.Base(app.Timer.File()))
 	}
+	// Add application path watch
+	if app.Watch != nil {
+		activators = append(activators, filepath.Base(app.Watch.File()))
+	}
 	return app.ServiceName(), activators
 }
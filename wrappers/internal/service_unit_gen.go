@@ -211,7 +211,7 @@ OOMScoreAdjust={{.OOMAdjustScore}}
 {{- if .SliceUnit}}
 Slice={{.SliceUnit}}
 {{- end}}
-{{- if not (or .App.Sockets .App.Timer .App.ActivatesOn) }}
+{{- if not (or .App.Sockets .App.Timer .App.Watch .App.ActivatesOn) }}
 
 [Install]
 WantedBy={{.ServicesTarget}}
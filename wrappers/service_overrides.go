@@ -0,0 +1,215 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package wrappers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/timeout"
+)
+
+// ServiceOverride carries the properties of a snap service which were
+// overridden by the administrator. Unset fields keep the value from the
+// snap.yaml (or the systemd default).
+type ServiceOverride struct {
+	// Environment is a list of NAME=value entries added to the environment
+	// of the service.
+	Environment []string `json:"environment,omitempty"`
+	// RestartCondition overrides Restart=.
+	RestartCondition snap.RestartCondition `json:"restart-condition,omitempty"`
+	// RestartDelay overrides RestartSec=.
+	RestartDelay timeout.Timeout `json:"restart-delay,omitempty"`
+	// StartTimeout overrides TimeoutStartSec=.
+	StartTimeout timeout.Timeout `json:"start-timeout,omitempty"`
+	// StopTimeout overrides TimeoutStopSec=.
+	StopTimeout timeout.Timeout `json:"stop-timeout,omitempty"`
+	// Nice overrides Nice=.
+	Nice *int `json:"nice,omitempty"`
+	// IOSchedulingClass overrides IOSchedulingClass=.
+	IOSchedulingClass string `json:"io-scheduling-class,omitempty"`
+	// IOSchedulingPriority overrides IOSchedulingPriority=.
+	IOSchedulingPriority *int `json:"io-scheduling-priority,omitempty"`
+	// MemoryMax overrides MemoryMax=.
+	MemoryMax ServiceMemorySize `json:"memory-max,omitempty"`
+	// After lists additional units the service is ordered after.
+	After []string `json:"after,omitempty"`
+}
+
+// ServiceMemorySize is a memory size as understood by systemd: a number of
+// bytes, optionally with a K, M, G or T suffix (base 1024), a percentage of
+// the physical memory, or "infinity".
+type ServiceMemorySize string
+
+// UnmarshalJSON accepts plain numbers of bytes besides strings, as those
+// are what setting a number through the configuration yields.
+func (size *ServiceMemorySize) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if len(b) > 0 && b[0] != '"' && json.Unmarshal(b, &n) == nil {
+		*size = ServiceMemorySize(n.String())
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	*size = ServiceMemorySize(str)
+	return nil
+}
+
+var serviceMemorySizeRegexp = regexp.MustCompile(`^(?:([0-9]+)[KMGT]?|([0-9]+(?:\.[0-9]+)?)%|infinity)$`)
+
+// Validate checks that the size is understood by systemd.
+func (size ServiceMemorySize) Validate() error {
+	m := serviceMemorySizeRegexp.FindStringSubmatch(string(size))
+	if m == nil {
+		return fmt.Errorf("invalid memory size %q: must be a number of bytes with an optional K, M, G or T suffix, a percentage or infinity", size)
+	}
+	if m[1] != "" {
+		if _, err := strconv.ParseUint(m[1], 10, 64); err != nil {
+			return fmt.Errorf("invalid memory size %q: out of range", size)
+		}
+	}
+	if m[2] != "" {
+		if pct, err := strconv.ParseFloat(m[2], 64); err != nil || pct > 100 {
+			return fmt.Errorf("invalid memory size %q: percentage must not exceed 100%%", size)
+		}
+	}
+	return nil
+}
+
+var (
+	overrideEnvNameRegexp  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	overrideUnitNameRegexp = regexp.MustCompile(`^[A-Za-z0-9:_.\\@-]+\.(service|socket|target|mount|automount|path|timer|device|swap|slice|scope)$`)
+)
+
+// Validate checks that the override only uses values which can be safely
+// rendered into a systemd drop-in.
+func (ov *ServiceOverride) Validate() error {
+	for _, env := range ov.Environment {
+		name, value, ok := strings.Cut(env, "=")
+		if !ok {
+			return fmt.Errorf("invalid environment entry %q: expected NAME=value", env)
+		}
+		if !overrideEnvNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid environment variable name %q", name)
+		}
+		if strings.ContainsAny(value, "\n\r") {
+			return fmt.Errorf("invalid value of environment variable %q: cannot contain newlines", name)
+		}
+	}
+	if ov.RestartCondition != "" {
+		if _, ok := snap.RestartMap[string(ov.RestartCondition)]; !ok {
+			return fmt.Errorf("invalid restart condition %q", ov.RestartCondition)
+		}
+	}
+	for _, t := range []struct {
+		name  string
+		value timeout.Timeout
+	}{
+		{"restart-delay", ov.RestartDelay},
+		{"start-timeout", ov.StartTimeout},
+		{"stop-timeout", ov.StopTimeout},
+	} {
+		if t.value < 0 {
+			return fmt.Errorf("%s cannot be negative", t.name)
+		}
+	}
+	if ov.Nice != nil && (*ov.Nice < -20 || *ov.Nice > 19) {
+		return fmt.Errorf("invalid nice value %d: must be between -20 and 19", *ov.Nice)
+	}
+	switch ov.IOSchedulingClass {
+	case "", "realtime", "best-effort", "idle":
+	default:
+		return fmt.Errorf("invalid IO scheduling class %q: must be one of realtime, best-effort or idle", ov.IOSchedulingClass)
+	}
+	if ov.IOSchedulingPriority != nil && (*ov.IOSchedulingPriority < 0 || *ov.IOSchedulingPriority > 7) {
+		return fmt.Errorf("invalid IO scheduling priority %d: must be between 0 and 7", *ov.IOSchedulingPriority)
+	}
+	if ov.MemoryMax != "" {
+		if err := ov.MemoryMax.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, unit := range ov.After {
+		if !overrideUnitNameRegexp.MatchString(unit) {
+			return fmt.Errorf("invalid unit name %q", unit)
+		}
+	}
+	return nil
+}
+
+// serviceOverrideFile returns the path of the drop-in file carrying the
+// administrator overrides of the given service.
+func serviceOverrideFile(app *snap.AppInfo) string {
+	return filepath.Join(app.ServiceFile()+".d", "50-snapd-override.conf")
+}
+
+// quoteEnvironment quotes a NAME=value entry for use with Environment=,
+// escaping systemd specifiers along the way.
+func quoteEnvironment(env string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `%`, `%%`)
+	return `"` + r.Replace(env) + `"`
+}
+
+// generateServiceOverrideFile renders the drop-in applying the given
+// override.
+func generateServiceOverrideFile(ov *ServiceOverride) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Auto-generated, DO NOT EDIT\n")
+	if len(ov.After) > 0 {
+		fmt.Fprintf(&buf, "[Unit]\nAfter=%s\n\n", strings.Join(ov.After, " "))
+	}
+	buf.WriteString("[Service]\n")
+	for _, env := range ov.Environment {
+		fmt.Fprintf(&buf, "Environment=%s\n", quoteEnvironment(env))
+	}
+	if ov.RestartCondition != "" {
+		fmt.Fprintf(&buf, "Restart=%s\n", snap.RestartMap[string(ov.RestartCondition)])
+	}
+	if ov.RestartDelay != 0 {
+		fmt.Fprintf(&buf, "RestartSec=%s\n", ov.RestartDelay)
+	}
+	if ov.StartTimeout != 0 {
+		fmt.Fprintf(&buf, "TimeoutStartSec=%s\n", ov.StartTimeout)
+	}
+	if ov.StopTimeout != 0 {
+		fmt.Fprintf(&buf, "TimeoutStopSec=%s\n", ov.StopTimeout)
+	}
+	if ov.Nice != nil {
+		fmt.Fprintf(&buf, "Nice=%d\n", *ov.Nice)
+	}
+	if ov.IOSchedulingClass != "" {
+		fmt.Fprintf(&buf, "IOSchedulingClass=%s\n", ov.IOSchedulingClass)
+	}
+	if ov.IOSchedulingPriority != nil {
+		fmt.Fprintf(&buf, "IOSchedulingPriority=%d\n", *ov.IOSchedulingPriority)
+	}
+	if ov.MemoryMax != "" {
+		fmt.Fprintf(&buf, "MemoryMax=%s\n", ov.MemoryMax)
+	}
+	return buf.Bytes()
+}
@@ -184,7 +184,7 @@ func reloadOrRestartServices(sysd systemd.Systemd, cli *userServiceClient, reloa
 }
 
 func serviceIsActivated(app *snap.AppInfo) bool {
-	return len(app.Sockets) > 0 || app.Timer != nil || len(app.ActivatesOn) > 0
+	return len(app.Sockets) > 0 || app.Timer != nil || app.Watch != nil || len(app.ActivatesOn) > 0
 }
 
 func serviceIsSlotActivated(app *snap.AppInfo) bool {
@@ -451,11 +451,16 @@ type SnapServiceOptions struct {
 
 	// QuotaGroup is the quota group for the specified snap.
 	QuotaGroup *quota.Group
+
+	// ServiceOverrides maps the names of the services of the specified snap
+	// to the overrides set for them by the administrator.
+	ServiceOverrides map[string]*ServiceOverride
 }
 
 // ObserveChangeCallback can be invoked by EnsureSnapServices to observe
 // the previous content of a unit and the new on a change.
-// unitType can be "service", "socket", "timer". name is empty for a timer.
+// unitType can be "service", "socket", "timer", "path", "override". name is
+// empty for a timer or a path.
 type ObserveChangeCallback func(app *snap.AppInfo, grp *quota.Group, unitType string, name, old, new string)
 
 // EnsureSnapServicesOptions is the set of options applying to the
@@ -562,7 +567,7 @@ func (es *ensureSnapServicesContext) reloadModified() error {
 
 // ensureSnapServiceSystemdUnits takes care of writing .service files for all services
 // registered in snap.Info apps.
-func (es *ensureSnapServicesContext) ensureSnapServiceSystemdUnits(snapInfo *snap.Info, opts *internal.SnapServicesUnitOptions) error {
+func (es *ensureSnapServicesContext) ensureSnapServiceSystemdUnits(snapInfo *snap.Info, opts *internal.SnapServicesUnitOptions, overrides map[string]*ServiceOverride) error {
 	handleFileModification := func(app *snap.AppInfo, unitType string, name, path string, content []byte) error {
 		old, modifiedFile, err := tryFileUpdate(path, content)
 		if err != nil {
@@ -592,6 +597,35 @@ func (es *ensureSnapServicesContext) ensureSnapServiceSystemdUnits(snapInfo *sna
 		return nil
 	}
 
+	handleFileRemoval := func(app *snap.AppInfo, unitType string, name, path string) error {
+		st, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		oldContent, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		if es.observeChange != nil {
+			es.observeChange(app, nil, unitType, name, string(oldContent), "")
+		}
+		es.modifiedUnits[path] = &osutil.MemoryFileState{Content: oldContent, Mode: st.Mode()}
+
+		switch app.DaemonScope {
+		case snap.SystemDaemon:
+			es.systemDaemonReloadNeeded = true
+		case snap.UserDaemon:
+			es.userDaemonReloadNeeded = true
+		}
+		return nil
+	}
+
 	// lets sort the service list before generating them for
 	// consistency when testing
 	services := snapInfo.Services()
@@ -644,6 +678,18 @@ func (es *ensureSnapServicesContext) ensureSnapServiceSystemdUnits(snapInfo *sna
 			return err
 		}
 
+		// the administrator overrides are kept in a drop-in, which is
+		// removed once no overrides are left
+		overridePath := serviceOverrideFile(svc)
+		if ov := overrides[svc.Name]; ov != nil {
+			content := generateServiceOverrideFile(ov)
+			if err := handleFileModification(svc, "override", svc.Name, overridePath, content); err != nil {
+				return err
+			}
+		} else if err := handleFileRemoval(svc, "override", svc.Name, overridePath); err != nil {
+			return err
+		}
+
 		// Generate systemd .socket files if needed
 		socketFiles, err := internal.GenerateSnapSocketUnitFiles(svc)
 		if err != nil {
@@ -666,6 +712,14 @@ func (es *ensureSnapServicesContext) ensureSnapServiceSystemdUnits(snapInfo *sna
 				return err
 			}
 		}
+
+		if svc.Watch != nil {
+			content := internal.GenerateSnapServicePathUnitFile(svc)
+			path := svc.Watch.File()
+			if err := handleFileModification(svc, "path", "", path, content); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -707,7 +761,7 @@ func (es *ensureSnapServicesContext) ensureSnapsSystemdServices() (*quota.QuotaG
 			}
 		}
 
-		if err := es.ensureSnapServiceSystemdUnits(s, genServiceOpts); err != nil {
+		if err := es.ensureSnapServiceSystemdUnits(s, genServiceOpts, snapSvcOpts.ServiceOverrides); err != nil {
 			return nil, err
 		}
 	}
@@ -1122,6 +1176,7 @@ func RemoveSnapServices(s *snap.Info, inter Interacter) error {
 	systemUnits := []string{}
 	userUnits := []string{}
 	systemUnitFiles := []string{}
+	dropInDirs := []string{}
 
 	// collect list of system units to disable and remove
 	for _, app := range s.Apps {
@@ -1164,6 +1219,20 @@ func RemoveSnapServices(s *snap.Info, inter Interacter) error {
 			systemUnitFiles = append(systemUnitFiles, path)
 		}
 
+		if app.Watch != nil {
+			path := app.Watch.File()
+
+			pathName := filepath.Base(path)
+			logger.Noticef("RemoveSnapServices - path %s", pathName)
+			switch app.DaemonScope {
+			case snap.SystemDaemon:
+				systemUnits = append(systemUnits, pathName)
+			case snap.UserDaemon:
+				userUnits = append(userUnits, pathName)
+			}
+			systemUnitFiles = append(systemUnitFiles, path)
+		}
+
 		logger.Noticef("RemoveSnapServices - disabling %s", serviceName)
 		switch app.DaemonScope {
 		case snap.SystemDaemon:
@@ -1172,6 +1241,8 @@ func RemoveSnapServices(s *snap.Info, inter Interacter) error {
 			userUnits = append(userUnits, serviceName)
 		}
 		systemUnitFiles = append(systemUnitFiles, app.ServiceFile())
+		systemUnitFiles = append(systemUnitFiles, serviceOverrideFile(app))
+		dropInDirs = append(dropInDirs, filepath.Dir(serviceOverrideFile(app)))
 	}
 
 	// disable all collected systemd units
@@ -1190,6 +1261,11 @@ func RemoveSnapServices(s *snap.Info, inter Interacter) error {
 			logger.Noticef("Failed to remove socket file %q: %v", systemUnitFile, err)
 		}
 	}
+	// drop-in directories are left behind if they carry files not owned
+	// by snapd
+	for _, dir := range dropInDirs {
+		os.Remove(dir)
+	}
 
 	// only reload if we actually had services
 	if removedSystem {
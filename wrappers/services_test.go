@@ -48,6 +48,7 @@ import (
 	"github.com/snapcore/snapd/systemd"
 	"github.com/snapcore/snapd/systemd/systemdtest"
 	"github.com/snapcore/snapd/testutil"
+	"github.com/snapcore/snapd/timeout"
 	"github.com/snapcore/snapd/timings"
 	"github.com/snapcore/snapd/usersession/agent"
 	"github.com/snapcore/snapd/wrappers"
@@ -229,6 +230,98 @@ WantedBy=multi-user.target
 	))
 }
 
+func (s *servicesTestSuite) TestEnsureSnapServicesWithServiceOverrides(c *C) {
+	// map unit -> new
+	seen := make(map[string]bool)
+	cb := func(app *snap.AppInfo, grp *quota.Group, unitType, name string, old, new string) {
+		seen[fmt.Sprintf("%s:%s:%s:%s", app.Snap.InstanceName(), app.Name, unitType, name)] = new != ""
+	}
+
+	info := snaptest.MockSnap(c, packageHello, &snap.SideInfo{Revision: snap.R(12)})
+	dropIn := filepath.Join(dirs.GlobalRootDir, "/etc/systemd/system/snap.hello-snap.svc1.service.d/50-snapd-override.conf")
+
+	nice := 10
+	m := map[*snap.Info]*wrappers.SnapServiceOptions{
+		info: {
+			ServiceOverrides: map[string]*wrappers.ServiceOverride{
+				"svc1": {
+					Environment:      []string{"FOO=bar"},
+					RestartCondition: snap.RestartNever,
+					StopTimeout:      timeout.Timeout(5 * time.Second),
+					Nice:             &nice,
+				},
+			},
+		},
+	}
+
+	err := wrappers.EnsureSnapServices(m, nil, cb, progress.Null)
+	c.Assert(err, IsNil)
+	c.Check(s.sysdLog, DeepEquals, [][]string{
+		{"daemon-reload"},
+	})
+	c.Check(seen, DeepEquals, map[string]bool{
+		"hello-snap:svc1:service:svc1":  true,
+		"hello-snap:svc1:override:svc1": true,
+	})
+	c.Check(dropIn, testutil.FileEquals, `# Auto-generated, DO NOT EDIT
+[Service]
+Environment="FOO=bar"
+Restart=no
+TimeoutStopSec=5s
+Nice=10
+`)
+
+	// without overrides the drop-in goes away
+	s.sysdLog = nil
+	seen = make(map[string]bool)
+	m[info] = nil
+	err = wrappers.EnsureSnapServices(m, nil, cb, progress.Null)
+	c.Assert(err, IsNil)
+	c.Check(s.sysdLog, DeepEquals, [][]string{
+		{"daemon-reload"},
+	})
+	c.Check(seen, DeepEquals, map[string]bool{
+		"hello-snap:svc1:override:svc1": false,
+	})
+	c.Check(dropIn, testutil.FileAbsent)
+}
+
+func (s *servicesTestSuite) TestEnsureSnapServicesWithServiceOverridesRollback(c *C) {
+	info := snaptest.MockSnap(c, packageHello, &snap.SideInfo{Revision: snap.R(12)})
+	dropIn := filepath.Join(dirs.GlobalRootDir, "/etc/systemd/system/snap.hello-snap.svc1.service.d/50-snapd-override.conf")
+
+	nice := 10
+	m := map[*snap.Info]*wrappers.SnapServiceOptions{
+		info: {
+			ServiceOverrides: map[string]*wrappers.ServiceOverride{
+				"svc1": {Nice: &nice},
+			},
+		},
+	}
+	err := wrappers.EnsureSnapServices(m, nil, nil, progress.Null)
+	c.Assert(err, IsNil)
+
+	r := systemd.MockSystemctl(func(cmd ...string) ([]byte, error) {
+		s.sysdLog = append(s.sysdLog, cmd)
+		if len(s.sysdLog) == 1 {
+			return nil, fmt.Errorf("oops")
+		}
+		return nil, nil
+	})
+	defer r()
+	s.sysdLog = nil
+
+	// removing the overrides fails on daemon-reload, so the drop-in is
+	// restored
+	m[info] = nil
+	err = wrappers.EnsureSnapServices(m, nil, nil, progress.Null)
+	c.Assert(err, ErrorMatches, "oops")
+	c.Check(dropIn, testutil.FileEquals, `# Auto-generated, DO NOT EDIT
+[Service]
+Nice=10
+`)
+}
+
 func (s *servicesTestSuite) TestEnsureSnapServicesWithQuotas(c *C) {
 	info := snaptest.MockSnap(c, packageHello, &snap.SideInfo{Revision: snap.R(12)})
 	svcFile := filepath.Join(dirs.GlobalRootDir, "/etc/systemd/system/snap.hello-snap.svc1.service")
@@ -4837,6 +4930,81 @@ func (s *servicesTestSuite) TestAddRemoveSnapWithTimersAddsRemovesTimerFiles(c *
 	c.Check(osutil.FileExists(app.ServiceFile()), Equals, false)
 }
 
+func (s *servicesTestSuite) TestRemoveSnapServicesRemovesServiceOverrides(c *C) {
+	info := snaptest.MockSnap(c, packageHello, &snap.SideInfo{Revision: snap.R(12)})
+
+	nice := 10
+	m := map[*snap.Info]*wrappers.SnapServiceOptions{
+		info: {
+			ServiceOverrides: map[string]*wrappers.ServiceOverride{
+				"svc1": {Nice: &nice},
+			},
+		},
+	}
+	err := wrappers.EnsureSnapServices(m, nil, nil, progress.Null)
+	c.Assert(err, IsNil)
+
+	dropInDir := filepath.Join(dirs.SnapServicesDir, "snap.hello-snap.svc1.service.d")
+	c.Check(filepath.Join(dropInDir, "50-snapd-override.conf"), testutil.FilePresent)
+
+	err = wrappers.RemoveSnapServices(info, &progress.Null)
+	c.Assert(err, IsNil)
+	c.Check(dropInDir, testutil.FileAbsent)
+}
+
+func (s *servicesTestSuite) TestAddRemoveSnapWithWatchAddsRemovesPathFiles(c *C) {
+	info := snaptest.MockSnap(c, packageHello+`
+ svc2:
+  command: bin/hello
+  daemon: simple
+  watch:
+   path-changed: [$SNAP_COMMON/config]
+`, &snap.SideInfo{Revision: snap.R(12)})
+
+	err := s.addSnapServices(info, false)
+	c.Assert(err, IsNil)
+
+	app := info.Apps["svc2"]
+	c.Assert(app.Watch, NotNil)
+
+	c.Check(osutil.FileExists(app.ServiceFile()), Equals, true)
+	c.Check(app.Watch.File(), testutil.FileContains, "\nPathChanged="+filepath.Join(dirs.SnapDataDir, "hello-snap/common/config")+"\n")
+
+	err = wrappers.StopServices(info.Services(), nil, nil, "", &progress.Null, s.perfTimings)
+	c.Assert(err, IsNil)
+
+	err = wrappers.RemoveSnapServices(info, &progress.Null)
+	c.Assert(err, IsNil)
+
+	c.Check(osutil.FileExists(app.Watch.File()), Equals, false)
+	c.Check(osutil.FileExists(app.ServiceFile()), Equals, false)
+}
+
+func (s *servicesTestSuite) TestStartSnapPathEnableStart(c *C) {
+	svc1Name := "snap.hello-snap.svc1.service"
+	svc2Path := "snap.hello-snap.svc2.path"
+
+	info := snaptest.MockSnap(c, packageHello+`
+ svc2:
+  command: bin/hello
+  daemon: simple
+  watch:
+   directory-not-empty: [$SNAP_COMMON/spool]
+`, &snap.SideInfo{Revision: snap.R(12)})
+
+	// fix the apps order to make the test stable
+	apps := []*snap.AppInfo{info.Apps["svc1"], info.Apps["svc2"]}
+	opts := &wrappers.StartServicesOptions{Enable: true}
+	err := wrappers.StartServices(apps, nil, opts, &progress.Null, s.perfTimings)
+	c.Assert(err, IsNil)
+	c.Check(s.sysdLog, DeepEquals, [][]string{
+		{"--no-reload", "enable", svc2Path, svc1Name},
+		{"daemon-reload"},
+		{"start", svc2Path},
+		{"start", svc1Name},
+	}, Commentf("calls: %v", s.sysdLog))
+}
+
 func (s *servicesTestSuite) TestFailedAddSnapCleansUp(c *C) {
 	info := snaptest.MockSnap(c, packageHello+`
  svc2: